@@ -0,0 +1,338 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/h3nc4/TelegramScout/internal/acks"
+	"github.com/h3nc4/TelegramScout/internal/admin"
+	"github.com/h3nc4/TelegramScout/internal/annotate"
+	"github.com/h3nc4/TelegramScout/internal/archive"
+	"github.com/h3nc4/TelegramScout/internal/audit"
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/graph"
+	"github.com/h3nc4/TelegramScout/internal/health"
+	"github.com/h3nc4/TelegramScout/internal/mentions"
+	"github.com/h3nc4/TelegramScout/internal/presence"
+	"github.com/h3nc4/TelegramScout/internal/scout"
+	"github.com/h3nc4/TelegramScout/internal/standing"
+)
+
+// Attach application endpoints to the admin listener
+func registerAdminRoutes(srv *admin.Server, s *scout.Scout, tracker *health.Tracker, events *annotate.Annotator, reload func() error) {
+	srv.Handle("/healthz", healthHandler(tracker))
+
+	srv.Handle("/explain", admin.JSONHandler(func(r *http.Request) (any, error) {
+		q := r.URL.Query()
+
+		limit := 20
+		if v := q.Get("limit"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid limit: %w", err)
+			}
+			limit = n
+		}
+
+		var chatID int64
+		if v := q.Get("chat"); v != "" {
+			id, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid chat: %w", err)
+			}
+			chatID = id
+		}
+
+		return s.Explanations(chatID, q.Get("tag"), limit), nil
+	}))
+
+	srv.Handle("/inspect", admin.JSONHandler(func(r *http.Request) (any, error) {
+		link := r.URL.Query().Get("link")
+		if link == "" {
+			return nil, errors.New("missing link")
+		}
+		return s.Inspect(r.Context(), link)
+	}))
+
+	srv.Handle("/engine", admin.JSONHandler(func(r *http.Request) (any, error) {
+		return engineStatus(s), nil
+	}))
+	srv.Handle("/engine/set", admin.JSONPostHandler(func(r *http.Request) (any, error) {
+		name := r.URL.Query().Get("name")
+		if err := s.SetEngine(name); err != nil {
+			return nil, err
+		}
+		status := engineStatus(s)
+		events.Event(r.Context(), annotate.KindEngine, fmt.Sprintf("Matching engine switched to %s", status.Engine))
+		return status, nil
+	}))
+
+	srv.Handle("/rules/soak", admin.JSONHandler(func(r *http.Request) (any, error) {
+		return soakStatus(s), nil
+	}))
+	srv.Handle("/rules/reload", admin.JSONPostHandler(func(r *http.Request) (any, error) {
+		if err := reload(); err != nil {
+			return nil, err
+		}
+		return soakStatus(s), nil
+	}))
+}
+
+// Reloaded keywords being soaked, as served by /rules/soak
+type soakInfo struct {
+	Soaking bool            `json:"soaking"`
+	Diff    *scout.RuleDiff `json:"diff,omitempty"`
+}
+
+func soakStatus(s *scout.Scout) soakInfo {
+	diff, ok := s.Soak()
+	if !ok {
+		return soakInfo{}
+	}
+	return soakInfo{Soaking: true, Diff: &diff}
+}
+
+// Matching engine in use, as served by /engine
+type engineInfo struct {
+	Engine string `json:"engine"`
+	Auto   bool   `json:"auto"`
+}
+
+func engineStatus(s *scout.Scout) engineInfo {
+	name, auto := s.Engine()
+	return engineInfo{Engine: name, Auto: auto}
+}
+
+// Attach endpoints querying and pruning the archive, and for day files
+// reporting their size and compacting them
+func registerArchiveRoutes(srv *admin.Server, store archive.Store) {
+	if files, ok := store.(*archive.Files); ok {
+		srv.Handle("/archive/stats", admin.JSONHandler(func(r *http.Request) (any, error) {
+			return files.Stats(r.Context())
+		}))
+		srv.Handle("/archive/compact", admin.JSONPostHandler(func(r *http.Request) (any, error) {
+			return files.Compact(r.Context())
+		}))
+	}
+	srv.Handle("/archive/query", admin.JSONHandler(func(r *http.Request) (any, error) {
+		v := r.URL.Query()
+		q := archive.Query{Kind: v.Get("kind"), Rule: v.Get("rule"), Text: v.Get("text"), Limit: 50}
+		if s := v.Get("chat"); s != "" {
+			id, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid chat: %w", err)
+			}
+			q.ChatID = id
+		}
+		if s := v.Get("limit"); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil {
+				return nil, fmt.Errorf("invalid limit: %w", err)
+			}
+			q.Limit = n
+		}
+		var err error
+		if s := v.Get("since"); s != "" {
+			if q.Since, err = parseDate("since", s, false); err != nil {
+				return nil, err
+			}
+		}
+		if s := v.Get("until"); s != "" {
+			if q.Until, err = parseDate("until", s, true); err != nil {
+				return nil, err
+			}
+		}
+		return store.Query(r.Context(), q)
+	}))
+	srv.Handle("/archive/prune", admin.JSONPostHandler(func(r *http.Request) (any, error) {
+		before, err := parseDate("before", r.URL.Query().Get("before"), false)
+		if err != nil {
+			return nil, err
+		}
+		removed, err := store.Prune(r.Context(), before)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]int{"removed": removed}, nil
+	}))
+	srv.Handle("/archive/summary", admin.JSONHandler(func(r *http.Request) (any, error) {
+		top := config.DefaultSummaryTop
+		if s := r.URL.Query().Get("top"); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("invalid top %q", s)
+			}
+			top = n
+		}
+		return archive.Summarize(r.Context(), store, time.Now(), top)
+	}))
+}
+
+// Look up chats through the monitoring account
+type chatLookup interface {
+	ResolveChat(ctx context.Context, ref mentions.Ref) (mentions.Info, error)
+	ChatFolder(ctx context.Context, slug string) ([]mentions.Info, error)
+}
+
+// Attach endpoints resolving usernames, invites and chat folder links, used by chats import
+func registerChatRoutes(srv *admin.Server, lookup chatLookup) {
+	srv.Handle("/chats/resolve", admin.JSONHandler(func(r *http.Request) (any, error) {
+		q := r.URL.Query()
+		ref := mentions.Ref{Username: strings.ToLower(strings.TrimPrefix(q.Get("username"), "@")), Invite: q.Get("invite")}
+		if ref == (mentions.Ref{}) {
+			return nil, errors.New("missing username or invite")
+		}
+		return lookup.ResolveChat(r.Context(), ref)
+	}))
+	srv.Handle("/chats/folder", admin.JSONHandler(func(r *http.Request) (any, error) {
+		slug := r.URL.Query().Get("slug")
+		if slug == "" {
+			return nil, errors.New("missing slug")
+		}
+		return lookup.ChatFolder(r.Context(), slug)
+	}))
+}
+
+// Attach endpoints starting and listing backfills, which run until done or ctx is cancelled
+func registerBackfillRoutes(ctx context.Context, srv *admin.Server, s *scout.Scout) {
+	srv.Handle("/backfill", admin.JSONHandler(func(r *http.Request) (any, error) {
+		return s.Backfills(), nil
+	}))
+	srv.Handle("/backfill/start", admin.JSONPostHandler(func(r *http.Request) (any, error) {
+		q := r.URL.Query()
+		chat := q.Get("chat")
+		if chat == "" {
+			return nil, errors.New("missing chat")
+		}
+		since, err := parseDate("since", q.Get("since"), false)
+		if err != nil {
+			return nil, err
+		}
+		return s.StartBackfill(ctx, chat, since, q.Get("evaluate") == "true")
+	}))
+}
+
+// Parse the query parameter name, a date or an RFC 3339 time. Dates stand
+// for their start, or for their end with endOfDay.
+func parseDate(name, v string, endOfDay bool) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, fmt.Errorf("missing %s", name)
+	}
+	if t, err := time.Parse(time.DateOnly, v); err == nil {
+		if endOfDay {
+			t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s %q: expected YYYY-MM-DD or RFC 3339", name, v)
+	}
+	return t, nil
+}
+
+// Attach endpoints listing alerts and changing their acknowledgement state,
+// recording changes in auditLog when it is not nil
+func registerAckRoutes(srv *admin.Server, store *acks.Store, auditLog *audit.Log) {
+	srv.Handle("/alerts", admin.JSONHandler(func(r *http.Request) (any, error) {
+		q := r.URL.Query()
+
+		limit := 50
+		if v := q.Get("limit"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid limit: %w", err)
+			}
+			limit = n
+		}
+		return store.List(q.Get("state"), limit), nil
+	}))
+
+	for path, state := range map[string]string{"/alerts/ack": acks.StateAcked, "/alerts/resolve": acks.StateResolved} {
+		srv.Handle(path, admin.JSONPostHandler(func(r *http.Request) (any, error) {
+			q := r.URL.Query()
+			by := cmp.Or(q.Get("by"), admin.Caller(r), "admin")
+			a, err := store.Set(q.Get("id"), state, by)
+			if err != nil || auditLog == nil {
+				return a, err
+			}
+
+			// The token is who made the change, whoever it claims to act for
+			e := audit.Entry{Actor: by, Source: audit.SourceAPI, Target: a.ID, ChatID: a.ChatID}
+			e.Action = map[string]string{acks.StateAcked: "ack", acks.StateResolved: "resolve"}[state]
+			if caller := admin.Caller(r); caller != "" && caller != by {
+				e.Actor, e.Detail = caller, "as "+by
+			}
+			if err := auditLog.Record(e); err != nil {
+				return nil, fmt.Errorf("state changed but not audited: %w", err)
+			}
+			return a, nil
+		}))
+	}
+}
+
+// Serve the chat graph for the graph command
+func graphHandler(g *graph.Graph) http.Handler {
+	return admin.JSONHandler(func(r *http.Request) (any, error) {
+		return g.Snapshot(), nil
+	})
+}
+
+// Serve a tracked user's status timeline, or the latest status of every tracked user
+func presenceHandler(t *presence.Tracker) http.Handler {
+	return admin.JSONHandler(func(r *http.Request) (any, error) {
+		v := r.URL.Query().Get("user")
+		if v == "" {
+			return t.Latest(), nil
+		}
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid user: %w", err)
+		}
+		return t.Timeline(id), nil
+	})
+}
+
+// Serve the account's last checked standing
+func standingHandler(m *standing.Monitor) http.Handler {
+	return admin.JSONHandler(func(r *http.Request) (any, error) {
+		return m.Last(), nil
+	})
+}
+
+// Report connection health, answering 503 when the client is down or wedged
+func healthHandler(tracker *health.Tracker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		st := tracker.Status()
+		w.Header().Set("Content-Type", "application/json")
+		if !st.Healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(st)
+	})
+}
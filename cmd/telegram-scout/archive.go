@@ -0,0 +1,192 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/h3nc4/TelegramScout/internal/archive"
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+// Longest a compaction or prune request may take, as it rewrites or reads every day file
+const maintenanceTimeout = 10 * time.Minute
+
+// Inspect and maintain the archive of a running instance
+func archiveCommand(ctx context.Context, args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("expected one of: stats, compact, query, prune, summary")
+	}
+	switch action := args[0]; action {
+	case "stats", "compact":
+		return archiveStats(ctx, action, args[1:], stdout)
+	case "query":
+		return archiveQuery(ctx, args[1:], stdout)
+	case "prune":
+		return archivePrune(ctx, args[1:], stdout)
+	case "summary":
+		return archiveSummary(ctx, args[1:], stdout)
+	default:
+		return fmt.Errorf("unknown archive action %q", action)
+	}
+}
+
+// Report the size of the archive, compacting it first for the compact action
+func archiveStats(ctx context.Context, action string, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("archive "+action, flag.ContinueOnError)
+	fs.SetOutput(stdout)
+	addr := fs.String("addr", "", "admin listener address (defaults to admin.listen from config)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var stats archive.Stats
+	var err error
+	if action == "compact" {
+		err = adminCallWithin(ctx, http.MethodPost, *addr, "/archive/compact", nil, maintenanceTimeout, &stats)
+	} else {
+		err = adminGet(ctx, *addr, "/archive/stats", nil, &stats)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(stdout, "Files:      %d", stats.Files)
+	if stats.Files > 0 {
+		_, _ = fmt.Fprintf(stdout, " (%s to %s)", stats.Oldest, stats.Newest)
+	}
+	_, _ = fmt.Fprintf(stdout, "\nRecords:    %d\nSize:       %d bytes\n", stats.Records, stats.Bytes)
+	if action == "compact" {
+		_, _ = fmt.Fprintf(stdout, "Removed:    %d duplicates, %d broken lines\nReclaimed:  %d bytes\n", stats.Duplicates, stats.Invalid, stats.Reclaimed)
+		return nil
+	}
+	_, _ = fmt.Fprintf(stdout, "Duplicates: %d\nBroken:     %d\n", stats.Duplicates, stats.Invalid)
+	return nil
+}
+
+// Print archived records, newest first
+func archiveQuery(ctx context.Context, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("archive query", flag.ContinueOnError)
+	fs.SetOutput(stdout)
+	kind := fs.String("kind", "", "only records of this kind: message, match, shadow, backfill or count")
+	chat := fs.Int64("chat", 0, "only records from this chat ID")
+	rule := fs.String("rule", "", "only records of this rule")
+	text := fs.String("text", "", "only messages containing this text, ignoring case")
+	since := fs.String("since", "", "only messages posted from this date, as YYYY-MM-DD or RFC 3339")
+	until := fs.String("until", "", "only messages posted up to this date, as YYYY-MM-DD or RFC 3339")
+	limit := fs.Int("limit", 50, "maximum number of records to show")
+	addr := fs.String("addr", "", "admin listener address (defaults to admin.listen from config)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	query := url.Values{"limit": {strconv.Itoa(*limit)}}
+	for key, v := range map[string]string{"kind": *kind, "rule": *rule, "text": *text, "since": *since, "until": *until} {
+		if v != "" {
+			query.Set(key, v)
+		}
+	}
+	if *chat != 0 {
+		query.Set("chat", strconv.FormatInt(*chat, 10))
+	}
+
+	var records []archive.Record
+	if err := adminCallWithin(ctx, http.MethodGet, *addr, "/archive/query", query, maintenanceTimeout, &records); err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		_, _ = fmt.Fprintln(stdout, "No records found.")
+		return nil
+	}
+	for _, r := range records {
+		_, _ = fmt.Fprintf(stdout, "[%s] %-8s chat=%s (%d) msg=%d", r.Date.Format(time.RFC3339), r.Kind, r.ChatTitle, r.ChatID, r.MsgID)
+		if r.Rule != "" {
+			_, _ = fmt.Fprintf(stdout, " rule=%q", r.Rule)
+		}
+		_, _ = fmt.Fprintf(stdout, "\n  %q\n", r.Text)
+	}
+	return nil
+}
+
+// Remove the records of messages posted before a date
+func archivePrune(ctx context.Context, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("archive prune", flag.ContinueOnError)
+	fs.SetOutput(stdout)
+	before := fs.String("before", "", "remove messages posted before this date, as YYYY-MM-DD")
+	addr := fs.String("addr", "", "admin listener address (defaults to admin.listen from config)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *before == "" {
+		return errors.New("missing -before")
+	}
+
+	var pruned struct {
+		Removed int `json:"removed"`
+	}
+	if err := adminCallWithin(ctx, http.MethodPost, *addr, "/archive/prune", url.Values{"before": {*before}}, maintenanceTimeout, &pruned); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(stdout, "Removed %d records of messages posted before %s\n", pruned.Removed, *before)
+	return nil
+}
+
+// Print the alert counts, top rules, top chats and spikes of the last day and week
+func archiveSummary(ctx context.Context, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("archive summary", flag.ContinueOnError)
+	fs.SetOutput(stdout)
+	top := fs.Int("top", config.DefaultSummaryTop, "number of rules and chats to list")
+	addr := fs.String("addr", "", "admin listener address (defaults to admin.listen from config)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var report archive.Report
+	if err := adminCallWithin(ctx, http.MethodGet, *addr, "/archive/summary", url.Values{"top": {strconv.Itoa(*top)}}, maintenanceTimeout, &report); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(stdout, "Alerts: %d in the last 24h, %d in the last 7d\n", report.Day, report.Week)
+	if len(report.TopRules) > 0 {
+		_, _ = fmt.Fprintln(stdout, "\nTop rules:")
+		for _, c := range report.TopRules {
+			_, _ = fmt.Fprintf(stdout, "  %-24q %5d  (%d in 7d)\n", c.Name, c.Day, c.Week)
+		}
+	}
+	if len(report.TopChats) > 0 {
+		_, _ = fmt.Fprintln(stdout, "\nTop chats:")
+		for _, c := range report.TopChats {
+			_, _ = fmt.Fprintf(stdout, "  %-24s %5d  (%d in 7d)\n", fmt.Sprintf("%s (%d)", c.Name, c.ChatID), c.Day, c.Week)
+		}
+	}
+	if len(report.Spikes) > 0 {
+		_, _ = fmt.Fprintln(stdout, "\nSpikes:")
+		for _, s := range report.Spikes {
+			_, _ = fmt.Fprintf(stdout, "  %-24q %5d  (%.1f a day before)\n", s.Rule, s.Day, s.Average)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/h3nc4/TelegramScout/internal/audit"
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+// Print the audit log of this instance, read from its state directory
+func auditCommand(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("audit", flag.ContinueOnError)
+	fs.SetOutput(stdout)
+	limit := fs.Int("limit", 50, "maximum number of entries to show, newest kept")
+	actor := fs.String("actor", "", "only show changes by this user")
+	action := fs.String("action", "", "only show this action, such as snooze, ack or config")
+	since := fs.Duration("since", 0, "only show changes this recent")
+	path := fs.String("config", config.FilePath(), "config file with the state directory")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadFile(*path)
+	if err != nil {
+		return err
+	}
+	file, err := cfg.StatePath("audit.jsonl")
+	if err != nil {
+		return err
+	}
+
+	q := audit.Query{Actor: *actor, Action: *action, Limit: *limit}
+	if *since > 0 {
+		q.Since = time.Now().Add(-*since)
+	}
+	entries, err := audit.Read(file, q)
+	if err != nil {
+		return err
+	}
+
+	if len(entries) == 0 {
+		_, _ = fmt.Fprintln(stdout, "No changes recorded.")
+		return nil
+	}
+
+	for _, e := range entries {
+		_, _ = fmt.Fprintf(stdout, "%s %-8s %-8s %s by=%s", e.Time.Format(time.RFC3339), e.Source, e.Action, e.Target, e.Actor)
+		if e.ChatID != 0 {
+			_, _ = fmt.Fprintf(stdout, " chat=%d", e.ChatID)
+		}
+		if e.Detail != "" {
+			_, _ = fmt.Fprintf(stdout, " (%s)", e.Detail)
+		}
+		_, _ = fmt.Fprintln(stdout)
+	}
+	return nil
+}
+
+// Record the config file when it differs from the one recorded last, so
+// edits between runs show up next to the runtime changes
+func auditConfigChange(log *audit.Log, logPath, cfgPath string) error {
+	data, err := os.ReadFile(cfgPath)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+	detail := "sha256 " + hex.EncodeToString(sum[:8])
+
+	entries, err := audit.Read(logPath, audit.Query{Action: "config", Limit: 1})
+	if err != nil {
+		return err
+	}
+	if len(entries) == 1 && entries[0].Detail == detail {
+		return nil
+	}
+	return log.Record(audit.Entry{
+		Actor:  cmp.Or(os.Getenv("USER"), "unknown"),
+		Source: audit.SourceStartup,
+		Action: "config",
+		Target: cfgPath,
+		Detail: detail,
+	})
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/h3nc4/TelegramScout/internal/scout"
+)
+
+// How often -wait checks on a running backfill
+var backfillPoll = 5 * time.Second
+
+// Start a backfill of a chat's history into the archive of a running
+// instance, or list backfills without -chat
+func backfillCommand(ctx context.Context, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("backfill", flag.ContinueOnError)
+	fs.SetOutput(stdout)
+	chat := fs.String("chat", "", "chat to backfill, as @username or ID")
+	since := fs.String("since", "", "oldest message date to fetch, as YYYY-MM-DD or RFC 3339")
+	evaluate := fs.Bool("evaluate", false, "also run rules and archive matches, without alerting")
+	wait := fs.Bool("wait", false, "wait for the backfill to finish and print its report")
+	addr := fs.String("addr", "", "admin listener address (defaults to admin.listen from config)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *chat == "" {
+		var reports []scout.BackfillReport
+		if err := adminGet(ctx, *addr, "/backfill", nil, &reports); err != nil {
+			return err
+		}
+		if len(reports) == 0 {
+			_, _ = fmt.Fprintln(stdout, "No backfills started.")
+			return nil
+		}
+		for _, r := range reports {
+			printBackfill(stdout, r)
+		}
+		return nil
+	}
+	if _, err := parseDate("since", *since, false); err != nil {
+		return err
+	}
+
+	query := url.Values{"chat": {*chat}, "since": {*since}, "evaluate": {strconv.FormatBool(*evaluate)}}
+	var report scout.BackfillReport
+	if err := adminCall(ctx, http.MethodPost, *addr, "/backfill/start", query, &report); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(stdout, "Started backfill %d of %s since %s\n", report.ID, report.Chat, report.Since.Format(time.DateOnly))
+	if !*wait {
+		return nil
+	}
+
+	for report.Finished.IsZero() {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backfillPoll):
+		}
+		var reports []scout.BackfillReport
+		if err := adminGet(ctx, *addr, "/backfill", nil, &reports); err != nil {
+			return err
+		}
+		i := slices.IndexFunc(reports, func(r scout.BackfillReport) bool { return r.ID == report.ID })
+		if i < 0 {
+			return fmt.Errorf("backfill %d is no longer tracked", report.ID)
+		}
+		report = reports[i]
+	}
+	printBackfill(stdout, report)
+	if report.Error != "" {
+		return fmt.Errorf("backfill failed: %s", report.Error)
+	}
+	return nil
+}
+
+func printBackfill(w io.Writer, r scout.BackfillReport) {
+	state := "running"
+	switch {
+	case r.Error != "":
+		state = "failed"
+	case !r.Finished.IsZero():
+		state = "done"
+	}
+	_, _ = fmt.Fprintf(w, "%-4d %-8s %s since %s: %d messages", r.ID, state, r.Chat, r.Since.Format(time.DateOnly), r.Messages)
+	if !r.Oldest.IsZero() {
+		_, _ = fmt.Fprintf(w, ", back to %s", r.Oldest.Format(time.DateOnly))
+	}
+	_, _ = fmt.Fprintln(w)
+	if r.Error != "" {
+		_, _ = fmt.Fprintf(w, "     error: %s\n", r.Error)
+	}
+	for _, rule := range slices.Sorted(maps.Keys(r.Matches)) {
+		_, _ = fmt.Fprintf(w, "     %d matches of %q\n", r.Matches[rule], rule)
+	}
+}
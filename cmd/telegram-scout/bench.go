@@ -0,0 +1,272 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/tabwriter"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/archive"
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/model"
+	"github.com/h3nc4/TelegramScout/internal/scout"
+)
+
+// Notifications still being sent are waited for until none arrives for this long
+const benchDrainIdle = 500 * time.Millisecond
+
+// Words synthetic messages are made of
+var benchWords = strings.Fields(`the a to and of in for on with is this that price new deal
+	today available shipping free used sale only now check out link group channel
+	update offer stock limited order best quality fast brand original`)
+
+// Run synthetic messages through the configured rules and report throughput
+// and latency, without connecting to Telegram or sending any alert
+func benchCommand(ctx context.Context, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	fs.SetOutput(stdout)
+	count := fs.Int("messages", 10000, "number of messages to send")
+	rate := fs.Float64("rate", 0, "messages per second, 0 sends as fast as they are processed")
+	matchRatio := fs.Float64("match", 0.05, "fraction of messages containing a configured keyword")
+	chats := fs.Int("chats", 20, "number of chats messages are spread over")
+	words := fs.Int("words", 30, "words per message")
+	seed := fs.Uint64("seed", 1, "seed for the generated messages")
+	path := fs.String("config", config.FilePath(), "config file with the rules to benchmark")
+	engines := fs.Bool("engines", false, "only compare the matching engines on the messages, without the rest of the pipeline")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *count <= 0 || *chats <= 0 || *words <= 0 || *rate < 0 || *matchRatio < 0 || *matchRatio > 1 {
+		return fmt.Errorf("messages, chats and words must be positive, rate non-negative and match between 0 and 1")
+	}
+
+	cfg, err := config.LoadFile(*path)
+	if err != nil {
+		return err
+	}
+	if err := applyPacks(cfg, zap.NewNop()); err != nil {
+		return err
+	}
+	notif := newBenchNotifier()
+	s := scout.New(cfg, notif, zap.NewNop())
+	matches := &matchCounter{}
+	s.UseArchive(matches)
+	compiled, rejected := s.Rules()
+
+	literals := literalKeywords(cfg.Monitoring.Keywords)
+	if len(literals) == 0 && *matchRatio > 0 {
+		_, _ = fmt.Fprintln(stdout, "No plain keywords to plant in messages, matches only come from chance.")
+	}
+
+	gen := rand.New(rand.NewPCG(*seed, *seed))
+	if *engines {
+		msgs := make([]model.Message, *count)
+		for i := range msgs {
+			msgs[i] = plantKeyword(gen, benchMessage(gen, i, *chats, *words), literals, *matchRatio)
+		}
+		_, _ = fmt.Fprintf(stdout, "Rules:       %d compiled, %d rejected\n", compiled, len(rejected))
+		benchEngines(s, msgs, stdout)
+		return nil
+	}
+
+	processing := make([]time.Duration, 0, *count)
+	start := time.Now()
+	for i := range *count {
+		if *rate > 0 {
+			due := start.Add(time.Duration(float64(i) / *rate * float64(time.Second)))
+			if wait := time.Until(due); wait > 0 {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(wait):
+				}
+			}
+		} else if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		msg := plantKeyword(gen, benchMessage(gen, i, *chats, *words), literals, *matchRatio)
+		received := time.Now()
+		notif.received(msg.Link, received)
+		s.Process(ctx, msg)
+		processing = append(processing, time.Since(received))
+	}
+	elapsed := time.Since(start)
+	matched := int(matches.n.Load())
+	delivered := notif.wait(ctx, matched, benchDrainIdle)
+
+	_, _ = fmt.Fprintf(stdout, "Rules:       %d compiled, %d rejected\n", compiled, len(rejected))
+	_, _ = fmt.Fprintf(stdout, "Messages:    %d in %s (%.0f msg/s)", *count, elapsed.Round(time.Millisecond), float64(*count)/elapsed.Seconds())
+	if *rate > 0 {
+		_, _ = fmt.Fprintf(stdout, ", target %.0f msg/s", *rate)
+	}
+	_, _ = fmt.Fprintln(stdout)
+	_, _ = fmt.Fprintf(stdout, "Processing:  %s\n", percentiles(processing))
+	_, _ = fmt.Fprintf(stdout, "Matches:     %d (%.1f%%)\n", matched, 100*float64(matched)/float64(*count))
+	_, _ = fmt.Fprintf(stdout, "Alerts:      %d sent, %s from receipt\n", len(delivered), percentiles(delivered))
+	if held := matched - len(delivered); held > 0 {
+		_, _ = fmt.Fprintf(stdout, "             %d held for the digest or still sending\n", held)
+	}
+	return nil
+}
+
+// Build the i-th synthetic message
+func benchMessage(gen *rand.Rand, i, chats, words int) model.Message {
+	chatID := -1000000000000 - int64(i%chats)
+	text := make([]string, words)
+	for w := range text {
+		text[w] = benchWords[gen.IntN(len(benchWords))]
+	}
+	// Unique text, so content deduplication does not skip messages
+	text = append(text, fmt.Sprintf("#%d", i))
+	return model.Message{
+		ID:        i + 1,
+		ChatID:    chatID,
+		ChatTitle: fmt.Sprintf("Bench Chat %d", i%chats),
+		Text:      strings.Join(text, " "),
+		Date:      time.Now(),
+		Link:      fmt.Sprintf("https://t.me/c/%d/%d", i%chats, i+1),
+	}
+}
+
+// Append one of literals to the message with probability ratio
+func plantKeyword(gen *rand.Rand, msg model.Message, literals []string, ratio float64) model.Message {
+	if len(literals) > 0 && gen.Float64() < ratio {
+		msg.Text += " " + literals[gen.IntN(len(literals))]
+	}
+	return msg
+}
+
+// Print the time each matching engine takes on msgs
+func benchEngines(s *scout.Scout, msgs []model.Message, stdout io.Writer) {
+	configured, _ := s.Engine()
+	_, _ = fmt.Fprintf(stdout, "Engine:      %s in use\n", configured)
+	w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ENGINE\tTIME\tPER MESSAGE\tMATCHES\tMISMATCHES")
+	for _, r := range s.BenchEngines(msgs) {
+		if r.Err != nil {
+			_, _ = fmt.Fprintf(w, "%s\t-\t-\t-\t%v\n", r.Engine, r.Err)
+			continue
+		}
+		per := r.Elapsed / time.Duration(len(msgs))
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\n", r.Engine, r.Elapsed.Round(time.Microsecond), per, r.Matches, r.Mismatches)
+	}
+	_ = w.Flush()
+}
+
+// Return the keywords that match their own text, which can be planted in messages
+func literalKeywords(keywords []string) []string {
+	var literals []string
+	for _, k := range keywords {
+		prefix, _, _ := strings.Cut(k, ":")
+		if prefix != "re" && prefix != "re2" && prefix != "pcre" && !strings.ContainsAny(k, "*{") {
+			literals = append(literals, k)
+		}
+	}
+	return literals
+}
+
+// Format the 50th, 90th and 99th percentiles and the maximum of durations
+func percentiles(d []time.Duration) string {
+	if len(d) == 0 {
+		return "no samples"
+	}
+	sorted := slices.Clone(d)
+	slices.Sort(sorted)
+	at := func(p float64) time.Duration {
+		return sorted[int(p*float64(len(sorted)-1))]
+	}
+	return fmt.Sprintf("p50=%s p90=%s p99=%s max=%s", at(0.5), at(0.9), at(0.99), sorted[len(sorted)-1])
+}
+
+// Count matches through the archive hook, which sees every one of them
+type matchCounter struct{ n atomic.Int64 }
+
+func (c *matchCounter) Add(r archive.Record) {
+	if r.Kind == archive.KindMatch {
+		c.n.Add(1)
+	}
+}
+
+var benchLink = regexp.MustCompile(`href="(https://t\.me/c/[^"]+)"`)
+
+// Accept every alert and time it from the receipt of its message
+type benchNotifier struct {
+	mux       sync.Mutex
+	receipts  map[string]time.Time
+	latencies []time.Duration
+	sent      chan struct{}
+}
+
+func newBenchNotifier() *benchNotifier {
+	return &benchNotifier{receipts: make(map[string]time.Time), sent: make(chan struct{}, 1)}
+}
+
+func (b *benchNotifier) received(link string, at time.Time) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	b.receipts[link] = at
+}
+
+func (b *benchNotifier) Send(ctx context.Context, text string) error {
+	now := time.Now()
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	if m := benchLink.FindStringSubmatch(text); m != nil {
+		if at, ok := b.receipts[m[1]]; ok {
+			b.latencies = append(b.latencies, now.Sub(at))
+		}
+	}
+	select {
+	case b.sent <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Wait until want alerts were sent or none was for idle, and return their latencies
+func (b *benchNotifier) wait(ctx context.Context, want int, idle time.Duration) []time.Duration {
+	for {
+		b.mux.Lock()
+		got := slices.Clone(b.latencies)
+		b.mux.Unlock()
+		if len(got) >= want {
+			return got
+		}
+		select {
+		case <-b.sent:
+		case <-time.After(idle):
+			return got
+		case <-ctx.Done():
+			return got
+		}
+	}
+}
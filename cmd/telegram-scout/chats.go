@@ -0,0 +1,192 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/h3nc4/TelegramScout/internal/atomicfile"
+	"github.com/h3nc4/TelegramScout/internal/chatimport"
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/mentions"
+)
+
+func chatsCommand(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("expected one of: import")
+	}
+	switch action := args[0]; action {
+	case "import":
+		return chatsImport(ctx, args[1:], stdin, stdout)
+	default:
+		return fmt.Errorf("unknown chats action %q", action)
+	}
+}
+
+// Resolve the chats of a CSV watchlist or folder link through a running
+// instance and add them to the config file
+func chatsImport(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("chats import", flag.ContinueOnError)
+	flags.SetOutput(stdout)
+	file := flags.String("file", "", "CSV file with a chat per row, or - for stdin")
+	folder := flags.String("folder", "", "chat folder link to import every chat of, t.me/addlist/...")
+	path := flags.String("config", config.FilePath(), "config file to add the chats to")
+	dryRun := flags.Bool("dry-run", false, "report what would change without writing the config")
+	delay := flags.Duration("delay", time.Second, "wait between lookups, keeping clear of Telegram's flood limits")
+	addr := flags.String("addr", "", "admin listener address (defaults to admin.listen from config)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" && *folder == "" {
+		return errors.New("usage: chats import [-file chats.csv] [-folder t.me/addlist/...] [-config path] [-dry-run]")
+	}
+
+	var entries []chatimport.Entry
+	if *file != "" {
+		in := stdin
+		if *file != "-" {
+			f, err := os.Open(*file)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = f.Close() }()
+			in = f
+		}
+		read, err := chatimport.ReadCSV(in)
+		if err != nil {
+			return fmt.Errorf("invalid CSV: %w", err)
+		}
+		entries = read
+	}
+	if *folder != "" {
+		entries = append(entries, chatimport.Entry{Input: *folder})
+	}
+
+	var chats []chatimport.Chat
+	failed := 0
+	for i, e := range entries {
+		if i > 0 && *delay > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(*delay):
+			}
+		}
+		resolved, err := resolveEntry(ctx, *addr, e)
+		if err != nil {
+			failed++
+			where := e.Input
+			if e.Line > 0 {
+				where = fmt.Sprintf("line %d %q", e.Line, e.Input)
+			}
+			_, _ = fmt.Fprintf(stdout, "failed  %s: %v\n", where, err)
+			continue
+		}
+		for _, chat := range resolved {
+			_, _ = fmt.Fprintf(stdout, "ok      %s -> %s\n", e.Input, chat.Ref)
+		}
+		chats = append(chats, resolved...)
+	}
+
+	// Importing into a missing config starts a new one
+	data, err := os.ReadFile(*path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	merged, rep, err := chatimport.Merge(data, chats)
+	if err != nil {
+		return fmt.Errorf("invalid config %s: %w", *path, err)
+	}
+	if len(rep.Added) > 0 {
+		_, _ = fmt.Fprintf(stdout, "Added: %s\n", strings.Join(rep.Added, ", "))
+	}
+	if len(rep.Kept) > 0 {
+		_, _ = fmt.Fprintf(stdout, "Already configured: %s\n", strings.Join(rep.Kept, ", "))
+	}
+
+	switch {
+	case len(rep.Added) == 0:
+		_, _ = fmt.Fprintf(stdout, "%s is up to date\n", *path)
+	case *dryRun:
+		_, _ = fmt.Fprintf(stdout, "Dry run, %s left unchanged\n", *path)
+	default:
+		if err := atomicfile.Write(*path, merged); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(stdout, "Updated %s, restart to apply\n", *path)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d entries could not be imported", failed, len(entries))
+	}
+	return nil
+}
+
+// Turn a watchlist entry into the chats it stands for, looking up usernames,
+// invites and folders through the admin API
+func resolveEntry(ctx context.Context, addr string, e chatimport.Entry) ([]chatimport.Chat, error) {
+	target, err := chatimport.Parse(e.Input)
+	if err != nil {
+		return nil, err
+	}
+	if target.Chat != "" {
+		// Already in config form, only the running instance can tell whether it exists
+		return []chatimport.Chat{{Ref: target.Chat, Comment: e.Note}}, nil
+	}
+
+	var infos []mentions.Info
+	if target.Folder != "" {
+		err = adminCallWithin(ctx, http.MethodGet, addr, "/chats/folder", url.Values{"slug": {target.Folder}}, 2*time.Minute, &infos)
+	} else {
+		var info mentions.Info
+		query := url.Values{"username": {target.Ref.Username}, "invite": {target.Ref.Invite}}
+		err = adminGet(ctx, addr, "/chats/resolve", query, &info)
+		infos = append(infos, info)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(infos) == 0 {
+		return nil, errors.New("folder has no groups or channels")
+	}
+
+	chats := make([]chatimport.Chat, 0, len(infos))
+	for _, info := range infos {
+		ref, err := chatimport.Normalize(info)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", info.Title, err)
+		}
+		comment := cmp.Or(e.Note, info.Title)
+		if !info.Joined {
+			comment += ", not joined"
+		}
+		chats = append(chats, chatimport.Chat{Ref: ref, Comment: comment})
+	}
+	return chats, nil
+}
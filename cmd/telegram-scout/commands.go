@@ -0,0 +1,394 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/h3nc4/TelegramScout/internal/acks"
+	"github.com/h3nc4/TelegramScout/internal/admin"
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/graph"
+	"github.com/h3nc4/TelegramScout/internal/health"
+	"github.com/h3nc4/TelegramScout/internal/scout"
+)
+
+const usage = `Usage: telegram-scout [command]
+
+Without a command, run the monitor.
+
+Commands:
+  alerts        List tracked alerts and their acknowledgement state (requires admin listener)
+  ack           Acknowledge an alert by ID (requires admin listener)
+  resolve       Resolve an alert by ID (requires admin listener)
+  archive       Inspect and maintain the archive: stats, compact, query, prune, summary (requires admin listener)
+  audit         Show who snoozed rules, changed alerts or edited the config, and when
+  backfill      Archive a chat's history from a date, optionally reporting rule matches (requires admin listener)
+  bench         Measure throughput and latency of the configured rules on synthetic messages
+  chats         Import chats to monitor from a CSV file or chat folder link (requires admin listener)
+  engine        Show or switch the keyword matching engine (requires admin listener)
+  explain       Show why recent alerts fired (requires admin listener)
+  graph         Export the forward and mention graph as DOT or GraphML (requires admin listener)
+  health        Exit non-zero unless the running instance is connected (for container health checks)
+  inspect       Fetch a message by link and trace it through the current rules (requires admin listener)
+  replay        Feed updates recorded with log.record_updates through the rules, offline
+  rules         Share rules between deployments and manage rule packs: export, import, packs, update, reload, soak
+  service       Manage the background service: install, uninstall, start, stop, run
+  validate      Check the config and report rejected, redundant and overly complex rules
+  version       Print version and build information
+  self-update   Replace this binary with the latest GitHub release
+`
+
+// Run a CLI subcommand and return the process exit code
+func runCommand(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	var err error
+	switch args[0] {
+	case "alerts":
+		err = alertsCommand(ctx, args[1:], stdout)
+	case "ack":
+		err = setAlertCommand(ctx, args[0], acks.StateAcked, args[1:], stdout)
+	case "resolve":
+		err = setAlertCommand(ctx, args[0], acks.StateResolved, args[1:], stdout)
+	case "archive":
+		err = archiveCommand(ctx, args[1:], stdout)
+	case "audit":
+		err = auditCommand(args[1:], stdout)
+	case "backfill":
+		err = backfillCommand(ctx, args[1:], stdout)
+	case "bench":
+		err = benchCommand(ctx, args[1:], stdout)
+	case "chats":
+		err = chatsCommand(ctx, args[1:], os.Stdin, stdout)
+	case "engine":
+		err = engineCommand(ctx, args[1:], stdout)
+	case "explain":
+		err = explainCommand(ctx, args[1:], stdout)
+	case "graph":
+		err = graphCommand(ctx, args[1:], stdout)
+	case "health":
+		err = healthCommand(ctx, args[1:], stdout)
+	case "inspect":
+		err = inspectCommand(ctx, args[1:], stdout)
+	case "replay":
+		err = replayCommand(ctx, args[1:], stdout)
+	case "rules":
+		err = rulesCommand(ctx, args[1:], os.Stdin, stdout)
+	case "service":
+		err = serviceCommand(args[1:], stdout)
+	case "validate":
+		err = validateCommand(args[1:], stdout)
+	case "version", "--version":
+		err = versionCommand(stdout)
+	case "self-update":
+		err = selfUpdateCommand(ctx, args[1:], stdout)
+	case "help", "-h", "--help":
+		_, _ = fmt.Fprint(stdout, usage)
+		return 0
+	default:
+		_, _ = fmt.Fprintf(stderr, "unknown command %q\n\n%s", args[0], usage)
+		return 2
+	}
+
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "%s: %v\n", args[0], err)
+		return 1
+	}
+	return 0
+}
+
+// Print recent match explanations fetched from a running instance
+func explainCommand(ctx context.Context, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("explain", flag.ContinueOnError)
+	fs.SetOutput(stdout)
+	limit := fs.Int("limit", 20, "maximum number of alerts to show")
+	chat := fs.Int64("chat", 0, "only show alerts from this chat ID")
+	tag := fs.String("tag", "", "only show alerts of rules with this tag")
+	addr := fs.String("addr", "", "admin listener address (defaults to admin.listen from config)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(*limit))
+	if *chat != 0 {
+		query.Set("chat", strconv.FormatInt(*chat, 10))
+	}
+	if *tag != "" {
+		query.Set("tag", *tag)
+	}
+
+	var entries []scout.Explanation
+	if err := adminGet(ctx, *addr, "/explain", query, &entries); err != nil {
+		return err
+	}
+
+	if len(entries) == 0 {
+		_, _ = fmt.Fprintln(stdout, "No alerts recorded yet.")
+		return nil
+	}
+
+	for _, e := range entries {
+		_, _ = fmt.Fprintf(stdout, "[%s] chat=%s (%d) msg=%d\n", e.Time.Format(time.RFC3339), e.ChatTitle, e.ChatID, e.MsgID)
+		_, _ = fmt.Fprintf(stdout, "  rule=%q kind=%s offsets=%d-%d matched=%q\n", e.Keyword, e.Kind, e.Start, e.End, e.Matched)
+		if len(e.Tags) > 0 {
+			_, _ = fmt.Fprintf(stdout, "  tags=%s\n", strings.Join(e.Tags, ","))
+		}
+		for _, ev := range e.Evaluations {
+			_, _ = fmt.Fprintf(stdout, "    %-9s %-6s %q %s\n", ev.Decision, ev.Kind, ev.Keyword, ev.Detail)
+		}
+	}
+	return nil
+}
+
+// List the alerts tracked by a running instance
+func alertsCommand(ctx context.Context, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("alerts", flag.ContinueOnError)
+	fs.SetOutput(stdout)
+	limit := fs.Int("limit", 50, "maximum number of alerts to show")
+	state := fs.String("state", "", "only show alerts in this state: new, acked or resolved")
+	addr := fs.String("addr", "", "admin listener address (defaults to admin.listen from config)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(*limit))
+	if *state != "" {
+		query.Set("state", *state)
+	}
+
+	var alerts []acks.Alert
+	if err := adminGet(ctx, *addr, "/alerts", query, &alerts); err != nil {
+		return err
+	}
+
+	if len(alerts) == 0 {
+		_, _ = fmt.Fprintln(stdout, "No alerts tracked.")
+		return nil
+	}
+
+	for _, a := range alerts {
+		_, _ = fmt.Fprintf(stdout, "%-24s %-8s [%s] rule=%q chat=%s", a.ID, a.State, a.Created.Format(time.RFC3339), a.Rule, a.ChatTitle)
+		if a.By != "" {
+			_, _ = fmt.Fprintf(stdout, " by=%s", a.By)
+		}
+		_, _ = fmt.Fprintln(stdout)
+	}
+	return nil
+}
+
+// Move an alert of a running instance to state
+func setAlertCommand(ctx context.Context, name, state string, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stdout)
+	by := fs.String("by", os.Getenv("USER"), "name recorded as changing the state")
+	addr := fs.String("addr", "", "admin listener address (defaults to admin.listen from config)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: %s [flags] <alert id>", name)
+	}
+
+	query := url.Values{}
+	query.Set("id", fs.Arg(0))
+	query.Set("by", *by)
+
+	path := map[string]string{acks.StateAcked: "/alerts/ack", acks.StateResolved: "/alerts/resolve"}[state]
+	var a acks.Alert
+	if err := adminCall(ctx, http.MethodPost, *addr, path, query, &a); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(stdout, "Alert %s is now %s\n", a.ID, a.State)
+	return nil
+}
+
+// Show or switch the matching engine of a running instance
+func engineCommand(ctx context.Context, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("engine", flag.ContinueOnError)
+	fs.SetOutput(stdout)
+	addr := fs.String("addr", "", "admin listener address (defaults to admin.listen from config)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var info engineInfo
+	var err error
+	switch fs.NArg() {
+	case 0:
+		err = adminGet(ctx, *addr, "/engine", nil, &info)
+	case 1:
+		err = adminCall(ctx, http.MethodPost, *addr, "/engine/set", url.Values{"name": {fs.Arg(0)}}, &info)
+	default:
+		return errors.New("usage: engine [flags] [auto|naive|aho-corasick|regex|hyperscan]")
+	}
+	if err != nil {
+		return err
+	}
+
+	if info.Auto {
+		_, _ = fmt.Fprintf(stdout, "Matching engine: %s (picked automatically)\n", info.Engine)
+	} else {
+		_, _ = fmt.Fprintf(stdout, "Matching engine: %s\n", info.Engine)
+	}
+	return nil
+}
+
+// Export the chat graph of a running instance
+func graphCommand(ctx context.Context, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("graph", flag.ContinueOnError)
+	fs.SetOutput(stdout)
+	format := fs.String("format", graph.FormatDOT, "output format: dot or graphml")
+	output := fs.String("o", "", "write to this file instead of stdout")
+	addr := fs.String("addr", "", "admin listener address (defaults to admin.listen from config)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var snap graph.Snapshot
+	if err := adminGet(ctx, *addr, "/graph", nil, &snap); err != nil {
+		return err
+	}
+
+	if *output == "" {
+		return snap.Write(stdout, *format)
+	}
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if err := snap.Write(f, *format); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Report whether the running instance has a live Telegram connection
+func healthCommand(ctx context.Context, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("health", flag.ContinueOnError)
+	fs.SetOutput(stdout)
+	addr := fs.String("addr", "", "admin listener address or unix:/path (defaults to admin.listen from config)")
+	timeout := fs.Duration("timeout", 5*time.Second, "maximum time to wait for a response")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *addr == "" {
+		cfg, err := config.LoadFile(config.FilePath())
+		if err != nil {
+			return err
+		}
+		*addr = cfg.Admin.Listen
+	}
+	if *addr == "" {
+		// Nothing to probe, do not mark the container unhealthy
+		_, _ = fmt.Fprintln(stdout, "Admin listener is disabled, skipping health check.")
+		return nil
+	}
+
+	resp, err := adminRequest(ctx, http.MethodGet, *addr, "/healthz", nil, *timeout)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var st health.Status
+	if err := json.NewDecoder(resp.Body).Decode(&st); err != nil {
+		return fmt.Errorf("invalid health response (status %d): %w", resp.StatusCode, err)
+	}
+	if !st.Healthy {
+		return fmt.Errorf("unhealthy: connected=%t last_heartbeat=%s %s", st.Connected, st.LastHeartbeat.Format(time.RFC3339), st.Error)
+	}
+
+	_, _ = fmt.Fprintf(stdout, "Healthy, last heartbeat %s\n", st.LastHeartbeat.Format(time.RFC3339))
+	return nil
+}
+
+// Query the admin API of a running instance and decode the JSON response
+func adminGet(ctx context.Context, addr, path string, query url.Values, out any) error {
+	return adminCall(ctx, http.MethodGet, addr, path, query, out)
+}
+
+// Call the admin API of a running instance with method and decode the JSON response
+func adminCall(ctx context.Context, method, addr, path string, query url.Values, out any) error {
+	return adminCallWithin(ctx, method, addr, path, query, 10*time.Second, out)
+}
+
+// Call the admin API like adminCall, for requests that may take up to timeout
+func adminCallWithin(ctx context.Context, method, addr, path string, query url.Values, timeout time.Duration, out any) error {
+	if addr == "" {
+		cfg, err := config.LoadFile(config.FilePath())
+		if err != nil {
+			return err
+		}
+		addr = cfg.Admin.Listen
+	}
+	if addr == "" {
+		return fmt.Errorf("admin listener is disabled, set admin.listen in config")
+	}
+
+	resp, err := adminRequest(ctx, method, addr, path, query, timeout)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("admin api returned status %d: %s", resp.StatusCode, body)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// Send a request to the admin listener at addr
+func adminRequest(ctx context.Context, method, addr, path string, query url.Values, timeout time.Duration) (*http.Response, error) {
+	client, base := admin.NewClient(addr, timeout)
+	u := base + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token := os.Getenv("TELEGRAM_ADMIN_TOKEN"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach admin listener: %w", err)
+	}
+	return resp, nil
+}
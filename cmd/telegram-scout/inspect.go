@@ -0,0 +1,96 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/h3nc4/TelegramScout/internal/scout"
+)
+
+// Trace a message through the rules of a running instance, for finding out
+// why it did or did not alert
+func inspectCommand(ctx context.Context, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("inspect", flag.ContinueOnError)
+	fs.SetOutput(stdout)
+	addr := fs.String("addr", "", "admin listener address (defaults to admin.listen from config)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("usage: inspect [-addr host:port] <message link>")
+	}
+
+	var in scout.Inspection
+	if err := adminGet(ctx, *addr, "/inspect", url.Values{"link": {fs.Arg(0)}}, &in); err != nil {
+		return err
+	}
+	printInspection(stdout, in)
+	return nil
+}
+
+func printInspection(w io.Writer, in scout.Inspection) {
+	msg, exp := in.Message, in.Explanation
+	_, _ = fmt.Fprintf(w, "Message %d in %s (%d), sent %s\n", msg.ID, msg.ChatTitle, msg.ChatID, msg.Date.Format(time.RFC3339))
+	if msg.Text != "" {
+		_, _ = fmt.Fprintf(w, "  %q\n", msg.Text)
+	}
+	if msg.Media != "" {
+		_, _ = fmt.Fprintf(w, "  media=%s\n", msg.Media)
+	}
+
+	_, _ = fmt.Fprintln(w, "Rules:")
+	for _, ev := range exp.Evaluations {
+		_, _ = fmt.Fprintf(w, "  %-9s %-7s %q %s\n", ev.Decision, ev.Kind, ev.Keyword, ev.Detail)
+	}
+	if len(exp.Evaluations) == 0 {
+		_, _ = fmt.Fprintln(w, "  none configured")
+	}
+	if len(in.Shadow) > 0 {
+		_, _ = fmt.Fprintf(w, "Shadow rules matching: %s\n", strings.Join(in.Shadow, ", "))
+	}
+	if len(in.Counters) > 0 {
+		_, _ = fmt.Fprintf(w, "Counter rules matching: %s\n", strings.Join(in.Counters, ", "))
+	}
+
+	var result string
+	switch {
+	case in.Filtered != "":
+		result = fmt.Sprintf("skipped by prefilters (%s)", in.Filtered)
+		if in.Matched {
+			result += fmt.Sprintf(", rule %q would match otherwise", exp.Keyword)
+		}
+	case !in.Matched:
+		result = "no rule matches"
+	case in.Duplicate:
+		result = fmt.Sprintf("rule %q matches, but the message was already alerted on", exp.Keyword)
+	case in.Snoozed:
+		result = fmt.Sprintf("rule %q matches, but is snoozed in this chat", exp.Keyword)
+	default:
+		result = fmt.Sprintf("alert on rule %q (%s), matching %q", exp.Keyword, exp.Kind, exp.Matched)
+	}
+	_, _ = fmt.Fprintf(w, "Result: %s\n", result)
+}
@@ -24,17 +24,41 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	"go.uber.org/zap"
 
+	"github.com/h3nc4/TelegramScout/internal/access"
+	"github.com/h3nc4/TelegramScout/internal/acks"
+	"github.com/h3nc4/TelegramScout/internal/admin"
+	"github.com/h3nc4/TelegramScout/internal/annotate"
+	"github.com/h3nc4/TelegramScout/internal/archive"
+	"github.com/h3nc4/TelegramScout/internal/audit"
+	"github.com/h3nc4/TelegramScout/internal/cluster"
 	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/enrich"
+	"github.com/h3nc4/TelegramScout/internal/graph"
+	"github.com/h3nc4/TelegramScout/internal/health"
+	"github.com/h3nc4/TelegramScout/internal/join"
+	"github.com/h3nc4/TelegramScout/internal/leader"
 	"github.com/h3nc4/TelegramScout/internal/logger"
+	"github.com/h3nc4/TelegramScout/internal/media"
+	"github.com/h3nc4/TelegramScout/internal/metrics"
 	"github.com/h3nc4/TelegramScout/internal/model"
 	"github.com/h3nc4/TelegramScout/internal/notifier"
+	"github.com/h3nc4/TelegramScout/internal/presence"
+	"github.com/h3nc4/TelegramScout/internal/rules"
 	"github.com/h3nc4/TelegramScout/internal/scout"
+	"github.com/h3nc4/TelegramScout/internal/snooze"
+	"github.com/h3nc4/TelegramScout/internal/standing"
+	"github.com/h3nc4/TelegramScout/internal/sweep"
 	"github.com/h3nc4/TelegramScout/internal/telegram"
+	"github.com/h3nc4/TelegramScout/internal/temprules"
+	"github.com/h3nc4/TelegramScout/internal/urls"
+	"github.com/h3nc4/TelegramScout/internal/version"
+	"github.com/h3nc4/TelegramScout/internal/watch"
 )
 
 func main() {
@@ -43,19 +67,26 @@ func main() {
 	defer cancel()
 
 	// Initialize logger
-	log, err := logger.New()
+	log, err := logger.New(logger.Options{})
 	if err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "failed to initialize logger: %v\n", err)
 		os.Exit(1)
 	}
 	defer func() { _ = log.Sync() }()
 
+	// Dispatch CLI subcommands
+	if len(os.Args) > 1 {
+		code := runCommand(ctx, os.Args[1:], os.Stdout, os.Stderr)
+		cancel()
+		os.Exit(code)
+	}
+
 	if err := run(ctx, log); err != nil {
 		log.Fatal("Application startup failed", zap.Error(err))
 	}
 }
 
-func run(ctx context.Context, log *zap.Logger) error {
+func run(ctx context.Context, log *zap.Logger) (err error) {
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
@@ -66,36 +97,730 @@ func run(ctx context.Context, log *zap.Logger) error {
 		return fmt.Errorf("no chats configured for monitoring")
 	}
 
+	// Rebuild the logger with the configured format, levels and sampling
+	if log, err = logger.New(logger.Options{
+		JSON:             cfg.Log.Format == config.LogFormatJSON,
+		Level:            cfg.Log.Level,
+		Levels:           cfg.Log.Levels,
+		SampleInitial:    cfg.Log.Sampling.Initial,
+		SampleThereafter: cfg.Log.Sampling.Thereafter,
+	}); err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	defer func() { _ = log.Sync() }()
+	if inst := cfg.Instance; inst.Name != "" || len(inst.Labels) > 0 {
+		log = log.With(zap.String("instance", inst.Name), zap.Any("labels", inst.Labels))
+	}
+
+	// Rules of enabled packs join the configured ones
+	if err := applyPacks(cfg, log); err != nil {
+		return err
+	}
+
 	// Channel for streaming messages from Telegram client to Scout
-	msgChan := make(chan model.Message, 100)
+	msgChan := make(chan model.Message, cfg.Pipeline.QueueSize)
+
+	// Media downloads and account messages go through whichever client session is currently connected
+	holder := &telegram.Holder{}
+
+	// Initialize Notifier (Bot API), with the account as fallback or on its own
+	notifLog := logger.For(log, logger.Notifier)
+	notif, bot, err := newNotifier(cfg, holder, notifLog)
+	if err != nil {
+		return fmt.Errorf("failed to set up notifier: %w", err)
+	}
+	host, _ := os.Hostname()
+	if cfg.Notifier.Shutdown.Enabled {
+		started := time.Now()
+		defer func() {
+			p := recover()
+			sendShutdownNotice(ctx, cfg, notif, shutdownData{Host: host, Version: version.Get().String(), Uptime: time.Since(started).Round(time.Second)}, p, err, log)
+			if p != nil {
+				panic(p)
+			}
+		}()
+	}
 
-	// Initialize Notifier (Bot API)
-	notif := notifier.New(cfg, log)
+	// Connect to the state shared by the fleet, if any
+	var shared *cluster.Store
+	if cfg.Cluster.Redis != "" {
+		shared, err = cluster.New(ctx, cfg.Cluster)
+		if err != nil {
+			return fmt.Errorf("failed to connect to cluster backend: %w", err)
+		}
+		defer func() { _ = shared.Close() }()
+		log.Info("Sharing state with the cluster", zap.String("prefix", cfg.Cluster.Prefix))
+	}
+
+	// Keep alerts within the rate limit, counted across the fleet when clustered
+	var alerts notifier.Notifier = notif
+	if limit := cfg.Notifier.RateLimit; limit > 0 {
+		var limiter notifier.RateLimiter = notifier.NewWindowLimiter(limit, notifier.RateWindow)
+		if shared != nil {
+			limiter = shared.RateLimiter("notifier", limit, notifier.RateWindow)
+		}
+		alerts = notifier.NewThrottle(alerts, limiter, notifLog)
+	}
+
+	// Merge alert bursts when coalescing is enabled
+	if cfg.Notifier.Coalesce.Window > 0 {
+		alerts = notifier.NewCoalescer(alerts, cfg.Notifier.Coalesce, notifLog)
+	}
 
 	// Initialize Scout
-	s := scout.New(cfg, notif, log)
+	s := scout.New(cfg, alerts, logger.For(log, logger.Scout))
+	for _, f := range s.Lint() {
+		log.Warn("Rule is redundant or can be simpler", zap.String("keyword", f.Keyword), zap.String("problem", f.Problem), zap.String("suggestion", f.Suggestion))
+	}
+	if shared != nil {
+		s.UseSharedState(shared)
+	}
+	if len(cfg.Notifier.Webhooks) > 0 {
+		hooks, err := notifier.NewWebhooks(cfg.Notifier, notifLog)
+		if err != nil {
+			return fmt.Errorf("failed to set up webhooks: %w", err)
+		}
+		// Deliveries pending when the process stopped are resent on startup
+		if cfg.Notifier.Outbox.Enabled {
+			path, err := cfg.StatePath("outbox.json")
+			if err != nil {
+				return err
+			}
+			outbox, err := notifier.LoadOutbox(path)
+			if err != nil {
+				return fmt.Errorf("failed to load webhook outbox: %w", err)
+			}
+			hooks.UseOutbox(outbox)
+			go hooks.Run(ctx)
+			log.Info("Keeping webhook deliveries until accepted", zap.Int("pending", len(outbox.List())))
+		}
+		s.UseWebhooks(hooks)
+		log.Info("Delivering matches to webhooks", zap.Int("count", hooks.Len()))
+	}
+	if len(cfg.Mirrors) > 0 {
+		s.UseMirror(holder)
+		log.Info("Mirroring matched messages", zap.Int("mirrors", len(cfg.Mirrors)))
+	}
+	if len(cfg.Actions.Hooks) > 0 {
+		s.UseActor(holder)
+		log.Info("Running actions on matches", zap.Int("hooks", len(cfg.Actions.Hooks)), zap.Int("rate_limit", cfg.Actions.RateLimit), zap.Bool("confirm", cfg.Actions.Confirm))
+	}
+	switch {
+	case cfg.Canary.Chat == "":
+	case cfg.Canary.Via == config.CanaryViaBot && bot == nil:
+		log.Warn("Posting the canary as the bot needs TELEGRAM_BOT_TOKEN, ignoring canary")
+	default:
+		var poster scout.CanaryPoster = holder
+		if cfg.Canary.Via == config.CanaryViaBot {
+			poster = bot
+		}
+		s.UseCanary(poster)
+		log.Info("Posting canaries", zap.String("chat", cfg.Canary.Chat), zap.String("via", cfg.Canary.Via), zap.Duration("interval", cfg.Canary.Interval))
+	}
+
+	// Track alert acknowledgement
+	var ackStore *acks.Store
+	if cfg.Acks.Enabled {
+		ackStore = acks.New(cfg.Acks.Size)
+		s.UseAcks(ackStore)
+	}
+
+	// Record who changed what at runtime, and config file edits between runs
+	var auditLog *audit.Log
+	if cfg.Audit.Enabled {
+		path, err := cfg.StatePath("audit.jsonl")
+		if err != nil {
+			return err
+		}
+		auditLog = audit.New(path)
+		s.UseAudit(auditLog)
+		if err := auditConfigChange(auditLog, path, cfg.ConfigFilePath); err != nil {
+			log.Error("Failed to audit config file", zap.Error(err))
+		}
+	}
+
+	// Mute rules per chat from bot commands and buttons, kept across restarts
+	if cfg.Commands.Enabled || (cfg.Acks.Enabled && cfg.Acks.Snooze > 0) {
+		path, err := cfg.StatePath("snoozes.json")
+		if err != nil {
+			return err
+		}
+		snoozes, err := snooze.Load(path)
+		if err != nil {
+			return fmt.Errorf("failed to load snoozes: %w", err)
+		}
+		s.UseSnoozes(snoozes)
+	}
+
+	// Match keywords added by bot commands until they expire, kept across restarts
+	if cfg.Commands.Enabled {
+		path, err := cfg.StatePath("temporary_rules.json")
+		if err != nil {
+			return err
+		}
+		temporary, err := temprules.Load(path)
+		if err != nil {
+			return fmt.Errorf("failed to load temporary rules: %w", err)
+		}
+		s.UseTemporaryRules(temporary)
+	}
+
+	// Answer alert buttons and commands through the bot
+	var handlers notifier.BotHandlers
+	if cfg.Acks.Enabled && cfg.Acks.Buttons {
+		handlers.Buttons = s.HandleButton
+	}
+	s.UseMessageFetcher(holder)
+	if cfg.Commands.Enabled {
+		s.UseHistorySearcher(holder)
+		handlers.Commands = s.Commands()
+	}
+	policy := access.New(cfg.Access)
+	if policy.RestrictsUsers() {
+		handlers.Allow = func(userID int64, name string) error {
+			return policy.User(userID, scout.CommandRole(name))
+		}
+	}
+	if cfg.Commands.Inline {
+		// Inline queries come from any chat, only configured users may read matches
+		if len(cfg.Access.Users) > 0 {
+			handlers.Inline = s.InlineQuery
+		} else {
+			log.Warn("Inline queries need access.users, ignoring commands.inline")
+		}
+	}
+	switch {
+	case bot != nil && (handlers.Buttons != nil || len(handlers.Commands) > 0 || handlers.Inline != nil):
+		go bot.Poll(ctx, handlers)
+	case bot == nil && (cfg.Commands.Enabled || cfg.Commands.Inline):
+		log.Warn("Bot commands need TELEGRAM_BOT_TOKEN, ignoring commands.enabled")
+	}
+
+	// Map forwards and mentions between chats, kept across restarts
+	var chatGraph *graph.Graph
+	if cfg.Graph.Enabled {
+		path, err := cfg.StatePath("graph.json")
+		if err != nil {
+			return err
+		}
+		chatGraph, err = graph.Load(path)
+		if err != nil {
+			return fmt.Errorf("failed to load chat graph: %w", err)
+		}
+		s.UseGraph(chatGraph)
+		go saveGraph(ctx, chatGraph, path, cfg.Graph.SaveInterval, log)
+		defer func() {
+			if err := chatGraph.Save(path); err != nil {
+				log.Error("Failed to save chat graph", zap.Error(err))
+			}
+		}()
+	}
+
+	if cfg.Media.Endpoint != "" {
+		store, err := media.NewS3Store(cfg.Media)
+		if err != nil {
+			return fmt.Errorf("failed to set up media archive: %w", err)
+		}
+		s.UseMediaArchiver(media.NewArchiver(holder, store, cfg.Media))
+		log.Info("Archiving matched media", zap.String("endpoint", cfg.Media.Endpoint), zap.String("bucket", cfg.Media.Bucket))
+		if cfg.Media.PublicURL == "" {
+			log.Warn("Archived media links expire after 7 days, set media_archive.public_url for lasting links")
+		}
+
+		// Screenshots are kept next to the archived media
+		if cfg.Shots.Endpoint != "" {
+			s.UseScreenshotter(media.NewScreenshotter(store, cfg.Shots, cfg.Media.Prefix))
+			log.Info("Rendering linked pages", zap.String("endpoint", cfg.Shots.Endpoint))
+		}
+	}
+	if cfg.Enrich.Enabled {
+		enricher := enrich.New(holder, cfg.Enrich, log)
+		s.UseEnricher(enricher)
+		log.Info("Enriching alerts about shared files", zap.Strings("lookups", enricher.Lookups()))
+	}
+	if cfg.Mentions.Enabled {
+		s.UseChatResolver(holder)
+	}
+	if cfg.URLs.Expand {
+		s.UseLinkExpander(urls.NewExpander(cfg.URLs))
+	}
+	if len(cfg.Monitoring.Images) > 0 {
+		images, err := media.NewImageMatcher(holder, cfg.Monitoring.Images)
+		if err != nil {
+			return fmt.Errorf("failed to load image rules: %w", err)
+		}
+		s.UseImageMatcher(images)
+		log.Info("Loaded reference images", zap.Int("count", images.Len()))
+	}
+
+	// Push counters for deployments where nothing scrapes /debug/vars
+	if cfg.Metrics.Pushgateway != "" || cfg.Metrics.StatsD != "" {
+		go metrics.New(cfg.Metrics, cfg.Instance, log).Run(ctx)
+		log.Info("Pushing metrics", zap.String("pushgateway", cfg.Metrics.Pushgateway), zap.String("statsd", cfg.Metrics.StatsD), zap.Duration("interval", cfg.Metrics.Interval))
+	}
+
+	// Archive messages off the message path, flushing the buffer before exiting
+	var archiveStore archive.Store
+	if cfg.Archive.Enabled {
+		dir, err := cfg.StatePath("archive")
+		if err != nil {
+			return err
+		}
+		spill, err := cfg.StatePath("archive-spill.jsonl")
+		if err != nil {
+			return err
+		}
+		var files *archive.Files
+		if ch := cfg.Archive.ClickHouse; ch.URL != "" {
+			archiveStore = archive.NewClickHouse(ch)
+		} else {
+			files = archive.NewFiles(dir)
+			archiveStore = files
+		}
+		if err := archiveStore.Migrate(ctx); err != nil {
+			return fmt.Errorf("failed to migrate archive: %w", err)
+		}
+		writer := archive.NewWriter(archiveStore, cfg.Archive, spill, log)
+		s.UseArchive(writer)
+		s.UseHistoryReader(holder)
+		archiveCtx, stopArchive := context.WithCancel(ctx)
+		var wg sync.WaitGroup
+		wg.Go(func() { writer.Run(archiveCtx) })
+		if files != nil && cfg.Archive.CompactInterval > 0 {
+			go files.RunCompaction(ctx, cfg.Archive.CompactInterval, log)
+		}
+		defer func() {
+			stopArchive()
+			wg.Wait()
+		}()
+		if sum := cfg.Archive.Summary; sum.Schedule != "" {
+			go runSummary(ctx, sum, archiveStore, notif.Send, log)
+			log.Info("Scheduled archive summary", zap.String("schedule", sum.Schedule), zap.Int("top", sum.Top))
+		}
+		if files != nil {
+			log.Info("Archiving messages", zap.String("dir", dir), zap.Bool("all", cfg.Archive.All))
+		} else {
+			log.Info("Archiving messages to ClickHouse", zap.String("database", cfg.Archive.ClickHouse.Database), zap.String("table", cfg.Archive.ClickHouse.Table), zap.Bool("all", cfg.Archive.All))
+		}
+	}
+
+	// Mark restarts, reconnects, alert storms and keyword reloads on dashboards
+	annotator := annotate.New(cfg.Annotate, log)
+	s.UseAlertCounter(annotator)
+	go annotator.Run(ctx)
+	compiled, _ := s.Rules()
+	engine, auto := s.Engine()
+	log.Info("Matching keywords", zap.String("engine", engine), zap.Bool("auto", auto))
+	annotator.Event(ctx, annotate.KindStart, fmt.Sprintf("Started %s with %d rules", version.Get().Version, compiled))
 
 	// Start Scout consumer in background
 	go s.Start(ctx, msgChan)
 
+	// Search chat history on a schedule, alongside live monitoring
+	if len(cfg.Sweeps) > 0 {
+		path, err := cfg.StatePath("sweeps.json")
+		if err != nil {
+			return err
+		}
+		sweeps, err := sweep.New(cfg.Sweeps, holder, path, s.SweepMatch, log)
+		if err != nil {
+			return fmt.Errorf("failed to set up sweeps: %w", err)
+		}
+		go sweeps.Run(ctx)
+		log.Info("Scheduled history sweeps", zap.Int("count", len(cfg.Sweeps)))
+	}
+
+	// Poll watched messages for edits and deletions, and watched users for profile changes
+	if len(cfg.Watch.Messages) > 0 || len(cfg.Watch.Users) > 0 {
+		path, err := cfg.StatePath("watch.json")
+		if err != nil {
+			return err
+		}
+		watcher, err := watch.New(cfg.Watch, holder, path, s.WatchChange, log)
+		if err != nil {
+			return fmt.Errorf("failed to set up message watch: %w", err)
+		}
+		go watcher.Run(ctx)
+		log.Info("Watching messages and users", zap.Int("messages", len(cfg.Watch.Messages)), zap.Int("users", len(cfg.Watch.Users)), zap.Duration("interval", cfg.Watch.Interval))
+	}
+
+	// Join chats from configured links, a few at a time so the account is not limited
+	if len(cfg.Join.Invites) > 0 {
+		path, err := cfg.StatePath("join.json")
+		if err != nil {
+			return err
+		}
+		queue, err := join.New(cfg.Join, holder, path, log)
+		if err != nil {
+			return fmt.Errorf("failed to set up auto-join: %w", err)
+		}
+		go queue.Run(ctx)
+		log.Info("Queued chats to join", zap.Int("pending", len(queue.Pending())), zap.Int("daily_cap", cfg.Join.DailyCap))
+	}
+
+	// Watch the account itself for restrictions that silently cut content off
+	var monitor *standing.Monitor
+	if cfg.Standing.Enabled {
+		path, err := cfg.StatePath("standing.json")
+		if err != nil {
+			return err
+		}
+		monitor, err = standing.New(cfg.Standing, holder, path, s.StandingChange, log)
+		if err != nil {
+			return fmt.Errorf("failed to set up account standing checks: %w", err)
+		}
+		go monitor.Run(ctx)
+		log.Info("Checking account standing", zap.Duration("interval", cfg.Standing.Interval), zap.Bool("spambot", cfg.Standing.SpamBot))
+	}
+
+	// Follow the online status of configured users, opt-in as it reveals activity windows
+	var presenceTracker *presence.Tracker
+	if len(cfg.Presence.Users) > 0 {
+		path, err := cfg.StatePath("presence.json")
+		if err != nil {
+			return err
+		}
+		presenceTracker, err = presence.Load(cfg.Presence, path)
+		if err != nil {
+			return fmt.Errorf("failed to load presence timeline: %w", err)
+		}
+		log.Info("Tracking user presence", zap.Int("users", len(cfg.Presence.Users)), zap.Duration("alert_every", cfg.Presence.AlertEvery))
+	}
+
+	// Consider the connection wedged after several missed heartbeats
+	tracker := health.NewTracker(3 * telegram.HeartbeatInterval)
+
+	// Start admin listener in background
+	adminSrv := admin.New(cfg, log)
+	reload := func() error { return reloadKeywords(ctx, cfg.ConfigFilePath, s, annotator, log) }
+	go reloadOnHangup(ctx, reload, log)
+	registerAdminRoutes(adminSrv, s, tracker, annotator, reload)
+	registerBackfillRoutes(ctx, adminSrv, s)
+	registerChatRoutes(adminSrv, holder)
+	if ackStore != nil {
+		registerAckRoutes(adminSrv, ackStore, auditLog)
+	}
+	if chatGraph != nil {
+		adminSrv.Handle("/graph", graphHandler(chatGraph))
+	}
+	if presenceTracker != nil {
+		adminSrv.Handle("/presence", presenceHandler(presenceTracker))
+	}
+	if monitor != nil {
+		adminSrv.Handle("/standing", standingHandler(monitor))
+	}
+	if archiveStore != nil {
+		registerArchiveRoutes(adminSrv, archiveStore)
+	}
+	go func() {
+		if err := adminSrv.Run(ctx); err != nil {
+			log.Error("Admin listener failed", zap.Error(err))
+		}
+	}()
+
+	info := version.Get()
 	log.Info("Starting TelegramScout",
+		zap.String("version", info.String()),
 		zap.Int("monitored_chats", len(cfg.Monitoring.Chats)),
 		zap.Int("keywords", len(cfg.Monitoring.Keywords)),
 	)
 
-	// Send startup notification
-	if err := notif.Send(ctx, "TelegramScout is now online and monitoring."); err != nil {
-		log.Error("failed to send startup notification", zap.Error(err))
+	// Send startup summary once the chats are resolved for the first time,
+	// and warn whenever the set of unresolved chats changes
+	var startupOnce sync.Once
+	var lastFailures, lastHints string
+	onResolved := func(ctx context.Context, report telegram.ResolveReport) {
+		startupOnce.Do(func() {
+			if !cfg.Notifier.Startup.Enabled {
+				return
+			}
+			compiled, rejected := s.Rules()
+			data := startupData{Host: host, Version: info.String(), Rules: compiled, Rejected: len(rejected), Chats: len(report.Resolved), Failed: len(report.Failed)}
+			text, err := renderLifecycle(cfg.Notifier.Startup.Template, data, startupSummary(info, report, compiled, rejected))
+			if err != nil {
+				log.Error("Failed to render startup template, sending the built-in summary", zap.Error(err))
+			}
+			if err := notif.Send(ctx, text); err != nil {
+				log.Error("failed to send startup notification", zap.Error(err))
+			}
+		})
+
+		if key := hintKey(report.Resolved); key != lastHints {
+			lastHints = key
+			if key != "" {
+				if err := notif.Send(ctx, outdatedAlert(report.Resolved)); err != nil {
+					log.Error("failed to send outdated chats alert", zap.Error(err))
+				}
+			}
+		}
+
+		key := failureKey(report.Failed)
+		if key == lastFailures {
+			return
+		}
+		lastFailures = key
+		if len(report.Failed) == 0 {
+			return
+		}
+		if err := notif.Send(ctx, unresolvedAlert(report.Failed)); err != nil {
+			log.Error("failed to send unresolved chats alert", zap.Error(err))
+		}
 	}
 
-	// Enter supervisor loop
-	runSupervisor(ctx, cfg, log, msgChan)
+	// Where configured usernames last pointed, to follow renamed chats
+	known, err := loadKnownChats(cfg, log)
+	if err != nil {
+		return err
+	}
+
+	hooks := sessionHooks{onResolved: onResolved, health: tracker, media: holder, known: known}
+	hooks.migrated = func(ctx context.Context, m telegram.Migration) {
+		go func() {
+			if err := notif.Send(ctx, migrationNotice(m)); err != nil {
+				log.Error("failed to send migration notice", zap.Error(err))
+			}
+		}()
+	}
+	hooks.restart = func(err error) {
+		annotator.Event(ctx, annotate.KindReconnect, "Telegram client reconnecting: "+err.Error())
+	}
+	if monitor != nil {
+		hooks.notice = func(text string, at time.Time) {
+			go monitor.Notice(ctx, text, at)
+		}
+	}
+	if presenceTracker != nil {
+		hooks.presence = func(userID int64, online bool, at time.Time) {
+			changed, alert, err := presenceTracker.Record(userID, online, at)
+			if err != nil {
+				log.Error("Failed to save presence timeline", zap.Error(err))
+			}
+			if changed {
+				log.Debug("User presence changed", zap.Int64("user", userID), zap.Bool("online", online))
+			}
+			if alert {
+				s.PresenceChange(ctx, userID, at)
+			}
+		}
+	}
+
+	// A hot spare account monitors the same chats, the pipeline drops its copies
+	var spareTracker *health.Tracker
+	run := func(ctx context.Context) {
+		runSupervisor(ctx, cfg, log, msgChan, hooks)
+	}
+	if cfg.Spare.Phone != "" {
+		spareTracker = health.NewTracker(3 * telegram.HeartbeatInterval)
+		tracker.WithSpare(spareTracker)
+		spareLog := log.With(zap.String("account", "spare"))
+		spareHooks := hooks
+		spareHooks.health = spareTracker
+		spareHooks.notice = nil
+		spareHooks.migrated = nil
+		spareHooks.known = nil // Access hashes differ between accounts
+		spareHooks.restart = func(err error) {
+			annotator.Event(ctx, annotate.KindReconnect, "Spare Telegram client reconnecting: "+err.Error())
+		}
+		spareHooks.onResolved = func(ctx context.Context, report telegram.ResolveReport) {
+			if len(report.Failed) > 0 {
+				spareLog.Warn("Spare account could not resolve some chats", zap.Int("failed", len(report.Failed)))
+			}
+		}
+		run = func(ctx context.Context) {
+			var wg sync.WaitGroup
+			wg.Go(func() { runSupervisor(ctx, cfg.ForSpare(), spareLog, msgChan, spareHooks) })
+			runSupervisor(ctx, cfg, log, msgChan, hooks)
+			wg.Wait()
+		}
+		log.Info("Running a hot spare account")
+	}
+
+	// With leader election, only the leader connects to Telegram
+	if cfg.Leader.Backend != "" {
+		elector, err := leader.New(cfg.Leader, log)
+		if err != nil {
+			return fmt.Errorf("failed to set up leader election: %w", err)
+		}
+		elector.OnChange(func(leading bool) {
+			tracker.Standby(!leading)
+			if spareTracker != nil {
+				spareTracker.Standby(!leading)
+			}
+		})
+		elector.Run(ctx, run)
+	} else {
+		// Enter supervisor loop
+		run(ctx)
+	}
 
 	log.Info("TelegramScout shutdown complete")
 	return nil
 }
 
-func runSupervisor(ctx context.Context, cfg *config.Config, log *zap.Logger, msgChan chan<- model.Message) {
+// Longest wait for the shutdown notification to go out
+const shutdownNoticeTimeout = 10 * time.Second
+
+// Announce that the process is stopping, after a panic p, a setup error err or a signal
+func sendShutdownNotice(ctx context.Context, cfg *config.Config, notif notifier.Notifier, data shutdownData, p any, err error, log *zap.Logger) {
+	switch {
+	case p != nil:
+		data.Reason, data.Error = shutdownCrash, fmt.Sprint(p)
+	case err != nil:
+		data.Reason, data.Error = shutdownConfig, err.Error()
+	default:
+		data.Reason = shutdownSignal
+	}
+	text, rerr := renderLifecycle(cfg.Notifier.Shutdown.Template, data, shutdownNotice(data))
+	if rerr != nil {
+		log.Error("Failed to render shutdown template, sending the built-in notice", zap.Error(rerr))
+	}
+
+	// The run context is already cancelled when shutting down on a signal
+	sendCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownNoticeTimeout)
+	defer cancel()
+	if err := notif.Send(sendCtx, text); err != nil {
+		log.Error("Failed to send shutdown notification", zap.Error(err))
+	}
+}
+
+// Load where configured usernames last pointed, in memory only when the
+// state directory cannot be created, as in a read-only container with the
+// session in TELEGRAM_SESSION
+func loadKnownChats(cfg *config.Config, log *zap.Logger) (*telegram.KnownChats, error) {
+	path, err := cfg.StatePath("usernames.json")
+	if err != nil {
+		log.Warn("Renamed chats will not be remembered across restarts", zap.Error(err))
+		path = ""
+	}
+	known, err := telegram.LoadKnownChats(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load known chats: %w", err)
+	}
+	return known, nil
+}
+
+// Add the rules of the enabled packs to cfg
+func applyPacks(cfg *config.Config, log *zap.Logger) error {
+	if len(cfg.Packs.Enabled) == 0 {
+		return nil
+	}
+	dir, err := cfg.StatePath("packs")
+	if err != nil {
+		return err
+	}
+	for _, name := range cfg.Packs.Enabled {
+		pack, source, err := rules.Open(name, dir)
+		if err != nil {
+			return fmt.Errorf("failed to load rule pack: %w", err)
+		}
+		added := rules.Apply(cfg, pack)
+		log.Info("Enabled rule pack", zap.String("pack", name), zap.Int("revision", pack.Revision), zap.Int("rules", added), zap.String("source", source))
+	}
+	return nil
+}
+
+// Reload the keywords, with those of enabled packs, from the config file.
+// Other settings keep their values until restart.
+func reloadKeywords(ctx context.Context, path string, s *scout.Scout, events *annotate.Annotator, log *zap.Logger) error {
+	cfg, err := config.LoadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := applyPacks(cfg, log); err != nil {
+		return err
+	}
+	s.ReloadKeywords(ctx, cfg.Monitoring.Keywords)
+	events.Event(ctx, annotate.KindReload, fmt.Sprintf("Reloaded %d keywords", len(cfg.Monitoring.Keywords)))
+	return nil
+}
+
+// Reload the keywords whenever the process gets SIGHUP
+func reloadOnHangup(ctx context.Context, reload func() error, log *zap.Logger) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			if err := reload(); err != nil {
+				log.Error("Failed to reload keywords, keeping the current ones", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Build the alert notifier from the bot, the account and stdout, whichever are configured.
+// The account backs the bot up when both are set. The bot is also returned, nil without a token.
+func newNotifier(cfg *config.Config, holder *telegram.Holder, log *zap.Logger) (notifier.Notifier, *notifier.TelegramNotifier, error) {
+	var notifiers []notifier.Notifier
+	var account notifier.Notifier
+	if cfg.Notifier.Account.Enabled {
+		account = notifier.NewAccount(holder, cfg.Notifier, log)
+	}
+
+	var bot *notifier.TelegramNotifier
+	switch {
+	case cfg.BotToken != "":
+		var err error
+		bot, err = notifier.New(cfg, log)
+		if err != nil {
+			return nil, nil, err
+		}
+		if account != nil {
+			notifiers = append(notifiers, notifier.NewFallback(bot, account, log))
+		} else {
+			notifiers = append(notifiers, bot)
+		}
+	case account != nil:
+		log.Info("Sending alerts as the account, no bot token configured")
+		notifiers = append(notifiers, account)
+	}
+	if cfg.Notifier.Stdout {
+		notifiers = append(notifiers, notifier.NewWriter(os.Stdout, cfg.Notifier.StdoutProfile))
+	}
+
+	switch len(notifiers) {
+	case 0:
+		log.Info("No alert notifier configured, matches only go to webhooks")
+		return notifier.Discard{}, nil, nil
+	case 1:
+		return notifiers[0], bot, nil
+	}
+	return notifier.NewMulti(log, notifiers...), bot, nil
+}
+
+// Write the chat graph to path periodically, the final save happens on shutdown
+func saveGraph(ctx context.Context, g *graph.Graph, path string, interval time.Duration, log *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := g.Save(path); err != nil {
+				log.Error("Failed to save chat graph", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Callbacks and state shared by every client session
+type sessionHooks struct {
+	onResolved func(context.Context, telegram.ResolveReport)
+	health     *health.Tracker
+	media      *telegram.Holder
+	presence   func(userID int64, online bool, at time.Time)
+	notice     func(text string, at time.Time)
+	migrated   func(context.Context, telegram.Migration)
+	restart    func(err error)
+	known      *telegram.KnownChats
+	restarted  bool // A previous session of this supervisor ended
+}
+
+func runSupervisor(ctx context.Context, cfg *config.Config, log *zap.Logger, msgChan chan<- model.Message, hooks sessionHooks) {
 	backoff := time.Second
 	maxBackoff := 1 * time.Minute
 
@@ -106,7 +831,9 @@ func runSupervisor(ctx context.Context, cfg *config.Config, log *zap.Logger, msg
 			return
 		}
 
-		shouldRetry, err := startClientSession(ctx, cfg, log, msgChan)
+		shouldRetry, err := startClientSession(ctx, cfg, log, msgChan, hooks)
+		// Sessions after this one read back what they may have missed
+		hooks.restarted = true
 		if !shouldRetry {
 			if err != nil {
 				// Fatal error during initialization
@@ -118,6 +845,9 @@ func runSupervisor(ctx context.Context, cfg *config.Config, log *zap.Logger, msg
 
 		// Runtime error, attempt restart
 		log.Error("Telegram client crashed, restarting...", zap.Error(err), zap.Duration("backoff", backoff))
+		if hooks.restart != nil {
+			hooks.restart(err)
+		}
 
 		select {
 		case <-ctx.Done():
@@ -132,15 +862,49 @@ func runSupervisor(ctx context.Context, cfg *config.Config, log *zap.Logger, msg
 	}
 }
 
-func startClientSession(ctx context.Context, cfg *config.Config, log *zap.Logger, msgChan chan<- model.Message) (bool, error) {
+func startClientSession(ctx context.Context, cfg *config.Config, log *zap.Logger, msgChan chan<- model.Message, hooks sessionHooks) (bool, error) {
 	log.Info("Initializing Telegram Client...")
-	client, err := telegram.NewClient(cfg, log, msgChan)
+	client, err := telegram.NewClient(cfg, logger.For(log, logger.Telegram), msgChan)
 	if err != nil {
 		return false, err
 	}
+	if cfg.Log.RecordUpdates {
+		path, err := cfg.StatePath("updates.jsonl")
+		if err != nil {
+			return false, err
+		}
+		rec, err := telegram.OpenRecorder(path)
+		if err != nil {
+			return false, err
+		}
+		defer func() { _ = rec.Close() }()
+		client.RecordUpdates(rec)
+		log.Warn("Recording raw updates, turn off once the capture is done", zap.String("path", path))
+	}
+	client.OnResolved(hooks.onResolved)
+	client.OnHeartbeat(hooks.health.Beat)
+	if hooks.presence != nil {
+		client.OnPresence(hooks.presence)
+	}
+	if hooks.notice != nil {
+		client.OnServiceNotice(hooks.notice)
+	}
+	if hooks.migrated != nil {
+		client.OnMigrated(hooks.migrated)
+	}
+	if hooks.known != nil {
+		client.UseKnownChats(hooks.known)
+	}
+	if hooks.restarted && cfg.Pipeline.CatchUp > 0 {
+		client.CatchUp(cfg.Pipeline.CatchUp, cfg.Pipeline.CatchUpMessages)
+	}
+	hooks.media.Attach(client)
+	defer hooks.media.Detach(client)
 
 	// Run Telegram Client (Blocking)
-	if err := client.Run(ctx); err != nil {
+	err = client.Run(ctx)
+	hooks.health.Disconnected(err)
+	if err != nil {
 		// If context is canceled, it's a graceful shutdown
 		if errors.Is(err, context.Canceled) {
 			log.Info("Telegram client stopped (context canceled)")
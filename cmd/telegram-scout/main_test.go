@@ -19,15 +19,40 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
 	"testing"
 	"time"
+	"unicode/utf8"
 
+	"github.com/gotd/td/tg"
 	"go.uber.org/zap"
 
+	"github.com/h3nc4/TelegramScout/internal/acks"
+	"github.com/h3nc4/TelegramScout/internal/admin"
+	"github.com/h3nc4/TelegramScout/internal/annotate"
+	"github.com/h3nc4/TelegramScout/internal/archive"
+	"github.com/h3nc4/TelegramScout/internal/audit"
 	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/graph"
+	"github.com/h3nc4/TelegramScout/internal/health"
+	"github.com/h3nc4/TelegramScout/internal/mentions"
 	"github.com/h3nc4/TelegramScout/internal/model"
+	"github.com/h3nc4/TelegramScout/internal/render"
 	"github.com/h3nc4/TelegramScout/internal/scout"
+	"github.com/h3nc4/TelegramScout/internal/telegram"
+	"github.com/h3nc4/TelegramScout/internal/version"
 )
 
 // Implement notifier.Notifier for testing
@@ -78,3 +103,936 @@ func TestWiring(t *testing.T) {
 		t.Errorf("expected 1 notification, got %d", notif.CallCount)
 	}
 }
+
+func TestNewNotifier(t *testing.T) {
+	log := zap.NewNop()
+	tests := []struct {
+		name string
+		cfg  config.Config
+		want string
+	}{
+		{"Webhooks only", config.Config{}, "notifier.Discard"},
+		{"Bot", config.Config{BotToken: "token", ChatID: 1}, "*notifier.TelegramNotifier"},
+		{"Account", config.Config{Notifier: config.NotifierConfig{Account: config.AccountConfig{Enabled: true}}}, "*notifier.AccountNotifier"},
+		{"Bot and account", config.Config{BotToken: "token", ChatID: 1, Notifier: config.NotifierConfig{Account: config.AccountConfig{Enabled: true}}}, "*notifier.Fallback"},
+		{"Bot and stdout", config.Config{BotToken: "token", ChatID: 1, Notifier: config.NotifierConfig{Stdout: true}}, "*notifier.Multi"},
+		{"Stdout", config.Config{Notifier: config.NotifierConfig{Stdout: true}}, "*notifier.WriterNotifier"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, _, err := newNotifier(&tt.cfg, &telegram.Holder{}, log)
+			if err != nil {
+				t.Fatalf("newNotifier() error = %v", err)
+			}
+			if got := fmt.Sprintf("%T", n); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestExplainCommand(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/explain" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("chat") != "100" {
+			t.Errorf("expected chat filter, got %q", r.URL.RawQuery)
+		}
+		_ = json.NewEncoder(w).Encode([]scout.Explanation{{
+			ChatID:  100,
+			MsgID:   7,
+			Keyword: "bitcoin",
+			Kind:    "word",
+			Matched: "Bitcoin",
+			End:     7,
+		}})
+	}))
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	addr := strings.TrimPrefix(server.URL, "http://")
+	code := runCommand(context.Background(), []string{"explain", "-addr", addr, "-chat", "100"}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), `rule="bitcoin"`) {
+		t.Errorf("unexpected output: %s", stdout.String())
+	}
+
+	if code := runCommand(context.Background(), []string{"bogus"}, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit code 2 for unknown command, got %d", code)
+	}
+}
+
+func TestAckCommands(t *testing.T) {
+	store := acks.New(10)
+	store.Add(acks.Alert{Rule: "bitcoin", ChatID: 100, ChatTitle: "Crypto", MsgID: 7})
+	srv := admin.New(&config.Config{}, zap.NewNop())
+	auditPath := filepath.Join(t.TempDir(), "audit.jsonl")
+	registerAckRoutes(srv, store, audit.New(auditPath))
+	server := httptest.NewServer(srv.Handler())
+	defer server.Close()
+	addr := strings.TrimPrefix(server.URL, "http://")
+
+	var stdout, stderr bytes.Buffer
+	if code := runCommand(context.Background(), []string{"ack", "-addr", addr, "-by", "alice", "100:7"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if a, _ := store.Get("100:7"); a.State != acks.StateAcked || a.By != "alice" {
+		t.Errorf("expected alert acked by alice, got %+v", a)
+	}
+
+	stdout.Reset()
+	if code := runCommand(context.Background(), []string{"alerts", "-addr", addr, "-state", "acked"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), `rule="bitcoin"`) || !strings.Contains(stdout.String(), "by=alice") {
+		t.Errorf("unexpected output: %s", stdout.String())
+	}
+
+	if code := runCommand(context.Background(), []string{"ack", "-addr", addr, "100:7"}, &stdout, &stderr); code != 1 {
+		t.Errorf("expected exit code 1 acking twice, got %d", code)
+	}
+	if code := runCommand(context.Background(), []string{"resolve", "-addr", addr, "100:7"}, &stdout, &stderr); code != 0 {
+		t.Errorf("expected exit code 0 resolving, got %d: %s", code, stderr.String())
+	}
+
+	entries, err := audit.Read(auditPath, audit.Query{})
+	if err != nil {
+		t.Fatalf("failed to read audit log: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Action != "ack" || entries[0].Actor != "alice" || entries[1].Action != "resolve" || entries[1].Source != audit.SourceAPI {
+		t.Errorf("unexpected audit entries %+v", entries)
+	}
+}
+
+func TestEngineCommand(t *testing.T) {
+	cfg := &config.Config{Monitoring: config.MonitoringRules{Keywords: []string{"gpu"}}}
+	s := scout.New(cfg, &MockNotifier{}, zap.NewNop())
+	srv := admin.New(&config.Config{}, zap.NewNop())
+	registerAdminRoutes(srv, s, health.NewTracker(time.Minute), annotate.New(config.AnnotationsConfig{}, zap.NewNop()), nil)
+	server := httptest.NewServer(srv.Handler())
+	defer server.Close()
+	addr := strings.TrimPrefix(server.URL, "http://")
+
+	var stdout, stderr bytes.Buffer
+	if code := runCommand(context.Background(), []string{"engine", "-addr", addr}, &stdout, &stderr); code != 0 {
+		t.Fatalf("engine failed with %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "naive (picked automatically)") {
+		t.Errorf("unexpected engine output: %s", stdout.String())
+	}
+
+	stdout.Reset()
+	if code := runCommand(context.Background(), []string{"engine", "-addr", addr, "regex"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("engine switch failed with %d: %s", code, stderr.String())
+	}
+	if name, auto := s.Engine(); name != config.EngineRegex || auto {
+		t.Errorf("expected the regex engine selected, got %s (auto %v)", name, auto)
+	}
+	if code := runCommand(context.Background(), []string{"engine", "-addr", addr, "hyperscan"}, &stdout, &stderr); code != 1 {
+		t.Errorf("expected switching to an unavailable engine to fail, got %d", code)
+	}
+}
+
+func TestRulesReload(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{Keywords: []string{"gpu", "ram"}},
+		Matching:   config.MatchingConfig{Soak: time.Hour},
+	}
+	s := scout.New(cfg, &MockNotifier{}, zap.NewNop())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reload := func() error {
+		s.ReloadKeywords(ctx, []string{"gpu", "cpu"})
+		return nil
+	}
+	srv := admin.New(&config.Config{}, zap.NewNop())
+	registerAdminRoutes(srv, s, health.NewTracker(time.Minute), annotate.New(config.AnnotationsConfig{}, zap.NewNop()), reload)
+	server := httptest.NewServer(srv.Handler())
+	defer server.Close()
+	addr := strings.TrimPrefix(server.URL, "http://")
+
+	var stdout, stderr bytes.Buffer
+	if code := runCommand(ctx, []string{"rules", "soak", "-addr", addr}, &stdout, &stderr); code != 0 {
+		t.Fatalf("rules soak failed with %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "No reloaded keywords are soaking") {
+		t.Errorf("unexpected soak output: %s", stdout.String())
+	}
+
+	stdout.Reset()
+	if code := runCommand(ctx, []string{"rules", "reload", "-addr", addr}, &stdout, &stderr); code != 0 {
+		t.Fatalf("rules reload failed with %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Soaking 2 keywords until") {
+		t.Errorf("unexpected reload output: %s", stdout.String())
+	}
+
+	s.Process(ctx, model.Message{ID: 1, ChatID: 100, Text: "ram for sale"})
+	stdout.Reset()
+	if code := runCommand(ctx, []string{"rules", "soak", "-addr", addr}, &stdout, &stderr); code != 0 {
+		t.Fatalf("rules soak failed with %d: %s", code, stderr.String())
+	}
+	if out := stdout.String(); !strings.Contains(out, "Messages: 1") || !strings.Contains(out, "only old  ram  1") {
+		t.Errorf("unexpected soak output: %s", out)
+	}
+}
+
+// Serve messages by link
+type fakeFetcher map[string]model.Message
+
+func (f fakeFetcher) Message(ctx context.Context, link string) (model.Message, bool, error) {
+	msg, ok := f[link]
+	return msg, ok, nil
+}
+
+func TestInspect(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{Keywords: []string{"gpu", "rtx 5090"}},
+		Filters:    config.FiltersConfig{IgnoreBots: true},
+	}
+	s := scout.New(cfg, &MockNotifier{}, zap.NewNop())
+	s.UseMessageFetcher(fakeFetcher{
+		"https://t.me/deals/1": {ID: 1, ChatID: 100, ChatTitle: "Deals", Text: "RTX 5090 for sale"},
+		"https://t.me/deals/2": {ID: 2, ChatID: 100, ChatTitle: "Deals", Text: "cheap gpu", FromBot: true},
+	})
+	srv := admin.New(&config.Config{}, zap.NewNop())
+	registerAdminRoutes(srv, s, health.NewTracker(time.Minute), annotate.New(config.AnnotationsConfig{}, zap.NewNop()), nil)
+	server := httptest.NewServer(srv.Handler())
+	defer server.Close()
+	addr := strings.TrimPrefix(server.URL, "http://")
+	ctx := context.Background()
+
+	var stdout, stderr bytes.Buffer
+	if code := runCommand(ctx, []string{"inspect", "-addr", addr, "https://t.me/deals/1"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("inspect failed with %d: %s", code, stderr.String())
+	}
+	out := stdout.String()
+	for _, want := range []string{`no_match  word    "gpu"`, `matched   phrase  "rtx 5090" offsets 0-8`, `Result: alert on rule "rtx 5090" (phrase), matching "RTX 5090"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in the trace, got:\n%s", want, out)
+		}
+	}
+
+	stdout.Reset()
+	if code := runCommand(ctx, []string{"inspect", "-addr", addr, "https://t.me/deals/2"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("inspect failed with %d: %s", code, stderr.String())
+	}
+	if out := stdout.String(); !strings.Contains(out, `Result: skipped by prefilters (bot), rule "gpu" would match otherwise`) {
+		t.Errorf("expected the prefilter reported, got:\n%s", out)
+	}
+
+	stderr.Reset()
+	if code := runCommand(ctx, []string{"inspect", "-addr", addr, "https://t.me/deals/3"}, &stdout, &stderr); code == 0 || !strings.Contains(stderr.String(), "not found") {
+		t.Errorf("expected a missing message reported, got %d: %s", code, stderr.String())
+	}
+}
+
+// Serve a fixed history for backfills
+type fakeHistory []model.Message
+
+func (f fakeHistory) History(ctx context.Context, chat string, since time.Time, fn func(model.Message) error) error {
+	for _, msg := range f {
+		if err := fn(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Count archived records, written from the backfill goroutine
+type countingArchive struct {
+	mux   sync.Mutex
+	kinds map[string]int
+}
+
+func (c *countingArchive) Add(r archive.Record) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.kinds[r.Kind]++
+}
+
+func TestBackfillCommand(t *testing.T) {
+	backfillPoll = 10 * time.Millisecond
+	cfg := &config.Config{Monitoring: config.MonitoringRules{Keywords: []string{"gpu"}}}
+	s := scout.New(cfg, &MockNotifier{}, zap.NewNop())
+	archived := &countingArchive{kinds: make(map[string]int)}
+	s.UseArchive(archived)
+	s.UseHistoryReader(fakeHistory{{ID: 2, Text: "cheap gpu"}, {ID: 1, Text: "hello"}})
+	srv := admin.New(&config.Config{}, zap.NewNop())
+	registerBackfillRoutes(context.Background(), srv, s)
+	server := httptest.NewServer(srv.Handler())
+	defer server.Close()
+	addr := strings.TrimPrefix(server.URL, "http://")
+
+	var stdout, stderr bytes.Buffer
+	if code := runCommand(context.Background(), []string{"backfill", "-addr", addr, "-chat", "@deals", "-since", "yesterday"}, &stdout, &stderr); code != 1 {
+		t.Errorf("expected an invalid date to fail, got %d", code)
+	}
+	args := []string{"backfill", "-addr", addr, "-chat", "@deals", "-since", "2025-01-01", "-evaluate", "-wait"}
+	if code := runCommand(context.Background(), args, &stdout, &stderr); code != 0 {
+		t.Fatalf("backfill failed with %d: %s", code, stderr.String())
+	}
+	out := stdout.String()
+	for _, want := range []string{"Started backfill 1 of @deals since 2025-01-01", "done     @deals since 2025-01-01: 2 messages", `1 matches of "gpu"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+	if archived.kinds[archive.KindMessage] != 2 || archived.kinds[archive.KindBackfill] != 1 {
+		t.Errorf("unexpected archived records %v", archived.kinds)
+	}
+
+	stdout.Reset()
+	if code := runCommand(context.Background(), []string{"backfill", "-addr", addr}, &stdout, &stderr); code != 0 || !strings.Contains(stdout.String(), "@deals") {
+		t.Errorf("expected the backfill listed, got %d: %s", code, stdout.String())
+	}
+}
+
+func TestArchiveCommand(t *testing.T) {
+	files := archive.NewFiles(t.TempDir())
+	record := archive.Record{Kind: archive.KindMatch, ChatID: -100, MsgID: 7, Date: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), Rule: "gpu"}
+	for range 2 {
+		if err := files.Write(context.Background(), []archive.Record{record}); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	srv := admin.New(&config.Config{}, zap.NewNop())
+	registerArchiveRoutes(srv, files)
+	server := httptest.NewServer(srv.Handler())
+	defer server.Close()
+	addr := strings.TrimPrefix(server.URL, "http://")
+
+	var stdout, stderr bytes.Buffer
+	if code := runCommand(context.Background(), []string{"archive", "stats", "-addr", addr}, &stdout, &stderr); code != 0 {
+		t.Fatalf("archive stats failed with %d: %s", code, stderr.String())
+	}
+	if out := stdout.String(); !strings.Contains(out, "(2025-01-02 to 2025-01-02)") || !strings.Contains(out, "Duplicates: 1") {
+		t.Errorf("unexpected stats output: %s", out)
+	}
+
+	stdout.Reset()
+	if code := runCommand(context.Background(), []string{"archive", "compact", "-addr", addr}, &stdout, &stderr); code != 0 {
+		t.Fatalf("archive compact failed with %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Removed:    1 duplicates") {
+		t.Errorf("unexpected compact output: %s", stdout.String())
+	}
+	if stats, err := files.Stats(context.Background()); err != nil || stats.Records != 1 || stats.Duplicates != 0 {
+		t.Errorf("expected the duplicate removed, got %+v, %v", stats, err)
+	}
+	if code := runCommand(context.Background(), []string{"archive"}, &stdout, &stderr); code != 1 {
+		t.Errorf("expected a missing action to fail, got %d", code)
+	}
+
+	stdout.Reset()
+	if code := runCommand(context.Background(), []string{"archive", "query", "-addr", addr, "-rule", "gpu", "-until", "2025-01-02"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("archive query failed with %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), `match    chat= (-100) msg=7 rule="gpu"`) {
+		t.Errorf("unexpected query output: %s", stdout.String())
+	}
+
+	stdout.Reset()
+	if code := runCommand(context.Background(), []string{"archive", "prune", "-addr", addr, "-before", "2025-01-03"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("archive prune failed with %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Removed 1 records") {
+		t.Errorf("unexpected prune output: %s", stdout.String())
+	}
+
+	recent := archive.Record{Kind: archive.KindMatch, ChatID: -100, ChatTitle: "Deals", MsgID: 8, Date: time.Now().Add(-time.Hour), Rule: "gpu"}
+	if err := files.Write(context.Background(), []archive.Record{recent}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	stdout.Reset()
+	if code := runCommand(context.Background(), []string{"archive", "summary", "-addr", addr}, &stdout, &stderr); code != 0 {
+		t.Fatalf("archive summary failed with %d: %s", code, stderr.String())
+	}
+	if out := stdout.String(); !strings.Contains(out, "Alerts: 1 in the last 24h, 1 in the last 7d") || !strings.Contains(out, "Deals (-100)") {
+		t.Errorf("unexpected summary output: %s", out)
+	}
+}
+
+func TestAlertSummary(t *testing.T) {
+	got := alertSummary(archive.Report{
+		Day:      7,
+		Week:     15,
+		TopRules: []archive.Count{{Name: "gpu<3", Day: 6, Week: 8}},
+		TopChats: []archive.Count{{Name: "Deals & more", ChatID: -100, Day: 6, Week: 8}},
+		Spikes:   []archive.Spike{{Rule: "gpu<3", Day: 6, Average: 0.5}},
+	})
+	for _, want := range []string{
+		"🔔 <b>Alerts:</b> 7 in the last 24h, 15 in the last 7d",
+		"<code>gpu&lt;3</code> — 6 (8 in 7d)",
+		"Deals &amp; more (<code>-100</code>) — 6 (8 in 7d)",
+		"<code>gpu&lt;3</code> — 6 in 24h, 0.5 a day before",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in summary:\n%s", want, got)
+		}
+	}
+	if quiet := alertSummary(archive.Report{}); strings.Contains(quiet, "Top rules") || strings.Contains(quiet, "Spikes") {
+		t.Errorf("expected empty sections left out:\n%s", quiet)
+	}
+}
+
+func TestAuditCommand(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("TELEGRAM_STATE_DIR", dir)
+	t.Setenv("USER", "alice")
+	cfgPath := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(cfgPath, []byte("chats: [cool_channel]\n"), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	logPath := filepath.Join(dir, "audit.jsonl")
+	log := audit.New(logPath)
+
+	var stdout, stderr bytes.Buffer
+	if code := runCommand(context.Background(), []string{"audit", "-config", cfgPath}, &stdout, &stderr); code != 0 || !strings.Contains(stdout.String(), "No changes") {
+		t.Fatalf("expected an empty log, got %d: %s%s", code, stdout.String(), stderr.String())
+	}
+
+	// Only an edited config file is recorded again
+	for range 2 {
+		if err := auditConfigChange(log, logPath, cfgPath); err != nil {
+			t.Fatalf("auditConfigChange() error = %v", err)
+		}
+	}
+	if err := os.WriteFile(cfgPath, []byte("chats: [other_channel]\n"), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	if err := auditConfigChange(log, logPath, cfgPath); err != nil {
+		t.Fatalf("auditConfigChange() error = %v", err)
+	}
+	if err := log.Record(audit.Entry{Actor: "bob", Source: audit.SourceCommand, Action: "snooze", Target: "giveaway", ChatID: -1001}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	stdout.Reset()
+	if code := runCommand(context.Background(), []string{"audit", "-config", cfgPath, "-action", "config"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if n := strings.Count(stdout.String(), "\n"); n != 2 || !strings.Contains(stdout.String(), "by=alice") {
+		t.Errorf("expected two config entries by alice, got: %s", stdout.String())
+	}
+
+	stdout.Reset()
+	if code := runCommand(context.Background(), []string{"audit", "-config", cfgPath, "-actor", "bob"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "snooze") || !strings.Contains(stdout.String(), "giveaway by=bob chat=-1001") {
+		t.Errorf("unexpected output: %s", stdout.String())
+	}
+}
+
+func TestGraphCommand(t *testing.T) {
+	g := graph.New()
+	g.Add(graph.Node{ID: "@source", Label: "Source"}, graph.Node{ID: "-1001", Label: "Mirror"}, graph.KindForward, time.Now())
+	server := httptest.NewServer(graphHandler(g))
+	defer server.Close()
+	addr := strings.TrimPrefix(server.URL, "http://")
+
+	var stdout, stderr bytes.Buffer
+	if code := runCommand(context.Background(), []string{"graph", "-addr", addr}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), `"@source" -> "-1001"`) {
+		t.Errorf("unexpected DOT output: %s", stdout.String())
+	}
+
+	stdout.Reset()
+	if code := runCommand(context.Background(), []string{"graph", "-addr", addr, "-format", "graphml"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), `<edge source="@source" target="-1001">`) {
+		t.Errorf("unexpected GraphML output: %s", stdout.String())
+	}
+}
+
+func TestHealthCommand(t *testing.T) {
+	tracker := health.NewTracker(time.Minute)
+	server := httptest.NewServer(healthHandler(tracker))
+	defer server.Close()
+	addr := strings.TrimPrefix(server.URL, "http://")
+
+	var stdout, stderr bytes.Buffer
+	if code := runCommand(context.Background(), []string{"health", "-addr", addr}, &stdout, &stderr); code != 1 {
+		t.Errorf("expected exit code 1 before the first heartbeat, got %d", code)
+	}
+
+	tracker.Beat(nil)
+	if code := runCommand(context.Background(), []string{"health", "-addr", addr}, &stdout, &stderr); code != 0 {
+		t.Errorf("expected exit code 0 when healthy, got %d: %s", code, stderr.String())
+	}
+
+	tracker.Disconnected(errors.New("connection reset"))
+	stderr.Reset()
+	if code := runCommand(context.Background(), []string{"health", "-addr", addr}, &stdout, &stderr); code != 1 {
+		t.Errorf("expected exit code 1 after disconnect, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "connection reset") {
+		t.Errorf("expected error in output, got %q", stderr.String())
+	}
+}
+
+func TestRulesCommand(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "source.yaml")
+	if err := os.WriteFile(src, []byte("keywords:\n  - \"urgent\" # Pages on-call\ntags:\n  urgent: [critical]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	pack := filepath.Join(dir, "pack.yaml")
+
+	var stdout, stderr bytes.Buffer
+	if code := runCommand(context.Background(), []string{"rules", "export", "-config", src, "-o", pack, "-comment", "On-call"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr.String())
+	}
+
+	dst := filepath.Join(dir, "config.yaml")
+	if code := runCommand(context.Background(), []string{"rules", "import", "-config", dst, "-dry-run", pack}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if _, err := os.Stat(dst); !os.IsNotExist(err) {
+		t.Errorf("expected a dry run to leave the config alone, got %v", err)
+	}
+
+	stdout.Reset()
+	if code := runCommand(context.Background(), []string{"rules", "import", "-config", dst, pack}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Pack: On-call") || !strings.Contains(stdout.String(), "Added: urgent") {
+		t.Errorf("unexpected import report: %s", stdout.String())
+	}
+	cfg, err := config.LoadFile(dst)
+	if err != nil {
+		t.Fatalf("imported config does not load: %v", err)
+	}
+	if !reflect.DeepEqual(cfg.Monitoring.Keywords, []string{"urgent"}) || !reflect.DeepEqual(cfg.Monitoring.Tags["urgent"], []string{"critical"}) {
+		t.Errorf("unexpected imported rules: %+v", cfg.Monitoring)
+	}
+
+	stdout.Reset()
+	if code := runCommand(context.Background(), []string{"rules", "import", "-config", dst, pack}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "is up to date") {
+		t.Errorf("expected a second import to change nothing, got %s", stdout.String())
+	}
+}
+
+// Resolve chats from fixed tables, for testing chats import
+type fakeLookup struct {
+	chats   map[mentions.Ref]mentions.Info
+	folders map[string][]mentions.Info
+}
+
+func (f fakeLookup) ResolveChat(ctx context.Context, ref mentions.Ref) (mentions.Info, error) {
+	info, ok := f.chats[ref]
+	if !ok {
+		return mentions.Info{}, errors.New("USERNAME_NOT_OCCUPIED")
+	}
+	return info, nil
+}
+
+func (f fakeLookup) ChatFolder(ctx context.Context, slug string) ([]mentions.Info, error) {
+	infos, ok := f.folders[slug]
+	if !ok {
+		return nil, errors.New("INVITE_SLUG_EXPIRED")
+	}
+	return infos, nil
+}
+
+func TestChatsImport(t *testing.T) {
+	srv := admin.New(&config.Config{}, zap.NewNop())
+	registerChatRoutes(srv, fakeLookup{
+		chats: map[mentions.Ref]mentions.Info{
+			{Username: "deals"}:  {ID: -1001, Title: "Deals", Username: "Deals", Joined: true},
+			{Invite: "AbCdEf"}:   {ID: -1002, Title: "Private ops", Joined: true},
+			{Invite: "NotInYet"}: {Title: "Closed club"},
+		},
+		folders: map[string][]mentions.Info{
+			"news": {{ID: -1003, Title: "News", Username: "news", Joined: true}, {ID: -1001, Title: "Deals", Username: "deals", Joined: true}},
+		},
+	})
+	server := httptest.NewServer(srv.Handler())
+	defer server.Close()
+	addr := strings.TrimPrefix(server.URL, "http://")
+
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "chats.csv")
+	csv := "chat,note\n@deals\nhttps://t.me/+AbCdEf,Ops team\n-1009\nt.me/+NotInYet\n@gone\n"
+	if err := os.WriteFile(csvPath, []byte(csv), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	dst := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(dst, []byte("chats:\n  - \"-1009\"\nkeywords:\n  - gpu\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"chats", "import", "-addr", addr, "-delay", "0", "-config", dst, "-file", csvPath, "-folder", "https://t.me/addlist/news"}
+	if code := runCommand(context.Background(), args, &stdout, &stderr); code != 1 {
+		t.Errorf("expected unresolved entries to fail the import, got %d", code)
+	}
+	out := stdout.String()
+	for _, want := range []string{
+		"ok      @deals -> deals",
+		`failed  line 5 "t.me/+NotInYet": Closed club: not a member`,
+		`failed  line 6 "@gone"`,
+		"Added: deals, -1002, news",
+		"Already configured: -1009, deals",
+		"Updated " + dst,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+	if !strings.Contains(stderr.String(), "2 of 6 entries could not be imported") {
+		t.Errorf("unexpected error: %s", stderr.String())
+	}
+
+	cfg, err := config.LoadFile(dst)
+	if err != nil {
+		t.Fatalf("imported config does not load: %v", err)
+	}
+	if want := []string{"-1009", "deals", "-1002", "news"}; !reflect.DeepEqual(cfg.Monitoring.Chats, want) {
+		t.Errorf("got chats %v, want %v", cfg.Monitoring.Chats, want)
+	}
+	data, _ := os.ReadFile(dst)
+	if !strings.Contains(string(data), "# Ops team") {
+		t.Errorf("expected the note kept as a comment:\n%s", data)
+	}
+}
+
+func TestServiceEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), serviceEnvFile)
+	environ := []string{"TELEGRAM_BOT_TOKEN=123:abc", "TELEGRAM_PASSWORD=pa ss=\"word\"\n", "HOME=/root", "TELEGRAM_ENV_FILE=/elsewhere"}
+	if err := writeServiceEnv(path, environ); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0o600 {
+		t.Errorf("expected the env file readable by the owner only, got %v", mode)
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "HOME") || strings.Contains(string(data), "/elsewhere") {
+		t.Errorf("expected only the TELEGRAM_* variables saved, got:\n%s", data)
+	}
+
+	t.Setenv("TELEGRAM_BOT_TOKEN", "")
+	_ = os.Unsetenv("TELEGRAM_BOT_TOKEN")
+	t.Setenv("TELEGRAM_PASSWORD", "already set")
+	if err := loadServiceEnv(path); err != nil {
+		t.Fatal(err)
+	}
+	if got := os.Getenv("TELEGRAM_BOT_TOKEN"); got != "123:abc" {
+		t.Errorf("expected the token loaded, got %q", got)
+	}
+	if got := os.Getenv("TELEGRAM_PASSWORD"); got != "already set" {
+		t.Errorf("expected a set variable to win over the file, got %q", got)
+	}
+
+	t.Setenv("TELEGRAM_SESSION", "secret")
+	t.Setenv("TELEGRAM_CONFIG_FILE", "/etc/scout.yaml")
+	want := map[string]string{"TELEGRAM_ENV_FILE": path, "TELEGRAM_CONFIG_FILE": "/etc/scout.yaml"}
+	if got := serviceEnv(path); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected no secrets in the service definition, got %v", got)
+	}
+}
+
+func TestStartupSummary(t *testing.T) {
+	report := telegram.ResolveReport{
+		Resolved: []telegram.ResolvedChat{{Target: "@deals", ID: 1, Title: "Deals & Steals"}},
+		Failed:   []telegram.FailedChat{{Target: "-100123", Reason: "not found in dialogs"}},
+	}
+	rejected := []scout.RejectedRule{{Keyword: "re:(", Reason: "missing closing )"}}
+
+	text := startupSummary(version.Info{Version: "1.0.0", Commit: "abc", Date: "today"}, report, 4, rejected)
+
+	for _, want := range []string{
+		"TelegramScout 1.0.0 (abc, today) is online",
+		"4 compiled, 1 rejected",
+		"<code>re:(</code>",
+		"1 resolved, 1 failed",
+		"Deals &amp; Steals",
+		"<code>-100123</code> — not found in dialogs",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected summary to contain %q, got:\n%s", want, text)
+		}
+	}
+	// A large watchlist is cut to one Telegram message, failures kept
+	report.Resolved = nil
+	for i := range 500 {
+		report.Resolved = append(report.Resolved, telegram.ResolvedChat{Target: fmt.Sprintf("@chat%d", i), Title: "Чат с длинным названием"})
+	}
+	text = startupSummary(version.Info{Version: "1.0.0"}, report, 4, rejected)
+	if n := utf8.RuneCountInString(text); n > render.MaxLength {
+		t.Errorf("expected the summary within %d characters, got %d", render.MaxLength, n)
+	}
+	if !strings.Contains(text, "500 resolved, 1 failed") || !strings.Contains(text, "<code>-100123</code>") {
+		t.Errorf("expected the counts and failures kept, got:\n%s", text)
+	}
+	if !regexp.MustCompile(`\n…and \d+ more$`).MatchString(text) {
+		t.Errorf("expected the summary to end with the omitted count, got:\n%s", text)
+	}
+}
+
+func TestRenderLifecycle(t *testing.T) {
+	data := startupData{Host: "scout-1", Version: "1.0.0", Rules: 4, Chats: 2}
+	if text, err := renderLifecycle("", data, "built-in"); err != nil || text != "built-in" {
+		t.Errorf("expected the built-in message without a template, got %q, %v", text, err)
+	}
+	text, err := renderLifecycle("{{.Host}} up with {{.Rules}} rules in {{.Chats}} chats", data, "built-in")
+	if err != nil || text != "scout-1 up with 4 rules in 2 chats" {
+		t.Errorf("unexpected rendering %q, %v", text, err)
+	}
+	if text, err := renderLifecycle("{{.Missing}}", data, "built-in"); err == nil || text != "built-in" {
+		t.Errorf("expected a failing template to fall back, got %q, %v", text, err)
+	}
+}
+
+func TestShutdownNotice(t *testing.T) {
+	cfg := &config.Config{}
+	data := shutdownData{Host: "scout-1", Version: "1.0.0", Uptime: 90 * time.Minute}
+	tests := []struct {
+		name  string
+		p     any
+		err   error
+		wants []string
+	}{
+		{"signal", nil, nil, []string{"1.0.0 on scout-1 is offline", "<b>Reason:</b> signal", "1h30m0s"}},
+		{"config error", nil, errors.New("failed to load image rules"), []string{"<b>Reason:</b> config error", "<code>failed to load image rules</code>"}},
+		{"crash", "boom <nil>", errors.New("ignored"), []string{"<b>Reason:</b> crash", "<code>boom &lt;nil&gt;</code>"}},
+	}
+	for _, tt := range tests {
+		notif := &MockNotifier{}
+		// Sent even though the run context is gone
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		sendShutdownNotice(ctx, cfg, notif, data, tt.p, tt.err, zap.NewNop())
+		for _, want := range tt.wants {
+			if !strings.Contains(notif.LastMessage, want) {
+				t.Errorf("%s: expected %q in notice:\n%s", tt.name, want, notif.LastMessage)
+			}
+		}
+	}
+
+	cfg.Notifier.Shutdown.Template = "{{.Host}} stopped: {{.Reason}}"
+	notif := &MockNotifier{}
+	sendShutdownNotice(context.Background(), cfg, notif, data, nil, nil, zap.NewNop())
+	if notif.LastMessage != "scout-1 stopped: signal" {
+		t.Errorf("unexpected templated notice %q", notif.LastMessage)
+	}
+}
+
+func TestUnresolvedAlert(t *testing.T) {
+	failed := []telegram.FailedChat{
+		{Target: "@typo", Reason: "USERNAME_NOT_OCCUPIED", Hint: "check the username spelling"},
+		{Target: "-100123", Reason: "not found in dialogs", Hint: "join the channel"},
+	}
+
+	text := unresolvedAlert(failed)
+	for _, want := range []string{"2 configured chats", "<code>@typo</code> — USERNAME_NOT_OCCUPIED", "💡 join the channel"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected alert to contain %q, got:\n%s", want, text)
+		}
+	}
+
+	reordered := []telegram.FailedChat{failed[1], failed[0]}
+	if failureKey(failed) != failureKey(reordered) {
+		t.Error("expected failure key to ignore ordering")
+	}
+	if failureKey(nil) == failureKey(failed) {
+		t.Error("expected different failure sets to have different keys")
+	}
+}
+
+func TestOutdatedAlert(t *testing.T) {
+	resolved := []telegram.ResolvedChat{
+		{Target: "@deals", ID: -100123, Title: "Deals"},
+		{Target: "@oldname", ID: -100456, Title: "Renamed", Hint: "renamed to @newname, replace @oldname with @newname in chats"},
+	}
+
+	text := outdatedAlert(resolved)
+	for _, want := range []string{"1 configured chats changed", "<code>@oldname</code> — Renamed", "💡 renamed to @newname"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected alert to contain %q, got:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "@deals") {
+		t.Errorf("expected chats without hints left out, got:\n%s", text)
+	}
+	if hintKey(resolved[:1]) != "" || hintKey(resolved) == "" {
+		t.Error("expected only chats with hints to make up the key")
+	}
+}
+
+func TestMigrationNotice(t *testing.T) {
+	text := migrationNotice(telegram.Migration{From: -4567, To: -1000000008888, Title: "Deals & Co"})
+	for _, want := range []string{"<b>Deals &amp; Co</b> was upgraded", "<code>-1000000008888</code>", "💡 upgraded to a supergroup, replace -4567 with -1000000008888"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected notice to contain %q, got:\n%s", want, text)
+		}
+	}
+}
+
+func TestLoadKnownChats(t *testing.T) {
+	chat := telegram.KnownChat{Username: "deals", ID: -1001234, AccessHash: 42}
+
+	t.Run("Unwritable State Dir", func(t *testing.T) {
+		// A file in the way fails like a read-only root filesystem, even as root
+		blocker := filepath.Join(t.TempDir(), "file")
+		if err := os.WriteFile(blocker, nil, 0600); err != nil {
+			t.Fatal(err)
+		}
+		known, err := loadKnownChats(&config.Config{StateDir: filepath.Join(blocker, "state")}, zap.NewNop())
+		if err != nil {
+			t.Fatalf("expected startup to go on without a state directory, got %v", err)
+		}
+		if err := known.Put(chat); err != nil {
+			t.Fatalf("Put() error = %v", err)
+		}
+		if got, ok := known.Get("deals"); !ok || got != chat {
+			t.Errorf("expected the chat to be kept in memory, got %+v", got)
+		}
+	})
+
+	t.Run("Saved", func(t *testing.T) {
+		cfg := &config.Config{StateDir: t.TempDir()}
+		known, err := loadKnownChats(cfg, zap.NewNop())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := known.Put(chat); err != nil {
+			t.Fatal(err)
+		}
+		reloaded, err := loadKnownChats(cfg, zap.NewNop())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, ok := reloaded.Get("deals"); !ok || got != chat {
+			t.Errorf("expected the chat to be saved in the state directory, got %+v", got)
+		}
+	})
+}
+
+func TestBenchCommand(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(cfgPath, []byte("chats: [cool_channel]\nkeywords: [gpu, \"re:rtx\\\\s*50\\\\d0\"]\n"), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"bench", "-config", cfgPath, "-messages", "200", "-match", "1"}
+	if code := runCommand(context.Background(), args, &stdout, &stderr); code != 0 {
+		t.Fatalf("bench failed with %d: %s%s", code, stdout.String(), stderr.String())
+	}
+	out := stdout.String()
+	for _, want := range []string{"Rules:       2 compiled", "Messages:    200 in", "Matches:     200 (100.0%)", "Processing:  p50="} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in the report, got:\n%s", want, out)
+		}
+	}
+
+	stdout.Reset()
+	args = []string{"bench", "-config", cfgPath, "-messages", "100", "-match", "1", "-engines"}
+	if code := runCommand(context.Background(), args, &stdout, &stderr); code != 0 {
+		t.Fatalf("bench -engines failed with %d: %s%s", code, stdout.String(), stderr.String())
+	}
+	for _, want := range []string{"Engine:      naive in use", "aho-corasick", "100  ", "hyperscan is not available"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("expected %q in the engine comparison, got:\n%s", want, stdout.String())
+		}
+	}
+
+	if code := runCommand(context.Background(), []string{"bench", "-config", cfgPath, "-match", "2"}, &stdout, &stderr); code != 1 {
+		t.Errorf("expected an out of range match ratio rejected, got %d", code)
+	}
+}
+
+func TestValidateCommand(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(cfgPath, []byte(content), 0600); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+	}
+
+	write("chats: [cool_channel]\nkeywords: [gpu, \"cheap gpu\"]\n")
+	var stdout, stderr bytes.Buffer
+	if code := runCommand(context.Background(), []string{"validate", "-config", cfgPath}, &stdout, &stderr); code != 0 {
+		t.Fatalf("validate failed with %d: %s%s", code, stdout.String(), stderr.String())
+	}
+	if out := stdout.String(); !strings.Contains(out, "1 lint findings") || !strings.Contains(out, `"cheap gpu": never fires`) {
+		t.Errorf("expected the covered rule reported, got:\n%s", out)
+	}
+	if code := runCommand(context.Background(), []string{"validate", "-strict", "-config", cfgPath}, &stdout, &stderr); code != 1 {
+		t.Errorf("expected lint findings to fail with -strict, got %d", code)
+	}
+
+	write("chats: [cool_channel]\nkeywords: [\"re:(\"]\n")
+	stderr.Reset()
+	if code := runCommand(context.Background(), []string{"validate", "-config", cfgPath}, &stdout, &stderr); code != 1 || !strings.Contains(stderr.String(), "1 rules rejected") {
+		t.Errorf("expected rejected rules to fail, got %d: %s", code, stderr.String())
+	}
+}
+
+func TestReplayCommand(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(cfgPath, []byte("chats: [cool_channel]\nkeywords: [gpu]\n"), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	recPath := filepath.Join(dir, "updates.jsonl")
+	rec, err := telegram.OpenRecorder(recPath)
+	if err != nil {
+		t.Fatalf("failed to open recorder: %v", err)
+	}
+	channel := &tg.Channel{ID: 1001, AccessHash: 1001, Title: "Deals", Username: "deals", Broadcast: true, Photo: &tg.ChatPhotoEmpty{}}
+	for i, text := range []string{"cheap GPU", "nothing to see"} {
+		msg := &tg.Message{ID: i + 1, PeerID: &tg.PeerChannel{ChannelID: channel.ID}, Message: text, Date: int(time.Now().Unix())}
+		err := rec.Record(&tg.Updates{
+			Updates: []tg.UpdateClass{&tg.UpdateNewChannelMessage{Message: msg}},
+			Chats:   []tg.ChatClass{channel},
+			Date:    msg.Date,
+		})
+		if err != nil {
+			t.Fatalf("failed to record: %v", err)
+		}
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatalf("failed to close recorder: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := runCommand(context.Background(), []string{"replay", "-config", cfgPath, recPath}, &stdout, &stderr); code != 0 {
+		t.Fatalf("replay failed with %d: %s%s", code, stdout.String(), stderr.String())
+	}
+	out := stdout.String()
+	for _, want := range []string{"Message 1 in Deals (-1000000001001)", `alert on rule "gpu" (word), matching "GPU"`, `"nothing to see"` + "\n  no alert", "Replayed 2 updates: 2 messages, 1 alerts"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in the replay, got:\n%s", want, out)
+		}
+	}
+
+	stdout.Reset()
+	if code := runCommand(context.Background(), []string{"replay", "-config", cfgPath, "-json", recPath}, &stdout, &stderr); code != 0 {
+		t.Fatalf("replay -json failed with %d: %s", code, stderr.String())
+	}
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	var first struct {
+		Message     model.Message      `json:"message"`
+		Explanation *scout.Explanation `json:"explanation"`
+	}
+	if len(lines) != 2 || json.Unmarshal([]byte(lines[0]), &first) != nil || first.Explanation == nil || first.Message.Link != "https://t.me/deals/1" {
+		t.Errorf("expected one JSON object per message, got:\n%s", stdout.String())
+	}
+}
@@ -0,0 +1,165 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gotd/td/tg"
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/archive"
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/model"
+	"github.com/h3nc4/TelegramScout/internal/scout"
+	"github.com/h3nc4/TelegramScout/internal/telegram"
+)
+
+// Feed updates recorded with log.record_updates through the client and the
+// rules of a config, offline, printing every parsed message and its outcome
+func replayCommand(ctx context.Context, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	fs.SetOutput(stdout)
+	path := fs.String("config", config.FilePath(), "config file with the rules to replay against")
+	asJSON := fs.Bool("json", false, "print one JSON object per message instead of text")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("usage: replay [-config path] [-json] <recording>")
+	}
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	cfg, err := config.LoadFile(*path)
+	if err != nil {
+		return err
+	}
+	if err := applyPacks(cfg, zap.NewNop()); err != nil {
+		return err
+	}
+	// Chats cannot be resolved offline, every chat not excluded is monitored
+	cfg.Session = "replay"
+	cfg.Monitoring.Chats = []string{config.AllChats}
+
+	msgChan := make(chan model.Message)
+	client, err := telegram.NewClient(cfg, zap.NewNop(), msgChan)
+	if err != nil {
+		return err
+	}
+	s := scout.New(cfg, discardNotifier{}, zap.NewNop())
+	matches := &replayMatches{}
+	s.UseArchive(matches)
+
+	var messages, alerts int
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for msg := range msgChan {
+			messages++
+			s.Process(ctx, msg)
+			var exp *scout.Explanation
+			if matches.take() {
+				alerts++
+				if recent := s.Explanations(msg.ChatID, "", 1); len(recent) > 0 {
+					exp = &recent[0]
+				}
+			}
+			printReplayed(stdout, msg, exp, *asJSON)
+		}
+	}()
+
+	batches := 0
+	err = telegram.Replay(f, func(_ time.Time, updates tg.UpdatesClass) error {
+		batches++
+		return client.HandleUpdates(ctx, updates)
+	})
+	close(msgChan)
+	<-done
+	if err != nil {
+		return err
+	}
+	if !*asJSON {
+		_, _ = fmt.Fprintf(stdout, "Replayed %d updates: %d messages, %d alerts\n", batches, messages, alerts)
+	}
+	return nil
+}
+
+// Print a replayed message and the alert it raised, exp is nil without one
+func printReplayed(w io.Writer, msg model.Message, exp *scout.Explanation, asJSON bool) {
+	if asJSON {
+		_ = json.NewEncoder(w).Encode(struct {
+			Message     model.Message      `json:"message"`
+			Explanation *scout.Explanation `json:"explanation"`
+		}{msg, exp})
+		return
+	}
+	_, _ = fmt.Fprintf(w, "Message %d in %s (%d), sent %s\n", msg.ID, msg.ChatTitle, msg.ChatID, msg.Date.Format(time.RFC3339))
+	if msg.Text != "" {
+		_, _ = fmt.Fprintf(w, "  %q\n", msg.Text)
+	}
+	if msg.Media != "" {
+		_, _ = fmt.Fprintf(w, "  media=%s\n", msg.Media)
+	}
+	if exp == nil {
+		_, _ = fmt.Fprintln(w, "  no alert")
+		return
+	}
+	_, _ = fmt.Fprintf(w, "  alert on rule %q (%s), matching %q\n", exp.Keyword, exp.Kind, exp.Matched)
+}
+
+// Note the matches the scout archives, to tell which messages alerted
+type replayMatches struct {
+	mux     sync.Mutex
+	matched bool
+}
+
+func (m *replayMatches) Add(r archive.Record) {
+	if r.Kind != archive.KindMatch {
+		return
+	}
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.matched = true
+}
+
+// Report whether a match was archived since the last call
+func (m *replayMatches) take() bool {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	matched := m.matched
+	m.matched = false
+	return matched
+}
+
+// Drop alerts, replay prints its own report
+type discardNotifier struct{}
+
+func (discardNotifier) Send(context.Context, string) error { return nil }
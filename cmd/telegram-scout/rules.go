@@ -0,0 +1,279 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/h3nc4/TelegramScout/internal/atomicfile"
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/rules"
+)
+
+// Share monitoring rules between deployments
+func rulesCommand(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("expected one of: export, import, packs, update, reload, soak")
+	}
+	switch action := args[0]; action {
+	case "export":
+		return rulesExport(args[1:], stdout)
+	case "import":
+		return rulesImport(args[1:], stdin, stdout)
+	case "packs":
+		return rulesPacks(args[1:], stdout)
+	case "update":
+		return rulesUpdate(ctx, args[1:], stdout)
+	case "reload", "soak":
+		return rulesReload(ctx, action, args[1:], stdout)
+	default:
+		return fmt.Errorf("unknown rules action %q", action)
+	}
+}
+
+// Write the rules of the config file as a pack
+func rulesExport(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("rules export", flag.ContinueOnError)
+	fs.SetOutput(stdout)
+	path := fs.String("config", config.FilePath(), "config file to read the rules from")
+	output := fs.String("o", "", "write to this file instead of stdout")
+	comment := fs.String("comment", "", "describe the pack for whoever imports it")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(*path)
+	if err != nil {
+		return err
+	}
+	pack, err := rules.Export(data)
+	if err != nil {
+		return fmt.Errorf("invalid config %s: %w", *path, err)
+	}
+	pack.Comment = *comment
+
+	if *output == "" {
+		return pack.Write(stdout)
+	}
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if err := pack.Write(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Merge a pack, read from a file or - for stdin, into the config file
+func rulesImport(args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("rules import", flag.ContinueOnError)
+	flags.SetOutput(stdout)
+	path := flags.String("config", config.FilePath(), "config file to merge the rules into")
+	dryRun := flags.Bool("dry-run", false, "report what would change without writing the config")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("expected the pack file to import, or - for stdin")
+	}
+
+	in := stdin
+	if name := flags.Arg(0); name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+		in = f
+	}
+	pack, err := rules.Read(in)
+	if err != nil {
+		return fmt.Errorf("invalid pack: %w", err)
+	}
+
+	// Importing into a missing config starts a new one
+	data, err := os.ReadFile(*path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	merged, rep, err := rules.Merge(data, pack)
+	if err != nil {
+		return fmt.Errorf("invalid config %s: %w", *path, err)
+	}
+
+	if pack.Comment != "" {
+		_, _ = fmt.Fprintf(stdout, "Pack: %s\n", pack.Comment)
+	}
+	for _, line := range []struct {
+		label string
+		names []string
+	}{
+		{"Added", rep.Added},
+		{"Already configured", rep.Kept},
+		{"Tagged", rep.Tagged},
+		{"Routed", rep.Routed},
+		{"Unknown destinations, skipped", rep.Unknown},
+	} {
+		if len(line.names) > 0 {
+			_, _ = fmt.Fprintf(stdout, "%s: %s\n", line.label, strings.Join(line.names, ", "))
+		}
+	}
+
+	switch {
+	case !rep.Changed():
+		_, _ = fmt.Fprintf(stdout, "%s is up to date\n", *path)
+	case *dryRun:
+		_, _ = fmt.Fprintf(stdout, "Dry run, %s left unchanged\n", *path)
+	default:
+		if err := atomicfile.Write(*path, merged); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(stdout, "Updated %s, restart to apply\n", *path)
+	}
+	return nil
+}
+
+// List the rule packs that can be enabled by name
+func rulesPacks(args []string, stdout io.Writer) error {
+	cfg, err := packsConfig("rules packs", args, stdout)
+	if err != nil {
+		return err
+	}
+	dir, err := cfg.StatePath("packs")
+	if err != nil {
+		return err
+	}
+	entries, err := rules.Catalog(dir)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "NAME\tREVISION\tENABLED\tSOURCE\tDESCRIPTION")
+	for _, e := range entries {
+		enabled := "no"
+		if slices.Contains(cfg.Packs.Enabled, e.Name) {
+			enabled = "yes"
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\n", e.Name, e.Revision, enabled, e.Source, e.Comment)
+	}
+	return tw.Flush()
+}
+
+// Download newer packs from the configured index into the state directory
+func rulesUpdate(ctx context.Context, args []string, stdout io.Writer) error {
+	cfg, err := packsConfig("rules update", args, stdout)
+	if err != nil {
+		return err
+	}
+	dir, err := cfg.StatePath("packs")
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: time.Minute}
+	updated, err := rules.Update(ctx, client, cfg.Packs.Index, dir)
+	for _, e := range updated {
+		_, _ = fmt.Fprintf(stdout, "Updated %s to revision %d\n", e.Name, e.Revision)
+	}
+	if err != nil {
+		return err
+	}
+	if len(updated) == 0 {
+		_, _ = fmt.Fprintln(stdout, "Rule packs are up to date")
+	} else {
+		_, _ = fmt.Fprintln(stdout, "Run rules reload, or restart, to apply updated packs that are enabled")
+	}
+	return nil
+}
+
+// Reload the keywords of a running instance from its config file, or show how
+// the keywords being soaked compare to the live ones so far
+func rulesReload(ctx context.Context, action string, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("rules "+action, flag.ContinueOnError)
+	fs.SetOutput(stdout)
+	addr := fs.String("addr", "", "admin listener address (defaults to admin.listen from config)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var info soakInfo
+	var err error
+	if action == "reload" {
+		err = adminCall(ctx, http.MethodPost, *addr, "/rules/reload", nil, &info)
+	} else {
+		err = adminGet(ctx, *addr, "/rules/soak", nil, &info)
+	}
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case info.Diff != nil:
+		d := info.Diff
+		_, _ = fmt.Fprintf(stdout, "Soaking %d keywords until %s\n", d.Keywords, d.Switch.Format(time.DateTime))
+		_, _ = fmt.Fprintf(stdout, "Messages: %d, matched alike: %d, by another rule: %d\n", d.Messages, d.Both, d.Changed)
+		w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+		for _, rule := range slices.Sorted(maps.Keys(d.Lost)) {
+			_, _ = fmt.Fprintf(w, "  only old\t%s\t%d\n", rule, d.Lost[rule])
+		}
+		for _, rule := range slices.Sorted(maps.Keys(d.Added)) {
+			_, _ = fmt.Fprintf(w, "  only new\t%s\t%d\n", rule, d.Added[rule])
+		}
+		_ = w.Flush()
+	case action == "reload":
+		_, _ = fmt.Fprintln(stdout, "Keywords reloaded")
+	default:
+		_, _ = fmt.Fprintln(stdout, "No reloaded keywords are soaking")
+	}
+	return nil
+}
+
+// Load the config for the pack subcommands, whose index and state directory it sets
+func packsConfig(name string, args []string, stdout io.Writer) (*config.Config, error) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(stdout)
+	path := flags.String("config", config.FilePath(), "config file with the packs settings")
+	index := flags.String("index", "", "pack index URL (defaults to packs.index from config)")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	cfg, err := config.LoadFile(*path)
+	if err != nil {
+		return nil, err
+	}
+	if *index != "" {
+		cfg.Packs.Index = *index
+	}
+	return cfg, nil
+}
@@ -0,0 +1,201 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/kardianos/service"
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/atomicfile"
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/logger"
+)
+
+// Adapt the monitor to the service manager lifecycle
+type scoutService struct {
+	log    *zap.Logger
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Launch the monitor without blocking the service manager
+func (p *scoutService) Start(s service.Service) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	p.done = make(chan struct{})
+
+	go func() {
+		defer close(p.done)
+		if err := run(ctx, p.log); err != nil {
+			p.log.Error("Service run failed", zap.Error(err))
+			_ = s.Stop()
+		}
+	}()
+	return nil
+}
+
+// Cancel the monitor and wait for it to shut down
+func (p *scoutService) Stop(s service.Service) error {
+	if p.cancel != nil {
+		p.cancel()
+		<-p.done
+	}
+	return nil
+}
+
+// Variables kept in the service definition, which service managers usually
+// leave world-readable. The rest, credentials included, go to the env file.
+var serviceVars = []string{"TELEGRAM_CONFIG_FILE", "TELEGRAM_STATE_DIR"}
+
+// Name of the file in the state directory holding the service's environment
+const serviceEnvFile = "service.env"
+
+// Build the service definition, capturing the current directory and pointing
+// the service at envFile for the rest of its environment
+func newService(prg service.Interface, envFile string) (service.Service, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	return service.New(prg, &service.Config{
+		Name:             "telegram-scout",
+		DisplayName:      "TelegramScout",
+		Description:      "Headless Telegram keyword monitor",
+		Arguments:        []string{"service", "run"},
+		WorkingDirectory: wd,
+		EnvVars:          serviceEnv(envFile),
+		Option: service.KeyValue{
+			"Restart": "on-failure",
+		},
+	})
+}
+
+// Return the variables written into the service definition
+func serviceEnv(envFile string) map[string]string {
+	env := map[string]string{"TELEGRAM_ENV_FILE": envFile}
+	for _, k := range serviceVars {
+		if v, ok := os.LookupEnv(k); ok {
+			env[k] = v
+		}
+	}
+	return env
+}
+
+// Save the TELEGRAM_* variables of environ to path, readable by the owner only,
+// as service managers do not inherit the shell environment
+func writeServiceEnv(path string, environ []string) error {
+	var b strings.Builder
+	for _, kv := range environ {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok || !strings.HasPrefix(k, "TELEGRAM_") || k == "TELEGRAM_ENV_FILE" {
+			continue
+		}
+		fmt.Fprintf(&b, "%s=%s\n", k, strconv.Quote(v))
+	}
+	if err := atomicfile.Write(path, []byte(b.String())); err != nil {
+		return err
+	}
+	return os.Chmod(path, 0o600)
+}
+
+// Set the variables saved by writeServiceEnv, leaving those already set alone
+func loadServiceEnv(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	for i, line := range strings.Split(string(data), "\n") {
+		if line == "" {
+			continue
+		}
+		k, quoted, ok := strings.Cut(line, "=")
+		v, err := strconv.Unquote(quoted)
+		if !ok || err != nil {
+			return fmt.Errorf("invalid line %d in %s", i+1, path)
+		}
+		if _, set := os.LookupEnv(k); !set {
+			_ = os.Setenv(k, v)
+		}
+	}
+	return nil
+}
+
+// Manage TelegramScout as a background service
+func serviceCommand(args []string, stdout io.Writer) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected one of: install, uninstall, start, stop, run")
+	}
+
+	log, err := logger.New(logger.Options{})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = log.Sync() }()
+
+	envFile := os.Getenv("TELEGRAM_ENV_FILE")
+	if envFile == "" {
+		state := &config.Config{StateDir: os.Getenv("TELEGRAM_STATE_DIR")}
+		if envFile, err = state.StatePath(serviceEnvFile); err != nil {
+			return err
+		}
+	}
+
+	prg := &scoutService{log: log}
+	svc, err := newService(prg, envFile)
+	if err != nil {
+		return err
+	}
+
+	switch action := args[0]; action {
+	case "run":
+		if err := loadServiceEnv(envFile); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return svc.Run()
+	case "install", "uninstall", "start", "stop":
+		if action == "install" {
+			if err := writeServiceEnv(envFile, os.Environ()); err != nil {
+				return fmt.Errorf("failed to save the service environment: %w", err)
+			}
+			_, _ = fmt.Fprintf(stdout, "Saved the TELEGRAM_* variables to %s\n", envFile)
+		}
+		if err := service.Control(svc, action); err != nil {
+			return err
+		}
+		if action == "uninstall" {
+			if err := os.Remove(envFile); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+		}
+		_, _ = fmt.Fprintf(stdout, "Service %s: done (%s)\n", action, service.Platform())
+		return nil
+	default:
+		return fmt.Errorf("unknown service action %q", action)
+	}
+}
@@ -0,0 +1,276 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/archive"
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/render"
+	"github.com/h3nc4/TelegramScout/internal/schedule"
+	"github.com/h3nc4/TelegramScout/internal/scout"
+	"github.com/h3nc4/TelegramScout/internal/telegram"
+	"github.com/h3nc4/TelegramScout/internal/version"
+)
+
+// Runes kept free for headings and the count of omitted entries
+const listingReserve = 256
+
+// Build a notification listing entries, stopping before render.MaxLength
+// and counting the entries left out
+type listing struct {
+	b       strings.Builder
+	length  int
+	omitted int
+}
+
+// Add a heading or count, always kept
+func (l *listing) line(format string, args ...any) {
+	l.write(fmt.Sprintf(format, args...))
+}
+
+// Add an entry while it fits, omitting it and every later one otherwise
+func (l *listing) entry(format string, args ...any) {
+	text := fmt.Sprintf(format, args...)
+	if l.omitted > 0 || l.length+utf8.RuneCountInString(text) > render.MaxLength-listingReserve {
+		l.omitted++
+		return
+	}
+	l.write(text)
+}
+
+func (l *listing) write(text string) {
+	l.b.WriteString(text)
+	l.length += utf8.RuneCountInString(text)
+}
+
+// Return the notification, ending with the number of omitted entries
+func (l *listing) String() string {
+	text := strings.TrimRight(l.b.String(), "\n")
+	if l.omitted > 0 {
+		text += fmt.Sprintf("\n…and %d more", l.omitted)
+	}
+	return text
+}
+
+// Build the HTML startup notification describing what is being monitored
+func startupSummary(info version.Info, report telegram.ResolveReport, compiled int, rejected []scout.RejectedRule) string {
+	var l listing
+	l.line("🟢 <b>TelegramScout %s is online</b>\n\n", html.EscapeString(info.String()))
+
+	l.line("📋 <b>Rules:</b> %d compiled, %d rejected\n", compiled, len(rejected))
+	for _, r := range rejected {
+		l.entry("  ✖ <code>%s</code> — %s\n", html.EscapeString(r.Keyword), html.EscapeString(r.Reason))
+	}
+
+	// Failures first, they are the ones worth reading when the list is cut
+	l.line("\n💬 <b>Chats:</b> %d resolved, %d failed\n", len(report.Resolved), len(report.Failed))
+	for _, c := range report.Failed {
+		l.entry("  ✖ <code>%s</code> — %s\n", html.EscapeString(c.Target), html.EscapeString(c.Reason))
+	}
+	for _, c := range report.Resolved {
+		text := fmt.Sprintf("  ✔ %s (<code>%s</code>)\n", html.EscapeString(c.Title), html.EscapeString(c.Target))
+		if c.Hint != "" {
+			text += fmt.Sprintf("     💡 %s\n", html.EscapeString(c.Hint))
+		}
+		l.entry("%s", text)
+	}
+
+	return l.String()
+}
+
+// Fields of notifier.startup.template
+type startupData struct {
+	Host     string
+	Version  string
+	Rules    int // Compiled rules
+	Rejected int // Rules that failed to compile
+	Chats    int // Resolved chats
+	Failed   int // Chats that could not be resolved
+}
+
+// Why the process stopped, as reported by the shutdown notification
+const (
+	shutdownSignal = "signal"       // Interrupted or terminated
+	shutdownCrash  = "crash"        // Panicked
+	shutdownConfig = "config error" // Failed to set up, such as on invalid rules
+)
+
+// Fields of notifier.shutdown.template
+type shutdownData struct {
+	Host    string
+	Version string
+	Reason  string // One of the shutdown constants
+	Error   string // What failed, empty on signals
+	Uptime  time.Duration
+}
+
+// Build the HTML notification sent when the process stops
+func shutdownNotice(d shutdownData) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "🔴 <b>TelegramScout %s on %s is offline</b>\n\n", html.EscapeString(d.Version), html.EscapeString(d.Host))
+	fmt.Fprintf(&b, "❔ <b>Reason:</b> %s\n", d.Reason)
+	if d.Error != "" {
+		fmt.Fprintf(&b, "  ✖ <code>%s</code>\n", html.EscapeString(d.Error))
+	}
+	fmt.Fprintf(&b, "⏱ <b>Uptime:</b> %s", d.Uptime)
+	return b.String()
+}
+
+// Render a lifecycle notification from its template, or return builtin without one
+func renderLifecycle(tmpl string, data any, builtin string) (string, error) {
+	if tmpl == "" {
+		return builtin, nil
+	}
+	t, err := template.New("lifecycle").Parse(tmpl)
+	if err != nil {
+		return builtin, err
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, data); err != nil {
+		return builtin, err
+	}
+	return b.String(), nil
+}
+
+// Build the HTML warning listing chats that could not be resolved
+func unresolvedAlert(failed []telegram.FailedChat) string {
+	var l listing
+	l.line("⚠️ <b>%d configured chats could not be resolved and are not monitored</b>\n", len(failed))
+	for _, c := range failed {
+		text := fmt.Sprintf("\n✖ <code>%s</code> — %s", html.EscapeString(c.Target), html.EscapeString(c.Reason))
+		if c.Hint != "" {
+			text += fmt.Sprintf("\n   💡 %s", html.EscapeString(c.Hint))
+		}
+		l.entry("%s", text)
+	}
+	return l.String()
+}
+
+// Build the HTML warning listing monitored chats whose config entry is outdated
+func outdatedAlert(resolved []telegram.ResolvedChat) string {
+	var outdated []telegram.ResolvedChat
+	for _, c := range resolved {
+		if c.Hint != "" {
+			outdated = append(outdated, c)
+		}
+	}
+	var l listing
+	l.line("🔁 <b>%d configured chats changed and were found again, update the config</b>\n", len(outdated))
+	for _, c := range outdated {
+		l.entry("\n✔ <code>%s</code> — %s\n   💡 %s", html.EscapeString(c.Target), html.EscapeString(c.Title), html.EscapeString(c.Hint))
+	}
+	return l.String()
+}
+
+// Identify the outdated chats of a resolution, empty when there are none
+func hintKey(resolved []telegram.ResolvedChat) string {
+	var hints []string
+	for _, c := range resolved {
+		if c.Hint != "" {
+			hints = append(hints, c.Target+"\x00"+c.Hint)
+		}
+	}
+	slices.Sort(hints)
+	return strings.Join(hints, "\x00")
+}
+
+// Build the HTML notice sent when a monitored group becomes a supergroup
+func migrationNotice(m telegram.Migration) string {
+	return fmt.Sprintf("⤴️ <b>%s</b> was upgraded to a supergroup and is now monitored as <code>%d</code>\n   💡 %s",
+		html.EscapeString(m.Title), m.To, html.EscapeString(m.Hint()))
+}
+
+// Identify a set of failures so repeated reports can be suppressed
+func failureKey(failed []telegram.FailedChat) string {
+	targets := make([]string, len(failed))
+	for i, c := range failed {
+		targets[i] = c.Target
+	}
+	slices.Sort(targets)
+	return strings.Join(targets, "\x00")
+}
+
+// Build the HTML report of the alerts archived over the last day and week
+func alertSummary(r archive.Report) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "📊 <b>TelegramScout summary</b>\n\n")
+	fmt.Fprintf(&b, "🔔 <b>Alerts:</b> %d in the last 24h, %d in the last 7d\n", r.Day, r.Week)
+
+	if len(r.TopRules) > 0 {
+		fmt.Fprintf(&b, "\n📋 <b>Top rules</b>\n")
+		for _, c := range r.TopRules {
+			fmt.Fprintf(&b, "  • <code>%s</code> — %d (%d in 7d)\n", html.EscapeString(c.Name), c.Day, c.Week)
+		}
+	}
+	if len(r.TopChats) > 0 {
+		fmt.Fprintf(&b, "\n💬 <b>Top chats</b>\n")
+		for _, c := range r.TopChats {
+			fmt.Fprintf(&b, "  • %s (<code>%d</code>) — %d (%d in 7d)\n", html.EscapeString(c.Name), c.ChatID, c.Day, c.Week)
+		}
+	}
+	if len(r.Spikes) > 0 {
+		fmt.Fprintf(&b, "\n📈 <b>Spikes</b>\n")
+		for _, s := range r.Spikes {
+			fmt.Fprintf(&b, "  • <code>%s</code> — %d in 24h, %.1f a day before\n", html.EscapeString(s.Rule), s.Day, s.Average)
+		}
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// Send the alert summary on its schedule until ctx is done
+func runSummary(ctx context.Context, cfg config.SummaryConfig, store archive.Store, send func(context.Context, string) error, log *zap.Logger) {
+	sched, err := schedule.Parse(cfg.Schedule)
+	if err != nil {
+		log.Error("Invalid summary schedule", zap.String("schedule", cfg.Schedule), zap.Error(err))
+		return
+	}
+	for {
+		next := sched.Next(time.Now())
+		if next.IsZero() {
+			log.Warn("Summary schedule never fires", zap.String("schedule", cfg.Schedule))
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Until(next)):
+		}
+
+		report, err := archive.Summarize(ctx, store, time.Now(), cfg.Top)
+		if err != nil {
+			log.Error("Failed to summarize archive", zap.Error(err))
+			continue
+		}
+		if err := send(ctx, alertSummary(report)); err != nil {
+			log.Error("Failed to send summary", zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/scout"
+)
+
+// Load the config and compile its rules as the monitor would, reporting
+// rejected rules and lint findings without connecting to Telegram
+func validateCommand(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	fs.SetOutput(stdout)
+	path := fs.String("config", config.FilePath(), "config file to check")
+	strict := fs.Bool("strict", false, "also fail on lint findings")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadFile(*path)
+	if err != nil {
+		return err
+	}
+	if err := applyPacks(cfg, zap.NewNop()); err != nil {
+		return err
+	}
+	s := scout.New(cfg, nil, zap.NewNop())
+	compiled, rejected := s.Rules()
+	findings := s.Lint()
+
+	_, _ = fmt.Fprintf(stdout, "Rules: %d compiled, %d rejected, %d lint findings\n", compiled, len(rejected), len(findings))
+	for _, r := range rejected {
+		_, _ = fmt.Fprintf(stdout, "  rejected %q: %s\n", r.Keyword, r.Reason)
+	}
+	for _, f := range findings {
+		_, _ = fmt.Fprintf(stdout, "  lint     %q: %s\n           %s\n", f.Keyword, f.Problem, f.Suggestion)
+	}
+
+	switch {
+	case len(rejected) > 0:
+		return fmt.Errorf("%d rules rejected", len(rejected))
+	case *strict && len(findings) > 0:
+		return fmt.Errorf("%d lint findings", len(findings))
+	}
+	return nil
+}
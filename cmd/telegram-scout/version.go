@@ -0,0 +1,96 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/h3nc4/TelegramScout/internal/version"
+)
+
+// Print build information
+func versionCommand(stdout io.Writer) error {
+	info := version.Get()
+	_, _ = fmt.Fprintf(stdout, "telegram-scout %s\n", info.Version)
+	_, _ = fmt.Fprintf(stdout, "  commit:   %s\n", info.Commit)
+	_, _ = fmt.Fprintf(stdout, "  built:    %s\n", info.Date)
+	_, _ = fmt.Fprintf(stdout, "  go:       %s\n", info.GoVersion)
+	_, _ = fmt.Fprintf(stdout, "  platform: %s\n", info.Platform)
+	return nil
+}
+
+// Replace the running binary with the latest GitHub release
+func selfUpdateCommand(ctx context.Context, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("self-update", flag.ContinueOnError)
+	fs.SetOutput(stdout)
+	check := fs.Bool("check", false, "only report whether an update is available")
+	force := fs.Bool("force", false, "install the latest release even when it is not newer, such as over a development build")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	current := version.Get().Version
+	u := version.NewUpdater()
+	rel, err := u.Latest(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !rel.Newer(current) && !*force {
+		if !version.IsRelease(current) {
+			_, _ = fmt.Fprintf(stdout, "Development build %s, use -force to install %s\n", current, rel.TagName)
+		} else {
+			_, _ = fmt.Fprintf(stdout, "Already up to date (%s, latest release %s)\n", current, rel.TagName)
+		}
+		return nil
+	}
+	if *check {
+		_, _ = fmt.Fprintf(stdout, "Update available: %s -> %s\n", current, rel.TagName)
+		return nil
+	}
+
+	asset, ok := rel.AssetFor(runtime.GOOS, runtime.GOARCH)
+	if !ok {
+		return fmt.Errorf("release %s has no binary for %s/%s", rel.TagName, runtime.GOOS, runtime.GOARCH)
+	}
+	sums, ok := rel.Checksums()
+	if !ok {
+		return fmt.Errorf("release %s has no checksums file, refusing to install an unverified binary", rel.TagName)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		return err
+	}
+
+	if err := u.Install(ctx, asset, sums, exe); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(stdout, "Updated %s -> %s\n", current, rel.TagName)
+	return nil
+}
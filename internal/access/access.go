@@ -0,0 +1,97 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package access decides which roles bot command users and admin API
+// callers hold, and whether a role may do what they ask.
+package access
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+var (
+	// Returned for users and tokens that are not configured
+	ErrUnknown = errors.New("not authorized")
+
+	// Returned when the role is too low for the request
+	ErrDenied = errors.New("permission denied")
+)
+
+// Roles of the configured users and tokens
+type Policy struct {
+	users  map[int64]string
+	tokens []config.AccessToken
+}
+
+// Create a Policy from the access config
+func New(cfg config.AccessConfig) *Policy {
+	return &Policy{users: cfg.Users, tokens: cfg.Tokens}
+}
+
+// Report whether bot command users are restricted, without users everyone
+// in the alert chats may do everything
+func (p *Policy) RestrictsUsers() bool {
+	return len(p.users) > 0
+}
+
+// Report whether admin API callers are restricted, without tokens everyone
+// on the admin listener may do everything
+func (p *Policy) RestrictsTokens() bool {
+	return len(p.tokens) > 0
+}
+
+// Check that the Telegram user holds at least the role need
+func (p *Policy) User(userID int64, need string) error {
+	if !p.RestrictsUsers() {
+		return nil
+	}
+	role, ok := p.users[userID]
+	if !ok {
+		return ErrUnknown
+	}
+	return check(role, need)
+}
+
+// Check that the bearer token holds at least the role need, returning its name
+func (p *Policy) Token(token, need string) (string, error) {
+	if !p.RestrictsTokens() {
+		return "", nil
+	}
+	// Compare against every token so the time taken does not tell which matched
+	var found *config.AccessToken
+	for i := range p.tokens {
+		if subtle.ConstantTimeCompare([]byte(p.tokens[i].Token), []byte(token)) == 1 {
+			found = &p.tokens[i]
+		}
+	}
+	if found == nil || token == "" {
+		return "", ErrUnknown
+	}
+	return found.Name, check(found.Role, need)
+}
+
+func check(role, need string) error {
+	if role == config.RoleAdmin || role == need {
+		return nil
+	}
+	return fmt.Errorf("%w: %s role required", ErrDenied, need)
+}
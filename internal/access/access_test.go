@@ -0,0 +1,88 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package access
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+func TestPolicy(t *testing.T) {
+	if err := New(config.AccessConfig{}).User(42, config.RoleAdmin); err != nil {
+		t.Errorf("expected everything allowed without access config, got %v", err)
+	}
+
+	p := New(config.AccessConfig{
+		Users: map[int64]string{1: config.RoleAdmin, 2: config.RoleViewer},
+		Tokens: []config.AccessToken{
+			{Name: "ops", Token: "s3cret", Role: config.RoleAdmin},
+			{Name: "grafana", Token: "r3ad", Role: config.RoleViewer},
+		},
+	})
+
+	users := []struct {
+		name string
+		id   int64
+		need string
+		want error
+	}{
+		{"Admin Changes", 1, config.RoleAdmin, nil},
+		{"Admin Views", 1, config.RoleViewer, nil},
+		{"Viewer Views", 2, config.RoleViewer, nil},
+		{"Viewer Changes", 2, config.RoleAdmin, ErrDenied},
+		{"Unknown User", 3, config.RoleViewer, ErrUnknown},
+	}
+	for _, tt := range users {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := p.User(tt.id, tt.need); !errors.Is(err, tt.want) {
+				t.Errorf("User(%d, %s) = %v, want %v", tt.id, tt.need, err, tt.want)
+			}
+		})
+	}
+
+	if name, err := p.Token("s3cret", config.RoleAdmin); err != nil || name != "ops" {
+		t.Errorf("Token(admin) = %q, %v", name, err)
+	}
+	if name, err := p.Token("r3ad", config.RoleAdmin); !errors.Is(err, ErrDenied) || name != "grafana" {
+		t.Errorf("Token(viewer) for a change = %q, %v", name, err)
+	}
+	for _, token := range []string{"", "wrong"} {
+		if _, err := p.Token(token, config.RoleViewer); !errors.Is(err, ErrUnknown) {
+			t.Errorf("Token(%q) = %v, want %v", token, err, ErrUnknown)
+		}
+	}
+
+	// Each channel is only restricted by its own list
+	usersOnly := New(config.AccessConfig{Users: map[int64]string{1: config.RoleViewer}})
+	if _, err := usersOnly.Token("", config.RoleAdmin); err != nil {
+		t.Errorf("expected the admin API open without tokens, got %v", err)
+	}
+	if err := usersOnly.User(2, config.RoleViewer); !errors.Is(err, ErrUnknown) {
+		t.Errorf("expected unknown users refused, got %v", err)
+	}
+	tokensOnly := New(config.AccessConfig{Tokens: []config.AccessToken{{Name: "ops", Token: "s3cret", Role: config.RoleAdmin}}})
+	if err := tokensOnly.User(42, config.RoleAdmin); err != nil {
+		t.Errorf("expected bot commands open without users, got %v", err)
+	}
+	if _, err := tokensOnly.Token("", config.RoleViewer); !errors.Is(err, ErrUnknown) {
+		t.Errorf("expected a missing token refused, got %v", err)
+	}
+}
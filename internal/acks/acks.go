@@ -0,0 +1,183 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package acks tracks whether someone has taken care of an alert.
+package acks
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Alert states
+const (
+	StateNew      = "new"
+	StateAcked    = "acked"
+	StateResolved = "resolved"
+)
+
+var (
+	// Returned for IDs that were never issued or were already forgotten
+	ErrUnknownAlert = errors.New("unknown alert")
+	// Returned when a state change does not apply, e.g. acknowledging a resolved alert
+	ErrInvalidTransition = errors.New("invalid state transition")
+)
+
+// A tracked alert and who last changed its state
+type Alert struct {
+	ID        string    `json:"id"`
+	Rule      string    `json:"rule"`
+	Tags      []string  `json:"tags,omitempty"`
+	Critical  bool      `json:"critical"`
+	ChatID    int64     `json:"chat_id"`
+	ChatTitle string    `json:"chat_title"`
+	MsgID     int       `json:"msg_id"`
+	Link      string    `json:"link,omitempty"`
+	Created   time.Time `json:"created"`
+	State     string    `json:"state"`
+	Updated   time.Time `json:"updated"`
+	By        string    `json:"by,omitempty"`
+	Reminders int       `json:"reminders"`
+
+	lastReminder time.Time
+}
+
+// Return the ID of the alert for a message
+func ID(chatID int64, msgID int) string {
+	return fmt.Sprintf("%d:%d", chatID, msgID)
+}
+
+// Keep the state of the most recent alerts, forgetting the oldest beyond size
+type Store struct {
+	mux    sync.Mutex
+	alerts map[string]*Alert
+	order  []string // Oldest first
+	size   int
+	now    func() time.Time
+}
+
+// Create new Store tracking up to size alerts
+func New(size int) *Store {
+	if size <= 0 {
+		size = 1
+	}
+	return &Store{alerts: make(map[string]*Alert), size: size, now: time.Now}
+}
+
+// Start tracking a as new, returning it with its ID and timestamps set
+func (s *Store) Add(a Alert) Alert {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	a.ID = ID(a.ChatID, a.MsgID)
+	a.Created = s.now()
+	a.Updated = a.Created
+	a.State = StateNew
+	if _, ok := s.alerts[a.ID]; ok {
+		// An edit alerting again counts as a new alert
+		s.order = slices.DeleteFunc(s.order, func(id string) bool { return id == a.ID })
+	}
+	s.order = append(s.order, a.ID)
+	s.alerts[a.ID] = &a
+
+	for len(s.order) > s.size {
+		delete(s.alerts, s.order[0])
+		s.order = s.order[1:]
+	}
+	return a
+}
+
+// Return the alert with id
+func (s *Store) Get(id string) (Alert, bool) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	a, ok := s.alerts[id]
+	if !ok {
+		return Alert{}, false
+	}
+	return *a, true
+}
+
+// Move the alert to state on behalf of by. New alerts can be acknowledged
+// or resolved, acknowledged ones resolved.
+func (s *Store) Set(id, state, by string) (Alert, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	a, ok := s.alerts[id]
+	if !ok {
+		return Alert{}, fmt.Errorf("%w: %s", ErrUnknownAlert, id)
+	}
+	switch {
+	case state == StateAcked && a.State == StateNew:
+	case state == StateResolved && a.State != StateResolved:
+	default:
+		return *a, fmt.Errorf("%w: %s to %s", ErrInvalidTransition, a.State, state)
+	}
+	a.State = state
+	a.By = by
+	a.Updated = s.now()
+	return *a, nil
+}
+
+// Return up to limit alerts, newest first. An empty state matches all.
+func (s *Store) List(state string, limit int) []Alert {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	var out []Alert
+	for _, id := range slices.Backward(s.order) {
+		if len(out) >= limit {
+			break
+		}
+		if a := s.alerts[id]; state == "" || a.State == state {
+			out = append(out, *a)
+		}
+	}
+	return out
+}
+
+// Return the critical alerts still new after every, at most max times each,
+// counting the reminder as sent
+func (s *Store) Due(every time.Duration, max int) []Alert {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	now := s.now()
+	var due []Alert
+	for _, id := range s.order {
+		a := s.alerts[id]
+		if !a.Critical || a.State != StateNew || a.Reminders >= max {
+			continue
+		}
+		last := a.lastReminder
+		if last.IsZero() {
+			last = a.Created
+		}
+		if now.Sub(last) < every {
+			continue
+		}
+		a.Reminders++
+		a.lastReminder = now
+		due = append(due, *a)
+	}
+	return due
+}
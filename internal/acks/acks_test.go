@@ -0,0 +1,103 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package acks
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStore_Set(t *testing.T) {
+	s := New(10)
+	a := s.Add(Alert{Rule: "bitcoin", ChatID: 100, MsgID: 7})
+	if a.ID != "100:7" || a.State != StateNew {
+		t.Fatalf("unexpected alert %+v", a)
+	}
+
+	if _, err := s.Set("1:1", StateAcked, "alice"); !errors.Is(err, ErrUnknownAlert) {
+		t.Errorf("expected ErrUnknownAlert, got %v", err)
+	}
+	if a, err := s.Set(a.ID, StateAcked, "alice"); err != nil || a.State != StateAcked || a.By != "alice" {
+		t.Errorf("Set(acked) = %+v, %v", a, err)
+	}
+	if _, err := s.Set(a.ID, StateAcked, "bob"); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("expected ErrInvalidTransition acking twice, got %v", err)
+	}
+	if a, err := s.Set(a.ID, StateResolved, "bob"); err != nil || a.State != StateResolved || a.By != "bob" {
+		t.Errorf("Set(resolved) = %+v, %v", a, err)
+	}
+	if _, err := s.Set(a.ID, StateResolved, "bob"); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("expected ErrInvalidTransition resolving twice, got %v", err)
+	}
+}
+
+func TestStore_List(t *testing.T) {
+	s := New(2)
+	s.Add(Alert{ChatID: 1, MsgID: 1})
+	s.Add(Alert{ChatID: 1, MsgID: 2})
+	s.Add(Alert{ChatID: 1, MsgID: 3})
+	_, _ = s.Set("1:3", StateAcked, "alice")
+
+	if _, ok := s.Get("1:1"); ok {
+		t.Error("expected the oldest alert to be forgotten")
+	}
+	all := s.List("", 10)
+	if len(all) != 2 || all[0].ID != "1:3" || all[1].ID != "1:2" {
+		t.Errorf("expected newest first, got %+v", all)
+	}
+	if acked := s.List(StateAcked, 10); len(acked) != 1 || acked[0].ID != "1:3" {
+		t.Errorf("expected only the acked alert, got %+v", acked)
+	}
+	if limited := s.List("", 1); len(limited) != 1 {
+		t.Errorf("expected limit to apply, got %+v", limited)
+	}
+}
+
+func TestStore_Due(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	s := New(10)
+	s.now = func() time.Time { return now }
+	s.Add(Alert{ChatID: 1, MsgID: 1, Critical: true})
+	s.Add(Alert{ChatID: 1, MsgID: 2})
+	s.Add(Alert{ChatID: 1, MsgID: 3, Critical: true})
+	_, _ = s.Set("1:3", StateAcked, "alice")
+
+	if due := s.Due(time.Minute, 2); len(due) != 0 {
+		t.Errorf("expected nothing due right away, got %+v", due)
+	}
+
+	now = now.Add(time.Minute)
+	due := s.Due(time.Minute, 2)
+	if len(due) != 1 || due[0].ID != "1:1" || due[0].Reminders != 1 {
+		t.Fatalf("expected the unacked critical alert, got %+v", due)
+	}
+	if due := s.Due(time.Minute, 2); len(due) != 0 {
+		t.Errorf("expected reminder interval to restart, got %+v", due)
+	}
+
+	now = now.Add(time.Minute)
+	if due := s.Due(time.Minute, 2); len(due) != 1 {
+		t.Errorf("expected second reminder, got %+v", due)
+	}
+	now = now.Add(time.Minute)
+	if due := s.Due(time.Minute, 2); len(due) != 0 {
+		t.Errorf("expected reminders to stop at the maximum, got %+v", due)
+	}
+}
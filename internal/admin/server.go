@@ -0,0 +1,168 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/access"
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+// Serve the local admin HTTP API
+type Server struct {
+	mux    *http.ServeMux
+	log    *zap.Logger
+	addr   string
+	policy *access.Policy
+}
+
+// Create new admin Server bound to the configured address
+func New(cfg *config.Config, log *zap.Logger) *Server {
+	s := &Server{
+		mux:    http.NewServeMux(),
+		log:    log,
+		addr:   cfg.Admin.Listen,
+		policy: access.New(cfg.Access),
+	}
+	if cfg.Admin.Diagnostics {
+		s.registerDiagnostics(cfg.Admin.DiagnosticsRemote)
+	}
+	return s
+}
+
+// Register a handler on the admin mux
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// Expose the mux, behind the token check, for tests and embedding
+func (s *Server) Handler() http.Handler {
+	return s.authorize(s.mux)
+}
+
+// Listen until the context is cancelled. Return immediately if no address is configured.
+func (s *Server) Run(ctx context.Context) error {
+	if s.addr == "" {
+		return nil
+	}
+
+	network, address := splitAddr(s.addr)
+	if network == "unix" {
+		// Clear a socket left behind by an unclean shutdown
+		if err := os.Remove(address); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+
+	ln, err := net.Listen(network, address)
+	if err != nil {
+		return err
+	}
+
+	srv := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		ConnContext: func(ctx context.Context, c net.Conn) context.Context {
+			// Socket clients are local by definition
+			if network == "unix" {
+				ctx = context.WithValue(ctx, localConnKey{}, true)
+			}
+			return ctx
+		},
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	s.log.Info("Admin listener started", zap.String("addr", ln.Addr().String()))
+	if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Mark requests that arrived over the unix socket
+type localConnKey struct{}
+
+// Split a listen address into network and address, accepting unix:/path for sockets
+func splitAddr(addr string) (string, string) {
+	if path, ok := strings.CutPrefix(addr, "unix:"); ok {
+		return "unix", path
+	}
+	return "tcp", addr
+}
+
+// Return an HTTP client and base URL for reaching the admin listener at addr
+func NewClient(addr string, timeout time.Duration) (*http.Client, string) {
+	network, address := splitAddr(addr)
+	if network != "unix" {
+		return &http.Client{Timeout: timeout}, "http://" + address
+	}
+
+	var d net.Dialer
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return d.DialContext(ctx, "unix", address)
+		},
+	}
+	return &http.Client{Timeout: timeout, Transport: transport}, "http://unix"
+}
+
+// Wrap a function returning a JSON-serializable value as a GET handler
+func JSONHandler(fn func(r *http.Request) (any, error)) http.Handler {
+	return jsonHandler(http.MethodGet, fn)
+}
+
+// Wrap a function changing state and returning a JSON-serializable value as a POST handler
+func JSONPostHandler(fn func(r *http.Request) (any, error)) http.Handler {
+	return jsonHandler(http.MethodPost, fn)
+}
+
+func jsonHandler(method string, fn func(r *http.Request) (any, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		v, err := fn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(v)
+	})
+}
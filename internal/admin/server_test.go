@@ -0,0 +1,227 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+func TestServer(t *testing.T) {
+	s := New(&config.Config{}, zap.NewNop())
+	s.Handle("/value", JSONHandler(func(r *http.Request) (any, error) {
+		if r.URL.Query().Get("fail") != "" {
+			return nil, errors.New("bad request")
+		}
+		return map[string]int{"answer": 42}, nil
+	}))
+
+	t.Run("JSON Response", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/value", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rec.Code)
+		}
+		if !strings.Contains(rec.Body.String(), `"answer":42`) {
+			t.Errorf("unexpected body: %s", rec.Body.String())
+		}
+	})
+
+	t.Run("Handler Error", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/value?fail=1", nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", rec.Code)
+		}
+	})
+
+	t.Run("Method Not Allowed", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/value", nil))
+		if rec.Code != http.StatusMethodNotAllowed {
+			t.Errorf("expected 405, got %d", rec.Code)
+		}
+	})
+
+	t.Run("POST Handler", func(t *testing.T) {
+		s.Handle("/change", JSONPostHandler(func(r *http.Request) (any, error) {
+			return map[string]bool{"changed": true}, nil
+		}))
+
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/change", nil))
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"changed":true`) {
+			t.Errorf("unexpected response %d: %s", rec.Code, rec.Body.String())
+		}
+
+		rec = httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/change", nil))
+		if rec.Code != http.StatusMethodNotAllowed {
+			t.Errorf("expected 405 for GET, got %d", rec.Code)
+		}
+	})
+
+	t.Run("Disabled Without Address", func(t *testing.T) {
+		if err := s.Run(context.Background()); err != nil {
+			t.Errorf("expected nil error when disabled, got %v", err)
+		}
+	})
+}
+
+func TestDiagnostics(t *testing.T) {
+	t.Run("Disabled By Default", func(t *testing.T) {
+		s := New(&config.Config{}, zap.NewNop())
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/vars", nil))
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("expected 404, got %d", rec.Code)
+		}
+	})
+
+	s := New(&config.Config{Admin: config.AdminConfig{Diagnostics: true}}, zap.NewNop())
+
+	t.Run("Loopback Allowed", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/debug/vars", nil)
+		req.RemoteAddr = "127.0.0.1:5000"
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rec.Code)
+		}
+		if !strings.Contains(rec.Body.String(), "memstats") {
+			t.Error("expected expvar output to contain memstats")
+		}
+	})
+
+	t.Run("Remote Rejected", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil)
+		req.RemoteAddr = "203.0.113.7:5000"
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, req)
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("expected 403, got %d", rec.Code)
+		}
+	})
+}
+
+func TestAccess(t *testing.T) {
+	s := New(&config.Config{Access: config.AccessConfig{Tokens: []config.AccessToken{
+		{Name: "ops", Token: "s3cret", Role: config.RoleAdmin},
+		{Name: "grafana", Token: "r3ad", Role: config.RoleViewer},
+	}}}, zap.NewNop())
+	s.Handle("/healthz", JSONHandler(func(r *http.Request) (any, error) { return "ok", nil }))
+	s.Handle("/value", JSONHandler(func(r *http.Request) (any, error) { return Caller(r), nil }))
+	s.Handle("/change", JSONPostHandler(func(r *http.Request) (any, error) { return Caller(r), nil }))
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		code   int
+	}{
+		{"Health Check Open", http.MethodGet, "/healthz", "", http.StatusOK},
+		{"Missing Token", http.MethodGet, "/value", "", http.StatusUnauthorized},
+		{"Wrong Token", http.MethodGet, "/value", "guess", http.StatusUnauthorized},
+		{"Viewer Reads", http.MethodGet, "/value", "r3ad", http.StatusOK},
+		{"Viewer Changes", http.MethodPost, "/change", "r3ad", http.StatusForbidden},
+		{"Admin Changes", http.MethodPost, "/change", "s3cret", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			s.Handler().ServeHTTP(rec, req)
+			if rec.Code != tt.code {
+				t.Errorf("expected %d, got %d: %s", tt.code, rec.Code, rec.Body.String())
+			}
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/change", nil)
+	req.Header.Set("Authorization", "Bearer s3cret")
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	if !strings.Contains(rec.Body.String(), `"ops"`) {
+		t.Errorf("expected the token name as caller, got %s", rec.Body.String())
+	}
+
+	// Bot users alone leave the admin API open
+	usersOnly := New(&config.Config{Access: config.AccessConfig{Users: map[int64]string{1: config.RoleAdmin}}}, zap.NewNop())
+	usersOnly.Handle("/change", JSONPostHandler(func(r *http.Request) (any, error) { return Caller(r), nil }))
+	rec = httptest.NewRecorder()
+	usersOnly.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/change", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected the admin API open without tokens, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestUnixSocket(t *testing.T) {
+	sock := filepath.Join(t.TempDir(), "admin.sock")
+	// A stale socket file must not prevent startup
+	if err := os.WriteFile(sock, nil, 0600); err != nil {
+		t.Fatalf("failed to create stale socket: %v", err)
+	}
+
+	addr := "unix:" + sock
+	s := New(&config.Config{Admin: config.AdminConfig{Listen: addr, Diagnostics: true}}, zap.NewNop())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Run(ctx) }()
+	defer func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Run returned error: %v", err)
+		}
+	}()
+
+	client, base := NewClient(addr, time.Second)
+	var resp *http.Response
+	var err error
+	for range 50 {
+		if resp, err = client.Get(base + "/debug/vars"); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("failed to reach socket: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// Diagnostics are allowed for socket clients
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,215 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package annotate marks notable events, such as restarts, reconnects and
+// alert storms, as Grafana annotations and counts them as a metric.
+package annotate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+// Kinds of events, also used as annotation tags
+const (
+	KindStart     = "start"     // The monitor started, with the rules it loaded
+	KindReconnect = "reconnect" // The Telegram client crashed and is restarted
+	KindStorm     = "storm"     // Alerts exceeded the storm threshold
+	KindEngine    = "engine"    // The matching engine was switched at runtime
+	KindReload    = "reload"    // Keywords were reloaded from the config file
+)
+
+// Events counted per kind, served at /debug/vars and pushed with the other metrics
+var eventMetrics = expvar.NewMap("events")
+
+// Tag on every annotation, so dashboards can filter for them
+const baseTag = "telegram-scout"
+
+// Record events and detect alert storms
+type Annotator struct {
+	cfg    config.AnnotationsConfig
+	client *http.Client
+	log    *zap.Logger
+	now    func() time.Time
+
+	mux    sync.Mutex
+	alerts int // In the current window
+
+	// Only used by Run
+	storm time.Time // Start of the running storm, zero when calm
+	peak  int       // Most alerts in a window of the running storm
+}
+
+// Create an Annotator, posting to Grafana only when a URL is configured
+func New(cfg config.AnnotationsConfig, log *zap.Logger) *Annotator {
+	return &Annotator{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 10 * time.Second},
+		log:    log,
+		now:    time.Now,
+	}
+}
+
+// Count an event and annotate it in the background
+func (a *Annotator) Event(ctx context.Context, kind, text string) {
+	eventMetrics.Add(kind, 1)
+	if a.cfg.Grafana == "" {
+		return
+	}
+	at := a.now()
+	go func() {
+		if _, err := a.post(context.WithoutCancel(ctx), kind, text, at); err != nil {
+			a.log.Warn("Failed to annotate event", zap.String("kind", kind), zap.Error(err))
+		}
+	}()
+}
+
+// Count an alert towards storm detection
+func (a *Annotator) Alert() {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	a.alerts++
+}
+
+// Check the alert count every storm window until ctx is done, annotating the
+// start of a storm and turning it into a region once it passes
+func (a *Annotator) Run(ctx context.Context) {
+	if a.cfg.StormThreshold <= 0 {
+		return
+	}
+	ticker := time.NewTicker(a.cfg.StormWindow)
+	defer ticker.Stop()
+
+	var id int64 // Of the running storm's annotation, zero when not posted
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			id = a.checkStorm(ctx, id)
+		}
+	}
+}
+
+// Close the current window, returning the annotation ID of the running storm
+func (a *Annotator) checkStorm(ctx context.Context, id int64) int64 {
+	a.mux.Lock()
+	count := a.alerts
+	a.alerts = 0
+	a.mux.Unlock()
+
+	now := a.now()
+	switch stormy := count >= a.cfg.StormThreshold; {
+	case stormy && a.storm.IsZero():
+		a.storm, a.peak = now.Add(-a.cfg.StormWindow), count
+		eventMetrics.Add(KindStorm, 1)
+		a.log.Warn("Alert storm started", zap.Int("alerts", count), zap.Duration("window", a.cfg.StormWindow))
+		if a.cfg.Grafana == "" {
+			return 0
+		}
+		id, err := a.post(ctx, KindStorm, fmt.Sprintf("Alert storm: %d alerts in %s", count, a.cfg.StormWindow), a.storm)
+		if err != nil {
+			a.log.Warn("Failed to annotate alert storm", zap.Error(err))
+		}
+		return id
+
+	case stormy:
+		a.peak = max(a.peak, count)
+
+	case !a.storm.IsZero():
+		lasted := now.Sub(a.storm).Round(time.Second)
+		a.log.Info("Alert storm ended", zap.Duration("duration", lasted), zap.Int("peak", a.peak))
+		if id != 0 {
+			text := fmt.Sprintf("Alert storm: up to %d alerts in %s for %s", a.peak, a.cfg.StormWindow, lasted)
+			if err := a.end(ctx, id, text, now); err != nil {
+				a.log.Warn("Failed to close alert storm annotation", zap.Error(err))
+			}
+		}
+		a.storm, a.peak = time.Time{}, 0
+		return 0
+	}
+	return id
+}
+
+type annotation struct {
+	Time         int64    `json:"time,omitempty"`
+	TimeEnd      int64    `json:"timeEnd,omitempty"`
+	Tags         []string `json:"tags,omitempty"`
+	Text         string   `json:"text"`
+	DashboardUID string   `json:"dashboardUID,omitempty"`
+}
+
+// Create an annotation, returning its ID
+func (a *Annotator) post(ctx context.Context, kind, text string, at time.Time) (int64, error) {
+	tags := slices.Concat([]string{baseTag, kind}, a.cfg.Tags)
+	var created struct {
+		ID int64 `json:"id"`
+	}
+	err := a.call(ctx, http.MethodPost, "/api/annotations", annotation{
+		Time:         at.UnixMilli(),
+		Tags:         tags,
+		Text:         text,
+		DashboardUID: a.cfg.DashboardUID,
+	}, &created)
+	return created.ID, err
+}
+
+// Turn an annotation into a region ending at end
+func (a *Annotator) end(ctx context.Context, id int64, text string, end time.Time) error {
+	return a.call(ctx, http.MethodPatch, fmt.Sprintf("/api/annotations/%d", id), annotation{TimeEnd: end.UnixMilli(), Text: text}, nil)
+}
+
+func (a *Annotator) call(ctx context.Context, method, path string, body annotation, out any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(a.cfg.Grafana, "/")+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if a.cfg.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+a.cfg.Token)
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("grafana returned status %d", resp.StatusCode)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
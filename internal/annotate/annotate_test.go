@@ -0,0 +1,141 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package annotate
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+type request struct {
+	method, path, auth string
+	body               annotation
+}
+
+func newGrafana(t *testing.T) (*httptest.Server, func() []request) {
+	t.Helper()
+	var mux sync.Mutex
+	var got []request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body annotation
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mux.Lock()
+		got = append(got, request{r.Method, r.URL.Path, r.Header.Get("Authorization"), body})
+		mux.Unlock()
+		_, _ = w.Write([]byte(`{"id":7,"message":"Annotation added"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []request {
+		mux.Lock()
+		defer mux.Unlock()
+		return slices.Clone(got)
+	}
+}
+
+func TestEvent(t *testing.T) {
+	server, requests := newGrafana(t)
+	a := New(config.AnnotationsConfig{Grafana: server.URL, Token: "glsa_x", DashboardUID: "abc", Tags: []string{"prod"}}, zap.NewNop())
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	a.now = func() time.Time { return now }
+
+	before := eventMetrics.Get(KindReconnect)
+	a.Event(context.Background(), KindReconnect, "Telegram client restarted")
+
+	deadline := time.Now().Add(time.Second)
+	for len(requests()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	got := requests()
+	if len(got) != 1 {
+		t.Fatalf("expected one annotation, got %+v", got)
+	}
+	r := got[0]
+	if r.method != http.MethodPost || r.path != "/api/annotations" || r.auth != "Bearer glsa_x" {
+		t.Errorf("unexpected request %+v", r)
+	}
+	if r.body.Time != now.UnixMilli() || r.body.DashboardUID != "abc" || !slices.Equal(r.body.Tags, []string{"telegram-scout", "reconnect", "prod"}) {
+		t.Errorf("unexpected annotation %+v", r.body)
+	}
+	if before == nil && eventMetrics.Get(KindReconnect) == nil {
+		t.Error("expected the event counted")
+	}
+}
+
+func TestStorm(t *testing.T) {
+	server, requests := newGrafana(t)
+	a := New(config.AnnotationsConfig{Grafana: server.URL, StormThreshold: 3, StormWindow: time.Minute}, zap.NewNop())
+	now := time.Date(2026, 1, 2, 3, 0, 0, 0, time.UTC)
+	a.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	alert := func(n int) {
+		for range n {
+			a.Alert()
+		}
+	}
+
+	// Below the threshold nothing happens
+	alert(2)
+	if id := a.checkStorm(ctx, 0); id != 0 || len(requests()) != 0 {
+		t.Fatalf("expected no storm, got id %d and %+v", id, requests())
+	}
+
+	now = now.Add(time.Minute)
+	alert(5)
+	id := a.checkStorm(ctx, 0)
+	if id != 7 {
+		t.Fatalf("expected the storm annotation ID, got %d", id)
+	}
+
+	now = now.Add(time.Minute)
+	alert(9)
+	if got := a.checkStorm(ctx, id); got != id || len(requests()) != 1 {
+		t.Errorf("expected the running storm kept without new annotations, got %d and %+v", got, requests())
+	}
+
+	now = now.Add(time.Minute)
+	if got := a.checkStorm(ctx, id); got != 0 {
+		t.Errorf("expected the storm to end, got %d", got)
+	}
+
+	got := requests()
+	if len(got) != 2 {
+		t.Fatalf("expected a start and an end request, got %+v", got)
+	}
+	start := time.Date(2026, 1, 2, 3, 0, 0, 0, time.UTC)
+	if got[0].body.Time != start.UnixMilli() || !slices.Contains(got[0].body.Tags, KindStorm) {
+		t.Errorf("unexpected storm start %+v", got[0].body)
+	}
+	if got[1].method != http.MethodPatch || got[1].path != "/api/annotations/7" || got[1].body.TimeEnd != now.UnixMilli() {
+		t.Errorf("unexpected storm end %+v", got[1])
+	}
+	if got[1].body.Text != "Alert storm: up to 9 alerts in 1m0s for 3m0s" {
+		t.Errorf("unexpected storm summary %q", got[1].body.Text)
+	}
+}
@@ -0,0 +1,135 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package archive keeps received messages and the matches among them, written
+// in batches off the message path.
+package archive
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// What a record describes
+const (
+	KindMessage  = "message"  // A received message
+	KindMatch    = "match"    // A message a rule matched
+	KindShadow   = "shadow"   // A message a shadow rule matched, without alerting
+	KindBackfill = "backfill" // A message from chat history a rule matched, without alerting
+	KindCount    = "count"    // A message a counter rule matched, kept for statistics
+)
+
+// A single archived message or match
+type Record struct {
+	Kind      string    `json:"kind"` // One of the Kind constants
+	Time      time.Time `json:"time"` // When it was archived
+	ChatID    int64     `json:"chat_id"`
+	ChatTitle string    `json:"chat_title,omitempty"`
+	MsgID     int       `json:"msg_id"`
+	SenderID  int64     `json:"sender_id,omitempty"`
+	Date      time.Time `json:"date"`
+	Text      string    `json:"text,omitempty"`
+	Media     string    `json:"media,omitempty"`
+	Link      string    `json:"link,omitempty"`
+	Rule      string    `json:"rule,omitempty"` // Matched rule, for KindMatch, KindShadow, KindBackfill and KindCount
+	Tags      []string  `json:"tags,omitempty"`
+}
+
+// Describe msg as a record of kind
+func FromMessage(kind string, msg model.Message) Record {
+	return Record{
+		Kind:      kind,
+		ChatID:    msg.ChatID,
+		ChatTitle: msg.ChatTitle,
+		MsgID:     msg.ID,
+		SenderID:  msg.SenderID,
+		Date:      msg.Date,
+		Text:      msg.Text,
+		Media:     msg.Media,
+		Link:      msg.Link,
+	}
+}
+
+// Persist batches of records
+type Backend interface {
+	Write(ctx context.Context, records []Record) error
+}
+
+// Append records to one JSON Lines file per day of the message date
+type Files struct {
+	mux sync.Mutex
+	dir string
+}
+
+// Create Files writing into dir, which is created on the first write
+func NewFiles(dir string) *Files {
+	return &Files{dir: dir}
+}
+
+func (f *Files) Write(ctx context.Context, records []Record) error {
+	days := make(map[string][]Record)
+	for _, r := range records {
+		day := cmp.Or(r.Date, r.Time).UTC().Format(time.DateOnly)
+		days[day] = append(days[day], r)
+	}
+
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	if err := os.MkdirAll(f.dir, 0o700); err != nil {
+		return err
+	}
+	for _, day := range slices.Sorted(maps.Keys(days)) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := f.append(filepath.Join(f.dir, day+".jsonl"), days[day]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *Files) append(path string, records []Record) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(file)
+	enc := json.NewEncoder(w)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			_ = file.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,490 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package archive
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+// Collect written records, failing while fail is set
+type memoryBackend struct {
+	mux     sync.Mutex
+	fail    bool
+	records []Record
+	batches int
+}
+
+func (m *memoryBackend) Write(ctx context.Context, records []Record) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if m.fail {
+		return errors.New("backend down")
+	}
+	m.records = append(m.records, records...)
+	m.batches++
+	return nil
+}
+
+func (m *memoryBackend) written() []Record {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	return m.records
+}
+
+func newWriter(t *testing.T, backend Backend, cfg config.ArchiveConfig) *Writer {
+	t.Helper()
+	if cfg.FlushInterval == 0 {
+		cfg.FlushInterval = time.Hour
+	}
+	if cfg.SpillMaxSize == 0 {
+		cfg.SpillMaxSize = 1 << 20
+	}
+	return NewWriter(backend, cfg, filepath.Join(t.TempDir(), "spill.jsonl"), zap.NewNop())
+}
+
+// Run w until every record so far is written, then stop it
+func runUntilClosed(w *Writer) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	w.Run(ctx)
+}
+
+func TestFiles(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "archive")
+	f := NewFiles(dir)
+	day := time.Date(2026, 3, 1, 23, 0, 0, 0, time.UTC)
+	err := f.Write(context.Background(), []Record{
+		{Kind: KindMessage, ChatID: 1, MsgID: 1, Date: day, Text: "first"},
+		{Kind: KindMatch, ChatID: 1, MsgID: 2, Date: day.Add(2 * time.Hour), Rule: "gpu"},
+		{Kind: KindMessage, ChatID: 1, MsgID: 3, Date: day, Text: "second"},
+	})
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	first, err := os.ReadFile(filepath.Join(dir, "2026-03-01.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(first)), "\n"); len(lines) != 2 || !strings.Contains(lines[1], `"text":"second"`) {
+		t.Errorf("expected both messages of the day in order, got %q", first)
+	}
+	second, err := os.ReadFile(filepath.Join(dir, "2026-03-02.jsonl"))
+	if err != nil || !strings.Contains(string(second), `"rule":"gpu"`) {
+		t.Errorf("expected the next day's match in its own file, got %q, %v", second, err)
+	}
+}
+
+func TestWriter_Batches(t *testing.T) {
+	backend := &memoryBackend{}
+	w := newWriter(t, backend, config.ArchiveConfig{Buffer: 10, BatchSize: 2})
+	for i := range 5 {
+		w.Add(Record{Kind: KindMessage, MsgID: i})
+	}
+	runUntilClosed(w)
+
+	got := backend.written()
+	if len(got) != 5 || backend.batches != 3 {
+		t.Fatalf("expected 5 records in 3 batches, got %d in %d", len(got), backend.batches)
+	}
+	for i, r := range got {
+		if r.MsgID != i || r.Time.IsZero() {
+			t.Errorf("unexpected record %d: %+v", i, r)
+		}
+	}
+
+	// Records added after closing are kept for the next run
+	w.Add(Record{Kind: KindMessage, MsgID: 5})
+	if _, err := os.Stat(w.spillPath); err != nil {
+		t.Errorf("expected a late record spilled, got %v", err)
+	}
+}
+
+func TestWriter_Spill(t *testing.T) {
+	backend := &memoryBackend{fail: true}
+	w := newWriter(t, backend, config.ArchiveConfig{Buffer: 1, BatchSize: 10})
+
+	// One fits in the buffer, the rest spill
+	for i := range 3 {
+		w.Add(Record{Kind: KindMessage, MsgID: i})
+	}
+	// The buffered one spills too when its batch fails
+	runUntilClosed(w)
+	if len(backend.written()) != 0 {
+		t.Fatal("expected nothing written while the backend fails")
+	}
+
+	backend.fail = false
+	restarted := NewWriter(backend, config.ArchiveConfig{Buffer: 1, BatchSize: 10, FlushInterval: time.Hour, SpillMaxSize: 1 << 20}, w.spillPath, zap.NewNop())
+	runUntilClosed(restarted)
+	if got := backend.written(); len(got) != 3 {
+		t.Fatalf("expected the spilled records written on the next run, got %+v", got)
+	}
+	if _, err := os.Stat(w.spillPath + ".replay"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected the replayed file removed, got %v", err)
+	}
+}
+
+func TestWriter_SpillLimit(t *testing.T) {
+	w := newWriter(t, &memoryBackend{}, config.ArchiveConfig{Buffer: 1, BatchSize: 10, SpillMaxSize: 200})
+	var before int64
+	if dropped, ok := archiveMetrics.Get("dropped").(*expvar.Int); ok {
+		before = dropped.Value()
+	}
+
+	for i := range 10 {
+		w.Add(Record{Kind: KindMessage, MsgID: i, Text: "a message long enough to fill the spill file"})
+	}
+	info, err := os.Stat(w.spillPath)
+	if err != nil || info.Size() > 200 {
+		t.Fatalf("expected the spill file capped, got %v, %v", info, err)
+	}
+	if dropped, ok := archiveMetrics.Get("dropped").(*expvar.Int); !ok || dropped.Value() <= before {
+		t.Error("expected dropped records counted")
+	}
+}
+
+func TestFiles_Compact(t *testing.T) {
+	dir := t.TempDir()
+	f := NewFiles(dir)
+	day := time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+	records := []Record{
+		{Kind: KindMessage, ChatID: -100, MsgID: 1, Date: day, Text: "a"},
+		{Kind: KindMatch, ChatID: -100, MsgID: 1, Date: day, Rule: "a"},
+		{Kind: KindMessage, ChatID: -100, MsgID: 2, Date: day, Text: "b"},
+		{Kind: KindMessage, ChatID: -200, MsgID: 1, Date: day.AddDate(0, 0, 1), Text: "c"},
+	}
+	if err := f.Write(context.Background(), records); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	// A restart archives the first two again, and a crash leaves a broken line
+	if err := f.Write(context.Background(), records[:2]); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	path := filepath.Join(dir, "2025-01-02.jsonl")
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		t.Fatalf("failed to open day file: %v", err)
+	}
+	_, _ = file.WriteString(`{"kind":"mess`)
+	_ = file.Close()
+
+	before, err := f.Stats(context.Background())
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+	if before.Files != 2 || before.Records != 4 || before.Duplicates != 2 || before.Invalid != 1 || before.Oldest != "2025-01-02" || before.Newest != "2025-01-03" {
+		t.Errorf("unexpected stats %+v", before)
+	}
+
+	after, err := f.Compact(context.Background())
+	if err != nil {
+		t.Fatalf("Compact() error = %v", err)
+	}
+	if after.Records != 4 || after.Duplicates != 2 || after.Reclaimed <= 0 || after.Bytes != before.Bytes-after.Reclaimed {
+		t.Errorf("unexpected compaction %+v", after)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read day file: %v", err)
+	}
+	if n := strings.Count(string(data), "\n"); n != 3 {
+		t.Errorf("expected 3 records left, got %d:\n%s", n, data)
+	}
+
+	again, err := f.Stats(context.Background())
+	if err != nil || again.Duplicates != 0 || again.Invalid != 0 || again.Bytes != after.Bytes {
+		t.Errorf("expected a clean archive after compacting, got %+v, %v", again, err)
+	}
+	if matches, _ := filepath.Glob(filepath.Join(dir, "*.jsonl.*")); len(matches) != 0 {
+		t.Errorf("expected no temporary files left, got %v", matches)
+	}
+}
+
+func TestFiles_QueryPrune(t *testing.T) {
+	f := NewFiles(t.TempDir())
+	day := time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+	err := f.Write(context.Background(), []Record{
+		{Kind: KindMessage, ChatID: -100, MsgID: 1, Date: day, Text: "Cheap GPU"},
+		{Kind: KindMatch, ChatID: -100, MsgID: 1, Date: day, Text: "Cheap GPU", Rule: "gpu"},
+		{Kind: KindMessage, ChatID: -200, MsgID: 2, Date: day.Add(time.Hour), Text: "hello"},
+		{Kind: KindMessage, ChatID: -100, MsgID: 3, Date: day.AddDate(0, 0, 1), Text: "gpu again"},
+	})
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	ids := func(q Query) []string {
+		t.Helper()
+		records, err := f.Query(context.Background(), q)
+		if err != nil {
+			t.Fatalf("Query() error = %v", err)
+		}
+		var out []string
+		for _, r := range records {
+			out = append(out, fmt.Sprintf("%s:%d", r.Kind, r.MsgID))
+		}
+		return out
+	}
+	tests := []struct {
+		name string
+		q    Query
+		want []string
+	}{
+		{"everything newest first", Query{}, []string{"message:3", "message:2", "match:1", "message:1"}},
+		{"text ignoring case", Query{Kind: KindMessage, Text: "gpu"}, []string{"message:3", "message:1"}},
+		{"chat and limit", Query{ChatID: -100, Limit: 2}, []string{"message:3", "match:1"}},
+		{"rule", Query{Rule: "gpu"}, []string{"match:1"}},
+		{"date range", Query{Since: day.Add(30 * time.Minute), Until: day.Add(2 * time.Hour)}, []string{"message:2"}},
+	}
+	for _, tt := range tests {
+		if got := ids(tt.q); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	removed, err := f.Prune(context.Background(), day.AddDate(0, 0, 1).Add(time.Hour))
+	if err != nil || removed != 3 {
+		t.Fatalf("Prune() = %d, %v, want 3 records of the first day", removed, err)
+	}
+	if got := ids(Query{}); !slices.Equal(got, []string{"message:3"}) {
+		t.Errorf("expected only the last day left, got %v", got)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	f := NewFiles(t.TempDir())
+	now := time.Date(2025, 1, 8, 12, 0, 0, 0, time.UTC)
+	var records []Record
+	add := func(n int, rule string, chatID int64, title string, age time.Duration) {
+		for i := range n {
+			records = append(records, Record{Kind: KindMatch, ChatID: chatID, ChatTitle: title, MsgID: len(records) + i, Date: now.Add(-age), Rule: rule})
+		}
+	}
+	add(2, "gpu", -100, "Old title", 3*24*time.Hour)
+	add(6, "gpu", -100, "Deals", time.Hour)
+	add(6, "ram", -200, "Parts", 2*24*time.Hour)
+	add(1, "ram", -200, "Parts", 2*time.Hour)
+	add(3, "ssd", -300, "Storage", 10*24*time.Hour) // Older than a week
+	records = append(records, Record{Kind: KindMessage, ChatID: -100, MsgID: 99, Date: now.Add(-time.Hour)})
+	if err := f.Write(context.Background(), records); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	report, err := Summarize(context.Background(), f, now, 1)
+	if err != nil {
+		t.Fatalf("Summarize() error = %v", err)
+	}
+	if report.Day != 7 || report.Week != 15 {
+		t.Errorf("got %d alerts in a day and %d in a week, want 7 and 15", report.Day, report.Week)
+	}
+	if want := []Count{{Name: "gpu", Day: 6, Week: 8}}; !slices.Equal(report.TopRules, want) {
+		t.Errorf("TopRules = %+v, want %+v", report.TopRules, want)
+	}
+	if want := []Count{{Name: "Deals", ChatID: -100, Day: 6, Week: 8}}; !slices.Equal(report.TopChats, want) {
+		t.Errorf("TopChats = %+v, want %+v", report.TopChats, want)
+	}
+	// ram fired more over the week, but too few times in the last day to spike
+	if len(report.Spikes) != 1 || report.Spikes[0].Rule != "gpu" || report.Spikes[0].Day != 6 {
+		t.Errorf("expected only gpu spiking, got %+v", report.Spikes)
+	}
+}
+
+func TestApplyMigrations(t *testing.T) {
+	var ran []int
+	step := func(v int, fail bool) Migration {
+		return Migration{Version: v, Name: fmt.Sprintf("step %d", v), Up: func(ctx context.Context) error {
+			if fail {
+				return errors.New("boom")
+			}
+			ran = append(ran, v)
+			return nil
+		}}
+	}
+	var recorded []int
+	applied := func(v int) error {
+		recorded = append(recorded, v)
+		return nil
+	}
+
+	// Out of order migrations run in version order, from the current version
+	version, err := ApplyMigrations(context.Background(), []Migration{step(3, false), step(1, false), step(2, false)}, 1, applied)
+	if err != nil || version != 3 || !slices.Equal(ran, []int{2, 3}) || !slices.Equal(recorded, []int{2, 3}) {
+		t.Errorf("got version %d, ran %v, recorded %v, err %v", version, ran, recorded, err)
+	}
+
+	ran, recorded = nil, nil
+	version, err = ApplyMigrations(context.Background(), []Migration{step(1, false), step(2, true), step(3, false)}, 0, applied)
+	if err == nil || version != 1 || !slices.Equal(recorded, []int{1}) {
+		t.Errorf("expected to stop at the failed migration, got version %d, recorded %v, err %v", version, recorded, err)
+	}
+
+	if _, err := ApplyMigrations(context.Background(), []Migration{step(1, false), step(1, false)}, 0, applied); err == nil {
+		t.Error("expected duplicate versions rejected")
+	}
+	if _, err := ApplyMigrations(context.Background(), []Migration{step(1, false)}, 2, applied); err == nil {
+		t.Error("expected a schema newer than the build rejected")
+	}
+}
+
+func TestFiles_Migrate(t *testing.T) {
+	dir := t.TempDir()
+	f := NewFiles(dir)
+	r := Record{Kind: KindMessage, ChatID: -100, MsgID: 1, Date: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)}
+	for range 2 {
+		if err := f.Write(context.Background(), []Record{r}); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+
+	for range 2 {
+		if err := f.Migrate(context.Background()); err != nil {
+			t.Fatalf("Migrate() error = %v", err)
+		}
+	}
+	data, err := os.ReadFile(filepath.Join(dir, schemaFile))
+	if err != nil || strings.TrimSpace(string(data)) != `{"version":1}` {
+		t.Errorf("expected schema version 1 recorded, got %s, %v", data, err)
+	}
+	if stats, err := f.Stats(context.Background()); err != nil || stats.Records != 1 || stats.Duplicates != 0 {
+		t.Errorf("expected duplicates removed by the first migration, got %+v, %v", stats, err)
+	}
+}
+
+// Answer the ClickHouse HTTP interface, recording the statements it receives
+type fakeClickHouse struct {
+	mux        sync.Mutex
+	statements []string
+	inserted   []string
+	version    int
+}
+
+func (f *fakeClickHouse) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	if r.Header.Get("X-ClickHouse-User") != "scout" || r.Header.Get("X-ClickHouse-Key") != "secret" {
+		http.Error(w, "Authentication failed", http.StatusUnauthorized)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	sql := r.URL.Query().Get("query")
+	if sql == "" {
+		sql = string(body)
+	}
+	f.statements = append(f.statements, sql)
+
+	switch {
+	case strings.HasPrefix(sql, "INSERT INTO db.archive FORMAT JSONEachRow"):
+		if r.URL.Query().Get("async_insert") != "1" {
+			http.Error(w, "expected an async insert", http.StatusBadRequest)
+			return
+		}
+		f.inserted = append(f.inserted, strings.Split(strings.TrimSpace(string(body)), "\n")...)
+	case strings.HasPrefix(sql, "SELECT max(version)"):
+		_, _ = fmt.Fprintln(w, f.version)
+	case strings.HasPrefix(sql, "INSERT INTO db.archive_migrations"):
+		f.version, _ = strconv.Atoi(r.URL.Query().Get("param_version"))
+	case strings.HasPrefix(sql, "SELECT count()"):
+		_, _ = fmt.Fprintln(w, 2)
+	case strings.HasPrefix(sql, "SELECT kind"):
+		if r.URL.Query().Get("param_rule") != "gpu" {
+			http.Error(w, "expected the rule parameter", http.StatusBadRequest)
+			return
+		}
+		_, _ = fmt.Fprintln(w, `{"kind":"match","time":"2025-01-02T12:00:01.000Z","chat_id":-1001234567890,"chat_title":"Deals","msg_id":7,"sender_id":0,"date":"2025-01-02T12:00:00.000Z","text":"gpu","media":"","link":"","rule":"gpu","tags":["hw"]}`)
+	}
+}
+
+func TestClickHouse(t *testing.T) {
+	fake := &fakeClickHouse{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	ch := NewClickHouse(config.ClickHouseConfig{URL: server.URL, Database: "db", Table: "archive", User: "scout", Password: "secret"})
+	ctx := context.Background()
+
+	for range 2 {
+		if err := ch.Migrate(ctx); err != nil {
+			t.Fatalf("Migrate() error = %v", err)
+		}
+	}
+	creates := 0
+	for _, sql := range fake.statements {
+		if strings.HasPrefix(sql, "CREATE TABLE IF NOT EXISTS db.archive (") {
+			creates++
+		}
+	}
+	if creates != 1 || fake.version != 1 {
+		t.Errorf("expected the table created once and version 1 recorded, got %d creates and version %d", creates, fake.version)
+	}
+
+	date := time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+	err := ch.Write(ctx, []Record{
+		{Kind: KindMessage, ChatID: -1001234567890, MsgID: 7, Date: date, Text: "gpu"},
+		{Kind: KindMatch, ChatID: -1001234567890, MsgID: 7, Date: date, Text: "gpu", Rule: "gpu", Tags: []string{"hw"}},
+	})
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if len(fake.inserted) != 2 || !strings.Contains(fake.inserted[1], `"rule":"gpu"`) {
+		t.Errorf("expected 2 JSON rows inserted, got %q", fake.inserted)
+	}
+
+	records, err := ch.Query(ctx, Query{Rule: "gpu", Since: date.Add(-time.Hour), Limit: 5})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(records) != 1 || records[0].ChatID != -1001234567890 || !records[0].Date.Equal(date) || !slices.Equal(records[0].Tags, []string{"hw"}) {
+		t.Errorf("unexpected records %+v", records)
+	}
+	last := fake.statements[len(fake.statements)-1]
+	if !strings.Contains(last, "FINAL WHERE rule = {rule:String} AND date >= {since:DateTime64(3, 'UTC')} ORDER BY date DESC LIMIT 5") {
+		t.Errorf("unexpected query %q", last)
+	}
+
+	removed, err := ch.Prune(ctx, date)
+	if err != nil || removed != 2 {
+		t.Errorf("Prune() = %d, %v, want 2", removed, err)
+	}
+	if last := fake.statements[len(fake.statements)-1]; !strings.HasPrefix(last, "ALTER TABLE db.archive DELETE WHERE date <") {
+		t.Errorf("expected a delete mutation, got %q", last)
+	}
+
+	bad := NewClickHouse(config.ClickHouseConfig{URL: server.URL, Database: "db", Table: "archive"})
+	if err := bad.Write(ctx, []Record{{Kind: KindMessage}}); err == nil || !strings.Contains(err.Error(), "status 401") {
+		t.Errorf("expected the server error reported, got %v", err)
+	}
+}
@@ -0,0 +1,254 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+// Longest a single ClickHouse request may take
+const clickHouseTimeout = time.Minute
+
+// Format of DateTime64 query parameters
+const clickHouseTime = "2006-01-02 15:04:05.000"
+
+// Keep records in a ClickHouse table through its HTTP interface. Inserts are
+// async, so the server merges the batches of every writer into larger parts.
+// The table deduplicates records of the same chat, message, kind and rule
+// in the background, so Query reads it with FINAL.
+type ClickHouse struct {
+	cfg    config.ClickHouseConfig
+	client *http.Client
+	table  string // Qualified with the database
+}
+
+// Create a ClickHouse store for the configured table, created by Migrate
+func NewClickHouse(cfg config.ClickHouseConfig) *ClickHouse {
+	return &ClickHouse{
+		cfg:    cfg,
+		client: &http.Client{Timeout: clickHouseTimeout},
+		table:  cfg.Database + "." + cfg.Table,
+	}
+}
+
+var _ Store = (*ClickHouse)(nil)
+
+func (c *ClickHouse) Write(ctx context.Context, records []Record) error {
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+
+	wait := "1"
+	if c.cfg.NoWait {
+		wait = "0"
+	}
+	settings := url.Values{
+		"query":                  {"INSERT INTO " + c.table + " FORMAT JSONEachRow"},
+		"async_insert":           {"1"},
+		"wait_for_async_insert":  {wait},
+		"date_time_input_format": {"best_effort"},
+	}
+	_, err := c.do(ctx, settings, &body)
+	return err
+}
+
+func (c *ClickHouse) Query(ctx context.Context, q Query) ([]Record, error) {
+	params := url.Values{
+		"date_time_output_format":                 {"iso"},
+		"output_format_json_quote_64bit_integers": {"0"},
+	}
+	var where []string
+	add := func(cond, name, value string) {
+		where = append(where, cond)
+		params.Set("param_"+name, value)
+	}
+	if q.Kind != "" {
+		add("kind = {kind:String}", "kind", q.Kind)
+	}
+	if q.ChatID != 0 {
+		add("chat_id = {chat:Int64}", "chat", strconv.FormatInt(q.ChatID, 10))
+	}
+	if q.Rule != "" {
+		add("rule = {rule:String}", "rule", q.Rule)
+	}
+	if q.Text != "" {
+		add("positionCaseInsensitiveUTF8(text, {text:String}) > 0", "text", q.Text)
+	}
+	if !q.Since.IsZero() {
+		add("date >= {since:DateTime64(3, 'UTC')}", "since", q.Since.UTC().Format(clickHouseTime))
+	}
+	if !q.Until.IsZero() {
+		add("date <= {until:DateTime64(3, 'UTC')}", "until", q.Until.UTC().Format(clickHouseTime))
+	}
+
+	sql := "SELECT kind, time, chat_id, chat_title, msg_id, sender_id, date, text, media, link, rule, tags FROM " + c.table + " FINAL"
+	if len(where) > 0 {
+		sql += " WHERE " + strings.Join(where, " AND ")
+	}
+	sql += " ORDER BY date DESC"
+	if q.Limit > 0 {
+		sql += " LIMIT " + strconv.Itoa(q.Limit)
+	}
+	body, err := c.do(ctx, params, strings.NewReader(sql+" FORMAT JSONEachRow"))
+	if err != nil {
+		return nil, err
+	}
+
+	var out []Record
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return nil, fmt.Errorf("unexpected clickhouse row: %w", err)
+		}
+		out = append(out, r)
+	}
+	return out, scanner.Err()
+}
+
+// Deletes run synchronously, so the records are gone once Prune returns
+func (c *ClickHouse) Prune(ctx context.Context, before time.Time) (int, error) {
+	params := url.Values{"param_before": {before.UTC().Format(clickHouseTime)}}
+	cond := " WHERE date < {before:DateTime64(3, 'UTC')}"
+
+	body, err := c.do(ctx, params, strings.NewReader("SELECT count() FROM "+c.table+cond+" FORMAT TabSeparated"))
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(string(body)))
+	if err != nil {
+		return 0, fmt.Errorf("unexpected clickhouse count %q", body)
+	}
+	if n == 0 {
+		return 0, nil
+	}
+
+	params.Set("mutations_sync", "1")
+	if _, err := c.do(ctx, params, strings.NewReader("ALTER TABLE "+c.table+" DELETE"+cond)); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// Migrations of the archive table, recorded in a table of their own
+func (c *ClickHouse) migrations() []Migration {
+	return []Migration{
+		{Version: 1, Name: "create archive table", Up: c.exec(`CREATE TABLE IF NOT EXISTS ` + c.table + ` (
+	kind LowCardinality(String),
+	time DateTime64(3, 'UTC'),
+	chat_id Int64,
+	chat_title String,
+	msg_id Int32,
+	sender_id Int64,
+	date DateTime64(3, 'UTC'),
+	text String,
+	media LowCardinality(String),
+	link String,
+	rule String,
+	tags Array(String)
+) ENGINE = ReplacingMergeTree
+PARTITION BY toYYYYMM(date)
+ORDER BY (chat_id, msg_id, kind, rule)`)},
+	}
+}
+
+func (c *ClickHouse) Migrate(ctx context.Context) error {
+	applied := c.table + "_migrations"
+	if _, err := c.do(ctx, nil, strings.NewReader("CREATE TABLE IF NOT EXISTS "+applied+
+		" (version UInt32, name String, applied DateTime DEFAULT now()) ENGINE = MergeTree ORDER BY version")); err != nil {
+		return err
+	}
+	body, err := c.do(ctx, nil, strings.NewReader("SELECT max(version) FROM "+applied+" FORMAT TabSeparated"))
+	if err != nil {
+		return err
+	}
+	current, err := strconv.Atoi(strings.TrimSpace(string(body)))
+	if err != nil {
+		return fmt.Errorf("unexpected clickhouse schema version %q", body)
+	}
+
+	migrations := c.migrations()
+	_, err = ApplyMigrations(ctx, migrations, current, func(version int) error {
+		var name string
+		for _, m := range migrations {
+			if m.Version == version {
+				name = m.Name
+			}
+		}
+		params := url.Values{"param_version": {strconv.Itoa(version)}, "param_name": {name}}
+		_, err := c.do(ctx, params, strings.NewReader("INSERT INTO "+applied+" (version, name) SELECT {version:UInt32}, {name:String}"))
+		return err
+	})
+	return err
+}
+
+// Return a migration step running sql
+func (c *ClickHouse) exec(sql string) func(context.Context) error {
+	return func(ctx context.Context) error {
+		_, err := c.do(ctx, nil, strings.NewReader(sql))
+		return err
+	}
+}
+
+// POST body to the HTTP interface with params, returning the response body
+func (c *ClickHouse) do(ctx context.Context, params url.Values, body io.Reader) ([]byte, error) {
+	u := strings.TrimSuffix(c.cfg.URL, "/") + "/"
+	if len(params) > 0 {
+		u += "?" + params.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, body)
+	if err != nil {
+		return nil, err
+	}
+	if c.cfg.User != "" {
+		req.Header.Set("X-ClickHouse-User", c.cfg.User)
+		req.Header.Set("X-ClickHouse-Key", c.cfg.Password)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach clickhouse: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("clickhouse returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(data[:min(len(data), 512)])))
+	}
+	return data, nil
+}
@@ -0,0 +1,193 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package archive
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Size of the archive, as reported by Stats and Compact
+type Stats struct {
+	Files   int    `json:"files"`
+	Records int    `json:"records"` // Left after removing duplicates
+	Bytes   int64  `json:"bytes"`
+	Oldest  string `json:"oldest,omitempty"` // Day of the oldest file
+	Newest  string `json:"newest,omitempty"` // Day of the newest file
+
+	// Found by Stats, removed by Compact
+	Duplicates int `json:"duplicates"` // Records archived again, e.g. by a restart replaying spilled records
+	Invalid    int `json:"invalid"`    // Lines cut short by a crash
+
+	Reclaimed int64 `json:"reclaimed,omitempty"` // Bytes freed by Compact
+}
+
+// Identify a record, so the same message is only kept once per kind and rule
+type recordKey struct {
+	kind   string
+	chatID int64
+	msgID  int
+	rule   string
+}
+
+// Report the size of the archive and how much Compact would remove
+func (f *Files) Stats(ctx context.Context) (Stats, error) {
+	return f.scan(ctx, false)
+}
+
+// Rewrite the day files holding duplicate records or broken lines without
+// them. Writes wait until compaction is done.
+func (f *Files) Compact(ctx context.Context) (Stats, error) {
+	return f.scan(ctx, true)
+}
+
+// Compact every interval until ctx is cancelled
+func (f *Files) RunCompaction(ctx context.Context, interval time.Duration, log *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		stats, err := f.Compact(ctx)
+		if err != nil {
+			log.Error("Failed to compact archive", zap.Error(err))
+			continue
+		}
+		if stats.Duplicates > 0 || stats.Invalid > 0 {
+			log.Info("Compacted archive", zap.Int("duplicates", stats.Duplicates), zap.Int("invalid", stats.Invalid), zap.Int64("reclaimed", stats.Reclaimed))
+		}
+	}
+}
+
+func (f *Files) scan(ctx context.Context, rewrite bool) (Stats, error) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+
+	var stats Stats
+	days, err := f.days()
+	if err != nil {
+		return stats, err
+	}
+	for _, d := range days {
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+		day, err := scanDay(f.dayPath(d), rewrite)
+		if err != nil {
+			return stats, err
+		}
+		stats.Files++
+		stats.Records += day.Records
+		stats.Bytes += day.Bytes
+		stats.Duplicates += day.Duplicates
+		stats.Invalid += day.Invalid
+		stats.Reclaimed += day.Reclaimed
+	}
+	if len(days) > 0 {
+		stats.Oldest = days[0]
+		stats.Newest = days[len(days)-1]
+	}
+	return stats, nil
+}
+
+// Count the records of one day file, rewriting it without duplicates and
+// broken lines when rewrite is set and it has any
+func scanDay(path string, rewrite bool) (Stats, error) {
+	var stats Stats
+	in, err := os.Open(path)
+	if err != nil {
+		return stats, err
+	}
+	defer func() { _ = in.Close() }()
+	info, err := in.Stat()
+	if err != nil {
+		return stats, err
+	}
+	stats.Bytes = info.Size()
+
+	// Kept lines go to a temporary file that replaces the day once done
+	var out *os.File
+	var w *bufio.Writer
+	if rewrite {
+		out, err = os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+		if err != nil {
+			return stats, err
+		}
+		defer func() {
+			_ = out.Close()
+			_ = os.Remove(out.Name())
+		}()
+		w = bufio.NewWriter(out)
+	}
+
+	seen := make(map[recordKey]bool)
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		var r Record
+		if err := json.Unmarshal(line, &r); err != nil {
+			stats.Invalid++
+			continue
+		}
+		key := recordKey{kind: r.Kind, chatID: r.ChatID, msgID: r.MsgID, rule: r.Rule}
+		if seen[key] {
+			stats.Duplicates++
+			continue
+		}
+		seen[key] = true
+		stats.Records++
+		if w != nil {
+			_, _ = w.Write(line)
+			_ = w.WriteByte('\n')
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return stats, err
+	}
+	if !rewrite || stats.Duplicates+stats.Invalid == 0 {
+		return stats, nil
+	}
+
+	if err := w.Flush(); err != nil {
+		return stats, err
+	}
+	if err := out.Close(); err != nil {
+		return stats, err
+	}
+	info, err = os.Stat(out.Name())
+	if err != nil {
+		return stats, err
+	}
+	if err := os.Rename(out.Name(), path); err != nil {
+		return stats, err
+	}
+	stats.Reclaimed = stats.Bytes - info.Size()
+	stats.Bytes = info.Size()
+	return stats, nil
+}
@@ -0,0 +1,118 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package archive
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/h3nc4/TelegramScout/internal/atomicfile"
+)
+
+// A schema change of a Store, applied once and in Version order. Versions
+// are never reused, so a migration that shipped is never edited, only
+// followed by another.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(ctx context.Context) error
+}
+
+// Apply the migrations above version current in Version order, calling
+// applied after each so a failure part way resumes with the one that failed.
+// Returns the version reached.
+func ApplyMigrations(ctx context.Context, migrations []Migration, current int, applied func(version int) error) (int, error) {
+	migrations = slices.SortedFunc(slices.Values(migrations), func(a, b Migration) int { return cmp.Compare(a.Version, b.Version) })
+	for i, m := range migrations {
+		if i > 0 && m.Version == migrations[i-1].Version {
+			return current, fmt.Errorf("migrations %q and %q share version %d", migrations[i-1].Name, m.Name, m.Version)
+		}
+	}
+	if n := len(migrations); n > 0 && current > migrations[n-1].Version {
+		return current, fmt.Errorf("schema version %d is newer than this build knows (%d)", current, migrations[n-1].Version)
+	}
+
+	for _, m := range migrations {
+		if m.Version <= current {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return current, err
+		}
+		if err := m.Up(ctx); err != nil {
+			return current, fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Name, err)
+		}
+		if err := applied(m.Version); err != nil {
+			return current, err
+		}
+		current = m.Version
+	}
+	return current, nil
+}
+
+// Records the schema version of the day files
+const schemaFile = "schema.json"
+
+type filesSchema struct {
+	Version int `json:"version"`
+}
+
+// Migrations of the day files
+func (f *Files) migrations() []Migration {
+	return []Migration{
+		{Version: 1, Name: "remove duplicate records", Up: func(ctx context.Context) error {
+			_, err := f.Compact(ctx)
+			return err
+		}},
+	}
+}
+
+func (f *Files) Migrate(ctx context.Context) error {
+	path := filepath.Join(f.dir, schemaFile)
+	var schema filesSchema
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return err
+	default:
+		if err := json.Unmarshal(data, &schema); err != nil {
+			return fmt.Errorf("invalid %s: %w", path, err)
+		}
+	}
+
+	_, err = ApplyMigrations(ctx, f.migrations(), schema.Version, func(version int) error {
+		if err := os.MkdirAll(f.dir, 0o700); err != nil {
+			return err
+		}
+		data, err := json.Marshal(filesSchema{Version: version})
+		if err != nil {
+			return err
+		}
+		return atomicfile.Write(path, data)
+	})
+	return err
+}
@@ -0,0 +1,132 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package archive
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+)
+
+// A rule is spiking when it fired at least this many times in the last day,
+// and spikeFactor times its daily average over the six days before
+const (
+	spikeMinimum = 5
+	spikeFactor  = 3
+)
+
+// Alerts archived over the last day and week, as sent in the scheduled summary
+type Report struct {
+	Generated time.Time `json:"generated"`
+	Day       int       `json:"day"`  // Alerts in the last 24 hours
+	Week      int       `json:"week"` // Alerts in the last 7 days
+
+	TopRules []Count `json:"top_rules"` // Most alerted rules of the last day
+	TopChats []Count `json:"top_chats"` // Chats with the most alerts in the last day
+	Spikes   []Spike `json:"spikes"`
+}
+
+// Alerts of one rule or chat
+type Count struct {
+	Name   string `json:"name"` // Rule name or chat title
+	ChatID int64  `json:"chat_id,omitempty"`
+	Day    int    `json:"day"`
+	Week   int    `json:"week"`
+}
+
+// A rule alerting far more in the last day than it used to
+type Spike struct {
+	Rule    string  `json:"rule"`
+	Day     int     `json:"day"`
+	Average float64 `json:"average"` // Alerts per day over the six days before
+}
+
+// Summarize the alerts archived in store over the week up to now, listing
+// at most top rules and chats
+func Summarize(ctx context.Context, store Store, now time.Time, top int) (Report, error) {
+	report := Report{Generated: now}
+	records, err := store.Query(ctx, Query{Kind: KindMatch, Since: now.Add(-7 * 24 * time.Hour), Until: now})
+	if err != nil {
+		return report, err
+	}
+
+	dayStart := now.Add(-24 * time.Hour)
+	rules := make(map[string]*Count)
+	chats := make(map[int64]*Count)
+	for _, r := range records {
+		rule := rules[r.Rule]
+		if rule == nil {
+			rule = &Count{Name: r.Rule}
+			rules[r.Rule] = rule
+		}
+		chat := chats[r.ChatID]
+		if chat == nil {
+			chat = &Count{ChatID: r.ChatID}
+			chats[r.ChatID] = chat
+		}
+		// Newest first, so the first title seen is the current one
+		if chat.Name == "" {
+			chat.Name = r.ChatTitle
+		}
+
+		report.Week++
+		rule.Week++
+		chat.Week++
+		if !r.Date.Before(dayStart) {
+			report.Day++
+			rule.Day++
+			chat.Day++
+		}
+	}
+
+	for _, rule := range rules {
+		if rule.Day < spikeMinimum {
+			continue
+		}
+		average := float64(rule.Week-rule.Day) / 6
+		if float64(rule.Day) >= spikeFactor*average {
+			report.Spikes = append(report.Spikes, Spike{Rule: rule.Name, Day: rule.Day, Average: average})
+		}
+	}
+	slices.SortFunc(report.Spikes, func(a, b Spike) int {
+		return cmp.Or(cmp.Compare(b.Day, a.Day), cmp.Compare(a.Rule, b.Rule))
+	})
+
+	report.TopRules = topCounts(rules, top)
+	report.TopChats = topCounts(chats, top)
+	return report, nil
+}
+
+// Return at most n counts with alerts in the last day, the most alerted first
+func topCounts[K comparable](counts map[K]*Count, n int) []Count {
+	var out []Count
+	for _, c := range counts {
+		if c.Day > 0 {
+			out = append(out, *c)
+		}
+	}
+	slices.SortFunc(out, func(a, b Count) int {
+		return cmp.Or(cmp.Compare(b.Day, a.Day), cmp.Compare(b.Week, a.Week), cmp.Compare(a.Name, b.Name), cmp.Compare(a.ChatID, b.ChatID))
+	})
+	if len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
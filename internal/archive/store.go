@@ -0,0 +1,188 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package archive
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Keep archived records, answer queries over them and evolve their schema.
+// Messages and alerts are saved through Write as KindMessage and KindMatch
+// records. A new backend implements Store and lists its own migrations.
+type Store interface {
+	Backend
+
+	// Return the records q selects, newest first
+	Query(ctx context.Context, q Query) ([]Record, error)
+	// Remove the records of messages posted before, returning how many
+	Prune(ctx context.Context, before time.Time) (int, error)
+	// Bring the schema up to date, call before the first Write
+	Migrate(ctx context.Context) error
+}
+
+// Select archived records, every set field must hold
+type Query struct {
+	Kind   string    `json:"kind,omitempty"`
+	ChatID int64     `json:"chat_id,omitempty"`
+	Rule   string    `json:"rule,omitempty"`
+	Text   string    `json:"text,omitempty"`  // Case-insensitive substring of the message text
+	Since  time.Time `json:"since,omitzero"`  // Oldest message date
+	Until  time.Time `json:"until,omitzero"`  // Newest message date
+	Limit  int       `json:"limit,omitempty"` // Zero returns every record selected
+}
+
+func (q Query) matches(r Record) bool {
+	switch {
+	case q.Kind != "" && r.Kind != q.Kind:
+		return false
+	case q.ChatID != 0 && r.ChatID != q.ChatID:
+		return false
+	case q.Rule != "" && r.Rule != q.Rule:
+		return false
+	case !q.Since.IsZero() && r.Date.Before(q.Since):
+		return false
+	case !q.Until.IsZero() && r.Date.After(q.Until):
+		return false
+	}
+	return q.Text == "" || strings.Contains(strings.ToLower(r.Text), strings.ToLower(q.Text))
+}
+
+var _ Store = (*Files)(nil)
+
+// Records within a day are in the order they were archived, which is taken as newest last
+func (f *Files) Query(ctx context.Context, q Query) ([]Record, error) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+
+	days, err := f.days()
+	if err != nil {
+		return nil, err
+	}
+	var out []Record
+	for _, day := range slices.Backward(days) {
+		if !q.Since.IsZero() && day < q.Since.UTC().Format(time.DateOnly) {
+			break
+		}
+		if !q.Until.IsZero() && day > q.Until.UTC().Format(time.DateOnly) {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		var selected []Record
+		err := readDay(f.dayPath(day), func(r Record) {
+			if q.matches(r) {
+				selected = append(selected, r)
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range slices.Backward(selected) {
+			if q.Limit > 0 && len(out) >= q.Limit {
+				return out, nil
+			}
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+// Whole days are removed, so records of the day of before are kept
+func (f *Files) Prune(ctx context.Context, before time.Time) (int, error) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+
+	days, err := f.days()
+	if err != nil {
+		return 0, err
+	}
+	cutoff := before.UTC().Format(time.DateOnly)
+	removed := 0
+	for _, day := range days {
+		if day >= cutoff {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return removed, err
+		}
+		n := 0
+		if err := readDay(f.dayPath(day), func(Record) { n++ }); err != nil {
+			return removed, err
+		}
+		if err := os.Remove(f.dayPath(day)); err != nil {
+			return removed, err
+		}
+		removed += n
+	}
+	return removed, nil
+}
+
+// Return the days with a file, oldest first
+func (f *Files) days() ([]string, error) {
+	entries, err := os.ReadDir(f.dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var days []string
+	for _, e := range entries {
+		if day, ok := strings.CutSuffix(e.Name(), ".jsonl"); ok && !e.IsDir() {
+			days = append(days, day)
+		}
+	}
+	slices.Sort(days)
+	return days, nil
+}
+
+func (f *Files) dayPath(day string) string {
+	return filepath.Join(f.dir, day+".jsonl")
+}
+
+// Call fn with each record of a day file, skipping lines cut short by a crash
+func readDay(path string, fn func(Record)) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			continue
+		}
+		fn(r)
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,293 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package archive
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+// Write buffer counters, served at /debug/vars by the admin server
+var archiveMetrics = expvar.NewMap("archive")
+
+// Records waiting in memory across writers
+var buffered = new(expvar.Int)
+
+func init() {
+	archiveMetrics.Set("buffered", buffered)
+}
+
+// Longest the final flush may take on shutdown
+const closeTimeout = 10 * time.Second
+
+// Batch records in a bounded buffer in front of a Backend. Records that do
+// not fit, or whose batch failed to write, are spilled to a file and written
+// once the backend keeps up again. Add never blocks.
+type Writer struct {
+	backend   Backend
+	log       *zap.Logger
+	queue     chan Record
+	batchSize int
+	interval  time.Duration
+
+	// Held for reading while queueing, so no record is queued after closing
+	mux    sync.RWMutex
+	closed bool
+
+	spillMux  sync.Mutex
+	spillPath string
+	spillMax  int64
+	spilled   int64 // Bytes in the spill file
+	dropping  bool  // The spill file is full, warned about once
+}
+
+// Create a Writer in front of backend, spilling to spillPath
+func NewWriter(backend Backend, cfg config.ArchiveConfig, spillPath string, log *zap.Logger) *Writer {
+	w := &Writer{
+		backend:   backend,
+		log:       log,
+		queue:     make(chan Record, cfg.Buffer),
+		batchSize: cfg.BatchSize,
+		interval:  cfg.FlushInterval,
+		spillPath: spillPath,
+		spillMax:  cfg.SpillMaxSize,
+	}
+	if info, err := os.Stat(spillPath); err == nil {
+		w.spilled = info.Size()
+	}
+	return w
+}
+
+// Queue r for writing, spilling it to disk when the buffer is full
+func (w *Writer) Add(r Record) {
+	if r.Time.IsZero() {
+		r.Time = time.Now().UTC()
+	}
+	w.mux.RLock()
+	if !w.closed {
+		select {
+		case w.queue <- r:
+			w.mux.RUnlock()
+			buffered.Add(1)
+			return
+		default:
+		}
+	}
+	w.mux.RUnlock()
+	w.spill(r)
+}
+
+// Write batches until ctx is cancelled, then flush what is left. Records
+// spilled by the last run are written first.
+func (w *Writer) Run(ctx context.Context) {
+	w.replay(ctx)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	batch := make([]Record, 0, w.batchSize)
+	for {
+		select {
+		case <-ctx.Done():
+			w.close(batch)
+			return
+		case r := <-w.queue:
+			buffered.Add(-1)
+			batch = append(batch, r)
+			if len(batch) >= w.batchSize {
+				batch = w.flush(ctx, batch)
+			}
+		case <-ticker.C:
+			batch = w.flush(ctx, batch)
+			// Catch up on spilled records once the buffer has drained
+			if len(w.queue) == 0 {
+				w.replay(ctx)
+			}
+		}
+	}
+}
+
+// Write batch, spilling it when the backend fails, and return it emptied
+func (w *Writer) flush(ctx context.Context, batch []Record) []Record {
+	if len(batch) == 0 {
+		return batch
+	}
+	if err := w.backend.Write(ctx, batch); err != nil {
+		archiveMetrics.Add("failures", 1)
+		w.log.Error("Failed to write archive batch, spilling to disk", zap.Int("records", len(batch)), zap.Error(err))
+		for _, r := range batch {
+			w.spill(r)
+		}
+	} else {
+		archiveMetrics.Add("written", int64(len(batch)))
+		archiveMetrics.Add("batches", 1)
+	}
+	return batch[:0]
+}
+
+// Stop queueing and write everything still in memory
+func (w *Writer) close(batch []Record) {
+	w.mux.Lock()
+	w.closed = true
+	w.mux.Unlock()
+
+	// Nothing is queued anymore, and only Run receives
+	for len(w.queue) > 0 {
+		batch = append(batch, <-w.queue)
+		buffered.Add(-1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+	defer cancel()
+	for len(batch) > 0 {
+		n := min(len(batch), w.batchSize)
+		w.flush(ctx, batch[:n])
+		batch = batch[n:]
+	}
+}
+
+// Append r to the spill file, dropping it once the file is full
+func (w *Writer) spill(r Record) {
+	line, err := json.Marshal(r)
+	if err != nil {
+		archiveMetrics.Add("dropped", 1)
+		return
+	}
+	line = append(line, '\n')
+
+	w.spillMux.Lock()
+	defer w.spillMux.Unlock()
+	if w.spilled+int64(len(line)) > w.spillMax {
+		archiveMetrics.Add("dropped", 1)
+		if !w.dropping {
+			w.dropping = true
+			w.log.Warn("Archive spill file is full, dropping records", zap.String("path", w.spillPath), zap.Int64("max_size", w.spillMax))
+		}
+		return
+	}
+	f, err := os.OpenFile(w.spillPath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err == nil {
+		_, err = f.Write(line)
+		err = errors.Join(err, f.Close())
+	}
+	if err != nil {
+		archiveMetrics.Add("dropped", 1)
+		w.log.Error("Failed to spill archive record", zap.Error(err))
+		return
+	}
+	w.spilled += int64(len(line))
+	archiveMetrics.Add("spilled", 1)
+}
+
+// Write the spilled records to the backend. The spill file is set aside
+// first so records keep spilling meanwhile; when writing fails part way,
+// the batches already written are written again on the next try.
+func (w *Writer) replay(ctx context.Context) {
+	pending := w.spillPath + ".replay"
+	for {
+		w.spillMux.Lock()
+		_, err := os.Stat(pending)
+		leftover := err == nil
+		if !leftover {
+			err := os.Rename(w.spillPath, pending)
+			if errors.Is(err, fs.ErrNotExist) {
+				w.spillMux.Unlock()
+				return
+			}
+			if err != nil {
+				w.spillMux.Unlock()
+				w.log.Error("Failed to set spilled archive records aside", zap.Error(err))
+				return
+			}
+			w.spilled = 0
+			w.dropping = false
+		}
+		w.spillMux.Unlock()
+
+		n, err := w.writeFile(ctx, pending)
+		if err != nil {
+			w.log.Error("Failed to write spilled archive records", zap.Error(err))
+			return
+		}
+		if err := os.Remove(pending); err != nil {
+			w.log.Error("Failed to remove spilled archive records", zap.Error(err))
+			return
+		}
+		archiveMetrics.Add("replayed", int64(n))
+		w.log.Info("Wrote spilled archive records", zap.Int("records", n))
+
+		// Records spilled while a leftover from an earlier try was written
+		if !leftover {
+			return
+		}
+	}
+}
+
+// Write the records in the JSON Lines file at path in batches
+func (w *Writer) writeFile(ctx context.Context, path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var n int
+	batch := make([]Record, 0, w.batchSize)
+	write := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := w.backend.Write(ctx, batch); err != nil {
+			return err
+		}
+		n += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			// A line cut short by a crash, the rest is still good
+			continue
+		}
+		batch = append(batch, r)
+		if len(batch) >= w.batchSize {
+			if err := write(); err != nil {
+				return n, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return n, err
+	}
+	return n, write()
+}
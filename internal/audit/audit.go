@@ -0,0 +1,135 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package audit keeps an append-only log of runtime changes, recording who
+// changed what and when.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+)
+
+// Where a change came from
+const (
+	SourceCommand = "command" // Bot command in an alert chat
+	SourceButton  = "button"  // Button on an alert
+	SourceAPI     = "api"     // Admin API
+	SourceStartup = "startup" // Found when the instance started
+)
+
+// A single change
+type Entry struct {
+	Time   time.Time `json:"time"`
+	Actor  string    `json:"actor"`  // Telegram user or admin API caller
+	Source string    `json:"source"` // One of the Source constants
+	Action string    `json:"action"` // Such as "snooze" or "ack"
+	Target string    `json:"target"` // Rule, alert ID or file changed
+	ChatID int64     `json:"chat_id,omitempty"`
+	Detail string    `json:"detail,omitempty"`
+}
+
+// Append entries to a file, one JSON object per line
+type Log struct {
+	mux  sync.Mutex
+	path string
+	now  func() time.Time
+}
+
+// Create a Log appending to path, which is created on the first entry
+func New(path string) *Log {
+	return &Log{path: path, now: time.Now}
+}
+
+// Append e, stamping it with the current time unless it has one
+func (l *Log) Record(e Entry) error {
+	if e.Time.IsZero() {
+		e.Time = l.now().UTC()
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Select entries when reading the log, zero fields match everything
+type Query struct {
+	Actor  string
+	Action string
+	Since  time.Time
+	Limit  int // Keep only the newest entries
+}
+
+// Read the entries at path matching q, oldest first. A missing file holds none.
+func Read(path string, q Query) ([]Entry, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("invalid audit log %s line %d: %w", path, n, err)
+		}
+		if q.matches(e) {
+			entries = append(entries, e)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if q.Limit > 0 && len(entries) > q.Limit {
+		entries = entries[len(entries)-q.Limit:]
+	}
+	return entries, nil
+}
+
+func (q Query) matches(e Entry) bool {
+	return (q.Actor == "" || e.Actor == q.Actor) &&
+		(q.Action == "" || e.Action == q.Action) &&
+		!e.Time.Before(q.Since)
+}
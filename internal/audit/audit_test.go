@@ -0,0 +1,82 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package audit
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	if entries, err := Read(path, Query{}); err != nil || len(entries) != 0 {
+		t.Fatalf("Read() of missing file = %v, %v", entries, err)
+	}
+
+	l := New(path)
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	l.now = func() time.Time { return now }
+
+	for _, e := range []Entry{
+		{Actor: "alice", Source: SourceCommand, Action: "snooze", Target: "giveaway", ChatID: -1001, Detail: "1h0m0s"},
+		{Actor: "bob", Source: SourceButton, Action: "ack", Target: "a1"},
+		{Actor: "alice", Source: SourceAPI, Action: "resolve", Target: "a1"},
+	} {
+		if err := l.Record(e); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+		now = now.Add(time.Hour)
+	}
+
+	all, err := Read(path, Query{})
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(all) != 3 || all[0].Action != "snooze" || !all[0].Time.Equal(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)) || all[0].ChatID != -1001 {
+		t.Errorf("unexpected entries %+v", all)
+	}
+
+	tests := []struct {
+		name string
+		q    Query
+		want []string
+	}{
+		{"Actor", Query{Actor: "alice"}, []string{"snooze", "resolve"}},
+		{"Action", Query{Action: "ack"}, []string{"ack"}},
+		{"Since", Query{Since: all[1].Time}, []string{"ack", "resolve"}},
+		{"Limit Keeps Newest", Query{Limit: 1}, []string{"resolve"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := Read(path, tt.q)
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			var got []string
+			for _, e := range entries {
+				got = append(got, e.Action)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package chatid
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Classify the peer a chat reference points at
+type Kind int
+
+const (
+	// Bare numeric ID, may be any peer type
+	Any Kind = iota
+	// Channel or supergroup, written as -100<id>
+	Channel
+	// Basic group, written as -<id>
+	Group
+	// User or bot
+	User
+)
+
+// Offset the Bot API adds to channel IDs
+const channelOffset = 1_000_000_000_000
+
+// Telegram usernames: 5-32 characters, letters, digits and underscores, starting with a letter
+var usernamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]{3,31}$`)
+
+// Describe a parsed entry of the chats config list
+type Ref struct {
+	Kind     Kind
+	ID       int64  // MTProto ID without any Bot API prefix
+	Username string // Set instead of ID for username references
+}
+
+// Parse a chat reference: @username, username, -100<id>, -<id> or a bare <id>
+func Parse(s string) (Ref, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Ref{}, fmt.Errorf("empty chat reference")
+	}
+
+	if s[0] == '-' || (s[0] >= '0' && s[0] <= '9') {
+		return parseNumeric(s)
+	}
+
+	name := strings.TrimPrefix(s, "@")
+	if !usernamePattern.MatchString(name) {
+		return Ref{}, fmt.Errorf("invalid chat %q: not a numeric ID or a valid username", s)
+	}
+	return Ref{Username: name}, nil
+}
+
+func parseNumeric(s string) (Ref, error) {
+	kind := Any
+	digits := s
+	switch {
+	case strings.HasPrefix(s, "-100") && len(s) > 4:
+		kind, digits = Channel, s[4:]
+	case strings.HasPrefix(s, "-"):
+		kind, digits = Group, s[1:]
+	}
+
+	id, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil || id <= 0 {
+		return Ref{}, fmt.Errorf("invalid chat ID %q", s)
+	}
+	return Ref{Kind: kind, ID: id}, nil
+}
+
+// International phone numbers: a plus sign and up to 15 digits
+var phonePattern = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+// Parse a user reference written as an international phone number, returning
+// its digits. Only the chats list accepts phone numbers.
+func ParsePhone(s string) (string, bool) {
+	s = strings.TrimSpace(s)
+	if !phonePattern.MatchString(s) {
+		return "", false
+	}
+	return s[1:], true
+}
+
+// Report whether the reference is a username rather than an ID
+func (r Ref) IsUsername() bool {
+	return r.Username != ""
+}
+
+// Report whether a peer of the given kind and ID is the one referenced
+func (r Ref) Matches(kind Kind, id int64) bool {
+	if r.IsUsername() || r.ID != id {
+		return false
+	}
+	return r.Kind == Any || r.Kind == kind
+}
+
+// Report whether the reference is the given username, ignoring case
+func (r Ref) MatchesUsername(username string) bool {
+	return r.IsUsername() && username != "" && strings.EqualFold(r.Username, username)
+}
+
+// Convert an MTProto peer to its Bot API ID, the form users put in config
+func BotAPI(kind Kind, id int64) int64 {
+	switch kind {
+	case Channel:
+		return -(channelOffset + id)
+	case Group:
+		return -id
+	default:
+		return id
+	}
+}
+
+// Split a Bot API ID back into kind and MTProto ID.
+// Positive IDs are reported as users.
+func FromBotAPI(id int64) (Kind, int64) {
+	switch {
+	case id < -channelOffset:
+		return Channel, -id - channelOffset
+	case id < 0:
+		return Group, -id
+	default:
+		return User, id
+	}
+}
+
+// Path of a t.me message link: [c/]<chat>/[<topic>/]<message>
+var linkPattern = regexp.MustCompile(`^(?:https?://)?(?:www\.)?(?:t|telegram)\.me/(?:(c)/(\d+)|([A-Za-z][A-Za-z0-9_]{3,31}))(?:/\d+)?/(\d+)/?$`)
+
+// Parse a t.me message link, public as t.me/<username>/<id> or private as
+// t.me/c/<channel>/<id>, either with an optional forum topic before the ID
+func ParseLink(link string) (Ref, int, error) {
+	m := linkPattern.FindStringSubmatch(strings.TrimSpace(link))
+	if m == nil {
+		return Ref{}, 0, fmt.Errorf("invalid message link %q: expected t.me/<username>/<id> or t.me/c/<channel>/<id>", link)
+	}
+	msgID, err := strconv.Atoi(m[4])
+	if err != nil || msgID <= 0 {
+		return Ref{}, 0, fmt.Errorf("invalid message ID in link %q", link)
+	}
+	if m[1] == "" {
+		return Ref{Username: m[3]}, msgID, nil
+	}
+	id, err := strconv.ParseInt(m[2], 10, 64)
+	if err != nil || id <= 0 {
+		return Ref{}, 0, fmt.Errorf("invalid channel ID in link %q", link)
+	}
+	return Ref{Kind: Channel, ID: id}, msgID, nil
+}
@@ -0,0 +1,165 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package chatid
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		want     Ref
+		hasError bool
+	}{
+		{"-1001803446893", Ref{Kind: Channel, ID: 1803446893}, false},
+		{"-4567", Ref{Kind: Group, ID: 4567}, false},
+		{"1710595474", Ref{Kind: Any, ID: 1710595474}, false},
+		{"@example_channel", Ref{Username: "example_channel"}, false},
+		{"example_channel", Ref{Username: "example_channel"}, false},
+		{"-100", Ref{Kind: Group, ID: 100}, false},
+		{"-100abc", Ref{}, true},
+		{"12ab", Ref{}, true},
+		{"@ab", Ref{}, true},
+		{"has space", Ref{}, true},
+		{"0", Ref{}, true},
+		{"", Ref{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.input)
+		if tt.hasError {
+			if err == nil {
+				t.Errorf("Parse(%q): expected error, got %+v", tt.input, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q): unexpected error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseLink(t *testing.T) {
+	tests := []struct {
+		input    string
+		want     Ref
+		msgID    int
+		hasError bool
+	}{
+		{"https://t.me/example_channel/42", Ref{Username: "example_channel"}, 42, false},
+		{"t.me/example_channel/7/42", Ref{Username: "example_channel"}, 42, false},
+		{"https://telegram.me/example_channel/42/", Ref{Username: "example_channel"}, 42, false},
+		{"https://t.me/c/1803446893/42", Ref{Kind: Channel, ID: 1803446893}, 42, false},
+		{"https://t.me/c/1803446893/3/42", Ref{Kind: Channel, ID: 1803446893}, 42, false},
+		{"https://t.me/example_channel", Ref{}, 0, true},
+		{"https://t.me/+AbCdEf/42", Ref{}, 0, true},
+		{"https://example.com/example_channel/42", Ref{}, 0, true},
+		{"https://t.me/c/1803446893/0", Ref{}, 0, true},
+	}
+
+	for _, tt := range tests {
+		got, msgID, err := ParseLink(tt.input)
+		if tt.hasError {
+			if err == nil {
+				t.Errorf("ParseLink(%q): expected error, got %+v", tt.input, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseLink(%q): unexpected error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want || msgID != tt.msgID {
+			t.Errorf("ParseLink(%q) = %+v, %d, want %+v, %d", tt.input, got, msgID, tt.want, tt.msgID)
+		}
+	}
+}
+
+func TestParsePhone(t *testing.T) {
+	tests := map[string]string{
+		"+5511987654321":  "5511987654321",
+		" +14155550100 ":  "14155550100",
+		"5511987654321":   "",
+		"+0123456789":     "",
+		"+123":            "",
+		"+55 11 98765432": "",
+	}
+	for input, want := range tests {
+		got, ok := ParsePhone(input)
+		if ok != (want != "") || got != want {
+			t.Errorf("ParsePhone(%q) = %q, %v, want %q", input, got, ok, want)
+		}
+	}
+}
+
+func TestMatches(t *testing.T) {
+	bare := Ref{Kind: Any, ID: 42}
+	channel := Ref{Kind: Channel, ID: 42}
+
+	if !bare.Matches(Channel, 42) || !bare.Matches(User, 42) {
+		t.Error("expected bare ID to match any peer kind")
+	}
+	if !channel.Matches(Channel, 42) || channel.Matches(Group, 42) {
+		t.Error("expected channel ID to match channels only")
+	}
+	if (Ref{Username: "x"}).Matches(Channel, 0) {
+		t.Error("expected username ref never to match by ID")
+	}
+}
+
+func TestMatchesUsername(t *testing.T) {
+	ref, err := Parse("@NoisyGroup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ref.MatchesUsername("noisygroup") {
+		t.Error("expected case-insensitive username match")
+	}
+	if ref.MatchesUsername("") || ref.MatchesUsername("other_group") {
+		t.Error("expected no match for other usernames")
+	}
+	if id, _ := Parse("-1001803446893"); id.MatchesUsername("") {
+		t.Error("expected numeric references never to match usernames")
+	}
+}
+
+func TestBotAPIRoundTrip(t *testing.T) {
+	tests := []struct {
+		kind  Kind
+		id    int64
+		botID int64
+	}{
+		{Channel, 1803446893, -1001803446893},
+		{Group, 4567, -4567},
+		{User, 1710595474, 1710595474},
+	}
+
+	for _, tt := range tests {
+		if got := BotAPI(tt.kind, tt.id); got != tt.botID {
+			t.Errorf("BotAPI(%d, %d) = %d, want %d", tt.kind, tt.id, got, tt.botID)
+		}
+		kind, id := FromBotAPI(tt.botID)
+		if kind != tt.kind || id != tt.id {
+			t.Errorf("FromBotAPI(%d) = %d, %d", tt.botID, kind, id)
+		}
+	}
+}
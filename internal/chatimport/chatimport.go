@@ -0,0 +1,122 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package chatimport reads watchlists of chats exported from other tools
+// and adds the chats, once resolved, to the config file.
+package chatimport
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/h3nc4/TelegramScout/internal/chatid"
+	"github.com/h3nc4/TelegramScout/internal/mentions"
+)
+
+// A chat reference read from a watchlist
+type Entry struct {
+	Line  int    // In the CSV file
+	Input string // As written
+	Note  string // Optional second column, kept as a comment in the config
+}
+
+// Header cells naming the chat column, skipped when on the first row
+var headers = map[string]bool{"chat": true, "chats": true, "username": true, "link": true, "id": true, "channel": true}
+
+// Read chat references from CSV, one per row in the first column with an
+// optional note in the second. A header row, blank rows and rows starting
+// with # are skipped.
+func ReadCSV(r io.Reader) ([]Entry, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.Comment = '#'
+	cr.TrimLeadingSpace = true
+	var entries []Entry
+	for first := true; ; first = false {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		input := strings.TrimSpace(record[0])
+		if input == "" || (first && headers[strings.ToLower(input)]) {
+			continue
+		}
+		line, _ := cr.FieldPos(0)
+		e := Entry{Line: line, Input: input}
+		if len(record) > 1 {
+			e.Note = strings.TrimSpace(record[1])
+		}
+		entries = append(entries, e)
+	}
+}
+
+// t.me links to a chat, an invite or a chat folder
+var linkPattern = regexp.MustCompile(`(?i)^(?:https?://)?(?:www\.)?(?:t|telegram)\.me/(addlist/|joinchat/|\+)?([A-Za-z0-9_\-]+)/?$`)
+
+// What a watchlist entry points at. Exactly one field is set.
+type Target struct {
+	Chat   string       // Already in config form: a numeric ID or a phone number
+	Ref    mentions.Ref // Username or invite to look up
+	Folder string       // Chat folder link slug, standing for every chat in it
+}
+
+// Classify a chat reference: numeric IDs and phone numbers as written in the
+// config, @usernames, bare usernames, t.me links, invite links or folder links
+func Parse(input string) (Target, error) {
+	input = strings.TrimSpace(input)
+	if _, ok := chatid.ParsePhone(input); ok {
+		return Target{Chat: input}, nil
+	}
+	if m := linkPattern.FindStringSubmatch(input); m != nil {
+		switch strings.ToLower(m[1]) {
+		case "addlist/":
+			return Target{Folder: m[2]}, nil
+		case "joinchat/", "+":
+			return Target{Ref: mentions.Ref{Invite: m[2]}}, nil
+		}
+		input = m[2]
+	}
+	ref, err := chatid.Parse(input)
+	if err != nil {
+		return Target{}, err
+	}
+	if !ref.IsUsername() {
+		return Target{Chat: input}, nil
+	}
+	return Target{Ref: mentions.Ref{Username: strings.ToLower(ref.Username)}}, nil
+}
+
+// Return the config entry for a resolved chat: its username when public, as
+// that keeps working for chats the account has not joined, else its ID.
+// Invites to chats the account has not joined do not reveal the ID.
+func Normalize(info mentions.Info) (string, error) {
+	if info.Username != "" {
+		return strings.ToLower(info.Username), nil
+	}
+	if info.ID == 0 {
+		return "", errors.New("not a member, join the chat before importing it")
+	}
+	return fmt.Sprint(info.ID), nil
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package chatimport
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/h3nc4/TelegramScout/internal/mentions"
+)
+
+func TestReadCSV(t *testing.T) {
+	in := "chat,note\n# exported from another tool\n@deals, Hardware deals\n\nhttps://t.me/+AbCdEf\n-1001234567890,\"Ops, private\"\n"
+	entries, err := ReadCSV(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Entry{
+		{Line: 3, Input: "@deals", Note: "Hardware deals"},
+		{Line: 5, Input: "https://t.me/+AbCdEf"},
+		{Line: 6, Input: "-1001234567890", Note: "Ops, private"},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("got %+v, want %+v", entries, want)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Target
+	}{
+		{"@Deals", Target{Ref: mentions.Ref{Username: "deals"}}},
+		{"deals_channel", Target{Ref: mentions.Ref{Username: "deals_channel"}}},
+		{"https://t.me/Deals", Target{Ref: mentions.Ref{Username: "deals"}}},
+		{"t.me/+AbCdEf", Target{Ref: mentions.Ref{Invite: "AbCdEf"}}},
+		{"https://telegram.me/joinchat/AbCdEf", Target{Ref: mentions.Ref{Invite: "AbCdEf"}}},
+		{"https://t.me/addlist/XyZ123", Target{Folder: "XyZ123"}},
+		{"-1001234567890", Target{Chat: "-1001234567890"}},
+		{"+15551234567", Target{Chat: "+15551234567"}},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+	if _, err := Parse("not a chat!"); err == nil {
+		t.Error("expected an invalid entry to fail")
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	if got, _ := Normalize(mentions.Info{ID: -1001, Username: "Deals"}); got != "deals" {
+		t.Errorf("expected the username, got %q", got)
+	}
+	if got, _ := Normalize(mentions.Info{ID: -1001}); got != "-1001" {
+		t.Errorf("expected the ID, got %q", got)
+	}
+	if _, err := Normalize(mentions.Info{Title: "Private"}); err == nil {
+		t.Error("expected an unjoined invite to fail")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	data := []byte("# Watchlist\nchats:\n  - \"@Deals\"\nkeywords:\n  - gpu\n")
+	out, rep, err := Merge(data, []Chat{
+		{Ref: "deals", Comment: "Deals"},
+		{Ref: "-1001234567890", Comment: "Ops"},
+		{Ref: "-1001234567890", Comment: "Ops again"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rep.Added, []string{"-1001234567890"}) || !reflect.DeepEqual(rep.Kept, []string{"deals", "-1001234567890"}) {
+		t.Errorf("unexpected report %+v", rep)
+	}
+	for _, want := range []string{"# Watchlist", `"-1001234567890" # Ops`, "keywords:"} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("expected %q in merged config:\n%s", want, out)
+		}
+	}
+
+	out, rep, err = Merge(nil, []Chat{{Ref: "deals"}})
+	if err != nil || string(out) != "chats:\n  - deals\n" || len(rep.Added) != 1 {
+		t.Errorf("unexpected merge into an empty config: %q %+v %v", out, rep, err)
+	}
+
+	if _, _, err := Merge(data, nil); err != nil {
+		t.Errorf("expected nothing to merge to succeed, got %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package chatimport
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/h3nc4/TelegramScout/internal/chatid"
+)
+
+// A resolved chat to add to the config
+type Chat struct {
+	Ref     string // As written to the chats list
+	Comment string // Kept on the entry's line, e.g. the chat's title
+}
+
+// What merging chats changed
+type Report struct {
+	Added []string // Chats new to the config
+	Kept  []string // Chats already listed
+}
+
+// Merge chats into the chats list of a config file, returning the new
+// contents. Chats already listed, by the same username or ID, are kept.
+func Merge(data []byte, chats []Chat) ([]byte, Report, error) {
+	var rep Report
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, rep, err
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, rep, errors.New("config is not a mapping")
+	}
+	seq := chatsList(root)
+
+	for _, chat := range chats {
+		if slices.ContainsFunc(seq.Content, func(n *yaml.Node) bool { return same(n.Value, chat.Ref) }) {
+			rep.Kept = append(rep.Kept, chat.Ref)
+			continue
+		}
+		item := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: chat.Ref}
+		if chat.Comment != "" {
+			item.LineComment = "# " + strings.ReplaceAll(chat.Comment, "\n", " ")
+		}
+		// Flow lists written inline would not keep the comments
+		seq.Style = 0
+		seq.Content = append(seq.Content, item)
+		rep.Added = append(rep.Added, chat.Ref)
+	}
+	if len(rep.Added) == 0 {
+		return data, rep, nil
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, rep, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, rep, err
+	}
+	return buf.Bytes(), rep, nil
+}
+
+// Return the chats sequence of the config, adding an empty one when absent or empty
+func chatsList(root *yaml.Node) *yaml.Node {
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != "chats" {
+			continue
+		}
+		if root.Content[i+1].Kind != yaml.SequenceNode {
+			root.Content[i+1] = &yaml.Node{Kind: yaml.SequenceNode}
+		}
+		return root.Content[i+1]
+	}
+	seq := &yaml.Node{Kind: yaml.SequenceNode}
+	root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "chats"}, seq)
+	return seq
+}
+
+// Report whether two chats list entries name the same chat
+func same(a, b string) bool {
+	ra, errA := chatid.Parse(a)
+	rb, errB := chatid.Parse(b)
+	if errA != nil || errB != nil {
+		return strings.TrimSpace(a) == strings.TrimSpace(b)
+	}
+	if ra.IsUsername() {
+		return rb.MatchesUsername(ra.Username)
+	}
+	return !rb.IsUsername() && ra.Kind == rb.Kind && ra.ID == rb.ID
+}
@@ -0,0 +1,161 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+// State shared by every instance in a fleet, backed by Redis
+type Store struct {
+	client *redis.Client
+	prefix string
+}
+
+// Connect to the configured Redis server
+func New(ctx context.Context, cfg config.ClusterConfig) (*Store, error) {
+	opts, err := redis.ParseURL(cfg.Redis)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cluster.redis url: %w", err)
+	}
+
+	s := &Store{client: redis.NewClient(opts), prefix: cfg.Prefix}
+	if err := s.client.Ping(ctx).Err(); err != nil {
+		_ = s.client.Close()
+		return nil, fmt.Errorf("failed to reach redis: %w", err)
+	}
+	return s, nil
+}
+
+// Close the connection pool
+func (s *Store) Close() error {
+	return s.client.Close()
+}
+
+func (s *Store) key(name string) string {
+	return s.prefix + ":" + name
+}
+
+// Mark key as seen for ttl, reporting whether this call was the first to do so
+func (s *Store) Claim(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return s.client.SetNX(ctx, s.key(key), 1, ttl).Result()
+}
+
+// Append value to the list, trimming it to the newest max entries.
+// Return the number of entries discarded.
+func (s *Store) Push(ctx context.Context, list string, value string, max int) (int, error) {
+	k := s.key(list)
+	n, err := s.client.RPush(ctx, k, value).Result()
+	if err != nil {
+		return 0, err
+	}
+	over := int(n) - max
+	if over <= 0 {
+		return 0, nil
+	}
+	return over, s.client.LTrim(ctx, k, int64(over), -1).Err()
+}
+
+// Put values back in front of the list, keeping their order
+func (s *Store) PushFront(ctx context.Context, list string, values ...string) error {
+	if len(values) == 0 {
+		return nil
+	}
+	args := make([]any, len(values))
+	for i, v := range values {
+		// LPUSH prepends one at a time, so push in reverse
+		args[len(values)-1-i] = v
+	}
+	return s.client.LPush(ctx, s.key(list), args...).Err()
+}
+
+// Remove and return up to n entries from the front of the list
+func (s *Store) Pop(ctx context.Context, list string, n int) ([]string, error) {
+	values, err := s.client.LPopCount(ctx, s.key(list), n).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	return values, err
+}
+
+// Return the length of the list
+func (s *Store) Len(ctx context.Context, list string) (int, error) {
+	n, err := s.client.LLen(ctx, s.key(list)).Result()
+	return int(n), err
+}
+
+// Add delta to a counter
+func (s *Store) Add(ctx context.Context, counter string, delta int) error {
+	return s.client.IncrBy(ctx, s.key(counter), int64(delta)).Err()
+}
+
+// Read a counter and reset it to zero
+func (s *Store) Take(ctx context.Context, counter string) (int, error) {
+	n, err := s.client.GetDel(ctx, s.key(counter)).Int()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return n, err
+}
+
+// Fixed window rate limit shared across the fleet
+type RateLimiter struct {
+	store  *Store
+	name   string
+	limit  int
+	window time.Duration
+}
+
+// Create a RateLimiter allowing limit events per window
+func (s *Store) RateLimiter(name string, limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{store: s, name: name, limit: limit, window: window}
+}
+
+// Count an event, returning how long to wait before retrying if the window is full
+func (r *RateLimiter) Reserve(ctx context.Context) (time.Duration, error) {
+	res, err := reserveScript.Run(ctx, r.store.client, []string{r.store.key("rate:" + r.name)}, r.window.Milliseconds()).Int64Slice()
+	if err != nil {
+		return 0, err
+	}
+
+	count, ttl := res[0], time.Duration(res[1])*time.Millisecond
+	if int(count) <= r.limit {
+		return 0, nil
+	}
+	if ttl > 0 {
+		return ttl, nil
+	}
+	return r.window, nil
+}
+
+// Increment the window counter, starting its expiry on the first event
+var reserveScript = redis.NewScript(`
+local n = redis.call("INCR", KEYS[1])
+if n == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return {n, redis.call("PTTL", KEYS[1])}
+`)
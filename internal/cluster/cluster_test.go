@@ -0,0 +1,125 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package cluster
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+func newTestStore(t *testing.T) (*Store, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	s, err := New(context.Background(), config.ClusterConfig{Redis: "redis://" + mr.Addr(), Prefix: "test"})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	return s, mr
+}
+
+func TestStore(t *testing.T) {
+	s, mr := newTestStore(t)
+	ctx := context.Background()
+
+	t.Run("Claim", func(t *testing.T) {
+		if first, err := s.Claim(ctx, "seen:a", time.Minute); err != nil || !first {
+			t.Fatalf("expected first claim to win, got %t %v", first, err)
+		}
+		if first, _ := s.Claim(ctx, "seen:a", time.Minute); first {
+			t.Error("expected second claim to lose")
+		}
+		if !mr.Exists("test:seen:a") {
+			t.Error("expected key to carry the prefix")
+		}
+
+		mr.FastForward(2 * time.Minute)
+		if first, _ := s.Claim(ctx, "seen:a", time.Minute); !first {
+			t.Error("expected claim to win again after expiry")
+		}
+	})
+
+	t.Run("Lists", func(t *testing.T) {
+		for _, v := range []string{"1", "2", "3"} {
+			if _, err := s.Push(ctx, "list", v, 2); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if n, _ := s.Len(ctx, "list"); n != 2 {
+			t.Errorf("expected list trimmed to 2, got %d", n)
+		}
+
+		got, err := s.Pop(ctx, "list", 10)
+		if err != nil || !slices.Equal(got, []string{"2", "3"}) {
+			t.Fatalf("unexpected pop: %v %v", got, err)
+		}
+		if got, err := s.Pop(ctx, "list", 10); err != nil || got != nil {
+			t.Errorf("expected empty pop, got %v %v", got, err)
+		}
+
+		_, _ = s.Push(ctx, "list", "c", 10)
+		if err := s.PushFront(ctx, "list", "a", "b"); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := s.Pop(ctx, "list", 10); !slices.Equal(got, []string{"a", "b", "c"}) {
+			t.Errorf("expected order preserved, got %v", got)
+		}
+	})
+
+	t.Run("Counters", func(t *testing.T) {
+		_ = s.Add(ctx, "count", 2)
+		_ = s.Add(ctx, "count", 3)
+		if n, err := s.Take(ctx, "count"); err != nil || n != 5 {
+			t.Errorf("expected 5, got %d %v", n, err)
+		}
+		if n, err := s.Take(ctx, "count"); err != nil || n != 0 {
+			t.Errorf("expected reset counter, got %d %v", n, err)
+		}
+	})
+}
+
+func TestRateLimiter(t *testing.T) {
+	s, mr := newTestStore(t)
+	ctx := context.Background()
+
+	// Two instances sharing one limit
+	a := s.RateLimiter("notifier", 2, time.Minute)
+	b := s.RateLimiter("notifier", 2, time.Minute)
+
+	for _, r := range []*RateLimiter{a, b} {
+		if wait, err := r.Reserve(ctx); err != nil || wait != 0 {
+			t.Fatalf("expected immediate slot, got %s %v", wait, err)
+		}
+	}
+	wait, err := a.Reserve(ctx)
+	if err != nil || wait <= 0 || wait > time.Minute {
+		t.Fatalf("expected wait within the window, got %s %v", wait, err)
+	}
+
+	mr.FastForward(time.Minute)
+	if wait, _ := b.Reserve(ctx); wait != 0 {
+		t.Errorf("expected slot in the next window, got %s", wait)
+	}
+}
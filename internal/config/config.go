@@ -19,17 +19,852 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"maps"
+	"net"
+	"net/url"
 	"os"
+	"path"
+	"path/filepath"
+	"slices"
 	"strconv"
+	"strings"
+	"text/template"
+	"time"
 
+	"go.uber.org/zap/zapcore"
 	"gopkg.in/yaml.v3"
+
+	"github.com/h3nc4/TelegramScout/internal/chatid"
+	"github.com/h3nc4/TelegramScout/internal/logger"
+	"github.com/h3nc4/TelegramScout/internal/mentions"
+	"github.com/h3nc4/TelegramScout/internal/schedule"
+)
+
+// Defaults for optional settings
+const (
+	DefaultExplainSize          = 100
+	DefaultQueueSize            = 100
+	DefaultDigestSize           = 500
+	DefaultDigestInterval       = 30 * time.Second
+	DefaultNotifierConcurrency  = 5
+	DefaultCoalesceThreshold    = 3
+	DefaultDedupWindow          = 512
+	DefaultDedupTTL             = time.Hour
+	DefaultDedupMaxEntries      = 100000
+	DefaultDedupCleanupInterval = 10 * time.Minute
+	DefaultMatchCacheEntries    = 10000
+	DefaultPCRETimeout          = 100 * time.Millisecond
+	DefaultCatchUpMessages      = 100
+)
+
+// Match documents by their attributes, every set criterion must hold
+type FileRule struct {
+	Name     string `yaml:"name"`      // Reported as the matched rule, defaults to the criteria
+	Filename string `yaml:"filename"`  // Case-insensitive glob, e.g. "*.apk"
+	MimeType string `yaml:"mime_type"` // Glob, e.g. "application/vnd.android.*"
+	MinSize  int64  `yaml:"min_size"`  // In bytes
+	MaxSize  int64  `yaml:"max_size"`  // In bytes, 0 means unbounded
+}
+
+// Return the rule's name, or a summary of its criteria when unnamed
+func (r FileRule) Label() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	var parts []string
+	if r.Filename != "" {
+		parts = append(parts, r.Filename)
+	}
+	if r.MimeType != "" {
+		parts = append(parts, r.MimeType)
+	}
+	switch {
+	case r.MinSize > 0 && r.MaxSize > 0:
+		parts = append(parts, fmt.Sprintf("%d-%d bytes", r.MinSize, r.MaxSize))
+	case r.MinSize > 0:
+		parts = append(parts, fmt.Sprintf(">=%d bytes", r.MinSize))
+	case r.MaxSize > 0:
+		parts = append(parts, fmt.Sprintf("<=%d bytes", r.MaxSize))
+	}
+	return strings.Join(parts, " ")
+}
+
+// Differing hash bits tolerated by image rules by default
+const DefaultImageMaxDistance = 10
+
+// Largest media file archived by default, in bytes
+const DefaultMediaMaxSize = 50 << 20
+
+// Longest wait for a threat intelligence lookup by default
+const DefaultEnrichTimeout = 10 * time.Second
+
+// Limits for following short links by default
+const (
+	DefaultURLMaxRedirects = 5
+	DefaultURLTimeout      = 5 * time.Second
+)
+
+// Limits for rendering linked pages by default
+const (
+	DefaultScreenshotMaxLinks = 1
+	DefaultScreenshotTimeout  = 30 * time.Second
+)
+
+// Actions run per hour by default
+const DefaultActionRateLimit = 20
+
+// How often the canary is posted, and how long its loop may take, by default
+const (
+	DefaultCanaryInterval = time.Hour
+	DefaultCanaryTimeout  = 2 * time.Minute
+)
+
+// How long a referenced chat stays quiet after being reported by default
+const DefaultMentionsCooldown = 24 * time.Hour
+
+// How often the chat graph is written to the state directory by default
+const DefaultGraphSaveInterval = 5 * time.Minute
+
+// Alerts tracked for acknowledgement, and how often new critical ones are reminded of, by default
+const (
+	DefaultAcksSize        = 1000
+	DefaultAcksRemindAfter = 30 * time.Minute
+)
+
+// Results shown by the /search bot command and inline queries by default
+const DefaultSearchLimit = 5
+
+// Bot commands and inline queries answered per user each minute by default
+const DefaultCommandRateLimit = 10
+
+// Results a scheduled sweep fetches per chat by default
+const DefaultSweepLimit = 20
+
+// How often watched messages are polled by default
+const DefaultWatchInterval = 5 * time.Minute
+
+// Status changes kept per tracked user by default
+const DefaultPresenceHistory = 500
+
+// How often the account's standing is checked by default
+const DefaultStandingInterval = time.Hour
+
+// Metrics push defaults
+const (
+	DefaultMetricsInterval = 30 * time.Second
+	DefaultMetricsJob      = "telegram-scout" // Pushgateway job name
+	DefaultMetricsPrefix   = "telegram_scout" // Prepended to every metric name
+)
+
+// Span over which alerts are counted to detect a storm by default
+const DefaultStormWindow = time.Minute
+
+// Archive write buffer defaults
+const (
+	DefaultArchiveBuffer        = 10000            // Records held in memory
+	DefaultArchiveBatchSize     = 500              // Records written at once
+	DefaultArchiveFlushInterval = 5 * time.Second  // Longest a record waits in memory
+	DefaultArchiveSpillMaxSize  = 64 * 1024 * 1024 // Bytes spilled to disk before dropping
+	DefaultSummaryTop           = 5                // Rules and chats listed in the summary
+	DefaultClickHouseDatabase   = "default"
+	DefaultClickHouseTable      = "telegram_scout_archive"
+)
+
+// Random wait before each auto-join, and joins allowed per day, by default
+const (
+	DefaultJoinMinDelay = 2 * time.Minute
+	DefaultJoinMaxDelay = 10 * time.Minute
+	DefaultJoinDailyCap = 20
+)
+
+// Published rule packs, kept next to the built-in ones in the upstream repository
+const DefaultPackIndex = "https://raw.githubusercontent.com/h3nc4/TelegramScout/main/internal/rules/packs/index.yaml"
+
+// Notifier retry and circuit breaker defaults
+const (
+	DefaultRetryAttempts    = 3
+	DefaultRetryBaseDelay   = time.Second
+	DefaultRetryMaxDelay    = 30 * time.Second
+	DefaultBreakerThreshold = 5
+	DefaultBreakerCooldown  = time.Minute
+)
+
+// Official Bot API server
+const DefaultBotAPIURL = "https://api.telegram.org"
+
+// Longest wait for a webhook endpoint by default
+const DefaultWebhookTimeout = 10 * time.Second
+
+// Webhook outbox defaults
+const (
+	DefaultOutboxInterval = time.Minute
+	DefaultOutboxMaxAge   = 24 * time.Hour
+)
+
+// Prefix for keys written to the shared cluster backend
+const DefaultClusterPrefix = "telegram-scout"
+
+// Leader election backends
+const (
+	LeaderBackendKubernetes = "kubernetes" // coordination.k8s.io Lease
+)
+
+// Leader election defaults
+const (
+	DefaultLeaderLeaseName     = "telegram-scout"
+	DefaultLeaderLeaseDuration = 15 * time.Second
+	DefaultLeaderRenewInterval = 5 * time.Second
+)
+
+// Styles of message links included in alerts
+const (
+	LinkStyleWeb  = "web"  // https://t.me links
+	LinkStyleDeep = "deep" // tg:// links that open the app directly
+)
+
+// Formats of the application log
+const (
+	LogFormatConsole = "console" // Human readable lines
+	LogFormatJSON    = "json"    // One JSON object per entry, for log aggregation
+)
+
+// Roles granted to bot command users and admin API tokens
+const (
+	RoleViewer = "viewer" // Read-only commands and endpoints
+	RoleAdmin  = "admin"  // Also changes, such as snoozes and alert states
 )
 
 // Define the structure of the YAML config file
 type MonitoringRules struct {
-	Chats    []string `yaml:"chats"`
-	Keywords []string `yaml:"keywords"`
+	Chats        []string    `yaml:"chats"`         // AllChats monitors every chat the account is in
+	ExcludeChats []string    `yaml:"exclude_chats"` // Never monitored, even when matched by AllChats
+	MentionsOnly bool        `yaml:"mentions_only"` // Chats only matched by AllChats emit messages with mentions alone
+	Keywords     []string    `yaml:"keywords"`
+	Shadow       []string    `yaml:"shadow"`   // Keywords counted and archived without alerting
+	Counters     []string    `yaml:"counters"` // Keywords only counted, for long-term statistics
+	Images       []ImageRule `yaml:"images"`
+	Files        []FileRule  `yaml:"files"`
+
+	// Labels keyed by rule as alerts report it, or a glob such as "file:*"
+	Tags map[string][]string `yaml:"tags"`
+
+	// Alert priority keyed the same way, overriding the priority of the chat
+	Priorities map[string]string `yaml:"priorities"`
+
+	// When rules keyed the same way stop matching, a date alone meaning its start in UTC
+	Expires map[string]time.Time `yaml:"expires"`
+}
+
+// Match images perceptually similar to a reference image
+type ImageRule struct {
+	Name        string `yaml:"name"`         // Reported as the matched rule, defaults to the file name
+	Path        string `yaml:"path"`         // Reference image, or a directory of them
+	MaxDistance int    `yaml:"max_distance"` // Differing bits tolerated between 64-bit hashes
+}
+
+// Skip messages before rule evaluation
+type FiltersConfig struct {
+	IgnoreOutgoing bool `yaml:"ignore_outgoing"` // Messages sent by the monitoring account
+	IgnoreBots     bool `yaml:"ignore_bots"`     // Messages from bot accounts
+
+	// Text length bounds in characters, zero disables a bound
+	MinLength int `yaml:"min_length"`
+	MaxLength int `yaml:"max_length"`
+
+	SkipEmojiOnly bool `yaml:"skip_emoji_only"` // Messages that are only emoji or stickers
+	SkipLinkOnly  bool `yaml:"skip_link_only"`  // Messages that are only links
+
+	// Messages sent longer ago than MaxAge, as delivered late after a gap in
+	// updates, are handled as Stale says. Zero disables.
+	MaxAge time.Duration `yaml:"max_age"`
+	Stale  string        `yaml:"stale"` // One of the Stale constants, StaleDrop by default
+}
+
+// Handling of messages older than max_age
+const (
+	StaleDrop  = "drop"  // Skip them like other filtered messages
+	StaleLabel = "label" // Alert on matches silently, labeled as stale
+)
+
+// How alerts on matches are delivered
+const (
+	PriorityLow    = "low"    // Only ever summarized in the digest
+	PriorityNormal = "normal" // Sent as they happen, the default
+	PriorityHigh   = "high"   // Sent at once with sound, past silent destinations, coalescing and the rate limit
+)
+
+// Override settings for one chat
+type ChatSettings struct {
+	Filters  FiltersConfig // Global filters with the chat's overrides applied
+	Priority string        // One of the Priority constants, empty for normal
+}
+
+// Per-chat overrides as written in the YAML file
+type fileChatSettings struct {
+	Filters  yaml.Node `yaml:"filters"` // Decoded on top of the global filters
+	Priority string    `yaml:"priority"`
+}
+
+// Chats entry that monitors every chat
+const AllChats = "*"
+
+// Configure the local admin HTTP listener
+type AdminConfig struct {
+	Listen string `yaml:"listen"` // Empty disables the listener
+
+	// Runtime diagnostics (pprof and expvar)
+	Diagnostics       bool `yaml:"diagnostics"`
+	DiagnosticsRemote bool `yaml:"diagnostics_remote"` // Allow non-loopback clients
+}
+
+// Configure recording of match explanations
+type ExplainConfig struct {
+	Size  int  `yaml:"size"`  // Number of recent alerts kept in memory
+	Debug bool `yaml:"debug"` // Also record every rule evaluation and near-miss
+}
+
+// Tune the message pipeline between the client and Scout
+type PipelineConfig struct {
+	QueueSize int `yaml:"queue_size"` // Buffered messages awaiting evaluation
+
+	// Alerts held back while the notifier is failing or saturated
+	DigestSize     int           `yaml:"digest_size"`
+	DigestInterval time.Duration `yaml:"digest_interval"` // How often delivery of the digest is attempted
+
+	// Warn when an alert is delivered longer than this after the message was posted, zero disables
+	LatencyBudget time.Duration `yaml:"latency_budget"`
+
+	// After the client restarts, the history of every resolved chat is read
+	// back this far and matched like live messages, zero disables
+	CatchUp         time.Duration `yaml:"catch_up"`
+	CatchUpMessages int           `yaml:"catch_up_messages"` // Most messages read back per chat
+}
+
+// Tune alert delivery
+type NotifierConfig struct {
+	APIURL        string          `yaml:"api_url"`        // Bot API server, a self-hosted telegram-bot-api instance for instance
+	Chats         []ChatTarget    `yaml:"chats"`          // Destinations besides TELEGRAM_CHAT_ID
+	Stdout        bool            `yaml:"stdout"`         // Also print alerts as plain text
+	StdoutProfile string          `yaml:"stdout_profile"` // Formatting profile of printed alerts
+	Account       AccountConfig   `yaml:"account"`
+	Concurrency   int             `yaml:"concurrency"` // Maximum in-flight notifications
+	RateLimit     int             `yaml:"rate_limit"`  // Maximum messages per minute, zero disables
+	Coalesce      CoalesceConfig  `yaml:"coalesce"`
+	Retry         RetryConfig     `yaml:"retry"`
+	HTTP          HTTPConfig      `yaml:"http"`
+	Webhooks      []WebhookConfig `yaml:"webhooks"`
+	Outbox        OutboxConfig    `yaml:"outbox"`
+
+	// Announce the process starting, on by default, and stopping, off by default
+	Startup  LifecycleConfig `yaml:"startup"`
+	Shutdown LifecycleConfig `yaml:"shutdown"`
+}
+
+// A notification sent when the process starts or stops
+type LifecycleConfig struct {
+	Enabled  bool   `yaml:"enabled"`
+	Template string `yaml:"template"` // Go template replacing the built-in HTML message
+}
+
+// Parse modes for alert destinations
+const (
+	ParseModeHTML = "html"
+	ParseModeText = "text"
+)
+
+// Formatting profiles, choosing which fields and how much text a destination gets
+const (
+	ProfileCompact  = "compact"  // One line with the rule, chat and link
+	ProfileStandard = "standard" // The usual alert
+	ProfileVerbose  = "verbose"  // The usual alert plus IDs, sender, date and the full text
+	ProfileRawJSON  = "raw-json" // The match as JSON, the same document webhooks receive
+)
+
+// Webhook body encodings
+const (
+	EncodingJSON     = "json"     // The match as JSON, or the template output
+	EncodingProtobuf = "protobuf" // The Match message of api/match.proto
+	EncodingMsgpack  = "msgpack"  // The JSON document's fields, as MessagePack
+)
+
+// A chat or channel receiving alerts
+type ChatTarget struct {
+	ID        int64  `yaml:"id"`
+	ParseMode string `yaml:"parse_mode"` // html, or text to send alerts without markup
+	Silent    bool   `yaml:"silent"`     // Deliver without a notification sound
+	Profile   string `yaml:"profile"`    // Formatting profile, standard when empty
+}
+
+// Deliver alerts as the monitoring account itself, through its MTProto session
+type AccountConfig struct {
+	Enabled bool   `yaml:"enabled"` // Fall back to the account when the Bot API fails, or use it alone without a bot token
+	Chat    string `yaml:"chat"`    // Chat reference in the same forms as chats, Saved Messages when empty
+	Silent  bool   `yaml:"silent"`  // Deliver without a notification sound
+	Profile string `yaml:"profile"` // Formatting profile, standard when empty
+}
+
+// Outbound HTTP settings for the Bot API and webhooks
+type HTTPConfig struct {
+	Proxy   string        `yaml:"proxy"`   // http, https, socks5 or socks5h URL, the environment's proxy when empty
+	CAFile  string        `yaml:"ca_file"` // PEM certificates trusted on top of the system roots
+	Timeout time.Duration `yaml:"timeout"` // Per request, each backend's own default when zero
+}
+
+// Retry failed sends and stop calling backends that keep failing, for every notifier backend
+type RetryConfig struct {
+	Attempts  int           `yaml:"attempts"`   // Tries per message, including the first
+	BaseDelay time.Duration `yaml:"base_delay"` // Wait before the first retry, doubled on each one and jittered
+	MaxDelay  time.Duration `yaml:"max_delay"`  // Cap on the wait between tries
+
+	BreakerThreshold int           `yaml:"breaker_threshold"` // Consecutive failed messages before sends are refused
+	BreakerCooldown  time.Duration `yaml:"breaker_cooldown"`  // How long sends are refused before a trial one
+}
+
+// Deliver matches of selected rules to an HTTP endpoint
+type WebhookConfig struct {
+	Name     string            `yaml:"name"` // Used in logs, defaults to the URL
+	URL      string            `yaml:"url"`
+	Method   string            `yaml:"method"` // Defaults to POST
+	Headers  map[string]string `yaml:"headers"`
+	Rules    []string          `yaml:"rules"`    // Reported rule names or globs over them, empty matches every rule
+	Template string            `yaml:"template"` // Go template rendering the body, the match as JSON when empty
+	Encoding string            `yaml:"encoding"` // Body encoding without a template, json when empty
+}
+
+// Keep webhook deliveries in the state directory until the endpoint accepts them
+type OutboxConfig struct {
+	Enabled  bool          `yaml:"enabled"`
+	Interval time.Duration `yaml:"interval"` // Between attempts to resend pending deliveries
+	MaxAge   time.Duration `yaml:"max_age"`  // Pending deliveries older than this are dropped
+}
+
+// Merge bursts of alerts into combined messages
+type CoalesceConfig struct {
+	Window    time.Duration `yaml:"window"`    // Collection window, zero disables coalescing
+	Threshold int           `yaml:"threshold"` // Alerts pending within a window before merging
+}
+
+// Tune duplicate message suppression
+type DedupConfig struct {
+	Window int `yaml:"window"` // Recent message IDs remembered per chat
+
+	// Cross-chat suppression of identical text
+	ContentHash     bool          `yaml:"content_hash"`
+	TTL             time.Duration `yaml:"ttl"`              // How long a content hash is remembered
+	MaxEntries      int           `yaml:"max_entries"`      // Cap before least recently used hashes are evicted
+	CleanupInterval time.Duration `yaml:"cleanup_interval"` // How often expired hashes are swept
+}
+
+// Choose how the application logs
+type LogConfig struct {
+	Format string                   `yaml:"format"` // LogFormatConsole or LogFormatJSON
+	Level  zapcore.Level            `yaml:"level"`  // Of everything without a level of its own
+	Levels map[string]zapcore.Level `yaml:"levels"` // Per subsystem, see logger.Subsystems
+
+	// Drop repeats of the same entry, zero Initial logs everything
+	Sampling LogSampling `yaml:"sampling"`
+
+	// Append raw incoming updates to updates.jsonl in the state directory,
+	// for the replay command
+	RecordUpdates bool `yaml:"record_updates"`
+}
+
+// Log the first Initial entries with the same level and message each second,
+// then every Thereafter-th
+type LogSampling struct {
+	Initial    int `yaml:"initial"`
+	Thereafter int `yaml:"thereafter"`
+}
+
+// Choose how links to matched messages are built
+type LinksConfig struct {
+	Style string `yaml:"style"` // LinkStyleWeb or LinkStyleDeep
+}
+
+// Share dedup, rate limiting and digests between instances
+type ClusterConfig struct {
+	Redis  string `yaml:"redis"`  // redis:// URL, empty keeps state local
+	Prefix string `yaml:"prefix"` // Namespace for keys, shared by the whole fleet
+}
+
+// Upload media of matched messages to S3-compatible storage
+type MediaArchiveConfig struct {
+	Endpoint  string `yaml:"endpoint"` // host[:port] of the S3 API, empty disables archiving
+	Bucket    string `yaml:"bucket"`
+	Region    string `yaml:"region"`
+	Insecure  bool   `yaml:"insecure"`   // Use plain HTTP, e.g. for a local MinIO
+	Prefix    string `yaml:"prefix"`     // Prepended to object keys
+	PublicURL string `yaml:"public_url"` // Base URL of the bucket, presigned URLs are used when empty
+	MaxSize   int64  `yaml:"max_size"`   // Larger files are not archived
+
+	// Credentials, preferably set through the environment
+	AccessKey string `yaml:"access_key"`
+	SecretKey string `yaml:"secret_key"`
+}
+
+// Hash documents of matched messages and look them up with threat intelligence services
+type EnrichmentConfig struct {
+	Enabled bool          `yaml:"enabled"`
+	MaxSize int64         `yaml:"max_size"` // Larger files are not hashed
+	Timeout time.Duration `yaml:"timeout"`  // Per lookup
+
+	// API keys, preferably set through the environment. Each enables its service.
+	VirusTotalKey    string `yaml:"virustotal_key"`
+	MalwareBazaarKey string `yaml:"malwarebazaar_key"`
+}
+
+// Inspect links in messages
+type URLsConfig struct {
+	Expand       bool          `yaml:"expand"`        // Follow short links and add their destination to alerts
+	Shorteners   []string      `yaml:"shorteners"`    // Hosts treated as shorteners besides the built-in ones
+	MaxRedirects int           `yaml:"max_redirects"` // Hops followed per link
+	Timeout      time.Duration `yaml:"timeout"`       // Per hop
+
+	BlockDomains []string `yaml:"block_domains"` // Alert on links to these domains or their subdomains
+	AllowDomains []string `yaml:"allow_domains"` // Skip messages whose links all point to these
+}
+
+// Render pages linked from matched messages through an external service
+type ScreenshotConfig struct {
+	Endpoint string        `yaml:"endpoint"`  // Rendering service URL, empty disables screenshots
+	MaxLinks int           `yaml:"max_links"` // Links rendered per alert
+	Timeout  time.Duration `yaml:"timeout"`   // Per page
+}
+
+// Report other chats referenced by t.me links and @mentions
+type MentionsConfig struct {
+	Enabled  bool          `yaml:"enabled"`
+	Cooldown time.Duration `yaml:"cooldown"` // Each referenced chat is reported once per period
+}
+
+// Track which chats forward from and mention which others
+type GraphConfig struct {
+	Enabled      bool          `yaml:"enabled"`
+	SaveInterval time.Duration `yaml:"save_interval"` // How often the graph is written to the state directory
+}
+
+// Track alert acknowledgement and remind about critical alerts nobody took
+type AcksConfig struct {
+	Enabled      bool          `yaml:"enabled"`
+	Size         int           `yaml:"size"`          // Alerts tracked, the oldest are forgotten
+	RemindAfter  time.Duration `yaml:"remind_after"`  // Interval between reminders about new critical alerts
+	MaxReminders int           `yaml:"max_reminders"` // Reminders per alert, zero disables reminders
+	CriticalTags []string      `yaml:"critical_tags"` // Tags making an alert critical, every alert when empty
+	Buttons      bool          `yaml:"buttons"`       // Add acknowledge and resolve buttons to bot alerts
+	Snooze       time.Duration `yaml:"snooze"`        // Period of the snooze button added next to them, hidden when 0
+}
+
+// Answer commands sent to the bot in the alert chats
+type CommandsConfig struct {
+	Enabled     bool `yaml:"enabled"`
+	SearchLimit int  `yaml:"search_limit"` // Results shown by /search and inline queries
+	RateLimit   int  `yaml:"rate_limit"`   // Commands and inline queries answered per user each minute
+
+	// Answer "@bot term" from any chat with recent matches, needs access.users
+	// and inline mode enabled in @BotFather
+	Inline bool `yaml:"inline"`
+}
+
+// Search the history of monitored chats on a schedule, alerting on new results
+type SweepConfig struct {
+	Name     string  `yaml:"name"`     // Reported as the rule sweep:<name>
+	Query    string  `yaml:"query"`    // Text for Telegram's server-side search
+	Schedule string  `yaml:"schedule"` // Cron expression, @hourly style macro or @every <duration>
+	Chats    []int64 `yaml:"chats"`    // Bot API IDs to search, every monitored chat when empty
+	Limit    int     `yaml:"limit"`    // Newest results fetched per search
+}
+
+// Copy matched messages verbatim into another chat as the account, building a
+// filtered mirror of the monitored chats. Either rules or chats is required,
+// and a message must satisfy every one set.
+type MirrorConfig struct {
+	To    string   `yaml:"to"`    // Destination chat, in the same forms as chats
+	Rules []string `yaml:"rules"` // Rules as alerts report them, or globs such as "file:*"
+	Chats []string `yaml:"chats"` // Source chats, in the same forms as chats
+}
+
+// Act on matched messages as the account, for workflows such as bookmarking
+// every matched deal
+type ActionsConfig struct {
+	RateLimit int          `yaml:"rate_limit"` // Actions run per hour, further ones are dropped, DefaultActionRateLimit by default
+	Confirm   bool         `yaml:"confirm"`    // Hold actions until confirmed with /confirm instead of running them
+	Hooks     []ActionHook `yaml:"hooks"`
+}
+
+// Post a canary message into a monitored test chat on an interval and check
+// it is received, recognised and alerted on within a timeout
+type CanaryConfig struct {
+	Chat     string        `yaml:"chat"`     // Monitored test chat, in the same forms as chats, empty disables
+	Via      string        `yaml:"via"`      // CanaryViaAccount by default, or CanaryViaBot
+	Interval time.Duration `yaml:"interval"` // DefaultCanaryInterval by default
+	Timeout  time.Duration `yaml:"timeout"`  // DefaultCanaryTimeout by default
+}
+
+// Senders of the canary message
+const (
+	CanaryViaAccount = "account"
+	CanaryViaBot     = "bot"
+)
+
+// Actions run on matched messages
+const (
+	ActionReact   = "react"   // React with Emoji
+	ActionSave    = "save"    // Forward to Saved Messages
+	ActionForward = "forward" // Forward to To
+)
+
+// Run an action on the messages a rule matches. Either rules or chats is
+// required, and a message must satisfy every one set.
+type ActionHook struct {
+	Do    string   `yaml:"do"`    // One of the Action constants
+	Emoji string   `yaml:"emoji"` // Reaction, for ActionReact
+	To    string   `yaml:"to"`    // Destination chat in the same forms as chats, for ActionForward
+	Rules []string `yaml:"rules"` // Rules as alerts report them, or globs such as "file:*"
+	Chats []string `yaml:"chats"` // Source chats, in the same forms as chats
+}
+
+// Poll specific messages and users, alerting when messages are edited or
+// deleted and when users change their profile
+type WatchConfig struct {
+	Messages []string      `yaml:"messages"` // t.me message links
+	Users    []string      `yaml:"users"`    // @username, or the ID of a user the account has a chat with
+	Interval time.Duration `yaml:"interval"` // Between polls of every message and user
+}
+
+// Record when specific users go online and offline, optionally alerting
+type PresenceConfig struct {
+	Users      []int64       `yaml:"users"`       // User IDs, only those sharing a chat or contact with the account report status
+	History    int           `yaml:"history"`     // Status changes kept per user
+	AlertEvery time.Duration `yaml:"alert_every"` // Least time between alerts about one user, zero records without alerting
+}
+
+// Check the monitoring account for restrictions, freezes and spam limits, alerting on changes
+type StandingConfig struct {
+	Enabled  bool          `yaml:"enabled"`
+	Interval time.Duration `yaml:"interval"` // Between checks
+	SpamBot  bool          `yaml:"spambot"`  // Also ask @SpamBot, which sends it a message on every check
+}
+
+// Push the counters served at /debug/vars, for environments nothing scrapes
+type MetricsConfig struct {
+	Interval    time.Duration `yaml:"interval"`    // Between pushes
+	Pushgateway string        `yaml:"pushgateway"` // Prometheus Pushgateway URL, empty disables
+	Job         string        `yaml:"job"`         // Pushgateway job name
+	StatsD      string        `yaml:"statsd"`      // StatsD or Datadog agent host:port, empty disables
+	Prefix      string        `yaml:"prefix"`      // Prepended to every metric name
+}
+
+// Tell apart the outputs of several instances, attached to alerts, webhook
+// payloads, metrics and logs
+type InstanceConfig struct {
+	Name   string            // The host name in metrics when empty
+	Labels map[string]string // Label names follow Prometheus rules
+}
+
+// Mark restarts, reconnects and alert storms on Grafana dashboards. Events are
+// also counted at /debug/vars under "events".
+type AnnotationsConfig struct {
+	Grafana      string   `yaml:"grafana"`       // Grafana URL, empty only counts events
+	Token        string   `yaml:"token"`         // Service account token
+	DashboardUID string   `yaml:"dashboard_uid"` // Annotate one dashboard, every dashboard of the org when empty
+	Tags         []string `yaml:"tags"`          // Added to every annotation
+
+	// Alerts within StormWindow that make a storm, zero disables detection
+	StormThreshold int           `yaml:"storm_threshold"`
+	StormWindow    time.Duration `yaml:"storm_window"`
+}
+
+// Keep matched, and optionally all, messages in daily JSON Lines files under
+// the state directory. Writes are batched off the message path.
+type ArchiveConfig struct {
+	Enabled       bool          `yaml:"enabled"`
+	All           bool          `yaml:"all"`            // Archive every message received, not only matches
+	Buffer        int           `yaml:"buffer"`         // Records held in memory, further ones spill to disk
+	BatchSize     int           `yaml:"batch_size"`     // Records written at once
+	FlushInterval time.Duration `yaml:"flush_interval"` // Longest a record waits in memory
+	SpillMaxSize  int64         `yaml:"spill_max_size"` // Bytes spilled to disk, further records are dropped
+
+	// Remove duplicate records this often, zero compacts only on demand
+	CompactInterval time.Duration `yaml:"compact_interval"`
+
+	// Write to ClickHouse instead of day files, when its URL is set
+	ClickHouse ClickHouseConfig `yaml:"clickhouse"`
+
+	// Send a report of the archived alerts on a schedule
+	Summary SummaryConfig `yaml:"summary"`
+}
+
+// Report top rules, top chats, alert counts and spikes over the last day and week
+type SummaryConfig struct {
+	Schedule string `yaml:"schedule"` // Cron expression or @every interval, empty disables
+	Top      int    `yaml:"top"`      // Rules and chats listed, DefaultSummaryTop by default
+}
+
+// Keep archived records in a ClickHouse table, through its HTTP interface
+type ClickHouseConfig struct {
+	URL      string `yaml:"url"`      // e.g. http://clickhouse:8123
+	Database string `yaml:"database"` // DefaultClickHouseDatabase by default
+	Table    string `yaml:"table"`    // DefaultClickHouseTable by default, created on start
+	User     string `yaml:"user"`
+	Password string `yaml:"password"` // Preferably set through TELEGRAM_CLICKHOUSE_PASSWORD
+
+	// Acknowledge inserts once buffered by the server instead of written
+	NoWait bool `yaml:"no_wait"`
+}
+
+// Engines finding the first keyword matching a message
+const (
+	EngineAuto        = "auto"         // Pick one from the number and kind of keywords
+	EngineNaive       = "naive"        // Try keywords one by one
+	EngineAhoCorasick = "aho-corasick" // Find every single-word keyword in one pass
+	EngineRegex       = "regex"        // Skip messages no keyword matches with one combined regex
+	EngineHyperscan   = "hyperscan"    // Intel Hyperscan, only in builds linking the library
+)
+
+// How the rule reported for a message is chosen when several match it
+const (
+	MatchFirst    = "first"    // The first in config order: keywords, then blocked domains, then file rules
+	MatchPriority = "priority" // The most urgent under priorities, config order among equals
+	MatchAll      = "all"      // The first in config order, listing every other rule that matched
+)
+
+// Languages whose Snowball stemmer keywords can be matched with
+var StemmingLanguages = []string{
+	"danish", "dutch", "english", "finnish", "french", "german", "hungarian", "italian",
+	"norwegian", "portuguese", "romanian", "russian", "spanish", "swedish", "turkish",
+}
+
+// Languages whose stopwords phrases can skip
+var StopwordLanguages = []string{"english", "portuguese", "spanish"}
+
+// Choose how keywords are matched. Every engine reports the same matches.
+type MatchingConfig struct {
+	Engine string `yaml:"engine"` // One of the Engine constants, EngineAuto by default
+	Mode   string `yaml:"mode"`   // One of the Match constants, MatchFirst by default
+
+	// Reuse the result for text seen again within CacheTTL, zero disables
+	CacheTTL     time.Duration `yaml:"cache_ttl"`
+	CacheEntries int           `yaml:"cache_entries"` // Cap before least recently used results are evicted
+
+	// Evaluate reloaded keywords next to the live ones for this long, reporting
+	// how their matches differ, before switching to them. Zero switches at once.
+	Soak time.Duration `yaml:"soak"`
+
+	// Longest a "pcre:" keyword may spend on one message. Backtracking is not
+	// linear-time, a match running over counts as no match.
+	PCRETimeout time.Duration `yaml:"pcre_timeout"`
+
+	// Also match plain keywords against other forms of their words, reducing
+	// both to their stems in each of these languages, e.g. "sell" matching
+	// "sells" or "vender" matching "vendendo". Empty disables stemming.
+	Stemming []string `yaml:"stemming"`
+
+	// Let the words of phrase keywords be separated by, and skip their own,
+	// stopwords of these languages, e.g. "looking for a 5070" matching
+	// "looking 5070" and the other way around. Empty disables it.
+	Stopwords []string `yaml:"stopwords"`
+}
+
+// Restrict bot commands to known users and the admin API to known tokens.
+// Without users, everyone in the alert chats may do everything, and without
+// tokens, everyone on the admin listener may.
+type AccessConfig struct {
+	Users  map[int64]string `yaml:"users"`  // Telegram user ID to RoleViewer or RoleAdmin
+	Tokens []AccessToken    `yaml:"tokens"` // Bearer tokens accepted by the admin API
+}
+
+// A bearer token for the admin API
+type AccessToken struct {
+	Name  string `yaml:"name"` // Recorded as who made a change
+	Token string `yaml:"token"`
+	Role  string `yaml:"role"`
+}
+
+// Report whether any users or tokens are configured
+func (a AccessConfig) Enabled() bool {
+	return len(a.Users) > 0 || len(a.Tokens) > 0
+}
+
+// Record runtime changes such as snoozes and alert acknowledgements in an append-only log
+type AuditConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// Join chats from invite and public links, paced so the account is not limited
+type JoinConfig struct {
+	Invites  []string      `yaml:"invites"`   // t.me/+hash, t.me/joinchat/hash or t.me/username links
+	MinDelay time.Duration `yaml:"min_delay"` // Least random wait before each join
+	MaxDelay time.Duration `yaml:"max_delay"` // Most random wait before each join
+	DailyCap int           `yaml:"daily_cap"` // Joins per day, the rest wait for the next day
+}
+
+// Add curated rule packs to the configured rules
+type PacksConfig struct {
+	Enabled []string `yaml:"enabled"` // Pack names, see telegram-scout rules packs
+	Index   string   `yaml:"index"`   // Where telegram-scout rules update looks for newer packs
+}
+
+// Run several replicas where only the elected leader connects to Telegram
+type LeaderConfig struct {
+	Backend       string        `yaml:"backend"`        // Empty disables election
+	Name          string        `yaml:"name"`           // Lock name shared by all replicas
+	Namespace     string        `yaml:"namespace"`      // Kubernetes namespace, defaults to the pod's own
+	Identity      string        `yaml:"identity"`       // Unique per replica, defaults to the hostname
+	LeaseDuration time.Duration `yaml:"lease_duration"` // How long a lease is valid without renewal
+	RenewInterval time.Duration `yaml:"renew_interval"` // How often the leader renews and candidates retry
+}
+
+// Mirror the layout of the YAML config file
+type fileConfig struct {
+	MonitoringRules `yaml:",inline"`
+	StateDir        string             `yaml:"state_dir"`
+	InstanceName    string             `yaml:"instance_name"`
+	Labels          map[string]string  `yaml:"labels"`
+	Admin           AdminConfig        `yaml:"admin"`
+	Explain         ExplainConfig      `yaml:"explain"`
+	Pipeline        PipelineConfig     `yaml:"pipeline"`
+	Notifier        NotifierConfig     `yaml:"notifier"`
+	Dedup           DedupConfig        `yaml:"dedup"`
+	Links           LinksConfig        `yaml:"links"`
+	Leader          LeaderConfig       `yaml:"leader"`
+	Cluster         ClusterConfig      `yaml:"cluster"`
+	Filters         FiltersConfig      `yaml:"filters"`
+	MediaArchive    MediaArchiveConfig `yaml:"media_archive"`
+	Enrichment      EnrichmentConfig   `yaml:"enrichment"`
+	URLs            URLsConfig         `yaml:"urls"`
+	Screenshots     ScreenshotConfig   `yaml:"screenshots"`
+	Mentions        MentionsConfig     `yaml:"mentions"`
+	Graph           GraphConfig        `yaml:"graph"`
+	Acks            AcksConfig         `yaml:"acks"`
+	Commands        CommandsConfig     `yaml:"commands"`
+	Sweeps          []SweepConfig      `yaml:"sweeps"`
+	Mirrors         []MirrorConfig     `yaml:"mirrors"`
+	Actions         ActionsConfig      `yaml:"actions"`
+	Canary          CanaryConfig       `yaml:"canary"`
+	Packs           PacksConfig        `yaml:"packs"`
+	Watch           WatchConfig        `yaml:"watch"`
+	Presence        PresenceConfig     `yaml:"presence"`
+	Join            JoinConfig         `yaml:"join"`
+	Standing        StandingConfig     `yaml:"standing"`
+	Log             LogConfig          `yaml:"log"`
+	Audit           AuditConfig        `yaml:"audit"`
+	Access          AccessConfig       `yaml:"access"`
+	Metrics         MetricsConfig      `yaml:"metrics"`
+	Annotations     AnnotationsConfig  `yaml:"annotations"`
+	Archive         ArchiveConfig      `yaml:"archive"`
+	Matching        MatchingConfig     `yaml:"matching"`
+
+	ChatSettings map[string]fileChatSettings `yaml:"chat_settings"`
+}
+
+// Credentials of a second account that monitors the same chats, so alerts
+// survive the first one being logged out or banned
+type SpareAccount struct {
+	Phone    string
+	Password string
+	Session  string
 }
 
 // Hold all application configuration
@@ -41,13 +876,57 @@ type Config struct {
 	Password string // 2FA Cloud Password
 	Session  string
 
+	// Hot spare account monitoring the same chats, disabled without a phone
+	Spare   SpareAccount
+	IsSpare bool // This config logs in as the spare account
+
 	// Bot Credentials
 	BotToken string
-	ChatID   int64
+	ChatID   int64 // First of Notifier.Chats
 
 	// Logic Configuration
 	Monitoring     MonitoringRules
 	ConfigFilePath string
+	StateDir       string // Where session and other runtime state is written
+	Instance       InstanceConfig
+
+	// Runtime Configuration
+	Admin    AdminConfig
+	Explain  ExplainConfig
+	Pipeline PipelineConfig
+	Notifier NotifierConfig
+	Dedup    DedupConfig
+	Links    LinksConfig
+	Leader   LeaderConfig
+	Cluster  ClusterConfig
+	Filters  FiltersConfig
+	Media    MediaArchiveConfig
+	Enrich   EnrichmentConfig
+	URLs     URLsConfig
+	Shots    ScreenshotConfig
+	Mentions MentionsConfig
+	Graph    GraphConfig
+	Acks     AcksConfig
+	Commands CommandsConfig
+	Sweeps   []SweepConfig
+	Mirrors  []MirrorConfig
+	Actions  ActionsConfig
+	Canary   CanaryConfig
+	Packs    PacksConfig
+	Watch    WatchConfig
+	Presence PresenceConfig
+	Join     JoinConfig
+	Standing StandingConfig
+	Log      LogConfig
+	Audit    AuditConfig
+	Access   AccessConfig
+	Metrics  MetricsConfig
+	Annotate AnnotationsConfig
+	Archive  ArchiveConfig
+	Matching MatchingConfig
+
+	// Keyed by chat reference, in the same forms as chats
+	ChatSettings map[string]ChatSettings
 }
 
 // Populate Config from environment variables and YAML file
@@ -73,55 +952,919 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("TELEGRAM_PHONE is required")
 	}
 
-	// Bot Configuration
-	botToken := os.Getenv("TELEGRAM_BOT_TOKEN")
-	if botToken == "" {
-		return nil, fmt.Errorf("TELEGRAM_BOT_TOKEN is required for notifications")
+	// Load Rules from YAML
+	cfg, err := LoadFile(FilePath())
+	if err != nil {
+		return nil, err
 	}
 
-	chatIDStr := os.Getenv("TELEGRAM_CHAT_ID")
-	if chatIDStr == "" {
-		return nil, fmt.Errorf("TELEGRAM_CHAT_ID is required for notifications")
+	// Bot Configuration, optional when another notifier is configured
+	botToken := os.Getenv("TELEGRAM_BOT_TOKEN")
+	if botToken == "" && !cfg.Notifier.hasOther() {
+		return nil, fmt.Errorf("no notifier configured: set TELEGRAM_BOT_TOKEN, or enable notifier.account, notifier.stdout or notifier.webhooks")
 	}
-	chatID, err := strconv.ParseInt(chatIDStr, 10, 64)
+
+	// Destinations from the environment join those in the YAML config
+	chatIDs, err := parseChatIDs(os.Getenv("TELEGRAM_CHAT_ID"))
 	if err != nil {
 		return nil, fmt.Errorf("invalid TELEGRAM_CHAT_ID: %w", err)
 	}
+	cfg.Notifier.Chats = mergeChatTargets(cfg.Notifier.Chats, chatIDs)
+	if botToken != "" && len(cfg.Notifier.Chats) == 0 {
+		return nil, fmt.Errorf("TELEGRAM_CHAT_ID is required for notifications")
+	}
 
-	// Load Rules from YAML
-	configPath := os.Getenv("TELEGRAM_CONFIG_FILE")
-	if configPath == "" {
-		configPath = "config.yaml"
+	cfg.AppID = appID
+	cfg.AppHash = appHash
+	cfg.Phone = phone
+	cfg.Password = os.Getenv("TELEGRAM_PASSWORD")
+	cfg.Session = os.Getenv("TELEGRAM_SESSION")
+	cfg.Spare = SpareAccount{
+		Phone:    os.Getenv("TELEGRAM_SPARE_PHONE"),
+		Password: os.Getenv("TELEGRAM_SPARE_PASSWORD"),
+		Session:  os.Getenv("TELEGRAM_SPARE_SESSION"),
+	}
+	cfg.BotToken = botToken
+	if len(cfg.Notifier.Chats) > 0 {
+		cfg.ChatID = cfg.Notifier.Chats[0].ID
+	}
+	return cfg, nil
+}
+
+// Report whether a notifier other than the bot is configured
+func (n NotifierConfig) hasOther() bool {
+	return n.Account.Enabled || n.Stdout || len(n.Webhooks) > 0
+}
+
+// Parse a comma separated list of chat IDs
+func parseChatIDs(s string) ([]int64, error) {
+	var ids []int64
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// Append ids not already configured as targets with the default settings
+func mergeChatTargets(targets []ChatTarget, ids []int64) []ChatTarget {
+	seen := make(map[int64]bool, len(targets))
+	for _, t := range targets {
+		seen[t.ID] = true
+	}
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			targets = append(targets, ChatTarget{ID: id, ParseMode: ParseModeHTML})
+		}
+	}
+	return targets
+}
+
+// Return the YAML config path from the environment, or the default
+func FilePath() string {
+	if path := os.Getenv("TELEGRAM_CONFIG_FILE"); path != "" {
+		return path
+	}
+	return "config.yaml"
+}
+
+// Populate Config from the YAML file only, without requiring credentials.
+// Used by CLI subcommands that talk to an already running instance.
+func LoadFile(path string) (*Config, error) {
+	file, err := loadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load monitoring rules from %s: %w", path, err)
+	}
+	if err := validateChats("chats", file.Chats); err != nil {
+		return nil, fmt.Errorf("invalid monitoring rules in %s: %w", path, err)
+	}
+	if err := validateChats("exclude_chats", file.ExcludeChats); err != nil {
+		return nil, fmt.Errorf("invalid monitoring rules in %s: %w", path, err)
+	}
+	if file.MentionsOnly && !slices.Contains(file.Chats, AllChats) {
+		return nil, fmt.Errorf("invalid monitoring rules in %s: mentions_only requires \"*\" in chats", path)
+	}
+	for i, img := range file.Images {
+		if img.Path == "" {
+			return nil, fmt.Errorf("invalid monitoring rules in %s: images entry %d: path is required", path, i)
+		}
+	}
+	for i, rule := range file.Files {
+		if err := validateFileRule(rule); err != nil {
+			return nil, fmt.Errorf("invalid monitoring rules in %s: files entry %d: %w", path, i, err)
+		}
+	}
+	for i, hook := range file.Notifier.Webhooks {
+		if hook.URL == "" {
+			return nil, fmt.Errorf("invalid notifier in %s: webhooks entry %d: url is required", path, i)
+		}
+		switch hook.Encoding {
+		case "", EncodingJSON:
+		case EncodingProtobuf, EncodingMsgpack:
+			if hook.Template != "" {
+				return nil, fmt.Errorf("invalid notifier in %s: webhooks entry %d: template requires encoding %q", path, i, EncodingJSON)
+			}
+		default:
+			return nil, fmt.Errorf("invalid notifier in %s: webhooks entry %d: encoding %q, expected %s, %s or %s", path, i, hook.Encoding, EncodingJSON, EncodingProtobuf, EncodingMsgpack)
+		}
+	}
+	for i, target := range file.Notifier.Chats {
+		if target.ID == 0 {
+			return nil, fmt.Errorf("invalid notifier in %s: chats entry %d: id is required", path, i)
+		}
+		switch target.ParseMode {
+		case "", ParseModeHTML, ParseModeText:
+		default:
+			return nil, fmt.Errorf("invalid notifier in %s: chats entry %d: parse_mode %q, expected %q or %q", path, i, target.ParseMode, ParseModeHTML, ParseModeText)
+		}
+		if err := validateProfile(target.Profile); err != nil {
+			return nil, fmt.Errorf("invalid notifier in %s: chats entry %d: %w", path, i, err)
+		}
+	}
+	if err := validateProfile(file.Notifier.Account.Profile); err != nil {
+		return nil, fmt.Errorf("invalid notifier.account in %s: %w", path, err)
+	}
+	if err := validateProfile(file.Notifier.StdoutProfile); err != nil {
+		return nil, fmt.Errorf("invalid notifier.stdout_profile in %s: %w", path, err)
+	}
+	if _, err := template.New("startup").Parse(file.Notifier.Startup.Template); err != nil {
+		return nil, fmt.Errorf("invalid notifier.startup.template in %s: %w", path, err)
+	}
+	if _, err := template.New("shutdown").Parse(file.Notifier.Shutdown.Template); err != nil {
+		return nil, fmt.Errorf("invalid notifier.shutdown.template in %s: %w", path, err)
+	}
+	if chat := file.Notifier.Account.Chat; chat != "" {
+		if _, err := chatid.Parse(chat); err != nil {
+			return nil, fmt.Errorf("invalid notifier.account.chat %q in %s: %w", chat, path, err)
+		}
+	}
+	if api := file.Notifier.APIURL; api != "" {
+		if u, err := url.Parse(api); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid notifier.api_url %q in %s: expected an http or https URL", api, path)
+		}
+	}
+	if proxy := file.Notifier.HTTP.Proxy; proxy != "" {
+		u, err := url.Parse(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid notifier.http.proxy in %s: %w", path, err)
+		}
+		switch u.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return nil, fmt.Errorf("invalid notifier.http.proxy %q in %s: expected an http, https, socks5 or socks5h URL", proxy, path)
+		}
+	}
+	if index := file.Packs.Index; index != "" {
+		if u, err := url.Parse(index); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid packs.index %q in %s: expected an http or https URL", index, path)
+		}
+	}
+	if gw := file.Metrics.Pushgateway; gw != "" {
+		if u, err := url.Parse(gw); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid metrics.pushgateway %q in %s: expected an http or https URL", gw, path)
+		}
+	}
+	if addr := file.Metrics.StatsD; addr != "" {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			return nil, fmt.Errorf("invalid metrics.statsd %q in %s: expected host:port", addr, path)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(file.Labels)) {
+		if err := validateLabel(name, file.Labels[name]); err != nil {
+			return nil, fmt.Errorf("invalid labels.%s in %s: %w", name, path, err)
+		}
+	}
+	if g := file.Annotations.Grafana; g != "" {
+		if u, err := url.Parse(g); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid annotations.grafana %q in %s: expected an http or https URL", g, path)
+		}
+	}
+	if file.Annotations.StormThreshold < 0 {
+		return nil, fmt.Errorf("invalid annotations.storm_threshold in %s: must not be negative", path)
+	}
+	if err := validateFilters(file.Filters); err != nil {
+		return nil, fmt.Errorf("invalid filters in %s: %w", path, err)
+	}
+	if file.Matching.Soak < 0 {
+		return nil, fmt.Errorf("invalid matching.soak in %s: must not be negative", path)
+	}
+	if file.Matching.PCRETimeout < 0 {
+		return nil, fmt.Errorf("invalid matching.pcre_timeout in %s: must not be negative", path)
+	}
+	for _, lang := range file.Matching.Stemming {
+		if !slices.Contains(StemmingLanguages, lang) {
+			return nil, fmt.Errorf("invalid matching.stemming language %q in %s: expected one of %s", lang, path, strings.Join(StemmingLanguages, ", "))
+		}
+	}
+	for _, lang := range file.Matching.Stopwords {
+		if !slices.Contains(StopwordLanguages, lang) {
+			return nil, fmt.Errorf("invalid matching.stopwords language %q in %s: expected one of %s", lang, path, strings.Join(StopwordLanguages, ", "))
+		}
+	}
+	if file.Pipeline.LatencyBudget < 0 {
+		return nil, fmt.Errorf("invalid pipeline.latency_budget in %s: must not be negative", path)
+	}
+	if file.Pipeline.CatchUp < 0 || file.Pipeline.CatchUpMessages < 0 {
+		return nil, fmt.Errorf("invalid pipeline in %s: catch_up and catch_up_messages must not be negative", path)
+	}
+	if a := file.Archive; a.Buffer < 0 || a.BatchSize < 0 || a.SpillMaxSize < 0 {
+		return nil, fmt.Errorf("invalid archive in %s: buffer, batch_size and spill_max_size must not be negative", path)
+	}
+	if ch := file.Archive.ClickHouse; ch.URL != "" {
+		if u, err := url.Parse(ch.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid archive.clickhouse.url %q in %s: expected an http or https URL", ch.URL, path)
+		}
+		for _, name := range []string{ch.Database, ch.Table} {
+			if name != "" && !isIdentifier(name) {
+				return nil, fmt.Errorf("invalid archive.clickhouse name %q in %s: use letters, digits and underscores", name, path)
+			}
+		}
+	}
+	if sum := file.Archive.Summary; sum.Schedule != "" {
+		if !file.Archive.Enabled {
+			return nil, fmt.Errorf("invalid archive.summary in %s: needs archive.enabled", path)
+		}
+		if _, err := schedule.Parse(sum.Schedule); err != nil {
+			return nil, fmt.Errorf("invalid archive.summary.schedule in %s: %w", path, err)
+		}
+		if sum.Top < 0 {
+			return nil, fmt.Errorf("invalid archive.summary.top in %s: must not be negative", path)
+		}
+	}
+	for _, link := range file.Watch.Messages {
+		if _, _, err := chatid.ParseLink(link); err != nil {
+			return nil, fmt.Errorf("invalid watch.messages in %s: %w", path, err)
+		}
+	}
+	for _, id := range file.Presence.Users {
+		if id <= 0 {
+			return nil, fmt.Errorf("invalid presence.users in %s: %d is not a user ID", path, id)
+		}
+	}
+	for _, user := range file.Watch.Users {
+		if ref, err := chatid.Parse(user); err != nil {
+			return nil, fmt.Errorf("invalid watch.users in %s: %w", path, err)
+		} else if ref.Kind == chatid.Channel || ref.Kind == chatid.Group {
+			return nil, fmt.Errorf("invalid watch.users in %s: %q is a chat ID, not a user", path, user)
+		}
+	}
+	for _, link := range file.Join.Invites {
+		if refs := mentions.Extract(link); len(refs) != 1 || !strings.Contains(link, "/") {
+			return nil, fmt.Errorf("invalid join.invites in %s: %q is not a t.me chat link", path, link)
+		}
+	}
+	if j := file.Join; j.MinDelay < 0 || j.MaxDelay < 0 || (j.MaxDelay > 0 && j.MinDelay > j.MaxDelay) {
+		return nil, fmt.Errorf("invalid join delays in %s: min_delay must not exceed max_delay", path)
+	}
+	if file.Join.DailyCap < 0 {
+		return nil, fmt.Errorf("invalid join.daily_cap in %s: must not be negative", path)
+	}
+	if err := validateMirrors(file.Mirrors); err != nil {
+		return nil, fmt.Errorf("invalid mirrors in %s: %w", path, err)
+	}
+	if err := validateActions(file.Actions); err != nil {
+		return nil, fmt.Errorf("invalid actions in %s: %w", path, err)
+	}
+	if file.Actions.Confirm && !file.Commands.Enabled {
+		return nil, fmt.Errorf("invalid actions.confirm in %s: needs commands.enabled to answer /confirm", path)
+	}
+	if err := validateCanary(file.Canary, file.Chats); err != nil {
+		return nil, fmt.Errorf("invalid canary in %s: %w", path, err)
+	}
+	if err := validateSweeps(file.Sweeps); err != nil {
+		return nil, fmt.Errorf("invalid sweeps in %s: %w", path, err)
+	}
+	switch file.Matching.Engine {
+	case "", EngineAuto, EngineNaive, EngineAhoCorasick, EngineRegex, EngineHyperscan:
+	default:
+		return nil, fmt.Errorf("invalid matching.engine %q in %s: expected one of %s", file.Matching.Engine, path, strings.Join([]string{EngineAuto, EngineNaive, EngineAhoCorasick, EngineRegex, EngineHyperscan}, ", "))
+	}
+	switch file.Matching.Mode {
+	case "", MatchFirst, MatchPriority, MatchAll:
+	default:
+		return nil, fmt.Errorf("invalid matching.mode %q in %s: expected %s, %s or %s", file.Matching.Mode, path, MatchFirst, MatchPriority, MatchAll)
+	}
+	switch file.Log.Format {
+	case "", LogFormatConsole, LogFormatJSON:
+	default:
+		return nil, fmt.Errorf("invalid log.format %q in %s: expected %q or %q", file.Log.Format, path, LogFormatConsole, LogFormatJSON)
+	}
+	for name := range file.Log.Levels {
+		if !slices.Contains(logger.Subsystems, name) {
+			return nil, fmt.Errorf("invalid log.levels in %s: unknown subsystem %q, expected one of %s", path, name, strings.Join(logger.Subsystems, ", "))
+		}
+	}
+	if file.Log.Sampling.Initial < 0 || file.Log.Sampling.Thereafter < 0 {
+		return nil, fmt.Errorf("invalid log.sampling in %s: must not be negative", path)
+	}
+	switch file.Links.Style {
+	case "", LinkStyleWeb, LinkStyleDeep:
+	default:
+		return nil, fmt.Errorf("invalid links.style %q in %s: expected %q or %q", file.Links.Style, path, LinkStyleWeb, LinkStyleDeep)
+	}
+	if file.Screenshots.Endpoint != "" && file.MediaArchive.Endpoint == "" {
+		return nil, fmt.Errorf("invalid screenshots in %s: media_archive.endpoint is required to store them", path)
+	}
+	if err := validateAccess(file.Access); err != nil {
+		return nil, fmt.Errorf("invalid access in %s: %w", path, err)
+	}
+	switch file.Leader.Backend {
+	case "", LeaderBackendKubernetes:
+	default:
+		return nil, fmt.Errorf("invalid leader.backend %q in %s: expected %q", file.Leader.Backend, path, LeaderBackendKubernetes)
 	}
 
-	rules, err := loadRules(configPath)
+	cfg := &Config{
+		Monitoring:     file.MonitoringRules,
+		ConfigFilePath: path,
+		StateDir:       file.StateDir,
+		Instance:       InstanceConfig{Name: file.InstanceName, Labels: file.Labels},
+		Admin:          file.Admin,
+		Explain:        file.Explain,
+		Pipeline:       file.Pipeline,
+		Notifier:       file.Notifier,
+		Dedup:          file.Dedup,
+		Links:          file.Links,
+		Leader:         file.Leader,
+		Cluster:        file.Cluster,
+		Filters:        file.Filters,
+		Media:          file.MediaArchive,
+		Enrich:         file.Enrichment,
+		URLs:           file.URLs,
+		Shots:          file.Screenshots,
+		Mentions:       file.Mentions,
+		Graph:          file.Graph,
+		Acks:           file.Acks,
+		Commands:       file.Commands,
+		Sweeps:         file.Sweeps,
+		Mirrors:        file.Mirrors,
+		Actions:        file.Actions,
+		Canary:         file.Canary,
+		Packs:          file.Packs,
+		Watch:          file.Watch,
+		Presence:       file.Presence,
+		Join:           file.Join,
+		Standing:       file.Standing,
+		Log:            file.Log,
+		Audit:          file.Audit,
+		Access:         file.Access,
+		Metrics:        file.Metrics,
+		Annotate:       file.Annotations,
+		Archive:        file.Archive,
+		Matching:       file.Matching,
+	}
+	for rule, p := range file.Priorities {
+		if err := validatePriority(p); err != nil {
+			return nil, fmt.Errorf("invalid priorities in %s: rule %q: %w", path, rule, err)
+		}
+	}
+	chats, err := chatSettings(file)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load monitoring rules from %s: %w", configPath, err)
+		return nil, fmt.Errorf("invalid chat_settings in %s: %w", path, err)
 	}
+	cfg.ChatSettings = chats
 
-	return &Config{
-		AppID:          appID,
-		AppHash:        appHash,
-		Phone:          phone,
-		Password:       os.Getenv("TELEGRAM_PASSWORD"),
-		Session:        os.Getenv("TELEGRAM_SESSION"),
-		BotToken:       botToken,
-		ChatID:         chatID,
-		Monitoring:     *rules,
-		ConfigFilePath: configPath,
-	}, nil
+	applyDefaults(cfg)
+	return cfg, nil
 }
 
-func loadRules(path string) (*MonitoringRules, error) {
+// Check that every user and token has a known role, and tokens are named and distinct
+func validateAccess(a AccessConfig) error {
+	validRole := func(role string) bool { return role == RoleViewer || role == RoleAdmin }
+	for id, role := range a.Users {
+		if !validRole(role) {
+			return fmt.Errorf("user %d: role %q, expected %q or %q", id, role, RoleViewer, RoleAdmin)
+		}
+	}
+	seen := make(map[string]bool, len(a.Tokens))
+	for i, t := range a.Tokens {
+		switch {
+		case t.Name == "":
+			return fmt.Errorf("token %d: name is required", i)
+		case t.Token == "":
+			return fmt.Errorf("token %q: token is required", t.Name)
+		case seen[t.Token]:
+			return fmt.Errorf("token %q: token is used twice", t.Name)
+		case !validRole(t.Role):
+			return fmt.Errorf("token %q: role %q, expected %q or %q", t.Name, t.Role, RoleViewer, RoleAdmin)
+		}
+		seen[t.Token] = true
+	}
+	return nil
+}
+
+// Reject filters that cannot apply
+func validateFilters(f FiltersConfig) error {
+	if f.MaxAge < 0 {
+		return errors.New("max_age must not be negative")
+	}
+	switch f.Stale {
+	case "", StaleDrop, StaleLabel:
+	default:
+		return fmt.Errorf("stale %q: expected %s or %s", f.Stale, StaleDrop, StaleLabel)
+	}
+	return nil
+}
+
+// Reject priorities other than the Priority constants
+func validatePriority(p string) error {
+	switch p {
+	case "", PriorityLow, PriorityNormal, PriorityHigh:
+		return nil
+	}
+	return fmt.Errorf("priority %q: expected %s, %s or %s", p, PriorityLow, PriorityNormal, PriorityHigh)
+}
+
+// Reject profiles other than the Profile constants
+func validateProfile(p string) error {
+	switch p {
+	case "", ProfileCompact, ProfileStandard, ProfileVerbose, ProfileRawJSON:
+		return nil
+	}
+	return fmt.Errorf("profile %q: expected %s, %s, %s or %s", p, ProfileCompact, ProfileStandard, ProfileVerbose, ProfileRawJSON)
+}
+
+// Resolve per-chat overrides against the global settings
+func chatSettings(file *fileConfig) (map[string]ChatSettings, error) {
+	settings := make(map[string]ChatSettings, len(file.ChatSettings))
+	for chat, fs := range file.ChatSettings {
+		if _, err := chatid.Parse(chat); err != nil {
+			return nil, fmt.Errorf("entry %q: %w", chat, err)
+		}
+
+		// Only keys present in the override replace the global value
+		cs := ChatSettings{Filters: file.Filters}
+		if !fs.Filters.IsZero() {
+			if err := fs.Filters.Decode(&cs.Filters); err != nil {
+				return nil, fmt.Errorf("entry %q: %w", chat, err)
+			}
+		}
+		if err := validateFilters(cs.Filters); err != nil {
+			return nil, fmt.Errorf("entry %q: %w", chat, err)
+		}
+		if err := validatePriority(fs.Priority); err != nil {
+			return nil, fmt.Errorf("entry %q: %w", chat, err)
+		}
+		cs.Priority = fs.Priority
+		settings[chat] = cs
+	}
+	return settings, nil
+}
+
+func loadFile(path string) (*fileConfig, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
 
-	var rules MonitoringRules
-	if err := yaml.Unmarshal(data, &rules); err != nil {
+	// Defaults for settings that are on unless turned off
+	file := fileConfig{Notifier: NotifierConfig{Startup: LifecycleConfig{Enabled: true}}}
+	if err := yaml.Unmarshal(data, &file); err != nil {
 		return nil, err
 	}
 
-	return &rules, nil
+	return &file, nil
+}
+
+// Reject chat entries that are neither a username nor a numeric ID, or a
+// phone number in the chats list
+func validateChats(field string, chats []string) error {
+	var errs []error
+	for _, c := range chats {
+		if c == AllChats && field == "chats" {
+			continue
+		}
+		if _, ok := chatid.ParsePhone(c); ok && field == "chats" {
+			continue
+		}
+		if _, err := chatid.Parse(c); err != nil {
+			errs = append(errs, fmt.Errorf("%s entry %q: %w", field, c, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Reject sweeps that are unnamed, duplicated, empty or never scheduled
+func validateSweeps(sweeps []SweepConfig) error {
+	names := make(map[string]bool, len(sweeps))
+	for i, sw := range sweeps {
+		switch {
+		case sw.Name == "":
+			return fmt.Errorf("entry %d: name is required", i)
+		case names[sw.Name]:
+			return fmt.Errorf("entry %d: duplicate name %q", i, sw.Name)
+		case strings.TrimSpace(sw.Query) == "":
+			return fmt.Errorf("%s: query is required", sw.Name)
+		}
+		names[sw.Name] = true
+		if _, err := schedule.Parse(sw.Schedule); err != nil {
+			return fmt.Errorf("%s: %w", sw.Name, err)
+		}
+	}
+	return nil
+}
+
+func validateMirrors(mirrors []MirrorConfig) error {
+	for i, m := range mirrors {
+		if _, err := chatid.Parse(m.To); err != nil {
+			return fmt.Errorf("entry %d: to: %w", i, err)
+		}
+		if len(m.Rules) == 0 && len(m.Chats) == 0 {
+			return fmt.Errorf("entry %d: rules or chats is required", i)
+		}
+		for _, chat := range m.Chats {
+			if _, err := chatid.Parse(chat); err != nil {
+				return fmt.Errorf("entry %d: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateActions(actions ActionsConfig) error {
+	if actions.RateLimit < 0 {
+		return errors.New("rate_limit must not be negative")
+	}
+	for i, h := range actions.Hooks {
+		switch h.Do {
+		case ActionReact:
+			if h.Emoji == "" {
+				return fmt.Errorf("hook %d: emoji is required to react", i)
+			}
+		case ActionSave:
+		case ActionForward:
+			if _, err := chatid.Parse(h.To); err != nil {
+				return fmt.Errorf("hook %d: to: %w", i, err)
+			}
+		default:
+			return fmt.Errorf("hook %d: do must be one of %s, %s or %s", i, ActionReact, ActionSave, ActionForward)
+		}
+		if len(h.Rules) == 0 && len(h.Chats) == 0 {
+			return fmt.Errorf("hook %d: rules or chats is required", i)
+		}
+		for _, chat := range h.Chats {
+			if _, err := chatid.Parse(chat); err != nil {
+				return fmt.Errorf("hook %d: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateCanary(canary CanaryConfig, chats []string) error {
+	if canary.Chat == "" {
+		return nil
+	}
+	if _, err := chatid.Parse(canary.Chat); err != nil {
+		return fmt.Errorf("chat: %w", err)
+	}
+	if !slices.Contains(chats, canary.Chat) && !slices.Contains(chats, AllChats) {
+		return fmt.Errorf("chat %q must be listed in chats", canary.Chat)
+	}
+	switch canary.Via {
+	case "", CanaryViaAccount, CanaryViaBot:
+	default:
+		return fmt.Errorf("via must be %s or %s", CanaryViaAccount, CanaryViaBot)
+	}
+	if canary.Interval < 0 || canary.Timeout < 0 {
+		return errors.New("interval and timeout must not be negative")
+	}
+	interval := canary.Interval
+	if interval == 0 {
+		interval = DefaultCanaryInterval
+	}
+	if canary.Timeout > interval {
+		return errors.New("timeout must not exceed interval")
+	}
+	return nil
+}
+
+// Reject file rules without criteria or with malformed globs
+func validateFileRule(rule FileRule) error {
+	if rule.Filename == "" && rule.MimeType == "" && rule.MinSize <= 0 && rule.MaxSize <= 0 {
+		return errors.New("at least one of filename, mime_type, min_size or max_size is required")
+	}
+	for _, pattern := range []string{rule.Filename, rule.MimeType} {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("pattern %q: %w", pattern, err)
+		}
+	}
+	if rule.MaxSize > 0 && rule.MinSize > rule.MaxSize {
+		return fmt.Errorf("min_size %d exceeds max_size %d", rule.MinSize, rule.MaxSize)
+	}
+	return nil
+}
+
+// Check an instance label, the name must also be valid in Prometheus and StatsD
+func validateLabel(name, value string) error {
+	switch {
+	case !isIdentifier(name) || strings.HasPrefix(name, "__"):
+		return errors.New("use letters, digits and underscores")
+	case name == "job" || name == "instance" || name == "key":
+		return fmt.Errorf("%q is set by the metrics exporter", name)
+	case value == "":
+		return errors.New("empty value")
+	}
+	return nil
+}
+
+// Report whether name is safe to use unquoted as an SQL identifier
+func isIdentifier(name string) bool {
+	for i, r := range name {
+		if r != '_' && (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (i == 0 || r < '0' || r > '9') {
+			return false
+		}
+	}
+	return name != ""
+}
+
+// Fill unset optional settings with their defaults
+func applyDefaults(cfg *Config) {
+	if dir := os.Getenv("TELEGRAM_STATE_DIR"); dir != "" {
+		cfg.StateDir = dir
+	}
+	if cfg.StateDir == "" {
+		cfg.StateDir = defaultStateDir()
+	}
+	if cfg.Explain.Size <= 0 {
+		cfg.Explain.Size = DefaultExplainSize
+	}
+	if cfg.Pipeline.QueueSize <= 0 {
+		cfg.Pipeline.QueueSize = DefaultQueueSize
+	}
+	if cfg.Pipeline.DigestSize <= 0 {
+		cfg.Pipeline.DigestSize = DefaultDigestSize
+	}
+	if cfg.Pipeline.DigestInterval <= 0 {
+		cfg.Pipeline.DigestInterval = DefaultDigestInterval
+	}
+	if cfg.Pipeline.CatchUpMessages <= 0 {
+		cfg.Pipeline.CatchUpMessages = DefaultCatchUpMessages
+	}
+	if cfg.Notifier.Concurrency <= 0 {
+		cfg.Notifier.Concurrency = DefaultNotifierConcurrency
+	}
+	if cfg.Notifier.Outbox.Interval <= 0 {
+		cfg.Notifier.Outbox.Interval = DefaultOutboxInterval
+	}
+	if cfg.Notifier.Outbox.MaxAge <= 0 {
+		cfg.Notifier.Outbox.MaxAge = DefaultOutboxMaxAge
+	}
+	if cfg.Notifier.Coalesce.Threshold <= 0 {
+		cfg.Notifier.Coalesce.Threshold = DefaultCoalesceThreshold
+	}
+	for i := range cfg.Notifier.Chats {
+		if cfg.Notifier.Chats[i].ParseMode == "" {
+			cfg.Notifier.Chats[i].ParseMode = ParseModeHTML
+		}
+	}
+	if api := os.Getenv("TELEGRAM_BOT_API_URL"); api != "" {
+		cfg.Notifier.APIURL = api
+	}
+	if cfg.Notifier.APIURL == "" {
+		cfg.Notifier.APIURL = DefaultBotAPIURL
+	}
+	if cfg.Notifier.Retry.Attempts <= 0 {
+		cfg.Notifier.Retry.Attempts = DefaultRetryAttempts
+	}
+	if cfg.Notifier.Retry.BaseDelay <= 0 {
+		cfg.Notifier.Retry.BaseDelay = DefaultRetryBaseDelay
+	}
+	if cfg.Notifier.Retry.MaxDelay <= 0 {
+		cfg.Notifier.Retry.MaxDelay = DefaultRetryMaxDelay
+	}
+	if cfg.Notifier.Retry.BreakerThreshold <= 0 {
+		cfg.Notifier.Retry.BreakerThreshold = DefaultBreakerThreshold
+	}
+	if cfg.Notifier.Retry.BreakerCooldown <= 0 {
+		cfg.Notifier.Retry.BreakerCooldown = DefaultBreakerCooldown
+	}
+	if cfg.Dedup.TTL <= 0 {
+		cfg.Dedup.TTL = DefaultDedupTTL
+	}
+	if cfg.Dedup.MaxEntries <= 0 {
+		cfg.Dedup.MaxEntries = DefaultDedupMaxEntries
+	}
+	if cfg.Dedup.CleanupInterval <= 0 {
+		cfg.Dedup.CleanupInterval = DefaultDedupCleanupInterval
+	}
+	if cfg.Dedup.Window <= 0 {
+		cfg.Dedup.Window = DefaultDedupWindow
+	}
+	for i := range cfg.Monitoring.Images {
+		if cfg.Monitoring.Images[i].MaxDistance <= 0 {
+			cfg.Monitoring.Images[i].MaxDistance = DefaultImageMaxDistance
+		}
+	}
+	if cfg.Links.Style == "" {
+		cfg.Links.Style = LinkStyleWeb
+	}
+	if url := os.Getenv("TELEGRAM_REDIS_URL"); url != "" {
+		cfg.Cluster.Redis = url
+	}
+	if key := os.Getenv("TELEGRAM_S3_ACCESS_KEY"); key != "" {
+		cfg.Media.AccessKey = key
+	}
+	if key := os.Getenv("TELEGRAM_S3_SECRET_KEY"); key != "" {
+		cfg.Media.SecretKey = key
+	}
+	if cfg.Media.MaxSize <= 0 {
+		cfg.Media.MaxSize = DefaultMediaMaxSize
+	}
+	if key := os.Getenv("TELEGRAM_VIRUSTOTAL_KEY"); key != "" {
+		cfg.Enrich.VirusTotalKey = key
+	}
+	if key := os.Getenv("TELEGRAM_MALWAREBAZAAR_KEY"); key != "" {
+		cfg.Enrich.MalwareBazaarKey = key
+	}
+	if cfg.Enrich.MaxSize <= 0 {
+		cfg.Enrich.MaxSize = DefaultMediaMaxSize
+	}
+	if cfg.Enrich.Timeout <= 0 {
+		cfg.Enrich.Timeout = DefaultEnrichTimeout
+	}
+	if cfg.URLs.MaxRedirects <= 0 {
+		cfg.URLs.MaxRedirects = DefaultURLMaxRedirects
+	}
+	if cfg.URLs.Timeout <= 0 {
+		cfg.URLs.Timeout = DefaultURLTimeout
+	}
+	if cfg.Shots.MaxLinks <= 0 {
+		cfg.Shots.MaxLinks = DefaultScreenshotMaxLinks
+	}
+	if cfg.Shots.Timeout <= 0 {
+		cfg.Shots.Timeout = DefaultScreenshotTimeout
+	}
+	if cfg.Mentions.Cooldown <= 0 {
+		cfg.Mentions.Cooldown = DefaultMentionsCooldown
+	}
+	if cfg.Graph.SaveInterval <= 0 {
+		cfg.Graph.SaveInterval = DefaultGraphSaveInterval
+	}
+	if cfg.Acks.Size <= 0 {
+		cfg.Acks.Size = DefaultAcksSize
+	}
+	if cfg.Acks.RemindAfter <= 0 {
+		cfg.Acks.RemindAfter = DefaultAcksRemindAfter
+	}
+	if cfg.Commands.SearchLimit <= 0 {
+		cfg.Commands.SearchLimit = DefaultSearchLimit
+	}
+	if cfg.Commands.RateLimit <= 0 {
+		cfg.Commands.RateLimit = DefaultCommandRateLimit
+	}
+	for i := range cfg.Sweeps {
+		if cfg.Sweeps[i].Limit <= 0 {
+			cfg.Sweeps[i].Limit = DefaultSweepLimit
+		}
+	}
+	if cfg.Cluster.Prefix == "" {
+		cfg.Cluster.Prefix = DefaultClusterPrefix
+	}
+	if cfg.Leader.Name == "" {
+		cfg.Leader.Name = DefaultLeaderLeaseName
+	}
+	if cfg.Leader.Identity == "" {
+		cfg.Leader.Identity, _ = os.Hostname()
+	}
+	if cfg.Leader.LeaseDuration <= 0 {
+		cfg.Leader.LeaseDuration = DefaultLeaderLeaseDuration
+	}
+	if cfg.Leader.RenewInterval <= 0 {
+		cfg.Leader.RenewInterval = DefaultLeaderRenewInterval
+	}
+	if cfg.Watch.Interval <= 0 {
+		cfg.Watch.Interval = DefaultWatchInterval
+	}
+	if cfg.Presence.History <= 0 {
+		cfg.Presence.History = DefaultPresenceHistory
+	}
+	if cfg.Packs.Index == "" {
+		cfg.Packs.Index = DefaultPackIndex
+	}
+	if cfg.Standing.Interval <= 0 {
+		cfg.Standing.Interval = DefaultStandingInterval
+	}
+	if cfg.Annotate.StormWindow <= 0 {
+		cfg.Annotate.StormWindow = DefaultStormWindow
+	}
+	if token := os.Getenv("TELEGRAM_GRAFANA_TOKEN"); token != "" {
+		cfg.Annotate.Token = token
+	}
+	if cfg.Metrics.Interval <= 0 {
+		cfg.Metrics.Interval = DefaultMetricsInterval
+	}
+	if cfg.Archive.Buffer <= 0 {
+		cfg.Archive.Buffer = DefaultArchiveBuffer
+	}
+	if cfg.Archive.BatchSize <= 0 {
+		cfg.Archive.BatchSize = DefaultArchiveBatchSize
+	}
+	if cfg.Archive.FlushInterval <= 0 {
+		cfg.Archive.FlushInterval = DefaultArchiveFlushInterval
+	}
+	if cfg.Archive.SpillMaxSize <= 0 {
+		cfg.Archive.SpillMaxSize = DefaultArchiveSpillMaxSize
+	}
+	if cfg.Actions.RateLimit <= 0 {
+		cfg.Actions.RateLimit = DefaultActionRateLimit
+	}
+	if cfg.Canary.Via == "" {
+		cfg.Canary.Via = CanaryViaAccount
+	}
+	if cfg.Canary.Interval <= 0 {
+		cfg.Canary.Interval = DefaultCanaryInterval
+	}
+	if cfg.Canary.Timeout <= 0 {
+		cfg.Canary.Timeout = DefaultCanaryTimeout
+	}
+	if cfg.Archive.Summary.Top <= 0 {
+		cfg.Archive.Summary.Top = DefaultSummaryTop
+	}
+	if cfg.Archive.ClickHouse.Database == "" {
+		cfg.Archive.ClickHouse.Database = DefaultClickHouseDatabase
+	}
+	if cfg.Archive.ClickHouse.Table == "" {
+		cfg.Archive.ClickHouse.Table = DefaultClickHouseTable
+	}
+	if password := os.Getenv("TELEGRAM_CLICKHOUSE_PASSWORD"); password != "" {
+		cfg.Archive.ClickHouse.Password = password
+	}
+	if cfg.Matching.Engine == "" {
+		cfg.Matching.Engine = EngineAuto
+	}
+	if cfg.Matching.Mode == "" {
+		cfg.Matching.Mode = MatchFirst
+	}
+	if cfg.Matching.CacheEntries <= 0 {
+		cfg.Matching.CacheEntries = DefaultMatchCacheEntries
+	}
+	if cfg.Matching.PCRETimeout <= 0 {
+		cfg.Matching.PCRETimeout = DefaultPCRETimeout
+	}
+	if cfg.Metrics.Job == "" {
+		cfg.Metrics.Job = DefaultMetricsJob
+	}
+	if cfg.Metrics.Prefix == "" {
+		cfg.Metrics.Prefix = DefaultMetricsPrefix
+	}
+	if cfg.Join.MinDelay <= 0 {
+		cfg.Join.MinDelay = DefaultJoinMinDelay
+	}
+	if cfg.Join.MaxDelay <= 0 {
+		cfg.Join.MaxDelay = max(DefaultJoinMaxDelay, cfg.Join.MinDelay)
+	}
+	if cfg.Join.DailyCap <= 0 {
+		cfg.Join.DailyCap = DefaultJoinDailyCap
+	}
+}
+
+// Resolve the XDG state directory, falling back to the working directory
+func defaultStateDir() string {
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, "telegram-scout")
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, ".local", "state", "telegram-scout")
+	}
+	return "."
+}
+
+// Return a copy of the config that logs in as the spare account
+func (c *Config) ForSpare() *Config {
+	spare := *c
+	spare.Phone = c.Spare.Phone
+	spare.Password = c.Spare.Password
+	spare.Session = c.Spare.Session
+	spare.Spare = SpareAccount{}
+	spare.IsSpare = true
+	return &spare
+}
+
+// Return the name of the session file in the state directory, one per account
+func (c *Config) SessionFile() string {
+	if c.IsSpare {
+		return "session-spare.json"
+	}
+	return "session.json"
+}
+
+// Return the path of a file inside the state directory, creating the directory if needed
+func (c *Config) StatePath(name string) (string, error) {
+	dir := c.StateDir
+	if dir == "" {
+		dir = defaultStateDir()
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create state directory %s: %w", dir, err)
+	}
+	return filepath.Join(dir, name), nil
 }
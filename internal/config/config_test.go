@@ -21,7 +21,13 @@ package config
 import (
 	"maps"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
 )
 
 func TestLoad(t *testing.T) {
@@ -52,6 +58,12 @@ chats:
 keywords:
   - "urgent"
   - "sale"
+admin:
+  listen: "127.0.0.1:8081"
+pipeline:
+  queue_size: 500
+dedup:
+  ttl: 30m
 `
 	tmpFile, err := os.CreateTemp("", "config_*.yaml")
 	if err != nil {
@@ -89,6 +101,206 @@ keywords:
 		if len(cfg.Monitoring.Keywords) != 2 {
 			t.Errorf("expected 2 keywords, got %d", len(cfg.Monitoring.Keywords))
 		}
+		if cfg.Admin.Listen != "127.0.0.1:8081" {
+			t.Errorf("expected admin listen address, got %q", cfg.Admin.Listen)
+		}
+		if cfg.Explain.Size != DefaultExplainSize {
+			t.Errorf("expected default explain size, got %d", cfg.Explain.Size)
+		}
+		if cfg.Pipeline.QueueSize != 500 {
+			t.Errorf("expected queue size 500, got %d", cfg.Pipeline.QueueSize)
+		}
+		if cfg.Notifier.Concurrency != DefaultNotifierConcurrency {
+			t.Errorf("expected default concurrency, got %d", cfg.Notifier.Concurrency)
+		}
+		if cfg.Dedup.TTL != 30*time.Minute {
+			t.Errorf("expected dedup TTL 30m, got %s", cfg.Dedup.TTL)
+		}
+		if cfg.Dedup.CleanupInterval != DefaultDedupCleanupInterval {
+			t.Errorf("expected default dedup cleanup interval, got %s", cfg.Dedup.CleanupInterval)
+		}
+		if cfg.Acks.Size != DefaultAcksSize || cfg.Acks.RemindAfter != DefaultAcksRemindAfter {
+			t.Errorf("expected default acks settings, got %+v", cfg.Acks)
+		}
+		if cfg.ChatID != 987654321 || len(cfg.Notifier.Chats) != 1 || cfg.Notifier.Chats[0].ParseMode != ParseModeHTML {
+			t.Errorf("expected the env chat as the only destination, got %d %+v", cfg.ChatID, cfg.Notifier.Chats)
+		}
+	})
+
+	t.Run("Spare Account", func(t *testing.T) {
+		env := make(map[string]string)
+		maps.Copy(env, baseEnv)
+		env["TELEGRAM_CONFIG_FILE"] = tmpFile.Name()
+		env["TELEGRAM_SPARE_PHONE"] = "+1987654321"
+		env["TELEGRAM_SPARE_SESSION"] = "{}"
+		setEnv(env)
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		spare := cfg.ForSpare()
+		if spare.Phone != "+1987654321" || spare.Session != "{}" || !spare.IsSpare || spare.Spare.Phone != "" {
+			t.Errorf("unexpected spare config %+v", spare)
+		}
+		if cfg.SessionFile() == spare.SessionFile() {
+			t.Errorf("expected separate session files, both use %q", cfg.SessionFile())
+		}
+		if cfg.Phone != "+1234567890" || cfg.IsSpare {
+			t.Error("expected the primary config left unchanged")
+		}
+	})
+
+	t.Run("Multiple Destinations", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "chats.yaml")
+		content := "chats: [cool_channel]\nnotifier:\n  chats:\n    - id: -1001\n      parse_mode: text\n      silent: true\n      profile: compact\n    - id: 987654321\n"
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		env := make(map[string]string)
+		maps.Copy(env, baseEnv)
+		env["TELEGRAM_CHAT_ID"] = "987654321, -1002"
+		env["TELEGRAM_CONFIG_FILE"] = path
+		setEnv(env)
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []ChatTarget{
+			{ID: -1001, ParseMode: ParseModeText, Silent: true, Profile: ProfileCompact},
+			{ID: 987654321, ParseMode: ParseModeHTML},
+			{ID: -1002, ParseMode: ParseModeHTML},
+		}
+		if !reflect.DeepEqual(cfg.Notifier.Chats, want) || cfg.ChatID != -1001 {
+			t.Errorf("expected YAML and env destinations merged, got %+v", cfg.Notifier.Chats)
+		}
+
+		// YAML destinations alone are enough
+		delete(env, "TELEGRAM_CHAT_ID")
+		setEnv(env)
+		if _, err := Load(); err != nil {
+			t.Errorf("expected YAML chats to stand in for TELEGRAM_CHAT_ID, got %v", err)
+		}
+
+		if err := os.WriteFile(path, []byte("notifier:\n  chats:\n    - id: 1\n      parse_mode: MarkdownV2\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "parse_mode") {
+			t.Errorf("expected unsupported parse mode error, got %v", err)
+		}
+
+		for _, content := range []string{
+			"notifier:\n  chats:\n    - id: 1\n      profile: tiny\n",
+			"notifier:\n  account:\n    profile: tiny\n",
+			"notifier:\n  stdout_profile: tiny\n",
+		} {
+			if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "profile") {
+				t.Errorf("expected unknown profile error for %q, got %v", content, err)
+			}
+		}
+	})
+
+	t.Run("Account Without Bot", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "account.yaml")
+		if err := os.WriteFile(path, []byte("chats: [cool_channel]\nnotifier:\n  account:\n    enabled: true\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		env := make(map[string]string)
+		maps.Copy(env, baseEnv)
+		delete(env, "TELEGRAM_BOT_TOKEN")
+		delete(env, "TELEGRAM_CHAT_ID")
+		env["TELEGRAM_CONFIG_FILE"] = path
+		setEnv(env)
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("expected the account to stand in for the bot, got %v", err)
+		}
+		if cfg.BotToken != "" || !cfg.Notifier.Account.Enabled {
+			t.Errorf("unexpected notifier config %+v", cfg.Notifier)
+		}
+
+		env["TELEGRAM_CONFIG_FILE"] = tmpFile.Name()
+		setEnv(env)
+		if _, err := Load(); err == nil || !strings.Contains(err.Error(), "TELEGRAM_BOT_TOKEN") {
+			t.Errorf("expected the bot token to be required without the account, got %v", err)
+		}
+	})
+
+	t.Run("Webhooks Without Bot", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "webhooks.yaml")
+		if err := os.WriteFile(path, []byte("chats: [cool_channel]\nnotifier:\n  webhooks:\n    - url: http://siem/ingest\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		env := make(map[string]string)
+		maps.Copy(env, baseEnv)
+		delete(env, "TELEGRAM_BOT_TOKEN")
+		delete(env, "TELEGRAM_CHAT_ID")
+		env["TELEGRAM_CONFIG_FILE"] = path
+		setEnv(env)
+
+		if _, err := Load(); err != nil {
+			t.Errorf("expected webhooks alone to be enough, got %v", err)
+		}
+
+		invalid := filepath.Join(t.TempDir(), "invalid.yaml")
+		for _, content := range []string{
+			"notifier:\n  webhooks:\n    - url: http://siem/ingest\n      encoding: avro\n",
+			"notifier:\n  webhooks:\n    - url: http://siem/ingest\n      encoding: protobuf\n      template: \"{{.Rule}}\"\n",
+		} {
+			if err := os.WriteFile(invalid, []byte(content), 0600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadFile(invalid); err == nil || !strings.Contains(err.Error(), "encoding") {
+				t.Errorf("expected encoding error for %q, got %v", content, err)
+			}
+		}
+
+		// A bot token still needs somewhere to send to
+		env["TELEGRAM_BOT_TOKEN"] = "bot_token"
+		setEnv(env)
+		if _, err := Load(); err == nil || !strings.Contains(err.Error(), "TELEGRAM_CHAT_ID") {
+			t.Errorf("expected TELEGRAM_CHAT_ID to be required with a bot token, got %v", err)
+		}
+	})
+
+	t.Run("State Dir", func(t *testing.T) {
+		dir := t.TempDir()
+		env := make(map[string]string)
+		maps.Copy(env, baseEnv)
+		env["TELEGRAM_CONFIG_FILE"] = tmpFile.Name()
+		env["XDG_STATE_HOME"] = dir
+		setEnv(env)
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := filepath.Join(dir, "telegram-scout"); cfg.StateDir != want {
+			t.Errorf("expected XDG state dir %q, got %q", want, cfg.StateDir)
+		}
+
+		path, err := cfg.StatePath("session.json")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := os.Stat(filepath.Dir(path)); err != nil {
+			t.Errorf("expected state dir to be created: %v", err)
+		}
+
+		env["TELEGRAM_STATE_DIR"] = filepath.Join(dir, "override")
+		setEnv(env)
+		cfg, err = Load()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.StateDir != env["TELEGRAM_STATE_DIR"] {
+			t.Errorf("expected env override, got %q", cfg.StateDir)
+		}
 	})
 
 	t.Run("Missing Env Var", func(t *testing.T) {
@@ -118,4 +330,827 @@ keywords:
 			t.Error("expected error due to missing config file, got nil")
 		}
 	})
+
+	t.Run("Invalid Chat Entry", func(t *testing.T) {
+		content := "chats:\n  - \"@valid_chan\"\n  - \"-100abc\"\nkeywords:\n  - \"test\"\n"
+		path := filepath.Join(t.TempDir(), "invalid_chat.yaml")
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+
+		_, err := LoadFile(path)
+		if err == nil || !strings.Contains(err.Error(), "-100abc") {
+			t.Errorf("expected error naming the malformed entry, got %v", err)
+		}
+	})
+
+	t.Run("Sweeps", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "sweeps.yaml")
+		load := func(sweeps string) (*Config, error) {
+			content := "chats: [cool_channel]\nsweeps:\n" + sweeps
+			if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+				t.Fatalf("failed to write config: %v", err)
+			}
+			return LoadFile(path)
+		}
+
+		cfg, err := load("  - {name: gpus, query: rtx 5070, schedule: \"0 */6 * * *\"}\n")
+		if err != nil {
+			t.Fatalf("LoadFile() error = %v", err)
+		}
+		if len(cfg.Sweeps) != 1 || cfg.Sweeps[0].Limit != DefaultSweepLimit {
+			t.Errorf("expected one sweep with the default limit, got %+v", cfg.Sweeps)
+		}
+
+		for _, bad := range []string{
+			"  - {query: rtx, schedule: \"@daily\"}\n",
+			"  - {name: gpus, schedule: \"@daily\"}\n",
+			"  - {name: gpus, query: rtx, schedule: \"0 25 * * *\"}\n",
+			"  - {name: gpus, query: rtx, schedule: \"@daily\"}\n  - {name: gpus, query: gtx, schedule: \"@daily\"}\n",
+		} {
+			if _, err := load(bad); err == nil || !strings.Contains(err.Error(), "invalid sweeps") {
+				t.Errorf("expected sweeps %q rejected, got %v", bad, err)
+			}
+		}
+	})
+
+	t.Run("Watch", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "watch.yaml")
+		if err := os.WriteFile(path, []byte("chats: [cool_channel]\nwatch:\n  messages: [https://t.me/cool_channel/42]\n"), 0600); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("LoadFile() error = %v", err)
+		}
+		if cfg.Watch.Interval != DefaultWatchInterval || len(cfg.Watch.Messages) != 1 {
+			t.Errorf("expected the watched message and the default interval, got %+v", cfg.Watch)
+		}
+
+		if err := os.WriteFile(path, []byte("chats: [cool_channel]\nwatch:\n  messages: [https://t.me/cool_channel]\n"), 0600); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+		if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "watch.messages") {
+			t.Errorf("expected a chat link without a message rejected, got %v", err)
+		}
+
+		if err := os.WriteFile(path, []byte("chats: [cool_channel]\nwatch:\n  users: [\"@durov\", \"-1001803446893\"]\n"), 0600); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+		if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "not a user") {
+			t.Errorf("expected a channel ID among watched users rejected, got %v", err)
+		}
+	})
+
+	t.Run("Presence", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "presence.yaml")
+		if err := os.WriteFile(path, []byte("chats: [cool_channel]\npresence:\n  users: [777000]\n"), 0600); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("LoadFile() error = %v", err)
+		}
+		if cfg.Presence.History != DefaultPresenceHistory || cfg.Presence.AlertEvery != 0 {
+			t.Errorf("expected the default history and no alerts, got %+v", cfg.Presence)
+		}
+		if cfg.Standing.Enabled || cfg.Standing.Interval != DefaultStandingInterval {
+			t.Errorf("expected standing checks off with the default interval, got %+v", cfg.Standing)
+		}
+
+		if err := os.WriteFile(path, []byte("chats: [cool_channel]\npresence:\n  users: [-1001803446893]\n"), 0600); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+		if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "presence.users") {
+			t.Errorf("expected a channel ID among presence users rejected, got %v", err)
+		}
+	})
+
+	t.Run("Join", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "join.yaml")
+		if err := os.WriteFile(path, []byte("chats: [\"*\"]\njoin:\n  invites: [https://t.me/+AbCdEf123, t.me/cool_channel]\n  min_delay: 15m\n"), 0600); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("LoadFile() error = %v", err)
+		}
+		if cfg.Join.MinDelay != 15*time.Minute || cfg.Join.MaxDelay != 15*time.Minute || cfg.Join.DailyCap != DefaultJoinDailyCap {
+			t.Errorf("expected max_delay raised to min_delay and the default cap, got %+v", cfg.Join)
+		}
+
+		if err := os.WriteFile(path, []byte("chats: [\"*\"]\njoin:\n  invites: [\"@cool_channel\"]\n"), 0600); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+		if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "join.invites") {
+			t.Errorf("expected a bare mention rejected, got %v", err)
+		}
+	})
+
+	t.Run("Log Format", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "log.yaml")
+		if err := os.WriteFile(path, []byte("chats: [cool_channel]\nlog:\n  format: logfmt\n"), 0600); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+		if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "log.format") {
+			t.Errorf("expected an unknown log format rejected, got %v", err)
+		}
+	})
+
+	t.Run("Log Levels", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "levels.yaml")
+		write := func(content string) {
+			if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+				t.Fatalf("failed to write config: %v", err)
+			}
+		}
+
+		write("chats: [cool_channel]\nlog:\n  level: warn\n  levels: {mtproto: debug, notifier: error}\n  sampling: {initial: 10, thereafter: 100}\n")
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("LoadFile() error = %v", err)
+		}
+		if cfg.Log.Level != zapcore.WarnLevel || cfg.Log.Levels["mtproto"] != zapcore.DebugLevel || cfg.Log.Levels["notifier"] != zapcore.ErrorLevel {
+			t.Errorf("unexpected levels %+v", cfg.Log)
+		}
+		if cfg.Log.Sampling.Initial != 10 || cfg.Log.Sampling.Thereafter != 100 {
+			t.Errorf("unexpected sampling %+v", cfg.Log.Sampling)
+		}
+
+		write("chats: [cool_channel]\nlog:\n  levels: {gotd: debug}\n")
+		if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "log.levels") {
+			t.Errorf("expected an unknown subsystem rejected, got %v", err)
+		}
+
+		write("chats: [cool_channel]\nlog:\n  level: loud\n")
+		if _, err := LoadFile(path); err == nil {
+			t.Error("expected an unknown level rejected")
+		}
+	})
+
+	t.Run("Access", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "access.yaml")
+		write := func(content string) {
+			if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+				t.Fatalf("failed to write config: %v", err)
+			}
+		}
+
+		write("chats: [cool_channel]\naccess:\n  users: {1001: admin, 1002: viewer}\n  tokens:\n    - {name: ops, token: s3cret, role: admin}\n")
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("LoadFile() error = %v", err)
+		}
+		if !cfg.Access.Enabled() || cfg.Access.Users[1002] != RoleViewer || cfg.Access.Tokens[0].Name != "ops" {
+			t.Errorf("unexpected access %+v", cfg.Access)
+		}
+
+		for _, bad := range []string{
+			"access:\n  users: {1001: owner}\n",
+			"access:\n  tokens:\n    - {token: s3cret, role: admin}\n",
+			"access:\n  tokens:\n    - {name: ops, role: admin}\n",
+			"access:\n  tokens:\n    - {name: a, token: x, role: admin}\n    - {name: b, token: x, role: viewer}\n",
+		} {
+			write("chats: [cool_channel]\n" + bad)
+			if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "invalid access") {
+				t.Errorf("expected %q rejected, got %v", bad, err)
+			}
+		}
+	})
+
+	t.Run("Metrics", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "metrics.yaml")
+		write := func(content string) {
+			if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+				t.Fatalf("failed to write config: %v", err)
+			}
+		}
+
+		write("chats: [cool_channel]\nmetrics:\n  statsd: localhost:8125\n")
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("LoadFile() error = %v", err)
+		}
+		if cfg.Metrics.Interval != DefaultMetricsInterval || cfg.Metrics.Job != DefaultMetricsJob || cfg.Metrics.Prefix != DefaultMetricsPrefix {
+			t.Errorf("expected defaults, got %+v", cfg.Metrics)
+		}
+
+		write("chats: [cool_channel]\nmetrics:\n  pushgateway: pushgateway:9091\n")
+		if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "metrics.pushgateway") {
+			t.Errorf("expected a pushgateway without scheme rejected, got %v", err)
+		}
+		write("chats: [cool_channel]\nmetrics:\n  statsd: localhost\n")
+		if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "metrics.statsd") {
+			t.Errorf("expected a statsd address without port rejected, got %v", err)
+		}
+	})
+
+	t.Run("Annotations", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "annotations.yaml")
+		write := func(content string) {
+			if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+				t.Fatalf("failed to write config: %v", err)
+			}
+		}
+
+		t.Setenv("TELEGRAM_GRAFANA_TOKEN", "glsa_env")
+		write("chats: [cool_channel]\nannotations:\n  grafana: http://grafana:3000\n  storm_threshold: 20\n")
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("LoadFile() error = %v", err)
+		}
+		if cfg.Annotate.StormWindow != DefaultStormWindow || cfg.Annotate.Token != "glsa_env" {
+			t.Errorf("expected the default window and the env token, got %+v", cfg.Annotate)
+		}
+
+		write("chats: [cool_channel]\nannotations:\n  grafana: grafana:3000\n")
+		if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "annotations.grafana") {
+			t.Errorf("expected a grafana URL without scheme rejected, got %v", err)
+		}
+		write("chats: [cool_channel]\nannotations:\n  storm_threshold: -1\n")
+		if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "annotations.storm_threshold") {
+			t.Errorf("expected a negative storm threshold rejected, got %v", err)
+		}
+	})
+
+	t.Run("Archive", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "archive.yaml")
+		write := func(content string) {
+			if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+				t.Fatalf("failed to write config: %v", err)
+			}
+		}
+
+		write("chats: [cool_channel]\narchive:\n  enabled: true\n  batch_size: 50\n")
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("LoadFile() error = %v", err)
+		}
+		a := cfg.Archive
+		if a.BatchSize != 50 || a.Buffer != DefaultArchiveBuffer || a.FlushInterval != DefaultArchiveFlushInterval || a.SpillMaxSize != DefaultArchiveSpillMaxSize {
+			t.Errorf("expected the batch size kept and defaults for the rest, got %+v", a)
+		}
+
+		write("chats: [cool_channel]\narchive:\n  buffer: -1\n")
+		if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "invalid archive") {
+			t.Errorf("expected a negative buffer rejected, got %v", err)
+		}
+	})
+
+	t.Run("Matching", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "matching.yaml")
+		write := func(content string) {
+			if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+				t.Fatalf("failed to write config: %v", err)
+			}
+		}
+
+		write("chats: [cool_channel]\n")
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("LoadFile() error = %v", err)
+		}
+		if cfg.Matching.Engine != EngineAuto {
+			t.Errorf("expected the auto engine by default, got %q", cfg.Matching.Engine)
+		}
+		if cfg.Matching.Mode != MatchFirst {
+			t.Errorf("expected the first match to win by default, got %q", cfg.Matching.Mode)
+		}
+		if cfg.Matching.CacheTTL != 0 || cfg.Matching.CacheEntries != DefaultMatchCacheEntries {
+			t.Errorf("expected caching off with the default size, got %v and %d", cfg.Matching.CacheTTL, cfg.Matching.CacheEntries)
+		}
+		if cfg.Matching.PCRETimeout != DefaultPCRETimeout {
+			t.Errorf("expected the default PCRE timeout, got %v", cfg.Matching.PCRETimeout)
+		}
+
+		write("chats: [cool_channel]\nmatching:\n  engine: turbo\n")
+		if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "matching.engine") {
+			t.Errorf("expected an unknown engine rejected, got %v", err)
+		}
+
+		write("chats: [cool_channel]\nmatching:\n  mode: best\n")
+		if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "matching.mode") {
+			t.Errorf("expected an unknown mode rejected, got %v", err)
+		}
+
+		write("chats: [cool_channel]\nmatching:\n  soak: -1m\n")
+		if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "matching.soak") {
+			t.Errorf("expected a negative soak rejected, got %v", err)
+		}
+
+		write("chats: [cool_channel]\nmatching:\n  pcre_timeout: -1s\n")
+		if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "matching.pcre_timeout") {
+			t.Errorf("expected a negative PCRE timeout rejected, got %v", err)
+		}
+
+		write("chats: [cool_channel]\nmatching:\n  stemming: [english, klingon]\n")
+		if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), `language "klingon"`) {
+			t.Errorf("expected an unknown stemming language rejected, got %v", err)
+		}
+
+		write("chats: [cool_channel]\nmatching:\n  stopwords: [german]\n")
+		if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "matching.stopwords") {
+			t.Errorf("expected stopwords of an unsupported language rejected, got %v", err)
+		}
+	})
+
+	t.Run("ClickHouse", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "clickhouse.yaml")
+		write := func(content string) {
+			if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+				t.Fatalf("failed to write config: %v", err)
+			}
+		}
+
+		t.Setenv("TELEGRAM_CLICKHOUSE_PASSWORD", "secret")
+		write("chats: [cool_channel]\narchive:\n  clickhouse:\n    url: http://clickhouse:8123\n")
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("LoadFile() error = %v", err)
+		}
+		ch := cfg.Archive.ClickHouse
+		if ch.Database != DefaultClickHouseDatabase || ch.Table != DefaultClickHouseTable || ch.Password != "secret" {
+			t.Errorf("expected defaults and the password from the environment, got %+v", ch)
+		}
+
+		for _, bad := range []string{"url: clickhouse:8123", "url: http://ch\n    table: archive;DROP", "url: http://ch\n    database: 1db"} {
+			write("chats: [cool_channel]\narchive:\n  clickhouse:\n    " + bad + "\n")
+			if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "archive.clickhouse") {
+				t.Errorf("expected %q rejected, got %v", bad, err)
+			}
+		}
+	})
+
+	t.Run("Summary", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "summary.yaml")
+		write := func(content string) {
+			if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+				t.Fatalf("failed to write config: %v", err)
+			}
+		}
+
+		write("chats: [cool_channel]\narchive:\n  enabled: true\n  summary:\n    schedule: \"0 8 * * *\"\n")
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("LoadFile() error = %v", err)
+		}
+		if cfg.Archive.Summary.Top != DefaultSummaryTop {
+			t.Errorf("expected the default top, got %d", cfg.Archive.Summary.Top)
+		}
+
+		for _, bad := range []string{
+			"archive:\n  summary:\n    schedule: \"@daily\"\n",
+			"archive:\n  enabled: true\n  summary:\n    schedule: \"daily\"\n",
+			"archive:\n  enabled: true\n  summary:\n    schedule: \"@daily\"\n    top: -1\n",
+		} {
+			write("chats: [cool_channel]\n" + bad)
+			if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "archive.summary") {
+				t.Errorf("expected %q rejected, got %v", bad, err)
+			}
+		}
+	})
+
+	t.Run("Mirrors", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "mirrors.yaml")
+		write := func(content string) {
+			if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+				t.Fatalf("failed to write config: %v", err)
+			}
+		}
+
+		write("chats: [cool_channel]\nmirrors:\n  - to: \"@gpu_mirror\"\n    rules: [\"gpu*\"]\n    chats: [cool_channel]\n")
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("LoadFile() error = %v", err)
+		}
+		if len(cfg.Mirrors) != 1 || cfg.Mirrors[0].To != "@gpu_mirror" || cfg.Mirrors[0].Rules[0] != "gpu*" {
+			t.Errorf("unexpected mirrors %+v", cfg.Mirrors)
+		}
+
+		for _, bad := range []string{"- to: \"@gpu_mirror\"", "- rules: [gpu]", "- to: \"@gpu_mirror\"\n    chats: [\"not a chat\"]"} {
+			write("chats: [cool_channel]\nmirrors:\n  " + bad + "\n")
+			if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "invalid mirrors") {
+				t.Errorf("expected %q rejected, got %v", bad, err)
+			}
+		}
+	})
+
+	t.Run("Actions", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "actions.yaml")
+		write := func(content string) {
+			if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+				t.Fatalf("failed to write config: %v", err)
+			}
+		}
+
+		write("chats: [cool_channel]\nactions:\n  hooks:\n    - do: save\n      rules: [deal]\n")
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("LoadFile() error = %v", err)
+		}
+		if cfg.Actions.RateLimit != DefaultActionRateLimit || len(cfg.Actions.Hooks) != 1 {
+			t.Errorf("unexpected actions %+v", cfg.Actions)
+		}
+
+		for _, bad := range []string{
+			"  hooks:\n    - do: delete\n      rules: [deal]",
+			"  hooks:\n    - do: react\n      rules: [deal]",
+			"  hooks:\n    - do: forward\n      rules: [deal]",
+			"  hooks:\n    - do: save",
+			"  confirm: true\n  hooks:\n    - do: save\n      rules: [deal]",
+		} {
+			write("chats: [cool_channel]\nactions:\n" + bad + "\n")
+			if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "actions") {
+				t.Errorf("expected %q rejected, got %v", bad, err)
+			}
+		}
+	})
+
+	t.Run("Lifecycle", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "lifecycle.yaml")
+		write := func(content string) {
+			if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+				t.Fatalf("failed to write config: %v", err)
+			}
+		}
+
+		write("chats: [cool_channel]\n")
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("LoadFile() error = %v", err)
+		}
+		if !cfg.Notifier.Startup.Enabled || cfg.Notifier.Shutdown.Enabled {
+			t.Errorf("expected only the startup notification by default, got %+v %+v", cfg.Notifier.Startup, cfg.Notifier.Shutdown)
+		}
+
+		write("chats: [cool_channel]\nnotifier:\n  startup:\n    enabled: false\n  shutdown:\n    enabled: true\n")
+		cfg, err = LoadFile(path)
+		if err != nil {
+			t.Fatalf("LoadFile() error = %v", err)
+		}
+		if cfg.Notifier.Startup.Enabled || !cfg.Notifier.Shutdown.Enabled {
+			t.Errorf("expected the defaults overridden, got %+v %+v", cfg.Notifier.Startup, cfg.Notifier.Shutdown)
+		}
+
+		write("chats: [cool_channel]\nnotifier:\n  shutdown:\n    template: \"{{.Host\"\n")
+		if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "notifier.shutdown.template") {
+			t.Errorf("expected the broken template rejected, got %v", err)
+		}
+	})
+
+	t.Run("LatencyBudget", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "latency.yaml")
+		if err := os.WriteFile(path, []byte("chats: [cool_channel]\npipeline:\n  latency_budget: 10s\n"), 0600); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("LoadFile() error = %v", err)
+		}
+		if cfg.Pipeline.LatencyBudget != 10*time.Second {
+			t.Errorf("expected a 10s budget, got %s", cfg.Pipeline.LatencyBudget)
+		}
+
+		if err := os.WriteFile(path, []byte("chats: [cool_channel]\npipeline:\n  latency_budget: -1s\n"), 0600); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+		if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "pipeline.latency_budget") {
+			t.Errorf("expected a negative budget rejected, got %v", err)
+		}
+	})
+
+	t.Run("CatchUp", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "catchup.yaml")
+		if err := os.WriteFile(path, []byte("chats: [cool_channel]\npipeline:\n  catch_up: 5m\n"), 0600); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("LoadFile() error = %v", err)
+		}
+		if cfg.Pipeline.CatchUp != 5*time.Minute || cfg.Pipeline.CatchUpMessages != DefaultCatchUpMessages {
+			t.Errorf("expected a 5m window with the default limit, got %s and %d", cfg.Pipeline.CatchUp, cfg.Pipeline.CatchUpMessages)
+		}
+
+		if err := os.WriteFile(path, []byte("chats: [cool_channel]\npipeline:\n  catch_up_messages: -1\n"), 0600); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+		if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "catch_up_messages") {
+			t.Errorf("expected a negative limit rejected, got %v", err)
+		}
+	})
+
+	t.Run("Canary", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "canary.yaml")
+		write := func(content string) {
+			if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+				t.Fatalf("failed to write config: %v", err)
+			}
+		}
+
+		write("chats: [cool_channel, \"-1005\"]\ncanary:\n  chat: \"-1005\"\n")
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("LoadFile() error = %v", err)
+		}
+		if c := cfg.Canary; c.Via != CanaryViaAccount || c.Interval != DefaultCanaryInterval || c.Timeout != DefaultCanaryTimeout {
+			t.Errorf("expected canary defaults, got %+v", c)
+		}
+
+		for _, bad := range []string{
+			"canary:\n  chat: \"-1009\"",
+			"canary:\n  chat: \"-1005\"\n  via: carrier_pigeon",
+			"canary:\n  chat: \"-1005\"\n  interval: 1m\n  timeout: 5m",
+		} {
+			write("chats: [cool_channel, \"-1005\"]\n" + bad + "\n")
+			if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "canary") {
+				t.Errorf("expected %q rejected, got %v", bad, err)
+			}
+		}
+	})
+
+	t.Run("Instance", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "instance.yaml")
+		write := func(content string) {
+			if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+				t.Fatalf("failed to write config: %v", err)
+			}
+		}
+
+		write("chats: [cool_channel]\ninstance_name: eu-1\nlabels:\n  team: intel\n  env: prod\n")
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("LoadFile() error = %v", err)
+		}
+		if cfg.Instance.Name != "eu-1" || cfg.Instance.Labels["team"] != "intel" || cfg.Instance.Labels["env"] != "prod" {
+			t.Errorf("unexpected instance %+v", cfg.Instance)
+		}
+
+		for _, bad := range []string{
+			"labels:\n  team-name: intel",
+			"labels:\n  __team: intel",
+			"labels:\n  job: scout",
+			"labels:\n  team: \"\"",
+		} {
+			write("chats: [cool_channel]\n" + bad + "\n")
+			if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "labels.") {
+				t.Errorf("expected %q rejected, got %v", bad, err)
+			}
+		}
+	})
+
+	t.Run("Packs", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "packs.yaml")
+		if err := os.WriteFile(path, []byte("chats: [cool_channel]\npacks:\n  enabled: [gpu-deals]\n"), 0600); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("LoadFile() error = %v", err)
+		}
+		if cfg.Packs.Index != DefaultPackIndex || len(cfg.Packs.Enabled) != 1 {
+			t.Errorf("expected the enabled pack and the default index, got %+v", cfg.Packs)
+		}
+
+		if err := os.WriteFile(path, []byte("chats: [cool_channel]\npacks:\n  index: ftp://example.com/index.yaml\n"), 0600); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+		if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "packs.index") {
+			t.Errorf("expected a non-HTTP index rejected, got %v", err)
+		}
+	})
+
+	t.Run("Wildcard And Exclusions", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "wildcard.yaml")
+		write := func(content string) {
+			if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+				t.Fatalf("failed to write config: %v", err)
+			}
+		}
+
+		write("chats: [\"*\"]\nexclude_chats: [\"@noisy_group\", \"-4567\"]\nkeywords: [\"test\"]\n")
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("LoadFile failed: %v", err)
+		}
+		if len(cfg.Monitoring.ExcludeChats) != 2 {
+			t.Errorf("expected 2 exclusions, got %v", cfg.Monitoring.ExcludeChats)
+		}
+
+		// The wildcard only makes sense as an inclusion
+		write("chats: [\"*\"]\nexclude_chats: [\"*\"]\n")
+		if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "exclude_chats") {
+			t.Errorf("expected exclude_chats error, got %v", err)
+		}
+
+		// Phone numbers resolve to users to monitor, never to exclude
+		write("chats: [\"+5511987654321\"]\nkeywords: [\"test\"]\n")
+		if _, err := LoadFile(path); err != nil {
+			t.Errorf("expected phone number chat to load, got %v", err)
+		}
+		write("chats: [\"*\"]\nexclude_chats: [\"+5511987654321\"]\n")
+		if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "exclude_chats") {
+			t.Errorf("expected exclude_chats error for a phone number, got %v", err)
+		}
+
+		// Mentions only narrow what the wildcard brings in
+		write("chats: [\"@somechannel\"]\nmentions_only: true\n")
+		if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "mentions_only") {
+			t.Errorf("expected mentions_only error without the wildcard, got %v", err)
+		}
+	})
+
+	t.Run("Chat Settings", func(t *testing.T) {
+		content := `
+chats: ["*"]
+filters:
+  ignore_bots: true
+  min_length: 10
+chat_settings:
+  "-1001803446893":
+    filters:
+      min_length: 0
+  "@quiet_chan": {}
+`
+		path := filepath.Join(t.TempDir(), "chat_settings.yaml")
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("LoadFile failed: %v", err)
+		}
+		override := cfg.ChatSettings["-1001803446893"].Filters
+		if override.MinLength != 0 || !override.IgnoreBots {
+			t.Errorf("expected override on top of global filters, got %+v", override)
+		}
+		if cfg.ChatSettings["@quiet_chan"].Filters != cfg.Filters {
+			t.Errorf("expected global filters without overrides, got %+v", cfg.ChatSettings["@quiet_chan"].Filters)
+		}
+
+		if err := os.WriteFile(path, []byte("chat_settings:\n  \"bad chat\": {}\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadFile(path); err == nil {
+			t.Error("expected error for malformed chat_settings key")
+		}
+
+		if err := os.WriteFile(path, []byte("chats: [\"*\"]\nfilters:\n  max_age: -1h\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "max_age") {
+			t.Errorf("expected a negative max_age rejected, got %v", err)
+		}
+		if err := os.WriteFile(path, []byte("chats: [\"*\"]\nchat_settings:\n  \"@quiet_chan\":\n    filters:\n      stale: hide\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), `stale "hide"`) {
+			t.Errorf("expected an unknown stale handling rejected, got %v", err)
+		}
+	})
+
+	t.Run("Priorities", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "priorities.yaml")
+		write := func(content string) {
+			if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+				t.Fatalf("failed to write config: %v", err)
+			}
+		}
+
+		write("chats: [\"*\"]\npriorities:\n  outage: high\nchat_settings:\n  \"@noisy_chan\":\n    priority: low\n")
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("LoadFile failed: %v", err)
+		}
+		if got := cfg.ChatSettings["@noisy_chan"].Priority; got != PriorityLow {
+			t.Errorf("expected the chat priority, got %q", got)
+		}
+		if got := cfg.Monitoring.Priorities["outage"]; got != PriorityHigh {
+			t.Errorf("expected the rule priority, got %q", got)
+		}
+
+		write("chats: [\"*\"]\npriorities:\n  outage: urgent\n")
+		if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), `priority "urgent"`) {
+			t.Errorf("expected an unknown rule priority rejected, got %v", err)
+		}
+		write("chats: [\"*\"]\nchat_settings:\n  \"@noisy_chan\":\n    priority: muted\n")
+		if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), `priority "muted"`) {
+			t.Errorf("expected an unknown chat priority rejected, got %v", err)
+		}
+	})
+
+	t.Run("Image Rules", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "images.yaml")
+		write := func(content string) {
+			if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+				t.Fatalf("failed to write config: %v", err)
+			}
+		}
+
+		write("images:\n  - path: refs/logo.png\n  - path: refs/scams\n    max_distance: 4\n")
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("LoadFile failed: %v", err)
+		}
+		images := cfg.Monitoring.Images
+		if len(images) != 2 || images[0].MaxDistance != DefaultImageMaxDistance || images[1].MaxDistance != 4 {
+			t.Errorf("unexpected image rules %+v", images)
+		}
+
+		write("images:\n  - name: logo\n")
+		if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "path is required") {
+			t.Errorf("expected missing path error, got %v", err)
+		}
+	})
+
+	t.Run("Screenshots", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "screenshots.yaml")
+		if err := os.WriteFile(path, []byte("screenshots:\n  endpoint: http://renderer:3000/screenshot\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "media_archive.endpoint") {
+			t.Errorf("expected error without a media archive, got %v", err)
+		}
+
+		content := "screenshots:\n  endpoint: http://renderer:3000/screenshot\nmedia_archive:\n  endpoint: minio:9000\n"
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("LoadFile failed: %v", err)
+		}
+		if cfg.Shots.MaxLinks != DefaultScreenshotMaxLinks || cfg.Shots.Timeout != DefaultScreenshotTimeout {
+			t.Errorf("expected screenshot defaults, got %+v", cfg.Shots)
+		}
+	})
+
+	t.Run("Bot API Server", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "api.yaml")
+		if err := os.WriteFile(path, []byte("notifier:\n  api_url: localhost:8081\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "notifier.api_url") {
+			t.Errorf("expected invalid api_url error, got %v", err)
+		}
+
+		if err := os.WriteFile(path, []byte("notifier:\n  concurrency: 1\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("LoadFile failed: %v", err)
+		}
+		if cfg.Notifier.APIURL != DefaultBotAPIURL {
+			t.Errorf("expected the official Bot API by default, got %q", cfg.Notifier.APIURL)
+		}
+
+		t.Setenv("TELEGRAM_BOT_API_URL", "http://bot-api:8081")
+		if cfg, err = LoadFile(path); err != nil || cfg.Notifier.APIURL != "http://bot-api:8081" {
+			t.Errorf("expected the environment to override api_url, got %q (%v)", cfg.Notifier.APIURL, err)
+		}
+	})
+
+	t.Run("Notifier Proxy", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "proxy.yaml")
+		if err := os.WriteFile(path, []byte("notifier:\n  http:\n    proxy: ftp://proxy:21\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "notifier.http.proxy") {
+			t.Errorf("expected unsupported proxy scheme error, got %v", err)
+		}
+
+		if err := os.WriteFile(path, []byte("notifier:\n  http:\n    proxy: socks5://proxy:1080\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatalf("LoadFile failed: %v", err)
+		}
+		if cfg.Notifier.HTTP.Proxy != "socks5://proxy:1080" {
+			t.Errorf("expected SOCKS5 proxy, got %q", cfg.Notifier.HTTP.Proxy)
+		}
+	})
+
+	t.Run("File Rules", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "files.yaml")
+		for content, want := range map[string]string{
+			"files:\n  - filename: \"*.apk\"\n  - mime_type: \"application/zip\"\n    max_size: 1024\n": "",
+			"files:\n  - name: empty\n":                   "at least one",
+			"files:\n  - filename: \"[a-\"\n":             "pattern",
+			"files:\n  - min_size: 10\n    max_size: 5\n": "exceeds",
+		} {
+			if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+				t.Fatal(err)
+			}
+			_, err := LoadFile(path)
+			if want == "" && err != nil {
+				t.Errorf("unexpected error for %q: %v", content, err)
+			}
+			if want != "" && (err == nil || !strings.Contains(err.Error(), want)) {
+				t.Errorf("expected %q error for %q, got %v", want, content, err)
+			}
+		}
+	})
 }
@@ -0,0 +1,152 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package enrich
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/media"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// What a threat intelligence service knows about a file
+type Verdict struct {
+	Found     bool   // Whether the service knows the file at all
+	Malicious int    // Engines flagging the file, when the service reports them
+	Total     int    // Engines that scanned the file
+	Label     string // Threat or malware family name
+	URL       string // Report page for the file
+}
+
+// Look up a file by its SHA-256 with a threat intelligence service
+type Lookup interface {
+	Name() string
+	Lookup(ctx context.Context, sha256 string) (Verdict, error)
+}
+
+// Hash documents of matched messages and report what lookups know about them
+type Enricher struct {
+	fetch   media.Fetcher
+	lookups []Lookup
+	maxSize int64
+	timeout time.Duration
+	log     *zap.Logger
+}
+
+// Create new Enricher with a lookup for every configured API key
+func New(fetch media.Fetcher, cfg config.EnrichmentConfig, log *zap.Logger) *Enricher {
+	client := &http.Client{}
+	var lookups []Lookup
+	if cfg.VirusTotalKey != "" {
+		lookups = append(lookups, NewVirusTotal(client, cfg.VirusTotalKey))
+	}
+	if cfg.MalwareBazaarKey != "" {
+		lookups = append(lookups, NewMalwareBazaar(client, cfg.MalwareBazaarKey))
+	}
+	return NewWithLookups(fetch, cfg, log, lookups...)
+}
+
+// Create new Enricher using the given lookups
+func NewWithLookups(fetch media.Fetcher, cfg config.EnrichmentConfig, log *zap.Logger, lookups ...Lookup) *Enricher {
+	maxSize := cfg.MaxSize
+	if maxSize <= 0 {
+		maxSize = config.DefaultMediaMaxSize
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = config.DefaultEnrichTimeout
+	}
+	return &Enricher{fetch: fetch, lookups: lookups, maxSize: maxSize, timeout: timeout, log: log}
+}
+
+// Return the names of the enabled lookups
+func (e *Enricher) Lookups() []string {
+	names := make([]string, len(e.lookups))
+	for i, l := range e.lookups {
+		names[i] = l.Name()
+	}
+	return names
+}
+
+// Return alert lines with the document's hash and the verdict of every lookup
+func (e *Enricher) Enrich(ctx context.Context, msg model.Message) ([]string, error) {
+	if msg.Media != "document" || msg.File == nil {
+		return nil, nil
+	}
+	if msg.File.Size > e.maxSize {
+		return []string{fmt.Sprintf("🧬 <b>SHA-256:</b> skipped, file larger than %d bytes", e.maxSize)}, nil
+	}
+
+	sum, err := e.hash(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+	lines := []string{fmt.Sprintf("🧬 <b>SHA-256:</b> <code>%s</code>", sum)}
+
+	for _, l := range e.lookups {
+		lookupCtx, cancel := context.WithTimeout(ctx, e.timeout)
+		v, err := l.Lookup(lookupCtx, sum)
+		cancel()
+		if err != nil {
+			e.log.Warn("Hash lookup failed", zap.String("service", l.Name()), zap.String("sha256", sum), zap.Error(err))
+			lines = append(lines, fmt.Sprintf("🛡 <b>%s:</b> lookup failed", l.Name()))
+			continue
+		}
+		lines = append(lines, verdictLine(l.Name(), v))
+	}
+	return lines, nil
+}
+
+// Stream the document through SHA-256 without keeping it in memory
+func (e *Enricher) hash(ctx context.Context, msg model.Message) (string, error) {
+	h := sha256.New()
+	if err := e.fetch.Download(ctx, msg.ChatID, msg.ID, h); err != nil {
+		return "", fmt.Errorf("download failed: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Format a verdict as an alert line
+func verdictLine(service string, v Verdict) string {
+	if !v.Found {
+		return fmt.Sprintf("🛡 <b>%s:</b> unknown file", service)
+	}
+
+	text := "listed"
+	if v.Total > 0 {
+		text = fmt.Sprintf("%d/%d engines flag it", v.Malicious, v.Total)
+	}
+	if v.Label != "" {
+		text += " (" + html.EscapeString(v.Label) + ")"
+	}
+	line := fmt.Sprintf("🛡 <b>%s:</b> %s", service, text)
+	if v.URL != "" {
+		line += fmt.Sprintf(" — <a href=\"%s\">report</a>", v.URL)
+	}
+	return line
+}
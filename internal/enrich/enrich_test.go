@@ -0,0 +1,105 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package enrich
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Serve fixed content for every message
+type fakeFetcher struct {
+	data  string
+	calls int
+}
+
+func (f *fakeFetcher) Download(ctx context.Context, chatID int64, msgID int, w io.Writer) error {
+	f.calls++
+	_, err := io.WriteString(w, f.data)
+	return err
+}
+
+// Return a canned verdict
+type fakeLookup struct {
+	name    string
+	verdict Verdict
+	err     error
+	hashes  []string
+}
+
+func (f *fakeLookup) Name() string { return f.name }
+
+func (f *fakeLookup) Lookup(ctx context.Context, sha256 string) (Verdict, error) {
+	f.hashes = append(f.hashes, sha256)
+	return f.verdict, f.err
+}
+
+// sha256("hello world")
+const helloSum = "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"
+
+func TestEnricher_Enrich(t *testing.T) {
+	fetch := &fakeFetcher{data: "hello world"}
+	vt := &fakeLookup{name: "VirusTotal", verdict: Verdict{Found: true, Malicious: 12, Total: 70, Label: "trojan.<agent>", URL: "https://vt.example/x"}}
+	mb := &fakeLookup{name: "MalwareBazaar"}
+	broken := &fakeLookup{name: "Broken", err: errors.New("boom")}
+	e := NewWithLookups(fetch, config.EnrichmentConfig{}, zap.NewNop(), vt, mb, broken)
+
+	msg := model.Message{Media: "document", File: &model.File{Name: "a.apk", Size: 11}}
+	lines, err := e.Enrich(context.Background(), msg)
+	if err != nil {
+		t.Fatalf("Enrich() error = %v", err)
+	}
+
+	want := []string{
+		"🧬 <b>SHA-256:</b> <code>" + helloSum + "</code>",
+		"🛡 <b>VirusTotal:</b> 12/70 engines flag it (trojan.&lt;agent&gt;) — <a href=\"https://vt.example/x\">report</a>",
+		"🛡 <b>MalwareBazaar:</b> unknown file",
+		"🛡 <b>Broken:</b> lookup failed",
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected lines:\n%s", strings.Join(lines, "\n"))
+	}
+	if len(vt.hashes) != 1 || vt.hashes[0] != helloSum {
+		t.Errorf("expected lookup by sha256, got %v", vt.hashes)
+	}
+}
+
+func TestEnricher_Skips(t *testing.T) {
+	fetch := &fakeFetcher{data: "hello world"}
+	e := NewWithLookups(fetch, config.EnrichmentConfig{MaxSize: 5}, zap.NewNop())
+
+	if lines, _ := e.Enrich(context.Background(), model.Message{Media: "photo"}); lines != nil {
+		t.Errorf("expected photos to be skipped, got %v", lines)
+	}
+	lines, err := e.Enrich(context.Background(), model.Message{Media: "document", File: &model.File{Size: 11}})
+	if err != nil || len(lines) != 1 || !strings.Contains(lines[0], "skipped") {
+		t.Errorf("expected oversized file to be skipped, got %v %v", lines, err)
+	}
+	if fetch.calls != 0 {
+		t.Errorf("expected no downloads, got %d", fetch.calls)
+	}
+}
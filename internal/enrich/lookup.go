@@ -0,0 +1,158 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package enrich
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Look up files in VirusTotal's v3 API
+type VirusTotal struct {
+	client  *http.Client
+	key     string
+	baseURL string
+}
+
+// Create new VirusTotal lookup
+func NewVirusTotal(client *http.Client, key string) *VirusTotal {
+	return &VirusTotal{client: client, key: key, baseURL: "https://www.virustotal.com"}
+}
+
+func (v *VirusTotal) Name() string {
+	return "VirusTotal"
+}
+
+// Fetch the latest analysis of the file
+func (v *VirusTotal) Lookup(ctx context.Context, sha256 string) (Verdict, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.baseURL+"/api/v3/files/"+sha256, nil)
+	if err != nil {
+		return Verdict{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("x-apikey", v.key)
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return Verdict{}, fmt.Errorf("network error: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return Verdict{}, nil
+	default:
+		return Verdict{}, fmt.Errorf("api returned status: %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Data struct {
+			Attributes struct {
+				Stats                map[string]int `json:"last_analysis_stats"`
+				ThreatClassification struct {
+					Label string `json:"suggested_threat_label"`
+				} `json:"popular_threat_classification"`
+			} `json:"attributes"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return Verdict{}, fmt.Errorf("invalid response: %w", err)
+	}
+
+	attrs := body.Data.Attributes
+	verdict := Verdict{
+		Found:     true,
+		Malicious: attrs.Stats["malicious"],
+		Label:     attrs.ThreatClassification.Label,
+		URL:       "https://www.virustotal.com/gui/file/" + sha256,
+	}
+	// Count only engines that produced a result
+	for category, n := range attrs.Stats {
+		switch category {
+		case "malicious", "suspicious", "undetected", "harmless":
+			verdict.Total += n
+		}
+	}
+	return verdict, nil
+}
+
+// Look up files in abuse.ch MalwareBazaar
+type MalwareBazaar struct {
+	client  *http.Client
+	key     string
+	baseURL string
+}
+
+// Create new MalwareBazaar lookup
+func NewMalwareBazaar(client *http.Client, key string) *MalwareBazaar {
+	return &MalwareBazaar{client: client, key: key, baseURL: "https://mb-api.abuse.ch"}
+}
+
+func (m *MalwareBazaar) Name() string {
+	return "MalwareBazaar"
+}
+
+// Report whether the file is a known malware sample and its family
+func (m *MalwareBazaar) Lookup(ctx context.Context, sha256 string) (Verdict, error) {
+	form := url.Values{"query": {"get_info"}, "hash": {sha256}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.baseURL+"/api/v1/", strings.NewReader(form.Encode()))
+	if err != nil {
+		return Verdict{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Auth-Key", m.key)
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return Verdict{}, fmt.Errorf("network error: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return Verdict{}, fmt.Errorf("api returned status: %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Status string `json:"query_status"`
+		Data   []struct {
+			Signature string `json:"signature"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return Verdict{}, fmt.Errorf("invalid response: %w", err)
+	}
+
+	switch body.Status {
+	case "ok":
+	case "hash_not_found", "no_results":
+		return Verdict{}, nil
+	default:
+		return Verdict{}, fmt.Errorf("query failed: %s", body.Status)
+	}
+
+	verdict := Verdict{Found: true, URL: "https://bazaar.abuse.ch/sample/" + sha256 + "/"}
+	if len(body.Data) > 0 {
+		verdict.Label = body.Data[0].Signature
+	}
+	return verdict, nil
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package enrich
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVirusTotal_Lookup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-apikey") != "secret" {
+			t.Errorf("missing api key header")
+		}
+		if r.URL.Path != "/api/v3/files/"+helloSum {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"attributes":{
+			"last_analysis_stats":{"malicious":3,"suspicious":1,"undetected":60,"harmless":0,"type-unsupported":10},
+			"popular_threat_classification":{"suggested_threat_label":"trojan.agent"}}}}`))
+	}))
+	defer server.Close()
+
+	vt := NewVirusTotal(server.Client(), "secret")
+	vt.baseURL = server.URL
+
+	v, err := vt.Lookup(context.Background(), helloSum)
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	if !v.Found || v.Malicious != 3 || v.Total != 64 || v.Label != "trojan.agent" {
+		t.Errorf("unexpected verdict %+v", v)
+	}
+
+	v, err = vt.Lookup(context.Background(), "0000")
+	if err != nil || v.Found {
+		t.Errorf("expected unknown file, got %+v %v", v, err)
+	}
+}
+
+func TestMalwareBazaar_Lookup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Auth-Key") != "secret" || r.FormValue("query") != "get_info" {
+			t.Errorf("unexpected request %v", r.Form)
+		}
+		if r.FormValue("hash") != helloSum {
+			_, _ = w.Write([]byte(`{"query_status":"hash_not_found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"query_status":"ok","data":[{"signature":"AgentTesla"}]}`))
+	}))
+	defer server.Close()
+
+	mb := NewMalwareBazaar(server.Client(), "secret")
+	mb.baseURL = server.URL
+
+	v, err := mb.Lookup(context.Background(), helloSum)
+	if err != nil || !v.Found || v.Label != "AgentTesla" {
+		t.Errorf("unexpected verdict %+v %v", v, err)
+	}
+
+	v, err = mb.Lookup(context.Background(), "0000")
+	if err != nil || v.Found {
+		t.Errorf("expected unknown file, got %+v %v", v, err)
+	}
+}
@@ -0,0 +1,148 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package graph
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Export formats
+const (
+	FormatDOT     = "dot"
+	FormatGraphML = "graphml"
+)
+
+// Write the snapshot in the given format
+func (s Snapshot) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatDOT:
+		return s.WriteDOT(w)
+	case FormatGraphML:
+		return s.WriteGraphML(w)
+	}
+	return fmt.Errorf("unknown format %q: expected %q or %q", format, FormatDOT, FormatGraphML)
+}
+
+// Write the snapshot as a Graphviz digraph, with edge weights as labels
+func (s Snapshot) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph telegram_scout {\n")
+	for _, n := range s.Nodes {
+		label := n.Label
+		if label == "" {
+			label = n.ID
+		}
+		fmt.Fprintf(&b, "  %s [label=%s];\n", dotQuote(n.ID), dotQuote(label))
+	}
+	for _, e := range s.Edges {
+		style := "solid"
+		if e.Kind == KindMention {
+			style = "dashed"
+		}
+		fmt.Fprintf(&b, "  %s -> %s [label=\"%d\", weight=%d, kind=%s, style=%s];\n",
+			dotQuote(e.From), dotQuote(e.To), e.Count, e.Count, dotQuote(e.Kind), style)
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Quote a DOT identifier, leaving non-ASCII titles readable
+func dotQuote(s string) string {
+	return `"` + dotEscaper.Replace(s) + `"`
+}
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// GraphML document layout
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   struct {
+		ID          string        `xml:"id,attr"`
+		EdgeDefault string        `xml:"edgedefault,attr"`
+		Nodes       []graphMLNode `xml:"node"`
+		Edges       []graphMLEdge `xml:"edge"`
+	} `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// Write the snapshot as GraphML, readable by Gephi, yEd and networkx
+func (s Snapshot) WriteGraphML(w io.Writer) error {
+	doc := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "label", For: "node", Name: "label", Type: "string"},
+			{ID: "kind", For: "edge", Name: "kind", Type: "string"},
+			{ID: "weight", For: "edge", Name: "weight", Type: "int"},
+			{ID: "last", For: "edge", Name: "last_seen", Type: "string"},
+		},
+	}
+	doc.Graph.ID = "telegram-scout"
+	doc.Graph.EdgeDefault = "directed"
+	for _, n := range s.Nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{ID: n.ID, Data: []graphMLData{{Key: "label", Value: n.Label}}})
+	}
+	for _, e := range s.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{Source: e.From, Target: e.To, Data: []graphMLData{
+			{Key: "kind", Value: e.Kind},
+			{Key: "weight", Value: strconv.Itoa(e.Count)},
+			{Key: "last", Value: e.Last.UTC().Format(time.RFC3339)},
+		}})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
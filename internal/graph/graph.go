@@ -0,0 +1,176 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package graph records how chats relate to each other through forwards and
+// mentions, for export to graph analysis tools.
+package graph
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/h3nc4/TelegramScout/internal/atomicfile"
+)
+
+// Relations between chats
+const (
+	KindForward = "forward" // From the origin of a forwarded message to the chat it was forwarded into
+	KindMention = "mention" // From a chat to the chat it referenced
+)
+
+// A chat, keyed by "@username" when public and by its Bot API ID otherwise
+type Node struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+}
+
+// Aggregated relation between two chats
+type Edge struct {
+	From  string    `json:"from"`
+	To    string    `json:"to"`
+	Kind  string    `json:"kind"`
+	Count int       `json:"count"`
+	Last  time.Time `json:"last"`
+}
+
+// Point-in-time copy of the graph, sorted for stable output
+type Snapshot struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+}
+
+type edgeKey struct {
+	from, to, kind string
+}
+
+// Accumulate relations between chats, safe for concurrent use
+type Graph struct {
+	mux   sync.Mutex
+	nodes map[string]string
+	edges map[edgeKey]*Edge
+	dirty bool
+}
+
+// Create an empty Graph
+func New() *Graph {
+	return &Graph{nodes: make(map[string]string), edges: make(map[edgeKey]*Edge)}
+}
+
+// Record a relation, creating both nodes as needed. Empty labels keep the known one.
+func (g *Graph) Add(from, to Node, kind string, at time.Time) {
+	if from.ID == "" || to.ID == "" || from.ID == to.ID {
+		return
+	}
+	g.mux.Lock()
+	defer g.mux.Unlock()
+
+	g.addNode(from)
+	g.addNode(to)
+	key := edgeKey{from.ID, to.ID, kind}
+	e, ok := g.edges[key]
+	if !ok {
+		e = &Edge{From: from.ID, To: to.ID, Kind: kind}
+		g.edges[key] = e
+	}
+	e.Count++
+	if at.After(e.Last) {
+		e.Last = at
+	}
+	g.dirty = true
+}
+
+func (g *Graph) addNode(n Node) {
+	if _, ok := g.nodes[n.ID]; !ok || n.Label != "" {
+		g.nodes[n.ID] = n.Label
+	}
+}
+
+// Return a copy of the graph
+func (g *Graph) Snapshot() Snapshot {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+
+	snap := Snapshot{
+		Nodes: make([]Node, 0, len(g.nodes)),
+		Edges: make([]Edge, 0, len(g.edges)),
+	}
+	for id, label := range g.nodes {
+		snap.Nodes = append(snap.Nodes, Node{ID: id, Label: label})
+	}
+	for _, e := range g.edges {
+		snap.Edges = append(snap.Edges, *e)
+	}
+	slices.SortFunc(snap.Nodes, func(a, b Node) int { return cmp.Compare(a.ID, b.ID) })
+	slices.SortFunc(snap.Edges, func(a, b Edge) int {
+		return cmp.Or(cmp.Compare(a.From, b.From), cmp.Compare(a.To, b.To), cmp.Compare(a.Kind, b.Kind))
+	})
+	return snap
+}
+
+// Read a graph saved by Save, returning an empty one if the file does not exist
+func Load(path string) (*Graph, error) {
+	g := New()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return g, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("invalid graph file %s: %w", path, err)
+	}
+	for _, n := range snap.Nodes {
+		g.nodes[n.ID] = n.Label
+	}
+	for _, e := range snap.Edges {
+		g.edges[edgeKey{e.From, e.To, e.Kind}] = &e
+	}
+	return g, nil
+}
+
+// Write the graph to path if it changed since it was loaded or last saved
+func (g *Graph) Save(path string) error {
+	g.mux.Lock()
+	dirty := g.dirty
+	g.dirty = false
+	g.mux.Unlock()
+	if !dirty {
+		return nil
+	}
+
+	data, err := json.Marshal(g.Snapshot())
+	if err == nil {
+		err = atomicfile.Write(path, data)
+	}
+	if err != nil {
+		g.mux.Lock()
+		g.dirty = true
+		g.mux.Unlock()
+	}
+	return err
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package graph
+
+import (
+	"bytes"
+	"encoding/xml"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGraph_Add(t *testing.T) {
+	g := New()
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	g.Add(Node{ID: "@source", Label: "Source"}, Node{ID: "-1001", Label: "Mirror"}, KindForward, at)
+	g.Add(Node{ID: "@source"}, Node{ID: "-1001"}, KindForward, at.Add(time.Hour))
+	g.Add(Node{ID: "-1001"}, Node{ID: "@other"}, KindMention, at)
+	g.Add(Node{ID: "-1001"}, Node{ID: "-1001"}, KindMention, at)
+
+	snap := g.Snapshot()
+	wantNodes := []Node{{ID: "-1001", Label: "Mirror"}, {ID: "@other"}, {ID: "@source", Label: "Source"}}
+	if !reflect.DeepEqual(snap.Nodes, wantNodes) {
+		t.Errorf("unexpected nodes %+v", snap.Nodes)
+	}
+	if len(snap.Edges) != 2 {
+		t.Fatalf("expected 2 edges without self loops, got %+v", snap.Edges)
+	}
+	if e := snap.Edges[1]; e.From != "@source" || e.Count != 2 || !e.Last.Equal(at.Add(time.Hour)) {
+		t.Errorf("expected aggregated forward edge, got %+v", e)
+	}
+}
+
+func TestGraph_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "graph.json")
+	g, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() of missing file error = %v", err)
+	}
+	g.Add(Node{ID: "@a", Label: "A"}, Node{ID: "@b"}, KindForward, time.Now())
+	if err := g.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got, want := loaded.Snapshot(), g.Snapshot(); !reflect.DeepEqual(got.Nodes, want.Nodes) || len(got.Edges) != 1 {
+		t.Errorf("Load() = %+v, want %+v", got, want)
+	}
+
+	// Adding to a loaded edge keeps counting from the saved total
+	loaded.Add(Node{ID: "@a"}, Node{ID: "@b"}, KindForward, time.Now())
+	if e := loaded.Snapshot().Edges[0]; e.Count != 2 {
+		t.Errorf("expected count to continue after load, got %d", e.Count)
+	}
+}
+
+func TestSnapshot_Write(t *testing.T) {
+	snap := Snapshot{
+		Nodes: []Node{{ID: "@a", Label: `Новости "A"`}, {ID: "-1002"}},
+		Edges: []Edge{{From: "@a", To: "-1002", Kind: KindMention, Count: 3}},
+	}
+
+	var dot bytes.Buffer
+	if err := snap.Write(&dot, FormatDOT); err != nil {
+		t.Fatalf("Write(dot) error = %v", err)
+	}
+	for _, want := range []string{`"@a" [label="Новости \"A\""]`, `"-1002" [label="-1002"]`, `"@a" -> "-1002" [label="3", weight=3`, "style=dashed"} {
+		if !strings.Contains(dot.String(), want) {
+			t.Errorf("expected %q in DOT output:\n%s", want, dot.String())
+		}
+	}
+
+	var ml bytes.Buffer
+	if err := snap.Write(&ml, FormatGraphML); err != nil {
+		t.Fatalf("Write(graphml) error = %v", err)
+	}
+	var doc graphML
+	if err := xml.Unmarshal(ml.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid GraphML: %v\n%s", err, ml.String())
+	}
+	if len(doc.Graph.Nodes) != 2 || len(doc.Graph.Edges) != 1 || doc.Graph.Edges[0].Data[1].Value != "3" {
+		t.Errorf("unexpected GraphML document %+v", doc.Graph)
+	}
+
+	if err := snap.Write(&ml, "png"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
@@ -0,0 +1,107 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package health
+
+import (
+	"sync"
+	"time"
+)
+
+// Track liveness of the MTProto connection from periodic heartbeats
+type Tracker struct {
+	mux        sync.Mutex
+	staleAfter time.Duration
+	connected  bool
+	standby    bool
+	lastBeat   time.Time
+	lastErr    error
+	now        func() time.Time
+	spare      *Tracker // The hot spare account's connection, nil without one
+}
+
+// Snapshot of the connection health
+type Status struct {
+	Healthy       bool      `json:"healthy"`
+	Connected     bool      `json:"connected"`
+	Standby       bool      `json:"standby,omitempty"` // Waiting for leadership, healthy without a connection
+	LastHeartbeat time.Time `json:"last_heartbeat,omitzero"`
+	Error         string    `json:"error,omitempty"`
+	Spare         *Status   `json:"spare,omitempty"` // The hot spare account, healthy overall while either account is
+}
+
+// Create new Tracker reporting unhealthy once no heartbeat succeeded for staleAfter
+func NewTracker(staleAfter time.Duration) *Tracker {
+	return &Tracker{staleAfter: staleAfter, now: time.Now}
+}
+
+// Report the hot spare account's connection alongside, so the service stays
+// healthy while either account is connected
+func (t *Tracker) WithSpare(spare *Tracker) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	t.spare = spare
+}
+
+// Record the outcome of a heartbeat
+func (t *Tracker) Beat(err error) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	t.lastErr = err
+	if err == nil {
+		t.connected = true
+		t.lastBeat = t.now()
+	}
+}
+
+// Record that the client session ended
+func (t *Tracker) Disconnected(err error) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	t.connected = false
+	t.lastErr = err
+}
+
+// Record whether this replica is waiting for leadership instead of connecting
+func (t *Tracker) Standby(standby bool) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	t.standby = standby
+}
+
+// Report the current health
+func (t *Tracker) Status() Status {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	st := Status{
+		Connected:     t.connected,
+		Standby:       t.standby,
+		LastHeartbeat: t.lastBeat,
+		Healthy:       t.standby || (t.connected && t.now().Sub(t.lastBeat) <= t.staleAfter),
+	}
+	if t.lastErr != nil {
+		st.Error = t.lastErr.Error()
+	}
+	if t.spare != nil {
+		spare := t.spare.Status()
+		st.Spare = &spare
+		st.Healthy = st.Healthy || spare.Healthy
+	}
+	return st
+}
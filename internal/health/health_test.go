@@ -0,0 +1,79 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package health
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTracker(t *testing.T) {
+	now := time.Unix(1000, 0)
+	tr := NewTracker(time.Minute)
+	tr.now = func() time.Time { return now }
+
+	if tr.Status().Healthy {
+		t.Error("expected unhealthy before the first heartbeat")
+	}
+
+	tr.Beat(nil)
+	if st := tr.Status(); !st.Healthy || !st.Connected {
+		t.Errorf("expected healthy after heartbeat, got %+v", st)
+	}
+
+	// A failed ping keeps the last good heartbeat until it goes stale
+	tr.Beat(errors.New("ping timeout"))
+	if st := tr.Status(); !st.Healthy || st.Error != "ping timeout" {
+		t.Errorf("expected healthy with error recorded, got %+v", st)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if tr.Status().Healthy {
+		t.Error("expected unhealthy once heartbeats are stale")
+	}
+
+	tr.Beat(nil)
+	tr.Disconnected(errors.New("connection reset"))
+	if st := tr.Status(); st.Healthy || st.Connected {
+		t.Errorf("expected unhealthy after disconnect, got %+v", st)
+	}
+
+	tr.Standby(true)
+	if !tr.Status().Healthy {
+		t.Error("expected standby replicas to report healthy")
+	}
+}
+
+func TestTracker_Spare(t *testing.T) {
+	primary, spare := NewTracker(time.Minute), NewTracker(time.Minute)
+	primary.WithSpare(spare)
+
+	spare.Beat(nil)
+	primary.Disconnected(errors.New("AUTH_KEY_UNREGISTERED"))
+	st := primary.Status()
+	if !st.Healthy || st.Connected || st.Spare == nil || !st.Spare.Connected {
+		t.Errorf("expected healthy through the spare, got %+v", st)
+	}
+
+	spare.Disconnected(errors.New("connection reset"))
+	if primary.Status().Healthy {
+		t.Error("expected unhealthy once both accounts are down")
+	}
+}
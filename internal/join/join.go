@@ -0,0 +1,265 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package join joins chats from configured links one at a time, with random
+// delays and a daily cap, backing off when Telegram pushes back.
+package join
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/rand/v2"
+	"os"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/atomicfile"
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+// Outcomes of a join
+const (
+	Joined    = "joined"    // The account became a member
+	Requested = "requested" // The chat approves new members, a request was sent
+	Member    = "member"    // The account was already a member
+	Failed    = "failed"    // Telegram refused the link, it is not tried again
+)
+
+// Returned when the account reached the limit of channels it can be in
+var ErrTooManyChannels = errors.New("account is in too many channels and supergroups")
+
+// Returned when Telegram refuses a link for good, such as an expired invite
+var ErrRejected = errors.New("join rejected")
+
+// Returned when Telegram asks to wait before joining again
+type FloodWaitError struct {
+	Wait time.Duration
+}
+
+func (e *FloodWaitError) Error() string {
+	return fmt.Sprintf("flood wait of %s", e.Wait)
+}
+
+// Join a chat by its t.me link, returning Joined, Requested or Member
+type Joiner interface {
+	Join(ctx context.Context, link string) (string, error)
+}
+
+// Longest factor the delays grow to after repeated flood waits
+const maxBackoff = 16
+
+// The outcome of a link, once settled
+type result struct {
+	Status string    `json:"status"`
+	Time   time.Time `json:"time"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// Settled links and today's joins, as saved to the state file
+type state struct {
+	Links map[string]result `json:"links"`
+	Day   string            `json:"day"`   // Local date the count belongs to
+	Count int               `json:"count"` // Joins attempted on Day
+}
+
+// Join the configured links in order, remembering which are settled in a state file
+type Queue struct {
+	links    []string
+	minDelay time.Duration
+	maxDelay time.Duration
+	dailyCap int
+	join     Joiner
+	path     string
+	log      *zap.Logger
+	now      func() time.Time
+
+	backoff int // Delay factor, doubled on every flood wait and halved on every join
+	seen    state
+}
+
+// Create a Queue for the configured links, loading the settled ones from path
+func New(cfg config.JoinConfig, join Joiner, path string, log *zap.Logger) (*Queue, error) {
+	q := &Queue{
+		links:    cfg.Invites,
+		minDelay: cfg.MinDelay,
+		maxDelay: cfg.MaxDelay,
+		dailyCap: cfg.DailyCap,
+		join:     join,
+		path:     path,
+		log:      log,
+		now:      time.Now,
+		backoff:  1,
+	}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return nil, err
+	default:
+		if err := json.Unmarshal(data, &q.seen); err != nil {
+			return nil, fmt.Errorf("invalid join state %s: %w", path, err)
+		}
+	}
+	if q.seen.Links == nil {
+		q.seen.Links = make(map[string]result)
+	}
+
+	// Forget links no longer configured
+	for link := range q.seen.Links {
+		if !slices.Contains(q.links, link) {
+			delete(q.seen.Links, link)
+		}
+	}
+	return q, nil
+}
+
+// Return the links not settled yet, in configured order
+func (q *Queue) Pending() []string {
+	var pending []string
+	for _, link := range q.links {
+		if _, ok := q.seen.Links[link]; !ok {
+			pending = append(pending, link)
+		}
+	}
+	return pending
+}
+
+// Join the pending links until all are settled or ctx is done, waiting a
+// random delay before each one and for the next day once the cap is reached
+func (q *Queue) Run(ctx context.Context) {
+	for {
+		pending := q.Pending()
+		if len(pending) == 0 {
+			return
+		}
+		if wait := q.capWait(); wait > 0 {
+			q.log.Info("Daily join cap reached, waiting for tomorrow", zap.Int("cap", q.dailyCap), zap.Int("pending", len(pending)), zap.Duration("wait", wait))
+			if !sleep(ctx, wait) {
+				return
+			}
+			continue
+		}
+		if !sleep(ctx, q.delay()) {
+			return
+		}
+		if !q.try(ctx, pending[0]) {
+			return
+		}
+	}
+}
+
+// Join one link and act on the outcome, reporting false once ctx is done
+func (q *Queue) try(ctx context.Context, link string) bool {
+	status, err := q.join.Join(ctx, link)
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var flood *FloodWaitError
+	switch {
+	case errors.As(err, &flood):
+		q.backoff = min(q.backoff*2, maxBackoff)
+		q.log.Warn("Telegram asked to slow down joins", zap.String("link", link), zap.Duration("wait", flood.Wait), zap.Int("backoff", q.backoff))
+		return sleep(ctx, flood.Wait)
+	case errors.Is(err, ErrTooManyChannels):
+		// Retried tomorrow, in case chats were left meanwhile
+		q.log.Error("Cannot join more chats, leave some to continue", zap.String("link", link))
+		q.count()
+		q.seen.Count = max(q.seen.Count, q.dailyCap)
+		q.save()
+		return true
+	case errors.Is(err, ErrRejected):
+		q.log.Warn("Join link refused", zap.String("link", link), zap.Error(err))
+		q.seen.Links[link] = result{Status: Failed, Time: q.now(), Error: err.Error()}
+		q.count()
+		q.save()
+		return true
+	case err != nil:
+		q.log.Warn("Failed to join, retrying later", zap.String("link", link), zap.Error(err))
+		return true
+	}
+
+	q.log.Info("Joined chat", zap.String("link", link), zap.String("status", status))
+	q.seen.Links[link] = result{Status: status, Time: q.now()}
+	q.backoff = max(q.backoff/2, 1)
+	if status != Member {
+		q.count()
+	}
+	q.save()
+	return true
+}
+
+// Count an attempt against today's cap
+func (q *Queue) count() {
+	if day := q.today(); q.seen.Day != day {
+		q.seen.Day, q.seen.Count = day, 0
+	}
+	q.seen.Count++
+}
+
+// Return how long to wait for the cap to reset, zero while joins are left today
+func (q *Queue) capWait() time.Duration {
+	now := q.now()
+	if q.seen.Day != q.today() || q.seen.Count < q.dailyCap {
+		return 0
+	}
+	y, m, d := now.Date()
+	return time.Date(y, m, d+1, 0, 0, 0, 0, now.Location()).Sub(now)
+}
+
+func (q *Queue) today() string {
+	return q.now().Format(time.DateOnly)
+}
+
+// Return a random wait between the delays, stretched by the backoff
+func (q *Queue) delay() time.Duration {
+	d := q.minDelay
+	if spread := q.maxDelay - q.minDelay; spread > 0 {
+		d += rand.N(spread + 1)
+	}
+	return d * time.Duration(q.backoff)
+}
+
+// Save the state file, logging failures so joins go on
+func (q *Queue) save() {
+	data, err := json.Marshal(q.seen)
+	if err == nil {
+		err = atomicfile.Write(q.path, data)
+	}
+	if err != nil {
+		q.log.Error("Failed to save join state", zap.String("path", q.path), zap.Error(err))
+	}
+}
+
+// Wait for d, reporting false if ctx is done first
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
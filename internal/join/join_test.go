@@ -0,0 +1,130 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package join
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+// Answer joins from a script of errors per link, joining once the script runs out
+type fakeJoiner struct {
+	script map[string][]error
+	calls  []string
+}
+
+func (f *fakeJoiner) Join(ctx context.Context, link string) (string, error) {
+	f.calls = append(f.calls, link)
+	if errs := f.script[link]; len(errs) > 0 {
+		f.script[link] = errs[1:]
+		return "", errs[0]
+	}
+	return Joined, nil
+}
+
+func newQueue(t *testing.T, cfg config.JoinConfig, j Joiner, path string) *Queue {
+	t.Helper()
+	q, err := New(cfg, j, path, zap.NewNop())
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return q
+}
+
+func TestQueue_Run(t *testing.T) {
+	links := []string{"https://t.me/+AAAA", "https://t.me/+BBBB", "https://t.me/cool_channel"}
+	cfg := config.JoinConfig{Invites: links, MinDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond, DailyCap: 10}
+	j := &fakeJoiner{script: map[string][]error{
+		links[0]: {&FloodWaitError{Wait: time.Millisecond}, errors.New("connection reset")},
+		links[1]: {ErrRejected},
+	}}
+	path := filepath.Join(t.TempDir(), "join.json")
+	q := newQueue(t, cfg, j, path)
+
+	q.Run(context.Background())
+
+	want := []string{links[0], links[0], links[0], links[1], links[2]}
+	if len(j.calls) != len(want) {
+		t.Fatalf("expected calls %v, got %v", want, j.calls)
+	}
+	if q.backoff != 1 {
+		t.Errorf("expected the backoff to recover after joins, got %d", q.backoff)
+	}
+	if got := q.seen.Links[links[1]].Status; got != Failed {
+		t.Errorf("expected the rejected link marked %q, got %q", Failed, got)
+	}
+	if q.seen.Count != 3 {
+		t.Errorf("expected 3 attempts counted, got %d", q.seen.Count)
+	}
+
+	// Settled links are not joined again after a restart
+	reloaded := newQueue(t, cfg, j, path)
+	if pending := reloaded.Pending(); len(pending) != 0 {
+		t.Errorf("expected nothing pending after reload, got %v", pending)
+	}
+}
+
+func TestQueue_DailyCap(t *testing.T) {
+	links := []string{"https://t.me/+AAAA", "https://t.me/+BBBB"}
+	cfg := config.JoinConfig{Invites: links, MinDelay: time.Millisecond, MaxDelay: time.Millisecond, DailyCap: 1}
+	j := &fakeJoiner{}
+	q := newQueue(t, cfg, j, filepath.Join(t.TempDir(), "join.json"))
+	now := time.Date(2026, 1, 2, 22, 0, 0, 0, time.UTC)
+	q.now = func() time.Time { return now }
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	q.Run(ctx)
+
+	if len(j.calls) != 1 {
+		t.Fatalf("expected one join before the cap, got %v", j.calls)
+	}
+	if wait := q.capWait(); wait != 2*time.Hour {
+		t.Errorf("expected to wait until midnight, got %s", wait)
+	}
+	now = now.Add(2 * time.Hour)
+	if wait := q.capWait(); wait != 0 {
+		t.Errorf("expected the cap reset on the next day, got %s", wait)
+	}
+}
+
+func TestQueue_TooManyChannels(t *testing.T) {
+	links := []string{"https://t.me/+AAAA"}
+	cfg := config.JoinConfig{Invites: links, MinDelay: time.Millisecond, MaxDelay: time.Millisecond, DailyCap: 5}
+	j := &fakeJoiner{script: map[string][]error{links[0]: {ErrTooManyChannels}}}
+	q := newQueue(t, cfg, j, filepath.Join(t.TempDir(), "join.json"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	q.Run(ctx)
+
+	if len(j.calls) != 1 || q.capWait() == 0 {
+		t.Errorf("expected one attempt then a wait for the next day, got calls %v", j.calls)
+	}
+	if len(q.Pending()) != 1 {
+		t.Error("expected the link left pending")
+	}
+}
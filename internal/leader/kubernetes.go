@@ -0,0 +1,248 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package leader
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// In-cluster service account credentials
+const serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// Lease timestamps use microsecond precision
+const microTimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+
+// Lock backed by a coordination.k8s.io/v1 Lease, spoken to over plain HTTP
+type KubernetesLease struct {
+	client    *http.Client
+	baseURL   string
+	tokenPath string
+	namespace string
+	name      string
+	now       func() time.Time
+}
+
+type lease struct {
+	APIVersion string        `json:"apiVersion"`
+	Kind       string        `json:"kind"`
+	Metadata   leaseMetadata `json:"metadata"`
+	Spec       leaseSpec     `json:"spec"`
+}
+
+type leaseMetadata struct {
+	Name            string `json:"name"`
+	Namespace       string `json:"namespace,omitempty"`
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+}
+
+type leaseSpec struct {
+	HolderIdentity       string `json:"holderIdentity,omitempty"`
+	LeaseDurationSeconds int    `json:"leaseDurationSeconds,omitempty"`
+	AcquireTime          string `json:"acquireTime,omitempty"`
+	RenewTime            string `json:"renewTime,omitempty"`
+	LeaseTransitions     int    `json:"leaseTransitions"`
+}
+
+// Create new KubernetesLease using the pod's service account.
+// An empty namespace selects the namespace the pod runs in.
+func NewKubernetesLease(namespace, name string) (*KubernetesLease, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, errors.New("not running in a Kubernetes cluster (KUBERNETES_SERVICE_HOST is unset)")
+	}
+
+	ca, err := os.ReadFile(serviceAccountDir + "/ca.crt")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cluster CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, errors.New("cluster CA contains no certificates")
+	}
+
+	if namespace == "" {
+		ns, err := os.ReadFile(serviceAccountDir + "/namespace")
+		if err != nil {
+			return nil, fmt.Errorf("failed to detect namespace: %w", err)
+		}
+		namespace = strings.TrimSpace(string(ns))
+	}
+
+	return &KubernetesLease{
+		client: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}},
+		},
+		baseURL:   "https://" + net.JoinHostPort(host, port),
+		tokenPath: serviceAccountDir + "/token",
+		namespace: namespace,
+		name:      name,
+		now:       time.Now,
+	}, nil
+}
+
+// Create, renew or take over an expired Lease
+func (k *KubernetesLease) TryAcquire(ctx context.Context, identity string, ttl time.Duration) (bool, error) {
+	now := k.now()
+	current, err := k.get(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if current == nil {
+		l := &lease{
+			APIVersion: "coordination.k8s.io/v1",
+			Kind:       "Lease",
+			Metadata:   leaseMetadata{Name: k.name, Namespace: k.namespace},
+			Spec: leaseSpec{
+				HolderIdentity:       identity,
+				LeaseDurationSeconds: int(ttl.Seconds()),
+				AcquireTime:          now.UTC().Format(microTimeFormat),
+				RenewTime:            now.UTC().Format(microTimeFormat),
+			},
+		}
+		return k.write(ctx, http.MethodPost, k.collectionURL(), l)
+	}
+
+	spec := &current.Spec
+	if spec.HolderIdentity != identity {
+		if spec.HolderIdentity != "" && !expired(spec, now) {
+			return false, nil
+		}
+		spec.HolderIdentity = identity
+		spec.AcquireTime = now.UTC().Format(microTimeFormat)
+		spec.LeaseTransitions++
+	}
+	spec.LeaseDurationSeconds = int(ttl.Seconds())
+	spec.RenewTime = now.UTC().Format(microTimeFormat)
+
+	// The resourceVersion makes concurrent takeovers fail with a conflict
+	return k.write(ctx, http.MethodPut, k.objectURL(), current)
+}
+
+// Clear the holder so candidates can acquire the Lease immediately
+func (k *KubernetesLease) Release(ctx context.Context, identity string) error {
+	current, err := k.get(ctx)
+	if err != nil || current == nil || current.Spec.HolderIdentity != identity {
+		return err
+	}
+	current.Spec.HolderIdentity = ""
+	_, err = k.write(ctx, http.MethodPut, k.objectURL(), current)
+	return err
+}
+
+// Report whether the holder stopped renewing the Lease
+func expired(spec *leaseSpec, now time.Time) bool {
+	renewed, err := time.Parse(microTimeFormat, spec.RenewTime)
+	if err != nil {
+		// Accept other RFC 3339 precisions written by other clients
+		if renewed, err = time.Parse(time.RFC3339Nano, spec.RenewTime); err != nil {
+			return true
+		}
+	}
+	return now.After(renewed.Add(time.Duration(spec.LeaseDurationSeconds) * time.Second))
+}
+
+func (k *KubernetesLease) collectionURL() string {
+	return fmt.Sprintf("%s/apis/coordination.k8s.io/v1/namespaces/%s/leases", k.baseURL, k.namespace)
+}
+
+func (k *KubernetesLease) objectURL() string {
+	return k.collectionURL() + "/" + k.name
+}
+
+// Fetch the Lease, returning nil if it does not exist yet
+func (k *KubernetesLease) get(ctx context.Context) (*lease, error) {
+	resp, err := k.do(ctx, http.MethodGet, k.objectURL(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		var l lease
+		if err := json.NewDecoder(resp.Body).Decode(&l); err != nil {
+			return nil, fmt.Errorf("failed to decode lease: %w", err)
+		}
+		return &l, nil
+	case http.StatusNotFound:
+		return nil, nil
+	default:
+		return nil, apiError(resp)
+	}
+}
+
+// Create or update the Lease, reporting false when another writer won the race
+func (k *KubernetesLease) write(ctx context.Context, method, url string, l *lease) (bool, error) {
+	body, err := json.Marshal(l)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := k.do(ctx, method, url, body)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated:
+		return true, nil
+	case http.StatusConflict:
+		return false, nil
+	default:
+		return false, apiError(resp)
+	}
+}
+
+func (k *KubernetesLease) do(ctx context.Context, method, url string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	// Projected tokens rotate, so read it for every request
+	token, err := os.ReadFile(k.tokenPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service account token: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	return k.client.Do(req)
+}
+
+func apiError(resp *http.Response) error {
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("kubernetes api returned status %d: %s", resp.StatusCode, msg)
+}
@@ -0,0 +1,128 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package leader
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Minimal Lease endpoint enforcing resourceVersion checks
+type fakeLeaseAPI struct {
+	mu      sync.Mutex
+	lease   *lease
+	version int
+}
+
+func (f *fakeLeaseAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "Bearer secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if f.lease == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(f.lease)
+	case http.MethodPost, http.MethodPut:
+		var l lease
+		if err := json.NewDecoder(r.Body).Decode(&l); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if (r.Method == http.MethodPost) != (f.lease == nil) ||
+			(f.lease != nil && l.Metadata.ResourceVersion != f.lease.Metadata.ResourceVersion) {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		f.version++
+		l.Metadata.ResourceVersion = strconv.Itoa(f.version)
+		f.lease = &l
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(f.lease)
+	}
+}
+
+func TestKubernetesLease(t *testing.T) {
+	api := &fakeLeaseAPI{}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenPath, []byte("secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Unix(1000, 0)
+	newLease := func() *KubernetesLease {
+		return &KubernetesLease{
+			client:    server.Client(),
+			baseURL:   server.URL,
+			tokenPath: tokenPath,
+			namespace: "default",
+			name:      "telegram-scout",
+			now:       func() time.Time { return now },
+		}
+	}
+	a, b := newLease(), newLease()
+	ctx := context.Background()
+	ttl := 15 * time.Second
+
+	acquire := func(k *KubernetesLease, identity string, want bool) {
+		t.Helper()
+		ok, err := k.TryAcquire(ctx, identity, ttl)
+		if err != nil {
+			t.Fatalf("TryAcquire(%s) failed: %v", identity, err)
+		}
+		if ok != want {
+			t.Fatalf("TryAcquire(%s) = %t, want %t", identity, ok, want)
+		}
+	}
+
+	acquire(a, "pod-a", true)  // Creates the lease
+	acquire(b, "pod-b", false) // Held by pod-a
+	acquire(a, "pod-a", true)  // Renewal
+
+	// pod-a stops renewing and the lease expires
+	now = now.Add(time.Minute)
+	acquire(b, "pod-b", true)
+	if api.lease.Spec.LeaseTransitions != 1 {
+		t.Errorf("expected 1 transition, got %d", api.lease.Spec.LeaseTransitions)
+	}
+	acquire(a, "pod-a", false)
+
+	if err := b.Release(ctx, "pod-b"); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	acquire(a, "pod-a", true)
+}
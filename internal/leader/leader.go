@@ -0,0 +1,182 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package leader
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+// Shared lock that at most one replica holds at a time
+type Lock interface {
+	// Acquire the lock for identity, or renew it if already held. Report whether identity holds it.
+	TryAcquire(ctx context.Context, identity string, ttl time.Duration) (bool, error)
+	// Give the lock up early so another replica can take over
+	Release(ctx context.Context, identity string) error
+}
+
+// Run work only while this replica holds the lock
+type Elector struct {
+	lock     Lock
+	identity string
+	ttl      time.Duration
+	renew    time.Duration
+	log      *zap.Logger
+
+	// Called on every leadership change
+	onChange func(leading bool)
+}
+
+// Create new Elector competing for lock as identity
+func NewElector(lock Lock, identity string, ttl, renew time.Duration, log *zap.Logger) *Elector {
+	return &Elector{
+		lock:     lock,
+		identity: identity,
+		ttl:      ttl,
+		renew:    renew,
+		log:      log,
+	}
+}
+
+// Create new Elector for the configured backend
+func New(cfg config.LeaderConfig, log *zap.Logger) (*Elector, error) {
+	var lock Lock
+	switch cfg.Backend {
+	case config.LeaderBackendKubernetes:
+		l, err := NewKubernetesLease(cfg.Namespace, cfg.Name)
+		if err != nil {
+			return nil, err
+		}
+		lock = l
+	default:
+		return nil, fmt.Errorf("unknown leader backend %q", cfg.Backend)
+	}
+	return NewElector(lock, cfg.Identity, cfg.LeaseDuration, cfg.RenewInterval, log), nil
+}
+
+// Register a callback invoked when leadership is gained or lost
+func (e *Elector) OnChange(fn func(leading bool)) {
+	e.onChange = fn
+}
+
+// Compete for leadership until ctx is cancelled, running lead while leading.
+// The context passed to lead is cancelled as soon as leadership is lost.
+func (e *Elector) Run(ctx context.Context, lead func(ctx context.Context)) {
+	e.notify(false)
+	for {
+		ok, err := e.lock.TryAcquire(ctx, e.identity, e.ttl)
+		if err != nil && ctx.Err() == nil {
+			e.log.Warn("Failed to acquire leadership", zap.Error(err))
+		}
+		wait := e.renew
+		if ok && e.lead(ctx, lead) {
+			// Whatever made lead stop is likely to again, let a standby take over first
+			wait = e.ttl
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// Run lead and keep renewing the lock, stepping down when renewal fails.
+// Report whether lead returned on its own, while still leading.
+func (e *Elector) lead(ctx context.Context, lead func(ctx context.Context)) bool {
+	e.log.Info("Acquired leadership", zap.String("identity", e.identity))
+	e.notify(true)
+
+	leadCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		// Stop renewing once there is no work to lead
+		defer cancel()
+		lead(leadCtx)
+	}()
+
+	lost := e.hold(leadCtx)
+	cancel()
+	<-done
+	e.notify(false)
+
+	stopped := !lost && ctx.Err() == nil
+	if lost && ctx.Err() == nil {
+		e.log.Warn("Lost leadership", zap.String("identity", e.identity))
+		return false
+	}
+
+	// Hand over quickly instead of letting the lease expire
+	releaseCtx, cancelRelease := context.WithTimeout(context.WithoutCancel(ctx), e.renew)
+	defer cancelRelease()
+	if err := e.lock.Release(releaseCtx, e.identity); err != nil {
+		e.log.Warn("Failed to release leadership", zap.Error(err))
+	}
+	if stopped {
+		e.log.Warn("Work stopped while leading, released leadership", zap.String("identity", e.identity))
+	} else {
+		e.log.Info("Released leadership", zap.String("identity", e.identity))
+	}
+	return stopped
+}
+
+// Renew the lock until ctx is cancelled or it can no longer be held safely,
+// reporting whether it was lost
+func (e *Elector) hold(ctx context.Context) bool {
+	ticker := time.NewTicker(e.renew)
+	defer ticker.Stop()
+	lastRenew := time.Now()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+
+		ok, err := e.lock.TryAcquire(ctx, e.identity, e.ttl)
+		switch {
+		case ctx.Err() != nil:
+			return false
+		case err != nil:
+			e.log.Warn("Failed to renew leadership", zap.Error(err))
+			// Step down before another replica may take the expired lease
+			if time.Since(lastRenew) >= e.ttl-e.renew {
+				return true
+			}
+		case !ok:
+			return true
+		default:
+			lastRenew = time.Now()
+		}
+	}
+}
+
+func (e *Elector) notify(leading bool) {
+	if e.onChange != nil {
+		e.onChange(leading)
+	}
+}
@@ -0,0 +1,147 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package leader
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// In-memory Lock shared by electors in a test
+type memLock struct {
+	mu     sync.Mutex
+	holder string
+}
+
+func (m *memLock) TryAcquire(ctx context.Context, identity string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.holder == "" || m.holder == identity {
+		m.holder = identity
+		return true, nil
+	}
+	return false, nil
+}
+
+func (m *memLock) Release(ctx context.Context, identity string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.holder == identity {
+		m.holder = ""
+	}
+	return nil
+}
+
+func (m *memLock) steal(identity string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.holder = identity
+}
+
+func TestElector(t *testing.T) {
+	lock := &memLock{}
+	leading := make(chan bool, 10)
+
+	a := NewElector(lock, "a", time.Second, 10*time.Millisecond, zap.NewNop())
+	a.OnChange(func(l bool) { leading <- l })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan context.Context, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		a.Run(ctx, func(ctx context.Context) {
+			started <- ctx
+			<-ctx.Done()
+		})
+	}()
+
+	expect := func(want bool) {
+		t.Helper()
+		select {
+		case got := <-leading:
+			if got != want {
+				t.Fatalf("expected leading=%t, got %t", want, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timeout waiting for leading=%t", want)
+		}
+	}
+
+	expect(false)
+	expect(true)
+	leadCtx := <-started
+
+	// Another replica taking the lock stops the work
+	lock.steal("b")
+	select {
+	case <-leadCtx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("expected lead context to be cancelled after losing the lock")
+	}
+	expect(false)
+
+	// Leadership is regained once the lock is free again
+	if err := lock.Release(context.Background(), "b"); err != nil {
+		t.Fatal(err)
+	}
+	expect(true)
+	<-started
+
+	// Shutting down hands the lock over
+	cancel()
+	<-done
+	expect(false)
+	if lock.holder != "" {
+		t.Errorf("expected lock to be released on shutdown, held by %q", lock.holder)
+	}
+}
+
+func TestElector_LeadReturns(t *testing.T) {
+	lock := &memLock{}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := NewElector(lock, "a", time.Hour, 10*time.Millisecond, zap.NewNop())
+	stopped := make(chan struct{})
+	go a.Run(ctx, func(ctx context.Context) { close(stopped) })
+	<-stopped
+
+	// Work stopping on its own hands the lock to a standby, well before the lease expires
+	b := NewElector(lock, "b", time.Hour, 10*time.Millisecond, zap.NewNop())
+	leading := make(chan bool, 10)
+	b.OnChange(func(l bool) { leading <- l })
+	go b.Run(ctx, func(ctx context.Context) { <-ctx.Done() })
+
+	deadline := time.After(time.Second)
+	for {
+		select {
+		case l := <-leading:
+			if l {
+				return
+			}
+		case <-deadline:
+			t.Fatalf("expected the standby to take over, lock held by %q", lock.holder)
+		}
+	}
+}
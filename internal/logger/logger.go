@@ -19,6 +19,7 @@
 package logger
 
 import (
+	"maps"
 	"os"
 	"time"
 
@@ -26,39 +27,57 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
-// Create new zap logger configured for console output.
-// Direct Info level and above to stdout, and Error level and above to stderr.
-func New() (*zap.Logger, error) {
-	// Configure encoder
-	encoderConfig := zap.NewProductionEncoderConfig()
+// Subsystems whose level can be set on their own
+const (
+	Telegram = "telegram" // The Telegram client
+	MTProto  = "mtproto"  // The gotd MTProto library, very verbose below warn
+	Scout    = "scout"    // Matching and alerting
+	Notifier = "notifier" // Alert delivery
+)
 
-	// Format time
-	encoderConfig.EncodeTime = func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
-		enc.AppendString("[" + t.Format(time.RFC3339) + "]")
-	}
+// Every subsystem, for validating configured names
+var Subsystems = []string{Telegram, MTProto, Scout, Notifier}
 
-	// Format level: [INFO]
-	encoderConfig.EncodeLevel = func(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
-		enc.AppendString("[" + l.CapitalString() + "]")
-	}
+// Levels of subsystems that are too noisy at the default level
+var defaultLevels = map[string]zapcore.Level{MTProto: zapcore.WarnLevel}
 
-	// Remove caller information
-	encoderConfig.EncodeCaller = nil
+// How to build a logger
+type Options struct {
+	JSON   bool                     // One JSON object per entry using zap's production encoder
+	Level  zapcore.Level            // Level of everything without a level of its own
+	Levels map[string]zapcore.Level // Per subsystem, see For
 
-	// Use spaces instead of tabs for separation
-	encoderConfig.ConsoleSeparator = " "
+	// Log the first SampleInitial entries with the same level and message each
+	// second and every SampleThereafter-th after that. Zero logs everything.
+	SampleInitial    int
+	SampleThereafter int
+}
 
-	// Use ConsoleEncoder instead of JSON for better readability
-	encoder := zapcore.NewConsoleEncoder(encoderConfig)
+// Create new zap logger, writing readable console lines or JSON objects.
+// Direct entries below Error level to stdout, and Error level and above to stderr.
+func New(opts Options) (*zap.Logger, error) {
+	encoder := consoleEncoder()
+	if opts.JSON {
+		encoder = zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+	}
+
+	levels := maps.Clone(defaultLevels)
+	maps.Copy(levels, opts.Levels)
+
+	// The cores let through the lowest level any subsystem wants
+	lowest := opts.Level
+	for _, l := range levels {
+		lowest = min(lowest, l)
+	}
 
 	// Direct high priority logs (Error, Panic, Fatal) to stderr
 	highPriority := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
 		return lvl >= zapcore.ErrorLevel
 	})
 
-	// Direct low priority logs (Info, Warn) to stdout
+	// Direct low priority logs (Debug, Info, Warn) to stdout
 	lowPriority := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
-		return lvl >= zapcore.InfoLevel && lvl < zapcore.ErrorLevel
+		return lvl >= lowest && lvl < zapcore.ErrorLevel
 	})
 
 	// Lock streams to prevent race conditions on writes
@@ -70,7 +89,71 @@ func New() (*zap.Logger, error) {
 		zapcore.NewCore(encoder, consoleErr, highPriority),
 		zapcore.NewCore(encoder, consoleOut, lowPriority),
 	)
+	if opts.SampleInitial > 0 {
+		core = zapcore.NewSamplerWithOptions(core, time.Second, opts.SampleInitial, opts.SampleThereafter)
+	}
 
 	// Build logger without AddCaller option
-	return zap.New(core), nil
+	return zap.New(&levelCore{Core: core, level: opts.Level, levels: levels}), nil
+}
+
+// Return the logger of a subsystem, at the subsystem's level when one is set.
+// Loggers not built by New are returned unchanged.
+func For(log *zap.Logger, subsystem string) *zap.Logger {
+	return log.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		lc, ok := core.(*levelCore)
+		if !ok {
+			return core
+		}
+		if l, ok := lc.levels[subsystem]; ok {
+			return &levelCore{Core: lc.Core, level: l, levels: lc.levels}
+		}
+		return core
+	}))
+}
+
+// Filter entries below a level, which For swaps per subsystem
+type levelCore struct {
+	zapcore.Core
+	level  zapcore.Level
+	levels map[string]zapcore.Level
+}
+
+func (c *levelCore) Enabled(l zapcore.Level) bool {
+	return l >= c.level && c.Core.Enabled(l)
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), level: c.level, levels: c.levels}
+}
+
+func (c *levelCore) Check(e zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if e.Level < c.level {
+		return ce
+	}
+	return c.Core.Check(e, ce)
+}
+
+func consoleEncoder() zapcore.Encoder {
+	// Configure encoder
+	encoderConfig := zap.NewProductionEncoderConfig()
+
+	// Format time
+	encoderConfig.EncodeTime = func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+		enc.AppendString("[" + t.Format(time.RFC3339) + "]")
+	}
+
+	// Format level: [INFO]
+	encoderConfig.EncodeLevel = func(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+		enc.AppendString("[" + l.CapitalString() + "]")
+	}
+
+	// Remove caller information
+	encoderConfig.EncodeCaller = nil
+
+	// Use spaces instead of tabs for separation
+	encoderConfig.ConsoleSeparator = " "
+
+	// Use ConsoleEncoder instead of JSON for better readability
+	return zapcore.NewConsoleEncoder(encoderConfig)
 }
@@ -19,28 +19,82 @@
 package logger
 
 import (
+	"context"
+	"slices"
 	"testing"
 
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func TestNew(t *testing.T) {
-	l, err := New()
-	if err != nil {
-		t.Fatalf("failed to create logger: %v", err)
+	for _, opts := range []Options{{}, {JSON: true}, {Level: zapcore.DebugLevel, SampleInitial: 10, SampleThereafter: 100}} {
+		l, err := New(opts)
+		if err != nil {
+			t.Fatalf("failed to create logger: %v", err)
+		}
+		if l == nil {
+			t.Fatal("expected logger instance, got nil")
+		}
+
+		// Verify logging to both streams without panic
+		l.Info("test info message")
+		l.Warn("test warning message")
+		l.Error("test error message")
+
+		// Verify structured logging fields
+		l.Info("test with fields", zap.String("key", "value"))
+
+		// Ignore sync error on stdout/stderr
+		_ = l.Sync()
+	}
+}
+
+func TestFor(t *testing.T) {
+	obs, logs := observer.New(zapcore.DebugLevel)
+	log := zap.New(&levelCore{
+		Core:   obs,
+		level:  zapcore.InfoLevel,
+		levels: map[string]zapcore.Level{Scout: zapcore.DebugLevel, MTProto: zapcore.WarnLevel},
+	})
+
+	log.Debug("root debug")
+	For(log, Scout).Debug("scout debug")
+	For(log, MTProto).Info("mtproto info")
+	For(log, MTProto).With(zap.Int("n", 1)).Warn("mtproto warn")
+	For(log, Notifier).Info("notifier info")
+	For(For(log, MTProto), Scout).Debug("rescoped debug")
+
+	var got []string
+	for _, e := range logs.All() {
+		got = append(got, e.Message)
 	}
-	if l == nil {
-		t.Fatal("expected logger instance, got nil")
+	want := []string{"scout debug", "mtproto warn", "notifier info", "rescoped debug"}
+	if !slices.Equal(got, want) {
+		t.Errorf("logged %q, want %q", got, want)
 	}
 
-	// Verify logging to both streams without panic
-	l.Info("test info message")
-	l.Warn("test warning message")
-	l.Error("test error message")
+	// Loggers built elsewhere are left alone
+	if nop := zap.NewNop(); For(nop, Scout).Core() != nop.Core() {
+		t.Error("expected the core of a foreign logger unchanged")
+	}
+}
 
-	// Verify structured logging fields
-	l.Info("test with fields", zap.String("key", "value"))
+func TestTrace(t *testing.T) {
+	id := NewTraceID()
+	if len(id) != 16 || id == NewTraceID() {
+		t.Errorf("expected distinct 16 character IDs, got %q", id)
+	}
 
-	// Ignore sync error on stdout/stderr
-	_ = l.Sync()
+	ctx := WithTraceID(context.Background(), id)
+	if got := TraceID(ctx); got != id {
+		t.Errorf("TraceID() = %q, want %q", got, id)
+	}
+	if f := Trace(ctx); f.Key != traceKey || f.String != id {
+		t.Errorf("unexpected field %+v", f)
+	}
+	if f := Trace(context.Background()); f.Type != zapcore.SkipType {
+		t.Errorf("expected the field skipped without an ID, got %+v", f)
+	}
 }
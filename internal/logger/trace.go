@@ -0,0 +1,66 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"go.uber.org/zap"
+)
+
+// Name of the correlation ID field in log entries
+const traceKey = "trace_id"
+
+type traceIDKey struct{}
+
+// Return a new correlation ID for a received message
+func NewTraceID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// Attach the correlation ID of the message being handled
+func WithTraceID(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, traceIDKey{}, id)
+}
+
+// Return the correlation ID attached to ctx, if any
+func TraceID(ctx context.Context) string {
+	id, _ := ctx.Value(traceIDKey{}).(string)
+	return id
+}
+
+// Return the correlation ID attached to ctx as a log field, skipped when there is none
+func Trace(ctx context.Context) zap.Field {
+	return TraceField(TraceID(ctx))
+}
+
+// Return a correlation ID as a log field, skipped when empty
+func TraceField(id string) zap.Field {
+	if id == "" {
+		return zap.Skip()
+	}
+	return zap.String(traceKey, id)
+}
@@ -0,0 +1,156 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package media
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Returned for media above the configured size limit
+var ErrTooLarge = errors.New("media exceeds max_size")
+
+// Download media of messages the client received recently
+type Fetcher interface {
+	Download(ctx context.Context, chatID int64, msgID int, w io.Writer) error
+}
+
+// Persist archived objects
+type Store interface {
+	Put(ctx context.Context, obj Object) error
+	// Return a URL the object can be fetched from
+	URL(ctx context.Context, key string) (string, error)
+}
+
+// A file to upload along with its metadata
+type Object struct {
+	Key         string
+	Body        io.Reader
+	Size        int64
+	ContentType string
+	Metadata    map[string]string
+}
+
+// Copy media of matched messages into durable storage
+type Archiver struct {
+	fetch   Fetcher
+	store   Store
+	prefix  string
+	maxSize int64
+}
+
+// Create new Archiver downloading through fetch and uploading to store
+func NewArchiver(fetch Fetcher, store Store, cfg config.MediaArchiveConfig) *Archiver {
+	maxSize := cfg.MaxSize
+	if maxSize <= 0 {
+		maxSize = config.DefaultMediaMaxSize
+	}
+	return &Archiver{fetch: fetch, store: store, prefix: cfg.Prefix, maxSize: maxSize}
+}
+
+// Upload the message's media and return its durable URL, or "" if nothing is archivable
+func (a *Archiver) Archive(ctx context.Context, msg model.Message) (string, error) {
+	// Stickers, polls and link previews are not worth keeping
+	switch msg.Media {
+	case "photo", "video", "animation", "voice", "audio", "document":
+	default:
+		return "", nil
+	}
+	if msg.File != nil && msg.File.Size > a.maxSize {
+		return "", ErrTooLarge
+	}
+
+	var buf bytes.Buffer
+	w := &limitWriter{w: &buf, remaining: a.maxSize}
+	if err := a.fetch.Download(ctx, msg.ChatID, msg.ID, w); err != nil {
+		return "", fmt.Errorf("download failed: %w", err)
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	hash := hex.EncodeToString(sum[:])
+	contentType := contentType(msg, buf.Bytes())
+	key := path.Join(a.prefix, strconv.FormatInt(msg.ChatID, 10),
+		fmt.Sprintf("%d-%s%s", msg.ID, hash[:16], extension(msg, contentType)))
+
+	err := a.store.Put(ctx, Object{
+		Key:         key,
+		Body:        &buf,
+		Size:        int64(buf.Len()),
+		ContentType: contentType,
+		Metadata: map[string]string{
+			"chat-id": strconv.FormatInt(msg.ChatID, 10),
+			"msg-id":  strconv.Itoa(msg.ID),
+			"sha256":  hash,
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("upload failed: %w", err)
+	}
+	return a.store.URL(ctx, key)
+}
+
+// Prefer the MIME type Telegram reported, sniffing the content otherwise
+func contentType(msg model.Message, data []byte) string {
+	if msg.File != nil && msg.File.MimeType != "" {
+		return msg.File.MimeType
+	}
+	return http.DetectContentType(data)
+}
+
+// Keep the original extension so archived files open with the right application
+func extension(msg model.Message, contentType string) string {
+	if msg.File != nil {
+		if ext := path.Ext(msg.File.Name); ext != "" {
+			return ext
+		}
+	}
+	if msg.Media == "photo" {
+		return ".jpg"
+	}
+	if exts, _ := mime.ExtensionsByType(contentType); len(exts) > 0 {
+		return exts[0]
+	}
+	return ""
+}
+
+// Fail writes once more than the allowed number of bytes arrive
+type limitWriter struct {
+	w         io.Writer
+	remaining int64
+}
+
+func (l *limitWriter) Write(p []byte) (int, error) {
+	if int64(len(p)) > l.remaining {
+		return 0, ErrTooLarge
+	}
+	l.remaining -= int64(len(p))
+	return l.w.Write(p)
+}
@@ -0,0 +1,141 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package media
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Serve fixed content for every message
+type fakeFetcher struct {
+	data  string
+	calls int
+}
+
+func (f *fakeFetcher) Download(ctx context.Context, chatID int64, msgID int, w io.Writer) error {
+	f.calls++
+	_, err := io.WriteString(w, f.data)
+	return err
+}
+
+// Keep uploaded objects in memory
+type fakeStore struct {
+	objects map[string]Object
+	bodies  map[string]string
+}
+
+func (f *fakeStore) Put(ctx context.Context, obj Object) error {
+	body, err := io.ReadAll(obj.Body)
+	if err != nil {
+		return err
+	}
+	if f.objects == nil {
+		f.objects = make(map[string]Object)
+		f.bodies = make(map[string]string)
+	}
+	f.objects[obj.Key] = obj
+	f.bodies[obj.Key] = string(body)
+	return nil
+}
+
+func (f *fakeStore) URL(ctx context.Context, key string) (string, error) {
+	return "https://media.example.com/" + key, nil
+}
+
+func TestArchiver_Archive(t *testing.T) {
+	fetch := &fakeFetcher{data: "hello world"}
+	store := &fakeStore{}
+	a := NewArchiver(fetch, store, config.MediaArchiveConfig{Prefix: "scout"})
+
+	msg := model.Message{
+		ID:     7,
+		ChatID: -1001234,
+		Media:  "document",
+		File:   &model.File{Name: "report.pdf", MimeType: "application/pdf", Size: 11},
+	}
+	url, err := a.Archive(context.Background(), msg)
+	if err != nil {
+		t.Fatalf("Archive() error = %v", err)
+	}
+
+	// sha256("hello world")
+	const sum = "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"
+	key := "scout/-1001234/7-" + sum[:16] + ".pdf"
+	if url != "https://media.example.com/"+key {
+		t.Errorf("unexpected URL %q", url)
+	}
+	obj, ok := store.objects[key]
+	if !ok {
+		t.Fatalf("object %q not stored, have %v", key, store.objects)
+	}
+	if store.bodies[key] != "hello world" || obj.Size != 11 || obj.ContentType != "application/pdf" {
+		t.Errorf("unexpected object %+v body %q", obj, store.bodies[key])
+	}
+	if obj.Metadata["sha256"] != sum || obj.Metadata["chat-id"] != "-1001234" || obj.Metadata["msg-id"] != "7" {
+		t.Errorf("unexpected metadata %v", obj.Metadata)
+	}
+}
+
+func TestArchiver_Photo(t *testing.T) {
+	store := &fakeStore{}
+	a := NewArchiver(&fakeFetcher{data: "\xff\xd8\xff\xe0jpeg"}, store, config.MediaArchiveConfig{})
+
+	url, err := a.Archive(context.Background(), model.Message{ID: 1, ChatID: 5, Media: "photo"})
+	if err != nil {
+		t.Fatalf("Archive() error = %v", err)
+	}
+	if !strings.HasPrefix(url, "https://media.example.com/5/1-") || !strings.HasSuffix(url, ".jpg") {
+		t.Errorf("unexpected URL %q", url)
+	}
+	for _, obj := range store.objects {
+		if obj.ContentType != "image/jpeg" {
+			t.Errorf("expected sniffed content type, got %q", obj.ContentType)
+		}
+	}
+}
+
+func TestArchiver_Skips(t *testing.T) {
+	fetch := &fakeFetcher{data: "0123456789"}
+	a := NewArchiver(fetch, &fakeStore{}, config.MediaArchiveConfig{MaxSize: 8})
+
+	// Nothing to download
+	url, err := a.Archive(context.Background(), model.Message{Media: "sticker"})
+	if url != "" || err != nil || fetch.calls != 0 {
+		t.Errorf("expected stickers to be skipped, got %q %v", url, err)
+	}
+
+	// Declared size over the limit
+	_, err = a.Archive(context.Background(), model.Message{Media: "video", File: &model.File{Size: 9}})
+	if !errors.Is(err, ErrTooLarge) || fetch.calls != 0 {
+		t.Errorf("expected ErrTooLarge before downloading, got %v", err)
+	}
+
+	// Actual size over the limit
+	_, err = a.Archive(context.Background(), model.Message{Media: "photo"})
+	if !errors.Is(err, ErrTooLarge) {
+		t.Errorf("expected ErrTooLarge while downloading, got %v", err)
+	}
+}
@@ -0,0 +1,163 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package media
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/gif"  // Register decoder for reference images
+	_ "image/jpeg" // Telegram photos are always JPEG
+	_ "image/png"  // Register decoder for reference images
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/model"
+	"github.com/h3nc4/TelegramScout/internal/phash"
+)
+
+// Largest image downloaded for hashing, in bytes
+const maxImageSize = 20 << 20
+
+// Compare images of messages against reference images by perceptual hash
+type ImageMatcher struct {
+	fetch Fetcher
+	refs  []reference
+}
+
+type reference struct {
+	name        string
+	hash        uint64
+	maxDistance int
+}
+
+// Create new ImageMatcher, hashing the reference images of every rule
+func NewImageMatcher(fetch Fetcher, rules []config.ImageRule) (*ImageMatcher, error) {
+	m := &ImageMatcher{fetch: fetch}
+	for _, rule := range rules {
+		paths, err := referencePaths(rule.Path)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range paths {
+			hash, err := hashFile(p)
+			if err != nil {
+				return nil, fmt.Errorf("reference image %s: %w", p, err)
+			}
+			name := rule.Name
+			if name == "" || p != rule.Path {
+				name = strings.TrimSuffix(filepath.Base(p), filepath.Ext(p))
+				if rule.Name != "" {
+					name = rule.Name + "/" + name
+				}
+			}
+			m.refs = append(m.refs, reference{name: name, hash: hash, maxDistance: rule.MaxDistance})
+		}
+	}
+	return m, nil
+}
+
+// Return the number of loaded reference images
+func (m *ImageMatcher) Len() int {
+	return len(m.refs)
+}
+
+// Return the closest reference within its distance and how far it is, or "" if none matches
+func (m *ImageMatcher) MatchImage(ctx context.Context, msg model.Message) (string, int, error) {
+	if !isImage(msg) || len(m.refs) == 0 {
+		return "", 0, nil
+	}
+	if msg.File != nil && msg.File.Size > maxImageSize {
+		return "", 0, nil
+	}
+
+	var buf bytes.Buffer
+	if err := m.fetch.Download(ctx, msg.ChatID, msg.ID, &limitWriter{w: &buf, remaining: maxImageSize}); err != nil {
+		return "", 0, fmt.Errorf("download failed: %w", err)
+	}
+	img, _, err := image.Decode(&buf)
+	if err != nil {
+		return "", 0, fmt.Errorf("decode failed: %w", err)
+	}
+	hash := phash.Hash(img)
+
+	best, bestDistance := "", 0
+	for _, ref := range m.refs {
+		d := phash.Distance(hash, ref.hash)
+		if d <= ref.maxDistance && (best == "" || d < bestDistance) {
+			best, bestDistance = ref.name, d
+		}
+	}
+	return best, bestDistance, nil
+}
+
+// Report whether the message carries a still image that can be decoded
+func isImage(msg model.Message) bool {
+	if msg.Media == "photo" {
+		return true
+	}
+	// Images sent uncompressed arrive as documents
+	return msg.Media == "document" && msg.File != nil && strings.HasPrefix(msg.File.MimeType, "image/")
+}
+
+// Expand a directory into the image files it contains
+func referencePaths(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, e := range entries {
+		switch strings.ToLower(filepath.Ext(e.Name())) {
+		case ".jpg", ".jpeg", ".png", ".gif":
+			if !e.IsDir() {
+				paths = append(paths, filepath.Join(path, e.Name()))
+			}
+		}
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no reference images in %s", path)
+	}
+	return paths, nil
+}
+
+func hashFile(path string) (uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = f.Close() }()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return 0, err
+	}
+	return phash.Hash(img), nil
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package media
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Draw a gradient whose direction tells images apart
+func gradient(w, h int, vertical bool) image.Image {
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			v := x * 255 / w
+			if vertical {
+				v = y * 255 / h
+			}
+			if x < w/4 && y < h/4 {
+				v = 255 - v
+			}
+			img.SetGray(x, y, color.Gray{Y: uint8(v)})
+		}
+	}
+	return img
+}
+
+func encode(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestImageMatcher(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "horizontal.png"), encode(t, gradient(200, 200, false)), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	fetch := &fakeFetcher{}
+	m, err := NewImageMatcher(fetch, []config.ImageRule{{Name: "refs", Path: dir, MaxDistance: 10}})
+	if err != nil {
+		t.Fatalf("NewImageMatcher() error = %v", err)
+	}
+	if m.Len() != 1 {
+		t.Fatalf("expected 1 reference, got %d", m.Len())
+	}
+
+	// A rescaled copy matches
+	fetch.data = string(encode(t, gradient(80, 80, false)))
+	name, distance, err := m.MatchImage(context.Background(), model.Message{Media: "photo"})
+	if err != nil || name != "refs/horizontal" || distance > 10 {
+		t.Errorf("expected match, got %q %d %v", name, distance, err)
+	}
+
+	// A different image does not
+	fetch.data = string(encode(t, gradient(80, 80, true)))
+	if name, _, err := m.MatchImage(context.Background(), model.Message{Media: "photo"}); err != nil || name != "" {
+		t.Errorf("expected no match, got %q %v", name, err)
+	}
+
+	// Only images are downloaded
+	calls := fetch.calls
+	if name, _, _ := m.MatchImage(context.Background(), model.Message{Media: "document", File: &model.File{MimeType: "application/pdf"}}); name != "" || fetch.calls != calls {
+		t.Error("expected non-image documents to be skipped")
+	}
+}
+
+func TestImageMatcher_MissingReference(t *testing.T) {
+	if _, err := NewImageMatcher(&fakeFetcher{}, []config.ImageRule{{Path: filepath.Join(t.TempDir(), "missing.png")}}); err == nil {
+		t.Error("expected error for missing reference image")
+	}
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package media
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+// Longest validity S3 allows for presigned URLs
+const presignTTL = 7 * 24 * time.Hour
+
+// Store backed by an S3-compatible bucket
+type S3Store struct {
+	client    *minio.Client
+	bucket    string
+	publicURL string
+}
+
+// Create new S3Store for the configured bucket
+func NewS3Store(cfg config.MediaArchiveConfig) (*S3Store, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("media_archive.bucket is required")
+	}
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure: !cfg.Insecure,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &S3Store{
+		client:    client,
+		bucket:    cfg.Bucket,
+		publicURL: strings.TrimSuffix(cfg.PublicURL, "/"),
+	}, nil
+}
+
+// Upload the object with its metadata
+func (s *S3Store) Put(ctx context.Context, obj Object) error {
+	_, err := s.client.PutObject(ctx, s.bucket, obj.Key, obj.Body, obj.Size, minio.PutObjectOptions{
+		ContentType:  obj.ContentType,
+		UserMetadata: obj.Metadata,
+	})
+	return err
+}
+
+// Return the public URL of the object, or a presigned one for private buckets
+func (s *S3Store) URL(ctx context.Context, key string) (string, error) {
+	if s.publicURL != "" {
+		return s.publicURL + "/" + key, nil
+	}
+	u, err := s.client.PresignedGetObject(ctx, s.bucket, key, presignTTL, nil)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
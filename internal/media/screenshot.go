@@ -0,0 +1,131 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package media
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Largest screenshot accepted from the rendering service
+const maxScreenshotSize = 10 << 20
+
+// Image formats accepted from the rendering service, by extension
+var screenshotTypes = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/webp": ".webp",
+}
+
+// Render linked pages through an external screenshot service and archive the images.
+// The service receives a POST with {"url": "..."} and answers with the image,
+// as browserless's /screenshot endpoint does.
+type Screenshotter struct {
+	client   *http.Client
+	endpoint string
+	store    Store
+	prefix   string
+}
+
+// Create new Screenshotter uploading the rendered pages to store
+func NewScreenshotter(store Store, cfg config.ScreenshotConfig, prefix string) *Screenshotter {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = config.DefaultScreenshotTimeout
+	}
+	return &Screenshotter{
+		client:   &http.Client{Timeout: timeout},
+		endpoint: cfg.Endpoint,
+		store:    store,
+		prefix:   prefix,
+	}
+}
+
+// Render link and return the URL of the stored screenshot
+func (s *Screenshotter) Screenshot(ctx context.Context, msg model.Message, link string) (string, error) {
+	data, contentType, err := s.render(ctx, link)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256([]byte(link))
+	key := path.Join(s.prefix, strconv.FormatInt(msg.ChatID, 10),
+		fmt.Sprintf("%d-page-%s%s", msg.ID, hex.EncodeToString(sum[:8]), screenshotTypes[contentType]))
+
+	err = s.store.Put(ctx, Object{
+		Key:         key,
+		Body:        bytes.NewReader(data),
+		Size:        int64(len(data)),
+		ContentType: contentType,
+		Metadata: map[string]string{
+			"chat-id":     strconv.FormatInt(msg.ChatID, 10),
+			"msg-id":      strconv.Itoa(msg.ID),
+			"source-url":  link,
+			"rendered-at": time.Now().UTC().Format(time.RFC3339),
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("upload failed: %w", err)
+	}
+	return s.store.URL(ctx, key)
+}
+
+// Ask the rendering service for a screenshot of link
+func (s *Screenshotter) render(ctx context.Context, link string) ([]byte, string, error) {
+	body, err := json.Marshal(map[string]string{"url": link})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("network error: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("renderer returned status: %d", resp.StatusCode)
+	}
+
+	var buf bytes.Buffer
+	if _, err := io.Copy(&limitWriter{w: &buf, remaining: maxScreenshotSize}, resp.Body); err != nil {
+		return nil, "", fmt.Errorf("failed to read screenshot: %w", err)
+	}
+	contentType := http.DetectContentType(buf.Bytes())
+	if _, ok := screenshotTypes[contentType]; !ok {
+		return nil, "", fmt.Errorf("renderer returned %s instead of an image", contentType)
+	}
+	return buf.Bytes(), contentType, nil
+}
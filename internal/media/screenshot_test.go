@@ -0,0 +1,79 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package media
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+func TestScreenshotter_Screenshot(t *testing.T) {
+	var rendered string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			URL string `json:"url"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		rendered = req.URL
+		if strings.Contains(req.URL, "broken") {
+			_, _ = w.Write([]byte("<html>not an image</html>"))
+			return
+		}
+		_, _ = w.Write([]byte("\x89PNG\r\n\x1a\npixels"))
+	}))
+	defer server.Close()
+
+	store := &fakeStore{}
+	s := NewScreenshotter(store, config.ScreenshotConfig{Endpoint: server.URL}, "scout")
+	msg := model.Message{ID: 3, ChatID: -1005}
+
+	url, err := s.Screenshot(context.Background(), msg, "https://shop.example.com/item")
+	if err != nil {
+		t.Fatalf("Screenshot() error = %v", err)
+	}
+	if rendered != "https://shop.example.com/item" {
+		t.Errorf("expected the link to be rendered, got %q", rendered)
+	}
+	if !strings.HasPrefix(url, "https://media.example.com/scout/-1005/3-page-") || !strings.HasSuffix(url, ".png") {
+		t.Errorf("unexpected URL %q", url)
+	}
+	for _, obj := range store.objects {
+		if obj.ContentType != "image/png" || obj.Metadata["source-url"] != "https://shop.example.com/item" {
+			t.Errorf("unexpected object %+v", obj)
+		}
+	}
+
+	// Error pages are not stored as screenshots
+	if _, err := s.Screenshot(context.Background(), msg, "https://broken.example.com"); err == nil {
+		t.Error("expected error for non-image response")
+	}
+	if len(store.objects) != 1 {
+		t.Errorf("expected a single stored screenshot, got %d", len(store.objects))
+	}
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package mentions finds references to other Telegram chats in message text:
+// t.me links, invite links and @username mentions.
+package mentions
+
+import (
+	"regexp"
+	"strings"
+)
+
+// t.me and telegram.me links, capturing the first path segment and what follows
+var linkPattern = regexp.MustCompile(`(?i)(?:https?://)?(?:www\.)?(?:t|telegram)\.me/(joinchat/|\+)?([A-Za-z0-9_\-]+)`)
+
+// @username not preceded by a word character, so e-mail addresses are ignored
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([A-Za-z][A-Za-z0-9_]{3,31})\b`)
+
+// Valid public usernames
+var usernamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]{3,31}$`)
+
+// Phone numbers share the t.me/+ prefix with invite links
+var phonePattern = regexp.MustCompile(`^[0-9]+$`)
+
+// t.me paths that are not chats
+var reservedPaths = map[string]bool{
+	"c": true, "s": true, "iv": true, "share": true, "proxy": true, "socks": true,
+	"addstickers": true, "addemoji": true, "addtheme": true, "addlist": true,
+	"setlanguage": true, "login": true, "confirmphone": true, "invoice": true,
+	"boost": true, "giftcode": true, "contact": true, "bg": true,
+}
+
+// A chat referenced from a message, by public username or private invite
+type Ref struct {
+	Username string // Lowercased, without the @
+	Invite   string // Invite hash
+}
+
+// Return the reference as it would be written in a message
+func (r Ref) String() string {
+	if r.Invite != "" {
+		return "t.me/+" + r.Invite
+	}
+	return "@" + r.Username
+}
+
+// What is known about a referenced chat
+type Info struct {
+	ID        int64 // Bot API ID, zero for invites to chats the account has not joined
+	Title     string
+	Username  string
+	Members   int
+	Broadcast bool // A channel rather than a group
+	Joined    bool // The monitoring account is already a member
+}
+
+// Return the distinct chats referenced in text, in order of appearance
+func Extract(text string) []Ref {
+	var refs []Ref
+	seen := make(map[Ref]bool)
+	add := func(r Ref) {
+		if !seen[r] {
+			seen[r] = true
+			refs = append(refs, r)
+		}
+	}
+
+	for _, m := range linkPattern.FindAllStringSubmatch(text, -1) {
+		invite, path := m[1] != "", m[2]
+		switch {
+		case invite && !phonePattern.MatchString(path):
+			add(Ref{Invite: path})
+		case !invite && !reservedPaths[strings.ToLower(path)] && usernamePattern.MatchString(path):
+			add(Ref{Username: strings.ToLower(path)})
+		}
+	}
+	for _, m := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		add(Ref{Username: strings.ToLower(m[1])})
+	}
+	return refs
+}
@@ -0,0 +1,50 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package mentions
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtract(t *testing.T) {
+	text := "Join https://t.me/+AbCdEf123_x or t.me/joinchat/QwErTy, follow @NewsChannel and " +
+		"https://t.me/newschannel/42. Call t.me/+15551234567, mail me@example.com, " +
+		"see t.me/c/1234/5 and t.me/addstickers/pack, ping @ab"
+	want := []Ref{
+		{Invite: "AbCdEf123_x"},
+		{Invite: "QwErTy"},
+		{Username: "newschannel"},
+	}
+	if got := Extract(text); !reflect.DeepEqual(got, want) {
+		t.Errorf("Extract() = %+v, want %+v", got, want)
+	}
+	if refs := Extract("nothing to see"); len(refs) != 0 {
+		t.Errorf("expected no references, got %v", refs)
+	}
+}
+
+func TestRef_String(t *testing.T) {
+	if got := (Ref{Username: "news"}).String(); got != "@news" {
+		t.Errorf("unexpected username form %q", got)
+	}
+	if got := (Ref{Invite: "AbC"}).String(); got != "t.me/+AbC" {
+		t.Errorf("unexpected invite form %q", got)
+	}
+}
@@ -0,0 +1,282 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package metrics pushes the expvar counters to a Prometheus Pushgateway or a
+// StatsD agent, for deployments where nothing scrapes the admin listener.
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"maps"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+// A counter read from expvar
+type Sample struct {
+	Name  string // Of the expvar variable
+	Key   string // Within a map variable, empty for plain numbers
+	Value float64
+}
+
+// Vars published by the runtime rather than the application
+var runtimeVars = []string{"cmdline", "memstats"}
+
+// Read every numeric expvar variable, including the numbers inside maps
+func Collect() []Sample {
+	var samples []Sample
+	expvar.Do(func(kv expvar.KeyValue) {
+		if slices.Contains(runtimeVars, kv.Key) {
+			return
+		}
+		if m, ok := kv.Value.(*expvar.Map); ok {
+			m.Do(func(e expvar.KeyValue) {
+				if v, ok := number(e.Value); ok {
+					samples = append(samples, Sample{Name: kv.Key, Key: e.Key, Value: v})
+				}
+			})
+			return
+		}
+		if v, ok := number(kv.Value); ok {
+			samples = append(samples, Sample{Name: kv.Key, Value: v})
+		}
+	})
+	return samples
+}
+
+func number(v expvar.Var) (float64, bool) {
+	switch n := v.(type) {
+	case *expvar.Int:
+		return float64(n.Value()), true
+	case *expvar.Float:
+		return n.Value(), true
+	}
+	return 0, false
+}
+
+// Push samples on an interval to the configured backends
+type Pusher struct {
+	cfg      config.MetricsConfig
+	client   *http.Client
+	instance string
+	labels   map[string]string // Added to the grouping key and StatsD tags
+	tags     string            // DogStatsD tag suffix, empty without a name or labels
+	log      *zap.Logger
+	collect  func() []Sample
+}
+
+// Create a Pusher, labelling pushes with the instance name, or the host name
+// when unnamed, so instances do not overwrite each other
+func New(cfg config.MetricsConfig, inst config.InstanceConfig, log *zap.Logger) *Pusher {
+	host := inst.Name
+	if host == "" {
+		var err error
+		if host, err = os.Hostname(); err != nil {
+			host = "unknown"
+		}
+	}
+	return &Pusher{
+		cfg:      cfg,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		instance: host,
+		labels:   inst.Labels,
+		tags:     statsdTags(inst),
+		log:      log,
+		collect:  Collect,
+	}
+}
+
+// Push every interval until ctx is done
+func (p *Pusher) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := p.Push(ctx); err != nil {
+				p.log.Warn("Failed to push metrics", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Send the current samples to every configured backend
+func (p *Pusher) Push(ctx context.Context) error {
+	samples := p.collect()
+	var errs []error
+	if p.cfg.Pushgateway != "" {
+		if err := p.pushGateway(ctx, samples); err != nil {
+			errs = append(errs, fmt.Errorf("pushgateway: %w", err))
+		}
+	}
+	if p.cfg.StatsD != "" {
+		if err := p.pushStatsD(ctx, samples); err != nil {
+			errs = append(errs, fmt.Errorf("statsd: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Replace this instance's group on the Pushgateway, in the text exposition format
+func (p *Pusher) pushGateway(ctx context.Context, samples []Sample) error {
+	var body bytes.Buffer
+	typed := make(map[string]bool)
+	for _, s := range samples {
+		name := metricName(p.cfg.Prefix + "_" + s.Name)
+		if !typed[name] {
+			fmt.Fprintf(&body, "# TYPE %s untyped\n", name)
+			typed[name] = true
+		}
+		if s.Key == "" {
+			fmt.Fprintf(&body, "%s %s\n", name, formatValue(s.Value))
+		} else {
+			fmt.Fprintf(&body, "%s{key=\"%s\"} %s\n", name, labelEscaper.Replace(s.Key), formatValue(s.Value))
+		}
+	}
+
+	u := fmt.Sprintf("%s/metrics/job/%s/instance/%s",
+		strings.TrimRight(p.cfg.Pushgateway, "/"), url.PathEscape(p.cfg.Job), url.PathEscape(p.instance))
+	for _, name := range slices.Sorted(maps.Keys(p.labels)) {
+		u += "/" + url.PathEscape(name) + "/" + url.PathEscape(p.labels[name])
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Largest StatsD datagram that fits a typical MTU without fragmenting
+const statsdPacket = 1432
+
+// Send every sample as a gauge, batching lines into datagrams
+func (p *Pusher) pushStatsD(ctx context.Context, samples []Sample) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "udp", p.cfg.StatsD)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+
+	var packet []byte
+	flush := func() error {
+		if len(packet) == 0 {
+			return nil
+		}
+		_, err := conn.Write(packet)
+		packet = packet[:0]
+		return err
+	}
+	for _, s := range samples {
+		name := p.cfg.Prefix + "." + statsdName(s.Name)
+		if s.Key != "" {
+			name += "." + statsdName(s.Key)
+		}
+		line := name + ":" + formatValue(s.Value) + "|g" + p.tags
+		if len(packet) > 0 && len(packet)+1+len(line) > statsdPacket {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+		if len(packet) > 0 {
+			packet = append(packet, '\n')
+		}
+		packet = append(packet, line...)
+	}
+	return flush()
+}
+
+// Format the instance name and labels as DogStatsD tags
+func statsdTags(inst config.InstanceConfig) string {
+	var tags []string
+	if inst.Name != "" {
+		tags = append(tags, "instance:"+statsdTag(inst.Name))
+	}
+	for _, name := range slices.Sorted(maps.Keys(inst.Labels)) {
+		tags = append(tags, name+":"+statsdTag(inst.Labels[name]))
+	}
+	if len(tags) == 0 {
+		return ""
+	}
+	return "|#" + strings.Join(tags, ",")
+}
+
+// Replace characters that would end a tag value
+func statsdTag(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ',', '|', '#', '\n', ' ':
+			return '_'
+		}
+		return r
+	}, s)
+}
+
+// Escape a label value for the text exposition format
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// Replace characters Prometheus does not allow in metric names
+func metricName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r == ':' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') {
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+// Replace characters with a meaning in the StatsD line format
+func statsdName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ':', '|', '@', '#', '\n', ' ':
+			return '_'
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,180 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package metrics
+
+import (
+	"context"
+	"expvar"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+var testMap = expvar.NewMap("metrics_test")
+
+func TestCollect(t *testing.T) {
+	testMap.Add("rule:gpu", 3)
+	testMap.AddFloat("ratio", 0.5)
+	testMap.Set("label", new(expvar.String))
+
+	var got []Sample
+	for _, s := range Collect() {
+		if s.Name == "metrics_test" {
+			got = append(got, s)
+		}
+		if slices.Contains(runtimeVars, s.Name) {
+			t.Errorf("expected runtime var %s skipped", s.Name)
+		}
+	}
+	want := []Sample{{"metrics_test", "ratio", 0.5}, {"metrics_test", "rule:gpu", 3}}
+	if !slices.Equal(got, want) {
+		t.Errorf("Collect() = %+v, want %+v", got, want)
+	}
+}
+
+func TestPush(t *testing.T) {
+	samples := []Sample{
+		{Name: "notifier", Key: "telegram:100.attempts", Value: 4},
+		{Name: "notifier", Key: `say "hi"`, Value: 1},
+		{Name: "uptime", Value: 1.5},
+	}
+
+	var path, body string
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("expected PUT, got %s", r.Method)
+		}
+		data, _ := io.ReadAll(r.Body)
+		path, body = r.URL.Path, string(data)
+	}))
+	defer gateway.Close()
+
+	udp, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = udp.Close() }()
+
+	p := New(config.MetricsConfig{
+		Interval:    time.Minute,
+		Pushgateway: gateway.URL + "/",
+		Job:         "scout",
+		StatsD:      udp.LocalAddr().String(),
+		Prefix:      "telegram_scout",
+	}, config.InstanceConfig{}, zap.NewNop())
+	p.instance = "host-1"
+	p.collect = func() []Sample { return samples }
+
+	if err := p.Push(context.Background()); err != nil {
+		t.Fatalf("Push() error = %v", err)
+	}
+
+	if path != "/metrics/job/scout/instance/host-1" {
+		t.Errorf("unexpected push path %q", path)
+	}
+	for _, want := range []string{
+		"# TYPE telegram_scout_notifier untyped\n",
+		`telegram_scout_notifier{key="telegram:100.attempts"} 4` + "\n",
+		`telegram_scout_notifier{key="say \"hi\""} 1` + "\n",
+		"telegram_scout_uptime 1.5\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q in pushed body:\n%s", want, body)
+		}
+	}
+	if strings.Count(body, "# TYPE telegram_scout_notifier") != 1 {
+		t.Errorf("expected one TYPE line per metric:\n%s", body)
+	}
+
+	buf := make([]byte, statsdPacket)
+	_ = udp.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := udp.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("failed to read StatsD packet: %v", err)
+	}
+	lines := strings.Split(string(buf[:n]), "\n")
+	want := []string{"telegram_scout.notifier.telegram_100.attempts:4|g", `telegram_scout.notifier.say_"hi":1|g`, "telegram_scout.uptime:1.5|g"}
+	if !slices.Equal(lines, want) {
+		t.Errorf("StatsD lines = %q, want %q", lines, want)
+	}
+}
+
+func TestPush_Instance(t *testing.T) {
+	var path string
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.EscapedPath()
+	}))
+	defer gateway.Close()
+
+	udp, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = udp.Close() }()
+
+	p := New(config.MetricsConfig{
+		Pushgateway: gateway.URL,
+		Job:         "scout",
+		StatsD:      udp.LocalAddr().String(),
+		Prefix:      "scout",
+	}, config.InstanceConfig{
+		Name:   "eu-1",
+		Labels: map[string]string{"team": "intel", "region": "eu west"},
+	}, zap.NewNop())
+	p.collect = func() []Sample { return []Sample{{Name: "uptime", Value: 2}} }
+
+	if err := p.Push(context.Background()); err != nil {
+		t.Fatalf("Push() error = %v", err)
+	}
+	if path != "/metrics/job/scout/instance/eu-1/region/eu%20west/team/intel" {
+		t.Errorf("unexpected push path %q", path)
+	}
+
+	buf := make([]byte, statsdPacket)
+	_ = udp.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := udp.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("failed to read StatsD packet: %v", err)
+	}
+	if got, want := string(buf[:n]), "scout.uptime:2|g|#instance:eu-1,region:eu_west,team:intel"; got != want {
+		t.Errorf("StatsD line = %q, want %q", got, want)
+	}
+}
+
+func TestPush_GatewayError(t *testing.T) {
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusBadRequest)
+	}))
+	defer gateway.Close()
+
+	p := New(config.MetricsConfig{Pushgateway: gateway.URL, Job: "scout"}, config.InstanceConfig{}, zap.NewNop())
+	p.collect = func() []Sample { return nil }
+	if err := p.Push(context.Background()); err == nil || !strings.Contains(err.Error(), "status 400") {
+		t.Errorf("expected the gateway status reported, got %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package model
+
+import "time"
+
+// Match is a rule matching a message, as handed to notifiers, webhooks and renderers
+type Match struct {
+	ID        string            `json:"id"`   // Alert ID, also sent as the Idempotency-Key header
+	Rule      string            `json:"rule"` // As reported in alerts, e.g. "urgent" or "file:apk"
+	Kind      string            `json:"kind"`
+	Tags      []string          `json:"tags,omitempty"`
+	Also      []string          `json:"also,omitempty"` // Other rules matching, under matching.mode all
+	Matched   string            `json:"matched"`
+	Captures  map[string]string `json:"captures,omitempty"` // Named groups of regex rules
+	ChatID    int64             `json:"chat_id"`
+	ChatTitle string            `json:"chat_title"`
+	Username  string            `json:"username,omitempty"`
+	MsgID     int               `json:"msg_id"`
+	SenderID  int64             `json:"sender_id,omitempty"`
+	Text      string            `json:"text"`
+	Link      string            `json:"link,omitempty"`
+	AppLink   string            `json:"app_link,omitempty"`
+	Date      time.Time         `json:"date"`
+	Stale     bool              `json:"stale,omitempty"`    // Older than the chat's filters.max_age
+	Instance  string            `json:"instance,omitempty"` // Instance name and labels from the config
+	Labels    map[string]string `json:"labels,omitempty"`
+}
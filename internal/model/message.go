@@ -26,7 +26,48 @@ type Message struct {
 	ChatID    int64
 	ChatTitle string
 	Username  string // Channel/User username if available
+	TopicID   int    // Forum topic ID, zero outside topics
+	SenderID  int64  // Bot API ID of the author, zero for anonymous channel posts
+	FromBot   bool   // Author is a bot account
+	Outgoing  bool   // Sent by the monitoring account itself
 	Text      string
+	Media     string   // Kind of attached media ("photo", "sticker", ...), empty for text only
+	File      *File    // Attached document details, nil for other media
+	Forward   *Forward // Origin of a forwarded message, nil otherwise
+	Entities  []Entity // Mentions, hashtags and cashtags as parsed by Telegram
 	Date      time.Time
-	Link      string
+	Edited    time.Time // Last edit, zero when never edited or not known
+	Received  time.Time // When the live client got it, zero for history and sweeps
+	Link      string    // Empty for chats without message links
+	AppLink   string    // tg:// link opening the app, when Link is a web link
+	Spare     bool      // Received by the hot spare account
+	TraceID   string    // Correlation ID in the logs of everything done about the message
+}
+
+// File describes a document attached to a message
+type File struct {
+	Name     string
+	MimeType string
+	Size     int64
+}
+
+// Kinds of message entities kept for matching
+const (
+	EntityMention = "mention"
+	EntityHashtag = "hashtag"
+	EntityCashtag = "cashtag"
+)
+
+// Entity is a mention, hashtag or cashtag located in Text
+type Entity struct {
+	Kind       string
+	Value      string // Lowercased, without the leading '@', '#' or '$'
+	Start, End int    // Byte offsets in Text
+}
+
+// Forward describes where a forwarded message was originally posted
+type Forward struct {
+	ChatID   int64  // Bot API ID of the origin, zero when its author hides their account
+	Title    string // Chat title, or the author's name for hidden accounts
+	Username string
 }
@@ -0,0 +1,106 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package notifier
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/logger"
+)
+
+// Post HTML messages as the monitoring account
+type AccountSender interface {
+	SendAlert(ctx context.Context, text string) error
+}
+
+// Send alerts through the account's MTProto session instead of a bot
+type AccountNotifier struct {
+	sender  AccountSender
+	log     *zap.Logger
+	retry   *RetryPolicy
+	profile string
+}
+
+// Create new AccountNotifier
+func NewAccount(sender AccountSender, cfg config.NotifierConfig, log *zap.Logger) *AccountNotifier {
+	return &AccountNotifier{
+		sender:  sender,
+		log:     log,
+		retry:   NewRetryPolicy("account", cfg.Retry, log),
+		profile: cfg.Account.Profile,
+	}
+}
+
+// Post message as the account, in its formatting profile
+func (a *AccountNotifier) Send(ctx context.Context, message string) error {
+	message = formatAlert(ctx, a.profile, message)
+	err := a.retry.Do(ctx, func(ctx context.Context) error {
+		return a.sender.SendAlert(ctx, message)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send notification as account: %w", err)
+	}
+	a.log.Info("Notification sent as account", logger.Trace(ctx))
+	return nil
+}
+
+// Report false while the circuit is open
+func (a *AccountNotifier) Healthy() bool {
+	return !a.retry.Open()
+}
+
+// Send through a secondary notifier when the primary fails
+type Fallback struct {
+	primary   Notifier
+	secondary Notifier
+	log       *zap.Logger
+}
+
+// Create new Fallback
+func NewFallback(primary, secondary Notifier, log *zap.Logger) *Fallback {
+	return &Fallback{primary: primary, secondary: secondary, log: log}
+}
+
+// Send message through the primary, then the secondary if that failed
+func (f *Fallback) Send(ctx context.Context, message string) error {
+	err := f.primary.Send(ctx, message)
+	if err == nil || ctx.Err() != nil {
+		return err
+	}
+	f.log.Warn("Primary notifier failed, using fallback", logger.Trace(ctx), zap.Error(err))
+	if ferr := f.secondary.Send(ctx, message); ferr != nil {
+		return fmt.Errorf("%w; fallback: %w", err, ferr)
+	}
+	return nil
+}
+
+// Report true while either notifier can deliver
+func (f *Fallback) Healthy() bool {
+	return healthy(f.primary) || healthy(f.secondary)
+}
+
+// Report whether n is healthy, assuming so when it cannot tell
+func healthy(n Notifier) bool {
+	h, ok := n.(HealthReporter)
+	return !ok || h.Healthy()
+}
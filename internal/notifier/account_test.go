@@ -0,0 +1,90 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package notifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+type failingNotifier struct{ healthy bool }
+
+func (f failingNotifier) Send(ctx context.Context, message string) error {
+	return errors.New("bot api unreachable")
+}
+
+func (f failingNotifier) Healthy() bool { return f.healthy }
+
+type accountFunc func(ctx context.Context, text string) error
+
+func (f accountFunc) SendAlert(ctx context.Context, text string) error { return f(ctx, text) }
+
+func TestAccountNotifier(t *testing.T) {
+	cfg := config.NotifierConfig{Retry: config.RetryConfig{Attempts: 2, BaseDelay: time.Millisecond}}
+
+	var sent []string
+	account := NewAccount(accountFunc(func(ctx context.Context, text string) error {
+		sent = append(sent, text)
+		return nil
+	}), cfg, zap.NewNop())
+	if err := account.Send(context.Background(), "<b>Hit</b>"); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if len(sent) != 1 || sent[0] != "<b>Hit</b>" {
+		t.Errorf("expected the HTML to be passed to the account, got %v", sent)
+	}
+
+	calls := 0
+	broken := NewAccount(accountFunc(func(ctx context.Context, text string) error {
+		calls++
+		return errors.New("not connected")
+	}), cfg, zap.NewNop())
+	if err := broken.Send(context.Background(), "x"); err == nil || calls != 2 {
+		t.Errorf("expected retries under the policy, got %v after %d calls", err, calls)
+	}
+}
+
+func TestFallback(t *testing.T) {
+	secondary := &recordingNotifier{}
+	f := NewFallback(failingNotifier{}, secondary, zap.NewNop())
+
+	if err := f.Send(context.Background(), "alert"); err != nil {
+		t.Fatalf("expected the fallback to deliver, got %v", err)
+	}
+	if got := secondary.Messages(); len(got) != 1 || got[0] != "alert" {
+		t.Errorf("expected the alert through the fallback, got %v", got)
+	}
+	if !f.Healthy() {
+		t.Error("expected a fallback without health reporting to count as healthy")
+	}
+
+	both := NewFallback(failingNotifier{}, failingNotifier{}, zap.NewNop())
+	if err := both.Send(context.Background(), "alert"); err == nil {
+		t.Error("expected an error when both notifiers fail")
+	}
+	if both.Healthy() {
+		t.Error("expected unhealthy when neither notifier is")
+	}
+}
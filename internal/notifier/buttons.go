@@ -0,0 +1,260 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Callback actions carried by alert buttons
+const (
+	ActionAck     = "ack"
+	ActionResolve = "resolve"
+	ActionSnooze  = "snooze"
+)
+
+type alertIDKey struct{}
+
+// Attach the ID of the alert being sent, letting notifiers add buttons for it
+func WithAlertID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, alertIDKey{}, id)
+}
+
+// Return the alert ID attached to ctx, if any
+func AlertID(ctx context.Context) string {
+	id, _ := ctx.Value(alertIDKey{}).(string)
+	return id
+}
+
+// Handle a button press on an alert, returning the text shown to the user
+// and the actions still available
+type ButtonHandler func(ctx context.Context, action, id, user string) (reply string, next []string, err error)
+
+type inlineButton struct {
+	Text         string `json:"text"`
+	CallbackData string `json:"callback_data"`
+}
+
+type inlineKeyboard struct {
+	InlineKeyboard [][]inlineButton `json:"inline_keyboard"`
+}
+
+var buttonLabels = map[string]string{
+	ActionAck:     "✅ Acknowledge",
+	ActionResolve: "☑️ Resolve",
+	ActionSnooze:  "🔕 Snooze",
+}
+
+// Build the keyboard offering actions on alert id
+func alertKeyboard(id string, actions []string) inlineKeyboard {
+	row := []inlineButton{}
+	for _, action := range actions {
+		row = append(row, inlineButton{Text: buttonLabels[action], CallbackData: action + ":" + id})
+	}
+	return inlineKeyboard{InlineKeyboard: [][]inlineButton{row}}
+}
+
+type botUser struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+}
+
+// Return the username, or the numeric ID for users without one
+func (u botUser) name() string {
+	if u.Username != "" {
+		return u.Username
+	}
+	return strconv.FormatInt(u.ID, 10)
+}
+
+type callbackQuery struct {
+	ID      string  `json:"id"`
+	From    botUser `json:"from"`
+	Message *struct {
+		MessageID int `json:"message_id"`
+		Chat      struct {
+			ID int64 `json:"id"`
+		} `json:"chat"`
+	} `json:"message"`
+	Data string `json:"data"`
+}
+
+type update struct {
+	UpdateID      int            `json:"update_id"`
+	CallbackQuery *callbackQuery `json:"callback_query"`
+	Message       *botMessage    `json:"message"`
+	InlineQuery   *inlineQuery   `json:"inline_query"`
+}
+
+// What the bot answers, nil handlers leave those updates unrequested
+type BotHandlers struct {
+	Buttons  ButtonHandler
+	Commands map[string]CommandHandler // Keyed by command name without the slash
+	Inline   InlineHandler
+
+	// Decide whether a user may run a command or press a button, named by its
+	// action. Nil allows everyone in the alert chats.
+	Allow func(userID int64, name string) error
+}
+
+func (h BotHandlers) allow(userID int64, name string) error {
+	if h.Allow == nil {
+		return nil
+	}
+	return h.Allow(userID, name)
+}
+
+// Long-poll the Bot API for button presses on alerts and commands until ctx is done
+func (t *TelegramNotifier) Poll(ctx context.Context, h BotHandlers) {
+	var allowed []string
+	if h.Buttons != nil {
+		allowed = append(allowed, "callback_query")
+	}
+	if len(h.Commands) > 0 {
+		allowed = append(allowed, "message")
+	}
+	if h.Inline != nil {
+		allowed = append(allowed, "inline_query")
+	}
+
+	offset := 0
+	for ctx.Err() == nil {
+		updates, err := t.getUpdates(ctx, offset, allowed)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			t.log.Warn("Failed to poll bot updates", zap.Error(err))
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(5 * time.Second):
+			}
+			continue
+		}
+		for _, u := range updates {
+			offset = u.UpdateID + 1
+			switch {
+			case u.CallbackQuery != nil && h.Buttons != nil:
+				t.handleButton(ctx, u.CallbackQuery, h)
+			case u.Message != nil && len(h.Commands) > 0:
+				t.handleCommand(ctx, u.Message, h)
+			case u.InlineQuery != nil && h.Inline != nil:
+				t.handleInline(ctx, u.InlineQuery, h)
+			}
+		}
+	}
+}
+
+func (t *TelegramNotifier) handleButton(ctx context.Context, q *callbackQuery, h BotHandlers) {
+	action, id, ok := strings.Cut(q.Data, ":")
+	if !ok {
+		_ = t.callAPI(ctx, "answerCallbackQuery", map[string]any{"callback_query_id": q.ID}, nil)
+		return
+	}
+	var reply string
+	var next []string
+	err := h.allow(q.From.ID, action)
+	if err == nil {
+		reply, next, err = h.Buttons(ctx, action, id, q.From.name())
+	} else {
+		t.log.Info("Refused button", zap.String("action", action), zap.String("user", q.From.name()), zap.Error(err))
+	}
+	if err != nil {
+		reply = err.Error()
+	}
+	_ = t.callAPI(ctx, "answerCallbackQuery", map[string]any{"callback_query_id": q.ID, "text": reply}, nil)
+	if err != nil || q.Message == nil {
+		return
+	}
+
+	err = t.callAPI(ctx, "editMessageReplyMarkup", map[string]any{
+		"chat_id":      q.Message.Chat.ID,
+		"message_id":   q.Message.MessageID,
+		"reply_markup": alertKeyboard(id, next),
+	}, nil)
+	if err != nil {
+		t.log.Warn("Failed to update alert buttons", zap.String("alert", id), zap.Error(err))
+	}
+}
+
+// Bot API long polling timeout, in seconds
+const pollTimeout = 50
+
+func (t *TelegramNotifier) getUpdates(ctx context.Context, offset int, allowed []string) ([]update, error) {
+	var updates []update
+	err := t.callAPI(ctx, "getUpdates", map[string]any{
+		"offset":          offset,
+		"timeout":         pollTimeout,
+		"allowed_updates": allowed,
+	}, &updates)
+	return updates, err
+}
+
+// Call a Bot API method, decoding its result into out when not nil
+func (t *TelegramNotifier) callAPI(ctx context.Context, method string, params map[string]any, out any) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	url := fmt.Sprintf("%s/bot%s/%s", t.baseURL, t.token, method)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	// Long polls outlive the client timeout used for sends
+	client := t.client
+	if method == "getUpdates" {
+		c := *t.client
+		c.Timeout = (pollTimeout + 10) * time.Second
+		client = &c
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("network error: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var result struct {
+		OK          bool            `json:"ok"`
+		Description string          `json:"description"`
+		Result      json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("%s returned status %d: %w", method, resp.StatusCode, err)
+	}
+	if !result.OK {
+		return fmt.Errorf("%s failed: %s", method, result.Description)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(result.Result, out)
+}
@@ -0,0 +1,329 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+func TestTelegramNotifier_Buttons(t *testing.T) {
+	cfg := &config.Config{
+		BotToken: "test_token",
+		ChatID:   123456,
+		Acks:     config.AcksConfig{Enabled: true, Buttons: true, Snooze: time.Hour},
+	}
+
+	t.Run("Keyboard On Tracked Alerts", func(t *testing.T) {
+		var markups []any
+		var mux sync.Mutex
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var payload map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&payload)
+			mux.Lock()
+			markups = append(markups, payload["reply_markup"])
+			mux.Unlock()
+		}))
+		defer server.Close()
+
+		n := newTestNotifier(t, cfg)
+		n.baseURL = server.URL
+		if err := n.Send(WithAlertID(context.Background(), "100:7"), "alert"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := n.Send(context.Background(), "startup"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(markups) != 2 {
+			t.Fatalf("expected 2 sends, got %d", len(markups))
+		}
+		raw, _ := json.Marshal(markups[0])
+		if !strings.Contains(string(raw), `"callback_data":"ack:100:7"`) || !strings.Contains(string(raw), `"callback_data":"resolve:100:7"`) || !strings.Contains(string(raw), `"callback_data":"snooze:100:7"`) {
+			t.Errorf("expected ack, resolve and snooze buttons, got %s", raw)
+		}
+		if markups[1] != nil {
+			t.Errorf("expected no buttons on untracked messages, got %v", markups[1])
+		}
+	})
+
+	t.Run("Button Press", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var polls int
+		calls := make(map[string]map[string]any)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			method := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+			var payload map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&payload)
+			calls[method] = payload
+
+			switch method {
+			case "getUpdates":
+				polls++
+				if polls > 1 {
+					cancel()
+					_, _ = w.Write([]byte(`{"ok":true,"result":[]}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"ok":true,"result":[{"update_id":5,"callback_query":{"id":"q1","from":{"id":1,"username":"alice"},"message":{"message_id":9,"chat":{"id":123456}},"data":"ack:100:7"}}]}`))
+			default:
+				_, _ = w.Write([]byte(`{"ok":true,"result":true}`))
+			}
+		}))
+		defer server.Close()
+
+		n := newTestNotifier(t, cfg)
+		n.baseURL = server.URL
+
+		var got []string
+		n.Poll(ctx, BotHandlers{Buttons: func(_ context.Context, action, id, user string) (string, []string, error) {
+			got = []string{action, id, user}
+			return "Acknowledged by " + user, []string{ActionResolve}, nil
+		}})
+
+		if strings.Join(got, " ") != "ack 100:7 alice" {
+			t.Errorf("unexpected handler call %v", got)
+		}
+		if calls["getUpdates"]["offset"] != float64(6) {
+			t.Errorf("expected offset past the handled update, got %v", calls["getUpdates"]["offset"])
+		}
+		if calls["answerCallbackQuery"]["text"] != "Acknowledged by alice" {
+			t.Errorf("unexpected answer %v", calls["answerCallbackQuery"])
+		}
+		raw, _ := json.Marshal(calls["editMessageReplyMarkup"]["reply_markup"])
+		if !strings.Contains(string(raw), "resolve:100:7") || strings.Contains(string(raw), "ack:100:7") {
+			t.Errorf("expected only the resolve button left, got %s", raw)
+		}
+	})
+}
+
+func TestTelegramNotifier_Commands(t *testing.T) {
+	cfg := &config.Config{BotToken: "test_token", ChatID: 123456}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var polls int
+	var replies []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		if strings.HasSuffix(r.URL.Path, "/sendMessage") {
+			replies = append(replies, payload)
+			_, _ = w.Write([]byte(`{"ok":true,"result":{}}`))
+			return
+		}
+
+		polls++
+		if polls > 1 {
+			cancel()
+			_, _ = w.Write([]byte(`{"ok":true,"result":[]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"result":[
+			{"update_id":1,"message":{"message_id":3,"from":{"id":1,"username":"alice"},"chat":{"id":123456},"text":"/snooze@scout_bot giveaway 1h"}},
+			{"update_id":2,"message":{"message_id":4,"from":{"id":2},"chat":{"id":999},"text":"/snooze giveaway 1h"}},
+			{"update_id":3,"message":{"message_id":5,"from":{"id":1},"chat":{"id":123456},"text":"/bogus"}},
+			{"update_id":4,"message":{"message_id":6,"from":{"id":1},"chat":{"id":123456},"text":"just chatting"}}
+		]}`))
+	}))
+	defer server.Close()
+
+	n := newTestNotifier(t, cfg)
+	n.baseURL = server.URL
+
+	var got []Command
+	n.Poll(ctx, BotHandlers{Commands: map[string]CommandHandler{
+		"snooze": func(_ context.Context, cmd Command) (string, error) {
+			got = append(got, cmd)
+			return "Snoozed", nil
+		},
+	}})
+
+	if len(got) != 1 {
+		t.Fatalf("expected only the command from the alert chat, got %+v", got)
+	}
+	if c := got[0]; c.Name != "snooze" || strings.Join(c.Args, " ") != "giveaway 1h" || c.User != "alice" || c.ChatID != 123456 {
+		t.Errorf("unexpected command %+v", c)
+	}
+	if len(replies) != 2 {
+		t.Fatalf("expected replies to the command and the unknown one, got %+v", replies)
+	}
+	if replies[0]["text"] != "Snoozed" {
+		t.Errorf("unexpected reply %v", replies[0]["text"])
+	}
+	if text, _ := replies[1]["text"].(string); !strings.Contains(text, "/snooze") {
+		t.Errorf("expected the available commands listed, got %q", text)
+	}
+}
+
+func TestTelegramNotifier_Allow(t *testing.T) {
+	cfg := &config.Config{BotToken: "test_token", ChatID: 123456}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var polls int
+	var replies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/sendMessage"):
+			text, _ := payload["text"].(string)
+			replies = append(replies, text)
+			_, _ = w.Write([]byte(`{"ok":true,"result":{}}`))
+			return
+		case strings.HasSuffix(r.URL.Path, "/answerCallbackQuery"):
+			text, _ := payload["text"].(string)
+			replies = append(replies, text)
+			_, _ = w.Write([]byte(`{"ok":true,"result":true}`))
+			return
+		}
+
+		polls++
+		if polls > 1 {
+			cancel()
+			_, _ = w.Write([]byte(`{"ok":true,"result":[]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"result":[
+			{"update_id":1,"message":{"message_id":3,"from":{"id":1,"username":"alice"},"chat":{"id":123456},"text":"/snooze giveaway 1h"}},
+			{"update_id":2,"message":{"message_id":4,"from":{"id":2,"username":"mallory"},"chat":{"id":123456},"text":"/snooze giveaway 1h"}},
+			{"update_id":3,"callback_query":{"id":"q1","from":{"id":2,"username":"mallory"},"data":"ack:100:7"}}
+		]}`))
+	}))
+	defer server.Close()
+
+	n := newTestNotifier(t, cfg)
+	n.baseURL = server.URL
+
+	var ran []string
+	n.Poll(ctx, BotHandlers{
+		Commands: map[string]CommandHandler{
+			"snooze": func(_ context.Context, cmd Command) (string, error) {
+				ran = append(ran, cmd.User)
+				return "Snoozed", nil
+			},
+		},
+		Buttons: func(_ context.Context, action, id, user string) (string, []string, error) {
+			ran = append(ran, user)
+			return "Acknowledged", nil, nil
+		},
+		Allow: func(userID int64, name string) error {
+			if userID != 1 {
+				return errors.New("permission denied")
+			}
+			return nil
+		},
+	})
+
+	if len(ran) != 1 || ran[0] != "alice" {
+		t.Errorf("expected only alice's command to run, got %v", ran)
+	}
+	if len(replies) != 3 || replies[0] != "Snoozed" || !strings.Contains(replies[1], "permission denied") || replies[2] != "permission denied" {
+		t.Errorf("unexpected replies %q", replies)
+	}
+}
+
+func TestTelegramNotifier_Inline(t *testing.T) {
+	cfg := &config.Config{BotToken: "test_token", ChatID: 123456, Commands: config.CommandsConfig{RateLimit: 1}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var polls int
+	var answers []map[string]any
+	var replies int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/answerInlineQuery"):
+			answers = append(answers, payload)
+			_, _ = w.Write([]byte(`{"ok":true,"result":true}`))
+			return
+		case strings.HasSuffix(r.URL.Path, "/sendMessage"):
+			replies++
+			_, _ = w.Write([]byte(`{"ok":true,"result":{}}`))
+			return
+		}
+
+		polls++
+		if polls > 1 {
+			cancel()
+			_, _ = w.Write([]byte(`{"ok":true,"result":[]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"result":[
+			{"update_id":1,"inline_query":{"id":"q1","from":{"id":1},"query":"gpu"}},
+			{"update_id":2,"inline_query":{"id":"q2","from":{"id":1},"query":"gpu"}},
+			{"update_id":3,"inline_query":{"id":"q3","from":{"id":2},"query":"gpu"}},
+			{"update_id":4,"message":{"message_id":3,"from":{"id":3},"chat":{"id":123456},"text":"/snoozes"}},
+			{"update_id":5,"message":{"message_id":4,"from":{"id":3},"chat":{"id":123456},"text":"/snoozes"}}
+		]}`))
+	}))
+	defer server.Close()
+
+	n := newTestNotifier(t, cfg)
+	n.baseURL = server.URL
+
+	var queries []string
+	n.Poll(ctx, BotHandlers{
+		Commands: map[string]CommandHandler{
+			"snoozes": func(context.Context, Command) (string, error) { return "Nothing is snoozed.", nil },
+		},
+		Inline: func(_ context.Context, query string) ([]InlineResult, error) {
+			queries = append(queries, query)
+			return []InlineResult{{Title: "Deals: gpu", Description: "RTX 5070", Text: "<b>gpu</b>"}}, nil
+		},
+		Allow: func(userID int64, name string) error {
+			if name == "inline" && userID == 2 {
+				return errors.New("not authorized")
+			}
+			return nil
+		},
+	})
+
+	// The second query and command of the same user exceed the limit of one a minute
+	if len(queries) != 1 || queries[0] != "gpu" {
+		t.Errorf("expected one answered query, got %v", queries)
+	}
+	if replies != 1 {
+		t.Errorf("expected one command reply, got %d", replies)
+	}
+	if len(answers) != 1 {
+		t.Fatalf("expected one inline answer, got %+v", answers)
+	}
+	results, _ := answers[0]["results"].([]any)
+	if answers[0]["inline_query_id"] != "q1" || len(results) != 1 {
+		t.Errorf("unexpected answer %+v", answers[0])
+	}
+	if r, _ := results[0].(map[string]any); r["title"] != "Deals: gpu" || r["type"] != "article" {
+		t.Errorf("unexpected result %+v", r)
+	}
+}
@@ -0,0 +1,187 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/render"
+)
+
+// Bot API limit for a single text message
+const maxMessageLength = render.MaxLength
+
+const coalesceSeparator = "\n\n➖➖➖\n\n"
+
+// Collect alerts for a short window and merge them when too many pile up
+type Coalescer struct {
+	next      Notifier
+	log       *zap.Logger
+	window    time.Duration
+	threshold int
+
+	mux     sync.Mutex
+	pending []*pendingSend
+	sending sync.Mutex // Held by a flush, so windows are delivered in order
+}
+
+type pendingSend struct {
+	ctx     context.Context
+	message string
+	done    chan error
+}
+
+// Create new Coalescer delivering through next
+func NewCoalescer(next Notifier, cfg config.CoalesceConfig, log *zap.Logger) *Coalescer {
+	threshold := cfg.Threshold
+	if threshold <= 0 {
+		threshold = config.DefaultCoalesceThreshold
+	}
+	return &Coalescer{
+		next:      next,
+		log:       log,
+		window:    cfg.Window,
+		threshold: threshold,
+	}
+}
+
+// Queue message for the current window and wait for its delivery, urgent
+// ones are sent right away
+func (c *Coalescer) Send(ctx context.Context, message string) error {
+	return <-c.Queue(ctx, message)
+}
+
+// Queue message for the current window, returning the channel its delivery
+// error is sent on once the window is flushed
+func (c *Coalescer) Queue(ctx context.Context, message string) <-chan error {
+	done := make(chan error, 1)
+	if urgent(ctx) {
+		done <- c.next.Send(ctx, message)
+		return done
+	}
+	if err := ctx.Err(); err != nil {
+		done <- err
+		return done
+	}
+
+	c.mux.Lock()
+	c.pending = append(c.pending, &pendingSend{ctx: ctx, message: message, done: done})
+	if len(c.pending) == 1 {
+		time.AfterFunc(c.window, c.flush)
+	}
+	c.mux.Unlock()
+	return done
+}
+
+// Forward the health of the wrapped notifier
+func (c *Coalescer) Healthy() bool {
+	if h, ok := c.next.(HealthReporter); ok {
+		return h.Healthy()
+	}
+	return true
+}
+
+// Deliver everything queued during the window
+func (c *Coalescer) flush() {
+	c.mux.Lock()
+	batch := c.pending
+	c.pending = nil
+	c.mux.Unlock()
+
+	if len(batch) == 0 {
+		return
+	}
+	c.sending.Lock()
+	defer c.sending.Unlock()
+
+	// Few alerts, deliver them as they are. Queued alerts are still sent when
+	// their sender is cancelled, as on shutdown.
+	if len(batch) <= c.threshold {
+		for _, p := range batch {
+			p.done <- c.next.Send(context.WithoutCancel(p.ctx), p.message)
+		}
+		return
+	}
+
+	c.log.Info("Coalescing pending alerts", zap.Int("count", len(batch)))
+	messages := make([]string, len(batch))
+	for i, p := range batch {
+		messages[i] = p.message
+	}
+
+	// Buttons and the match of the first alert would not apply to the merged message
+	ctx := withoutMatch(WithAlertID(context.WithoutCancel(batch[0].ctx), ""))
+	var errs []error
+	for _, chunk := range combine(messages) {
+		if err := c.next.Send(ctx, chunk); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	err := errors.Join(errs...)
+	for _, p := range batch {
+		p.done <- err
+	}
+}
+
+// Join messages into as few chunks as fit the Bot API length limit
+func combine(messages []string) []string {
+	var chunks []string
+	var b strings.Builder
+	count := 0
+
+	emit := func() {
+		if count == 0 {
+			return
+		}
+		chunks = append(chunks, fmt.Sprintf("📦 <b>%d alerts</b>%s%s", count, coalesceSeparator, b.String()))
+		b.Reset()
+		count = 0
+	}
+
+	// Leave room for the header
+	limit := maxMessageLength - 64
+	sepLen := utf8.RuneCountInString(coalesceSeparator)
+	length := 0
+	for _, m := range messages {
+		size := utf8.RuneCountInString(m)
+		if count > 0 && length+sepLen+size > limit {
+			emit()
+			length = 0
+		}
+		if count > 0 {
+			b.WriteString(coalesceSeparator)
+			length += sepLen
+		}
+		b.WriteString(m)
+		length += size
+		count++
+	}
+	emit()
+
+	return chunks
+}
@@ -0,0 +1,155 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package notifier
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+type recordingNotifier struct {
+	mu       sync.Mutex
+	messages []string
+	err      error // Returned by every send
+}
+
+func (r *recordingNotifier) Send(ctx context.Context, message string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	r.messages = append(r.messages, message)
+	return r.err
+}
+
+func (r *recordingNotifier) Messages() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.messages...)
+}
+
+func TestCoalescer(t *testing.T) {
+	// Queue n alerts in order and wait for their delivery
+	sendAll := func(ctx context.Context, c *Coalescer, n int) []error {
+		results := make([]<-chan error, n)
+		for i := range n {
+			results[i] = c.Queue(ctx, "alert "+string(rune('A'+i)))
+		}
+		errs := make([]error, n)
+		for i, result := range results {
+			select {
+			case errs[i] = <-result:
+			case <-time.After(time.Second):
+				t.Fatal("timeout waiting for delivery")
+			}
+		}
+		return errs
+	}
+
+	t.Run("Below Threshold", func(t *testing.T) {
+		rec := &recordingNotifier{}
+		c := NewCoalescer(rec, config.CoalesceConfig{Window: 20 * time.Millisecond, Threshold: 3}, zap.NewNop())
+		for _, err := range sendAll(context.Background(), c, 3) {
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}
+		if got := rec.Messages(); !slices.Equal(got, []string{"alert A", "alert B", "alert C"}) {
+			t.Errorf("expected the individual messages in order, got %q", got)
+		}
+	})
+
+	t.Run("Above Threshold", func(t *testing.T) {
+		rec := &recordingNotifier{}
+		c := NewCoalescer(rec, config.CoalesceConfig{Window: 20 * time.Millisecond, Threshold: 3}, zap.NewNop())
+		for _, err := range sendAll(context.Background(), c, 5) {
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}
+		msgs := rec.Messages()
+		if len(msgs) != 1 {
+			t.Fatalf("expected 1 combined message, got %d", len(msgs))
+		}
+		if !strings.Contains(msgs[0], "5 alerts") || !strings.Contains(msgs[0], "alert E") {
+			t.Errorf("unexpected combined message: %s", msgs[0])
+		}
+	})
+
+	t.Run("Reports Delivery Errors", func(t *testing.T) {
+		for _, n := range []int{2, 5} {
+			rec := &recordingNotifier{err: errors.New("bot down")}
+			c := NewCoalescer(rec, config.CoalesceConfig{Window: 20 * time.Millisecond, Threshold: 3}, zap.NewNop())
+			for _, err := range sendAll(context.Background(), c, n) {
+				if err == nil || !strings.Contains(err.Error(), "bot down") {
+					t.Errorf("expected the delivery error for %d alerts, got %v", n, err)
+				}
+			}
+		}
+	})
+
+	t.Run("Delivers After Cancel", func(t *testing.T) {
+		rec := &recordingNotifier{}
+		c := NewCoalescer(rec, config.CoalesceConfig{Window: 20 * time.Millisecond, Threshold: 3}, zap.NewNop())
+		ctx, cancel := context.WithCancel(context.Background())
+		result := c.Queue(ctx, "alert A")
+		cancel()
+		if err := <-result; err != nil || len(rec.Messages()) != 1 {
+			t.Errorf("expected an alert queued before shutdown to be delivered, got %v", err)
+		}
+	})
+
+	t.Run("Queue Returns Before The Window", func(t *testing.T) {
+		rec := &recordingNotifier{}
+		c := NewCoalescer(rec, config.CoalesceConfig{Window: time.Hour, Threshold: 3}, zap.NewNop())
+		start := time.Now()
+		for range 20 {
+			c.Queue(context.Background(), "alert")
+		}
+		if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+			t.Errorf("expected Queue not to wait for the window, took %s", elapsed)
+		}
+		if got := len(rec.Messages()); got != 0 {
+			t.Errorf("expected nothing delivered before the window ends, got %d", got)
+		}
+	})
+}
+
+func TestCombineRespectsLimit(t *testing.T) {
+	long := strings.Repeat("x", 3000)
+	chunks := combine([]string{long, long, long})
+	if len(chunks) != 3 {
+		t.Fatalf("expected 3 chunks, got %d", len(chunks))
+	}
+	for _, c := range chunks {
+		if n := len([]rune(c)); n > maxMessageLength {
+			t.Errorf("chunk exceeds limit: %d", n)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package notifier
+
+import (
+	"context"
+	"html"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// A bot command sent in one of the alert chats
+type Command struct {
+	Name   string   // Without the slash and any @botname suffix
+	Args   []string // Whitespace-separated words after the name
+	ChatID int64
+	UserID int64
+	User   string
+}
+
+// Handle a bot command, returning the HTML reply
+type CommandHandler func(ctx context.Context, cmd Command) (string, error)
+
+type botMessage struct {
+	MessageID int     `json:"message_id"`
+	From      botUser `json:"from"`
+	Chat      struct {
+		ID int64 `json:"id"`
+	} `json:"chat"`
+	Text string `json:"text"`
+}
+
+// Split "/name@bot arg..." into a Command, reporting whether text is one
+func parseCommand(text string) (Command, bool) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+		return Command{}, false
+	}
+	name, _, _ := strings.Cut(fields[0][1:], "@")
+	return Command{Name: strings.ToLower(name), Args: fields[1:]}, name != ""
+}
+
+// Run the handler for a command and reply in the chat it came from. Only the
+// configured alert chats may send commands, the rest are ignored.
+func (t *TelegramNotifier) handleCommand(ctx context.Context, m *botMessage, h BotHandlers) {
+	cmd, ok := parseCommand(m.Text)
+	if !ok {
+		return
+	}
+	if !slices.ContainsFunc(t.targets, func(dest target) bool { return dest.ID == m.Chat.ID }) {
+		t.log.Debug("Ignoring command from a chat that receives no alerts", zap.Int64("chat_id", m.Chat.ID), zap.String("command", cmd.Name))
+		return
+	}
+	cmd.ChatID = m.Chat.ID
+	cmd.UserID = m.From.ID
+	cmd.User = m.From.name()
+	if !t.limits.allow(cmd.UserID) {
+		t.log.Info("Command rate limited", zap.String("command", cmd.Name), zap.String("user", cmd.User))
+		return
+	}
+
+	var reply string
+	handle, ok := h.Commands[cmd.Name]
+	if !ok {
+		reply = "Unknown command, available: " + html.EscapeString(commandList(h.Commands))
+	} else if err := h.allow(cmd.UserID, cmd.Name); err != nil {
+		t.log.Info("Refused command", zap.String("command", cmd.Name), zap.String("user", cmd.User), zap.Error(err))
+		reply = "⛔ " + html.EscapeString(err.Error())
+	} else {
+		var err error
+		reply, err = handle(ctx, cmd)
+		if err != nil {
+			reply = "⚠️ " + html.EscapeString(err.Error())
+		}
+	}
+
+	err := t.callAPI(ctx, "sendMessage", map[string]any{
+		"chat_id":                  m.Chat.ID,
+		"text":                     reply,
+		"parse_mode":               "HTML",
+		"disable_web_page_preview": true,
+		"reply_parameters":         map[string]any{"message_id": m.MessageID, "allow_sending_without_reply": true},
+	}, nil)
+	if err != nil {
+		t.log.Warn("Failed to reply to command", zap.String("command", cmd.Name), zap.Error(err))
+	}
+}
+
+func commandList(handlers map[string]CommandHandler) string {
+	var names []string
+	for name := range handlers {
+		names = append(names, "/"+name)
+	}
+	slices.Sort(names)
+	return strings.Join(names, " ")
+}
@@ -0,0 +1,176 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package notifier
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/tinylib/msgp/msgp"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Return the Content-Type of bodies in encoding
+func contentType(encoding string) string {
+	switch encoding {
+	case config.EncodingProtobuf:
+		return "application/x-protobuf"
+	case config.EncodingMsgpack:
+		return "application/msgpack"
+	}
+	return "application/json"
+}
+
+// Encode m as the Match message of api/match.proto, leaving out empty fields as proto3 does
+func marshalProto(m model.Match) []byte {
+	var b []byte
+	b = appendProtoString(b, 1, m.Rule)
+	b = appendProtoString(b, 2, m.Kind)
+	for _, tag := range m.Tags {
+		b = protowire.AppendTag(b, 3, protowire.BytesType)
+		b = protowire.AppendString(b, tag)
+	}
+	b = appendProtoString(b, 4, m.Matched)
+	b = appendProtoMap(b, 5, m.Captures)
+	b = appendProtoInt(b, 6, m.ChatID)
+	b = appendProtoString(b, 7, m.ChatTitle)
+	b = appendProtoString(b, 8, m.Username)
+	b = appendProtoInt(b, 9, int64(m.MsgID))
+	b = appendProtoInt(b, 10, m.SenderID)
+	b = appendProtoString(b, 11, m.Text)
+	b = appendProtoString(b, 12, m.Link)
+	if !m.Date.IsZero() {
+		// google.protobuf.Timestamp
+		ts := appendProtoInt(nil, 1, m.Date.Unix())
+		ts = appendProtoInt(ts, 2, int64(m.Date.Nanosecond()))
+		b = protowire.AppendTag(b, 13, protowire.BytesType)
+		b = protowire.AppendBytes(b, ts)
+	}
+	b = appendProtoString(b, 14, m.Instance)
+	b = appendProtoMap(b, 15, m.Labels)
+	if m.Stale {
+		b = protowire.AppendTag(b, 16, protowire.VarintType)
+		b = protowire.AppendVarint(b, 1)
+	}
+	b = appendProtoString(b, 17, m.ID)
+	b = appendProtoString(b, 18, m.AppLink)
+	for _, rule := range m.Also {
+		b = protowire.AppendTag(b, 19, protowire.BytesType)
+		b = protowire.AppendString(b, rule)
+	}
+	return b
+}
+
+func appendProtoString(b []byte, num protowire.Number, s string) []byte {
+	if s == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, s)
+}
+
+func appendProtoInt(b []byte, num protowire.Number, v int64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, uint64(v))
+}
+
+// Append a map<string, string> as its entry messages, sorted by key so equal matches encode alike
+func appendProtoMap(b []byte, num protowire.Number, m map[string]string) []byte {
+	for _, k := range slices.Sorted(maps.Keys(m)) {
+		entry := protowire.AppendTag(nil, 1, protowire.BytesType)
+		entry = protowire.AppendString(entry, k)
+		entry = protowire.AppendTag(entry, 2, protowire.BytesType)
+		entry = protowire.AppendString(entry, m[k])
+		b = protowire.AppendTag(b, num, protowire.BytesType)
+		b = protowire.AppendBytes(b, entry)
+	}
+	return b
+}
+
+// Encode m as a MessagePack map with the keys and omitted fields of its JSON document
+func marshalMsgpack(m model.Match) []byte {
+	var (
+		b []byte
+		n uint32
+	)
+	str := func(key, v string, omitEmpty bool) {
+		if omitEmpty && v == "" {
+			return
+		}
+		b = msgp.AppendString(msgp.AppendString(b, key), v)
+		n++
+	}
+	list := func(key string, v []string) {
+		if len(v) == 0 {
+			return
+		}
+		b = msgp.AppendArrayHeader(msgp.AppendString(b, key), uint32(len(v)))
+		for _, s := range v {
+			b = msgp.AppendString(b, s)
+		}
+		n++
+	}
+	dict := func(key string, v map[string]string) {
+		if len(v) == 0 {
+			return
+		}
+		b = msgp.AppendMapHeader(msgp.AppendString(b, key), uint32(len(v)))
+		for _, k := range slices.Sorted(maps.Keys(v)) {
+			b = msgp.AppendString(msgp.AppendString(b, k), v[k])
+		}
+		n++
+	}
+
+	str("id", m.ID, false)
+	str("rule", m.Rule, false)
+	str("kind", m.Kind, false)
+	list("tags", m.Tags)
+	list("also", m.Also)
+	str("matched", m.Matched, false)
+	dict("captures", m.Captures)
+	b = msgp.AppendInt64(msgp.AppendString(b, "chat_id"), m.ChatID)
+	n++
+	str("chat_title", m.ChatTitle, false)
+	str("username", m.Username, true)
+	b = msgp.AppendInt64(msgp.AppendString(b, "msg_id"), int64(m.MsgID))
+	n++
+	if m.SenderID != 0 {
+		b = msgp.AppendInt64(msgp.AppendString(b, "sender_id"), m.SenderID)
+		n++
+	}
+	str("text", m.Text, false)
+	str("link", m.Link, true)
+	str("app_link", m.AppLink, true)
+	b = msgp.AppendTimeExt(msgp.AppendString(b, "date"), m.Date)
+	n++
+	if m.Stale {
+		b = msgp.AppendBool(msgp.AppendString(b, "stale"), true)
+		n++
+	}
+	str("instance", m.Instance, true)
+	dict("labels", m.Labels)
+
+	return append(msgp.AppendMapHeader(nil, n), b...)
+}
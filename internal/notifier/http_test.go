@@ -0,0 +1,96 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package notifier
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+func TestNewHTTPClient(t *testing.T) {
+	t.Run("Proxy", func(t *testing.T) {
+		proxied := make(chan string, 1)
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			proxied <- r.URL.String()
+		}))
+		defer proxy.Close()
+
+		client, err := newHTTPClient(config.HTTPConfig{Proxy: proxy.URL}, time.Second)
+		if err != nil {
+			t.Fatalf("newHTTPClient() error = %v", err)
+		}
+		resp, err := client.Get("http://api.telegram.invalid/bot/getMe")
+		if err != nil {
+			t.Fatalf("request through proxy failed: %v", err)
+		}
+		_ = resp.Body.Close()
+		if got := <-proxied; got != "http://api.telegram.invalid/bot/getMe" {
+			t.Errorf("expected the proxy to receive the full URL, got %q", got)
+		}
+	})
+
+	t.Run("Custom CA", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer server.Close()
+
+		// Without the CA the self-signed certificate is rejected
+		client, err := newHTTPClient(config.HTTPConfig{}, time.Second)
+		if err != nil {
+			t.Fatalf("newHTTPClient() error = %v", err)
+		}
+		if _, err := client.Get(server.URL); err == nil {
+			t.Fatal("expected an untrusted certificate error")
+		}
+
+		path := filepath.Join(t.TempDir(), "ca.pem")
+		cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+		if err := os.WriteFile(path, cert, 0600); err != nil {
+			t.Fatal(err)
+		}
+		client, err = newHTTPClient(config.HTTPConfig{CAFile: path, Timeout: 3 * time.Second}, time.Second)
+		if err != nil {
+			t.Fatalf("newHTTPClient() error = %v", err)
+		}
+		if client.Timeout != 3*time.Second {
+			t.Errorf("expected configured timeout, got %s", client.Timeout)
+		}
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("expected the custom CA to be trusted, got %v", err)
+		}
+		_ = resp.Body.Close()
+	})
+
+	t.Run("Invalid CA", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "ca.pem")
+		if err := os.WriteFile(path, []byte("not a certificate"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := newHTTPClient(config.HTTPConfig{CAFile: path}, time.Second); err == nil {
+			t.Error("expected an error for a CA file without certificates")
+		}
+	})
+}
@@ -0,0 +1,116 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package notifier
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// A result offered for an inline query, sent as an HTML message when picked
+type InlineResult struct {
+	Title       string
+	Description string
+	Text        string // HTML
+}
+
+// Answer an inline query with results, newest first
+type InlineHandler func(ctx context.Context, query string) ([]InlineResult, error)
+
+type inlineQuery struct {
+	ID    string  `json:"id"`
+	From  botUser `json:"from"`
+	Query string  `json:"query"`
+}
+
+// Seconds Telegram may reuse an answer for the same user and query
+const inlineCacheTime = 10
+
+// Answer an inline query from a user allowed to read, staying silent for the rest
+func (t *TelegramNotifier) handleInline(ctx context.Context, q *inlineQuery, h BotHandlers) {
+	if err := h.allow(q.From.ID, "inline"); err != nil {
+		t.log.Debug("Ignoring inline query", zap.String("user", q.From.name()), zap.Error(err))
+		return
+	}
+	if !t.limits.allow(q.From.ID) {
+		t.log.Debug("Inline query rate limited", zap.String("user", q.From.name()))
+		return
+	}
+
+	found, err := h.Inline(ctx, q.Query)
+	if err != nil {
+		t.log.Warn("Failed to answer inline query", zap.Error(err))
+		return
+	}
+
+	results := make([]map[string]any, len(found))
+	for i, r := range found {
+		results[i] = map[string]any{
+			"type":        "article",
+			"id":          strconv.Itoa(i),
+			"title":       r.Title,
+			"description": r.Description,
+			"input_message_content": map[string]any{
+				"message_text":             r.Text,
+				"parse_mode":               "HTML",
+				"disable_web_page_preview": true,
+			},
+		}
+	}
+	err = t.callAPI(ctx, "answerInlineQuery", map[string]any{
+		"inline_query_id": q.ID,
+		"results":         results,
+		"cache_time":      inlineCacheTime,
+		"is_personal":     true,
+	}, nil)
+	if err != nil {
+		t.log.Warn("Failed to answer inline query", zap.Error(err))
+	}
+}
+
+// Window of the per-user command and inline query limit
+const CommandRateWindow = time.Minute
+
+// Limit how often the bot answers each user, so one user cannot spend the
+// bot's own flood limits. A zero limit answers everything.
+type userLimits struct {
+	limit int
+	users map[int64]*WindowLimiter
+}
+
+func newUserLimits(limit int) *userLimits {
+	return &userLimits{limit: limit, users: make(map[int64]*WindowLimiter)}
+}
+
+// Count an answer to the user, reporting whether it is within the limit
+func (u *userLimits) allow(userID int64) bool {
+	if u == nil || u.limit <= 0 {
+		return true
+	}
+	l, ok := u.users[userID]
+	if !ok {
+		l = NewWindowLimiter(u.limit, CommandRateWindow)
+		u.users[userID] = l
+	}
+	wait, _ := l.Reserve(context.Background())
+	return wait == 0
+}
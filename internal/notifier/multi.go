@@ -0,0 +1,77 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package notifier
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/logger"
+)
+
+// Send every alert to several notifiers at once
+type Multi struct {
+	notifiers []Notifier
+	log       *zap.Logger
+}
+
+// Create new Multi
+func NewMulti(log *zap.Logger, notifiers ...Notifier) *Multi {
+	return &Multi{notifiers: notifiers, log: log}
+}
+
+// Send message to every notifier, failing only if none delivered it
+func (m *Multi) Send(ctx context.Context, message string) error {
+	errs := make([]error, len(m.notifiers))
+	var wg sync.WaitGroup
+	for i, n := range m.notifiers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = n.Send(ctx, message)
+		}()
+	}
+	wg.Wait()
+
+	err := errors.Join(errs...)
+	if err == nil {
+		return nil
+	}
+	for _, e := range errs {
+		if e == nil {
+			// Delivered somewhere: resending to all would duplicate it
+			m.log.Error("Failed to send notification to some notifiers", logger.Trace(ctx), zap.Error(err))
+			return nil
+		}
+	}
+	return err
+}
+
+// Report true while any notifier can deliver
+func (m *Multi) Healthy() bool {
+	for _, n := range m.notifiers {
+		if healthy(n) {
+			return true
+		}
+	}
+	return false
+}
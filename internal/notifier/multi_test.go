@@ -0,0 +1,62 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestMulti(t *testing.T) {
+	a, b := &recordingNotifier{}, &recordingNotifier{}
+	m := NewMulti(zap.NewNop(), a, failingNotifier{}, b)
+
+	// Partial delivery succeeds so callers do not resend everywhere
+	if err := m.Send(context.Background(), "alert"); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if len(a.Messages()) != 1 || len(b.Messages()) != 1 {
+		t.Errorf("expected every notifier to get the alert, got %v and %v", a.Messages(), b.Messages())
+	}
+	if !m.Healthy() {
+		t.Error("expected healthy while any notifier is")
+	}
+
+	none := NewMulti(zap.NewNop(), failingNotifier{}, failingNotifier{})
+	if err := none.Send(context.Background(), "alert"); err == nil {
+		t.Error("expected an error when no notifier delivered")
+	}
+	if none.Healthy() {
+		t.Error("expected unhealthy when no notifier is")
+	}
+}
+
+func TestWriterNotifier(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, "")
+	if err := w.Send(context.Background(), `<b>Keyword:</b> <code>urgent</code>`); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if got := buf.String(); got != "Keyword: urgent\n\n" {
+		t.Errorf("expected plain text output, got %q", got)
+	}
+}
@@ -0,0 +1,151 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package notifier
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/h3nc4/TelegramScout/internal/atomicfile"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// A webhook delivery its endpoint has not accepted yet
+type Pending struct {
+	Webhook string      `json:"webhook"`
+	Match   model.Match `json:"match"`
+	Queued  time.Time   `json:"queued"`
+}
+
+type outboxKey struct {
+	webhook string
+	id      string
+}
+
+// Hold webhook deliveries until their endpoint accepts them, saving them to a file on every change
+type Outbox struct {
+	mux     sync.Mutex
+	path    string
+	pending map[outboxKey]Pending
+	sending map[outboxKey]bool // Claimed by a send in progress
+	now     func() time.Time
+}
+
+// Load the deliveries left pending at path, a missing file holds none. An empty path keeps them in memory only.
+func LoadOutbox(path string) (*Outbox, error) {
+	o := &Outbox{path: path, pending: make(map[outboxKey]Pending), sending: make(map[outboxKey]bool), now: time.Now}
+	if path == "" {
+		return o, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return o, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var list []Pending
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("invalid outbox file %s: %w", path, err)
+	}
+	for _, p := range list {
+		o.pending[outboxKey{p.Webhook, p.Match.ID}] = p
+	}
+	return o, nil
+}
+
+// Return the pending deliveries, oldest first
+func (o *Outbox) List() []Pending {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+	return o.sorted()
+}
+
+// Save m for webhook and claim it for sending, reporting false when the alert is already pending there
+func (o *Outbox) add(webhook string, m model.Match) (bool, error) {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+
+	k := outboxKey{webhook, m.ID}
+	if _, ok := o.pending[k]; ok {
+		return false, nil
+	}
+	o.pending[k] = Pending{Webhook: webhook, Match: m, Queued: o.now()}
+	o.sending[k] = true
+	return true, o.save()
+}
+
+// Claim a pending delivery for sending, reporting false when another send holds it
+func (o *Outbox) claim(p Pending) bool {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+
+	k := outboxKey{p.Webhook, p.Match.ID}
+	if _, ok := o.pending[k]; !ok || o.sending[k] {
+		return false
+	}
+	o.sending[k] = true
+	return true
+}
+
+// Release a claimed delivery, forgetting it when done or keeping it for the next attempt
+func (o *Outbox) release(webhook, id string, done bool) error {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+
+	k := outboxKey{webhook, id}
+	delete(o.sending, k)
+	if !done {
+		return nil
+	}
+	delete(o.pending, k)
+	return o.save()
+}
+
+// Return the pending deliveries oldest first. Called with mux held.
+func (o *Outbox) sorted() []Pending {
+	list := make([]Pending, 0, len(o.pending))
+	for _, p := range o.pending {
+		list = append(list, p)
+	}
+	slices.SortFunc(list, func(a, b Pending) int {
+		return cmp.Or(a.Queued.Compare(b.Queued), cmp.Compare(a.Webhook, b.Webhook), cmp.Compare(a.Match.ID, b.Match.ID))
+	})
+	return list
+}
+
+// Write the pending deliveries to the file. Called with mux held.
+func (o *Outbox) save() error {
+	if o.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(o.sorted())
+	if err != nil {
+		return err
+	}
+	return atomicfile.Write(o.path, data)
+}
@@ -0,0 +1,163 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package notifier
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Wait until the outbox holds n deliveries
+func waitPending(t *testing.T, o *Outbox, n int) []Pending {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		list := o.List()
+		if len(list) == n {
+			return list
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d pending deliveries, got %+v", n, list)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWebhooks_Outbox(t *testing.T) {
+	var status atomic.Int32
+	status.Store(http.StatusServiceUnavailable)
+	keys := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys <- r.Header.Get("Idempotency-Key")
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer server.Close()
+
+	cfg := config.NotifierConfig{
+		Retry:    config.RetryConfig{Attempts: 1, BreakerThreshold: 100},
+		Webhooks: []config.WebhookConfig{{Name: "siem", URL: server.URL}},
+		Outbox:   config.OutboxConfig{Interval: time.Hour},
+	}
+	path := filepath.Join(t.TempDir(), "outbox.json")
+	outbox, err := LoadOutbox(path)
+	if err != nil {
+		t.Fatalf("LoadOutbox() error = %v", err)
+	}
+	w, err := NewWebhooks(cfg, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewWebhooks() error = %v", err)
+	}
+	w.UseOutbox(outbox)
+
+	m := model.Match{ID: "-1001:42", Rule: "urgent", ChatID: -1001, MsgID: 42}
+	if err := w.Deliver(context.Background(), m); err != nil {
+		t.Fatalf("Deliver() error = %v", err)
+	}
+	if key := <-keys; key != m.ID {
+		t.Errorf("expected the alert ID as idempotency key, got %q", key)
+	}
+	waitPending(t, outbox, 1)
+
+	// The alert is already pending, the outbox will send it
+	if err := w.Deliver(context.Background(), m); err != nil {
+		t.Fatalf("Deliver() error = %v", err)
+	}
+	select {
+	case key := <-keys:
+		t.Fatalf("expected no second request while pending, got one with key %q", key)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// A restart resends what was left pending, with the same key
+	status.Store(http.StatusOK)
+	reloaded, err := LoadOutbox(path)
+	if err != nil {
+		t.Fatalf("LoadOutbox() error = %v", err)
+	}
+	if list := reloaded.List(); len(list) != 1 || list[0].Webhook != "siem" || list[0].Match.MsgID != 42 {
+		t.Fatalf("expected the pending delivery to be saved, got %+v", list)
+	}
+	w, err = NewWebhooks(cfg, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewWebhooks() error = %v", err)
+	}
+	w.UseOutbox(reloaded)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go w.Run(ctx)
+	if key := <-keys; key != m.ID {
+		t.Errorf("expected the resend to keep the idempotency key, got %q", key)
+	}
+	waitPending(t, reloaded, 0)
+	if again, err := LoadOutbox(path); err != nil || len(again.List()) != 0 {
+		t.Errorf("expected the delivered match to be removed from the file, got %+v, %v", again.List(), err)
+	}
+
+	// Rejected payloads are not kept
+	status.Store(http.StatusBadRequest)
+	if err := w.Deliver(ctx, model.Match{ID: "-1001:43", Rule: "urgent"}); err != nil {
+		t.Fatalf("Deliver() error = %v", err)
+	}
+	<-keys
+	waitPending(t, reloaded, 0)
+}
+
+func TestWebhooks_OutboxDropsStale(t *testing.T) {
+	outbox, err := LoadOutbox("")
+	if err != nil {
+		t.Fatalf("LoadOutbox() error = %v", err)
+	}
+	now := time.Now()
+	outbox.now = func() time.Time { return now.Add(-2 * time.Hour) }
+	if _, err := outbox.add("siem", model.Match{ID: "-1001:1"}); err != nil {
+		t.Fatalf("add() error = %v", err)
+	}
+	outbox.now = func() time.Time { return now }
+	if _, err := outbox.add("removed", model.Match{ID: "-1001:2"}); err != nil {
+		t.Fatalf("add() error = %v", err)
+	}
+	for _, p := range outbox.List() {
+		if err := outbox.release(p.Webhook, p.Match.ID, false); err != nil {
+			t.Fatalf("release() error = %v", err)
+		}
+	}
+
+	w, err := NewWebhooks(config.NotifierConfig{
+		Webhooks: []config.WebhookConfig{{Name: "siem", URL: "http://127.0.0.1:0"}},
+		Outbox:   config.OutboxConfig{MaxAge: time.Hour},
+	}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewWebhooks() error = %v", err)
+	}
+	w.UseOutbox(outbox)
+	w.resend(context.Background())
+	if list := outbox.List(); len(list) != 0 {
+		t.Errorf("expected stale and orphaned deliveries to be dropped, got %+v", list)
+	}
+}
@@ -0,0 +1,55 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package notifier
+
+import (
+	"context"
+
+	"github.com/h3nc4/TelegramScout/internal/model"
+	"github.com/h3nc4/TelegramScout/internal/render"
+)
+
+type matchKey struct{}
+
+// Attach the match an alert is about, letting notifiers with a formatting
+// profile other than standard render it their own way
+func WithMatch(ctx context.Context, m model.Match) context.Context {
+	return context.WithValue(ctx, matchKey{}, &m)
+}
+
+// Detach the match, for messages no longer about a single one
+func withoutMatch(ctx context.Context) context.Context {
+	return context.WithValue(ctx, matchKey{}, (*model.Match)(nil))
+}
+
+// Return the match attached to ctx, nil when there is none
+func matchFrom(ctx context.Context) *model.Match {
+	m, _ := ctx.Value(matchKey{}).(*model.Match)
+	return m
+}
+
+// Render message for a destination's profile. Messages without a match,
+// like digests and lifecycle notices, are sent as built under every profile.
+func formatAlert(ctx context.Context, profile, message string) string {
+	m := matchFrom(ctx)
+	if m == nil {
+		return message
+	}
+	return render.Profile(profile, *m, message)
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+func TestFormatAlert(t *testing.T) {
+	ctx := WithMatch(context.Background(), model.Match{Rule: "urgent", ChatTitle: "Ops", Link: "https://t.me/c/5/7"})
+	const standard = "🚨 <b>Match:</b> urgent"
+
+	if got := formatAlert(ctx, "", standard); got != standard {
+		t.Errorf("expected the message unchanged without a profile, got %q", got)
+	}
+	if got := formatAlert(ctx, config.ProfileCompact, standard); got != `🚨 <b>urgent</b> in Ops — <a href="https://t.me/c/5/7">link</a>` {
+		t.Errorf("expected the compact alert, got %q", got)
+	}
+	if got := formatAlert(context.Background(), config.ProfileCompact, "Digest"); got != "Digest" {
+		t.Errorf("expected messages without a match unchanged, got %q", got)
+	}
+	if got := formatAlert(withoutMatch(ctx), config.ProfileCompact, "Merged"); got != "Merged" {
+		t.Errorf("expected a detached match to leave the message unchanged, got %q", got)
+	}
+}
+
+func TestProfiles_PerDestination(t *testing.T) {
+	ctx := WithMatch(context.Background(), model.Match{Rule: "urgent", ChatTitle: "Ops", MsgID: 7, Text: "urgent deploy"})
+	const standard = "<b>Match:</b> urgent"
+
+	t.Run("Telegram chats", func(t *testing.T) {
+		var mux sync.Mutex
+		texts := make(map[float64]any)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var payload map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&payload)
+			mux.Lock()
+			texts[payload["chat_id"].(float64)] = payload["text"]
+			mux.Unlock()
+		}))
+		defer server.Close()
+
+		cfg := &config.Config{BotToken: "test_token"}
+		cfg.Notifier.Chats = []config.ChatTarget{
+			{ID: -1},
+			{ID: -2, Profile: config.ProfileCompact},
+		}
+		n := newTestNotifier(t, cfg)
+		n.baseURL = server.URL
+		if err := n.Send(ctx, standard); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if texts[-1] != standard {
+			t.Errorf("expected the standard alert, got %v", texts[-1])
+		}
+		if texts[-2] != "🚨 <b>urgent</b> in Ops" {
+			t.Errorf("expected the compact alert, got %v", texts[-2])
+		}
+	})
+
+	t.Run("Stdout as JSON lines", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := NewWriter(&buf, config.ProfileRawJSON)
+		if err := w.Send(ctx, standard); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+		if err := w.Send(context.Background(), "<b>Digest</b>"); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+		lines := strings.SplitN(buf.String(), "\n", 2)
+		var decoded model.Match
+		if err := json.Unmarshal([]byte(lines[0]), &decoded); err != nil || decoded.Rule != "urgent" {
+			t.Errorf("expected the match as one JSON line, got %q (%v)", lines[0], err)
+		}
+		if lines[1] != "Digest\n\n" {
+			t.Errorf("expected messages without a match as plain text, got %q", lines[1])
+		}
+	})
+}
@@ -0,0 +1,236 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package notifier
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/logger"
+)
+
+// Returned without trying while a backend's circuit is open
+var ErrCircuitOpen = errors.New("circuit open, backend is failing")
+
+// Per-backend delivery counters, served at /debug/vars by the admin server
+var retryMetrics = expvar.NewMap("notifier")
+
+// Mark an error as not worth retrying
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Report whether err was marked as not worth retrying
+func isPermanent(err error) bool {
+	var perm permanentError
+	return errors.As(err, &perm)
+}
+
+// Ask the policy to wait at least after before the next try
+type retryAfterError struct {
+	err   error
+	after time.Duration
+}
+
+func (e retryAfterError) Error() string { return e.err.Error() }
+func (e retryAfterError) Unwrap() error { return e.err }
+
+// Retry sends with jittered exponential backoff and trip a circuit breaker
+// after consecutive failed messages, shared by every notifier backend
+type RetryPolicy struct {
+	name      string
+	log       *zap.Logger
+	attempts  int
+	baseDelay time.Duration
+	maxDelay  time.Duration
+	threshold int
+	cooldown  time.Duration
+
+	mux       sync.Mutex
+	failures  int
+	openUntil time.Time
+	trial     bool // A send is probing a circuit whose cooldown elapsed
+}
+
+// Create a RetryPolicy for the named backend, zero config values use the defaults
+func NewRetryPolicy(name string, cfg config.RetryConfig, log *zap.Logger) *RetryPolicy {
+	p := &RetryPolicy{
+		name:      name,
+		log:       log,
+		attempts:  cfg.Attempts,
+		baseDelay: cfg.BaseDelay,
+		maxDelay:  cfg.MaxDelay,
+		threshold: cfg.BreakerThreshold,
+		cooldown:  cfg.BreakerCooldown,
+	}
+	if p.attempts <= 0 {
+		p.attempts = config.DefaultRetryAttempts
+	}
+	if p.baseDelay <= 0 {
+		p.baseDelay = config.DefaultRetryBaseDelay
+	}
+	if p.maxDelay <= 0 {
+		p.maxDelay = config.DefaultRetryMaxDelay
+	}
+	if p.threshold <= 0 {
+		p.threshold = config.DefaultBreakerThreshold
+	}
+	if p.cooldown <= 0 {
+		p.cooldown = config.DefaultBreakerCooldown
+	}
+	retryMetrics.Set(p.name+".circuit_open", new(expvar.Int))
+	return p
+}
+
+// Call send until it succeeds, fails permanently or runs out of attempts
+func (p *RetryPolicy) Do(ctx context.Context, send func(context.Context) error) error {
+	if !p.allow() {
+		retryMetrics.Add(p.name+".rejected", 1)
+		return ErrCircuitOpen
+	}
+
+	var lastErr error
+	for i := range p.attempts {
+		if err := ctx.Err(); err != nil {
+			p.abort()
+			return err
+		}
+
+		retryMetrics.Add(p.name+".attempts", 1)
+		err := send(ctx)
+		if err == nil {
+			p.record(true)
+			return nil
+		}
+		lastErr = err
+
+		var perm permanentError
+		if errors.As(err, &perm) || i == p.attempts-1 {
+			break
+		}
+
+		delay := p.backoff(i)
+		var ra retryAfterError
+		if errors.As(err, &ra) && ra.after > delay {
+			// Waiting out a long rate limit here would stall the caller
+			if ra.after > p.maxDelay {
+				break
+			}
+			delay = ra.after
+		}
+
+		p.log.Warn("Failed to send notification, retrying...",
+			zap.String("backend", p.name),
+			zap.Int("attempt", i+1),
+			zap.Duration("delay", delay),
+			logger.Trace(ctx),
+			zap.Error(err),
+		)
+		retryMetrics.Add(p.name+".retries", 1)
+
+		select {
+		case <-ctx.Done():
+			p.abort()
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+
+	retryMetrics.Add(p.name+".failures", 1)
+	p.record(false)
+	return fmt.Errorf("failed after retries: %w", lastErr)
+}
+
+// Report whether the circuit is open
+func (p *RetryPolicy) Open() bool {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	return time.Now().Before(p.openUntil)
+}
+
+// Return the jittered wait before retry i, between half and all of the doubled delay
+func (p *RetryPolicy) backoff(i int) time.Duration {
+	d := p.baseDelay << i
+	if d <= 0 || d > p.maxDelay {
+		d = p.maxDelay
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// Admit a send unless the circuit is open, letting one trial through after the cooldown
+func (p *RetryPolicy) allow() bool {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	if p.failures < p.threshold {
+		return true
+	}
+	if time.Now().Before(p.openUntil) || p.trial {
+		return false
+	}
+	p.trial = true
+	return true
+}
+
+// Release a trial that was cancelled before it could tell anything
+func (p *RetryPolicy) abort() {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	p.trial = false
+}
+
+func (p *RetryPolicy) record(ok bool) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	p.trial = false
+	if ok {
+		if p.failures >= p.threshold {
+			p.log.Info("Notifier circuit closed", zap.String("backend", p.name))
+			retryMetrics.Set(p.name+".circuit_open", new(expvar.Int))
+		}
+		p.failures = 0
+		p.openUntil = time.Time{}
+		return
+	}
+
+	p.failures++
+	if p.failures < p.threshold {
+		return
+	}
+	p.openUntil = time.Now().Add(p.cooldown)
+	if p.failures == p.threshold {
+		p.log.Warn("Notifier circuit opened",
+			zap.String("backend", p.name),
+			zap.Int("failures", p.failures),
+			zap.Duration("cooldown", p.cooldown),
+		)
+		retryMetrics.Add(p.name+".circuit_trips", 1)
+		open := new(expvar.Int)
+		open.Set(1)
+		retryMetrics.Set(p.name+".circuit_open", open)
+	}
+}
@@ -0,0 +1,118 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package notifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+func TestRetryPolicy_Do(t *testing.T) {
+	cfg := config.RetryConfig{Attempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+	t.Run("Retries until success", func(t *testing.T) {
+		p := NewRetryPolicy("test-retry", cfg, zap.NewNop())
+		calls := 0
+		err := p.Do(context.Background(), func(context.Context) error {
+			calls++
+			if calls < 3 {
+				return errors.New("boom")
+			}
+			return nil
+		})
+		if err != nil || calls != 3 {
+			t.Errorf("expected success on the third try, got %v after %d calls", err, calls)
+		}
+	})
+
+	t.Run("Permanent errors are not retried", func(t *testing.T) {
+		p := NewRetryPolicy("test-permanent", cfg, zap.NewNop())
+		calls := 0
+		err := p.Do(context.Background(), func(context.Context) error {
+			calls++
+			return permanentError{errors.New("bad request")}
+		})
+		if err == nil || calls != 1 {
+			t.Errorf("expected a single failed try, got %v after %d calls", err, calls)
+		}
+	})
+
+	t.Run("Long retry-after gives up", func(t *testing.T) {
+		p := NewRetryPolicy("test-retry-after", cfg, zap.NewNop())
+		calls := 0
+		_ = p.Do(context.Background(), func(context.Context) error {
+			calls++
+			return retryAfterError{err: errors.New("slow down"), after: time.Minute}
+		})
+		if calls != 1 {
+			t.Errorf("expected no retry past max_delay, got %d calls", calls)
+		}
+	})
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := NewRetryPolicy("test-backoff", config.RetryConfig{BaseDelay: time.Second, MaxDelay: 3 * time.Second}, zap.NewNop())
+	for i, want := range []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second} {
+		for range 20 {
+			if d := p.backoff(i); d < want/2 || d > want {
+				t.Fatalf("backoff(%d) = %s, want within [%s, %s]", i, d, want/2, want)
+			}
+		}
+	}
+}
+
+func TestRetryPolicy_Breaker(t *testing.T) {
+	p := NewRetryPolicy("test-breaker", config.RetryConfig{
+		Attempts:         1,
+		BreakerThreshold: 2,
+		BreakerCooldown:  20 * time.Millisecond,
+	}, zap.NewNop())
+	fail := func(context.Context) error { return errors.New("down") }
+
+	for range 2 {
+		if err := p.Do(context.Background(), fail); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("expected a delivery error, got %v", err)
+		}
+	}
+	if !p.Open() {
+		t.Fatal("expected the circuit to open after consecutive failures")
+	}
+	called := false
+	if err := p.Do(context.Background(), func(context.Context) error { called = true; return nil }); !errors.Is(err, ErrCircuitOpen) || called {
+		t.Fatalf("expected ErrCircuitOpen without trying, got %v", err)
+	}
+	if got := retryMetrics.Get("test-breaker.circuit_open").String(); got != "1" {
+		t.Errorf("expected circuit_open metric 1, got %s", got)
+	}
+
+	// After the cooldown a trial send closes the circuit again
+	time.Sleep(30 * time.Millisecond)
+	if err := p.Do(context.Background(), func(context.Context) error { return nil }); err != nil {
+		t.Fatalf("expected trial send to go through, got %v", err)
+	}
+	if p.Open() || retryMetrics.Get("test-breaker.circuit_open").String() != "0" {
+		t.Error("expected the circuit to close after a successful trial")
+	}
+}
@@ -22,14 +22,18 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"go.uber.org/zap"
 
 	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/logger"
 )
 
 // Define interface for sending alerts
@@ -37,74 +41,224 @@ type Notifier interface {
 	Send(ctx context.Context, message string) error
 }
 
+// Optionally implemented by notifiers that can signal delivery trouble,
+// letting callers hold alerts back instead of piling up sends
+type HealthReporter interface {
+	Healthy() bool
+}
+
+// Optionally implemented by notifiers that deliver later, letting callers
+// give up their send slot once the message is queued and still learn whether
+// it was delivered
+type Queuer interface {
+	Queue(ctx context.Context, message string) <-chan error
+}
+
+type silentKey struct{}
+
+// Deliver messages sent with the returned context without a notification sound
+func WithSilent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, silentKey{}, true)
+}
+
+func silent(ctx context.Context) bool {
+	s, _ := ctx.Value(silentKey{}).(bool)
+	return s
+}
+
+type urgentKey struct{}
+
+// Deliver messages sent with the returned context at once and with sound,
+// past silent destinations, coalescing and the rate limit
+func WithUrgent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, urgentKey{}, true)
+}
+
+func urgent(ctx context.Context) bool {
+	u, _ := ctx.Value(urgentKey{}).(bool)
+	return u
+}
+
+// Consecutive failed sends before a notifier reports itself unhealthy
+const unhealthyAfter = 3
+
 // Send messages using the Telegram Bot API
 type TelegramNotifier struct {
 	client  *http.Client
 	log     *zap.Logger
 	token   string
-	chatID  int64
+	targets []target
 	baseURL string
+	buttons bool        // Offer acknowledge and resolve buttons on alerts with an ID
+	snooze  bool        // Also offer a snooze button
+	limits  *userLimits // Commands and inline queries answered per user, only used by Poll
+
+	// Delivery health
+	mux                 sync.Mutex
+	consecutiveFailures int
+	lastFailure         time.Time
+	recovery            time.Duration // After the last failure, before a send is tried again
+	rateLimitedUntil    time.Time
+}
+
+// A destination chat with its own retry policy, so one broken chat
+// does not hold back the others
+type target struct {
+	config.ChatTarget
+	retry *RetryPolicy
 }
 
 // Create new TelegramNotifier
-func New(cfg *config.Config, log *zap.Logger) *TelegramNotifier {
-	return &TelegramNotifier{
-		client: &http.Client{
-			Timeout: 15 * time.Second,
-		},
-		log:     log,
-		token:   cfg.BotToken,
-		chatID:  cfg.ChatID,
-		baseURL: "https://api.telegram.org",
+func New(cfg *config.Config, log *zap.Logger) (*TelegramNotifier, error) {
+	client, err := newHTTPClient(cfg.Notifier.HTTP, 15*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	baseURL := strings.TrimRight(cfg.Notifier.APIURL, "/")
+	if baseURL == "" {
+		baseURL = config.DefaultBotAPIURL
+	}
+
+	chats := cfg.Notifier.Chats
+	if len(chats) == 0 {
+		chats = []config.ChatTarget{{ID: cfg.ChatID, ParseMode: config.ParseModeHTML}}
+	}
+	targets := make([]target, len(chats))
+	for i, chat := range chats {
+		targets[i] = target{
+			ChatTarget: chat,
+			retry:      NewRetryPolicy(fmt.Sprintf("telegram:%d", chat.ID), cfg.Notifier.Retry, log),
+		}
 	}
+
+	recovery := cfg.Notifier.Retry.BreakerCooldown
+	if recovery <= 0 {
+		recovery = config.DefaultBreakerCooldown
+	}
+
+	return &TelegramNotifier{
+		client:   client,
+		log:      log,
+		token:    cfg.BotToken,
+		targets:  targets,
+		baseURL:  baseURL,
+		buttons:  cfg.Acks.Enabled && cfg.Acks.Buttons,
+		snooze:   cfg.Acks.Snooze > 0,
+		limits:   newUserLimits(cfg.Commands.RateLimit),
+		recovery: recovery,
+	}, nil
 }
 
-// Post text message to configured chat
+// Post text message to every configured chat, failing only if none received it
 func (t *TelegramNotifier) Send(ctx context.Context, message string) error {
 	url := fmt.Sprintf("%s/bot%s/sendMessage", t.baseURL, t.token)
 
+	errs := make([]error, len(t.targets))
+	var wg sync.WaitGroup
+	for i, dest := range t.targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = t.sendTo(ctx, url, dest, message)
+		}()
+	}
+	wg.Wait()
+
+	delivered := 0
+	for _, err := range errs {
+		if err == nil {
+			delivered++
+		}
+	}
+	if delivered == 0 {
+		if ctx.Err() == nil {
+			t.recordResult(false)
+		}
+		return fmt.Errorf("failed to send notification: %w", errors.Join(errs...))
+	}
+
+	// Reached some chats: report the rest, but do not make callers resend to all
+	for i, err := range errs {
+		if err != nil {
+			t.log.Error("Failed to send notification to chat", zap.Int64("chat_id", t.targets[i].ID), logger.Trace(ctx), zap.Error(err))
+		}
+	}
+	t.recordResult(true)
+	return nil
+}
+
+// Post message to one chat in its profile and parse mode, retrying under its policy
+func (t *TelegramNotifier) sendTo(ctx context.Context, url string, dest target, message string) error {
+	message = formatAlert(ctx, dest.Profile, message)
 	payload := map[string]interface{}{
-		"chat_id":                  t.chatID,
+		"chat_id":                  dest.ID,
 		"text":                     message,
-		"parse_mode":               "HTML",
 		"disable_web_page_preview": true,
 	}
+	if dest.ParseMode == config.ParseModeText {
+		payload["text"] = plainText(message)
+	} else {
+		payload["parse_mode"] = "HTML"
+	}
+	if (dest.Silent || silent(ctx)) && !urgent(ctx) {
+		payload["disable_notification"] = true
+	}
+	if id := AlertID(ctx); id != "" && t.buttons {
+		actions := []string{ActionAck, ActionResolve}
+		if t.snooze {
+			actions = append(actions, ActionSnooze)
+		}
+		payload["reply_markup"] = alertKeyboard(id, actions)
+	}
 
 	body, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
-	const maxRetries = 3
-	var lastErr error
-
-	for i := range maxRetries {
-		if ctx.Err() != nil {
-			return ctx.Err()
-		}
+	err = dest.retry.Do(ctx, func(ctx context.Context) error {
+		return t.attemptSend(ctx, url, body)
+	})
+	if err != nil {
+		return err
+	}
+	t.log.Info("Notification sent", zap.Int64("chat_id", dest.ID), logger.Trace(ctx))
+	return nil
+}
 
-		err := t.attemptSend(ctx, url, body)
-		if err == nil {
-			t.log.Info("Notification sent", zap.Int64("chat_id", t.chatID))
-			return nil
-		}
+// Post plain text as the bot to a chat outside the alert targets, given as a
+// Bot API ID or @username
+func (t *TelegramNotifier) Post(ctx context.Context, chat, text string) error {
+	return t.callAPI(ctx, "sendMessage", map[string]any{"chat_id": chat, "text": text}, nil)
+}
 
-		lastErr = err
-		t.log.Warn("Failed to send notification, retrying...",
-			zap.Int("attempt", i+1),
-			zap.Error(err),
-		)
-
-		// Exponential backoff
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-time.After(time.Duration(1<<i) * time.Second):
-			continue
+// Report false while rate limited, for a cooldown after repeated delivery
+// failures or while the circuit of every chat is open. Once the cooldown
+// passes the next send probes the API, and a failure restarts it.
+func (t *TelegramNotifier) Healthy() bool {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	failing := t.consecutiveFailures >= unhealthyAfter && time.Since(t.lastFailure) < t.recovery
+	if failing || time.Now().Before(t.rateLimitedUntil) {
+		return false
+	}
+	for _, dest := range t.targets {
+		if !dest.retry.Open() {
+			return true
 		}
 	}
+	return false
+}
 
-	return fmt.Errorf("failed to send notification after %d attempts: %w", maxRetries, lastErr)
+func (t *TelegramNotifier) recordResult(ok bool) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	if ok {
+		t.consecutiveFailures = 0
+		return
+	}
+	t.consecutiveFailures++
+	t.lastFailure = time.Now()
 }
 
 func (t *TelegramNotifier) attemptSend(ctx context.Context, url string, body []byte) error {
@@ -131,8 +285,19 @@ func (t *TelegramNotifier) attemptSend(ctx context.Context, url string, body []b
 		if retryAfter == 0 {
 			retryAfter = 5 // Default backoff
 		}
-		return fmt.Errorf("rate limited, retry after %d seconds", retryAfter)
+		t.mux.Lock()
+		t.rateLimitedUntil = time.Now().Add(time.Duration(retryAfter) * time.Second)
+		t.mux.Unlock()
+		return retryAfterError{
+			err:   fmt.Errorf("rate limited, retry after %d seconds", retryAfter),
+			after: time.Duration(retryAfter) * time.Second,
+		}
 	}
 
-	return fmt.Errorf("api returned status: %d", resp.StatusCode)
+	err = fmt.Errorf("api returned status: %d", resp.StatusCode)
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 {
+		// Bad markup, a wrong chat or a revoked token will not fix themselves
+		return permanentError{err}
+	}
+	return err
 }
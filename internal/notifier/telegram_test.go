@@ -23,8 +23,10 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -32,7 +34,6 @@ import (
 )
 
 func TestTelegramNotifier_Send(t *testing.T) {
-	log := zap.NewNop()
 	cfg := &config.Config{
 		BotToken: "test_token",
 		ChatID:   123456,
@@ -64,7 +65,7 @@ func TestTelegramNotifier_Send(t *testing.T) {
 		}))
 		defer server.Close()
 
-		n := New(cfg, log)
+		n := newTestNotifier(t, cfg)
 		n.baseURL = server.URL // Override base URL for testing
 
 		if err := n.Send(context.Background(), "<b>Hello</b>"); err != nil {
@@ -72,6 +73,96 @@ func TestTelegramNotifier_Send(t *testing.T) {
 		}
 	})
 
+	t.Run("Self-hosted Bot API server", func(t *testing.T) {
+		var path string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.Path
+		}))
+		defer server.Close()
+
+		local := *cfg
+		local.Notifier.APIURL = server.URL + "/"
+		n := newTestNotifier(t, &local)
+		if err := n.Send(context.Background(), "Hello"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if path != "/bottest_token/sendMessage" {
+			t.Errorf("expected the configured server to be called, got path %q", path)
+		}
+	})
+
+	t.Run("Multiple chats", func(t *testing.T) {
+		var mux sync.Mutex
+		payloads := make(map[float64]map[string]interface{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var payload map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&payload)
+			mux.Lock()
+			payloads[payload["chat_id"].(float64)] = payload
+			mux.Unlock()
+			if payload["chat_id"] == float64(-3) {
+				w.WriteHeader(http.StatusBadRequest)
+			}
+		}))
+		defer server.Close()
+
+		multi := *cfg
+		multi.Notifier.Chats = []config.ChatTarget{
+			{ID: -1, ParseMode: config.ParseModeHTML},
+			{ID: -2, ParseMode: config.ParseModeText, Silent: true},
+			{ID: -3, ParseMode: config.ParseModeHTML},
+		}
+		n := newTestNotifier(t, &multi)
+		n.baseURL = server.URL
+
+		// A chat rejecting the alert does not fail the others
+		if err := n.Send(context.Background(), `<b>Hit</b> in <a href="https://t.me/c/1/2">chat</a>`); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(payloads) != 3 {
+			t.Fatalf("expected every chat to be called, got %v", payloads)
+		}
+		if html := payloads[-1]; html["parse_mode"] != "HTML" || html["disable_notification"] != nil {
+			t.Errorf("unexpected HTML payload %v", html)
+		}
+		text := payloads[-2]
+		if text["parse_mode"] != nil || text["disable_notification"] != true || text["text"] != "Hit in chat (https://t.me/c/1/2)" {
+			t.Errorf("unexpected plain text payload %v", text)
+		}
+	})
+
+	t.Run("Silent and posted messages", func(t *testing.T) {
+		var payloads []map[string]any
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var payload map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&payload)
+			payloads = append(payloads, payload)
+			_, _ = w.Write([]byte(`{"ok": true, "result": {}}`))
+		}))
+		defer server.Close()
+
+		n := newTestNotifier(t, cfg)
+		n.baseURL = server.URL
+		if err := n.Send(WithSilent(context.Background()), "Quiet"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := n.Post(context.Background(), "@canary_chat", "Hello"); err != nil {
+			t.Fatalf("Post() error = %v", err)
+		}
+		if len(payloads) != 2 || payloads[0]["disable_notification"] != true {
+			t.Fatalf("expected a silent alert, got %v", payloads)
+		}
+		if err := n.Send(WithUrgent(WithSilent(context.Background())), "Loud"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if p := payloads[2]; p["disable_notification"] != nil {
+			t.Errorf("expected an urgent alert with sound, got %v", p)
+		}
+		if p := payloads[1]; p["chat_id"] != "@canary_chat" || p["text"] != "Hello" || p["parse_mode"] != nil {
+			t.Errorf("unexpected posted payload %v", p)
+		}
+	})
+
 	t.Run("Retry on 500", func(t *testing.T) {
 		var calls int32
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -84,7 +175,7 @@ func TestTelegramNotifier_Send(t *testing.T) {
 		}))
 		defer server.Close()
 
-		n := New(cfg, log)
+		n := newTestNotifier(t, cfg)
 		n.baseURL = server.URL
 
 		if err := n.Send(context.Background(), "RetryMe"); err != nil {
@@ -101,7 +192,7 @@ func TestTelegramNotifier_Send(t *testing.T) {
 		}))
 		defer server.Close()
 
-		n := New(cfg, log)
+		n := newTestNotifier(t, cfg)
 		n.baseURL = server.URL
 
 		if err := n.Send(context.Background(), "FailMe"); err == nil {
@@ -109,3 +200,68 @@ func TestTelegramNotifier_Send(t *testing.T) {
 		}
 	})
 }
+
+func TestTelegramNotifier_Healthy(t *testing.T) {
+	cfg := &config.Config{BotToken: "test_token", ChatID: 1}
+
+	t.Run("Rate Limited", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		n := newTestNotifier(t, cfg)
+		if !n.Healthy() {
+			t.Fatal("expected fresh notifier to be healthy")
+		}
+		if err := n.attemptSend(context.Background(), server.URL, []byte("{}")); err == nil {
+			t.Fatal("expected rate limit error")
+		}
+		if n.Healthy() {
+			t.Error("expected notifier to be unhealthy while rate limited")
+		}
+	})
+
+	t.Run("Consecutive Failures", func(t *testing.T) {
+		n := newTestNotifier(t, cfg)
+		for range unhealthyAfter {
+			n.recordResult(false)
+		}
+		if n.Healthy() {
+			t.Error("expected notifier to be unhealthy after repeated failures")
+		}
+		n.recordResult(true)
+		if !n.Healthy() {
+			t.Error("expected a success to restore health")
+		}
+	})
+
+	t.Run("Recovery", func(t *testing.T) {
+		n := newTestNotifier(t, cfg)
+		n.recovery = 20 * time.Millisecond
+		for range unhealthyAfter {
+			n.recordResult(false)
+		}
+		if n.Healthy() {
+			t.Fatal("expected notifier to be unhealthy after repeated failures")
+		}
+		time.Sleep(30 * time.Millisecond)
+		if !n.Healthy() {
+			t.Error("expected a trial send to be allowed after the cooldown")
+		}
+		n.recordResult(false)
+		if n.Healthy() {
+			t.Error("expected a failed trial to restart the cooldown")
+		}
+	})
+}
+
+func newTestNotifier(t *testing.T, cfg *config.Config) *TelegramNotifier {
+	t.Helper()
+	n, err := New(cfg, zap.NewNop())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return n
+}
@@ -0,0 +1,44 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package notifier
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	linkTag = regexp.MustCompile(`<a href="([^"]*)">(.*?)</a>`)
+	anyTag  = regexp.MustCompile(`</?[a-z]+[^>]*>`)
+)
+
+// Convert the HTML alerts are written in to plain text, keeping link
+// targets next to their labels
+func plainText(message string) string {
+	message = linkTag.ReplaceAllStringFunc(message, func(tag string) string {
+		m := linkTag.FindStringSubmatch(tag)
+		href, label := m[1], anyTag.ReplaceAllString(m[2], "")
+		if strings.TrimSpace(label) == "" || html.UnescapeString(label) == html.UnescapeString(href) {
+			return href
+		}
+		return label + " (" + href + ")"
+	})
+	return html.UnescapeString(anyTag.ReplaceAllString(message, ""))
+}
@@ -0,0 +1,114 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package notifier
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/logger"
+)
+
+// Window over which the notifier rate limit applies
+const RateWindow = time.Minute
+
+// Decide when the next message may be sent
+type RateLimiter interface {
+	// Count a send, returning how long to wait first if the limit is reached
+	Reserve(ctx context.Context) (time.Duration, error)
+}
+
+// Delay sends so they stay within a rate limit
+type Throttle struct {
+	next    Notifier
+	limiter RateLimiter
+	log     *zap.Logger
+}
+
+// Create new Throttle delivering through next
+func NewThrottle(next Notifier, limiter RateLimiter, log *zap.Logger) *Throttle {
+	return &Throttle{next: next, limiter: limiter, log: log}
+}
+
+// Wait for a free slot, then deliver the message. Urgent messages do not wait.
+func (t *Throttle) Send(ctx context.Context, message string) error {
+	for !urgent(ctx) {
+		wait, err := t.limiter.Reserve(ctx)
+		if err != nil {
+			// Prefer a possible 429 over dropping the alert
+			t.log.Warn("Rate limiter unavailable, sending anyway", logger.Trace(ctx), zap.Error(err))
+			break
+		}
+		if wait <= 0 {
+			break
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return t.next.Send(ctx, message)
+}
+
+// Forward the health of the wrapped notifier
+func (t *Throttle) Healthy() bool {
+	if h, ok := t.next.(HealthReporter); ok {
+		return h.Healthy()
+	}
+	return true
+}
+
+// In-process fixed window RateLimiter
+type WindowLimiter struct {
+	mux    sync.Mutex
+	limit  int
+	window time.Duration
+	start  time.Time
+	count  int
+	now    func() time.Time
+}
+
+// Create new WindowLimiter allowing limit sends per window
+func NewWindowLimiter(limit int, window time.Duration) *WindowLimiter {
+	return &WindowLimiter{limit: limit, window: window, now: time.Now}
+}
+
+// Count a send, returning how long to wait first if the window is full
+func (w *WindowLimiter) Reserve(ctx context.Context) (time.Duration, error) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+
+	now := w.now()
+	if now.Sub(w.start) >= w.window {
+		w.start = now
+		w.count = 0
+	}
+	if w.count >= w.limit {
+		return w.start.Add(w.window).Sub(now), nil
+	}
+	w.count++
+	return 0, nil
+}
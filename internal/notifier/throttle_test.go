@@ -0,0 +1,90 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package notifier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestWindowLimiter(t *testing.T) {
+	now := time.Unix(1000, 0)
+	w := NewWindowLimiter(2, time.Minute)
+	w.now = func() time.Time { return now }
+
+	for range 2 {
+		if wait, _ := w.Reserve(context.Background()); wait != 0 {
+			t.Fatalf("expected immediate slot, got %s", wait)
+		}
+	}
+
+	now = now.Add(20 * time.Second)
+	if wait, _ := w.Reserve(context.Background()); wait != 40*time.Second {
+		t.Errorf("expected 40s until the window resets, got %s", wait)
+	}
+
+	now = now.Add(40 * time.Second)
+	if wait, _ := w.Reserve(context.Background()); wait != 0 {
+		t.Errorf("expected slot in the next window, got %s", wait)
+	}
+}
+
+// Report a short wait before every second send
+type alternatingLimiter struct{ calls int }
+
+func (a *alternatingLimiter) Reserve(ctx context.Context) (time.Duration, error) {
+	a.calls++
+	if a.calls%2 == 0 {
+		return 10 * time.Millisecond, nil
+	}
+	return 0, nil
+}
+
+func TestThrottle(t *testing.T) {
+	rec := &recordingNotifier{}
+	limiter := &alternatingLimiter{}
+	th := NewThrottle(rec, limiter, zap.NewNop())
+
+	for range 2 {
+		if err := th.Send(context.Background(), "alert"); err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+	}
+	if got := len(rec.Messages()); got != 2 {
+		t.Errorf("expected 2 deliveries, got %d", got)
+	}
+	if limiter.calls != 3 {
+		t.Errorf("expected the throttled send to retry once, got %d reservations", limiter.calls)
+	}
+
+	// Urgent alerts neither wait nor count
+	if err := th.Send(WithUrgent(context.Background()), "alert"); err != nil || limiter.calls != 3 {
+		t.Errorf("expected an urgent send to skip the limiter, got %d reservations, %v", limiter.calls, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	limiter.calls = 1
+	if err := th.Send(ctx, "alert"); err == nil {
+		t.Error("expected cancelled context to abort a throttled send")
+	}
+}
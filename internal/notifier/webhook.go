@@ -0,0 +1,311 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"text/template"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/logger"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Returned when every webhook delivery slot is busy
+var ErrWebhooksSaturated = errors.New("webhook deliveries saturated")
+
+// Helpers available to payload templates
+var templateFuncs = template.FuncMap{
+	// Encode a value as JSON, for embedding strings safely in JSON payloads
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// A configured endpoint and the rules routed to it
+type webhook struct {
+	name     string
+	url      string
+	method   string
+	headers  map[string]string
+	rules    []string
+	template *template.Template // nil sends the match in the webhook's encoding
+	encoding string
+	retry    *RetryPolicy
+}
+
+// Deliver matches to the webhooks whose rules they satisfy
+type Webhooks struct {
+	client *http.Client
+	log    *zap.Logger
+	hooks  []webhook
+	sem    chan struct{}
+
+	outbox   *Outbox // nil sends each match once, without keeping it
+	interval time.Duration
+	maxAge   time.Duration
+}
+
+// Create new Webhooks, failing on invalid payload templates
+func NewWebhooks(cfg config.NotifierConfig, log *zap.Logger) (*Webhooks, error) {
+	concurrency := cfg.Concurrency
+	if concurrency <= 0 {
+		concurrency = config.DefaultNotifierConcurrency
+	}
+	client, err := newHTTPClient(cfg.HTTP, config.DefaultWebhookTimeout)
+	if err != nil {
+		return nil, err
+	}
+	w := &Webhooks{
+		client:   client,
+		log:      log,
+		sem:      make(chan struct{}, concurrency),
+		interval: cfg.Outbox.Interval,
+		maxAge:   cfg.Outbox.MaxAge,
+	}
+	if w.interval <= 0 {
+		w.interval = config.DefaultOutboxInterval
+	}
+	if w.maxAge <= 0 {
+		w.maxAge = config.DefaultOutboxMaxAge
+	}
+
+	for i, hc := range cfg.Webhooks {
+		hook := webhook{
+			name:     hc.Name,
+			url:      hc.URL,
+			method:   strings.ToUpper(hc.Method),
+			headers:  hc.Headers,
+			rules:    hc.Rules,
+			encoding: hc.Encoding,
+		}
+		if hook.name == "" {
+			hook.name = hc.URL
+		}
+		if hook.method == "" {
+			hook.method = http.MethodPost
+		}
+		hook.retry = NewRetryPolicy("webhook:"+hook.name, cfg.Retry, log)
+		if hc.Template != "" {
+			tmpl, err := template.New(hook.name).Funcs(templateFuncs).Option("missingkey=error").Parse(hc.Template)
+			if err != nil {
+				return nil, fmt.Errorf("webhooks entry %d: invalid template: %w", i, err)
+			}
+			hook.template = tmpl
+		}
+		w.hooks = append(w.hooks, hook)
+	}
+	return w, nil
+}
+
+// Return the number of configured webhooks
+func (w *Webhooks) Len() int {
+	return len(w.hooks)
+}
+
+// Keep deliveries in o until their endpoint accepts them, resent by Run. Call before Deliver.
+func (w *Webhooks) UseOutbox(o *Outbox) {
+	w.outbox = o
+}
+
+// Send the match to every webhook routed its rule, in the background
+func (w *Webhooks) Deliver(ctx context.Context, m model.Match) error {
+	var errs []error
+	for _, hook := range w.hooks {
+		if !hook.wants(m.Rule) {
+			continue
+		}
+		kept := w.outbox != nil && m.ID != ""
+		if kept {
+			added, err := w.outbox.add(hook.name, m)
+			if err != nil {
+				// Still worth trying once
+				w.log.Error("Failed to save webhook delivery to the outbox", zap.String("webhook", hook.name), zap.String("id", m.ID), logger.Trace(ctx), zap.Error(err))
+			}
+			if !added {
+				continue
+			}
+		}
+		select {
+		case w.sem <- struct{}{}:
+		default:
+			if kept {
+				// Left pending for Run
+				_ = w.outbox.release(hook.name, m.ID, false)
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", hook.name, ErrWebhooksSaturated))
+			continue
+		}
+		go w.deliver(ctx, hook, m, kept)
+	}
+	return errors.Join(errs...)
+}
+
+// Resend the deliveries left in the outbox, at once and then every interval, until ctx is done
+func (w *Webhooks) Run(ctx context.Context) {
+	if w.outbox == nil {
+		return
+	}
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		w.resend(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Send the pending deliveries not already in flight, dropping those too old or for removed webhooks
+func (w *Webhooks) resend(ctx context.Context) {
+	hooks := make(map[string]webhook, len(w.hooks))
+	for _, hook := range w.hooks {
+		hooks[hook.name] = hook
+	}
+	for _, p := range w.outbox.List() {
+		if !w.outbox.claim(p) {
+			continue
+		}
+		hook, ok := hooks[p.Webhook]
+		if !ok || time.Since(p.Queued) > w.maxAge {
+			w.log.Warn("Dropping undelivered webhook match", zap.String("webhook", p.Webhook), zap.String("id", p.Match.ID), zap.Time("queued", p.Queued), zap.Bool("configured", ok))
+			if err := w.outbox.release(p.Webhook, p.Match.ID, true); err != nil {
+				w.log.Error("Failed to save the webhook outbox", zap.Error(err))
+			}
+			continue
+		}
+		select {
+		case w.sem <- struct{}{}:
+		default:
+			_ = w.outbox.release(p.Webhook, p.Match.ID, false)
+			return
+		}
+		go w.deliver(ctx, hook, p.Match, true)
+	}
+}
+
+// Send m to hook holding a delivery slot, then settle it in the outbox when kept
+func (w *Webhooks) deliver(ctx context.Context, hook webhook, m model.Match, kept bool) {
+	defer func() { <-w.sem }()
+	err := w.send(ctx, hook, m)
+	if err != nil {
+		w.log.Error("Failed to deliver webhook", zap.String("webhook", hook.name), zap.String("rule", m.Rule), zap.Bool("pending", kept && !isPermanent(err)), logger.Trace(ctx), zap.Error(err))
+	}
+	if !kept {
+		return
+	}
+	// Retrying a rejected payload would fail the same way
+	if err := w.outbox.release(hook.name, m.ID, err == nil || isPermanent(err)); err != nil {
+		w.log.Error("Failed to save the webhook outbox", zap.String("webhook", hook.name), zap.Error(err))
+	}
+}
+
+// Report whether rule is routed to the webhook
+func (h webhook) wants(rule string) bool {
+	if len(h.rules) == 0 {
+		return true
+	}
+	for _, pattern := range h.rules {
+		if pattern == rule {
+			return true
+		}
+		// Regex rules are rarely valid globs, a malformed pattern just does not match
+		if ok, _ := path.Match(pattern, rule); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Render the payload and post it, retrying under the webhook's policy
+func (w *Webhooks) send(ctx context.Context, hook webhook, m model.Match) error {
+	body, err := hook.render(m)
+	if err != nil {
+		return permanentError{err}
+	}
+	return hook.retry.Do(ctx, func(ctx context.Context) error {
+		return w.post(ctx, hook, m.ID, body)
+	})
+}
+
+func (w *Webhooks) post(ctx context.Context, hook webhook, id string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, hook.method, hook.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType(hook.encoding))
+	if id != "" {
+		// The same for every retry and resend, so the receiver can drop duplicates
+		req.Header.Set("Idempotency-Key", id)
+	}
+	for k, v := range hook.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("network error: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		err := fmt.Errorf("endpoint returned status: %d", resp.StatusCode)
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			return permanentError{err}
+		}
+		return err
+	}
+	return nil
+}
+
+// Build the request body from the template, or the match itself
+func (h webhook) render(m model.Match) ([]byte, error) {
+	switch {
+	case h.encoding == config.EncodingProtobuf:
+		return marshalProto(m), nil
+	case h.encoding == config.EncodingMsgpack:
+		return marshalMsgpack(m), nil
+	case h.template == nil:
+		body, err := json.Marshal(m)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal payload: %w", err)
+		}
+		return body, nil
+	}
+	var buf bytes.Buffer
+	if err := h.template.Execute(&buf, m); err != nil {
+		return nil, fmt.Errorf("failed to render template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,244 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tinylib/msgp/msgp"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Received webhook request
+type hookRequest struct {
+	path   string
+	header string
+	body   string
+}
+
+func TestWebhooks_Deliver(t *testing.T) {
+	received := make(chan hookRequest, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- hookRequest{path: r.URL.Path, header: r.Header.Get("X-Token"), body: string(body)}
+	}))
+	defer server.Close()
+
+	w, err := NewWebhooks(config.NotifierConfig{Webhooks: []config.WebhookConfig{
+		{
+			URL:      server.URL + "/siem",
+			Rules:    []string{"urgent", "file:*"},
+			Headers:  map[string]string{"X-Token": "secret"},
+			Template: `{"alert": {{json .Rule}}, "chat": {{.ChatID}}, "text": {{json .Text}}}`,
+		},
+		{URL: server.URL + "/all"},
+	}}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewWebhooks() error = %v", err)
+	}
+
+	m := model.Match{Rule: "file:apk", Kind: "file", ChatID: -1001, Text: `say "hi"`, Date: time.Unix(0, 0).UTC()}
+	if err := w.Deliver(context.Background(), m); err != nil {
+		t.Fatalf("Deliver() error = %v", err)
+	}
+
+	got := make(map[string]hookRequest)
+	for range 2 {
+		select {
+		case r := <-received:
+			got[r.path] = r
+		case <-time.After(time.Second):
+			t.Fatal("expected both webhooks to be called")
+		}
+	}
+	if siem := got["/siem"]; siem.body != `{"alert": "file:apk", "chat": -1001, "text": "say \"hi\""}` || siem.header != "secret" {
+		t.Errorf("unexpected templated request %+v", siem)
+	}
+	var all model.Match
+	if err := json.Unmarshal([]byte(got["/all"].body), &all); err != nil || all.Rule != "file:apk" || all.ChatID != -1001 {
+		t.Errorf("expected the match as JSON, got %q", got["/all"].body)
+	}
+
+	// Rules not routed to a webhook skip it
+	if err := w.Deliver(context.Background(), model.Match{Rule: "domain:evil.com"}); err != nil {
+		t.Fatalf("Deliver() error = %v", err)
+	}
+	select {
+	case r := <-received:
+		if r.path != "/all" {
+			t.Errorf("expected only the catch-all webhook, got %s", r.path)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the catch-all webhook to be called")
+	}
+}
+
+func TestNewWebhooks_InvalidTemplate(t *testing.T) {
+	_, err := NewWebhooks(config.NotifierConfig{Webhooks: []config.WebhookConfig{
+		{URL: "http://example.com", Template: "{{.Rule"},
+	}}, zap.NewNop())
+	if err == nil {
+		t.Error("expected error for malformed template")
+	}
+}
+
+func TestWebhooks_Encoding(t *testing.T) {
+	received := make(chan hookRequest, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- hookRequest{path: r.URL.Path, header: r.Header.Get("Content-Type"), body: string(body)}
+	}))
+	defer server.Close()
+
+	w, err := NewWebhooks(config.NotifierConfig{Webhooks: []config.WebhookConfig{
+		{URL: server.URL + "/proto", Encoding: config.EncodingProtobuf},
+		{URL: server.URL + "/msgpack", Encoding: config.EncodingMsgpack},
+	}}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewWebhooks() error = %v", err)
+	}
+
+	m := model.Match{
+		ID: "-1001:42", Rule: "urgent", Kind: "word", Tags: []string{"oncall"}, Matched: "URGENT",
+		Captures: map[string]string{"price": "10"}, ChatID: -1001, ChatTitle: "Example", MsgID: 42,
+		Text: "ünïcode", Date: time.Unix(1767366245, 500).UTC(), Instance: "eu-1", Stale: true,
+	}
+	if err := w.Deliver(context.Background(), m); err != nil {
+		t.Fatalf("Deliver() error = %v", err)
+	}
+	got := make(map[string]hookRequest)
+	for range 2 {
+		select {
+		case r := <-received:
+			got[r.path] = r
+		case <-time.After(time.Second):
+			t.Fatal("expected both webhooks to be called")
+		}
+	}
+
+	t.Run("Protobuf", func(t *testing.T) {
+		r := got["/proto"]
+		if r.header != "application/x-protobuf" {
+			t.Errorf("expected protobuf content type, got %q", r.header)
+		}
+		fields := make(map[protowire.Number][]any)
+		b := []byte(r.body)
+		for len(b) > 0 {
+			num, typ, n := protowire.ConsumeTag(b)
+			if n < 0 {
+				t.Fatalf("invalid tag: %v", protowire.ParseError(n))
+			}
+			b = b[n:]
+			var v any
+			switch typ {
+			case protowire.VarintType:
+				v, n = protowire.ConsumeVarint(b)
+			case protowire.BytesType:
+				v, n = protowire.ConsumeString(b)
+			default:
+				t.Fatalf("unexpected wire type %d for field %d", typ, num)
+			}
+			if n < 0 {
+				t.Fatalf("invalid field %d: %v", num, protowire.ParseError(n))
+			}
+			b = b[n:]
+			fields[num] = append(fields[num], v)
+		}
+
+		if fields[1][0] != "urgent" || fields[3][0] != "oncall" || fields[11][0] != "ünïcode" || fields[14][0] != "eu-1" || fields[17][0] != "-1001:42" {
+			t.Errorf("unexpected string fields %v", fields)
+		}
+		if chatID := int64(fields[6][0].(uint64)); chatID != -1001 || fields[9][0] != uint64(42) || fields[16][0] != uint64(1) {
+			t.Errorf("unexpected integer fields %v", fields)
+		}
+		if _, ok := fields[8]; ok {
+			t.Error("expected the empty username to be left out")
+		}
+		// Map entries and the timestamp are nested messages
+		entry := protowire.AppendString(protowire.AppendTag(nil, 1, protowire.BytesType), "price")
+		entry = protowire.AppendString(protowire.AppendTag(entry, 2, protowire.BytesType), "10")
+		if fields[5][0] != string(entry) {
+			t.Errorf("unexpected captures entry %q", fields[7][0])
+		}
+		ts := protowire.AppendVarint(protowire.AppendTag(nil, 1, protowire.VarintType), 1767366245)
+		ts = protowire.AppendVarint(protowire.AppendTag(ts, 2, protowire.VarintType), 500)
+		if fields[13][0] != string(ts) {
+			t.Errorf("unexpected date %q", fields[16][0])
+		}
+	})
+
+	t.Run("Msgpack", func(t *testing.T) {
+		r := got["/msgpack"]
+		if r.header != "application/msgpack" {
+			t.Errorf("expected msgpack content type, got %q", r.header)
+		}
+		n, b, err := msgp.ReadMapHeaderBytes([]byte(r.body))
+		if err != nil {
+			t.Fatalf("invalid msgpack body: %v", err)
+		}
+		doc := make(map[string]any, n)
+		for range n {
+			var (
+				key string
+				v   any
+			)
+			if key, b, err = msgp.ReadStringBytes(b); err != nil {
+				t.Fatalf("invalid msgpack key: %v", err)
+			}
+			if key == "date" {
+				v, b, err = msgp.ReadTimeBytes(b)
+			} else {
+				v, b, err = msgp.ReadIntfBytes(b)
+			}
+			if err != nil {
+				t.Fatalf("invalid msgpack value for %s: %v", key, err)
+			}
+			doc[key] = v
+		}
+		if len(b) != 0 {
+			t.Errorf("unexpected %d bytes after the document", len(b))
+		}
+		if doc["id"] != "-1001:42" || doc["rule"] != "urgent" || doc["chat_id"] != int64(-1001) || doc["msg_id"] != int64(42) || doc["text"] != "ünïcode" || doc["instance"] != "eu-1" || doc["stale"] != true {
+			t.Errorf("unexpected document %v", doc)
+		}
+		if date, ok := doc["date"].(time.Time); !ok || !date.Equal(m.Date) {
+			t.Errorf("expected the date as a timestamp, got %v", doc["date"])
+		}
+		if _, ok := doc["username"]; ok {
+			t.Error("expected the empty username to be left out, as in JSON")
+		}
+		// The same keys as the JSON document
+		body, _ := json.Marshal(m)
+		var want map[string]any
+		_ = json.Unmarshal(body, &want)
+		if len(doc) != len(want) {
+			t.Errorf("expected %d fields as in JSON, got %d", len(want), len(doc))
+		}
+	})
+}
@@ -0,0 +1,70 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+// Print alerts as plain text, for running without any chat to alert
+type WriterNotifier struct {
+	mux     sync.Mutex
+	w       io.Writer
+	profile string
+}
+
+// Create new WriterNotifier writing to w in the given formatting profile
+func NewWriter(w io.Writer, profile string) *WriterNotifier {
+	return &WriterNotifier{w: w, profile: profile}
+}
+
+// Write message without markup, followed by a blank line. Under the
+// raw-json profile matches are written as one JSON document per line instead.
+func (n *WriterNotifier) Send(ctx context.Context, message string) error {
+	n.mux.Lock()
+	defer n.mux.Unlock()
+	if m := matchFrom(ctx); m != nil && n.profile == config.ProfileRawJSON {
+		line, err := json.Marshal(m)
+		if err != nil {
+			return fmt.Errorf("failed to marshal notification: %w", err)
+		}
+		if _, err := fmt.Fprintf(n.w, "%s\n", line); err != nil {
+			return fmt.Errorf("failed to write notification: %w", err)
+		}
+		return nil
+	}
+	if _, err := fmt.Fprintf(n.w, "%s\n\n", plainText(formatAlert(ctx, n.profile, message))); err != nil {
+		return fmt.Errorf("failed to write notification: %w", err)
+	}
+	return nil
+}
+
+// Drop alerts, for setups that only deliver matches to webhooks
+type Discard struct{}
+
+// Do nothing
+func (Discard) Send(ctx context.Context, message string) error {
+	return nil
+}
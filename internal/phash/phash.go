@@ -0,0 +1,128 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package phash computes DCT-based perceptual hashes of images.
+// Visually similar images, even after resizing or recompression,
+// produce hashes that differ in only a few bits.
+package phash
+
+import (
+	"image"
+	"math"
+	"math/bits"
+	"sort"
+)
+
+const (
+	// Images are reduced to this many pixels per side before the DCT
+	sampleSize = 32
+	// Low frequencies kept per side, giving a 64-bit hash
+	hashSize = 8
+)
+
+// Return the perceptual hash of img
+func Hash(img image.Image) uint64 {
+	pixels := grayscale(img)
+	freq := dct(pixels)
+
+	// Ignore the DC term, it only carries the average brightness
+	values := make([]float64, 0, hashSize*hashSize-1)
+	for y := range hashSize {
+		for x := range hashSize {
+			if x == 0 && y == 0 {
+				continue
+			}
+			values = append(values, freq[y][x])
+		}
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	median := sorted[len(sorted)/2]
+
+	var hash uint64
+	for i, v := range values {
+		if v > median {
+			hash |= 1 << uint(i)
+		}
+	}
+	return hash
+}
+
+// Return the number of bits that differ between two hashes
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// Downscale img to sampleSize² luma values by averaging each covered area
+func grayscale(img image.Image) [sampleSize][sampleSize]float64 {
+	var out [sampleSize][sampleSize]float64
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w == 0 || h == 0 {
+		return out
+	}
+
+	ycc, _ := img.(*image.YCbCr)
+	for sy := range sampleSize {
+		y0, y1 := b.Min.Y+sy*h/sampleSize, b.Min.Y+max((sy+1)*h/sampleSize, sy*h/sampleSize+1)
+		for sx := range sampleSize {
+			x0, x1 := b.Min.X+sx*w/sampleSize, b.Min.X+max((sx+1)*w/sampleSize, sx*w/sampleSize+1)
+			var sum float64
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					sum += luma(img, ycc, x, y)
+				}
+			}
+			out[sy][sx] = sum / float64((y1-y0)*(x1-x0))
+		}
+	}
+	return out
+}
+
+// Return the brightness of a pixel, reading JPEG luma planes directly
+func luma(img image.Image, ycc *image.YCbCr, x, y int) float64 {
+	if ycc != nil {
+		return float64(ycc.Y[ycc.YOffset(x, y)])
+	}
+	r, g, b, _ := img.At(x, y).RGBA()
+	return (0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)) / 257
+}
+
+// Two-dimensional DCT-II, only computing the low frequencies used by the hash
+func dct(pixels [sampleSize][sampleSize]float64) [hashSize][hashSize]float64 {
+	var cos [hashSize][sampleSize]float64
+	for u := range hashSize {
+		for x := range sampleSize {
+			cos[u][x] = math.Cos(float64(2*x+1) * float64(u) * math.Pi / (2 * sampleSize))
+		}
+	}
+
+	var out [hashSize][hashSize]float64
+	for v := range hashSize {
+		for u := range hashSize {
+			var sum float64
+			for y := range sampleSize {
+				for x := range sampleSize {
+					sum += pixels[y][x] * cos[u][x] * cos[v][y]
+				}
+			}
+			out[v][u] = sum
+		}
+	}
+	return out
+}
@@ -0,0 +1,80 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package phash
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// Draw a diagonal gradient with a bright square in one corner
+func pattern(w, h int, flip bool) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			px := x
+			if flip {
+				px = w - 1 - x
+			}
+			v := uint8((px*255/w + y*255/h) / 2)
+			if px < w/3 && y < h/3 {
+				v = 255
+			}
+			img.Set(x, y, color.RGBA{v, v / 2, 255 - v, 255})
+		}
+	}
+	return img
+}
+
+func TestHash_Similar(t *testing.T) {
+	original := Hash(pattern(640, 480, false))
+	resized := Hash(pattern(160, 120, false))
+	if d := Distance(original, resized); d > 4 {
+		t.Errorf("expected resized copy to be similar, distance %d", d)
+	}
+
+	flipped := Hash(pattern(640, 480, true))
+	if d := Distance(original, flipped); d < 16 {
+		t.Errorf("expected mirrored image to differ, distance %d", d)
+	}
+}
+
+func TestHash_YCbCr(t *testing.T) {
+	src := pattern(64, 64, false)
+	ycc := image.NewYCbCr(src.Bounds(), image.YCbCrSubsampleRatio444)
+	for y := range 64 {
+		for x := range 64 {
+			c := src.RGBAAt(x, y)
+			yy, cb, cr := color.RGBToYCbCr(c.R, c.G, c.B)
+			ycc.Y[ycc.YOffset(x, y)] = yy
+			ycc.Cb[ycc.COffset(x, y)] = cb
+			ycc.Cr[ycc.COffset(x, y)] = cr
+		}
+	}
+	if d := Distance(Hash(src), Hash(ycc)); d > 6 {
+		t.Errorf("expected luma fast path to agree with RGB, distance %d", d)
+	}
+}
+
+func TestDistance(t *testing.T) {
+	if d := Distance(0b1011, 0b0001); d != 2 {
+		t.Errorf("Distance() = %d, want 2", d)
+	}
+}
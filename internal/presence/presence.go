@@ -0,0 +1,150 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package presence keeps a timeline of when tracked users go online and
+// offline, deciding which changes are worth an alert.
+package presence
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/h3nc4/TelegramScout/internal/atomicfile"
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+// A user going online or offline
+type Event struct {
+	Time   time.Time `json:"time"`
+	Online bool      `json:"online"`
+}
+
+// Record status changes of the tracked users, saving them to a file on every change
+type Tracker struct {
+	users      []int64
+	history    int
+	alertEvery time.Duration
+	path       string
+
+	mux       sync.Mutex
+	timelines map[int64][]Event // Oldest first
+	alerted   map[int64]time.Time
+}
+
+// Load the timelines saved at path, a missing file holds none. An empty path keeps them in memory only.
+func Load(cfg config.PresenceConfig, path string) (*Tracker, error) {
+	t := &Tracker{
+		users:      cfg.Users,
+		history:    cfg.History,
+		alertEvery: cfg.AlertEvery,
+		path:       path,
+		timelines:  make(map[int64][]Event),
+		alerted:    make(map[int64]time.Time),
+	}
+	if path == "" {
+		return t, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return t, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &t.timelines); err != nil {
+		return nil, fmt.Errorf("invalid presence state %s: %w", path, err)
+	}
+	// Forget users no longer tracked
+	for id := range t.timelines {
+		if !slices.Contains(t.users, id) {
+			delete(t.timelines, id)
+		}
+	}
+	return t, nil
+}
+
+// Report whether the user's status is tracked
+func (t *Tracker) Tracks(userID int64) bool {
+	return slices.Contains(t.users, userID)
+}
+
+// Record a status update, returning whether it changed the user's status and
+// deserves an alert. Repeated updates with the same status are ignored.
+func (t *Tracker) Record(userID int64, online bool, at time.Time) (changed, alert bool, err error) {
+	if !t.Tracks(userID) {
+		return false, false, nil
+	}
+
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	timeline := t.timelines[userID]
+	if n := len(timeline); n > 0 && timeline[n-1].Online == online {
+		return false, false, nil
+	}
+	timeline = append(timeline, Event{Time: at, Online: online})
+	if len(timeline) > t.history {
+		timeline = slices.Delete(timeline, 0, len(timeline)-t.history)
+	}
+	t.timelines[userID] = timeline
+
+	// Online transitions are the interesting ones, at most one per user per interval
+	if online && t.alertEvery > 0 && time.Since(t.alerted[userID]) >= t.alertEvery {
+		t.alerted[userID] = time.Now()
+		alert = true
+	}
+	return true, alert, t.save()
+}
+
+// Return the status changes of a user, oldest first
+func (t *Tracker) Timeline(userID int64) []Event {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	return slices.Clone(t.timelines[userID])
+}
+
+// Return the latest status change of every tracked user seen so far
+func (t *Tracker) Latest() map[int64]Event {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	latest := make(map[int64]Event, len(t.timelines))
+	for id, timeline := range t.timelines {
+		if len(timeline) > 0 {
+			latest[id] = timeline[len(timeline)-1]
+		}
+	}
+	return latest
+}
+
+// Write the timelines to the state file, with mux held so writes land in order
+func (t *Tracker) save() error {
+	if t.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(t.timelines)
+	if err != nil {
+		return err
+	}
+	return atomicfile.Write(t.path, data)
+}
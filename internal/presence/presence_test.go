@@ -0,0 +1,85 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package presence
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+func TestTracker_Record(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "presence.json")
+	cfg := config.PresenceConfig{Users: []int64{1, 2}, History: 3, AlertEvery: time.Hour}
+	tr, err := Load(cfg, path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	at := time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+
+	changed, alert, err := tr.Record(1, true, at)
+	if err != nil || !changed || !alert {
+		t.Fatalf("first online: changed=%v alert=%v err=%v", changed, alert, err)
+	}
+	if changed, _, _ := tr.Record(1, true, at.Add(time.Minute)); changed {
+		t.Error("repeated status should be ignored")
+	}
+	if changed, alert, _ := tr.Record(1, false, at.Add(2*time.Minute)); !changed || alert {
+		t.Errorf("going offline: changed=%v alert=%v, want true false", changed, alert)
+	}
+	if _, alert, _ := tr.Record(1, true, at.Add(3*time.Minute)); alert {
+		t.Error("second online within alert_every should not alert")
+	}
+	if changed, _, _ := tr.Record(3, true, at); changed {
+		t.Error("untracked user should be ignored")
+	}
+
+	tr.Record(1, false, at.Add(4*time.Minute))
+	timeline := tr.Timeline(1)
+	if len(timeline) != 3 {
+		t.Fatalf("expected history trimmed to 3, got %d", len(timeline))
+	}
+	if !timeline[0].Time.Equal(at.Add(2*time.Minute)) || timeline[2].Online {
+		t.Errorf("unexpected timeline %+v", timeline)
+	}
+
+	// Timelines survive a restart, users dropped from the config are forgotten
+	tr.Record(2, true, at)
+	cfg.Users = []int64{1}
+	reloaded, err := Load(cfg, path)
+	if err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	latest := reloaded.Latest()
+	if len(latest) != 1 || latest[1].Online || !latest[1].Time.Equal(at.Add(4*time.Minute)) {
+		t.Errorf("unexpected latest after reload %+v", latest)
+	}
+}
+
+func TestTracker_NoAlerts(t *testing.T) {
+	tr, err := Load(config.PresenceConfig{Users: []int64{1}, History: 10}, "")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if changed, alert, _ := tr.Record(1, true, time.Now()); !changed || alert {
+		t.Errorf("changed=%v alert=%v, want a timeline entry without alert", changed, alert)
+	}
+}
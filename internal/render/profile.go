@@ -0,0 +1,121 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Characters in one Telegram message
+const MaxLength = 4096
+
+// Reshape the standard alert for m to a destination's formatting profile
+func Profile(profile string, m model.Match, standard string) string {
+	switch profile {
+	case config.ProfileCompact:
+		return compactAlert(&m)
+	case config.ProfileVerbose:
+		return verboseAlert(&m, standard)
+	case config.ProfileRawJSON:
+		return rawJSONAlert(m)
+	}
+	return standard
+}
+
+// One line with the rule, chat and link
+func compactAlert(m *model.Match) string {
+	line := fmt.Sprintf("🚨 <b>%s</b> in %s", html.EscapeString(m.Rule), html.EscapeString(m.ChatTitle))
+	if m.Link != "" {
+		line += fmt.Sprintf(" — <a href=\"%s\">link</a>", html.EscapeString(m.Link))
+	}
+	return line
+}
+
+// The standard alert followed by IDs, sender, date and the text past the preview
+func verboseAlert(m *model.Match, message string) string {
+	var b strings.Builder
+	b.WriteString(message)
+	b.WriteString("\n\n")
+	if m.Matched != "" {
+		fmt.Fprintf(&b, "🔎 <b>Matched:</b> <code>%s</code>\n", html.EscapeString(m.Matched))
+	}
+	fmt.Fprintf(&b, "🆔 <b>Chat:</b> <code>%d</code>, <b>message:</b> <code>%d</code>\n", m.ChatID, m.MsgID)
+	if m.SenderID != 0 {
+		fmt.Fprintf(&b, "👤 <b>Sender:</b> <code>%d</code>\n", m.SenderID)
+	}
+	fmt.Fprintf(&b, "📅 <b>Date:</b> %s", m.Date.UTC().Format(time.RFC3339))
+	if utf8.RuneCountInString(m.Text) > previewLength {
+		// Leave room for the markup around it within one Telegram message
+		budget := MaxLength - len([]rune(b.String())) - 64
+		if budget > 0 {
+			fmt.Fprintf(&b, "\n\n📝 <b>Full text:</b>\n<i>%s</i>", clipEscaped(m.Text, budget))
+		}
+	}
+	return b.String()
+}
+
+// The match as indented JSON, shortening the text to fit one Telegram message
+func rawJSONAlert(m model.Match) string {
+	for {
+		doc, err := json.MarshalIndent(m, "", "  ")
+		if err != nil {
+			return fmt.Sprintf("<pre>%s</pre>", html.EscapeString(err.Error()))
+		}
+		text := fmt.Sprintf("<pre>%s</pre>", html.EscapeString(string(doc)))
+		over := len([]rune(text)) - MaxLength
+		if over <= 0 || m.Text == "" {
+			return text
+		}
+		if keep := len([]rune(m.Text)) - over - 16; keep > 0 {
+			m.Text = clip(m.Text, keep)
+		} else {
+			m.Text = ""
+		}
+	}
+}
+
+// Escape s, cut so the escaped text stays within n runes
+func clipEscaped(s string, n int) string {
+	keep := n
+	for {
+		escaped := html.EscapeString(clip(s, keep))
+		over := len([]rune(escaped)) - n
+		if over <= 0 || keep == 0 {
+			return escaped
+		}
+		keep = max(keep-over, 0)
+	}
+}
+
+// Cut s to at most n runes, marking the cut
+func clip(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n]) + "…"
+}
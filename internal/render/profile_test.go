@@ -0,0 +1,85 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package render
+
+import (
+	"encoding/json"
+	"html"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+func TestProfile(t *testing.T) {
+	m := model.Match{
+		ID:        "-1005:7",
+		Rule:      "urgent",
+		Matched:   "urgent",
+		ChatID:    -1005,
+		ChatTitle: "Ops & Alerts",
+		MsgID:     7,
+		SenderID:  42,
+		Text:      strings.Repeat("word ", 100),
+		Link:      "https://t.me/c/5/7",
+		Date:      time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+	const standard = "🚨 <b>Match:</b> urgent"
+
+	for _, profile := range []string{"", config.ProfileStandard} {
+		if got := Profile(profile, m, standard); got != standard {
+			t.Errorf("expected the message unchanged under %q, got %q", profile, got)
+		}
+	}
+
+	compact := Profile(config.ProfileCompact, m, standard)
+	if compact != `🚨 <b>urgent</b> in Ops &amp; Alerts — <a href="https://t.me/c/5/7">link</a>` {
+		t.Errorf("unexpected compact alert %q", compact)
+	}
+
+	verbose := Profile(config.ProfileVerbose, m, standard)
+	for _, want := range []string{standard, "<code>-1005</code>", "<code>7</code>", "<code>42</code>", "2026-05-01T12:00:00Z", "Full text:", m.Text} {
+		if !strings.Contains(verbose, want) {
+			t.Errorf("expected verbose alert to contain %q, got %q", want, verbose)
+		}
+	}
+
+	raw := Profile(config.ProfileRawJSON, m, standard)
+	if !strings.HasPrefix(raw, "<pre>") || !strings.HasSuffix(raw, "</pre>") {
+		t.Fatalf("expected a preformatted JSON block, got %q", raw)
+	}
+	doc := html.UnescapeString(strings.TrimSuffix(strings.TrimPrefix(raw, "<pre>"), "</pre>"))
+	var decoded model.Match
+	if err := json.Unmarshal([]byte(doc), &decoded); err != nil {
+		t.Fatalf("expected the block to hold the match as JSON: %v", err)
+	}
+	if decoded.ID != m.ID || decoded.Text != m.Text || decoded.ChatTitle != m.ChatTitle {
+		t.Errorf("unexpected decoded match %+v", decoded)
+	}
+
+	// Long texts are shortened to fit one Telegram message
+	m.Text = strings.Repeat("<&>", 3000)
+	for _, profile := range []string{config.ProfileVerbose, config.ProfileRawJSON} {
+		if n := len([]rune(Profile(profile, m, standard))); n > MaxLength {
+			t.Errorf("expected %s alert within %d characters, got %d", profile, MaxLength, n)
+		}
+	}
+}
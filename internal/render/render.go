@@ -0,0 +1,130 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package render turns matches into the HTML text of alerts and digest
+// lines, and reshapes them for the formatting profile of each destination.
+package render
+
+import (
+	"fmt"
+	"html"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Characters of message text shown by the standard alert
+const previewLength = 200
+
+// Format the standard alert for m, followed by preformatted extra lines
+func Alert(m model.Match, extra []string) string {
+	var labels string
+	if len(m.Tags) > 0 {
+		labels = fmt.Sprintf("🏷 <b>Tags:</b> %s\n", html.EscapeString(strings.Join(m.Tags, ", ")))
+	}
+	if len(m.Also) > 0 {
+		labels += fmt.Sprintf("➕ <b>Also matched:</b> %s\n", html.EscapeString(strings.Join(m.Also, ", ")))
+	}
+	if len(m.Captures) > 0 {
+		labels += captureLine(m.Captures) + "\n"
+	}
+	var link string
+	if m.Link != "" {
+		link = fmt.Sprintf("🔗 <a href=\"%s\">Link to Message</a>\n", html.EscapeString(m.Link))
+	}
+	if m.AppLink != "" {
+		link += fmt.Sprintf("📱 <a href=\"%s\">Open in App</a>\n", html.EscapeString(m.AppLink))
+	}
+	if line := instanceLine(m.Instance, m.Labels); line != "" {
+		link += line + "\n"
+	}
+	if m.Stale {
+		link += staleLine(m.Date) + "\n"
+	}
+	for _, line := range extra {
+		link += line + "\n"
+	}
+	return fmt.Sprintf(
+		"🚨 <b>Match:</b> %s\n"+
+			"%s"+
+			"📢 <b>Chat:</b> %s\n"+
+			"🕒 <b>Time:</b> %s\n"+
+			"%s\n"+
+			"<i>%s</i>",
+		html.EscapeString(m.Rule),
+		labels,
+		html.EscapeString(m.ChatTitle),
+		m.Date.Format(time.Kitchen),
+		link,
+		html.EscapeString(truncate(m.Text, previewLength)),
+	)
+}
+
+// Summarize m in one digest line
+func DigestLine(m model.Match) string {
+	line := fmt.Sprintf("• <b>%s</b> in %s at %s", html.EscapeString(m.Rule), html.EscapeString(m.ChatTitle), m.Date.Format(time.Kitchen))
+	if m.Link != "" {
+		line += fmt.Sprintf(" — <a href=\"%s\">link</a>", html.EscapeString(m.Link))
+	}
+	return line
+}
+
+// Format captured values for the alert, by group name
+func captureLine(groups map[string]string) string {
+	var parts []string
+	for _, name := range slices.Sorted(maps.Keys(groups)) {
+		parts = append(parts, fmt.Sprintf("%s=<code>%s</code>", html.EscapeString(name), html.EscapeString(groups[name])))
+	}
+	return "🧩 <b>Captured:</b> " + strings.Join(parts, ", ")
+}
+
+// Format the line naming the instance that raised an alert, or nothing when unnamed
+func instanceLine(name string, labels map[string]string) string {
+	if name == "" && len(labels) == 0 {
+		return ""
+	}
+	var parts []string
+	for _, label := range slices.Sorted(maps.Keys(labels)) {
+		parts = append(parts, fmt.Sprintf("%s=%s", label, html.EscapeString(labels[label])))
+	}
+	line := "🖥 <b>Instance:</b> " + html.EscapeString(name)
+	if len(parts) > 0 {
+		if name != "" {
+			line += " "
+		}
+		line += "(" + strings.Join(parts, ", ") + ")"
+	}
+	return line
+}
+
+// Label an alert for a message too old to act on, as kept by filters.stale
+func staleLine(date time.Time) string {
+	age := time.Since(date).Round(time.Minute)
+	return fmt.Sprintf("🕰 <b>Stale:</b> sent %s ago", strings.TrimSuffix(age.String(), "0s"))
+}
+
+// Cut s to at most max runes, never inside a character, before it is escaped
+func truncate(s string, max int) string {
+	if r := []rune(s); len(r) > max {
+		return string(r[:max]) + "..."
+	}
+	return s
+}
@@ -0,0 +1,116 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package render
+
+import (
+	"html"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+func TestAlert(t *testing.T) {
+	m := model.Match{
+		Rule:      "re:price",
+		Tags:      []string{"deals"},
+		Captures:  map[string]string{"price": "120"},
+		ChatTitle: "Market",
+		Text:      strings.Repeat("a", 300),
+		Link:      "https://t.me/c/5/7",
+		AppLink:   "tg://privatepost?channel=5&post=7",
+		Date:      time.Now().Add(-2 * time.Hour),
+		Stale:     true,
+		Instance:  "eu-1",
+		Labels:    map[string]string{"region": "eu"},
+	}
+	text := Alert(m, []string{"📎 <b>File:</b> app.apk"})
+	for _, want := range []string{
+		"🚨 <b>Match:</b> re:price\n",
+		"🏷 <b>Tags:</b> deals\n",
+		"🧩 <b>Captured:</b> price=<code>120</code>\n",
+		"📢 <b>Chat:</b> Market\n",
+		`🔗 <a href="https://t.me/c/5/7">Link to Message</a>`,
+		"📱 <a href=",
+		"🖥 <b>Instance:</b> eu-1 (region=eu)\n",
+		"🕰 <b>Stale:</b> sent 2h0m ago\n",
+		"📎 <b>File:</b> app.apk\n",
+		"<i>" + strings.Repeat("a", previewLength) + "...</i>",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected alert to contain %q, got %q", want, text)
+		}
+	}
+
+	plain := Alert(model.Match{Rule: "urgent", ChatTitle: "Ops", Text: "urgent"}, nil)
+	for _, unwanted := range []string{"Tags:", "Captured:", "Link to Message", "Instance:", "Stale:"} {
+		if strings.Contains(plain, unwanted) {
+			t.Errorf("expected no %q line, got %q", unwanted, plain)
+		}
+	}
+}
+
+func TestAlertEscaping(t *testing.T) {
+	m := model.Match{
+		Rule:      "price <100",
+		Tags:      []string{"r&d"},
+		ChatTitle: "Deals <&> Steals",
+		Text:      "цена <100 & " + strings.Repeat("ж", 300),
+		Link:      "https://t.me/c/5/7?single&thread=1",
+		Date:      time.Now(),
+	}
+	text := Alert(m, nil)
+	for _, want := range []string{
+		"<b>Match:</b> price &lt;100\n",
+		"<b>Tags:</b> r&amp;d\n",
+		"<b>Chat:</b> Deals &lt;&amp;&gt; Steals\n",
+		`href="https://t.me/c/5/7?single&amp;thread=1"`,
+		"<i>цена &lt;100 &amp; жжж",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected alert to contain %q, got %q", want, text)
+		}
+	}
+	if !utf8.ValidString(text) {
+		t.Error("expected the preview cut between characters")
+	}
+	preview := text[strings.Index(text, "<i>")+len("<i>") : strings.LastIndex(text, "...</i>")]
+	if n := utf8.RuneCountInString(html.UnescapeString(preview)); n != previewLength {
+		t.Errorf("expected a %d character preview, got %d", previewLength, n)
+	}
+
+	line := DigestLine(m)
+	if !strings.Contains(line, "<b>price &lt;100</b> in Deals &lt;&amp;&gt; Steals") || !strings.Contains(line, "single&amp;thread") {
+		t.Errorf("unexpected digest line %q", line)
+	}
+}
+
+func TestDigestLine(t *testing.T) {
+	date := time.Date(2026, 5, 1, 15, 4, 0, 0, time.UTC)
+	m := model.Match{Rule: "urgent", ChatTitle: "Ops", Date: date, Link: "https://t.me/c/5/7"}
+	if got := DigestLine(m); got != `• <b>urgent</b> in Ops at 3:04PM — <a href="https://t.me/c/5/7">link</a>` {
+		t.Errorf("unexpected digest line %q", got)
+	}
+	m.Link = ""
+	if got := DigestLine(m); got != "• <b>urgent</b> in Ops at 3:04PM" {
+		t.Errorf("unexpected digest line without a link %q", got)
+	}
+}
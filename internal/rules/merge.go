@@ -0,0 +1,241 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package rules
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+// What merging a pack changed
+type Report struct {
+	Added   []string // Rules new to the config
+	Kept    []string // Rules already present, their own settings left alone
+	Tagged  []string // Rules given tags they lacked
+	Routed  []string // Rules routed to webhooks they were not sent to
+	Unknown []string // Destinations naming no configured webhook, skipped
+}
+
+// Report whether merging changed the config
+func (r Report) Changed() bool {
+	return len(r.Added) > 0 || len(r.Tagged) > 0 || len(r.Routed) > 0
+}
+
+// Merge the pack into a config file, returning the new contents.
+// Rules are matched by the name alerts report, present ones keep their
+// settings while gaining the pack's tags and destinations.
+func Merge(data []byte, pack *Pack) ([]byte, Report, error) {
+	var rep Report
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, rep, err
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, rep, errors.New("config is not a mapping")
+	}
+	var src source
+	if err := root.Decode(&src); err != nil {
+		return nil, rep, err
+	}
+
+	for _, rule := range pack.Rules {
+		name := rule.Name()
+		added, err := addRule(root, &src, rule)
+		if err != nil {
+			return nil, rep, err
+		}
+		if added {
+			rep.Added = append(rep.Added, name)
+		} else {
+			rep.Kept = append(rep.Kept, name)
+		}
+
+		if tagRule(root, &src, name, rule.Tags) {
+			rep.Tagged = append(rep.Tagged, name)
+		}
+
+		routed := false
+		for _, dest := range rule.Destinations {
+			switch ok, known := routeRule(root, &src, name, dest); {
+			case !known:
+				if !slices.Contains(rep.Unknown, dest) {
+					rep.Unknown = append(rep.Unknown, dest)
+				}
+			case ok:
+				routed = true
+			}
+		}
+		if routed {
+			rep.Routed = append(rep.Routed, name)
+		}
+	}
+
+	if !rep.Changed() {
+		return data, rep, nil
+	}
+	out, err := encode(&doc)
+	return out, rep, err
+}
+
+// Append the rule to its list unless one with the same name is there
+func addRule(root *yaml.Node, src *source, rule Rule) (bool, error) {
+	name := rule.Name()
+	switch {
+	case rule.Image != nil:
+		if slices.ContainsFunc(src.Images, func(r config.ImageRule) bool { return "image:"+imageLabel(r) == name }) {
+			return false, nil
+		}
+		src.Images = append(src.Images, config.ImageRule(*rule.Image))
+		return true, appendItem(sequence(root, "images"), rule.Image, rule.Comment)
+	case rule.File != nil:
+		if slices.ContainsFunc(src.Files, func(r config.FileRule) bool { return "file:"+r.Label() == name }) {
+			return false, nil
+		}
+		src.Files = append(src.Files, config.FileRule(*rule.File))
+		return true, appendItem(sequence(root, "files"), rule.File, rule.Comment)
+	}
+	if slices.Contains(src.Keywords, rule.Keyword) {
+		return false, nil
+	}
+	src.Keywords = append(src.Keywords, rule.Keyword)
+	seq := sequence(root, "keywords")
+	kw := scalar(rule.Keyword)
+	kw.Style = yaml.DoubleQuotedStyle
+	// Keep the comment on the keyword's own line, as the examples do
+	kw.LineComment = commentMarker(strings.ReplaceAll(rule.Comment, "\n", " "))
+	seq.Content = append(seq.Content, kw)
+	return true, nil
+}
+
+// Add the labels the rule does not carry yet under its exact name
+func tagRule(root *yaml.Node, src *source, name string, tags []string) bool {
+	have := tagsFor(src.Tags, name)
+	var missing []string
+	for _, tag := range tags {
+		if !slices.Contains(have, tag) && !slices.Contains(missing, tag) {
+			missing = append(missing, tag)
+		}
+	}
+	if len(missing) == 0 {
+		return false
+	}
+
+	if src.Tags == nil {
+		src.Tags = make(map[string][]string)
+	}
+	src.Tags[name] = append(src.Tags[name], missing...)
+	m := lookup(root, "tags")
+	if m == nil || m.Kind != yaml.MappingNode {
+		m = &yaml.Node{Kind: yaml.MappingNode}
+		set(root, "tags", m)
+	}
+	seq := lookup(m, name)
+	if seq == nil || seq.Kind != yaml.SequenceNode {
+		seq = &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+		set(m, name, seq)
+	}
+	for _, tag := range missing {
+		seq.Content = append(seq.Content, scalar(tag))
+	}
+	return true
+}
+
+// Route the rule to the named webhook, reporting whether that changed
+// anything and whether the webhook exists
+func routeRule(root *yaml.Node, src *source, name, dest string) (changed, known bool) {
+	i := slices.IndexFunc(src.Notifier.Webhooks, func(h config.WebhookConfig) bool { return hookName(h) == dest })
+	if i < 0 {
+		return false, false
+	}
+	hook := &src.Notifier.Webhooks[i]
+	if wants(*hook, name) {
+		return false, true
+	}
+	hook.Rules = append(hook.Rules, name)
+
+	item := lookup(root, "notifier")
+	if item != nil {
+		item = lookup(item, "webhooks")
+	}
+	if item == nil || len(item.Content) <= i {
+		return false, true
+	}
+	seq := lookup(item.Content[i], "rules")
+	if seq == nil {
+		return false, true
+	}
+	seq.Content = append(seq.Content, scalar(name))
+	return true, true
+}
+
+// Return the sequence under key, adding an empty one when absent
+func sequence(m *yaml.Node, key string) *yaml.Node {
+	if seq := lookup(m, key); seq != nil && seq.Kind == yaml.SequenceNode {
+		return seq
+	}
+	seq := &yaml.Node{Kind: yaml.SequenceNode}
+	set(m, key, seq)
+	return seq
+}
+
+// Replace the value under key in a mapping node, appending the key when absent
+func set(m *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content[i+1] = value
+			return
+		}
+	}
+	m.Content = append(m.Content, scalar(key), value)
+}
+
+func appendItem(seq *yaml.Node, v any, comment string) error {
+	var n yaml.Node
+	if err := n.Encode(v); err != nil {
+		return err
+	}
+	n.HeadComment = commentMarker(comment)
+	seq.Content = append(seq.Content, &n)
+	return nil
+}
+
+func scalar(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+// Turn comment text back into YAML comment lines
+func commentMarker(text string) string {
+	if text == "" {
+		return ""
+	}
+	var lines []string
+	for line := range strings.Lines(text) {
+		lines = append(lines, "# "+strings.TrimRight(line, "\n"))
+	}
+	return strings.Join(lines, "\n")
+}
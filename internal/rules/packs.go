@@ -0,0 +1,255 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package rules
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/h3nc4/TelegramScout/internal/atomicfile"
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+// Curated packs shipped with the binary
+//
+//go:embed packs/*.yaml
+var builtin embed.FS
+
+// Lists the packs offered for download, next to them
+const indexFile = "index.yaml"
+
+// Largest index or pack accepted from the network
+const maxDownload = 1 << 20
+
+// Pack names double as file names
+var validName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// A pack available by name
+type Entry struct {
+	Name     string `yaml:"name"`
+	Revision int    `yaml:"revision"`
+	URL      string `yaml:"url"` // Relative to the index
+
+	Comment string `yaml:"-"`
+	Source  string `yaml:"-"` // "builtin", or the downloaded file in use
+}
+
+type index struct {
+	Version int     `yaml:"version"`
+	Packs   []Entry `yaml:"packs"`
+}
+
+// Load the newest copy of the named pack, built in or downloaded to dir
+func Open(name, dir string) (*Pack, string, error) {
+	if !validName.MatchString(name) {
+		return nil, "", fmt.Errorf("invalid pack name %q", name)
+	}
+
+	var pack *Pack
+	source := ""
+	if data, err := builtin.ReadFile("packs/" + name + ".yaml"); err == nil {
+		if pack, err = Read(bytes.NewReader(data)); err != nil {
+			return nil, "", fmt.Errorf("built-in pack %s: %w", name, err)
+		}
+		source = "builtin"
+	}
+
+	path := filepath.Join(dir, name+".yaml")
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		if pack == nil {
+			return nil, "", fmt.Errorf("unknown pack %q", name)
+		}
+		return pack, source, nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() { _ = f.Close() }()
+	downloaded, err := Read(f)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", path, err)
+	}
+	// A newer binary may ship a newer copy than the one downloaded earlier
+	if pack == nil || downloaded.Revision > pack.Revision {
+		return downloaded, path, nil
+	}
+	return pack, source, nil
+}
+
+// List the packs available by name, built in or downloaded to dir
+func Catalog(dir string) ([]Entry, error) {
+	var names []string
+	builtins, err := builtin.ReadDir("packs")
+	if err != nil {
+		return nil, err
+	}
+	downloaded, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	for _, e := range slices.Concat(builtins, downloaded) {
+		name, ok := strings.CutSuffix(e.Name(), ".yaml")
+		if ok && e.Name() != indexFile && validName.MatchString(name) && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	entries := make([]Entry, 0, len(names))
+	for _, name := range names {
+		pack, source, err := Open(name, dir)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, Entry{Name: name, Revision: pack.Revision, Comment: pack.Comment, Source: source})
+	}
+	return entries, nil
+}
+
+// Add the rules of a pack to the config in memory, with their tags and
+// destinations, and return how many were not configured already
+func Apply(cfg *config.Config, pack *Pack) int {
+	added := 0
+	m := &cfg.Monitoring
+	for _, rule := range pack.Rules {
+		name := rule.Name()
+		switch {
+		case rule.Image != nil:
+			if !slices.ContainsFunc(m.Images, func(r config.ImageRule) bool { return "image:"+imageLabel(r) == name }) {
+				m.Images = append(m.Images, config.ImageRule(*rule.Image))
+				added++
+			}
+		case rule.File != nil:
+			if !slices.ContainsFunc(m.Files, func(r config.FileRule) bool { return "file:"+r.Label() == name }) {
+				m.Files = append(m.Files, config.FileRule(*rule.File))
+				added++
+			}
+		case !slices.Contains(m.Keywords, rule.Keyword):
+			m.Keywords = append(m.Keywords, rule.Keyword)
+			added++
+		}
+
+		have := tagsFor(m.Tags, name)
+		for _, tag := range rule.Tags {
+			if !slices.Contains(have, tag) {
+				if m.Tags == nil {
+					m.Tags = make(map[string][]string)
+				}
+				m.Tags[name] = append(m.Tags[name], tag)
+				have = append(have, tag)
+			}
+		}
+
+		for _, dest := range rule.Destinations {
+			i := slices.IndexFunc(cfg.Notifier.Webhooks, func(h config.WebhookConfig) bool { return hookName(h) == dest })
+			if i >= 0 && !wants(cfg.Notifier.Webhooks[i], name) {
+				cfg.Notifier.Webhooks[i].Rules = append(cfg.Notifier.Webhooks[i].Rules, name)
+			}
+		}
+	}
+	return added
+}
+
+// Download the packs of the index newer than the copies available, into dir
+func Update(ctx context.Context, client *http.Client, indexURL, dir string) ([]Entry, error) {
+	base, err := url.Parse(indexURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid index URL: %w", err)
+	}
+	data, err := fetch(ctx, client, base.String())
+	if err != nil {
+		return nil, err
+	}
+	var idx index
+	if err := yaml.Unmarshal(data, &idx); err != nil {
+		return nil, fmt.Errorf("invalid index: %w", err)
+	}
+	if idx.Version > Version {
+		return nil, fmt.Errorf("index version %d is newer than the supported %d, upgrade telegram-scout", idx.Version, Version)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	var updated []Entry
+	var errs []error
+	for _, e := range idx.Packs {
+		if !validName.MatchString(e.Name) {
+			errs = append(errs, fmt.Errorf("invalid pack name %q", e.Name))
+			continue
+		}
+		if current, _, err := Open(e.Name, dir); err == nil && current.Revision >= e.Revision {
+			continue
+		}
+
+		ref, err := base.Parse(e.URL)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", e.Name, err))
+			continue
+		}
+		data, err := fetch(ctx, client, ref.String())
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", e.Name, err))
+			continue
+		}
+		pack, err := Read(bytes.NewReader(data))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", e.Name, err))
+			continue
+		}
+		if err := atomicfile.Write(filepath.Join(dir, e.Name+".yaml"), data); err != nil {
+			return updated, err
+		}
+		e.Revision = pack.Revision
+		e.Comment = pack.Comment
+		updated = append(updated, e)
+	}
+	return updated, errors.Join(errs...)
+}
+
+func fetch(ctx context.Context, client *http.Client, u string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned status: %d", u, resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxDownload))
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package rules
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+func TestBuiltinPacks(t *testing.T) {
+	data, err := builtin.ReadFile("packs/" + indexFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var idx index
+	if err := yaml.Unmarshal(data, &idx); err != nil {
+		t.Fatalf("invalid index: %v", err)
+	}
+
+	entries, err := Catalog(t.TempDir())
+	if err != nil {
+		t.Fatalf("Catalog failed: %v", err)
+	}
+	if len(entries) != len(idx.Packs) {
+		t.Fatalf("expected every built-in pack in the index, got %d packs and %d entries", len(entries), len(idx.Packs))
+	}
+	for i, e := range entries {
+		if e.Name != idx.Packs[i].Name || e.Revision != idx.Packs[i].Revision || e.Source != "builtin" {
+			t.Errorf("index entry %+v does not match built-in pack %+v", idx.Packs[i], e)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{
+			Keywords: []string{`re:(?i)\brtx\s?50[6-9]0(\s?ti)?\b`},
+			Tags:     map[string][]string{"re:*": {"regex"}},
+		},
+		Notifier: config.NotifierConfig{Webhooks: []config.WebhookConfig{{Name: "deals", Rules: []string{"urgent"}}}},
+	}
+	pack, _, err := Open("gpu-deals", t.TempDir())
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	pack.Rules[0].Destinations = []string{"deals"}
+
+	if added := Apply(cfg, pack); added != len(pack.Rules)-1 {
+		t.Errorf("expected the configured keyword kept once, added %d", added)
+	}
+	first := pack.Rules[0].Keyword
+	if n := len(slices.DeleteFunc(slices.Clone(cfg.Monitoring.Keywords), func(k string) bool { return k != first })); n != 1 {
+		t.Errorf("expected %q once, got %d", first, n)
+	}
+	if got := tagsFor(cfg.Monitoring.Tags, first); !reflect.DeepEqual(got, []string{"gpu-deals", "regex"}) {
+		t.Errorf("unexpected tags %v", got)
+	}
+	if got := cfg.Notifier.Webhooks[0].Rules; !reflect.DeepEqual(got, []string{"urgent", first}) {
+		t.Errorf("expected the rule routed to the webhook, got %v", got)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	const pack = "version: 1\nrevision: 2\ncomment: Newer\nrules:\n  - keyword: rtx 6090\n"
+	mux := http.NewServeMux()
+	mux.HandleFunc("/packs/index.yaml", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("version: 1\npacks:\n  - {name: gpu-deals, revision: 2, url: gpu-deals.yaml}\n  - {name: data-leaks, revision: 1, url: data-leaks.yaml}\n  - {name: missing, revision: 1, url: missing.yaml}\n"))
+	})
+	mux.HandleFunc("/packs/gpu-deals.yaml", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(pack))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	dir := filepath.Join(t.TempDir(), "packs")
+	updated, err := Update(context.Background(), server.Client(), server.URL+"/packs/index.yaml", dir)
+	if err == nil {
+		t.Error("expected the missing pack reported")
+	}
+	if len(updated) != 1 || updated[0].Name != "gpu-deals" || updated[0].Revision != 2 {
+		t.Fatalf("expected only gpu-deals updated, got %+v", updated)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "gpu-deals.yaml")); err != nil || string(data) != pack {
+		t.Fatalf("expected the pack saved, got %q, %v", data, err)
+	}
+
+	got, source, err := Open("gpu-deals", dir)
+	if err != nil || got.Revision != 2 || source != filepath.Join(dir, "gpu-deals.yaml") {
+		t.Errorf("expected the downloaded copy preferred, got %+v from %s, %v", got, source, err)
+	}
+	if _, source, _ := Open("data-leaks", dir); source != "builtin" {
+		t.Errorf("expected the built-in copy when nothing newer was downloaded, got %s", source)
+	}
+	if _, _, err := Open("../config", dir); err == nil {
+		t.Error("expected a path-like pack name rejected")
+	}
+}
@@ -0,0 +1,303 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package rules moves monitoring rules between deployments in a portable,
+// versioned format, merging them into an existing config file.
+package rules
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/dlclark/regexp2"
+	"gopkg.in/yaml.v3"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+// Format version written by Export, packs of newer versions are refused
+const Version = 1
+
+// A shareable set of rules
+type Pack struct {
+	Version  int    `yaml:"version"`
+	Revision int    `yaml:"revision,omitempty"` // Bumped by the author on every change, newer copies replace older ones
+	Comment  string `yaml:"comment,omitempty"`
+	Rules    []Rule `yaml:"rules"`
+}
+
+// One rule with what routes and labels its alerts, exactly one of Keyword, Image and File is set
+type Rule struct {
+	Keyword      string   `yaml:"keyword,omitempty"`
+	Image        *Image   `yaml:"image,omitempty"`
+	File         *File    `yaml:"file,omitempty"`
+	Comment      string   `yaml:"comment,omitempty"`
+	Tags         []string `yaml:"tags,omitempty"`
+	Destinations []string `yaml:"destinations,omitempty"` // Webhooks by name, or URL when unnamed
+}
+
+// Image rule as shared, the reference path must exist on the importing side
+type Image struct {
+	Name        string `yaml:"name,omitempty"`
+	Path        string `yaml:"path"`
+	MaxDistance int    `yaml:"max_distance,omitempty"`
+}
+
+// Document rule as shared
+type File struct {
+	Name     string `yaml:"name,omitempty"`
+	Filename string `yaml:"filename,omitempty"`
+	MimeType string `yaml:"mime_type,omitempty"`
+	MinSize  int64  `yaml:"min_size,omitempty"`
+	MaxSize  int64  `yaml:"max_size,omitempty"`
+}
+
+// Return the rule's name as alerts report it
+func (r Rule) Name() string {
+	switch {
+	case r.Image != nil:
+		return "image:" + imageLabel(config.ImageRule(*r.Image))
+	case r.File != nil:
+		return "file:" + config.FileRule(*r.File).Label()
+	}
+	return r.Keyword
+}
+
+// Name an image rule, unnamed ones after their reference file
+func imageLabel(r config.ImageRule) string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return strings.TrimSuffix(filepath.Base(r.Path), filepath.Ext(r.Path))
+}
+
+// The parts of the config file rules are exported from and merged into
+type source struct {
+	config.MonitoringRules `yaml:",inline"`
+	Notifier               struct {
+		Webhooks []config.WebhookConfig `yaml:"webhooks"`
+	} `yaml:"notifier"`
+}
+
+// Collect the rules of a config file with their tags, webhooks and comments
+func Export(data []byte) (*Pack, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	pack := &Pack{Version: Version, Rules: []Rule{}}
+	if len(doc.Content) == 0 {
+		return pack, nil
+	}
+	root := doc.Content[0]
+	var src source
+	if err := root.Decode(&src); err != nil {
+		return nil, err
+	}
+
+	var comments []string
+	if seq := lookup(root, "keywords"); seq != nil {
+		for _, n := range seq.Content {
+			comments = append(comments, comment(n))
+		}
+	}
+	for i, kw := range src.Keywords {
+		pack.Rules = append(pack.Rules, Rule{Keyword: kw, Comment: comments[i]})
+	}
+
+	comments = comments[:0]
+	if seq := lookup(root, "images"); seq != nil {
+		for _, n := range seq.Content {
+			comments = append(comments, comment(n))
+		}
+	}
+	for i, img := range src.Images {
+		pack.Rules = append(pack.Rules, Rule{Image: (*Image)(&img), Comment: comments[i]})
+	}
+
+	comments = comments[:0]
+	if seq := lookup(root, "files"); seq != nil {
+		for _, n := range seq.Content {
+			comments = append(comments, comment(n))
+		}
+	}
+	for i, f := range src.Files {
+		pack.Rules = append(pack.Rules, Rule{File: (*File)(&f), Comment: comments[i]})
+	}
+
+	for i := range pack.Rules {
+		r := &pack.Rules[i]
+		r.Tags = tagsFor(src.Tags, r.Name())
+		for _, hook := range src.Notifier.Webhooks {
+			// Hooks taking every rule are not specific to any of them
+			if len(hook.Rules) > 0 && wants(hook, r.Name()) {
+				r.Destinations = append(r.Destinations, hookName(hook))
+			}
+		}
+	}
+	return pack, nil
+}
+
+// Decode and check a pack
+func Read(r io.Reader) (*Pack, error) {
+	var pack Pack
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	if err := dec.Decode(&pack); err != nil {
+		return nil, err
+	}
+	switch {
+	case pack.Version == 0:
+		return nil, errors.New("missing version")
+	case pack.Version > Version:
+		return nil, fmt.Errorf("version %d is newer than the supported %d, upgrade telegram-scout", pack.Version, Version)
+	}
+	for i, rule := range pack.Rules {
+		if err := validate(rule); err != nil {
+			return nil, fmt.Errorf("rules entry %d: %w", i, err)
+		}
+	}
+	return &pack, nil
+}
+
+// Encode the pack as YAML
+func (p *Pack) Write(w io.Writer) error {
+	data, err := encode(p)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// Reject rules that would not load once merged
+func validate(r Rule) error {
+	set := 0
+	for _, ok := range []bool{r.Keyword != "", r.Image != nil, r.File != nil} {
+		if ok {
+			set++
+		}
+	}
+	if set != 1 {
+		return errors.New("exactly one of keyword, image or file is required")
+	}
+	switch {
+	case r.Image != nil && r.Image.Path == "":
+		return errors.New("image path is required")
+	case r.File != nil && r.File.Filename == "" && r.File.MimeType == "" && r.File.MinSize <= 0 && r.File.MaxSize <= 0:
+		return errors.New("file needs at least one of filename, mime_type, min_size or max_size")
+	}
+	prefix, pattern, _ := strings.Cut(r.Keyword, ":")
+	switch prefix {
+	case "re", "re2":
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("keyword %q: %w", r.Keyword, err)
+		}
+	case "pcre":
+		if _, err := regexp2.Compile(pattern, regexp2.None); err != nil {
+			return fmt.Errorf("keyword %q: %w", r.Keyword, err)
+		}
+	}
+	return nil
+}
+
+// Return the labels configured for a rule, by exact name or glob, sorted and deduplicated
+func tagsFor(tags map[string][]string, rule string) []string {
+	var out []string
+	for pattern, labels := range tags {
+		if pattern == rule {
+			out = append(out, labels...)
+			continue
+		}
+		if ok, _ := path.Match(pattern, rule); ok {
+			out = append(out, labels...)
+		}
+	}
+	slices.Sort(out)
+	return slices.Compact(out)
+}
+
+// Report whether rule is routed to the webhook
+func wants(hook config.WebhookConfig, rule string) bool {
+	if len(hook.Rules) == 0 {
+		return true
+	}
+	for _, pattern := range hook.Rules {
+		if pattern == rule {
+			return true
+		}
+		if ok, _ := path.Match(pattern, rule); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func hookName(hook config.WebhookConfig) string {
+	if hook.Name != "" {
+		return hook.Name
+	}
+	return hook.URL
+}
+
+// Return the value under key in a mapping node, nil when absent
+func lookup(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// Return the text of the comments attached to a node, without the markers
+func comment(n *yaml.Node) string {
+	text := n.LineComment
+	if text == "" {
+		text = n.HeadComment
+	}
+	if text == "" && n.Kind == yaml.MappingNode && len(n.Content) > 1 {
+		text = n.Content[1].LineComment
+	}
+	var lines []string
+	for line := range strings.Lines(text) {
+		lines = append(lines, strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "#")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Encode as YAML with the indentation of the bundled examples
+func encode(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,154 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package rules
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+const configYAML = `chats: ["*"]
+keywords:
+  - "urgent" # Pages on-call
+  - "rtx 5070"
+files:
+  # Android droppers
+  - filename: "*.apk"
+tags:
+  urgent: [critical]
+  "file:*": [malware]
+notifier:
+  webhooks:
+    - name: siem
+      url: https://siem.example.com
+      rules: ["file:*"]
+    - url: https://all.example.com
+`
+
+func TestExportImport(t *testing.T) {
+	pack, err := Export([]byte(configYAML))
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	want := []Rule{
+		{Keyword: "urgent", Comment: "Pages on-call", Tags: []string{"critical"}},
+		{Keyword: "rtx 5070"},
+		{File: &File{Filename: "*.apk"}, Comment: "Android droppers", Tags: []string{"malware"}, Destinations: []string{"siem"}},
+	}
+	if pack.Version != Version || !reflect.DeepEqual(pack.Rules, want) {
+		t.Fatalf("unexpected pack: %+v", pack)
+	}
+
+	var buf bytes.Buffer
+	if err := pack.Write(&buf); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	read, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if !reflect.DeepEqual(read, pack) {
+		t.Errorf("round trip changed the pack: %+v", read)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	pack := &Pack{Version: Version, Rules: []Rule{
+		{Keyword: "urgent", Tags: []string{"critical", "oncall"}},
+		{Keyword: "re:leak(ed)?", Comment: "Data leaks", Tags: []string{"critical"}, Destinations: []string{"siem", "pager"}},
+		{File: &File{Name: "combolists", Filename: "combo*.txt"}, Destinations: []string{"siem"}},
+	}}
+	out, rep, err := Merge([]byte(configYAML), pack)
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	wantRep := Report{
+		Added:   []string{"re:leak(ed)?", "file:combolists"},
+		Kept:    []string{"urgent"},
+		Tagged:  []string{"urgent", "re:leak(ed)?"},
+		Routed:  []string{"re:leak(ed)?"},
+		Unknown: []string{"pager"},
+	}
+	if !reflect.DeepEqual(rep, wantRep) {
+		t.Errorf("unexpected report: %+v", rep)
+	}
+	if !strings.Contains(string(out), "# Data leaks") || !strings.Contains(string(out), "# Pages on-call") {
+		t.Errorf("expected comments to be kept and added, got:\n%s", out)
+	}
+
+	merged, err := Export(out)
+	if err != nil {
+		t.Fatalf("Export of merged config failed: %v", err)
+	}
+	got := map[string]Rule{}
+	for _, r := range merged.Rules {
+		got[r.Name()] = r
+	}
+	if r := got["urgent"]; !reflect.DeepEqual(r.Tags, []string{"critical", "oncall"}) {
+		t.Errorf("expected urgent to gain a tag, got %v", r.Tags)
+	}
+	if r := got["re:leak(ed)?"]; r.Comment != "Data leaks" || !reflect.DeepEqual(r.Destinations, []string{"siem"}) {
+		t.Errorf("expected the new keyword routed to siem, got %+v", r)
+	}
+	if r := got["file:combolists"]; !reflect.DeepEqual(r.Tags, []string{"malware"}) || !reflect.DeepEqual(r.Destinations, []string{"siem"}) {
+		t.Errorf("expected the new file rule covered by the existing globs, got %+v", r)
+	}
+
+	// Merging again changes nothing
+	again, rep, err := Merge(out, pack)
+	if err != nil || rep.Changed() || !bytes.Equal(again, out) {
+		t.Errorf("expected a second merge to be a no-op, got %+v, %v", rep, err)
+	}
+}
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name, pack, err string
+	}{
+		{"missing version", "rules: []", "missing version"},
+		{"newer version", "version: 2\nrules: []", "newer"},
+		{"no kind", "version: 1\nrules: [{comment: x}]", "exactly one"},
+		{"two kinds", "version: 1\nrules: [{keyword: x, file: {filename: a}}]", "exactly one"},
+		{"bad regex", "version: 1\nrules: [{keyword: 're:('}]", "missing closing"},
+		{"bad pcre", "version: 1\nrules: [{keyword: 'pcre:(?<=('}]", "pcre:"},
+		{"empty file rule", "version: 1\nrules: [{file: {name: x}}]", "at least one"},
+		{"unknown field", "version: 1\nrules: [{keywrd: x}]", "not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Read(strings.NewReader(tt.pack))
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("expected error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestName(t *testing.T) {
+	if got := (Rule{File: &File{Filename: "*.apk", MinSize: 10}}).Name(); got != "file:"+(config.FileRule{Filename: "*.apk", MinSize: 10}).Label() {
+		t.Errorf("unexpected file rule name %q", got)
+	}
+	if got := (Rule{Image: &Image{Path: "refs/logo.png"}}).Name(); got != "image:logo" {
+		t.Errorf("unexpected image rule name %q", got)
+	}
+}
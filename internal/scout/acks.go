@@ -0,0 +1,154 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/acks"
+	"github.com/h3nc4/TelegramScout/internal/audit"
+	"github.com/h3nc4/TelegramScout/internal/model"
+	"github.com/h3nc4/TelegramScout/internal/notifier"
+)
+
+// Track the state of every alert in store and remind about critical ones. Call before Start.
+func (s *Scout) UseAcks(store *acks.Store) {
+	s.acks = store
+}
+
+// Start tracking the alert, returning ctx carrying its ID for notifier buttons
+func (s *Scout) trackAlert(ctx context.Context, msg model.Message, exp Explanation) context.Context {
+	if s.acks == nil {
+		return ctx
+	}
+	a := s.acks.Add(acks.Alert{
+		Rule:      exp.Keyword,
+		Tags:      exp.Tags,
+		Critical:  s.critical(exp.Tags),
+		ChatID:    msg.ChatID,
+		ChatTitle: msg.ChatTitle,
+		MsgID:     msg.ID,
+		Link:      msg.Link,
+	})
+	return notifier.WithAlertID(ctx, a.ID)
+}
+
+// Report whether an alert with tags needs reminders while unacknowledged
+func (s *Scout) critical(tags []string) bool {
+	critical := s.cfg.Acks.CriticalTags
+	if len(critical) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(tags, func(t string) bool { return slices.Contains(critical, t) })
+}
+
+// Format the line identifying a tracked alert, or nothing when untracked
+func alertIDLine(ctx context.Context) []string {
+	id := notifier.AlertID(ctx)
+	if id == "" {
+		return nil
+	}
+	return []string{fmt.Sprintf("🆔 <b>Alert:</b> <code>%s</code>", id)}
+}
+
+// Re-send critical alerts nobody acknowledged, until the reminders run out
+func (s *Scout) remindUnacked(ctx context.Context) {
+	every := s.cfg.Acks.RemindAfter
+	if every <= 0 || s.cfg.Acks.MaxReminders <= 0 {
+		return
+	}
+	ticker := time.NewTicker(min(every, time.Minute))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, a := range s.acks.Due(every, s.cfg.Acks.MaxReminders) {
+				if err := s.notifier.Send(notifier.WithAlertID(ctx, a.ID), reminderText(a)); err != nil {
+					s.log.Error("Failed to send reminder", zap.String("alert", a.ID), zap.Error(err))
+				}
+			}
+		}
+	}
+}
+
+func reminderText(a acks.Alert) string {
+	text := fmt.Sprintf("⏰ <b>Unacknowledged:</b> %s in %s for %s\n🆔 <b>Alert:</b> <code>%s</code>",
+		html.EscapeString(a.Rule), html.EscapeString(a.ChatTitle), time.Since(a.Created).Round(time.Minute), a.ID)
+	if a.Link != "" {
+		text += fmt.Sprintf("\n🔗 <a href=\"%s\">Link to Message</a>", html.EscapeString(a.Link))
+	}
+	return text
+}
+
+// Apply a button press to an alert, returning a confirmation and the actions
+// still available on it
+func (s *Scout) HandleButton(ctx context.Context, action, id, user string) (string, []string, error) {
+	if s.acks == nil {
+		return "", nil, fmt.Errorf("acknowledgements are disabled")
+	}
+	if action == notifier.ActionSnooze {
+		a, ok := s.acks.Get(id)
+		if !ok {
+			return "", nil, fmt.Errorf("%w: %s", acks.ErrUnknownAlert, id)
+		}
+		reply, err := s.snooze(a.Rule, a.ChatID, s.cfg.Acks.Snooze, user, audit.SourceButton)
+		return reply, s.alertActions(a), err
+	}
+
+	state := map[string]string{notifier.ActionAck: acks.StateAcked, notifier.ActionResolve: acks.StateResolved}[action]
+	if state == "" {
+		return "", nil, fmt.Errorf("unknown action %q", action)
+	}
+	a, err := s.acks.Set(id, state, user)
+	if err != nil {
+		return "", nil, err
+	}
+	s.log.Info("Alert state changed", zap.String("alert", id), zap.String("state", state), zap.String("by", user))
+	s.record(audit.Entry{Actor: user, Source: audit.SourceButton, Action: action, Target: id, ChatID: a.ChatID})
+	if state == acks.StateAcked {
+		return fmt.Sprintf("Acknowledged by %s", a.By), s.alertActions(a), nil
+	}
+	return fmt.Sprintf("Resolved by %s", a.By), s.alertActions(a), nil
+}
+
+// Return the buttons left on an alert in its current state
+func (s *Scout) alertActions(a acks.Alert) []string {
+	var actions []string
+	switch a.State {
+	case acks.StateNew:
+		actions = []string{notifier.ActionAck, notifier.ActionResolve}
+	case acks.StateAcked:
+		actions = []string{notifier.ActionResolve}
+	default:
+		return nil
+	}
+	if s.cfg.Acks.Snooze > 0 {
+		actions = append(actions, notifier.ActionSnooze)
+	}
+	return actions
+}
@@ -0,0 +1,235 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/audit"
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/logger"
+	"github.com/h3nc4/TelegramScout/internal/model"
+	"github.com/h3nc4/TelegramScout/internal/notifier"
+)
+
+// Returned when actions.rate_limit actions already ran within the last hour
+var errActionLimited = errors.New("action rate limit reached")
+
+// Actions awaiting /confirm, the oldest are forgotten beyond this
+const pendingSize = 100
+
+// Act on monitored messages as the account
+type Actor interface {
+	React(ctx context.Context, chatID int64, msgID int, emoji string) error
+	// Forward to the chat to refers to, Saved Messages when empty
+	Forward(ctx context.Context, chatID int64, msgID int, to string) error
+}
+
+// An action hook with its chat references parsed
+type actionHook struct {
+	route
+	cfg config.ActionHook
+}
+
+// An action due on a matched message
+type Action struct {
+	ID      string    `json:"id,omitempty"` // Set while awaiting confirmation
+	Do      string    `json:"do"`
+	Emoji   string    `json:"emoji,omitempty"`
+	To      string    `json:"to,omitempty"`
+	Rule    string    `json:"rule"`
+	ChatID  int64     `json:"chat_id"`
+	MsgID   int       `json:"msg_id"`
+	Created time.Time `json:"created"`
+}
+
+// Describe what the action does, as HTML
+func (a Action) describe() string {
+	switch a.Do {
+	case config.ActionReact:
+		return "react with " + html.EscapeString(a.Emoji)
+	case config.ActionForward:
+		return "forward to " + html.EscapeString(a.To)
+	default:
+		return "save to Saved Messages"
+	}
+}
+
+// Hold actions awaiting confirmation and limit how often actions run
+type actionQueue struct {
+	mux     sync.Mutex
+	nextID  int
+	pending []Action    // Oldest first
+	runs    []time.Time // Actions run within the last hour, oldest first
+}
+
+// Keep a until confirmed, returning it with its ID
+func (q *actionQueue) hold(a Action) Action {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+	q.nextID++
+	a.ID = strconv.Itoa(q.nextID)
+	q.pending = append(q.pending, a)
+	if len(q.pending) > pendingSize {
+		q.pending = q.pending[len(q.pending)-pendingSize:]
+	}
+	return a
+}
+
+// Remove and return the pending action id
+func (q *actionQueue) take(id string) (Action, bool) {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+	for i, a := range q.pending {
+		if a.ID == id {
+			q.pending = append(q.pending[:i], q.pending[i+1:]...)
+			return a, true
+		}
+	}
+	return Action{}, false
+}
+
+// Put back an action taken but not run
+func (q *actionQueue) restore(a Action) {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+	q.pending = append(q.pending, a)
+}
+
+func (q *actionQueue) list() []Action {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+	return append([]Action(nil), q.pending...)
+}
+
+// Count a run at now, reporting false when limit runs happened within the last hour
+func (q *actionQueue) allow(now time.Time, limit int) bool {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+	cutoff := now.Add(-time.Hour)
+	i := 0
+	for i < len(q.runs) && !q.runs[i].After(cutoff) {
+		i++
+	}
+	q.runs = q.runs[i:]
+	if len(q.runs) >= limit {
+		return false
+	}
+	q.runs = append(q.runs, now)
+	return true
+}
+
+// Run the configured action hooks on matches through a. Call before Start.
+func (s *Scout) UseActor(a Actor) {
+	s.actor = a
+	for _, h := range s.cfg.Actions.Hooks {
+		s.actionHooks = append(s.actionHooks, actionHook{route: newRoute(h.Rules, h.Chats), cfg: h})
+	}
+}
+
+// Run the actions hooked to the match in the background, or hold them with
+// actions.confirm, returning an alert line per held action
+func (s *Scout) triggerActions(ctx context.Context, msg model.Message, exp Explanation) []string {
+	if s.actor == nil {
+		return nil
+	}
+	var lines []string
+	for _, h := range s.actionHooks {
+		if !h.matches(msg, exp.Keyword) {
+			continue
+		}
+		a := Action{Do: h.cfg.Do, Emoji: h.cfg.Emoji, To: h.cfg.To, Rule: exp.Keyword, ChatID: msg.ChatID, MsgID: msg.ID, Created: time.Now()}
+		if s.cfg.Actions.Confirm {
+			a = s.actions.hold(a)
+			lines = append(lines, fmt.Sprintf("⏳ <b>Action:</b> %s, /confirm %s", a.describe(), a.ID))
+			continue
+		}
+		go func() {
+			if err := s.runAction(ctx, a); err != nil {
+				s.log.Warn("Failed to run action", zap.String("action", a.Do), zap.Int64("chat_id", msg.ChatID), zap.Int("msg_id", msg.ID), logger.TraceField(msg.TraceID), zap.Error(err))
+			}
+		}()
+	}
+	return lines
+}
+
+// Run a within the rate limit, counting it under its kind
+func (s *Scout) runAction(ctx context.Context, a Action) error {
+	if !s.actions.allow(time.Now(), s.cfg.Actions.RateLimit) {
+		matchMetrics.Add("action_limited", 1)
+		return errActionLimited
+	}
+	var err error
+	switch a.Do {
+	case config.ActionReact:
+		err = s.actor.React(ctx, a.ChatID, a.MsgID, a.Emoji)
+	case config.ActionForward:
+		err = s.actor.Forward(ctx, a.ChatID, a.MsgID, a.To)
+	default:
+		err = s.actor.Forward(ctx, a.ChatID, a.MsgID, "")
+	}
+	if err != nil {
+		return err
+	}
+	matchMetrics.Add("action:"+a.Do, 1)
+	return nil
+}
+
+// /confirm <id>
+func (s *Scout) confirmCommand(ctx context.Context, cmd notifier.Command) (string, error) {
+	if len(cmd.Args) != 1 {
+		return "", fmt.Errorf("usage: /confirm <id>")
+	}
+	a, ok := s.actions.take(cmd.Args[0])
+	if !ok {
+		return "", fmt.Errorf("no pending action %s", cmd.Args[0])
+	}
+	if err := s.runAction(ctx, a); err != nil {
+		if errors.Is(err, errActionLimited) {
+			// Let it be confirmed again once the limit allows
+			s.actions.restore(a)
+		}
+		return "", err
+	}
+	s.log.Info("Action confirmed", zap.String("id", a.ID), zap.String("action", a.Do), zap.String("by", cmd.User))
+	s.record(audit.Entry{Actor: cmd.User, Source: audit.SourceCommand, Action: "confirm", Target: a.Do, ChatID: a.ChatID, Detail: fmt.Sprintf("message %d, rule %s", a.MsgID, a.Rule)})
+	return fmt.Sprintf("✅ Done: %s on message %d in %s", a.describe(), a.MsgID, chatLabel(a.ChatID)), nil
+}
+
+// /pending
+func (s *Scout) pendingCommand(_ context.Context, _ notifier.Command) (string, error) {
+	list := s.actions.list()
+	if len(list) == 0 {
+		return "No actions awaiting confirmation.", nil
+	}
+	lines := []string{"⏳ <b>Pending actions</b>"}
+	for _, a := range list {
+		lines = append(lines, fmt.Sprintf("• %s: %s on message %d in %s (%s)", a.ID, a.describe(), a.MsgID, chatLabel(a.ChatID), html.EscapeString(a.Rule)))
+	}
+	return strings.Join(lines, "\n"), nil
+}
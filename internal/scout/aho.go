@@ -0,0 +1,111 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+// Aho-Corasick automaton finding which of many byte patterns occur in a text
+// in one pass over it. Patterns are matched exactly, callers lowercase both.
+type ahoCorasick struct {
+	next []map[byte]int32
+	fail []int32
+	// Lowest index of the patterns ending at each node, including through
+	// failure links, or -1
+	out []int32
+}
+
+// Build the automaton, patterns must not be empty
+func newAhoCorasick(patterns []string) *ahoCorasick {
+	a := &ahoCorasick{}
+	a.node()
+	for i, p := range patterns {
+		node := int32(0)
+		for j := range len(p) {
+			n, ok := a.next[node][p[j]]
+			if !ok {
+				n = a.node()
+				a.next[node][p[j]] = n
+			}
+			node = n
+		}
+		// Duplicates keep the first index
+		if a.out[node] < 0 {
+			a.out[node] = int32(i)
+		}
+	}
+
+	// Breadth first, so failure links point to nodes already complete
+	var queue []int32
+	for _, n := range a.next[0] {
+		queue = append(queue, n)
+	}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for c, child := range a.next[node] {
+			f := a.fail[node]
+			for {
+				if n, ok := a.next[f][c]; ok {
+					a.fail[child] = n
+					break
+				}
+				if f == 0 {
+					break
+				}
+				f = a.fail[f]
+			}
+			if o := a.out[a.fail[child]]; o >= 0 && (a.out[child] < 0 || o < a.out[child]) {
+				a.out[child] = o
+			}
+			queue = append(queue, child)
+		}
+	}
+	return a
+}
+
+func (a *ahoCorasick) node() int32 {
+	a.next = append(a.next, map[byte]int32{})
+	a.fail = append(a.fail, 0)
+	a.out = append(a.out, -1)
+	return int32(len(a.next) - 1)
+}
+
+// Return the lowest index of the patterns occurring in text, or -1
+func (a *ahoCorasick) lowest(text string) int {
+	best := int32(-1)
+	node := int32(0)
+	for i := range len(text) {
+		c := text[i]
+		for {
+			if n, ok := a.next[node][c]; ok {
+				node = n
+				break
+			}
+			if node == 0 {
+				break
+			}
+			node = a.fail[node]
+		}
+		if o := a.out[node]; o >= 0 && (best < 0 || o < best) {
+			if o == 0 {
+				return 0
+			}
+			best = o
+		}
+	}
+	return int(best)
+}
@@ -0,0 +1,54 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"github.com/h3nc4/TelegramScout/internal/archive"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Keep records of messages and matches
+type MessageArchive interface {
+	// Queue r for writing without blocking
+	Add(r archive.Record)
+}
+
+// Archive matches, and every message with archive.all, through a. Call before Start.
+func (s *Scout) UseArchive(a MessageArchive) {
+	s.archive = a
+}
+
+// Archive a received message when every message is archived
+func (s *Scout) archiveMessage(msg model.Message) {
+	if s.archive == nil || !s.cfg.Archive.All {
+		return
+	}
+	s.archive.Add(archive.FromMessage(archive.KindMessage, msg))
+}
+
+// Archive a match
+func (s *Scout) archiveMatch(msg model.Message, exp Explanation) {
+	if s.archive == nil {
+		return
+	}
+	r := archive.FromMessage(archive.KindMatch, msg)
+	r.Rule = exp.Keyword
+	r.Tags = exp.Tags
+	s.archive.Add(r)
+}
@@ -0,0 +1,40 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/audit"
+)
+
+// Record snoozes and alert state changes in log. Call before Start.
+func (s *Scout) UseAudit(log *audit.Log) {
+	s.audit = log
+}
+
+// Append e to the audit log when one is set
+func (s *Scout) record(e audit.Entry) {
+	if s.audit == nil {
+		return
+	}
+	if err := s.audit.Record(e); err != nil {
+		s.log.Error("Failed to write audit log", zap.String("action", e.Action), zap.Error(err))
+	}
+}
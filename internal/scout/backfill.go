@@ -0,0 +1,160 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/archive"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Backfills kept for Backfills once finished
+const backfillHistory = 20
+
+// Walk the history of a chat, given as @username or ID, from the newest message back to since
+type HistoryReader interface {
+	History(ctx context.Context, chat string, since time.Time, fn func(model.Message) error) error
+}
+
+// A backfill, running or finished
+type BackfillReport struct {
+	ID       int            `json:"id"`
+	Chat     string         `json:"chat"`
+	Since    time.Time      `json:"since"`
+	Evaluate bool           `json:"evaluate"`
+	Started  time.Time      `json:"started"`
+	Finished time.Time      `json:"finished,omitzero"`
+	Messages int            `json:"messages"`          // Archived so far
+	Oldest   time.Time      `json:"oldest,omitzero"`   // Date of the oldest message archived so far
+	Matches  map[string]int `json:"matches,omitempty"` // By rule, when evaluating
+	Error    string         `json:"error,omitempty"`
+}
+
+// Backfills started on this instance, oldest first
+type backfills struct {
+	mux     sync.Mutex
+	next    int
+	reports []*BackfillReport
+}
+
+// Read chat history for backfills through h. Call before Start.
+func (s *Scout) UseHistoryReader(h HistoryReader) {
+	s.history = h
+}
+
+// Start writing the history of chat since the given time into the archive,
+// running until done or ctx is cancelled. With evaluate, messages also run
+// against the rules and matches are archived as archive.KindBackfill, without
+// alerting, so datasets can be bootstrapped from past messages.
+func (s *Scout) StartBackfill(ctx context.Context, chat string, since time.Time, evaluate bool) (BackfillReport, error) {
+	if s.archive == nil {
+		return BackfillReport{}, errors.New("archive is disabled, set archive.enabled in config")
+	}
+	if s.history == nil {
+		return BackfillReport{}, errors.New("chat history is not available")
+	}
+
+	s.backfills.mux.Lock()
+	s.backfills.next++
+	report := &BackfillReport{
+		ID:       s.backfills.next,
+		Chat:     chat,
+		Since:    since,
+		Evaluate: evaluate,
+		Started:  time.Now(),
+	}
+	s.backfills.reports = append(s.backfills.reports, report)
+	// Forget the oldest finished backfill once too many are kept
+	if len(s.backfills.reports) > backfillHistory {
+		if i := slices.IndexFunc(s.backfills.reports, func(r *BackfillReport) bool { return !r.Finished.IsZero() }); i >= 0 {
+			s.backfills.reports = slices.Delete(s.backfills.reports, i, i+1)
+		}
+	}
+	started := *report
+	s.backfills.mux.Unlock()
+
+	s.log.Info("Backfilling chat history", zap.String("chat", chat), zap.Time("since", since), zap.Bool("evaluate", evaluate))
+	go s.backfill(ctx, report)
+	return started, nil
+}
+
+// Return the backfills started on this instance, newest first
+func (s *Scout) Backfills() []BackfillReport {
+	s.backfills.mux.Lock()
+	defer s.backfills.mux.Unlock()
+
+	out := make([]BackfillReport, 0, len(s.backfills.reports))
+	for _, r := range slices.Backward(s.backfills.reports) {
+		c := *r
+		c.Matches = maps.Clone(r.Matches)
+		out = append(out, c)
+	}
+	return out
+}
+
+func (s *Scout) backfill(ctx context.Context, report *BackfillReport) {
+	err := s.history.History(ctx, report.Chat, report.Since, func(msg model.Message) error {
+		s.archive.Add(archive.FromMessage(archive.KindMessage, msg))
+
+		var rule string
+		if report.Evaluate {
+			if exp, ok := s.evaluate(msg); ok {
+				rule = exp.Keyword
+				r := archive.FromMessage(archive.KindBackfill, msg)
+				r.Rule = rule
+				r.Tags = s.tagsFor(rule)
+				s.archive.Add(r)
+			}
+		}
+
+		s.backfills.mux.Lock()
+		defer s.backfills.mux.Unlock()
+		report.Messages++
+		report.Oldest = msg.Date
+		if rule != "" {
+			if report.Matches == nil {
+				report.Matches = make(map[string]int)
+			}
+			report.Matches[rule]++
+		}
+		return nil
+	})
+
+	s.backfills.mux.Lock()
+	report.Finished = time.Now()
+	if err != nil {
+		report.Error = err.Error()
+	}
+	final := *report
+	s.backfills.mux.Unlock()
+
+	if err != nil {
+		s.log.Error("Backfill failed", zap.String("chat", final.Chat), zap.Int("messages", final.Messages), zap.Error(err))
+		return
+	}
+	s.log.Info("Backfill finished", zap.String("chat", final.Chat), zap.Int("messages", final.Messages), zap.Duration("took", final.Finished.Sub(final.Started)))
+}
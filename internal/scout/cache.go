@@ -0,0 +1,141 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"container/list"
+	"hash/maphash"
+	"sync"
+	"time"
+
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Remember which rule matched recently seen text, so content forwarded or
+// cross-posted to many chats is only matched once. Results are keyed by the
+// content hash of cross-chat dedup and reused only for the exact same text
+// and entities, since rules may care about case and spacing.
+type matchCache struct {
+	mux        sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	seed       maphash.Seed
+
+	order *list.List // Front = most recently used
+	items map[string]*list.Element
+}
+
+type matchCacheEntry struct {
+	key    string
+	exact  uint64 // Of the text and entities the result was found for
+	rule   int    // Index of the matching rule, -1 for none
+	expiry time.Time
+}
+
+func newMatchCache(ttl time.Duration, maxEntries int) *matchCache {
+	return &matchCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		seed:       maphash.MakeSeed(),
+		order:      list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+// Return the rule cached for msg under key, or false when there is none
+func (c *matchCache) get(key string, msg model.Message) (int, bool) {
+	exact := c.exact(msg)
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return 0, false
+	}
+	entry := el.Value.(*matchCacheEntry)
+	if time.Now().After(entry.expiry) {
+		c.order.Remove(el)
+		delete(c.items, key)
+		return 0, false
+	}
+	if entry.exact != exact {
+		return 0, false
+	}
+	c.order.MoveToFront(el)
+	return entry.rule, true
+}
+
+// Record rule as the result for msg, replacing any variant cached under key
+func (c *matchCache) put(key string, msg model.Message, rule int) {
+	exact := c.exact(msg)
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	expiry := time.Now().Add(c.ttl)
+	if el, ok := c.items[key]; ok {
+		entry := el.Value.(*matchCacheEntry)
+		entry.exact, entry.rule, entry.expiry = exact, rule, expiry
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&matchCacheEntry{key: key, exact: exact, rule: rule, expiry: expiry})
+	if c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		back := c.order.Back()
+		c.order.Remove(back)
+		delete(c.items, back.Value.(*matchCacheEntry).key)
+	}
+}
+
+func (c *matchCache) exact(msg model.Message) uint64 {
+	var h maphash.Hash
+	h.SetSeed(c.seed)
+	h.WriteString(msg.Text)
+	for _, e := range msg.Entities {
+		maphash.WriteComparable(&h, e)
+	}
+	return h.Sum64()
+}
+
+// Find the first matching keyword, reusing the result for repeated content.
+// hash is the content hash of msg when the caller has it already.
+func (e *engine) firstMatch(msg model.Message, hash string) (int, []int) {
+	if e.results == nil || msg.Text == "" {
+		return e.first(msg)
+	}
+	if hash == "" {
+		hash = contentHash(msg.Text)
+	}
+
+	if i, ok := e.results.get(hash, msg); ok {
+		matchMetrics.Add("cache:hit", 1)
+		if i < 0 {
+			return -1, nil
+		}
+		if loc := e.rules[i].locate(msg); loc != nil {
+			return i, loc
+		}
+	}
+	matchMetrics.Add("cache:miss", 1)
+	i, loc := e.first(msg)
+	e.results.put(hash, msg, i)
+	return i, loc
+}
@@ -0,0 +1,165 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/logger"
+	"github.com/h3nc4/TelegramScout/internal/model"
+	"github.com/h3nc4/TelegramScout/internal/notifier"
+)
+
+// Post canary messages into the monitored test chat
+type CanaryPoster interface {
+	Post(ctx context.Context, chat, text string) error
+}
+
+// Starts every canary message, followed by the token of its loop
+const canaryPrefix = "TelegramScout canary "
+
+// Check the receive, match and alert loop on an interval by posting canaries
+// into canary.chat through p. Call before Start.
+func (s *Scout) UseCanary(p CanaryPoster) {
+	s.canaryPoster = p
+	s.canaryRoute = newRoute(nil, []string{s.cfg.Canary.Chat})
+}
+
+// The canary loop in flight
+type canaryRun struct {
+	token    string
+	posted   time.Time
+	received time.Time
+	done     chan struct{} // Closed once its alert is delivered
+}
+
+type canaryState struct {
+	mux sync.Mutex
+	run *canaryRun
+}
+
+func (c *canaryState) set(run *canaryRun) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.run = run
+}
+
+// Return the run token belongs to the first time it is received, nil otherwise
+func (c *canaryState) receive(token string, at time.Time) *canaryRun {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if c.run == nil || c.run.token != token || !c.run.received.IsZero() {
+		return nil
+	}
+	c.run.received = at
+	return c.run
+}
+
+func (c *canaryState) receivedAt(run *canaryRun) time.Time {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return run.received
+}
+
+// Post a canary every interval until ctx is done
+func (s *Scout) runCanaries(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.Canary.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.checkCanary(ctx)
+		}
+	}
+}
+
+// Post one canary and wait for its alert, alerting when it does not come in time
+func (s *Scout) checkCanary(ctx context.Context) {
+	run := &canaryRun{token: logger.NewTraceID(), posted: time.Now(), done: make(chan struct{})}
+	s.canary.set(run)
+	defer s.canary.set(nil)
+
+	waitCtx, cancel := context.WithTimeout(ctx, s.cfg.Canary.Timeout)
+	defer cancel()
+	if err := s.canaryPoster.Post(waitCtx, s.cfg.Canary.Chat, canaryPrefix+run.token); err != nil {
+		s.canaryFailed(ctx, fmt.Sprintf("posting it failed: %v", err))
+		return
+	}
+
+	select {
+	case <-run.done:
+		took := time.Since(run.posted)
+		latencyMetrics.Add("canary_ok", 1)
+		last := new(expvar.Int)
+		last.Set(took.Milliseconds())
+		latencyMetrics.Set("canary_ms", last)
+		s.log.Info("Canary loop completed", zap.Duration("took", took))
+	case <-waitCtx.Done():
+		if ctx.Err() != nil {
+			return
+		}
+		reason := fmt.Sprintf("it was not received within %s", s.cfg.Canary.Timeout)
+		if received := s.canary.receivedAt(run); !received.IsZero() {
+			reason = fmt.Sprintf("it was received after %s but its alert was not delivered within %s",
+				received.Sub(run.posted).Round(time.Millisecond), s.cfg.Canary.Timeout)
+		}
+		s.canaryFailed(ctx, reason)
+	}
+}
+
+func (s *Scout) canaryFailed(ctx context.Context, reason string) {
+	latencyMetrics.Add("canary_failed", 1)
+	s.log.Error("Canary loop failed", zap.String("chat", s.cfg.Canary.Chat), zap.String("reason", reason))
+	if err := s.notifier.Send(ctx, "🐤 <b>Canary failed:</b> "+reason); err != nil {
+		s.log.Warn("Failed to send canary failure", zap.Error(err))
+	}
+}
+
+// Recognise canary messages in the canary chat, which never reach the rules,
+// and alert silently on the one awaited
+func (s *Scout) canaryReceived(ctx context.Context, msg model.Message) bool {
+	if s.canaryPoster == nil || !strings.HasPrefix(msg.Text, canaryPrefix) || !s.canaryRoute.matches(msg, "") {
+		return false
+	}
+	run := s.canary.receive(strings.TrimPrefix(msg.Text, canaryPrefix), time.Now())
+	if run == nil {
+		// Late, from an earlier run or from another instance
+		return true
+	}
+
+	go func() {
+		text := fmt.Sprintf("🐤 <b>Canary:</b> received %s after posting", run.received.Sub(run.posted).Round(time.Millisecond))
+		if err := s.notifier.Send(notifier.WithSilent(ctx), text); err != nil {
+			s.log.Warn("Failed to send canary alert", logger.TraceField(msg.TraceID), zap.Error(err))
+			return
+		}
+		close(run.done)
+	}()
+	return true
+}
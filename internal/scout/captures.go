@@ -0,0 +1,36 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import "regexp"
+
+// Return the named groups of the first match of re in text, unset groups omitted
+func captures(re *regexp.Regexp, text string) map[string]string {
+	m := re.FindStringSubmatchIndex(text)
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]string)
+	for i, name := range re.SubexpNames() {
+		if name != "" && m[2*i] >= 0 {
+			out[name] = text[m[2*i]:m[2*i+1]]
+		}
+	}
+	return out
+}
@@ -0,0 +1,274 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Number of independently locked parts of recentIDs
+const recentShards = 32
+
+// Remember the most recent matched message IDs of each chat.
+// Message IDs grow monotonically per chat, so anything above the highest
+// recorded ID is new and only older IDs need a scan of the ring. Chats are
+// spread over shards so busy chats do not contend on a single lock.
+type recentIDs struct {
+	window int
+	shards [recentShards]recentShard
+}
+
+type recentShard struct {
+	mux   sync.Mutex
+	chats map[int64]*idRing
+}
+
+type idRing struct {
+	ids     []int
+	next    int
+	highest int
+}
+
+func newRecentIDs(window int) *recentIDs {
+	if window <= 0 {
+		window = 1
+	}
+	r := &recentIDs{window: window}
+	for i := range r.shards {
+		r.shards[i].chats = make(map[int64]*idRing)
+	}
+	return r
+}
+
+func (r *recentIDs) shard(chatID int64) *recentShard {
+	return &r.shards[uint64(chatID)%recentShards]
+}
+
+// Report whether the message ID was recorded for the chat
+func (r *recentIDs) contains(chatID int64, msgID int) bool {
+	sh := r.shard(chatID)
+	sh.mux.Lock()
+	defer sh.mux.Unlock()
+
+	ring, ok := sh.chats[chatID]
+	if !ok || msgID > ring.highest {
+		return false
+	}
+	return slices.Contains(ring.ids, msgID)
+}
+
+// Record the message ID, overwriting the oldest one once the ring is full
+func (r *recentIDs) add(chatID int64, msgID int) {
+	sh := r.shard(chatID)
+	sh.mux.Lock()
+	defer sh.mux.Unlock()
+
+	ring, ok := sh.chats[chatID]
+	if !ok {
+		ring = &idRing{ids: make([]int, 0, r.window)}
+		sh.chats[chatID] = ring
+	}
+
+	if len(ring.ids) < r.window {
+		ring.ids = append(ring.ids, msgID)
+	} else {
+		ring.ids[ring.next] = msgID
+		ring.next = (ring.next + 1) % r.window
+	}
+	ring.highest = max(ring.highest, msgID)
+}
+
+// Derive a dedup key from message text, ignoring case and whitespace layout
+func contentHash(text string) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(text)), " ")
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// Bounded set of recently seen keys with TTL expiry and LRU eviction
+type dedupCache struct {
+	mux        sync.Mutex
+	ttl        time.Duration
+	maxEntries int // Zero means unbounded
+
+	order *list.List // Front = most recently used
+	items map[string]*list.Element
+}
+
+type dedupEntry struct {
+	key    string
+	expiry time.Time
+}
+
+func newDedupCache(ttl time.Duration, maxEntries int) *dedupCache {
+	return &dedupCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		order:      list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+// Report whether key was seen and has not expired yet
+func (c *dedupCache) contains(key string) bool {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return false
+	}
+	if time.Now().After(el.Value.(*dedupEntry).expiry) {
+		c.remove(el)
+		return false
+	}
+	c.order.MoveToFront(el)
+	return true
+}
+
+// Record key, evicting the least recently used entry when full
+func (c *dedupCache) add(key string) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	expiry := time.Now().Add(c.ttl)
+	if el, ok := c.items[key]; ok {
+		el.Value.(*dedupEntry).expiry = expiry
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&dedupEntry{key: key, expiry: expiry})
+	if c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		c.remove(c.order.Back())
+	}
+}
+
+// Drop every expired entry
+func (c *dedupCache) cleanup(now time.Time) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	for el := c.order.Back(); el != nil; {
+		prev := el.Prev()
+		if now.After(el.Value.(*dedupEntry).expiry) {
+			c.remove(el)
+		}
+		el = prev
+	}
+}
+
+func (c *dedupCache) len() int {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.order.Len()
+}
+
+func (c *dedupCache) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.items, el.Value.(*dedupEntry).key)
+}
+
+// How far apart the dates of one message seen by both accounts may be
+const spareSkew = 10 * time.Second
+
+// How long a message is remembered for the other account to catch up on,
+// covering updates it fetches late after reconnecting
+const spareTTL = 10 * time.Minute
+
+// Drop the copy of each message that the second account receives when the
+// primary and the hot spare monitor the same chats. Message IDs differ
+// between accounts outside channels, so copies are matched by chat, content
+// and date. Identical messages posted close together are all kept, since
+// each account counts how many it saw.
+type spareDedup struct {
+	mux       sync.Mutex
+	groups    map[string][]*spareGroup
+	lastPrune time.Time
+}
+
+// Copies of one message, by account
+type spareGroup struct {
+	date      time.Time
+	seen      [2]int // Primary, spare
+	forwarded int
+	at        time.Time // When the first copy arrived
+}
+
+func newSpareDedup() *spareDedup {
+	return &spareDedup{groups: make(map[string][]*spareGroup)}
+}
+
+// Report whether msg is a copy the other account already delivered
+func (d *spareDedup) duplicate(msg model.Message) bool {
+	key := fmt.Sprintf("%d:%s:%s", msg.ChatID, msg.Media, contentHash(msg.Text))
+	account := 0
+	if msg.Spare {
+		account = 1
+	}
+
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	now := time.Now()
+	d.prune(now)
+
+	var group *spareGroup
+	for _, g := range d.groups[key] {
+		if diff := g.date.Sub(msg.Date); diff <= spareSkew && diff >= -spareSkew {
+			group = g
+			break
+		}
+	}
+	if group == nil {
+		group = &spareGroup{date: msg.Date, at: now}
+		d.groups[key] = append(d.groups[key], group)
+	}
+
+	group.seen[account]++
+	if group.seen[account] <= group.forwarded {
+		return true
+	}
+	group.forwarded++
+	return false
+}
+
+// Forget messages both accounts had time to receive, at most twice per TTL
+func (d *spareDedup) prune(now time.Time) {
+	if now.Sub(d.lastPrune) < spareTTL/2 {
+		return
+	}
+	d.lastPrune = now
+	for key, groups := range d.groups {
+		groups = slices.DeleteFunc(groups, func(g *spareGroup) bool { return now.Sub(g.at) > spareTTL })
+		if len(groups) == 0 {
+			delete(d.groups, key)
+		} else {
+			d.groups[key] = groups
+		}
+	}
+}
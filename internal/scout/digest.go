@@ -0,0 +1,162 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/notifier"
+)
+
+// Keep digest messages under the Bot API text limit
+const maxDigestLength = 4000
+
+// Hold alert summaries back while the notifier is degraded
+type digestStore interface {
+	// Append a summary line, discarding the oldest one when full
+	add(line string)
+	// Remove as many lines as fit in one message, plus the count of dropped lines
+	take() ([]string, int)
+	// Put lines back in front after a failed delivery
+	requeue(lines []string, dropped int)
+	len() int
+}
+
+// Bounded in-memory digestStore
+type digestQueue struct {
+	mux     sync.Mutex
+	size    int
+	lines   []string
+	dropped int
+}
+
+func newDigestQueue(size int) *digestQueue {
+	if size <= 0 {
+		size = 1
+	}
+	return &digestQueue{size: size}
+}
+
+// Append a summary line, discarding the oldest one when full
+func (q *digestQueue) add(line string) {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+	if len(q.lines) >= q.size {
+		q.lines = q.lines[1:]
+		q.dropped++
+	}
+	q.lines = append(q.lines, line)
+}
+
+// Remove as many lines as fit in one message, plus the count of dropped lines
+func (q *digestQueue) take() ([]string, int) {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+
+	length := 0
+	n := 0
+	for n < len(q.lines) {
+		size := utf8.RuneCountInString(q.lines[n]) + 1
+		if n > 0 && length+size > maxDigestLength {
+			break
+		}
+		length += size
+		n++
+	}
+
+	lines := append([]string(nil), q.lines[:n]...)
+	q.lines = q.lines[n:]
+	dropped := q.dropped
+	q.dropped = 0
+	return lines, dropped
+}
+
+// Put lines back in front after a failed delivery
+func (q *digestQueue) requeue(lines []string, dropped int) {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+	q.lines = append(lines, q.lines...)
+	if over := len(q.lines) - q.size; over > 0 {
+		q.lines = q.lines[over:]
+		dropped += over
+	}
+	q.dropped += dropped
+}
+
+func (q *digestQueue) len() int {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+	return len(q.lines)
+}
+
+// Report whether the notifier signalled it is failing or rate limited
+func (s *Scout) notifierDegraded() bool {
+	if h, ok := s.notifier.(notifier.HealthReporter); ok {
+		return !h.Healthy()
+	}
+	return false
+}
+
+// Periodically send queued alerts as a digest once the notifier recovers
+func (s *Scout) flushDigests(ctx context.Context) {
+	ticker := time.NewTicker(s.digestInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for s.digest.len() > 0 && !s.notifierDegraded() {
+				if !s.sendDigest(ctx) {
+					break
+				}
+			}
+		}
+	}
+}
+
+func (s *Scout) sendDigest(ctx context.Context) bool {
+	lines, dropped := s.digest.take()
+	if len(lines) == 0 {
+		return false
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "🗂 <b>Digest:</b> %d alerts held back\n", len(lines))
+	if dropped > 0 {
+		fmt.Fprintf(&b, "⚠️ %d older alerts were discarded\n", dropped)
+	}
+	b.WriteString("\n")
+	b.WriteString(strings.Join(lines, "\n"))
+
+	if err := s.notifier.Send(ctx, b.String()); err != nil {
+		s.log.Error("Failed to send digest, requeueing", zap.Error(err))
+		s.digest.requeue(lines, dropped)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,269 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Keywords from which the auto engine stops trying them one by one
+const autoEngineRules = 32
+
+// Returned when selecting Hyperscan, whose library this build does not link
+var errNoHyperscan = errors.New("hyperscan is not available in this build")
+
+// Find the first keyword, in config order, matching a message. Every
+// engine reports the same rule as trying the keywords one by one does.
+type matcher interface {
+	// Return the index of the matching rule and its offsets, or -1
+	first(msg model.Message) (int, []int)
+}
+
+// An engine built for the compiled rules. Engines are swapped whole, so the
+// matcher, the rules its indexes refer to and their cached results agree.
+type engine struct {
+	name string // Resolved, never EngineAuto
+	auto bool   // Picked by EngineAuto
+	matcher
+	rules    []matchRule    // Keywords in config order
+	rejected []RejectedRule // Keywords that failed to compile
+	keywords []string       // Compiled into rules and rejected
+	results  *matchCache    // Rules matched by recently seen content, nil when caching is disabled
+}
+
+// Switch the engine matching keywords, taking effect from the next message.
+// An empty name or "auto" picks one from the number and kind of keywords.
+func (s *Scout) SetEngine(name string) error {
+	s.engineMux.Lock()
+	defer s.engineMux.Unlock()
+	cur := s.engine.Load()
+	e, err := newEngine(name, cur.rules)
+	if err != nil {
+		return err
+	}
+	// Same rules, so the cached results still hold
+	e.rejected, e.results, e.keywords = cur.rejected, cur.results, cur.keywords
+	s.engine.Store(e)
+	return nil
+}
+
+// Return the engine in use and whether auto picked it
+func (s *Scout) Engine() (string, bool) {
+	e := s.engine.Load()
+	return e.name, e.auto
+}
+
+func newEngine(name string, rules []matchRule) (*engine, error) {
+	auto := name == "" || name == config.EngineAuto
+	if auto {
+		name = autoEngine(rules)
+	}
+	var m matcher
+	switch name {
+	case config.EngineNaive:
+		m = naiveMatcher(rules)
+	case config.EngineAhoCorasick:
+		m = newAhoMatcher(rules)
+	case config.EngineRegex:
+		rm, err := newRegexMatcher(rules)
+		if err != nil && !auto {
+			return nil, err
+		}
+		m = rm
+		if err != nil {
+			name, m = config.EngineNaive, naiveMatcher(rules)
+		}
+	case config.EngineHyperscan:
+		return nil, errNoHyperscan
+	default:
+		return nil, fmt.Errorf("unknown matching engine %q", name)
+	}
+	return &engine{name: name, auto: auto, matcher: m, rules: rules}, nil
+}
+
+// Pick the engine for the rules: one by one while they are few, in one pass
+// when most are single words, behind a combined regex otherwise
+func autoEngine(rules []matchRule) string {
+	if len(rules) < autoEngineRules {
+		return config.EngineNaive
+	}
+	words := 0
+	for _, r := range rules {
+		if r.kind == kindWord && !r.wordwise {
+			words++
+		}
+	}
+	if words*2 >= len(rules) {
+		return config.EngineAhoCorasick
+	}
+	return config.EngineRegex
+}
+
+// Try every rule in order
+type naiveMatcher []matchRule
+
+func (m naiveMatcher) first(msg model.Message) (int, []int) {
+	for i, rule := range m {
+		if loc := rule.locate(msg); loc != nil {
+			return i, loc
+		}
+	}
+	return -1, nil
+}
+
+// Find single-word keywords with one automaton and try the others in order
+// only while they come before the first word found
+type ahoMatcher struct {
+	rules  []matchRule
+	words  *ahoCorasick
+	index  []int // Rule index of each automaton pattern
+	others []int // Rule indexes outside the automaton, ascending
+}
+
+func newAhoMatcher(rules []matchRule) *ahoMatcher {
+	m := &ahoMatcher{rules: rules}
+	var patterns []string
+	for i, r := range rules {
+		if r.kind != kindWord || r.original == "" || r.wordwise {
+			m.others = append(m.others, i)
+			continue
+		}
+		patterns = append(patterns, strings.ToLower(r.original))
+		m.index = append(m.index, i)
+	}
+	m.words = newAhoCorasick(patterns)
+	return m
+}
+
+func (m *ahoMatcher) first(msg model.Message) (int, []int) {
+	lowText := strings.ToLower(msg.Text)
+	if len(lowText) != len(msg.Text) {
+		// Word rules fall back to a regex for such text, so must the engine
+		return naiveMatcher(m.rules).first(msg)
+	}
+
+	found := -1
+	if p := m.words.lowest(lowText); p >= 0 {
+		found = m.index[p]
+	}
+	for _, i := range m.others {
+		if found >= 0 && i > found {
+			break
+		}
+		if loc := m.rules[i].locate(msg); loc != nil {
+			return i, loc
+		}
+	}
+	if found < 0 {
+		return -1, nil
+	}
+	return found, m.rules[found].locate(msg)
+}
+
+// Skip messages that no text rule matches with one regex combining all of
+// them, trying the rules in order for the rest. Most messages match nothing.
+type regexMatcher struct {
+	rules    []matchRule
+	any      *regexp.Regexp // Nil without text rules
+	entities bool           // Some rules match entities, which the regex does not cover
+	opaque   bool           // Some rules are not RE2, so the regex cannot rule out any message
+}
+
+func newRegexMatcher(rules []matchRule) (*regexMatcher, error) {
+	m := &regexMatcher{rules: rules}
+	var alts []string
+	for _, r := range rules {
+		if r.entity != "" {
+			m.entities = true
+			continue
+		}
+		if r.pattern == "" {
+			m.opaque = true
+			continue
+		}
+		alts = append(alts, "(?:"+r.pattern+")")
+	}
+	if len(alts) > 0 {
+		re, err := regexp.Compile(strings.Join(alts, "|"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to combine keywords: %w", err)
+		}
+		m.any = re
+	}
+	return m, nil
+}
+
+func (m *regexMatcher) first(msg model.Message) (int, []int) {
+	text := m.any != nil && m.any.MatchString(msg.Text)
+	if !text && !m.opaque && !(m.entities && len(msg.Entities) > 0) {
+		return -1, nil
+	}
+	return naiveMatcher(m.rules).first(msg)
+}
+
+// Time taken by one engine to match a set of messages
+type EngineResult struct {
+	Engine  string
+	Elapsed time.Duration
+	Matches int
+	// Messages where the engine reported another rule than trying them in order
+	Mismatches int
+	Err        error
+}
+
+// Match msgs against the keywords with every engine, for comparing them on
+// the configured rules. Only keywords are matched, not the whole pipeline.
+func (s *Scout) BenchEngines(msgs []model.Message) []EngineResult {
+	rules := s.engine.Load().rules
+	reference := make([]int, len(msgs))
+	for i, msg := range msgs {
+		reference[i], _ = naiveMatcher(rules).first(msg)
+	}
+
+	var results []EngineResult
+	for _, name := range []string{config.EngineNaive, config.EngineAhoCorasick, config.EngineRegex, config.EngineHyperscan} {
+		res := EngineResult{Engine: name}
+		e, err := newEngine(name, rules)
+		if err != nil {
+			res.Err = err
+			results = append(results, res)
+			continue
+		}
+		start := time.Now()
+		for i, msg := range msgs {
+			idx, _ := e.first(msg)
+			if idx >= 0 {
+				res.Matches++
+			}
+			if idx != reference[i] {
+				res.Mismatches++
+			}
+		}
+		res.Elapsed = time.Since(start)
+		results = append(results, res)
+	}
+	return results
+}
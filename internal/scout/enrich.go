@@ -0,0 +1,53 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/logger"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Attach threat intelligence about shared files to alerts
+type Enricher interface {
+	// Return preformatted alert lines describing the message's file, or nil
+	Enrich(ctx context.Context, msg model.Message) ([]string, error)
+}
+
+// Enrich alerts about documents through e. Call before Start.
+func (s *Scout) UseEnricher(e Enricher) {
+	s.enricher = e
+}
+
+// Enrich the alert, returning nil when disabled or on failure
+func (s *Scout) enrich(ctx context.Context, msg model.Message) []string {
+	if s.enricher == nil || msg.File == nil {
+		return nil
+	}
+	lines, err := s.enricher.Enrich(ctx, msg)
+	if err != nil {
+		// Still alert without the verdict
+		s.log.Error("Failed to enrich alert", zap.Int64("chat_id", msg.ChatID), zap.Int("msg_id", msg.ID), logger.TraceField(msg.TraceID), zap.Error(err))
+		return nil
+	}
+	return lines
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/h3nc4/TelegramScout/internal/chatid"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Prepended to keywords matched against mention entities rather than text
+const handlePrefix = "mention:"
+
+// Keywords compiled to hashtag and cashtag rules. Cashtags are short tickers,
+// as Telegram only recognizes up to eight letters after the '$'.
+var (
+	hashtagPattern = regexp.MustCompile(`^#[\p{L}\p{N}_]*[\p{L}_][\p{L}\p{N}_]*$`)
+	cashtagPattern = regexp.MustCompile(`^\$[A-Za-z]{1,8}$`)
+)
+
+// Compile a "mention:@handle" keyword. It only matches messages whose
+// entities mention the handle, so "@brand" in a URL or code block does not.
+func handleRule(k string) (matchRule, error) {
+	ref, err := chatid.Parse(strings.TrimPrefix(k, handlePrefix))
+	if err != nil || !ref.IsUsername() {
+		return matchRule{}, errors.New("expected mention:@username")
+	}
+	return entityRule(k, kindMention, model.EntityMention, ref.Username), nil
+}
+
+// Build a rule matching message entities of the given kind and value
+func entityRule(k, kind, entity, value string) matchRule {
+	return matchRule{
+		original: k,
+		kind:     kind,
+		entity:   entity,
+		value:    strings.ToLower(value),
+		find:     func(string) []int { return nil },
+	}
+}
+
+// Return the byte offsets of the first match in msg, or nil
+func (r matchRule) locate(msg model.Message) []int {
+	if r.entity == "" {
+		return r.find(msg.Text)
+	}
+	for _, e := range msg.Entities {
+		if e.Kind == r.entity && e.Value == r.value {
+			return []int{e.Start, e.End}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/audit"
+	"github.com/h3nc4/TelegramScout/internal/notifier"
+	"github.com/h3nc4/TelegramScout/internal/temprules"
+)
+
+// How often expired rules are dropped from the engine
+const ruleExpiryInterval = time.Minute
+
+// Match the keywords added by /addkeyword until they expire. Call before Start.
+func (s *Scout) UseTemporaryRules(store *temprules.Store) {
+	s.temporary = store
+	s.refreshRules()
+}
+
+// Return when rule stops matching, by its own entry or else the earliest matching glob
+func (s *Scout) expiresAt(rule string) (time.Time, bool) {
+	var at time.Time
+	for pattern, t := range s.cfg.Monitoring.Expires {
+		if pattern == rule {
+			return t, true
+		}
+		// Regex rules are rarely valid globs, a malformed pattern just does not match
+		if ok, _ := path.Match(pattern, rule); ok && (at.IsZero() || t.Before(at)) {
+			at = t
+		}
+	}
+	return at, !at.IsZero()
+}
+
+// Report whether rule had expired by now
+func (s *Scout) expired(rule string, now time.Time) bool {
+	at, ok := s.expiresAt(rule)
+	return ok && !now.Before(at)
+}
+
+// Return the configured keywords that have not expired, followed by the temporary ones
+func (s *Scout) activeKeywords(keywords []string) []string {
+	now := time.Now()
+	var active []string
+	for _, k := range keywords {
+		if !s.expired(k, now) {
+			active = append(active, k)
+		}
+	}
+	if s.temporary != nil {
+		for _, r := range s.temporary.List() {
+			if !slices.Contains(active, r.Keyword) {
+				active = append(active, r.Keyword)
+			}
+		}
+	}
+	return active
+}
+
+// Point out expires entries already past, which only clutter the config
+func (s *Scout) warnExpired() {
+	now := time.Now()
+	for pattern, at := range s.cfg.Monitoring.Expires {
+		if !now.Before(at) {
+			s.log.Warn("Rule expired, remove it from the config", zap.String("rule", pattern), zap.Time("expires", at))
+		}
+	}
+}
+
+// Recompile the keywords when some expired or temporary ones changed
+func (s *Scout) refreshRules() {
+	s.engineMux.Lock()
+	defer s.engineMux.Unlock()
+
+	cur := s.engine.Load()
+	active := s.activeKeywords(s.keywords)
+	if slices.Equal(active, cur.keywords) {
+		return
+	}
+	for _, k := range cur.keywords {
+		if !slices.Contains(active, k) {
+			s.log.Info("Keyword expired or removed", zap.String("keyword", k))
+		}
+	}
+	e := s.rebuild(s.keywordEngine(active))
+	s.engine.Store(e)
+	s.log.Info("Keywords updated", zap.Int("rules", len(e.rules)), zap.Int("rejected", len(e.rejected)))
+}
+
+// Drop rules from the engine as they expire
+func (s *Scout) expireRules(ctx context.Context) {
+	ticker := time.NewTicker(ruleExpiryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.refreshRules()
+		}
+	}
+}
+
+// /addkeyword <keyword> <duration>
+func (s *Scout) addKeywordCommand(_ context.Context, cmd notifier.Command) (string, error) {
+	if len(cmd.Args) < 2 {
+		return "", fmt.Errorf("usage: /addkeyword <keyword> <duration>")
+	}
+	d, err := parsePeriod(cmd.Args[len(cmd.Args)-1])
+	if err != nil {
+		return "", err
+	}
+	keyword := strings.Join(cmd.Args[:len(cmd.Args)-1], " ")
+	if _, rejected := s.compileKeywords([]string{keyword}); len(rejected) > 0 {
+		return "", fmt.Errorf("invalid keyword: %s", rejected[0].Reason)
+	}
+
+	r, err := s.temporary.Add(keyword, d, cmd.User)
+	if err != nil {
+		s.log.Error("Failed to save temporary rules", zap.Error(err))
+	}
+	s.refreshRules()
+	s.log.Info("Temporary keyword added", zap.String("keyword", keyword), zap.Time("expires", r.Expires), zap.String("by", cmd.User))
+	s.record(audit.Entry{Actor: cmd.User, Source: audit.SourceCommand, Action: "addkeyword", Target: keyword, Detail: "until " + r.Expires.UTC().Format(time.RFC3339)})
+	return fmt.Sprintf("🆕 <b>%s</b> matches until %s", html.EscapeString(keyword), r.Expires.UTC().Format("2006-01-02 15:04 MST")), nil
+}
+
+// /removekeyword <keyword>
+func (s *Scout) removeKeywordCommand(_ context.Context, cmd notifier.Command) (string, error) {
+	if len(cmd.Args) == 0 {
+		return "", fmt.Errorf("usage: /removekeyword <keyword>")
+	}
+	keyword := strings.Join(cmd.Args, " ")
+	removed, err := s.temporary.Remove(keyword)
+	if err != nil {
+		s.log.Error("Failed to save temporary rules", zap.Error(err))
+	}
+	if !removed {
+		return "", fmt.Errorf("%s is not a temporary keyword", keyword)
+	}
+	s.refreshRules()
+	s.log.Info("Temporary keyword removed", zap.String("keyword", keyword), zap.String("by", cmd.User))
+	s.record(audit.Entry{Actor: cmd.User, Source: audit.SourceCommand, Action: "removekeyword", Target: keyword})
+	return fmt.Sprintf("🗑 <b>%s</b> no longer matches", html.EscapeString(keyword)), nil
+}
+
+// /tempkeywords
+func (s *Scout) tempKeywordsCommand(_ context.Context, _ notifier.Command) (string, error) {
+	list := s.temporary.List()
+	if len(list) == 0 {
+		return "No temporary keywords.", nil
+	}
+	lines := []string{"⏳ <b>Temporary keywords</b>"}
+	for _, r := range list {
+		lines = append(lines, fmt.Sprintf("• %s until %s (%s)",
+			html.EscapeString(r.Keyword), r.Expires.UTC().Format("2006-01-02 15:04 MST"), html.EscapeString(r.By)))
+	}
+	return strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,252 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Rule variants, as reported in explanations
+const (
+	kindRegex   = "regex"
+	kindPCRE    = "pcre"
+	kindGlob    = "glob"
+	kindRange   = "range"
+	kindPhrase  = "phrase"
+	kindWord    = "word"
+	kindImage   = "image"
+	kindFile    = "file"
+	kindDomain  = "domain"
+	kindMention = "mention"
+	kindHashtag = "hashtag"
+	kindCashtag = "cashtag"
+	kindSweep   = "sweep"
+)
+
+// Prepended to the reference name of image matches
+const imagePrefix = "image:"
+
+// Evaluation decisions recorded in debug mode
+const (
+	DecisionMatched  = "matched"
+	DecisionNearMiss = "near_miss"
+	DecisionNoMatch  = "no_match"
+	DecisionSkipped  = "skipped"
+)
+
+// Describe why an alert fired
+type Explanation struct {
+	Time      time.Time `json:"time"`
+	ChatID    int64     `json:"chat_id"`
+	ChatTitle string    `json:"chat_title"`
+	MsgID     int       `json:"msg_id"`
+	Keyword   string    `json:"keyword"`
+	Kind      string    `json:"kind"`
+	Start     int       `json:"start"`
+	End       int       `json:"end"`
+	Matched   string    `json:"matched"`
+	Tags      []string  `json:"tags,omitempty"`
+	Also      []string  `json:"also,omitempty"`  // Other rules matching, under matching.mode all
+	Stale     bool      `json:"stale,omitempty"` // Older than filters.max_age, alerted silently
+
+	// Named groups of regex rules, by name
+	Captures map[string]string `json:"captures,omitempty"`
+
+	// Populated only in debug mode
+	Evaluations []Evaluation `json:"evaluations,omitempty"`
+}
+
+// Record the decision taken for a single rule
+type Evaluation struct {
+	Keyword  string `json:"keyword"`
+	Kind     string `json:"kind"`
+	Decision string `json:"decision"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// Keep the most recent explanations in a fixed-size ring
+type explainLog struct {
+	mux     sync.RWMutex
+	entries []Explanation
+	next    int
+	full    bool
+}
+
+func newExplainLog(size int) *explainLog {
+	if size <= 0 {
+		size = 1
+	}
+	return &explainLog{entries: make([]Explanation, size)}
+}
+
+func (l *explainLog) add(e Explanation) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	l.entries[l.next] = e
+	l.next = (l.next + 1) % len(l.entries)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// Return entries newest first, optionally filtered by chat and tag
+func (l *explainLog) recent(chatID int64, tag string, limit int) []Explanation {
+	l.mux.RLock()
+	defer l.mux.RUnlock()
+
+	count := l.next
+	if l.full {
+		count = len(l.entries)
+	}
+
+	out := make([]Explanation, 0, min(count, max(limit, 0)))
+	for i := 1; i <= count && len(out) < limit; i++ {
+		e := l.entries[(l.next-i+len(l.entries))%len(l.entries)]
+		if chatID != 0 && e.ChatID != chatID {
+			continue
+		}
+		if tag != "" && !slices.Contains(e.Tags, tag) {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// Return up to limit recent explanations, newest first. A zero chatID matches
+// all chats and an empty tag all rules.
+func (s *Scout) Explanations(chatID int64, tag string, limit int) []Explanation {
+	return s.explanations.recent(chatID, tag, limit)
+}
+
+// Run the rules against a message, returning the explanation for the first match
+func (s *Scout) evaluate(msg model.Message) (Explanation, bool) {
+	return s.evaluateContent(msg, "")
+}
+
+// Evaluate msg, whose content hash is hash when already computed for dedup
+func (s *Scout) evaluateContent(msg model.Message, hash string) (Explanation, bool) {
+	return s.evaluateTrace(msg, hash, s.cfg.Explain.Debug)
+}
+
+// Evaluate msg, recording the decision for every rule with debug
+func (s *Scout) evaluateTrace(msg model.Message, hash string, debug bool) (Explanation, bool) {
+	exp := Explanation{
+		Time:      time.Now(),
+		ChatID:    msg.ChatID,
+		ChatTitle: msg.ChatTitle,
+		MsgID:     msg.ID,
+	}
+	matched := false
+
+	// Debug mode records a decision per rule, so it tries them one by one
+	e := s.engine.Load()
+	if debug {
+		matched = evaluateEach(e.rules, msg, &exp, s.cfg.Matching.Mode == config.MatchAll)
+	} else if i, loc := e.firstMatch(msg, hash); i >= 0 {
+		matched = true
+		setMatch(&exp, e.rules[i], msg.Text, loc)
+	}
+	matched = s.evaluateDomains(msg, &exp, matched, debug)
+	matched = s.evaluateFiles(msg, &exp, matched, debug)
+	matched = s.settleMatch(msg, &exp, matched)
+
+	return exp, matched
+}
+
+// Try the rules in order, recording the decision for each. With all, the
+// rules after the first match are still tried, only the first is reported.
+func evaluateEach(rules []matchRule, msg model.Message, exp *Explanation, all bool) bool {
+	matched := false
+	for _, rule := range rules {
+		if matched && !all {
+			exp.Evaluations = append(exp.Evaluations, Evaluation{
+				Keyword:  rule.original,
+				Kind:     rule.kind,
+				Decision: DecisionSkipped,
+			})
+			continue
+		}
+
+		loc := rule.locate(msg)
+		if loc == nil {
+			exp.Evaluations = append(exp.Evaluations, nearMiss(rule, msg.Text))
+			continue
+		}
+		if !matched {
+			setMatch(exp, rule, msg.Text, loc)
+		}
+		matched = true
+		exp.Evaluations = append(exp.Evaluations, Evaluation{
+			Keyword:  rule.original,
+			Kind:     rule.kind,
+			Decision: DecisionMatched,
+			Detail:   fmt.Sprintf("offsets %d-%d", loc[0], loc[1]),
+		})
+	}
+	return matched
+}
+
+// Record rule as the match found at loc in text
+func setMatch(exp *Explanation, rule matchRule, text string, loc []int) {
+	exp.Keyword = rule.original
+	exp.Kind = rule.kind
+	exp.Start, exp.End = loc[0], loc[1]
+	exp.Matched = text[loc[0]:loc[1]]
+	if rule.captures != nil {
+		exp.Captures = rule.captures(text)
+	}
+}
+
+// Classify a failed rule, flagging multi-term rules whose terms partially appear
+func nearMiss(rule matchRule, text string) Evaluation {
+	ev := Evaluation{
+		Keyword:  rule.original,
+		Kind:     rule.kind,
+		Decision: DecisionNoMatch,
+	}
+	if len(rule.terms) < 2 {
+		return ev
+	}
+
+	lowText := strings.ToLower(text)
+	found := 0
+	for _, term := range rule.terms {
+		if strings.Contains(lowText, term) {
+			found++
+		}
+	}
+	if found > 0 {
+		ev.Decision = DecisionNearMiss
+		ev.Detail = fmt.Sprintf("%d/%d terms present", found, len(rule.terms))
+	}
+	return ev
+}
+
+// Split a keyword into its lowercased literal terms
+func literalTerms(k string) []string {
+	return strings.Fields(strings.ToLower(k))
+}
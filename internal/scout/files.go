@@ -0,0 +1,110 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Prepended to the name of document rule matches
+const filePrefix = "file:"
+
+// Document rule with its patterns normalized for matching
+type fileRule struct {
+	name     string
+	filename string
+	mimeType string
+	minSize  int64
+	maxSize  int64
+}
+
+func newFileRules(rules []config.FileRule) []fileRule {
+	out := make([]fileRule, 0, len(rules))
+	for _, r := range rules {
+		out = append(out, fileRule{
+			name:     filePrefix + r.Label(),
+			filename: strings.ToLower(r.Filename),
+			mimeType: strings.ToLower(r.MimeType),
+			minSize:  r.MinSize,
+			maxSize:  r.MaxSize,
+		})
+	}
+	return out
+}
+
+// Report whether the document satisfies every criterion of the rule
+func (r fileRule) matches(f *model.File) bool {
+	if r.filename != "" {
+		if ok, _ := path.Match(r.filename, strings.ToLower(f.Name)); !ok {
+			return false
+		}
+	}
+	if r.mimeType != "" {
+		if ok, _ := path.Match(r.mimeType, strings.ToLower(f.MimeType)); !ok {
+			return false
+		}
+	}
+	if f.Size < r.minSize {
+		return false
+	}
+	return r.maxSize <= 0 || f.Size <= r.maxSize
+}
+
+// Run the document rules once no text rule matched
+func (s *Scout) evaluateFiles(msg model.Message, exp *Explanation, matched, debug bool) bool {
+	if msg.File == nil {
+		return matched
+	}
+
+	for _, rule := range s.fileRules {
+		if matched {
+			if !debug {
+				break
+			}
+			exp.Evaluations = append(exp.Evaluations, Evaluation{Keyword: rule.name, Kind: kindFile, Decision: DecisionSkipped})
+			continue
+		}
+
+		if !rule.matches(msg.File) {
+			if debug {
+				exp.Evaluations = append(exp.Evaluations, Evaluation{Keyword: rule.name, Kind: kindFile, Decision: DecisionNoMatch})
+			}
+			continue
+		}
+
+		matched = true
+		exp.Keyword = rule.name
+		exp.Kind = kindFile
+		exp.Matched = msg.File.Name
+		if debug {
+			exp.Evaluations = append(exp.Evaluations, Evaluation{
+				Keyword:  rule.name,
+				Kind:     kindFile,
+				Decision: DecisionMatched,
+				Detail:   fmt.Sprintf("%s %s %d bytes", msg.File.Name, msg.File.MimeType, msg.File.Size),
+			})
+		}
+	}
+	return matched
+}
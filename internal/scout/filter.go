@@ -0,0 +1,137 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/h3nc4/TelegramScout/internal/chatid"
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Message is nothing but links separated by whitespace
+var linkOnlyPattern = regexp.MustCompile(`(?i)^(?:\s*(?:https?://|www\.|t\.me/)\S+)+\s*$`)
+
+// Filters and priority that apply to a specific chat
+type chatFilter struct {
+	ref      chatid.Ref
+	filters  config.FiltersConfig
+	priority string
+}
+
+// Parse the per-chat overrides so messages can be matched to them
+func newChatFilters(settings map[string]config.ChatSettings) []chatFilter {
+	var filters []chatFilter
+	for chat, cs := range settings {
+		// Entries are validated when the config is loaded
+		ref, err := chatid.Parse(chat)
+		if err != nil {
+			continue
+		}
+		filters = append(filters, chatFilter{ref: ref, filters: cs.Filters, priority: cs.Priority})
+	}
+	return filters
+}
+
+// Return the filters for the message's chat, falling back to the global ones
+func (s *Scout) filtersFor(msg model.Message) config.FiltersConfig {
+	if cf := s.chatFilterFor(msg); cf != nil {
+		return cf.filters
+	}
+	return s.cfg.Filters
+}
+
+// Return the chat_settings entry of the message's chat, or nil
+func (s *Scout) chatFilterFor(msg model.Message) *chatFilter {
+	kind, id := chatid.FromBotAPI(msg.ChatID)
+	for i, cf := range s.chatFilters {
+		if cf.ref.Matches(kind, id) || cf.ref.MatchesUsername(msg.Username) {
+			return &s.chatFilters[i]
+		}
+	}
+	return nil
+}
+
+// Return why the message is skipped before rule evaluation, or "" to evaluate it
+func (s *Scout) filtered(msg model.Message) string {
+	f := s.filtersFor(msg)
+	text := strings.TrimSpace(msg.Text)
+	length := utf8.RuneCountInString(text)
+
+	switch {
+	case f.IgnoreOutgoing && msg.Outgoing:
+		return "outgoing"
+	case f.IgnoreBots && msg.FromBot:
+		return "bot"
+	case f.SkipEmojiOnly && emojiOnly(msg.Media, text):
+		return "emoji_only"
+	case f.SkipLinkOnly && linkOnlyPattern.MatchString(text):
+		return "link_only"
+	case f.MinLength > 0 && length < f.MinLength:
+		return "too_short"
+	case f.MaxLength > 0 && length > f.MaxLength:
+		return "too_long"
+	case s.allowedLinks(text):
+		return "allowed_domains"
+	case f.Stale != config.StaleLabel && tooOld(msg, f):
+		return "stale"
+	}
+	return ""
+}
+
+// Report whether msg was sent longer ago than the filters allow
+func tooOld(msg model.Message, f config.FiltersConfig) bool {
+	return f.MaxAge > 0 && !msg.Date.IsZero() && time.Since(msg.Date) > f.MaxAge
+}
+
+// Report whether a message carries nothing but emoji, a sticker or a dice roll
+func emojiOnly(media, text string) bool {
+	switch media {
+	case "sticker", "dice":
+		return text == ""
+	case "":
+	default:
+		return false
+	}
+	if text == "" {
+		return false
+	}
+
+	for _, r := range text {
+		switch {
+		case unicode.IsSpace(r):
+		case unicode.Is(unicode.So, r), unicode.Is(unicode.Sk, r):
+			// Pictographs and skin tone modifiers
+		case r == '\u200d', unicode.Is(unicode.Variation_Selector, r):
+			// Joiners and presentation selectors inside emoji sequences
+		case r >= 0x1F1E6 && r <= 0x1F1FF:
+			// Regional indicators forming flags
+		case r >= 0xE0020 && r <= 0xE007F:
+			// Tag sequences for subdivision flags
+		default:
+			return false
+		}
+	}
+	return true
+}
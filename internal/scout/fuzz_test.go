@@ -0,0 +1,143 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/model"
+	"github.com/h3nc4/TelegramScout/internal/render"
+)
+
+// Rules and messages the fuzzers start from, covering every rule kind
+var fuzzSeeds = []struct{ keywords, text string }{
+	{"urgent", "This is URGENT!"},
+	{"rtx 5090", "selling an RTX\n5090 today"},
+	{"deal*today", "great DEAL for you, only today"},
+	{`re:(?i)price:\s*(?P<price>\d+)`, "Price: 120 BRL"},
+	{"re:(unclosed", "anything"},
+	{"İstanbul", "i̇stanbul ve İSTANBUL"},
+	{"ß", "STRASSE straße"},
+	{"*", ""},
+	{"a\nb\nre:[", "b"},
+	{"", "\xff\xfe invalid utf-8"},
+}
+
+// Compiling arbitrary keywords never panics and accounts for every keyword
+func FuzzCompileRules(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed.keywords)
+	}
+	f.Fuzz(func(t *testing.T, keywords string) {
+		list := strings.Split(keywords, "\n")
+		s := New(&config.Config{Monitoring: config.MonitoringRules{Keywords: list}}, &MockNotifier{}, zap.NewNop())
+		compiled, rejected := s.Rules()
+		if compiled+len(rejected) != len(list) {
+			t.Errorf("%d keywords compiled to %d rules and %d rejections", len(list), compiled, len(rejected))
+		}
+	})
+}
+
+// Matching arbitrary text against arbitrary rules never panics and reports
+// offsets of the matched text
+func FuzzMatch(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed.keywords, seed.text)
+	}
+	f.Fuzz(func(t *testing.T, keywords, text string) {
+		cfg := &config.Config{
+			Monitoring: config.MonitoringRules{Keywords: strings.Split(keywords, "\n")},
+			Explain:    config.ExplainConfig{Debug: true},
+		}
+		s := New(cfg, &MockNotifier{}, zap.NewNop())
+		msg := model.Message{ID: 1, ChatID: 100, ChatTitle: "Fuzz", Text: text}
+
+		exp, ok := s.evaluate(msg)
+		if !ok {
+			return
+		}
+		if exp.Start < 0 || exp.Start > exp.End || exp.End > len(text) {
+			t.Fatalf("rule %q reported offsets %d-%d in %d bytes", exp.Keyword, exp.Start, exp.End, len(text))
+		}
+		if exp.Matched != text[exp.Start:exp.End] {
+			t.Errorf("rule %q reported %q, the offsets hold %q", exp.Keyword, exp.Matched, text[exp.Start:exp.End])
+		}
+		if utf8.ValidString(text) && !utf8.ValidString(exp.Matched) {
+			t.Errorf("rule %q split a character: %q", exp.Keyword, exp.Matched)
+		}
+		_ = render.Alert(s.matchFor(msg, exp), nil)
+	})
+}
+
+// Every engine reports the same rule as trying the keywords in order
+func FuzzEngines(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed.keywords, seed.text)
+	}
+	f.Fuzz(func(t *testing.T, keywords, text string) {
+		cfg := &config.Config{Monitoring: config.MonitoringRules{Keywords: strings.Split(keywords, "\n")}}
+		s := New(cfg, &MockNotifier{}, zap.NewNop())
+		msg := model.Message{Text: text}
+		want, _ := naiveMatcher(s.engine.Load().rules).first(msg)
+
+		for _, name := range []string{config.EngineAhoCorasick, config.EngineRegex} {
+			e, err := newEngine(name, s.engine.Load().rules)
+			if err != nil {
+				// Combining can fail where each keyword compiles, such as when
+				// named groups repeat with different patterns
+				continue
+			}
+			if got, _ := e.first(msg); got != want {
+				t.Errorf("%s matched rule %d, in order rule %d matches", name, got, want)
+			}
+		}
+	})
+}
+
+// Rule panics drop the message instead of stopping the pipeline
+func TestScout_GuardedProcess(t *testing.T) {
+	notif := &MockNotifier{NotifyChan: make(chan string, 1)}
+	s := New(&config.Config{Monitoring: config.MonitoringRules{Keywords: []string{"boom", "urgent"}}}, notif, zap.NewNop())
+	s.engine.Load().rules[0].find = func(text string) []int {
+		if text == "boom" {
+			panic("broken rule")
+		}
+		return nil
+	}
+
+	input := make(chan model.Message)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Start(ctx, input)
+
+	input <- model.Message{ID: 1, ChatID: 100, Text: "boom"}
+	input <- model.Message{ID: 2, ChatID: 100, Text: "urgent"}
+	select {
+	case <-notif.NotifyChan:
+	case <-time.After(time.Second):
+		t.Fatal("expected the pipeline to keep running after a panic")
+	}
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/h3nc4/TelegramScout/internal/graph"
+	"github.com/h3nc4/TelegramScout/internal/mentions"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Record forwards and mentions between chats into g. Call before Start.
+func (s *Scout) UseGraph(g *graph.Graph) {
+	s.graph = g
+}
+
+// Return the node of a chat, keyed by username when it has one so mentions line up with it
+func chatNode(id int64, title, username string) graph.Node {
+	if username != "" {
+		return graph.Node{ID: "@" + strings.ToLower(username), Label: title}
+	}
+	return graph.Node{ID: strconv.FormatInt(id, 10), Label: title}
+}
+
+// Add the message's forward origin and referenced chats to the graph
+func (s *Scout) recordRelations(msg model.Message) {
+	if s.graph == nil {
+		return
+	}
+	chat := chatNode(msg.ChatID, msg.ChatTitle, msg.Username)
+
+	if f := msg.Forward; f != nil {
+		origin := chatNode(f.ChatID, f.Title, f.Username)
+		if f.ChatID == 0 && f.Username == "" {
+			// Authors hiding their account are only known by name
+			origin.ID = "name:" + f.Title
+		}
+		s.graph.Add(origin, chat, graph.KindForward, msg.Date)
+	}
+	for _, ref := range mentions.Extract(msg.Text) {
+		s.graph.Add(chat, graph.Node{ID: ref.String()}, graph.KindMention, msg.Date)
+	}
+}
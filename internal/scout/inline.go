@@ -0,0 +1,116 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/h3nc4/TelegramScout/internal/model"
+	"github.com/h3nc4/TelegramScout/internal/notifier"
+)
+
+// Matched messages kept for inline queries
+const inlineHistory = 200
+
+// A matched message offered by inline queries
+type recentMatch struct {
+	time      time.Time
+	rule      string
+	chatTitle string
+	text      string
+	link      string
+}
+
+// Keep the most recent matches in a fixed-size ring
+type matchLog struct {
+	mux     sync.RWMutex
+	entries []recentMatch
+	next    int
+	full    bool
+}
+
+func newMatchLog(size int) *matchLog {
+	return &matchLog{entries: make([]recentMatch, size)}
+}
+
+func (l *matchLog) add(m recentMatch) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	l.entries[l.next] = m
+	l.next = (l.next + 1) % len(l.entries)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// Return up to limit matches whose rule, chat or text contains query, newest first
+func (l *matchLog) find(query string, limit int) []recentMatch {
+	l.mux.RLock()
+	defer l.mux.RUnlock()
+
+	count := l.next
+	if l.full {
+		count = len(l.entries)
+	}
+	query = strings.ToLower(strings.TrimSpace(query))
+
+	var out []recentMatch
+	for i := 1; i <= count && len(out) < limit; i++ {
+		m := l.entries[(l.next-i+len(l.entries))%len(l.entries)]
+		if query == "" || strings.Contains(strings.ToLower(m.rule+"\n"+m.chatTitle+"\n"+m.text), query) {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// Remember a match for inline queries, when they are enabled
+func (s *Scout) rememberMatch(msg model.Message, exp Explanation) {
+	if s.matches == nil {
+		return
+	}
+	s.matches.add(recentMatch{time: exp.Time, rule: exp.Keyword, chatTitle: msg.ChatTitle, text: msg.Text, link: msg.Link})
+}
+
+// Answer an inline query with recent matches containing query, all of them when it is empty
+func (s *Scout) InlineQuery(_ context.Context, query string) ([]notifier.InlineResult, error) {
+	if s.matches == nil {
+		return nil, fmt.Errorf("inline queries are disabled")
+	}
+	found := s.matches.find(query, s.cfg.Commands.SearchLimit)
+	results := make([]notifier.InlineResult, len(found))
+	for i, m := range found {
+		text := fmt.Sprintf("🔎 <b>%s</b> in <b>%s</b> %s\n%s",
+			html.EscapeString(m.rule), html.EscapeString(m.chatTitle), m.time.UTC().Format("2006-01-02 15:04"), html.EscapeString(snippet(m.text)))
+		if m.link != "" {
+			text += fmt.Sprintf("\n🔗 <a href=\"%s\">Link to Message</a>", html.EscapeString(m.link))
+		}
+		results[i] = notifier.InlineResult{
+			Title:       m.chatTitle + ": " + m.rule,
+			Description: snippet(m.text),
+			Text:        text,
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Fetch single messages by their t.me link
+type MessageFetcher interface {
+	// Report false when the message does not exist or was deleted
+	Message(ctx context.Context, link string) (model.Message, bool, error)
+}
+
+// Fetch messages for Inspect through f. Call before Start.
+func (s *Scout) UseMessageFetcher(f MessageFetcher) {
+	s.fetcher = f
+}
+
+// What the pipeline would do with a message now, as reported by Inspect
+type Inspection struct {
+	Message model.Message `json:"message"`
+	// Why prefilters skip the message, empty when it reaches the rules
+	Filtered string `json:"filtered,omitempty"`
+	// The message was already alerted on by this instance
+	Duplicate bool `json:"duplicate,omitempty"`
+	// The first matching rule and the decision taken for every rule, traced
+	// even when prefilters skip the message
+	Explanation Explanation `json:"explanation"`
+	Matched     bool        `json:"matched"`
+	// The matched rule is snoozed in the message's chat
+	Snoozed bool `json:"snoozed,omitempty"`
+	// Shadow and counter rules matching the message
+	Shadow   []string `json:"shadow,omitempty"`
+	Counters []string `json:"counters,omitempty"`
+}
+
+// Fetch the message at link and trace it through the current rules without
+// alerting, archiving or counting it, for finding out why an alert did or did
+// not fire. Images and short links are matched too, mentions are not.
+func (s *Scout) Inspect(ctx context.Context, link string) (Inspection, error) {
+	if s.fetcher == nil {
+		return Inspection{}, errors.New("message lookup is not available")
+	}
+	msg, found, err := s.fetcher.Message(ctx, link)
+	if err != nil {
+		return Inspection{}, fmt.Errorf("failed to fetch %s: %w", link, err)
+	}
+	if !found {
+		return Inspection{}, fmt.Errorf("message %s not found or deleted", link)
+	}
+
+	in := Inspection{
+		Message:   msg,
+		Filtered:  s.filtered(msg),
+		Duplicate: s.recent.contains(msg.ChatID, msg.ID),
+	}
+	in.Explanation, in.Matched = s.evaluateTrace(msg, "", true)
+	if !in.Matched {
+		late, ok := s.matchImage(ctx, msg)
+		if !ok {
+			late, ok = s.matchExpandedLinks(ctx, msg)
+		}
+		if ok {
+			in.Explanation.Keyword, in.Explanation.Kind, in.Explanation.Matched = late.Keyword, late.Kind, late.Matched
+			in.Matched = true
+		}
+	}
+	if in.Matched {
+		in.Explanation.Tags = s.tagsFor(in.Explanation.Keyword)
+		in.Snoozed = s.snoozed(in.Explanation.Keyword, msg.ChatID)
+	}
+	for _, rule := range s.shadow {
+		if rule.locate(msg) != nil {
+			in.Shadow = append(in.Shadow, rule.original)
+		}
+	}
+	for _, rule := range s.counters {
+		if rule.locate(msg) != nil {
+			in.Counters = append(in.Counters, rule.original)
+		}
+	}
+	return in, nil
+}
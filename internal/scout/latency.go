@@ -0,0 +1,125 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/logger"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Alert delivery latency percentiles in milliseconds, served at /debug/vars
+// and pushed with the other counters
+var latencyMetrics = expvar.NewMap("latency")
+
+const (
+	// Deliveries the percentiles are computed over
+	latencySamples = 1024
+	// Least time between two lag warnings sent as notifications
+	latencyNoticeInterval = 15 * time.Minute
+)
+
+// The latest alert delivery times, both from posting and from receipt
+type latencyTracker struct {
+	mux        sync.Mutex
+	total      []time.Duration // Posted to delivered, includes Telegram's own delay
+	pipeline   []time.Duration // Received to delivered
+	next       int
+	lastNotice time.Time
+}
+
+// Add a delivery, returning whether a lag notice is due for it
+func (l *latencyTracker) add(total, pipeline time.Duration, over bool, now time.Time) bool {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	if len(l.total) < latencySamples {
+		l.total = append(l.total, total)
+		l.pipeline = append(l.pipeline, pipeline)
+	} else {
+		l.total[l.next] = total
+		l.pipeline[l.next] = pipeline
+		l.next = (l.next + 1) % latencySamples
+	}
+	publishPercentiles("total", l.total)
+	publishPercentiles("pipeline", l.pipeline)
+
+	if !over || now.Sub(l.lastNotice) < latencyNoticeInterval {
+		return false
+	}
+	l.lastNotice = now
+	return true
+}
+
+// Set the 50th, 90th and 99th percentiles and the maximum of d
+func publishPercentiles(name string, d []time.Duration) {
+	sorted := slices.Sorted(slices.Values(d))
+	for _, p := range []struct {
+		key string
+		q   float64
+	}{{"p50", 0.5}, {"p90", 0.9}, {"p99", 0.99}, {"max", 1}} {
+		v := new(expvar.Float)
+		v.Set(float64(sorted[int(p.q*float64(len(sorted)-1))]) / float64(time.Millisecond))
+		latencyMetrics.Set(name+"_"+p.key+"_ms", v)
+	}
+}
+
+// Record how long a delivered alert took, warning when it went over the budget.
+// Only messages from the live client are measured, history is late by design.
+func (s *Scout) recordLatency(ctx context.Context, msg model.Message) {
+	if msg.Received.IsZero() {
+		return
+	}
+	now := time.Now()
+	total, pipeline := now.Sub(msg.Date), now.Sub(msg.Received)
+	// Dates have a one second resolution, so fast deliveries can look early
+	total = max(total, pipeline)
+
+	latencyMetrics.Add("alerts", 1)
+	budget := s.cfg.Pipeline.LatencyBudget
+	over := budget > 0 && total > budget
+	notice := s.latency.add(total, pipeline, over, now)
+	if !over {
+		return
+	}
+	latencyMetrics.Add("over_budget", 1)
+	s.log.Warn("Alert delivered over the latency budget",
+		zap.Duration("latency", total),
+		zap.Duration("pipeline", pipeline),
+		zap.Duration("budget", budget),
+		zap.Int64("chat_id", msg.ChatID),
+		zap.Int("msg_id", msg.ID),
+		logger.TraceField(msg.TraceID),
+	)
+	if !notice {
+		return
+	}
+	text := fmt.Sprintf("⏱ <b>Alerts are lagging:</b> delivered %s after posting, %s of it in the pipeline, over the %s budget",
+		total.Round(time.Second), pipeline.Round(time.Millisecond), budget)
+	if err := s.notifier.Send(ctx, text); err != nil {
+		s.log.Warn("Failed to send latency warning", zap.Error(err))
+	}
+}
@@ -0,0 +1,145 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"context"
+	"fmt"
+	"html"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/model"
+	"github.com/h3nc4/TelegramScout/internal/urls"
+)
+
+// Prepended to the domain of link rule matches
+const domainPrefix = "domain:"
+
+// Most short links expanded per message
+const maxExpandedLinks = 5
+
+// Resolve short links to their destination
+type LinkExpander interface {
+	IsShort(link string) bool
+	Expand(ctx context.Context, link string) (string, error)
+}
+
+// Expand short links through e, both for alerts and block_domains. Call before Start.
+func (s *Scout) UseLinkExpander(e LinkExpander) {
+	s.expander = e
+}
+
+// Report whether the text has links and all of them point to allowed domains
+func (s *Scout) allowedLinks(text string) bool {
+	if len(s.cfg.URLs.AllowDomains) == 0 {
+		return false
+	}
+	links := urls.Extract(text)
+	for _, link := range links {
+		if urls.MatchDomain(urls.Host(link), s.cfg.URLs.AllowDomains) == "" {
+			return false
+		}
+	}
+	return len(links) > 0
+}
+
+// Check links as written against block_domains once no text rule matched
+func (s *Scout) evaluateDomains(msg model.Message, exp *Explanation, matched, debug bool) bool {
+	blocked := s.cfg.URLs.BlockDomains
+	if len(blocked) == 0 {
+		return matched
+	}
+	if matched {
+		if debug {
+			exp.Evaluations = append(exp.Evaluations, Evaluation{Keyword: domainPrefix + "*", Kind: kindDomain, Decision: DecisionSkipped})
+		}
+		return true
+	}
+
+	for _, link := range urls.Extract(msg.Text) {
+		if d := urls.MatchDomain(urls.Host(link), blocked); d != "" {
+			exp.Keyword = domainPrefix + d
+			exp.Kind = kindDomain
+			exp.Matched = link
+			if debug {
+				exp.Evaluations = append(exp.Evaluations, Evaluation{Keyword: exp.Keyword, Kind: kindDomain, Decision: DecisionMatched, Detail: link})
+			}
+			return true
+		}
+	}
+	if debug {
+		exp.Evaluations = append(exp.Evaluations, Evaluation{Keyword: domainPrefix + "*", Kind: kindDomain, Decision: DecisionNoMatch})
+	}
+	return false
+}
+
+// Link as written in the message and where it leads
+type expandedLink struct {
+	short string
+	final string
+}
+
+// Resolve the short links of the message
+func (s *Scout) expandLinks(ctx context.Context, msg model.Message) []expandedLink {
+	if s.expander == nil {
+		return nil
+	}
+
+	var out []expandedLink
+	for _, link := range urls.Extract(msg.Text) {
+		if len(out) == maxExpandedLinks {
+			break
+		}
+		if !s.expander.IsShort(link) {
+			continue
+		}
+		final, err := s.expander.Expand(ctx, link)
+		if err != nil {
+			s.log.Debug("Failed to expand link", zap.String("link", link), zap.Error(err))
+			continue
+		}
+		if final != link {
+			out = append(out, expandedLink{short: link, final: final})
+		}
+	}
+	return out
+}
+
+// Match the destinations of short links against block_domains
+func (s *Scout) matchExpandedLinks(ctx context.Context, msg model.Message) (Explanation, bool) {
+	if len(s.cfg.URLs.BlockDomains) == 0 {
+		return Explanation{}, false
+	}
+	for _, l := range s.expandLinks(ctx, msg) {
+		if d := urls.MatchDomain(urls.Host(l.final), s.cfg.URLs.BlockDomains); d != "" {
+			return Explanation{Keyword: domainPrefix + d, Kind: kindDomain, Matched: l.final}, true
+		}
+	}
+	return Explanation{}, false
+}
+
+// Format the destinations of short links as alert lines, shown as text so they are not clicked blind
+func (s *Scout) expandedLinks(ctx context.Context, msg model.Message) []string {
+	var lines []string
+	for _, l := range s.expandLinks(ctx, msg) {
+		lines = append(lines, fmt.Sprintf("🔀 %s → <code>%s</code>", html.EscapeString(l.short), html.EscapeString(l.final)))
+	}
+	return lines
+}
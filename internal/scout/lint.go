@@ -0,0 +1,231 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"fmt"
+	"regexp/syntax"
+	"strings"
+	"unicode"
+)
+
+// Describe a rule that is redundant or more complex than needed
+type Finding struct {
+	Keyword    string `json:"keyword"`
+	Problem    string `json:"problem"`
+	Suggestion string `json:"suggestion"`
+}
+
+// Report keywords that duplicate or can never outrank an earlier keyword,
+// shadow rules that duplicate a keyword, and regexes equivalent to plain
+// keywords. Only the first matching keyword is reported for a message, so a
+// keyword whose every match is also matched by an earlier one never fires.
+// The check is conservative: it misses overlaps between regexes and globs.
+func (s *Scout) Lint() []Finding {
+	var findings []Finding
+	rules := s.engine.Load().rules
+	for i, rule := range rules {
+		if f, ok := lintRegex(rule); ok {
+			findings = append(findings, f)
+		}
+		for _, earlier := range rules[:i] {
+			if f, ok := lintPair(earlier, rule); ok {
+				findings = append(findings, f)
+				break
+			}
+		}
+	}
+
+	for _, rule := range s.shadow {
+		for _, live := range rules {
+			if sameRule(live, rule) {
+				findings = append(findings, Finding{
+					Keyword:    rule.original,
+					Problem:    fmt.Sprintf("shadow rule duplicates keyword %q", live.original),
+					Suggestion: "remove it from shadow, it would add no alerts",
+				})
+				break
+			}
+		}
+	}
+	return findings
+}
+
+// Check whether earlier makes later redundant
+func lintPair(earlier, later matchRule) (Finding, bool) {
+	if sameRule(earlier, later) {
+		return Finding{
+			Keyword:    later.original,
+			Problem:    fmt.Sprintf("duplicate of %q", earlier.original),
+			Suggestion: "remove it",
+		}, true
+	}
+	if covers(earlier, later) {
+		return Finding{
+			Keyword:    later.original,
+			Problem:    fmt.Sprintf("never fires, every message it matches is matched by %q first", earlier.original),
+			Suggestion: fmt.Sprintf("remove it, or move it above %q to report it by name", earlier.original),
+		}, true
+	}
+	return Finding{}, false
+}
+
+// Report whether two rules match exactly the same messages
+func sameRule(a, b matchRule) bool {
+	if a.entity != "" || b.entity != "" {
+		return a.entity == b.entity && a.value == b.value
+	}
+	if a.original == b.original {
+		return true
+	}
+	// Text rules other than regexes ignore case and whitespace runs
+	la, oka := ruleLiteral(a)
+	lb, okb := ruleLiteral(b)
+	return oka && okb && la == lb
+}
+
+// Report whether every message later matches is also matched by earlier
+func covers(earlier, later matchRule) bool {
+	if earlier.kind == kindGlob && strings.Trim(earlier.original, "* ") == "" {
+		// A bare wildcard matches every message, even without text
+		return true
+	}
+	lit, ok := ruleLiteral(earlier)
+	if !ok {
+		return false
+	}
+	for _, required := range requiredText(later) {
+		if strings.Contains(required, lit) {
+			return true
+		}
+	}
+	return false
+}
+
+// Return the normalized text a rule matches as a substring, if it is such a rule
+func ruleLiteral(r matchRule) (string, bool) {
+	switch r.kind {
+	case kindWord, kindPhrase:
+		return normalizeLiteral(r.original), true
+	case kindRegex:
+		// Phrases match any whitespace between words, a regex only what it spells
+		lit, fold, ok := regexLiteral(r.pattern)
+		if ok && fold && !strings.ContainsFunc(lit, unicode.IsSpace) {
+			return normalizeLiteral(lit), true
+		}
+	}
+	return "", false
+}
+
+// Return normalized text that appears in every message the rule matches
+func requiredText(r matchRule) []string {
+	if r.wordwise {
+		// Matches word by word need not spell the keyword
+		return nil
+	}
+	switch r.kind {
+	case kindWord, kindPhrase:
+		lit, _ := ruleLiteral(r)
+		return []string{lit}
+	case kindRegex:
+		if lit, _, ok := regexLiteral(r.pattern); ok {
+			return []string{normalizeLiteral(lit)}
+		}
+	case kindGlob:
+		var parts []string
+		for part := range strings.SplitSeq(r.original, "*") {
+			if part = normalizeLiteral(part); part != "" {
+				parts = append(parts, part)
+			}
+		}
+		return parts
+	case kindHashtag, kindCashtag:
+		// The entity is the tag as written, '#' or '$' included
+		return []string{normalizeLiteral(r.original)}
+	}
+	return nil
+}
+
+// Lowercase and collapse whitespace, as word and phrase rules compare text
+func normalizeLiteral(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+// Suggest plain keywords for regexes that are only literals
+func lintRegex(r matchRule) (Finding, bool) {
+	if r.kind != kindRegex || r.captures != nil {
+		return Finding{}, false
+	}
+	re, err := syntax.Parse(r.pattern, syntax.Perl)
+	if err != nil {
+		return Finding{}, false
+	}
+	re = re.Simplify()
+
+	var alts []*syntax.Regexp
+	if re.Op == syntax.OpAlternate {
+		alts = re.Sub
+	} else {
+		alts = []*syntax.Regexp{re}
+	}
+	var keywords []string
+	for _, alt := range alts {
+		// Keywords with spaces or '*' are phrases and globs, which match more
+		lit, ok := literalOf(alt)
+		if !ok || strings.ContainsFunc(lit, unicode.IsSpace) || strings.Contains(lit, "*") {
+			return Finding{}, false
+		}
+		keywords = append(keywords, fmt.Sprintf("%q", lit))
+	}
+
+	if len(keywords) == 1 {
+		return Finding{
+			Keyword:    r.original,
+			Problem:    "regex only matches a literal, ignoring case",
+			Suggestion: "use the keyword " + keywords[0] + " instead",
+		}, true
+	}
+	return Finding{
+		Keyword:    r.original,
+		Problem:    "regex only matches one of several literals, ignoring case",
+		Suggestion: "use the keywords " + strings.Join(keywords, ", ") + " instead",
+	}, true
+}
+
+// Return the text a parsed regex matches and whether it ignores case
+func regexLiteral(pattern string) (string, bool, bool) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", false, false
+	}
+	re = re.Simplify()
+	if re.Op != syntax.OpLiteral {
+		return "", false, false
+	}
+	return string(re.Rune), re.Flags&syntax.FoldCase != 0, true
+}
+
+// Return the literal a case-insensitive regex node matches. Case-sensitive
+// literals are stricter than keywords, which always ignore case.
+func literalOf(re *syntax.Regexp) (string, bool) {
+	if re.Op != syntax.OpLiteral || re.Flags&syntax.FoldCase == 0 {
+		return "", false
+	}
+	return strings.ToLower(string(re.Rune)), true
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"context"
+	"fmt"
+	"html"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/logger"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Store media of matched messages somewhere durable
+type MediaArchiver interface {
+	// Upload the message's media and return its URL, or "" if it has none
+	Archive(ctx context.Context, msg model.Message) (string, error)
+}
+
+// Archive media of matched messages through a. Call before Start.
+func (s *Scout) UseMediaArchiver(a MediaArchiver) {
+	s.archiver = a
+}
+
+// Collect the lines media processing adds to the alert
+func (s *Scout) attachments(ctx context.Context, msg model.Message) []string {
+	var lines []string
+	if url := s.archiveMedia(ctx, msg); url != "" {
+		lines = append(lines, fmt.Sprintf("📎 <a href=\"%s\">Archived Media</a>", html.EscapeString(url)))
+	}
+	lines = append(lines, s.enrich(ctx, msg)...)
+	lines = append(lines, s.expandedLinks(ctx, msg)...)
+	lines = append(lines, s.mentionedChats(ctx, msg)...)
+	return append(lines, s.screenshots(ctx, msg)...)
+}
+
+// Archive the message's media, returning "" when disabled or on failure
+func (s *Scout) archiveMedia(ctx context.Context, msg model.Message) string {
+	if s.archiver == nil || msg.Media == "" {
+		return ""
+	}
+	url, err := s.archiver.Archive(ctx, msg)
+	if err != nil {
+		// Still alert without the archived copy
+		s.log.Error("Failed to archive media", zap.Int64("chat_id", msg.ChatID), zap.Int("msg_id", msg.ID), logger.TraceField(msg.TraceID), zap.Error(err))
+		return ""
+	}
+	return url
+}
+
+// Match message images against reference images
+type ImageMatcher interface {
+	// Return the name of the closest matching reference and its distance, or "" if none matches
+	MatchImage(ctx context.Context, msg model.Message) (string, int, error)
+}
+
+// Match images of messages no text rule matched through m. Call before Start.
+func (s *Scout) UseImageMatcher(m ImageMatcher) {
+	s.images = m
+}
+
+// Hash the message's image and compare it against the references
+func (s *Scout) matchImage(ctx context.Context, msg model.Message) (Explanation, bool) {
+	if s.images == nil || msg.Media == "" {
+		return Explanation{}, false
+	}
+	name, distance, err := s.images.MatchImage(ctx, msg)
+	if err != nil {
+		s.log.Debug("Failed to match image", zap.Int64("chat_id", msg.ChatID), zap.Int("msg_id", msg.ID), logger.TraceField(msg.TraceID), zap.Error(err))
+		return Explanation{}, false
+	}
+	if name == "" {
+		return Explanation{}, false
+	}
+	return Explanation{
+		Keyword: imagePrefix + name,
+		Kind:    kindImage,
+		Matched: fmt.Sprintf("distance %d", distance),
+	}, true
+}
@@ -0,0 +1,189 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/mentions"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Prepended to the referenced chat of mention matches
+const mentionPrefix = "mention:"
+
+// Most referenced chats looked up per message
+const maxMentions = 5
+
+// Referenced chats remembered before stale entries are pruned
+const mentionCacheSize = 4096
+
+// Look up chats referenced by t.me links and @mentions
+type ChatResolver interface {
+	ResolveChat(ctx context.Context, ref mentions.Ref) (mentions.Info, error)
+}
+
+// Report chats referenced in messages, looked up through r. Call before Start.
+func (s *Scout) UseChatResolver(r ChatResolver) {
+	cooldown := s.cfg.Mentions.Cooldown
+	if cooldown <= 0 {
+		cooldown = config.DefaultMentionsCooldown
+	}
+	s.resolver = r
+	s.mentioned = newMentionCache(cooldown)
+}
+
+// Outcome of looking up a referenced chat
+type mentionEntry struct {
+	info     mentions.Info
+	err      error
+	resolved time.Time
+	reported time.Time
+}
+
+// Remember lookups and reports for a cooldown, sparing lookups that Telegram rate limits heavily
+type mentionCache struct {
+	mux      sync.Mutex
+	cooldown time.Duration
+	entries  map[mentions.Ref]*mentionEntry
+}
+
+func newMentionCache(cooldown time.Duration) *mentionCache {
+	return &mentionCache{cooldown: cooldown, entries: make(map[mentions.Ref]*mentionEntry)}
+}
+
+// Return the cached lookup of ref, if still fresh
+func (c *mentionCache) get(ref mentions.Ref) (*mentionEntry, bool) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	e, ok := c.entries[ref]
+	if !ok || time.Since(e.resolved) > c.cooldown {
+		return nil, false
+	}
+	return e, true
+}
+
+// Store a lookup, keeping when the chat was last reported
+func (c *mentionCache) put(ref mentions.Ref, info mentions.Info, err error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	e := &mentionEntry{info: info, err: err, resolved: time.Now()}
+	if old, ok := c.entries[ref]; ok {
+		e.reported = old.reported
+	}
+	if len(c.entries) >= mentionCacheSize {
+		c.prune()
+	}
+	if len(c.entries) >= mentionCacheSize {
+		// Everything is fresh, start over rather than grow without bound
+		c.entries = make(map[mentions.Ref]*mentionEntry)
+	}
+	c.entries[ref] = e
+}
+
+// Mark ref as reported, returning false if it already was within the cooldown
+func (c *mentionCache) report(ref mentions.Ref) bool {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	e, ok := c.entries[ref]
+	if !ok || time.Since(e.reported) <= c.cooldown {
+		return false
+	}
+	e.reported = time.Now()
+	return true
+}
+
+// Drop entries neither looked up nor reported within the cooldown. Call with mux held.
+func (c *mentionCache) prune() {
+	for ref, e := range c.entries {
+		if time.Since(e.resolved) > c.cooldown && time.Since(e.reported) > c.cooldown {
+			delete(c.entries, ref)
+		}
+	}
+}
+
+// Return the chats referenced by the message, up to maxMentions
+func mentionedRefs(msg model.Message) []mentions.Ref {
+	refs := mentions.Extract(msg.Text)
+	if len(refs) > maxMentions {
+		refs = refs[:maxMentions]
+	}
+	return refs
+}
+
+// Look ref up, from the cache when possible
+func (s *Scout) lookupChat(ctx context.Context, ref mentions.Ref) (mentions.Info, error) {
+	if e, ok := s.mentioned.get(ref); ok {
+		return e.info, e.err
+	}
+	info, err := s.resolver.ResolveChat(ctx, ref)
+	if err != nil {
+		s.log.Debug("Failed to resolve referenced chat", zap.String("ref", ref.String()), zap.Error(err))
+	}
+	s.mentioned.put(ref, info, err)
+	return info, err
+}
+
+// Match the first referenced chat not reported within the cooldown
+func (s *Scout) matchMentions(ctx context.Context, msg model.Message) (Explanation, bool) {
+	if s.resolver == nil {
+		return Explanation{}, false
+	}
+	for _, ref := range mentionedRefs(msg) {
+		info, err := s.lookupChat(ctx, ref)
+		if err != nil {
+			continue
+		}
+		if s.mentioned.report(ref) {
+			return Explanation{Keyword: mentionPrefix + ref.String(), Kind: kindMention, Matched: info.Title}, true
+		}
+	}
+	return Explanation{}, false
+}
+
+// Describe the chats referenced by the message as alert lines
+func (s *Scout) mentionedChats(ctx context.Context, msg model.Message) []string {
+	if s.resolver == nil {
+		return nil
+	}
+	var lines []string
+	for _, ref := range mentionedRefs(msg) {
+		info, err := s.lookupChat(ctx, ref)
+		if err != nil {
+			continue
+		}
+		kind := "group"
+		if info.Broadcast {
+			kind = "channel"
+		}
+		line := fmt.Sprintf("👥 %s: <b>%s</b>, %s with %d members", html.EscapeString(ref.String()), html.EscapeString(info.Title), kind, info.Members)
+		if info.Joined {
+			line += ", joined"
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
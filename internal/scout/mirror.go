@@ -0,0 +1,122 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"context"
+	"path"
+	"slices"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/chatid"
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/logger"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Copy monitored messages into other chats
+type Mirrorer interface {
+	Mirror(ctx context.Context, chatID int64, msgID int, to string) error
+}
+
+// Select matches by rule and source chat, every list set must hold
+type route struct {
+	rules []string
+	chats []chatid.Ref
+}
+
+// Entries are validated when the config is loaded, malformed chats are skipped
+func newRoute(rules, chats []string) route {
+	r := route{rules: rules}
+	for _, chat := range chats {
+		if ref, err := chatid.Parse(chat); err == nil {
+			r.chats = append(r.chats, ref)
+		}
+	}
+	return r
+}
+
+// Report whether a match of rule on msg is selected
+func (r route) matches(msg model.Message, rule string) bool {
+	if len(r.rules) > 0 && !slices.ContainsFunc(r.rules, func(pattern string) bool {
+		ok, _ := path.Match(pattern, rule)
+		return pattern == rule || ok
+	}) {
+		return false
+	}
+	kind, id := chatid.FromBotAPI(msg.ChatID)
+	return len(r.chats) == 0 || slices.ContainsFunc(r.chats, func(ref chatid.Ref) bool {
+		return ref.Matches(kind, id) || ref.MatchesUsername(msg.Username)
+	})
+}
+
+// A mirror with its chat references parsed
+type mirror struct {
+	route
+	to   string
+	dest chatid.Ref
+}
+
+// Parse the configured mirrors so matches can be routed to them
+func newMirrors(mirrors []config.MirrorConfig) []mirror {
+	var out []mirror
+	for _, m := range mirrors {
+		dest, err := chatid.Parse(m.To)
+		if err != nil {
+			continue
+		}
+		out = append(out, mirror{route: newRoute(m.Rules, m.Chats), to: m.To, dest: dest})
+	}
+	return out
+}
+
+// Report whether a match of rule on msg goes to the mirror. Messages already
+// in the destination are left out, so a monitored mirror does not feed itself.
+func (m mirror) wants(msg model.Message, rule string) bool {
+	kind, id := chatid.FromBotAPI(msg.ChatID)
+	if m.dest.Matches(kind, id) || m.dest.MatchesUsername(msg.Username) {
+		return false
+	}
+	return m.matches(msg, rule)
+}
+
+// Copy matched messages into the configured mirrors through m. Call before Start.
+func (s *Scout) UseMirror(m Mirrorer) {
+	s.mirrorer = m
+}
+
+// Copy the message into every mirror routed the match, in the background
+func (s *Scout) mirrorMatch(ctx context.Context, msg model.Message, exp Explanation) {
+	if s.mirrorer == nil {
+		return
+	}
+	for _, m := range s.mirrors {
+		if !m.wants(msg, exp.Keyword) {
+			continue
+		}
+		go func() {
+			if err := s.mirrorer.Mirror(ctx, msg.ChatID, msg.ID, m.to); err != nil {
+				s.log.Warn("Failed to mirror message", zap.String("to", m.to), zap.Int64("chat_id", msg.ChatID), zap.Int("msg_id", msg.ID), logger.TraceField(msg.TraceID), zap.Error(err))
+				return
+			}
+			matchMetrics.Add("mirror:"+m.to, 1)
+		}()
+	}
+}
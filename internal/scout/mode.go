@@ -0,0 +1,87 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"slices"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/model"
+	"github.com/h3nc4/TelegramScout/internal/urls"
+)
+
+// Settle the rule reported for msg under matching.mode, exp holding the
+// first match in evaluation order. Under priority a more urgent blocked
+// domain or file rule takes its place, keywords being tried by priority
+// already; under all the other matching rules are listed in exp.Also.
+func (s *Scout) settleMatch(msg model.Message, exp *Explanation, matched bool) bool {
+	mode := s.cfg.Matching.Mode
+	if !matched || (mode != config.MatchPriority && mode != config.MatchAll) {
+		return matched
+	}
+	others := s.otherMatches(msg, exp.Keyword)
+	if mode == config.MatchAll {
+		for _, o := range others {
+			exp.Also = append(exp.Also, o.Keyword)
+		}
+		return true
+	}
+	best := s.rank(exp.Keyword)
+	for _, o := range others {
+		if r := s.rank(o.Keyword); r > best {
+			best = r
+			exp.Keyword, exp.Kind, exp.Matched, exp.Captures = o.Keyword, o.Kind, o.Matched, o.Captures
+			exp.Start, exp.End = o.Start, o.End
+		}
+	}
+	return true
+}
+
+// Find every keyword, blocked domain and file rule matching msg besides
+// reported, in evaluation order
+func (s *Scout) otherMatches(msg model.Message, reported string) []Explanation {
+	var out []Explanation
+	for _, rule := range s.engine.Load().rules {
+		if rule.original == reported {
+			continue
+		}
+		if loc := rule.locate(msg); loc != nil {
+			var o Explanation
+			setMatch(&o, rule, msg.Text, loc)
+			out = append(out, o)
+		}
+	}
+	if blocked := s.cfg.URLs.BlockDomains; len(blocked) > 0 {
+		for _, link := range urls.Extract(msg.Text) {
+			d := urls.MatchDomain(urls.Host(link), blocked)
+			if d == "" || domainPrefix+d == reported || slices.ContainsFunc(out, func(o Explanation) bool { return o.Keyword == domainPrefix+d }) {
+				continue
+			}
+			out = append(out, Explanation{Keyword: domainPrefix + d, Kind: kindDomain, Matched: link})
+		}
+	}
+	if msg.File != nil {
+		for _, rule := range s.fileRules {
+			if rule.name != reported && rule.matches(msg.File) {
+				out = append(out, Explanation{Keyword: rule.name, Kind: kindFile, Matched: msg.File.Name})
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/dlclark/regexp2"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+// Compile a "pcre:" keyword with a backtracking engine, for the lookarounds and
+// backreferences RE2 lacks. Matching may take exponential time on some texts, so
+// each match gives up after timeout and counts as no match.
+func pcreRule(k string, timeout time.Duration) (matchRule, error) {
+	re, err := regexp2.Compile(k[len("pcre:"):], regexp2.None)
+	if err != nil {
+		return matchRule{}, err
+	}
+	if timeout <= 0 {
+		timeout = config.DefaultPCRETimeout
+	}
+	re.MatchTimeout = timeout
+
+	rule := matchRule{original: k, kind: kindPCRE}
+	match := func(text string) *regexp2.Match {
+		m, err := re.FindStringMatch(text)
+		if err != nil {
+			// The only error is running over the timeout
+			matchMetrics.Add("pcre_timeout:"+k, 1)
+			return nil
+		}
+		return m
+	}
+	rule.find = func(text string) []int {
+		m := match(text)
+		if m == nil {
+			return nil
+		}
+		return runeSpan(text, m.Index, m.Length)
+	}
+
+	var names []string
+	for _, name := range re.GetGroupNames() {
+		// Unnamed groups are named by their number
+		if _, err := strconv.Atoi(name); err != nil {
+			names = append(names, name)
+		}
+	}
+	if len(names) > 0 {
+		rule.captures = func(text string) map[string]string {
+			m := match(text)
+			if m == nil {
+				return nil
+			}
+			out := make(map[string]string)
+			for _, name := range names {
+				if g := m.GroupByName(name); g != nil && len(g.Captures) > 0 {
+					out[name] = g.String()
+				}
+			}
+			return out
+		}
+	}
+	return rule, nil
+}
+
+// Convert the rune offset and length regexp2 reports into byte offsets in text
+func runeSpan(text string, index, length int) []int {
+	loc := []int{len(text), len(text)}
+	n := 0
+	for i := range text {
+		if n == index {
+			loc[0] = i
+		}
+		if n == index+length {
+			loc[1] = i
+			break
+		}
+		n++
+	}
+	return loc
+}
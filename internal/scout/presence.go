@@ -0,0 +1,39 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Alert that a tracked user came online
+func (s *Scout) PresenceChange(ctx context.Context, userID int64, at time.Time) {
+	if err := s.notifier.Send(ctx, presenceText(userID, at)); err != nil {
+		s.log.Error("Failed to send presence alert", zap.Int64("user", userID), zap.Error(err))
+	}
+}
+
+func presenceText(userID int64, at time.Time) string {
+	return fmt.Sprintf("🟢 <b>User online:</b> <code>%d</code>\n🕒 <b>Time:</b> %s\n👤 <a href=\"tg://user?id=%d\">Open Profile</a>",
+		userID, at.Format(time.Kitchen), userID)
+}
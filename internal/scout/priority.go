@@ -0,0 +1,70 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"cmp"
+	"path"
+	"slices"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Order of precedence among the priorities of matching globs
+var priorityRank = map[string]int{config.PriorityLow: 1, config.PriorityNormal: 2, config.PriorityHigh: 3}
+
+// Return the priority of an alert on rule in the message's chat. A rule's own
+// entry wins over globs, the most urgent glob over the others, and any of them
+// over the chat's priority.
+func (s *Scout) priorityFor(rule string, msg model.Message) string {
+	if p := s.rulePriority(rule); p != "" {
+		return p
+	}
+	if cf := s.chatFilterFor(msg); cf != nil && cf.priority != "" {
+		return cf.priority
+	}
+	return config.PriorityNormal
+}
+
+// Return the priority given to rule under priorities, empty without an entry
+func (s *Scout) rulePriority(rule string) string {
+	var priority string
+	for pattern, p := range s.cfg.Monitoring.Priorities {
+		if pattern == rule {
+			return p
+		}
+		if ok, _ := path.Match(pattern, rule); ok && priorityRank[p] > priorityRank[priority] {
+			priority = p
+		}
+	}
+	return priority
+}
+
+// Rank rule for matching.mode priority, rules without an entry as normal
+func (s *Scout) rank(rule string) int {
+	return priorityRank[cmp.Or(s.rulePriority(rule), config.PriorityNormal)]
+}
+
+// Order rules most urgent first, keeping config order among equals
+func (s *Scout) byPriority(rules []matchRule) {
+	slices.SortStableFunc(rules, func(a, b matchRule) int {
+		return cmp.Compare(s.rank(b.original), s.rank(a.original))
+	})
+}
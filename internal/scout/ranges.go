@@ -0,0 +1,129 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Numeric placeholder in plain keywords, such as "rtx 50{60..90}"
+var rangePattern = regexp.MustCompile(`\{(\d+)\.\.(\d+)\}`)
+
+// Bounds of a placeholder. Zero-padded bounds, as in "{01..12}", require
+// numbers of their width.
+type numberRange struct {
+	from, to uint64
+	width    int // Zero when not padded
+}
+
+func (r numberRange) contains(digits string) bool {
+	if r.width > 0 && len(digits) != r.width {
+		return false
+	}
+	n, err := strconv.ParseUint(digits, 10, 64)
+	return err == nil && n >= r.from && n <= r.to
+}
+
+// Compile a plain keyword with numeric placeholders. Placeholders match a
+// whole number within their bounds, so "iphone {13..16}" does not match
+// "iphone 130"; the rest of the keyword matches as words, phrases and globs do.
+func rangeRule(k string) (matchRule, error) {
+	var ranges []numberRange
+	var pattern strings.Builder
+	pattern.WriteString("(?si)")
+	last := 0
+	for _, loc := range rangePattern.FindAllStringSubmatchIndex(k, -1) {
+		from, to := k[loc[2]:loc[3]], k[loc[4]:loc[5]]
+		r, err := parseRange(from, to)
+		if err != nil {
+			return matchRule{}, fmt.Errorf("range %s: %w", k[loc[0]:loc[1]], err)
+		}
+		ranges = append(ranges, r)
+		pattern.WriteString(literalPattern(k[last:loc[0]]))
+		pattern.WriteString(`(\d+)`)
+		last = loc[1]
+	}
+	pattern.WriteString(literalPattern(k[last:]))
+
+	re := regexp.MustCompile(pattern.String())
+	rule := matchRule{original: k, kind: kindRange}
+	rule.terms = literalTerms(strings.ReplaceAll(rangePattern.ReplaceAllString(k, " "), "*", " "))
+	// A superset, numbers out of bounds are only ruled out by find
+	rule.pattern = pattern.String()
+	rule.find = func(text string) []int {
+		for _, m := range re.FindAllStringSubmatchIndex(text, -1) {
+			if inRanges(ranges, text, m) {
+				return m[:2]
+			}
+		}
+		return nil
+	}
+	return rule, nil
+}
+
+// Report whether the numbers of match m in text are within their placeholder bounds
+func inRanges(ranges []numberRange, text string, m []int) bool {
+	for i, r := range ranges {
+		start, end := m[2*i+2], m[2*i+3]
+		// Digits before a placeholder starting the match belong to another number
+		if start == m[0] && start > 0 && isDigit(text[start-1]) {
+			return false
+		}
+		if !r.contains(text[start:end]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Parse the bounds of a placeholder
+func parseRange(from, to string) (numberRange, error) {
+	lo, err := strconv.ParseUint(from, 10, 64)
+	if err != nil {
+		return numberRange{}, err
+	}
+	hi, err := strconv.ParseUint(to, 10, 64)
+	if err != nil {
+		return numberRange{}, err
+	}
+	if lo > hi {
+		return numberRange{}, fmt.Errorf("%d is above %d", lo, hi)
+	}
+	r := numberRange{from: lo, to: hi}
+	if (len(from) > 1 && from[0] == '0') || (len(to) > 1 && to[0] == '0') {
+		r.width = max(len(from), len(to))
+	}
+	return r, nil
+}
+
+// Return the RE2 source for the text of a plain keyword, as globs and phrases spell it
+func literalPattern(text string) string {
+	parts := strings.Split(text, "*")
+	for i := range parts {
+		parts[i] = strings.ReplaceAll(regexp.QuoteMeta(parts[i]), " ", `\s+`)
+	}
+	return strings.Join(parts, ".*")
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
@@ -0,0 +1,222 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Links to messages the reloaded keywords would miss kept for the report
+const soakExamples = 5
+
+// How the matches of reloaded keywords differed from the live ones while
+// both were evaluated
+type RuleDiff struct {
+	Started  time.Time      `json:"started"`
+	Switch   time.Time      `json:"switch"`   // When the reloaded keywords take over
+	Keywords int            `json:"keywords"` // Reloaded keywords that compiled
+	Messages int            `json:"messages"` // Evaluated by both sets
+	Both     int            `json:"both"`     // Matched by the same rule in both
+	Changed  int            `json:"changed"`  // Matched by another rule, so reported differently
+	Lost     map[string]int `json:"lost"`     // Live rules matching messages the reloaded ones miss
+	Added    map[string]int `json:"added"`    // Reloaded rules matching messages the live ones miss
+	Missed   []string       `json:"missed"`   // Links to some of the lost messages
+}
+
+// Reloaded keywords evaluated next to the live ones
+type soak struct {
+	engine *engine
+
+	mux  sync.Mutex
+	diff RuleDiff
+}
+
+// Count how the live and reloaded keywords, given by the rules they matched
+// or empty, differ on msg
+func (k *soak) record(msg model.Message, live, next string) {
+	k.mux.Lock()
+	defer k.mux.Unlock()
+	d := &k.diff
+	d.Messages++
+	switch {
+	case live == next:
+		if live != "" {
+			d.Both++
+		}
+	case next == "":
+		d.Lost[live]++
+		if msg.Link != "" && len(d.Missed) < soakExamples {
+			d.Missed = append(d.Missed, msg.Link)
+		}
+	case live == "":
+		d.Added[next]++
+	default:
+		d.Changed++
+	}
+}
+
+func (k *soak) snapshot() RuleDiff {
+	k.mux.Lock()
+	defer k.mux.Unlock()
+	d := k.diff
+	d.Lost, d.Added = maps.Clone(d.Lost), maps.Clone(d.Added)
+	d.Missed = slices.Clone(d.Missed)
+	return d
+}
+
+// Replace the keywords with those of a reloaded config, keeping the engine
+// selection. With matching.soak set, both sets are evaluated for that long
+// and the difference in their matches is reported before switching; another
+// reload meanwhile starts the soak over with its keywords.
+func (s *Scout) ReloadKeywords(ctx context.Context, keywords []string) {
+	e := s.rebuild(s.keywordEngine(s.activeKeywords(keywords)))
+
+	wait := s.cfg.Matching.Soak
+	if wait <= 0 {
+		s.soaking.Store(nil)
+		s.switchKeywords(e, keywords)
+		s.log.Info("Keywords reloaded", zap.Int("rules", len(e.rules)), zap.Int("rejected", len(e.rejected)))
+		return
+	}
+
+	now := time.Now()
+	k := &soak{engine: e, diff: RuleDiff{
+		Started:  now,
+		Switch:   now.Add(wait),
+		Keywords: len(e.rules),
+		Lost:     make(map[string]int),
+		Added:    make(map[string]int),
+	}}
+	s.soaking.Store(k)
+	s.log.Info("Soaking reloaded keywords", zap.Int("rules", len(e.rules)), zap.Int("rejected", len(e.rejected)), zap.Duration("soak", wait))
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+		// A later reload took over
+		if !s.soaking.CompareAndSwap(k, nil) {
+			return
+		}
+		s.switchKeywords(e, keywords)
+		d := k.snapshot()
+		s.log.Info("Keywords reloaded after soak",
+			zap.Int("rules", len(e.rules)),
+			zap.Int("messages", d.Messages),
+			zap.Int("both", d.Both),
+			zap.Int("changed", d.Changed),
+			zap.Any("lost", d.Lost),
+			zap.Any("added", d.Added),
+		)
+		if err := s.notifier.Send(ctx, soakReport(d)); err != nil {
+			s.log.Warn("Failed to send soak report", zap.Error(err))
+		}
+	}()
+}
+
+// Return the difference found so far by the soak in progress
+func (s *Scout) Soak() (RuleDiff, bool) {
+	k := s.soaking.Load()
+	if k == nil {
+		return RuleDiff{}, false
+	}
+	return k.snapshot(), true
+}
+
+// Build an engine of the kind in use for the rules of next, which is compiled
+// to try them one by one
+func (s *Scout) rebuild(next *engine) *engine {
+	cur := s.engine.Load()
+	name := cur.name
+	if cur.auto {
+		name = config.EngineAuto
+	}
+	e, err := newEngine(name, next.rules)
+	if err != nil {
+		e, _ = newEngine(config.EngineAuto, next.rules)
+	}
+	e.rejected, e.results, e.keywords = next.rejected, next.results, next.keywords
+	return e
+}
+
+// Make e, compiled from the configured keywords, the live engine
+func (s *Scout) switchKeywords(e *engine, keywords []string) {
+	s.engineMux.Lock()
+	defer s.engineMux.Unlock()
+	s.engine.Store(e)
+	s.keywords = keywords
+}
+
+// Match msg with the reloaded keywords too while they soak
+func (s *Scout) evaluateSoak(msg model.Message, hash string) {
+	k := s.soaking.Load()
+	if k == nil {
+		return
+	}
+	var live, next string
+	e := s.engine.Load()
+	if i, _ := e.firstMatch(msg, hash); i >= 0 {
+		live = e.rules[i].original
+	}
+	if i, _ := k.engine.firstMatch(msg, hash); i >= 0 {
+		next = k.engine.rules[i].original
+	}
+	k.record(msg, live, next)
+}
+
+// Format the outcome of a soak for the alert chats
+func soakReport(d RuleDiff) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "🔁 <b>Keywords reloaded</b> after a %s soak over %d messages\n", d.Switch.Sub(d.Started).Round(time.Second), d.Messages)
+	fmt.Fprintf(&b, "Matched alike: %d, by another rule: %d\n", d.Both, d.Changed)
+	fmt.Fprintf(&b, "Only by the old keywords: %s\n", ruleCounts(d.Lost))
+	fmt.Fprintf(&b, "Only by the new keywords: %s", ruleCounts(d.Added))
+	for _, link := range d.Missed {
+		fmt.Fprintf(&b, "\n• <a href=\"%s\">missed message</a>", html.EscapeString(link))
+	}
+	return b.String()
+}
+
+// List rules with their counts, most first
+func ruleCounts(counts map[string]int) string {
+	if len(counts) == 0 {
+		return "none"
+	}
+	rules := slices.Sorted(maps.Keys(counts))
+	slices.SortStableFunc(rules, func(a, b string) int { return counts[b] - counts[a] })
+	parts := make([]string, len(rules))
+	for i, r := range rules {
+		parts[i] = fmt.Sprintf("%s ×%d", html.EscapeString(r), counts[r])
+	}
+	return strings.Join(parts, ", ")
+}
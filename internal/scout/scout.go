@@ -20,23 +20,48 @@ package scout
 
 import (
 	"context"
-	"fmt"
 	"regexp"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	"go.uber.org/zap"
 
+	"github.com/h3nc4/TelegramScout/internal/acks"
+	"github.com/h3nc4/TelegramScout/internal/audit"
+	"github.com/h3nc4/TelegramScout/internal/cluster"
 	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/graph"
+	"github.com/h3nc4/TelegramScout/internal/logger"
+	"github.com/h3nc4/TelegramScout/internal/mentions"
 	"github.com/h3nc4/TelegramScout/internal/model"
 	"github.com/h3nc4/TelegramScout/internal/notifier"
+	"github.com/h3nc4/TelegramScout/internal/render"
+	"github.com/h3nc4/TelegramScout/internal/snooze"
+	"github.com/h3nc4/TelegramScout/internal/temprules"
 )
 
 // Encapsulate a compiled matching strategy
 type matchRule struct {
 	original string
-	check    func(text string) bool
+	kind     string
+	// Return the byte offsets of the first match, or nil
+	find func(text string) []int
+	// Lowercased literal terms, used to report near-misses
+	terms []string
+	// Return the named groups of the first match, nil for rules without any
+	captures func(text string) map[string]string
+	// Kind and lowercased value of the message entities matched instead
+	// of text, empty for text rules
+	entity string
+	value  string
+	// RE2 source matching the same text, empty for entity, PCRE and wordwise rules
+	pattern string
+	// Also matched word by word, by stems or past stopwords
+	wordwise bool
 }
 
 // Process incoming messages and triggers alerts
@@ -45,63 +70,296 @@ type Scout struct {
 	notifier notifier.Notifier
 	log      *zap.Logger
 
-	// Compiled matching rules
-	rules []matchRule
+	// Compiled matching rules, keywords are kept with their engine
+	fileRules []fileRule
+	// Counted and archived but never alerting
+	shadow         []matchRule
+	rejectedShadow []RejectedRule
+	// Only counted, never alerting nor hiding live matches
+	counters         []matchRule
+	rejectedCounters []RejectedRule
+	// Finds the first matching keyword, swapped by SetEngine and reloads
+	engine    atomic.Pointer[engine]
+	engineMux sync.Mutex // Held while swapping
+	// Reloaded keywords evaluated next to the live ones, nil outside a soak
+	soaking atomic.Pointer[soak]
+	// Configured keywords of the live engine, before expired ones are
+	// dropped and temporary ones added. Held with engineMux.
+	keywords []string
 
-	// Dedup cache: Key = "ChatID:MsgID", Value = Expiration
-	seenMsgs sync.Map
+	// Recently matched message IDs per chat
+	recent *recentIDs
+	// Recently matched content hashes across chats, nil when disabled
+	seenContent     *dedupCache
+	cleanupInterval time.Duration
+	// Messages seen by either account, nil without a hot spare
+	spares *spareDedup
 
 	// Semaphore to limit concurrent notification requests
 	notifySem chan struct{}
+
+	// Alerts held back while the notifier is degraded
+	digest         digestStore
+	digestInterval time.Duration
+
+	// Dedup and digests shared with other instances, nil when running alone
+	shared *cluster.Store
+
+	// Recent match explanations
+	explanations *explainLog
+
+	// Recent matched messages for inline queries, nil when they are disabled
+	matches *matchLog
+
+	// Per-chat filter overrides
+	chatFilters []chatFilter
+
+	// Uploads media of matched messages, nil when archiving is disabled
+	archiver MediaArchiver
+
+	// Looks up shared files with threat intelligence services, nil when disabled
+	enricher Enricher
+
+	// Compares images against reference hashes, nil without image rules
+	images ImageMatcher
+
+	// Renders linked pages, nil when screenshots are disabled
+	screenshotter Screenshotter
+
+	// Looks up chats referenced in messages, nil when disabled
+	resolver  ChatResolver
+	mentioned *mentionCache
+
+	// Rule-specific endpoints, nil without webhooks
+	webhooks WebhookDeliverer
+
+	// Copies matched messages into the configured mirrors, nil when not set up
+	mirrorer Mirrorer
+	mirrors  []mirror
+
+	// Runs actions hooked to matches, nil when not set up
+	actor       Actor
+	actionHooks []actionHook
+	actions     actionQueue
+
+	// Alert acknowledgement state, nil when disabled
+	acks *acks.Store
+
+	// Rules muted per chat, nil when snoozing is disabled
+	snoozes *snooze.Store
+	// Keywords added by bot commands until they expire, nil when commands are off
+	temporary *temprules.Store
+
+	// Who changed snoozes and alert states, nil when auditing is disabled
+	audit *audit.Log
+
+	// Told about every alert to spot storms, nil without annotations
+	alerts AlertCounter
+
+	// Keeps matches and optionally every message, nil when archiving is disabled
+	archive MessageArchive
+
+	// Answers /search, nil without bot commands
+	searcher HistorySearcher
+
+	// Reads chat history for backfills, nil when unavailable
+	history   HistoryReader
+	backfills backfills
+	// Fetches single messages for Inspect, nil when unavailable
+	fetcher MessageFetcher
+
+	// Relations between chats, nil when the graph is disabled
+	graph *graph.Graph
+
+	// Resolves short links, nil when expansion is disabled
+	expander LinkExpander
+	// Semaphore to limit concurrent matching that needs network access
+	lateSem chan struct{}
+
+	// Recent alert delivery times
+	latency latencyTracker
+
+	// Posts canaries into the canary chat, nil when the self-test is disabled
+	canaryPoster CanaryPoster
+	canaryRoute  route
+	canary       canaryState
 }
 
 // Create a new Scout instance and compiles matching rules
 func New(cfg *config.Config, notifier notifier.Notifier, log *zap.Logger) *Scout {
+	concurrency := cfg.Notifier.Concurrency
+	if concurrency <= 0 {
+		concurrency = config.DefaultNotifierConcurrency
+	}
+	window := cfg.Dedup.Window
+	if window <= 0 {
+		window = config.DefaultDedupWindow
+	}
+	digestSize := cfg.Pipeline.DigestSize
+	if digestSize <= 0 {
+		digestSize = config.DefaultDigestSize
+	}
+	digestInterval := cfg.Pipeline.DigestInterval
+	if digestInterval <= 0 {
+		digestInterval = config.DefaultDigestInterval
+	}
+
 	s := &Scout{
 		cfg:      cfg,
 		notifier: notifier,
 		log:      log,
 		// Limit concurrent notifications
-		notifySem: make(chan struct{}, 5),
+		notifySem:      make(chan struct{}, concurrency),
+		recent:         newRecentIDs(window),
+		digest:         newDigestQueue(digestSize),
+		digestInterval: digestInterval,
+		explanations:   newExplainLog(cfg.Explain.Size),
+		chatFilters:    newChatFilters(cfg.ChatSettings),
+		mirrors:        newMirrors(cfg.Mirrors),
+		lateSem:        make(chan struct{}, lateConcurrency),
+	}
+	if cfg.Dedup.ContentHash {
+		ttl := cfg.Dedup.TTL
+		if ttl <= 0 {
+			ttl = config.DefaultDedupTTL
+		}
+		s.seenContent = newDedupCache(ttl, cfg.Dedup.MaxEntries)
+		s.cleanupInterval = cfg.Dedup.CleanupInterval
+		if s.cleanupInterval <= 0 {
+			s.cleanupInterval = config.DefaultDedupCleanupInterval
+		}
+	}
+	if cfg.Spare.Phone != "" {
+		s.spares = newSpareDedup()
+	}
+	if cfg.Commands.Inline {
+		s.matches = newMatchLog(inlineHistory)
 	}
 	s.compileRules()
+	if err := s.SetEngine(cfg.Matching.Engine); err != nil {
+		log.Warn("Matching engine unavailable, picking one instead", zap.String("engine", cfg.Matching.Engine), zap.Error(err))
+		_ = s.SetEngine(config.EngineAuto)
+	}
 	return s
 }
 
-// Process config keywords into efficient matching functions
+// Describe a configured keyword that failed to compile
+type RejectedRule struct {
+	Keyword string
+	Reason  string
+}
+
+// Return the number of compiled rules and those rejected as invalid
+func (s *Scout) Rules() (int, []RejectedRule) {
+	e := s.engine.Load()
+	return len(e.rules) + len(s.fileRules), slices.Concat(e.rejected, s.rejectedShadow, s.rejectedCounters)
+}
+
+// Process config keywords into efficient matching functions, trying them one
+// by one until an engine is set
 func (s *Scout) compileRules() {
+	shadow, rejectedShadow := s.compileKeywords(s.cfg.Monitoring.Shadow)
+
+	s.keywords = s.cfg.Monitoring.Keywords
+	s.warnExpired()
+	s.engine.Store(s.keywordEngine(s.activeKeywords(s.keywords)))
+	s.shadow = shadow
+	s.rejectedShadow = rejectedShadow
+	s.counters, s.rejectedCounters = s.compileKeywords(s.cfg.Monitoring.Counters)
+	s.fileRules = newFileRules(s.cfg.Monitoring.Files)
+}
+
+// Compile keywords into an engine trying them one by one, with an empty result cache.
+// Under matching.mode priority they are tried most urgent first.
+func (s *Scout) keywordEngine(keywords []string) *engine {
+	rules, rejected := s.compileKeywords(keywords)
+	if s.cfg.Matching.Mode == config.MatchPriority {
+		s.byPriority(rules)
+	}
+	e := &engine{name: config.EngineNaive, matcher: naiveMatcher(rules), rules: rules, rejected: rejected, keywords: keywords}
+	if s.cfg.Matching.CacheTTL > 0 {
+		e.results = newMatchCache(s.cfg.Matching.CacheTTL, s.cfg.Matching.CacheEntries)
+	}
+	return e
+}
+
+// Compile keywords, skipping and reporting invalid ones
+func (s *Scout) compileKeywords(keywords []string) ([]matchRule, []RejectedRule) {
 	var rules []matchRule
+	var rejected []RejectedRule
+	wm := newWordMatcher(s.cfg.Matching.Stemming, s.cfg.Matching.Stopwords)
 
-	for _, k := range s.cfg.Monitoring.Keywords {
-		k := k // Capture for closure
-		var check func(string) bool
+	for _, k := range keywords {
+		rule := matchRule{original: k}
+
+		// Patterns are built from the keyword, which must be text to compile
+		if !utf8.ValidString(k) {
+			s.log.Error("Keyword with invalid UTF-8 ignored", zap.String("keyword", k))
+			rejected = append(rejected, RejectedRule{Keyword: k, Reason: "invalid UTF-8"})
+			continue
+		}
 
 		switch {
-		// Explicit Regex (prefix "re:")
-		case strings.HasPrefix(k, "re:"):
-			pattern := k[3:]
+		// Mention entities (prefix "mention:")
+		case strings.HasPrefix(k, handlePrefix):
+			r, err := handleRule(k)
+			if err != nil {
+				s.log.Error("Invalid mention keyword ignored", zap.String("keyword", k), zap.Error(err))
+				rejected = append(rejected, RejectedRule{Keyword: k, Reason: err.Error()})
+				continue
+			}
+			rule = r
+
+		// Hashtag and cashtag entities ("#tag", "$TICKER")
+		case hashtagPattern.MatchString(k):
+			rule = entityRule(k, kindHashtag, model.EntityHashtag, k[1:])
+		case cashtagPattern.MatchString(k):
+			rule = entityRule(k, kindCashtag, model.EntityCashtag, k[1:])
+
+		// Backtracking regex (prefix "pcre:"), for lookarounds and backreferences
+		case strings.HasPrefix(k, "pcre:"):
+			r, err := pcreRule(k, s.cfg.Matching.PCRETimeout)
+			if err != nil {
+				s.log.Error("Invalid PCRE keyword ignored", zap.String("keyword", k), zap.Error(err))
+				rejected = append(rejected, RejectedRule{Keyword: k, Reason: err.Error()})
+				continue
+			}
+			rule = r
+
+		// Explicit Regex (prefix "re:" or "re2:")
+		case strings.HasPrefix(k, "re:"), strings.HasPrefix(k, "re2:"):
+			_, pattern, _ := strings.Cut(k, ":")
 			re, err := regexp.Compile(pattern)
 			if err != nil {
 				s.log.Error("Invalid regex keyword ignored", zap.String("keyword", k), zap.Error(err))
+				rejected = append(rejected, RejectedRule{Keyword: k, Reason: err.Error()})
 				continue
 			}
-			check = func(text string) bool {
-				return re.MatchString(text)
+			rule.kind = kindRegex
+			rule.find = re.FindStringIndex
+			rule.pattern = pattern
+			if slices.ContainsFunc(re.SubexpNames(), func(name string) bool { return name != "" }) {
+				rule.captures = func(text string) map[string]string { return captures(re, text) }
 			}
 
+		// Numeric placeholders ("{60..90}"), in words, phrases and globs alike
+		case rangePattern.MatchString(k):
+			r, err := rangeRule(k)
+			if err != nil {
+				s.log.Error("Invalid range keyword ignored", zap.String("keyword", k), zap.Error(err))
+				rejected = append(rejected, RejectedRule{Keyword: k, Reason: err.Error()})
+				continue
+			}
+			rule = r
+
 		// Glob Pattern (contains "*")
 		case strings.Contains(k, "*"):
 			// Escape everything except '*', then replace '*' with '.*'
-			parts := strings.Split(k, "*")
-			for i := range parts {
-				quoted := regexp.QuoteMeta(parts[i])
-				parts[i] = strings.ReplaceAll(quoted, " ", `\s+`)
-			}
-			pattern := "(?si)" + strings.Join(parts, ".*")
-			re := regexp.MustCompile(pattern)
-			check = func(text string) bool {
-				return re.MatchString(text)
-			}
+			pattern := "(?si)" + literalPattern(k)
+			rule.kind = kindGlob
+			rule.find = regexp.MustCompile(pattern).FindStringIndex
+			rule.pattern = pattern
+			rule.terms = literalTerms(strings.ReplaceAll(k, "*", " "))
 
 		// Simple Substring
 		default:
@@ -109,134 +367,311 @@ func (s *Scout) compileRules() {
 				// Lenient matching for phrases with spaces
 				quoted := regexp.QuoteMeta(k)
 				pattern := "(?si)" + strings.ReplaceAll(quoted, " ", `\s+`)
-				re := regexp.MustCompile(pattern)
-				check = func(text string) bool {
-					return re.MatchString(text)
-				}
+				rule.kind = kindPhrase
+				rule.find = regexp.MustCompile(pattern).FindStringIndex
+				rule.pattern = pattern
+				rule.terms = literalTerms(k)
 			} else {
 				// Fast path for single words
 				lowK := strings.ToLower(k)
-				check = func(text string) bool {
-					return strings.Contains(strings.ToLower(text), lowK)
+				slow := regexp.MustCompile("(?i)" + regexp.QuoteMeta(k))
+				rule.kind = kindWord
+				rule.pattern = slow.String()
+				rule.find = func(text string) []int {
+					lowText := strings.ToLower(text)
+					i := strings.Index(lowText, lowK)
+					if i < 0 {
+						return nil
+					}
+					// Lowercasing changed byte lengths, offsets would not line up
+					if len(lowText) != len(text) {
+						return slow.FindStringIndex(text)
+					}
+					return []int{i, i + len(lowK)}
 				}
 			}
+			// Other forms and spacings of the words match too, which no pattern spells
+			if byWord := wm.find(k); byWord != nil {
+				find := rule.find
+				rule.find = func(text string) []int {
+					if loc := find(text); loc != nil {
+						return loc
+					}
+					return byWord(text)
+				}
+				rule.pattern = ""
+				rule.wordwise = true
+			}
 		}
 
-		rules = append(rules, matchRule{
-			original: k,
-			check:    check,
-		})
+		rules = append(rules, rule)
 	}
-
-	s.rules = rules
+	return rules, rejected
 }
 
 // Listen to the message channel and process messages
 func (s *Scout) Start(ctx context.Context, input <-chan model.Message) {
-	// Start cleanup ticker for deduplication cache
-	go s.cleanupCache(ctx)
+	// Deliver alerts queued while the notifier was degraded
+	go s.flushDigests(ctx)
+	if s.seenContent != nil {
+		go s.cleanupCache(ctx)
+	}
+	if s.acks != nil {
+		go s.remindUnacked(ctx)
+	}
+	if s.canaryPoster != nil {
+		go s.runCanaries(ctx)
+	}
+	if len(s.cfg.Monitoring.Expires) > 0 || s.temporary != nil {
+		go s.expireRules(ctx)
+	}
 
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case msg := <-input:
-			s.process(ctx, msg)
+			s.guardedProcess(ctx, msg)
+		}
+	}
+}
+
+// Remove expired content hashes from the deduplication cache
+func (s *Scout) cleanupCache(ctx context.Context) {
+	ticker := time.NewTicker(s.cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.seenContent.cleanup(now)
 		}
 	}
 }
 
+// Process msg, dropping it instead of stopping the pipeline if a rule panics on it
+func (s *Scout) guardedProcess(ctx context.Context, msg model.Message) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.log.Error("Panic while processing message, dropped",
+				zap.Any("panic", r),
+				zap.Int64("chat_id", msg.ChatID),
+				zap.Int("msg_id", msg.ID),
+				logger.TraceField(msg.TraceID),
+				zap.Stack("stack"),
+			)
+		}
+	}()
+	s.process(ctx, msg)
+}
+
+// Run msg through the pipeline as Start does, returning once it is matched.
+// Notifications are still sent in the background.
+func (s *Scout) Process(ctx context.Context, msg model.Message) {
+	s.process(ctx, msg)
+}
+
 func (s *Scout) process(ctx context.Context, msg model.Message) {
+	// Both accounts receive every message when a hot spare runs
+	if s.spares != nil && s.spares.duplicate(msg) {
+		return
+	}
+	if msg.TraceID == "" {
+		msg.TraceID = logger.NewTraceID()
+	}
+	ctx = logger.WithTraceID(ctx, msg.TraceID)
+	if s.canaryReceived(ctx, msg) {
+		return
+	}
+
+	// Map relations between chats from every message, matched or not
+	s.recordRelations(msg)
+
+	// Apply prefilters
+	if reason := s.filtered(msg); reason != "" {
+		s.log.Debug("Message filtered", zap.String("reason", reason), zap.Int64("chat_id", msg.ChatID), zap.Int("msg_id", msg.ID), logger.TraceField(msg.TraceID))
+		return
+	}
+
 	// Check Deduplication
-	dedupKey := fmt.Sprintf("%d:%d", msg.ChatID, msg.ID)
-	if _, exists := s.seenMsgs.Load(dedupKey); exists {
+	if s.recent.contains(msg.ChatID, msg.ID) {
 		return
 	}
+	s.archiveMessage(msg)
+	var hash string
+	if s.seenContent != nil && msg.Text != "" {
+		hash = contentHash(msg.Text)
+		if s.seenContent.contains(hash) {
+			return
+		}
+	}
 
 	// Rule Matching
-	matchedKeyword := ""
-	for _, rule := range s.rules {
-		if rule.check(msg.Text) {
-			matchedKeyword = rule.original
-			break
+	exp, ok := s.evaluateContent(msg, hash)
+	s.evaluateShadow(msg, ok)
+	s.evaluateCounters(msg)
+	s.evaluateSoak(msg, hash)
+	if !ok {
+		// Images and short links need network access, match them off the reader loop
+		if s.needsLateMatch(msg) {
+			s.matchLateAsync(ctx, msg, hash)
 		}
+		return
+	}
+	s.alert(ctx, msg, exp, hash)
+}
+
+// Record a match and notify about it, unless another instance already did
+func (s *Scout) alert(ctx context.Context, msg model.Message, exp Explanation, hash string) {
+	matchedKeyword := exp.Keyword
+
+	// Mark as seen
+	s.recent.add(msg.ChatID, msg.ID)
+	if hash != "" {
+		s.seenContent.add(hash)
 	}
 
-	if matchedKeyword == "" {
+	// Keywords are dropped from the engine when they expire, other rules only here
+	if s.expired(matchedKeyword, time.Now()) {
+		s.log.Debug("Rule expired", zap.String("keyword", matchedKeyword), logger.TraceField(msg.TraceID))
+		return
+	}
+	if s.snoozed(matchedKeyword, msg.ChatID) {
+		s.log.Debug("Rule snoozed in chat", zap.String("keyword", matchedKeyword), zap.Int64("chat_id", msg.ChatID), logger.TraceField(msg.TraceID))
 		return
 	}
 
-	// Mark as seen
-	s.seenMsgs.Store(dedupKey, time.Now().Add(1*time.Hour))
+	// Another instance may have alerted on the same message or content already
+	if s.shared != nil && !s.claim(ctx, msg, hash) {
+		s.log.Debug("Match already alerted by another instance", zap.Int64("chat_id", msg.ChatID), zap.Int("msg_id", msg.ID), logger.TraceField(msg.TraceID))
+		return
+	}
+	exp.Tags = s.alertTags(exp)
+	exp.Stale = tooOld(msg, s.filtersFor(msg))
+	if exp.Stale {
+		matchMetrics.Add("stale", 1)
+	}
+	s.explanations.add(exp)
+	s.rememberMatch(msg, exp)
+	recordMatch(exp)
+	s.countAlert()
+	s.archiveMatch(msg, exp)
 	s.log.Info("Keyword matched",
 		zap.String("keyword", matchedKeyword),
+		zap.String("kind", exp.Kind),
+		zap.Strings("tags", exp.Tags),
 		zap.String("channel", msg.ChatTitle),
 		zap.Int("msg_id", msg.ID),
+		logger.TraceField(msg.TraceID),
 	)
 
-	// Build Alert
-	alertText := fmt.Sprintf(
-		"🚨 <b>Match:</b> %s\n"+
-			"📢 <b>Chat:</b> %s\n"+
-			"🕒 <b>Time:</b> %s\n"+
-			"🔗 <a href=\"%s\">Link to Message</a>\n\n"+
-			"<i>%s</i>",
-		matchedKeyword,
-		msg.ChatTitle,
-		msg.Date.Format(time.Kitchen),
-		msg.Link,
-		truncate(msg.Text, 200),
-	)
+	// Webhooks and mirrors do not depend on the health of the Telegram notifier
+	match := s.matchFor(msg, exp)
+	s.deliverWebhooks(ctx, msg, match)
+	s.mirrorMatch(ctx, msg, exp)
+	held := s.triggerActions(ctx, msg, exp)
+
+	// Notifiers with a formatting profile render the match themselves
+	ctx = notifier.WithMatch(s.trackAlert(ctx, msg, exp), match)
+	if exp.Stale {
+		// Too late to act on, no reason to sound
+		ctx = notifier.WithSilent(ctx)
+	}
+	switch s.priorityFor(matchedKeyword, msg) {
+	case config.PriorityLow:
+		matchMetrics.Add("low_priority", 1)
+		s.log.Debug("Low priority match, holding alert for digest", zap.String("keyword", matchedKeyword), zap.Int("msg_id", msg.ID), logger.TraceField(msg.TraceID))
+		s.digest.add(render.DigestLine(match))
+		return
+	case config.PriorityHigh:
+		ctx = notifier.WithUrgent(ctx)
+	}
+
+	// Hold alerts back while the notifier reports trouble
+	if s.notifierDegraded() {
+		s.queueDigest(msg, match)
+		return
+	}
 
 	// Dispatch notification asynchronously to not block the reader loop
 	select {
 	case s.notifySem <- struct{}{}:
 		go func() {
-			defer func() { <-s.notifySem }()
-			if err := s.notifier.Send(ctx, alertText); err != nil {
-				s.log.Error("Failed to send notification", zap.Error(err))
+			release := sync.OnceFunc(func() { <-s.notifySem })
+			defer release()
+			lines := append(alertIDLine(ctx), held...)
+			if err := s.send(ctx, render.Alert(match, append(lines, s.attachments(ctx, msg)...)), release); err != nil {
+				s.log.Error("Failed to send notification", logger.TraceField(msg.TraceID), zap.Error(err))
+				return
 			}
+			s.recordLatency(ctx, msg)
 		}()
 	case <-ctx.Done():
 		return
 	default:
-		s.log.Warn("Notification queue full, blocking momentarily to dispatch alert", zap.Int("msg_id", msg.ID))
-		// Fallback to blocking send if queue is full to ensure alerts are not dropped
-		s.notifySem <- struct{}{}
-		go func() {
-			defer func() { <-s.notifySem }()
-			if err := s.notifier.Send(ctx, alertText); err != nil {
-				s.log.Error("Failed to send notification", zap.Error(err))
-			}
-		}()
+		// All senders busy, queue for the digest instead of piling up goroutines
+		s.queueDigest(msg, match)
 	}
 }
 
-// Remove old entries from deduplication map
-func (s *Scout) cleanupCache(ctx context.Context) {
-	ticker := time.NewTicker(10 * time.Minute)
-	defer ticker.Stop()
+// Send through the notifier, calling release once a queueing notifier has
+// taken the message so the send slot is not held while it waits to be delivered
+func (s *Scout) send(ctx context.Context, message string, release func()) error {
+	q, ok := s.notifier.(notifier.Queuer)
+	if !ok {
+		return s.notifier.Send(ctx, message)
+	}
+	result := q.Queue(ctx, message)
+	release()
+	return <-result
+}
 
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			now := time.Now()
-			s.seenMsgs.Range(func(key, value interface{}) bool {
-				expiry := value.(time.Time)
-				if now.After(expiry) {
-					s.seenMsgs.Delete(key)
-				}
-				return true
-			})
-		}
+// Limit concurrent image downloads, link expansions and chat lookups
+const lateConcurrency = 4
+
+// Report whether rules that need network access could still match the message
+func (s *Scout) needsLateMatch(msg model.Message) bool {
+	if s.images != nil && msg.Media != "" {
+		return true
+	}
+	if s.resolver != nil && len(mentions.Extract(msg.Text)) > 0 {
+		return true
 	}
+	return s.expander != nil && len(s.cfg.URLs.BlockDomains) > 0 && strings.Contains(msg.Text, "://")
 }
 
-func truncate(s string, max int) string {
-	if len(s) > max {
-		return s[:max] + "..."
+// Run the network-bound rules in the background and alert on a match
+func (s *Scout) matchLateAsync(ctx context.Context, msg model.Message, hash string) {
+	select {
+	case s.lateSem <- struct{}{}:
+	default:
+		s.log.Warn("Late matching saturated, skipping message", zap.Int64("chat_id", msg.ChatID), zap.Int("msg_id", msg.ID), logger.TraceField(msg.TraceID))
+		return
 	}
-	return s
+
+	go func() {
+		defer func() { <-s.lateSem }()
+		exp, ok := s.matchImage(ctx, msg)
+		if !ok {
+			exp, ok = s.matchExpandedLinks(ctx, msg)
+		}
+		if !ok {
+			exp, ok = s.matchMentions(ctx, msg)
+		}
+		if !ok {
+			return
+		}
+		exp.Time = time.Now()
+		exp.ChatID = msg.ChatID
+		exp.ChatTitle = msg.ChatTitle
+		exp.MsgID = msg.ID
+		s.alert(ctx, msg, exp, hash)
+	}()
+}
+
+func (s *Scout) queueDigest(msg model.Message, m model.Match) {
+	s.log.Warn("Notifier saturated or degraded, queueing alert for digest", zap.Int("msg_id", msg.ID), logger.TraceField(msg.TraceID))
+	s.digest.add(render.DigestLine(m))
 }
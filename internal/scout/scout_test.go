@@ -20,15 +20,40 @@ package scout
 
 import (
 	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/alicebob/miniredis/v2"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 
+	"github.com/h3nc4/TelegramScout/internal/acks"
+	"github.com/h3nc4/TelegramScout/internal/archive"
+	"github.com/h3nc4/TelegramScout/internal/audit"
+	"github.com/h3nc4/TelegramScout/internal/cluster"
 	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/graph"
+	"github.com/h3nc4/TelegramScout/internal/logger"
+	"github.com/h3nc4/TelegramScout/internal/mentions"
 	"github.com/h3nc4/TelegramScout/internal/model"
+	"github.com/h3nc4/TelegramScout/internal/notifier"
+	"github.com/h3nc4/TelegramScout/internal/snooze"
+	"github.com/h3nc4/TelegramScout/internal/standing"
+	"github.com/h3nc4/TelegramScout/internal/temprules"
+	"github.com/h3nc4/TelegramScout/internal/watch"
 )
 
 type MockNotifier struct {
@@ -47,6 +72,25 @@ func (m *MockNotifier) Send(ctx context.Context, message string) error {
 	return nil
 }
 
+// Report health as toggled by the test
+type DegradableNotifier struct {
+	MockNotifier
+	mu      sync.Mutex
+	healthy bool
+}
+
+func (d *DegradableNotifier) Healthy() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.healthy
+}
+
+func (d *DegradableNotifier) SetHealthy(v bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.healthy = v
+}
+
 func (m *MockNotifier) Messages() []string {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -107,8 +151,8 @@ func TestScout_Process(t *testing.T) {
 				Date:      time.Now(),
 				Link:      "http://t.me/msg/1",
 			}
-			// Bypass dedup for testing by ensuring unique ID effectively (or clearing map)
-			s.seenMsgs = sync.Map{}
+			// Bypass dedup for testing by clearing the cache
+			s.recent = newRecentIDs(16)
 
 			s.process(context.Background(), msg)
 
@@ -136,7 +180,7 @@ func TestScout_Process(t *testing.T) {
 		notifier.mu.Lock()
 		notifier.SentMessages = nil
 		notifier.mu.Unlock()
-		s.seenMsgs = sync.Map{}
+		s.recent = newRecentIDs(16)
 		msg := model.Message{
 			ID:     999,
 			ChatID: 100,
@@ -162,4 +206,2382 @@ func TestScout_Process(t *testing.T) {
 			// OK
 		}
 	})
+
+	t.Run("Links", func(t *testing.T) {
+		s.recent = newRecentIDs(16)
+		msg := model.Message{
+			ID:      5,
+			ChatID:  100,
+			Text:    "urgent",
+			Date:    time.Now(),
+			Link:    "https://t.me/c/100/5",
+			AppLink: "tg://privatepost?channel=100&post=5",
+		}
+		s.process(context.Background(), msg)
+		received := <-notifier.NotifyChan
+		if !strings.Contains(received, msg.Link) || !strings.Contains(received, html.EscapeString(msg.AppLink)) {
+			t.Errorf("expected web and app links in alert, got: %s", received)
+		}
+
+		// Chats without message links get no link lines
+		msg.ID, msg.Link, msg.AppLink = 6, "", ""
+		s.process(context.Background(), msg)
+		received = <-notifier.NotifyChan
+		if strings.Contains(received, "href") {
+			t.Errorf("expected no links in alert, got: %s", received)
+		}
+	})
+}
+
+func TestScout_Explanations(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{
+			Keywords: []string{"rtx * 5070", "bitcoin", "hello world"},
+		},
+		Explain: config.ExplainConfig{Size: 2, Debug: true},
+	}
+	s := New(cfg, &MockNotifier{}, zap.NewNop())
+
+	msg := model.Message{ID: 1, ChatID: 100, Text: "Buy BITCOIN, hello there"}
+	exp, ok := s.evaluate(msg)
+	if !ok {
+		t.Fatal("expected a match")
+	}
+	if exp.Keyword != "bitcoin" || exp.Kind != kindWord {
+		t.Errorf("unexpected winning rule %q (%s)", exp.Keyword, exp.Kind)
+	}
+	if exp.Matched != "BITCOIN" || exp.Start != 4 || exp.End != 11 {
+		t.Errorf("unexpected offsets %d-%d (%q)", exp.Start, exp.End, exp.Matched)
+	}
+
+	decisions := make(map[string]string)
+	for _, ev := range exp.Evaluations {
+		decisions[ev.Keyword] = ev.Decision
+	}
+	if decisions["rtx * 5070"] != DecisionNoMatch {
+		t.Errorf("expected no_match for glob, got %q", decisions["rtx * 5070"])
+	}
+	if decisions["bitcoin"] != DecisionMatched {
+		t.Errorf("expected matched for bitcoin, got %q", decisions["bitcoin"])
+	}
+	if decisions["hello world"] != DecisionSkipped {
+		t.Errorf("expected skipped after first match, got %q", decisions["hello world"])
+	}
+
+	t.Run("Near Miss", func(t *testing.T) {
+		_, ok := s.evaluate(model.Message{Text: "hello there, rtx 4070 for sale"})
+		if ok {
+			t.Fatal("expected no match")
+		}
+		ev := nearMiss(s.engine.Load().rules[2], "hello there")
+		if ev.Decision != DecisionNearMiss {
+			t.Errorf("expected near_miss, got %q", ev.Decision)
+		}
+	})
+
+	t.Run("Ring Buffer", func(t *testing.T) {
+		for i := 1; i <= 3; i++ {
+			s.explanations.add(Explanation{ChatID: int64(i), MsgID: i})
+		}
+		recent := s.Explanations(0, "", 10)
+		if len(recent) != 2 {
+			t.Fatalf("expected 2 retained entries, got %d", len(recent))
+		}
+		if recent[0].MsgID != 3 || recent[1].MsgID != 2 {
+			t.Errorf("expected newest first, got %d, %d", recent[0].MsgID, recent[1].MsgID)
+		}
+		if got := s.Explanations(2, "", 10); len(got) != 1 || got[0].MsgID != 2 {
+			t.Errorf("unexpected chat filter result: %+v", got)
+		}
+	})
+}
+
+func TestDedupCache(t *testing.T) {
+	t.Run("LRU Eviction", func(t *testing.T) {
+		c := newDedupCache(time.Hour, 2)
+		c.add("a")
+		c.add("b")
+		c.contains("a") // Touch "a" so "b" becomes least recently used
+		c.add("c")
+
+		if !c.contains("a") || !c.contains("c") {
+			t.Error("expected recently used entries to be kept")
+		}
+		if c.contains("b") {
+			t.Error("expected least recently used entry to be evicted")
+		}
+	})
+
+	t.Run("TTL Expiry", func(t *testing.T) {
+		c := newDedupCache(time.Millisecond, 0)
+		c.add("a")
+		c.add("b")
+		time.Sleep(5 * time.Millisecond)
+
+		if c.contains("a") {
+			t.Error("expected expired entry to be reported as unseen")
+		}
+		if n := c.len(); n != 1 {
+			t.Errorf("expected lookup to drop the expired entry, %d left", n)
+		}
+		c.cleanup(time.Now())
+		if n := c.len(); n != 0 {
+			t.Errorf("expected cleanup to drop expired entries, %d left", n)
+		}
+	})
+}
+
+func TestRecentIDs(t *testing.T) {
+	r := newRecentIDs(2)
+	r.add(1, 10)
+	r.add(1, 11)
+
+	if !r.contains(1, 10) || !r.contains(1, 11) {
+		t.Error("expected recorded IDs to be found")
+	}
+	if r.contains(2, 10) {
+		t.Error("expected IDs to be scoped per chat")
+	}
+	if r.contains(1, 12) {
+		t.Error("expected higher ID to be new")
+	}
+
+	r.add(1, 12)
+	if r.contains(1, 10) {
+		t.Error("expected oldest ID to be overwritten once the ring is full")
+	}
+
+	// Chats sharing a shard, including Bot API IDs, keep separate rings
+	r.add(1+recentShards, 10)
+	r.add(-1001, 10)
+	if !r.contains(1+recentShards, 10) || !r.contains(-1001, 10) || r.contains(1, 10) {
+		t.Error("expected chats in one shard kept apart")
+	}
+
+	// Chats are recorded concurrently
+	var wg sync.WaitGroup
+	for chat := range int64(64) {
+		wg.Go(func() {
+			for id := range 100 {
+				r.add(chat, id)
+				r.contains(chat, id)
+			}
+		})
+	}
+	wg.Wait()
+	if !r.contains(63, 99) || !r.contains(63, 98) {
+		t.Error("expected concurrently recorded IDs to be found")
+	}
+}
+
+func TestSpareDedup(t *testing.T) {
+	d := newSpareDedup()
+	at := time.Date(2026, 1, 2, 15, 4, 0, 0, time.UTC)
+	msg := model.Message{ID: 10, ChatID: -4567, Text: "Selling RTX 5090", Date: at}
+	spare := msg
+	spare.ID, spare.Spare, spare.Date = 77, true, at.Add(time.Second)
+
+	if d.duplicate(msg) {
+		t.Error("expected the first spare delivered")
+	}
+	if !d.duplicate(spare) {
+		t.Error("expected the spare's spare dropped")
+	}
+
+	// The same text posted again is a new message for both accounts
+	again, againSpare := msg, spare
+	again.ID, againSpare.ID = 11, 78
+	if d.duplicate(againSpare) || !d.duplicate(again) {
+		t.Error("expected a repeated post delivered once, whichever account sees it first")
+	}
+
+	later := msg
+	later.Date = at.Add(time.Minute)
+	if d.duplicate(later) {
+		t.Error("expected the same text much later delivered")
+	}
+	other := spare
+	other.ChatID = -1001
+	if d.duplicate(other) {
+		t.Error("expected the same text in another chat delivered")
+	}
+}
+
+func TestScout_ContentHashDedup(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{Keywords: []string{"urgent"}},
+		Dedup:      config.DedupConfig{ContentHash: true},
+	}
+	notifier := &MockNotifier{NotifyChan: make(chan string, 10)}
+	s := New(cfg, notifier, zap.NewNop())
+
+	s.process(context.Background(), model.Message{ID: 1, ChatID: 100, Text: "Urgent  sale"})
+	s.process(context.Background(), model.Message{ID: 7, ChatID: 200, Text: "urgent sale"})
+
+	select {
+	case <-notifier.NotifyChan:
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("expected notification for first message")
+	}
+	select {
+	case <-notifier.NotifyChan:
+		t.Fatal("expected cross-posted duplicate to be suppressed")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestScout_ContentHashCleanup(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{Keywords: []string{"urgent"}},
+		Dedup:      config.DedupConfig{ContentHash: true, TTL: time.Millisecond, CleanupInterval: 10 * time.Millisecond},
+	}
+	s := New(cfg, &MockNotifier{NotifyChan: make(chan string, 10)}, zap.NewNop())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Start(ctx, make(chan model.Message))
+
+	s.process(ctx, model.Message{ID: 1, ChatID: 100, Text: "urgent sale"})
+	deadline := time.Now().Add(time.Second)
+	for s.seenContent.len() > 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the expired hash swept")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestScout_DigestWhenDegraded(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{Keywords: []string{"urgent"}},
+	}
+	notifier := &DegradableNotifier{}
+	s := New(cfg, notifier, zap.NewNop())
+
+	for i := 1; i <= 3; i++ {
+		s.process(context.Background(), model.Message{ID: i, ChatID: 100, ChatTitle: "Deals", Text: "urgent"})
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if got := len(notifier.Messages()); got != 0 {
+		t.Fatalf("expected no direct sends while degraded, got %d", got)
+	}
+	if got := s.digest.len(); got != 3 {
+		t.Fatalf("expected 3 queued alerts, got %d", got)
+	}
+
+	notifier.SetHealthy(true)
+	if !s.sendDigest(context.Background()) {
+		t.Fatal("expected digest to be sent")
+	}
+	msgs := notifier.Messages()
+	if len(msgs) != 1 || !strings.Contains(msgs[0], "3 alerts held back") {
+		t.Errorf("unexpected digest: %v", msgs)
+	}
+	if s.digest.len() != 0 {
+		t.Error("expected digest queue to be empty after delivery")
+	}
+}
+
+func TestScout_DigestAfterNotifierRecovers(t *testing.T) {
+	var mux sync.Mutex
+	var calls int
+	var digests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mux.Lock()
+		defer mux.Unlock()
+		calls++
+		if calls <= 3 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if strings.Contains(string(body), "Digest") {
+			digests = append(digests, string(body))
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		BotToken:   "token",
+		ChatID:     1,
+		Monitoring: config.MonitoringRules{Keywords: []string{"urgent"}},
+		Notifier: config.NotifierConfig{
+			APIURL: server.URL,
+			Retry:  config.RetryConfig{Attempts: 1, BreakerThreshold: 10, BreakerCooldown: 50 * time.Millisecond},
+		},
+		Pipeline: config.PipelineConfig{DigestInterval: 10 * time.Millisecond},
+	}
+	tn, err := notifier.New(cfg, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := New(cfg, tn, zap.NewNop())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.flushDigests(ctx)
+
+	for i := 1; i <= 3; i++ {
+		s.process(ctx, model.Message{ID: i, ChatID: 100, ChatTitle: "Deals", Text: "urgent"})
+		time.Sleep(10 * time.Millisecond)
+	}
+	if tn.Healthy() {
+		t.Fatal("expected the notifier to be unhealthy after 3 failed sends")
+	}
+	s.process(ctx, model.Message{ID: 4, ChatID: 100, ChatTitle: "Deals", Text: "urgent"})
+	if got := s.digest.len(); got != 1 {
+		t.Fatalf("expected the alert held for the digest, got %d", got)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for s.digest.len() > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	mux.Lock()
+	defer mux.Unlock()
+	if len(digests) != 1 || !strings.Contains(digests[0], "1 alerts held back") {
+		t.Errorf("expected the digest delivered once the notifier recovered, got %v", digests)
+	}
+	if !tn.Healthy() {
+		t.Error("expected the delivered digest to restore health")
+	}
+}
+
+func TestDigestQueue(t *testing.T) {
+	q := newDigestQueue(2)
+	q.add("a")
+	q.add("b")
+	q.add("c")
+
+	lines, dropped := q.take()
+	if len(lines) != 2 || lines[0] != "b" || dropped != 1 {
+		t.Errorf("unexpected take result: %v, dropped %d", lines, dropped)
+	}
+
+	q.requeue(lines, dropped)
+	if q.len() != 2 {
+		t.Errorf("expected requeued lines, got %d", q.len())
+	}
+}
+
+func TestScout_SharedState(t *testing.T) {
+	mr := miniredis.RunT(t)
+	store, err := cluster.New(context.Background(), config.ClusterConfig{Redis: "redis://" + mr.Addr(), Prefix: "test"})
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{Keywords: []string{"urgent"}},
+		Dedup:      config.DedupConfig{ContentHash: true},
+	}
+	notifier := &DegradableNotifier{}
+	notifier.SetHealthy(true)
+	a := New(cfg, notifier, zap.NewNop())
+	b := New(cfg, notifier, zap.NewNop())
+	a.UseSharedState(store)
+	b.UseSharedState(store)
+
+	// Both instances see the same post, and a cross-post of it
+	msg := model.Message{ID: 1, ChatID: 100, ChatTitle: "Deals", Text: "urgent sale"}
+	a.process(context.Background(), msg)
+	b.process(context.Background(), msg)
+	msg.ChatID = 200
+	b.process(context.Background(), msg)
+
+	time.Sleep(20 * time.Millisecond)
+	if got := len(notifier.Messages()); got != 1 {
+		t.Fatalf("expected one alert across instances, got %d", got)
+	}
+
+	// Alerts held back by one instance can be flushed by another
+	notifier.SetHealthy(false)
+	a.process(context.Background(), model.Message{ID: 2, ChatID: 100, ChatTitle: "Deals", Text: "urgent restock"})
+	if got := b.digest.len(); got != 1 {
+		t.Fatalf("expected shared digest to hold 1 alert, got %d", got)
+	}
+	notifier.SetHealthy(true)
+	if !b.sendDigest(context.Background()) {
+		t.Fatal("expected digest to be sent")
+	}
+	if a.digest.len() != 0 {
+		t.Error("expected shared digest to be empty after delivery")
+	}
+}
+
+// Return a fixed URL for every archived message
+type fakeArchiver struct{ url string }
+
+func (f fakeArchiver) Archive(ctx context.Context, msg model.Message) (string, error) {
+	return f.url, nil
+}
+
+func TestScout_MediaArchive(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{Keywords: []string{"urgent"}},
+	}
+	notifier := &MockNotifier{NotifyChan: make(chan string, 10)}
+	s := New(cfg, notifier, zap.NewNop())
+	// Presigned URLs carry their signature in the query
+	s.UseMediaArchiver(fakeArchiver{url: "https://media.example.com/100/1.jpg?X-Amz-Expires=604800&X-Amz-Signature=abc"})
+
+	s.process(context.Background(), model.Message{ID: 1, ChatID: 100, Text: "urgent", Media: "photo"})
+	s.process(context.Background(), model.Message{ID: 2, ChatID: 100, Text: "urgent too"})
+
+	for range 2 {
+		select {
+		case msg := <-notifier.NotifyChan:
+			archived := strings.Contains(msg, `href="https://media.example.com/100/1.jpg?X-Amz-Expires=604800&amp;X-Amz-Signature=abc"`)
+			if strings.Contains(msg, "urgent too") == archived {
+				t.Errorf("archived link only expected for messages with media: %s", msg)
+			}
+		case <-time.After(100 * time.Millisecond):
+			t.Fatal("expected notification")
+		}
+	}
+}
+
+// Attach a fixed verdict to every document
+type fakeEnricher struct{}
+
+func (fakeEnricher) Enrich(ctx context.Context, msg model.Message) ([]string, error) {
+	return []string{"🛡 <b>VirusTotal:</b> 3/70 engines flag it"}, nil
+}
+
+func TestScout_Enrichment(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{Files: []config.FileRule{{Filename: "*.apk"}}},
+	}
+	notifier := &MockNotifier{NotifyChan: make(chan string, 10)}
+	s := New(cfg, notifier, zap.NewNop())
+	s.UseEnricher(fakeEnricher{})
+
+	s.process(context.Background(), model.Message{ID: 1, ChatID: 100, Media: "document", File: &model.File{Name: "update.apk"}})
+
+	select {
+	case msg := <-notifier.NotifyChan:
+		if !strings.Contains(msg, "3/70 engines flag it") {
+			t.Errorf("expected verdict in alert, got %s", msg)
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("expected notification")
+	}
+}
+
+// Match every photo against a single reference
+type fakeImageMatcher struct{}
+
+func (fakeImageMatcher) MatchImage(ctx context.Context, msg model.Message) (string, int, error) {
+	if msg.Media != "photo" {
+		return "", 0, nil
+	}
+	return "logo", 3, nil
+}
+
+func TestScout_ImageRules(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{Keywords: []string{"urgent"}},
+	}
+	notifier := &MockNotifier{NotifyChan: make(chan string, 10)}
+	s := New(cfg, notifier, zap.NewNop())
+	s.UseImageMatcher(fakeImageMatcher{})
+
+	s.process(context.Background(), model.Message{ID: 1, ChatID: 100, Media: "photo"})
+	s.process(context.Background(), model.Message{ID: 2, ChatID: 100, Media: "video"})
+
+	select {
+	case msg := <-notifier.NotifyChan:
+		if !strings.Contains(msg, "image:logo") {
+			t.Errorf("expected image rule in alert, got %s", msg)
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("expected notification for matching photo")
+	}
+	select {
+	case msg := <-notifier.NotifyChan:
+		t.Fatalf("unexpected notification %s", msg)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	exps := s.Explanations(0, "", 10)
+	if len(exps) != 1 || exps[0].Kind != kindImage || exps[0].Matched != "distance 3" {
+		t.Errorf("unexpected explanations %+v", exps)
+	}
+}
+
+func TestScout_FileRules(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{
+			Keywords: []string{"urgent"},
+			Files: []config.FileRule{
+				{Filename: "*.apk"},
+				{Name: "combolist", Filename: "combo*.txt", MinSize: 1 << 20},
+				{MimeType: "application/x-ms*", MaxSize: 10 << 20},
+			},
+		},
+	}
+	s := New(cfg, &MockNotifier{}, zap.NewNop())
+
+	tests := []struct {
+		name string
+		file *model.File
+		want string
+	}{
+		{"Extension", &model.File{Name: "Update.APK", Size: 100}, "file:*.apk"},
+		{"Glob And Size", &model.File{Name: "combo_2026.txt", Size: 5 << 20}, "file:combolist"},
+		{"Too Small", &model.File{Name: "combo_2026.txt", Size: 100}, ""},
+		{"Mime Type", &model.File{Name: "setup.exe", MimeType: "application/x-msdownload", Size: 1 << 20}, "file:application/x-ms* <=10485760 bytes"},
+		{"Too Large", &model.File{Name: "setup.exe", MimeType: "application/x-msdownload", Size: 20 << 20}, ""},
+		{"No File", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exp, ok := s.evaluate(model.Message{Media: "document", File: tt.file})
+			if ok != (tt.want != "") || exp.Keyword != tt.want {
+				t.Errorf("evaluate() = %q %t, want %q", exp.Keyword, ok, tt.want)
+			}
+			if ok && exp.Kind != kindFile {
+				t.Errorf("expected kind %q, got %q", kindFile, exp.Kind)
+			}
+		})
+	}
+
+	// Text rules take precedence
+	exp, _ := s.evaluate(model.Message{Text: "urgent", File: &model.File{Name: "a.apk"}})
+	if exp.Keyword != "urgent" {
+		t.Errorf("expected text match first, got %q", exp.Keyword)
+	}
+}
+
+// Resolve every bit.ly link to a phishing page
+type fakeExpander struct{}
+
+func (fakeExpander) IsShort(link string) bool {
+	return strings.Contains(link, "bit.ly")
+}
+
+func (fakeExpander) Expand(ctx context.Context, link string) (string, error) {
+	return "https://login.evil.com/steal", nil
+}
+
+func TestScout_Domains(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{Keywords: []string{"urgent"}},
+		URLs: config.URLsConfig{
+			BlockDomains: []string{"evil.com"},
+			AllowDomains: []string{"example.com"},
+		},
+	}
+	notifier := &MockNotifier{NotifyChan: make(chan string, 10)}
+	s := New(cfg, notifier, zap.NewNop())
+	s.UseLinkExpander(fakeExpander{})
+
+	// Links as written
+	exp, ok := s.evaluate(model.Message{Text: "see https://www.evil.com/x"})
+	if !ok || exp.Keyword != "domain:evil.com" || exp.Kind != kindDomain {
+		t.Errorf("expected domain match, got %+v", exp)
+	}
+
+	// Messages linking only to allowed domains are skipped
+	if got := s.filtered(model.Message{Text: "urgent: https://docs.example.com/a"}); got != "allowed_domains" {
+		t.Errorf("expected allowed_domains filter, got %q", got)
+	}
+	if got := s.filtered(model.Message{Text: "urgent: https://example.com https://other.org"}); got != "" {
+		t.Errorf("expected mixed links to be evaluated, got %q", got)
+	}
+
+	// Short links are expanded before matching, and in alerts
+	s.process(context.Background(), model.Message{ID: 1, ChatID: 100, Text: "free stuff https://bit.ly/abc"})
+	select {
+	case msg := <-notifier.NotifyChan:
+		if !strings.Contains(msg, "domain:evil.com") || !strings.Contains(msg, "https://bit.ly/abc → <code>https://login.evil.com/steal</code>") {
+			t.Errorf("expected expanded domain match, got %s", msg)
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("expected notification for expanded link")
+	}
+}
+
+// Store a screenshot per link under a predictable URL
+type fakeScreenshotter struct{}
+
+func (fakeScreenshotter) Screenshot(ctx context.Context, msg model.Message, link string) (string, error) {
+	if strings.Contains(link, "down") {
+		return "", errors.New("renderer unavailable")
+	}
+	return "https://media.example.com/shot.png", nil
+}
+
+func TestScout_Screenshots(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{Keywords: []string{"urgent"}},
+		Shots:      config.ScreenshotConfig{MaxLinks: 2},
+	}
+	notifier := &MockNotifier{NotifyChan: make(chan string, 10)}
+	s := New(cfg, notifier, zap.NewNop())
+	s.UseScreenshotter(fakeScreenshotter{})
+
+	s.process(context.Background(), model.Message{ID: 1, ChatID: 100, Text: "urgent https://down.example.com https://shop.example.com/a https://third.example.com"})
+
+	select {
+	case msg := <-notifier.NotifyChan:
+		if strings.Count(msg, "Screenshot</a>") != 1 || !strings.Contains(msg, "of https://shop.example.com/a") {
+			t.Errorf("expected one screenshot for the second link, got %s", msg)
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("expected notification")
+	}
+}
+
+// Know a single channel, every other reference is a user
+type fakeResolver struct{ calls int }
+
+func (f *fakeResolver) ResolveChat(ctx context.Context, ref mentions.Ref) (mentions.Info, error) {
+	f.calls++
+	if ref.Username != "leaks" {
+		return mentions.Info{}, errors.New("not a chat")
+	}
+	return mentions.Info{Title: "Leaks & Dumps", Members: 1200, Broadcast: true}, nil
+}
+
+func TestScout_Mentions(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{Keywords: []string{"urgent"}},
+		Mentions:   config.MentionsConfig{Enabled: true, Cooldown: time.Hour},
+	}
+	notifier := &MockNotifier{NotifyChan: make(chan string, 10)}
+	s := New(cfg, notifier, zap.NewNop())
+	resolver := &fakeResolver{}
+	s.UseChatResolver(resolver)
+
+	s.process(context.Background(), model.Message{ID: 1, ChatID: 100, Text: "thanks @someone, more at t.me/leaks"})
+	select {
+	case msg := <-notifier.NotifyChan:
+		if !strings.Contains(msg, "mention:@leaks") || !strings.Contains(msg, "<b>Leaks &amp; Dumps</b>, channel with 1200 members") {
+			t.Errorf("expected mention alert with chat details, got %s", msg)
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("expected notification for referenced channel")
+	}
+
+	// Reported chats stay quiet during the cooldown, without new lookups
+	calls := resolver.calls
+	s.process(context.Background(), model.Message{ID: 2, ChatID: 100, Text: "join @leaks"})
+	select {
+	case msg := <-notifier.NotifyChan:
+		t.Errorf("expected no alert within cooldown, got %s", msg)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if resolver.calls != calls {
+		t.Errorf("expected cached lookup, got %d new calls", resolver.calls-calls)
+	}
+}
+
+func TestScout_Graph(t *testing.T) {
+	cfg := &config.Config{Filters: config.FiltersConfig{IgnoreBots: true}}
+	s := New(cfg, &MockNotifier{}, zap.NewNop())
+	g := graph.New()
+	s.UseGraph(g)
+
+	// Recorded before filters, without any rule matching
+	s.process(context.Background(), model.Message{
+		ID: 1, ChatID: -1001, ChatTitle: "Mirror", FromBot: true,
+		Text:    "via @Origin, also t.me/+Inv1te",
+		Forward: &model.Forward{ChatID: -1002, Title: "Origin", Username: "origin"},
+	})
+	s.process(context.Background(), model.Message{ID: 2, ChatID: -1001, Forward: &model.Forward{Title: "Hidden Author"}})
+
+	snap := g.Snapshot()
+	var got []string
+	for _, e := range snap.Edges {
+		got = append(got, e.From+" "+e.Kind+" "+e.To)
+	}
+	want := []string{"-1001 mention @origin", "-1001 mention t.me/+Inv1te", "@origin forward -1001", "name:Hidden Author forward -1001"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("unexpected edges %v, want %v", got, want)
+	}
+}
+
+// Record matches handed to webhooks
+type fakeWebhooks struct{ matches chan model.Match }
+
+func (f fakeWebhooks) Deliver(ctx context.Context, m model.Match) error {
+	f.matches <- m
+	return nil
+}
+
+func TestScout_Webhooks(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{Keywords: []string{"urgent"}},
+	}
+	s := New(cfg, &MockNotifier{}, zap.NewNop())
+	hooks := fakeWebhooks{matches: make(chan model.Match, 1)}
+	s.UseWebhooks(hooks)
+
+	s.process(context.Background(), model.Message{ID: 4, ChatID: 100, ChatTitle: "Chat", Text: "very URGENT"})
+	select {
+	case m := <-hooks.matches:
+		if m.Rule != "urgent" || m.Kind != kindWord || m.Matched != "URGENT" || m.MsgID != 4 || m.Text != "very URGENT" {
+			t.Errorf("unexpected match %+v", m)
+		}
+	default:
+		t.Fatal("expected match to be handed to webhooks")
+	}
+}
+
+func TestScout_Instance(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{Keywords: []string{"urgent"}},
+		Instance:   config.InstanceConfig{Name: "eu-1", Labels: map[string]string{"team": "intel", "env": "prod"}},
+	}
+	mock := &MockNotifier{NotifyChan: make(chan string, 1)}
+	s := New(cfg, mock, zap.NewNop())
+	hooks := fakeWebhooks{matches: make(chan model.Match, 1)}
+	s.UseWebhooks(hooks)
+
+	s.process(context.Background(), model.Message{ID: 4, ChatID: 100, ChatTitle: "Chat", Text: "urgent"})
+	select {
+	case m := <-hooks.matches:
+		if m.Instance != "eu-1" || m.Labels["team"] != "intel" {
+			t.Errorf("expected the instance in the webhook match, got %+v", m)
+		}
+	default:
+		t.Fatal("expected match to be handed to webhooks")
+	}
+	select {
+	case msg := <-mock.NotifyChan:
+		if !strings.Contains(msg, "🖥 <b>Instance:</b> eu-1 (env=prod, team=intel)") {
+			t.Errorf("expected the instance in the alert, got %q", msg)
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("expected notification")
+	}
+}
+
+func TestScout_LatencyBudget(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{Keywords: []string{"urgent"}},
+		Pipeline:   config.PipelineConfig{LatencyBudget: 10 * time.Second},
+	}
+	mock := &MockNotifier{NotifyChan: make(chan string, 4)}
+	s := New(cfg, mock, zap.NewNop())
+	over := func() int64 {
+		if v, ok := latencyMetrics.Get("over_budget").(*expvar.Int); ok {
+			return v.Value()
+		}
+		return 0
+	}
+	before := over()
+
+	// History has no receipt time and is never measured
+	s.process(context.Background(), model.Message{ID: 1, ChatID: 100, Text: "urgent", Date: time.Now().Add(-time.Hour)})
+	<-mock.NotifyChan
+	// Posted a minute ago, received just now
+	now := time.Now()
+	s.process(context.Background(), model.Message{ID: 2, ChatID: 100, Text: "urgent", Date: now.Add(-time.Minute), Received: now})
+	<-mock.NotifyChan
+	select {
+	case msg := <-mock.NotifyChan:
+		if !strings.Contains(msg, "Alerts are lagging") || !strings.Contains(msg, "10s budget") {
+			t.Errorf("unexpected latency warning %q", msg)
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("expected a latency warning")
+	}
+	if got := over() - before; got != 1 {
+		t.Errorf("expected one alert over budget, got %d", got)
+	}
+	if v, ok := latencyMetrics.Get("total_max_ms").(*expvar.Float); !ok || v.Value() < 60000 {
+		t.Errorf("expected the maximum latency published, got %v", latencyMetrics.Get("total_max_ms"))
+	}
+
+	// Further lag is only logged until the notice interval passes
+	now = time.Now()
+	s.process(context.Background(), model.Message{ID: 3, ChatID: 100, Text: "urgent", Date: now.Add(-time.Minute), Received: now})
+	<-mock.NotifyChan
+	select {
+	case msg := <-mock.NotifyChan:
+		t.Errorf("expected no second warning, got %q", msg)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// Deliver posted canaries back to the scout, unless dropping them
+type fakeCanary struct {
+	s    *Scout
+	drop bool
+}
+
+func (f *fakeCanary) Post(ctx context.Context, chat, text string) error {
+	if !f.drop {
+		go f.s.process(ctx, model.Message{ID: 1, ChatID: -1000000000005, Text: text, Date: time.Now()})
+	}
+	return nil
+}
+
+func TestScout_Canary(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{Keywords: []string{"canary"}},
+		Canary:     config.CanaryConfig{Chat: "-1005", Interval: time.Minute, Timeout: 200 * time.Millisecond},
+	}
+	mock := &MockNotifier{NotifyChan: make(chan string, 4)}
+	s := New(cfg, mock, zap.NewNop())
+	poster := &fakeCanary{s: s}
+	s.UseCanary(poster)
+
+	// The loop completes with a canary alert, the rules never see it
+	s.checkCanary(context.Background())
+	select {
+	case msg := <-mock.NotifyChan:
+		if !strings.HasPrefix(msg, "🐤 <b>Canary:</b> received") {
+			t.Errorf("unexpected canary alert %q", msg)
+		}
+	default:
+		t.Fatal("expected a canary alert")
+	}
+
+	// A canary that never arrives is reported
+	poster.drop = true
+	s.checkCanary(context.Background())
+	select {
+	case msg := <-mock.NotifyChan:
+		if !strings.Contains(msg, "Canary failed:</b> it was not received") {
+			t.Errorf("unexpected canary failure %q", msg)
+		}
+	default:
+		t.Fatal("expected a canary failure")
+	}
+
+	// Stale canaries are dropped quietly
+	s.process(context.Background(), model.Message{ID: 2, ChatID: -1000000000005, Text: canaryPrefix + "0123"})
+	select {
+	case msg := <-mock.NotifyChan:
+		t.Errorf("expected a stale canary ignored, got %q", msg)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestScout_ReloadKeywords(t *testing.T) {
+	cfg := &config.Config{Monitoring: config.MonitoringRules{Keywords: []string{"gpu", "ram"}}}
+	mock := &MockNotifier{NotifyChan: make(chan string, 8)}
+	s := New(cfg, mock, zap.NewNop())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Without a soak the keywords switch at once
+	s.ReloadKeywords(ctx, []string{"gpu", "re:[", "ssd"})
+	if n, rejected := s.Rules(); n != 2 || len(rejected) != 1 {
+		t.Errorf("expected 2 rules and 1 rejected, got %d and %v", n, rejected)
+	}
+	if _, ok := s.evaluate(model.Message{Text: "ssd"}); !ok {
+		t.Error("expected the reloaded keyword to match")
+	}
+
+	// With one, both sets are evaluated and the difference reported on switching
+	cfg.Matching.Soak = 100 * time.Millisecond
+	s.ReloadKeywords(ctx, []string{"gpu", "cpu"})
+	for i, text := range []string{"gpu", "ssd", "cpu", "nothing"} {
+		s.process(ctx, model.Message{ID: i + 1, ChatID: 100, Text: text, Link: fmt.Sprintf("tg://privatepost?channel=100&post=%d", i+1)})
+	}
+	diff, ok := s.Soak()
+	if !ok || diff.Messages != 4 || diff.Both != 1 || diff.Lost["ssd"] != 1 || diff.Added["cpu"] != 1 || !slices.Equal(diff.Missed, []string{"tg://privatepost?channel=100&post=2"}) {
+		t.Errorf("unexpected soak diff %+v", diff)
+	}
+	if _, ok := s.evaluate(model.Message{Text: "ssd"}); !ok {
+		t.Error("expected the live keywords kept while soaking")
+	}
+
+	deadline := time.After(time.Second)
+	for {
+		select {
+		case msg := <-mock.NotifyChan:
+			if !strings.Contains(msg, "Keywords reloaded") {
+				continue
+			}
+			if !strings.Contains(msg, "Only by the old keywords: ssd ×1") || !strings.Contains(msg, "Only by the new keywords: cpu ×1") ||
+				!strings.Contains(msg, `<a href="tg://privatepost?channel=100&amp;post=2">missed message</a>`) {
+				t.Errorf("unexpected soak report %q", msg)
+			}
+			if _, ok := s.Soak(); ok {
+				t.Error("expected the soak over")
+			}
+			if _, ok := s.evaluate(model.Message{Text: "cpu"}); !ok {
+				t.Error("expected the soaked keywords live")
+			}
+			return
+		case <-deadline:
+			t.Fatal("expected a soak report")
+		}
+	}
+}
+
+// Record messages handed to mirrors
+type fakeMirror struct{ copies chan string }
+
+func (f fakeMirror) Mirror(ctx context.Context, chatID int64, msgID int, to string) error {
+	f.copies <- fmt.Sprintf("%d/%d>%s", chatID, msgID, to)
+	return nil
+}
+
+func TestScout_Mirror(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{Keywords: []string{"urgent", "sale"}},
+		Mirrors: []config.MirrorConfig{
+			{To: "@urgent_mirror", Rules: []string{"urg*"}},
+			{To: "-1005", Chats: []string{"-1001"}},
+			{To: "-1001", Rules: []string{"urgent"}}, // Never back into the source chat
+		},
+	}
+	s := New(cfg, &MockNotifier{}, zap.NewNop())
+	m := fakeMirror{copies: make(chan string, 8)}
+	s.UseMirror(m)
+
+	// Channels 1 and 2, written -1001 and -1002 in config
+	s.process(context.Background(), model.Message{ID: 1, ChatID: -1000000000001, Text: "urgent"})
+	s.process(context.Background(), model.Message{ID: 2, ChatID: -1000000000002, Text: "sale"})
+	s.process(context.Background(), model.Message{ID: 3, ChatID: -1000000000002, Text: "urgent"})
+
+	var got []string
+	for range 4 {
+		select {
+		case c := <-m.copies:
+			got = append(got, c)
+		case <-time.After(time.Second):
+			t.Fatalf("expected 4 copies, got %v", got)
+		}
+	}
+	slices.Sort(got)
+	want := []string{"-1000000000001/1>-1005", "-1000000000001/1>@urgent_mirror", "-1000000000002/3>-1001", "-1000000000002/3>@urgent_mirror"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got copies %v, want %v", got, want)
+	}
+	select {
+	case c := <-m.copies:
+		t.Errorf("unexpected copy %s", c)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestScout_RuleExpiry(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	cfg := &config.Config{Monitoring: config.MonitoringRules{
+		Keywords: []string{"sale", "launch", "gpu"},
+		Files:    []config.FileRule{{Filename: "*.apk"}},
+		Expires:  map[string]time.Time{"sale": past, "file:*": past, "launch": time.Now().Add(time.Hour)},
+	}}
+	s := New(cfg, &MockNotifier{}, zap.NewNop())
+	ctx := context.Background()
+
+	if _, ok := s.evaluate(model.Message{Text: "sale"}); ok {
+		t.Error("expected an expired keyword not to match")
+	}
+	if _, ok := s.evaluate(model.Message{Text: "launch"}); !ok {
+		t.Error("expected a keyword before its expiry to match")
+	}
+	s.process(ctx, model.Message{ID: 1, ChatID: 100, Media: "document", File: &model.File{Name: "app.apk"}})
+	if got := s.Explanations(0, "", 10); len(got) != 0 {
+		t.Errorf("expected no alert on an expired file rule, got %+v", got)
+	}
+
+	// Keywords are dropped once their time comes
+	cfg.Monitoring.Expires["launch"] = past
+	s.refreshRules()
+	if _, ok := s.evaluate(model.Message{Text: "launch"}); ok {
+		t.Error("expected the keyword dropped after expiring")
+	}
+
+	// Temporary keywords from bot commands
+	store, err := temprules.Load("")
+	if err != nil {
+		t.Fatalf("temprules.Load() error = %v", err)
+	}
+	s.UseTemporaryRules(store)
+	cmds := s.Commands()
+	reply, err := cmds["addkeyword"](ctx, notifier.Command{Args: []string{"black", "friday", "48h"}, User: "alice"})
+	if err != nil || !strings.Contains(reply, "black friday") {
+		t.Fatalf("unexpected reply %q, %v", reply, err)
+	}
+	if _, ok := s.evaluate(model.Message{Text: "Black Friday deals"}); !ok {
+		t.Error("expected the temporary keyword to match")
+	}
+	if reply, _ := cmds["tempkeywords"](ctx, notifier.Command{}); !strings.Contains(reply, "black friday until") {
+		t.Errorf("expected the temporary keyword listed, got %q", reply)
+	}
+	if _, err := cmds["addkeyword"](ctx, notifier.Command{Args: []string{"re:[", "1h"}}); err == nil {
+		t.Error("expected an invalid keyword refused")
+	}
+	if _, err := cmds["addkeyword"](ctx, notifier.Command{Args: []string{"forever"}}); err == nil {
+		t.Error("expected a keyword without a duration refused")
+	}
+
+	// Reloads keep temporary keywords
+	s.ReloadKeywords(ctx, []string{"gpu", "ssd"})
+	if _, ok := s.evaluate(model.Message{Text: "black friday"}); !ok {
+		t.Error("expected the temporary keyword to survive a reload")
+	}
+	if _, err := cmds["removekeyword"](ctx, notifier.Command{Args: []string{"black", "friday"}}); err != nil {
+		t.Fatalf("removekeyword error = %v", err)
+	}
+	if _, ok := s.evaluate(model.Message{Text: "black friday"}); ok {
+		t.Error("expected the removed keyword not to match")
+	}
+	if _, ok := s.evaluate(model.Message{Text: "ssd"}); !ok {
+		t.Error("expected the reloaded keywords kept")
+	}
+}
+
+// Record actions run on messages
+type fakeActor struct{ done chan string }
+
+func (f fakeActor) React(ctx context.Context, chatID int64, msgID int, emoji string) error {
+	f.done <- fmt.Sprintf("react %d/%d %s", chatID, msgID, emoji)
+	return nil
+}
+
+func (f fakeActor) Forward(ctx context.Context, chatID int64, msgID int, to string) error {
+	f.done <- fmt.Sprintf("forward %d/%d %q", chatID, msgID, to)
+	return nil
+}
+
+func TestScout_Actions(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{Keywords: []string{"deal", "urgent"}},
+		Actions: config.ActionsConfig{RateLimit: 2, Hooks: []config.ActionHook{
+			{Do: config.ActionReact, Emoji: "👍", Rules: []string{"deal"}},
+			{Do: config.ActionSave, Rules: []string{"deal"}},
+		}},
+	}
+	s := New(cfg, &MockNotifier{}, zap.NewNop())
+	actor := fakeActor{done: make(chan string, 4)}
+	s.UseActor(actor)
+
+	s.process(context.Background(), model.Message{ID: 1, ChatID: 100, Text: "urgent"})
+	s.process(context.Background(), model.Message{ID: 2, ChatID: 100, Text: "deal"})
+	var got []string
+	for range 2 {
+		select {
+		case a := <-actor.done:
+			got = append(got, a)
+		case <-time.After(time.Second):
+			t.Fatalf("expected 2 actions, got %v", got)
+		}
+	}
+	slices.Sort(got)
+	if want := []string{`forward 100/2 ""`, "react 100/2 👍"}; !slices.Equal(got, want) {
+		t.Errorf("got actions %v, want %v", got, want)
+	}
+
+	// Both runs of the hour are used up
+	s.process(context.Background(), model.Message{ID: 3, ChatID: 100, Text: "another deal"})
+	select {
+	case a := <-actor.done:
+		t.Errorf("expected the rate limit to drop %s", a)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestScout_ConfirmActions(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{Keywords: []string{"deal"}},
+		Actions: config.ActionsConfig{RateLimit: 5, Confirm: true, Hooks: []config.ActionHook{
+			{Do: config.ActionForward, To: "@bookmarks", Chats: []string{"100"}},
+		}},
+	}
+	notif := &MockNotifier{NotifyChan: make(chan string, 1)}
+	s := New(cfg, notif, zap.NewNop())
+	actor := fakeActor{done: make(chan string, 1)}
+	s.UseActor(actor)
+
+	s.process(context.Background(), model.Message{ID: 7, ChatID: 100, Text: "deal"})
+	if alert := <-notif.NotifyChan; !strings.Contains(alert, "⏳ <b>Action:</b> forward to @bookmarks, /confirm 1") {
+		t.Errorf("expected the held action in the alert, got %s", alert)
+	}
+	select {
+	case a := <-actor.done:
+		t.Fatalf("expected the action held, got %s", a)
+	default:
+	}
+
+	cmds := s.Commands()
+	if reply, err := cmds["pending"](context.Background(), notifier.Command{}); err != nil || !strings.Contains(reply, "1: forward to @bookmarks on message 7") {
+		t.Errorf("unexpected /pending reply %q, %v", reply, err)
+	}
+	if _, err := cmds["confirm"](context.Background(), notifier.Command{Args: []string{"2"}}); err == nil {
+		t.Error("expected an unknown action to fail")
+	}
+	if reply, err := cmds["confirm"](context.Background(), notifier.Command{Args: []string{"1"}, User: "alice"}); err != nil || !strings.HasPrefix(reply, "✅ Done") {
+		t.Fatalf("unexpected /confirm reply %q, %v", reply, err)
+	}
+	if a := <-actor.done; a != `forward 100/7 "@bookmarks"` {
+		t.Errorf("unexpected action %s", a)
+	}
+	if reply, _ := cmds["pending"](context.Background(), notifier.Command{}); !strings.Contains(reply, "No actions") {
+		t.Errorf("expected nothing pending after confirming, got %q", reply)
+	}
+}
+
+// Collect archived records
+type fakeArchive struct{ records []archive.Record }
+
+func (f *fakeArchive) Add(r archive.Record) { f.records = append(f.records, r) }
+
+func TestScout_Archive(t *testing.T) {
+	for _, all := range []bool{false, true} {
+		cfg := &config.Config{
+			Monitoring: config.MonitoringRules{Keywords: []string{"urgent"}},
+			Archive:    config.ArchiveConfig{All: all},
+			Explain:    config.ExplainConfig{Size: 10},
+		}
+		s := New(cfg, &MockNotifier{}, zap.NewNop())
+		archived := &fakeArchive{}
+		s.UseArchive(archived)
+
+		s.process(context.Background(), model.Message{ID: 1, ChatID: 100, Text: "nothing to see"})
+		s.process(context.Background(), model.Message{ID: 2, ChatID: 100, Text: "urgent"})
+
+		var kinds []string
+		for _, r := range archived.records {
+			kinds = append(kinds, r.Kind)
+		}
+		want := []string{archive.KindMatch}
+		if all {
+			want = []string{archive.KindMessage, archive.KindMessage, archive.KindMatch}
+		}
+		if !slices.Equal(kinds, want) {
+			t.Errorf("all=%v: expected %v archived, got %v", all, want, kinds)
+		}
+		if last := archived.records[len(archived.records)-1]; last.Rule != "urgent" || last.MsgID != 2 {
+			t.Errorf("all=%v: unexpected match record %+v", all, last)
+		}
+	}
+}
+
+func TestScout_Tags(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{
+			Keywords: []string{"urgent", "sale"},
+			Tags: map[string][]string{
+				"urgent": {"project-a", "oncall"},
+				"urg*":   {"oncall"},
+				"file:*": {"malware"},
+			},
+		},
+		Explain: config.ExplainConfig{Size: 10},
+	}
+	notif := &MockNotifier{NotifyChan: make(chan string, 2)}
+	s := New(cfg, notif, zap.NewNop())
+	hooks := fakeWebhooks{matches: make(chan model.Match, 2)}
+	s.UseWebhooks(hooks)
+
+	s.process(context.Background(), model.Message{ID: 1, ChatID: 100, ChatTitle: "Chat", Text: "urgent"})
+	s.process(context.Background(), model.Message{ID: 2, ChatID: 100, ChatTitle: "Chat", Text: "sale"})
+
+	if m := <-hooks.matches; !slices.Equal(m.Tags, []string{"oncall", "project-a"}) {
+		t.Errorf("expected sorted, deduplicated tags in the webhook match, got %v", m.Tags)
+	}
+	if m := <-hooks.matches; m.Tags != nil {
+		t.Errorf("expected no tags for an untagged rule, got %v", m.Tags)
+	}
+	for range 2 {
+		select {
+		case msg := <-notif.NotifyChan:
+			if tagged := strings.Contains(msg, "<b>Tags:</b> oncall, project-a"); tagged != strings.Contains(msg, "urgent") {
+				t.Errorf("expected tags only in the tagged alert, got %q", msg)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected two alerts")
+		}
+	}
+
+	if got := s.Explanations(0, "oncall", 10); len(got) != 1 || got[0].Keyword != "urgent" {
+		t.Errorf("expected the tagged alert only, got %+v", got)
+	}
+	if got := s.Explanations(0, "malware", 10); len(got) != 0 {
+		t.Errorf("expected no alerts for an unused tag, got %+v", got)
+	}
+	if v := matchMetrics.Get("tag:project-a"); v == nil || v.String() == "0" {
+		t.Error("expected the tag to be counted")
+	}
+}
+
+func TestReminderText(t *testing.T) {
+	text := reminderText(acks.Alert{
+		ID: "-1001234:5", Rule: "a<b", ChatTitle: "Deals & <Co>", Created: time.Now(),
+		Link: "tg://privatepost?channel=1234&post=5",
+	})
+	for _, want := range []string{
+		"a&lt;b in Deals &amp; &lt;Co&gt;",
+		`<a href="tg://privatepost?channel=1234&amp;post=5">`,
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected reminder to contain %q, got:\n%s", want, text)
+		}
+	}
+}
+
+func TestScout_Acks(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{
+			Keywords: []string{"urgent", "sale"},
+			Tags:     map[string][]string{"urgent": {"oncall"}},
+		},
+		Acks: config.AcksConfig{Enabled: true, CriticalTags: []string{"oncall"}},
+	}
+	notif := &MockNotifier{NotifyChan: make(chan string, 2)}
+	s := New(cfg, notif, zap.NewNop())
+	store := acks.New(10)
+	s.UseAcks(store)
+
+	s.process(context.Background(), model.Message{ID: 1, ChatID: 100, ChatTitle: "Chat", Text: "urgent"})
+	s.process(context.Background(), model.Message{ID: 2, ChatID: 100, ChatTitle: "Chat", Text: "sale"})
+	for range 2 {
+		select {
+		case msg := <-notif.NotifyChan:
+			if !strings.Contains(msg, "<b>Alert:</b> <code>100:") {
+				t.Errorf("expected the alert ID in %q", msg)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected two alerts")
+		}
+	}
+
+	if a, ok := store.Get("100:1"); !ok || !a.Critical || a.Rule != "urgent" {
+		t.Errorf("expected critical tracked alert, got %+v", a)
+	}
+	if a, _ := store.Get("100:2"); a.Critical {
+		t.Error("expected alert without a critical tag not to be critical")
+	}
+
+	reply, next, err := s.HandleButton(context.Background(), notifier.ActionAck, "100:1", "alice")
+	if err != nil || reply != "Acknowledged by alice" || !slices.Equal(next, []string{notifier.ActionResolve}) {
+		t.Errorf("HandleButton(ack) = %q, %v, %v", reply, next, err)
+	}
+	if _, next, err := s.HandleButton(context.Background(), notifier.ActionResolve, "100:1", "bob"); err != nil || next != nil {
+		t.Errorf("HandleButton(resolve) = %v, %v", next, err)
+	}
+	if _, _, err := s.HandleButton(context.Background(), notifier.ActionAck, "100:1", "bob"); !errors.Is(err, acks.ErrInvalidTransition) {
+		t.Errorf("expected ErrInvalidTransition, got %v", err)
+	}
+}
+
+func TestScout_Snooze(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{Keywords: []string{"giveaway", "urgent"}},
+		Acks:       config.AcksConfig{Enabled: true, Snooze: time.Hour},
+	}
+	notif := &MockNotifier{NotifyChan: make(chan string, 4)}
+	s := New(cfg, notif, zap.NewNop())
+	s.UseAcks(acks.New(10))
+	snoozes, err := snooze.Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.UseSnoozes(snoozes)
+	auditPath := filepath.Join(t.TempDir(), "audit.jsonl")
+	s.UseAudit(audit.New(auditPath))
+	cmds := s.Commands()
+
+	reply, err := cmds["snooze"](context.Background(), notifier.Command{Args: []string{"giveaway", "2d", "in", "100"}, User: "alice"})
+	if err != nil || !strings.Contains(reply, "<b>giveaway</b> in chat <code>100</code>") {
+		t.Fatalf("snooze = %q, %v", reply, err)
+	}
+	if _, err := cmds["snooze"](context.Background(), notifier.Command{Args: []string{"giveaway", "soon"}}); err == nil {
+		t.Error("expected an invalid duration to be rejected")
+	}
+
+	s.process(context.Background(), model.Message{ID: 1, ChatID: 100, ChatTitle: "Muted", Text: "giveaway"})
+	s.process(context.Background(), model.Message{ID: 2, ChatID: 200, ChatTitle: "Other", Text: "giveaway"})
+	s.process(context.Background(), model.Message{ID: 3, ChatID: 100, ChatTitle: "Muted", Text: "urgent"})
+	for range 2 {
+		select {
+		case msg := <-notif.NotifyChan:
+			if strings.Contains(msg, "Muted") && strings.Contains(msg, "giveaway") {
+				t.Errorf("expected the snoozed rule to stay quiet in its chat, got %q", msg)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected two alerts")
+		}
+	}
+
+	// The button snoozes the alert's rule in the alert's chat
+	reply, next, err := s.HandleButton(context.Background(), notifier.ActionSnooze, "200:2", "bob")
+	if err != nil || !strings.Contains(reply, "chat <code>200</code>") || !slices.Contains(next, notifier.ActionSnooze) {
+		t.Errorf("HandleButton(snooze) = %q, %v, %v", reply, next, err)
+	}
+	if !snoozes.Active("giveaway", 200) {
+		t.Error("expected the button to snooze the rule")
+	}
+
+	list, _ := cmds["snoozes"](context.Background(), notifier.Command{})
+	if !strings.Contains(list, "(alice)") || !strings.Contains(list, "(bob)") {
+		t.Errorf("unexpected snooze list %q", list)
+	}
+	if _, err := cmds["unsnooze"](context.Background(), notifier.Command{Args: []string{"giveaway", "in", "100"}, User: "carol"}); err != nil {
+		t.Errorf("unsnooze error = %v", err)
+	}
+	if _, err := cmds["unsnooze"](context.Background(), notifier.Command{Args: []string{"giveaway", "in", "100"}}); err == nil {
+		t.Error("expected an error unsnoozing twice")
+	}
+
+	// Only changes that took effect are audited
+	entries, err := audit.Read(auditPath, audit.Query{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, fmt.Sprintf("%s %s %s %s %d", e.Actor, e.Source, e.Action, e.Target, e.ChatID))
+	}
+	want := []string{"alice command snooze giveaway 100", "bob button snooze giveaway 200", "carol command unsnooze giveaway 100"}
+	if !slices.Equal(got, want) {
+		t.Errorf("audited %q, want %q", got, want)
+	}
+}
+
+type fakeSearcher struct{ chatID int64 }
+
+func (f *fakeSearcher) Search(ctx context.Context, text string, chatID int64, limit int) ([]model.Message, error) {
+	f.chatID = chatID
+	if text != "rtx 5070" {
+		return nil, nil
+	}
+	return []model.Message{{
+		ChatTitle: "Deals <3",
+		Text:      "RTX 5070\n" + strings.Repeat("cheap ", 40),
+		Date:      time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC),
+		Link:      "tg://privatepost?channel=1234&post=7",
+	}}, nil
+}
+
+func TestScout_SearchCommand(t *testing.T) {
+	s := New(&config.Config{Commands: config.CommandsConfig{SearchLimit: 5}}, &MockNotifier{}, zap.NewNop())
+	searcher := &fakeSearcher{}
+	s.UseHistorySearcher(searcher)
+	search := s.Commands()["search"]
+	if search == nil {
+		t.Fatal("expected /search to be available")
+	}
+
+	reply, err := search(context.Background(), notifier.Command{Args: []string{"rtx", "5070", "in", "-1001"}})
+	if err != nil {
+		t.Fatalf("search error = %v", err)
+	}
+	if searcher.chatID != -1001 {
+		t.Errorf("expected the search limited to the chat, got %d", searcher.chatID)
+	}
+	for _, want := range []string{"<b>1 results for</b> rtx 5070", "<b>Deals &lt;3</b> 2026-01-02 03:04: RTX 5070 cheap", "…", `<a href="tg://privatepost?channel=1234&amp;post=7">Link</a>`} {
+		if !strings.Contains(reply, want) {
+			t.Errorf("expected %q in reply %q", want, reply)
+		}
+	}
+
+	if reply, _ := search(context.Background(), notifier.Command{Args: []string{"nothing"}}); !strings.Contains(reply, "No messages found") {
+		t.Errorf("unexpected empty reply %q", reply)
+	}
+	if _, err := search(context.Background(), notifier.Command{}); err == nil {
+		t.Error("expected usage error without a query")
+	}
+}
+
+func TestScout_InlineQuery(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{Keywords: []string{"rtx", "giveaway"}},
+		Commands:   config.CommandsConfig{Inline: true, SearchLimit: 5},
+	}
+	notif := &MockNotifier{NotifyChan: make(chan string, 4)}
+	s := New(cfg, notif, zap.NewNop())
+	s.process(context.Background(), model.Message{ID: 1, ChatID: 100, ChatTitle: "Deals", Text: "RTX 5070 <cheap>", Link: "tg://privatepost?channel=100&post=1"})
+	s.process(context.Background(), model.Message{ID: 2, ChatID: 200, ChatTitle: "Promos", Text: "giveaway today"})
+
+	results, err := s.InlineQuery(context.Background(), "5070")
+	if err != nil {
+		t.Fatalf("InlineQuery() error = %v", err)
+	}
+	if len(results) != 1 || results[0].Title != "Deals: rtx" || results[0].Description != "RTX 5070 <cheap>" {
+		t.Fatalf("unexpected results %+v", results)
+	}
+	for _, want := range []string{"<b>rtx</b> in <b>Deals</b>", "RTX 5070 &lt;cheap&gt;", `<a href="tg://privatepost?channel=100&amp;post=1">`} {
+		if !strings.Contains(results[0].Text, want) {
+			t.Errorf("expected %q in %q", want, results[0].Text)
+		}
+	}
+
+	// An empty query lists the newest matches first
+	if results, _ := s.InlineQuery(context.Background(), ""); len(results) != 2 || results[0].Title != "Promos: giveaway" {
+		t.Errorf("unexpected results %+v", results)
+	}
+	if results, _ := s.InlineQuery(context.Background(), "PROMOS"); len(results) != 1 {
+		t.Errorf("expected a case-insensitive chat match, got %+v", results)
+	}
+}
+
+func TestScout_SweepMatch(t *testing.T) {
+	cfg := &config.Config{Monitoring: config.MonitoringRules{Keywords: []string{"rtx"}}, Explain: config.ExplainConfig{Size: 10}}
+	notif := &MockNotifier{NotifyChan: make(chan string, 2)}
+	s := New(cfg, notif, zap.NewNop())
+	sweep := config.SweepConfig{Name: "gpus", Query: "rtx 5070"}
+
+	// Already alerted on by live monitoring
+	s.process(context.Background(), model.Message{ID: 1, ChatID: 100, ChatTitle: "Deals", Text: "rtx"})
+	s.SweepMatch(context.Background(), sweep, model.Message{ID: 1, ChatID: 100, ChatTitle: "Deals", Text: "rtx 5070"})
+	s.SweepMatch(context.Background(), sweep, model.Message{ID: 2, ChatID: 100, ChatTitle: "Deals", Text: "old rtx 5070 post"})
+
+	var rules []string
+	for range 2 {
+		select {
+		case msg := <-notif.NotifyChan:
+			rule, _, _ := strings.Cut(strings.TrimPrefix(msg, "🚨 <b>Match:</b> "), "\n")
+			rules = append(rules, rule)
+		case <-time.After(time.Second):
+			t.Fatal("expected two alerts")
+		}
+	}
+	if slices.Sort(rules); !slices.Equal(rules, []string{"rtx", "sweep:gpus"}) {
+		t.Errorf("expected the live alert and one sweep alert, got %v", rules)
+	}
+	if got := s.Explanations(0, "", 10); len(got) != 2 || got[0].Kind != kindSweep || got[0].Matched != "rtx 5070" {
+		t.Errorf("unexpected explanations %+v", got)
+	}
+}
+
+func TestScout_WatchChange(t *testing.T) {
+	notif := &MockNotifier{NotifyChan: make(chan string, 3)}
+	s := New(&config.Config{}, notif, zap.NewNop())
+	at := time.Date(2026, 1, 2, 15, 4, 0, 0, time.UTC)
+
+	s.WatchChange(context.Background(), watch.Change{Kind: watch.Edited, Link: "tg://privatepost?channel=1234&post=42", Title: "Shop", Before: "Returns <30 days>", After: "Returns <14 days>", Time: at})
+	s.WatchChange(context.Background(), watch.Change{Kind: watch.Deleted, Link: "https://t.me/shop/42", Title: "Shop", Before: "Returns <14 days>", Time: at})
+
+	edit, del := <-notif.NotifyChan, <-notif.NotifyChan
+	for _, want := range []string{"✏️ <b>Watched message edited</b>", "🕒 <b>Time:</b> 3:04PM", "<blockquote>Returns &lt;30 days&gt;</blockquote>", "<b>After:</b>\n<blockquote>Returns &lt;14 days&gt;</blockquote>", `<a href="tg://privatepost?channel=1234&amp;post=42">`} {
+		if !strings.Contains(edit, want) {
+			t.Errorf("expected %q in the edit alert, got %q", want, edit)
+		}
+	}
+	if !strings.HasPrefix(del, "🗑 <b>Watched message deleted</b>") || !strings.Contains(del, "<b>Last seen:</b>\n<blockquote>Returns &lt;14 days&gt;</blockquote>") {
+		t.Errorf("unexpected deletion alert %q", del)
+	}
+
+	s.WatchChange(context.Background(), watch.Change{Kind: watch.Profile, Link: "tg://resolve?domain=alice_2&profile", Title: "Alice", Time: at, Fields: []watch.Field{
+		{Name: "username", Before: "alice", After: "alice_2"},
+		{Name: "bio", Before: "Seller <3", After: ""},
+	}})
+	profile := <-notif.NotifyChan
+	for _, want := range []string{"👤 <b>Profile changed:</b> Alice", `<a href="tg://resolve?domain=alice_2&amp;profile">Open Profile</a>`, "<b>Username:</b> <code>alice</code> → <code>alice_2</code>", "<b>Bio:</b> <code>Seller &lt;3</code> → <i>(empty)</i>"} {
+		if !strings.Contains(profile, want) {
+			t.Errorf("expected %q in the profile alert, got %q", want, profile)
+		}
+	}
+}
+
+func TestScout_PresenceChange(t *testing.T) {
+	notif := &MockNotifier{NotifyChan: make(chan string, 1)}
+	s := New(&config.Config{}, notif, zap.NewNop())
+
+	s.PresenceChange(context.Background(), 777, time.Date(2026, 1, 2, 15, 4, 0, 0, time.UTC))
+	got := <-notif.NotifyChan
+	for _, want := range []string{"🟢 <b>User online:</b> <code>777</code>", "🕒 <b>Time:</b> 3:04PM", `<a href="tg://user?id=777">`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in the presence alert, got %q", want, got)
+		}
+	}
+}
+
+func TestScout_StandingChange(t *testing.T) {
+	notif := &MockNotifier{NotifyChan: make(chan string, 3)}
+	s := New(&config.Config{}, notif, zap.NewNop())
+	at := time.Date(2026, 1, 2, 15, 4, 0, 0, time.UTC)
+	limited := standing.Status{Restricted: true, Reasons: []string{"all: <spam>"}, Frozen: true, FrozenUntil: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), AppealURL: "https://t.me/spambot", Limited: true, SpamBot: "Your account is limited"}
+
+	s.StandingChange(context.Background(), standing.Change{After: limited, Time: at})
+	s.StandingChange(context.Background(), standing.Change{Before: limited, Time: at})
+	s.StandingChange(context.Background(), standing.Change{Notice: "Login from a new device", Time: at})
+
+	alert, lifted, notice := <-notif.NotifyChan, <-notif.NotifyChan, <-notif.NotifyChan
+	for _, want := range []string{"⛔️ <b>Account limited</b>", "• all: &lt;spam&gt;", "🧊 <b>Frozen</b> until 2026-02-01", `<a href="https://t.me/spambot">Appeal</a>`, "<blockquote>Your account is limited</blockquote>"} {
+		if !strings.Contains(alert, want) {
+			t.Errorf("expected %q in the standing alert, got %q", want, alert)
+		}
+	}
+	if !strings.HasPrefix(lifted, "✅ <b>Account limits lifted</b>") {
+		t.Errorf("unexpected lifted alert %q", lifted)
+	}
+	if !strings.Contains(notice, "<blockquote>Login from a new device</blockquote>") {
+		t.Errorf("unexpected notice alert %q", notice)
+	}
+}
+
+func TestScout_Captures(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{Keywords: []string{`re:(?P<item>rtx \d{4}) for \$(?P<price>\d+)(?P<note> obo)?`}},
+		Explain:    config.ExplainConfig{Size: 10},
+	}
+	notif := &MockNotifier{NotifyChan: make(chan string, 1)}
+	s := New(cfg, notif, zap.NewNop())
+	hooks := fakeWebhooks{matches: make(chan model.Match, 1)}
+	s.UseWebhooks(hooks)
+
+	s.process(context.Background(), model.Message{ID: 1, ChatID: 100, ChatTitle: "Deals", Text: "selling rtx 5070 for $450 <fast>"})
+
+	want := map[string]string{"item": "rtx 5070", "price": "450"}
+	if m := <-hooks.matches; !reflect.DeepEqual(m.Captures, want) {
+		t.Errorf("expected named groups in the webhook match, got %v", m.Captures)
+	}
+	if got := s.Explanations(0, "", 1); len(got) != 1 || !reflect.DeepEqual(got[0].Captures, want) {
+		t.Errorf("expected named groups in the explanation, got %+v", got)
+	}
+	select {
+	case msg := <-notif.NotifyChan:
+		if !strings.Contains(msg, "🧩 <b>Captured:</b> item=<code>rtx 5070</code>, price=<code>450</code>\n") {
+			t.Errorf("expected captured values in the alert, got %q", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected an alert")
+	}
+}
+
+func TestScout_PCRE(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{Keywords: []string{
+			`pcre:(?<item>rtx \d{4})(?! ti)`,
+			`pcre:\b(\w+) \1\b`,
+			`re2:^ping$`,
+			`pcre:^(a+)+$`,
+			`pcre:(?<=(`,
+		}},
+		Matching: config.MatchingConfig{PCRETimeout: time.Millisecond},
+	}
+	s := New(cfg, &MockNotifier{}, zap.NewNop())
+	if compiled, rejected := s.Rules(); compiled != 4 || len(rejected) != 1 {
+		t.Fatalf("expected the invalid PCRE keyword rejected, got %d and %+v", compiled, rejected)
+	}
+
+	msgs := []model.Message{
+		{Text: "rtx 5070 ti and a plain rtx 5080"},
+		{Text: "café, très très cher"},
+		{Text: "ping"},
+		{Text: strings.Repeat("a", 64) + "!"},
+	}
+	for _, name := range []string{config.EngineNaive, config.EngineAhoCorasick, config.EngineRegex} {
+		if err := s.SetEngine(name); err != nil {
+			t.Fatalf("SetEngine(%s) failed: %v", name, err)
+		}
+		var got []string
+		for _, msg := range msgs {
+			exp, _ := s.evaluate(msg)
+			got = append(got, exp.Keyword+"="+exp.Matched)
+		}
+		want := []string{`pcre:(?<item>rtx \d{4})(?! ti)=rtx 5080`, `pcre:\b(\w+) \1\b=très très`, "re2:^ping$=ping", "="}
+		if !slices.Equal(got, want) {
+			t.Errorf("%s: got %q, want %q", name, got, want)
+		}
+	}
+
+	exp, _ := s.evaluate(msgs[0])
+	if exp.Kind != kindPCRE || !reflect.DeepEqual(exp.Captures, map[string]string{"item": "rtx 5080"}) {
+		t.Errorf("expected the named group of a PCRE rule captured, got %+v", exp)
+	}
+	if v := matchMetrics.Get("pcre_timeout:pcre:^(a+)+$"); v == nil || v.String() == "0" {
+		t.Error("expected the backtracking rule to time out")
+	}
+}
+
+func TestScout_Stemming(t *testing.T) {
+	keywords := []string{"running", "vender carro", "c++"}
+	msgs := []model.Message{
+		{Text: "She RUNS daily"},
+		{Text: "Estou vendendo carros usados"},
+		{Text: "vendendo o carro"},
+		{Text: "written in c, not c++"},
+		{Text: "a runner"},
+	}
+
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{Keywords: keywords},
+		Matching:   config.MatchingConfig{Stemming: []string{"english", "portuguese"}},
+	}
+	s := New(cfg, &MockNotifier{}, zap.NewNop())
+	for _, name := range []string{config.EngineNaive, config.EngineAhoCorasick, config.EngineRegex} {
+		if err := s.SetEngine(name); err != nil {
+			t.Fatalf("SetEngine(%s) failed: %v", name, err)
+		}
+		var got []string
+		for _, msg := range msgs {
+			exp, _ := s.evaluate(msg)
+			got = append(got, exp.Keyword+"="+exp.Matched)
+		}
+		want := []string{"running=RUNS", "vender carro=vendendo carros", "=", "c++=c++", "="}
+		if !slices.Equal(got, want) {
+			t.Errorf("%s: got %q, want %q", name, got, want)
+		}
+	}
+
+	plain := New(&config.Config{Monitoring: config.MonitoringRules{Keywords: keywords}}, &MockNotifier{}, zap.NewNop())
+	if exp, ok := plain.evaluate(msgs[0]); ok {
+		t.Errorf("expected no stemming unless configured, got %+v", exp)
+	}
+}
+
+func TestScout_Stopwords(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{Keywords: []string{"looking 5070", "vendo o carro", "for the"}},
+		Matching:   config.MatchingConfig{Stopwords: []string{"english", "portuguese"}},
+	}
+	s := New(cfg, &MockNotifier{}, zap.NewNop())
+
+	tests := []struct{ text, want string }{
+		{"Looking for a 5070, DM me", "looking 5070=Looking for a 5070"},
+		{"vendo carro barato", "vendo o carro=vendo carro"},
+		{"vendo um carro", "vendo o carro=vendo um carro"},
+		{"looking at a used 5070", "="},
+		{"vendo moto e carro", "="},
+		{"all for free", "="},
+		{"great for the price", "for the=for the"},
+	}
+	for _, name := range []string{config.EngineNaive, config.EngineAhoCorasick, config.EngineRegex} {
+		if err := s.SetEngine(name); err != nil {
+			t.Fatalf("SetEngine(%s) failed: %v", name, err)
+		}
+		for _, tt := range tests {
+			exp, _ := s.evaluate(model.Message{Text: tt.text})
+			if got := exp.Keyword + "=" + exp.Matched; got != tt.want {
+				t.Errorf("%s: %q matched %q, want %q", name, tt.text, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestScout_Ranges(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{Keywords: []string{"rtx 50{60..90}", "iphone {13..16} pro", "{01..12}/{2020..2030}*sale", "gen {9..1}"}},
+	}
+	s := New(cfg, &MockNotifier{}, zap.NewNop())
+	if compiled, rejected := s.Rules(); compiled != 3 || len(rejected) != 1 || !strings.Contains(rejected[0].Reason, "{9..1}") {
+		t.Fatalf("expected the reversed range rejected, got %d and %+v", compiled, rejected)
+	}
+
+	tests := []struct{ text, want string }{
+		{"Selling RTX 5070 Ti", "rtx 50{60..90}=RTX 5070"},
+		{"rtx 5050 and rtx 5090", "rtx 50{60..90}=rtx 5090"},
+		{"rtx 50700", "="},
+		{"rtx 5095", "="},
+		{"iPhone 15  Pro Max", "iphone {13..16} pro=iPhone 15  Pro"},
+		{"iphone 150 pro", "="},
+		{"iphone 12 pro", "="},
+		{"from 03/2025, on sale", "{01..12}/{2020..2030}*sale=03/2025, on sale"},
+		{"from 3/2025, on sale", "="},
+		{"from 103/2025, on sale", "="},
+	}
+	for _, name := range []string{config.EngineNaive, config.EngineAhoCorasick, config.EngineRegex} {
+		if err := s.SetEngine(name); err != nil {
+			t.Fatalf("SetEngine(%s) failed: %v", name, err)
+		}
+		for _, tt := range tests {
+			exp, _ := s.evaluate(model.Message{Text: tt.text})
+			if got := exp.Keyword + "=" + exp.Matched; got != tt.want {
+				t.Errorf("%s: %q matched %q, want %q", name, tt.text, got, tt.want)
+			}
+		}
+	}
+}
+
+// Record the correlation ID each alert was sent with
+type traceNotifier struct{ ids chan string }
+
+func (n traceNotifier) Send(ctx context.Context, message string) error {
+	n.ids <- logger.TraceID(ctx)
+	return nil
+}
+
+func TestScout_TraceID(t *testing.T) {
+	cfg := &config.Config{Monitoring: config.MonitoringRules{Keywords: []string{"urgent"}}}
+	core, logs := observer.New(zapcore.InfoLevel)
+	notif := traceNotifier{ids: make(chan string, 1)}
+	s := New(cfg, notif, zap.New(core))
+
+	s.process(context.Background(), model.Message{ID: 1, ChatID: 100, Text: "urgent sale", TraceID: "4bf92f3577b34da6"})
+
+	select {
+	case id := <-notif.ids:
+		if id != "4bf92f3577b34da6" {
+			t.Errorf("expected the message's trace ID on the notification context, got %q", id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected an alert")
+	}
+	matched := logs.FilterMessage("Keyword matched").All()
+	if len(matched) != 1 || matched[0].ContextMap()["trace_id"] != "4bf92f3577b34da6" {
+		t.Errorf("expected the trace ID in the match log, got %+v", matched)
+	}
+}
+
+func TestScout_Filters(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{Keywords: []string{"urgent"}},
+		Filters:    config.FiltersConfig{IgnoreOutgoing: true, IgnoreBots: true},
+	}
+	s := New(cfg, &MockNotifier{}, zap.NewNop())
+
+	tests := []struct {
+		name string
+		msg  model.Message
+		want string
+	}{
+		{"Incoming", model.Message{Text: "urgent"}, ""},
+		{"Outgoing", model.Message{Text: "urgent", Outgoing: true}, "outgoing"},
+		{"Bot", model.Message{Text: "urgent", FromBot: true}, "bot"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.filtered(tt.msg); got != tt.want {
+				t.Errorf("filtered() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// Filters are opt-in
+	s = New(&config.Config{}, &MockNotifier{}, zap.NewNop())
+	if got := s.filtered(model.Message{Outgoing: true, FromBot: true}); got != "" {
+		t.Errorf("expected no filtering by default, got %q", got)
+	}
+}
+
+func TestScout_StaleMessages(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{Keywords: []string{"deal"}},
+		Filters:    config.FiltersConfig{MaxAge: time.Hour},
+		ChatSettings: map[string]config.ChatSettings{
+			"-1005": {Filters: config.FiltersConfig{MaxAge: time.Hour, Stale: config.StaleLabel}},
+		},
+	}
+	notif := &MockNotifier{NotifyChan: make(chan string, 1)}
+	s := New(cfg, notif, zap.NewNop())
+
+	old := time.Now().Add(-3 * time.Hour)
+	if got := s.filtered(model.Message{Text: "deal", Date: old}); got != "stale" {
+		t.Errorf("expected an old message dropped, got %q", got)
+	}
+	if got := s.filtered(model.Message{Text: "deal", Date: time.Now()}); got != "" {
+		t.Errorf("expected a recent message kept, got %q", got)
+	}
+	if got := s.filtered(model.Message{Text: "deal"}); got != "" {
+		t.Errorf("expected a message without a date kept, got %q", got)
+	}
+
+	s.process(context.Background(), model.Message{ID: 1, ChatID: -1000000000005, Text: "deal", Date: old})
+	select {
+	case msg := <-notif.NotifyChan:
+		if !strings.Contains(msg, "🕰 <b>Stale:</b> sent 3h0m ago") {
+			t.Errorf("expected the alert labeled stale, got %q", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected an alert for an old message in a labeling chat")
+	}
+	if got := s.Explanations(0, "", 1); len(got) != 1 || !got[0].Stale {
+		t.Errorf("expected the explanation marked stale, got %+v", got)
+	}
+}
+
+func TestScout_CoalescesBurstPastConcurrency(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{Keywords: []string{"deal"}},
+		Notifier:   config.NotifierConfig{Concurrency: 2},
+	}
+	mock := &MockNotifier{NotifyChan: make(chan string, 10)}
+	coalescer := notifier.NewCoalescer(mock, config.CoalesceConfig{Window: 50 * time.Millisecond, Threshold: 3}, zap.NewNop())
+	s := New(cfg, coalescer, zap.NewNop())
+
+	for i := 1; i <= 8; i++ {
+		s.process(context.Background(), model.Message{ID: i, ChatID: 100, ChatTitle: "Deals", Text: "deal"})
+		time.Sleep(time.Millisecond)
+	}
+	if got := s.digest.len(); got != 0 {
+		t.Errorf("expected the burst to wait in the coalescing window, not the digest, got %d lines", got)
+	}
+	select {
+	case msg := <-mock.NotifyChan:
+		if !strings.Contains(msg, "8 alerts") {
+			t.Errorf("expected the burst merged into one message, got %q", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the merged alerts")
+	}
+}
+
+// Fail every send
+type downNotifier struct{}
+
+func (downNotifier) Send(ctx context.Context, message string) error {
+	return errors.New("bot down")
+}
+
+func TestScout_CoalescedDigestRequeued(t *testing.T) {
+	coalescer := notifier.NewCoalescer(downNotifier{}, config.CoalesceConfig{Window: 10 * time.Millisecond, Threshold: 3}, zap.NewNop())
+	s := New(&config.Config{}, coalescer, zap.NewNop())
+	s.digest.add("held alert")
+
+	if s.sendDigest(context.Background()) {
+		t.Error("expected the digest to fail while the bot is down")
+	}
+	if got := s.digest.len(); got != 1 {
+		t.Errorf("expected the digest requeued, got %d lines", got)
+	}
+}
+
+func TestScout_CoalescedLatency(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{Keywords: []string{"deal"}},
+		Pipeline:   config.PipelineConfig{LatencyBudget: 50 * time.Millisecond},
+	}
+	mock := &MockNotifier{NotifyChan: make(chan string, 4)}
+	coalescer := notifier.NewCoalescer(mock, config.CoalesceConfig{Window: 200 * time.Millisecond, Threshold: 3}, zap.NewNop())
+	s := New(cfg, coalescer, zap.NewNop())
+	over := func() int64 {
+		if v, ok := latencyMetrics.Get("over_budget").(*expvar.Int); ok {
+			return v.Value()
+		}
+		return 0
+	}
+	before := over()
+
+	// Received within budget, but delivered only once the window ends
+	now := time.Now()
+	s.process(context.Background(), model.Message{ID: 1, ChatID: 100, Text: "deal", Date: now, Received: now})
+	<-mock.NotifyChan
+	deadline := time.Now().Add(time.Second)
+	for over() == before && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := over() - before; got != 1 {
+		t.Errorf("expected the latency measured at delivery, past the budget, got %d over budget", got)
+	}
+}
+
+func TestScout_Priority(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{
+			Keywords:   []string{"deal", "outage", "sale"},
+			Priorities: map[string]string{"outage": config.PriorityHigh, "s*": config.PriorityNormal},
+		},
+		ChatSettings: map[string]config.ChatSettings{
+			"-1005": {Priority: config.PriorityLow},
+			"-1006": {Priority: config.PriorityHigh},
+		},
+	}
+	mock := &MockNotifier{NotifyChan: make(chan string, 4)}
+	// Only urgent alerts get through the window in time
+	coalescer := notifier.NewCoalescer(mock, config.CoalesceConfig{Window: time.Hour}, zap.NewNop())
+	s := New(cfg, coalescer, zap.NewNop())
+
+	lowChat, highChat := int64(-1000000000005), int64(-1000000000006)
+	for _, tt := range []struct {
+		rule   string
+		chatID int64
+		want   string
+	}{
+		{"deal", 100, config.PriorityNormal},
+		{"deal", lowChat, config.PriorityLow},
+		{"sale", lowChat, config.PriorityNormal}, // Rules override the chat
+		{"outage", lowChat, config.PriorityHigh},
+		{"deal", highChat, config.PriorityHigh},
+	} {
+		if got := s.priorityFor(tt.rule, model.Message{ChatID: tt.chatID}); got != tt.want {
+			t.Errorf("priorityFor(%q, %d) = %q, want %q", tt.rule, tt.chatID, got, tt.want)
+		}
+	}
+
+	// Low priority chats only reach the digest
+	s.process(context.Background(), model.Message{ID: 1, ChatID: lowChat, ChatTitle: "Quiet", Text: "deal"})
+	if got := s.digest.len(); got != 1 {
+		t.Errorf("expected the low priority alert in the digest, got %d lines", got)
+	}
+
+	s.process(context.Background(), model.Message{ID: 2, ChatID: highChat, ChatTitle: "Loud", Text: "deal"})
+	select {
+	case msg := <-mock.NotifyChan:
+		if !strings.Contains(msg, "Loud") {
+			t.Errorf("expected the high priority alert, got %q", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the high priority alert to skip the coalescing window")
+	}
+}
+
+func TestScout_MatchMode(t *testing.T) {
+	newScout := func(mode string, debug bool) *Scout {
+		cfg := &config.Config{
+			Monitoring: config.MonitoringRules{
+				Keywords:   []string{"sale", "deal", "outage"},
+				Files:      []config.FileRule{{Name: "apk", Filename: "*.apk"}},
+				Tags:       map[string][]string{"sale": {"shop"}, "outage": {"oncall"}},
+				Priorities: map[string]string{"outage": config.PriorityHigh, "sale": config.PriorityLow, "file:*": config.PriorityHigh},
+			},
+			Matching: config.MatchingConfig{Mode: mode},
+			Explain:  config.ExplainConfig{Debug: debug},
+		}
+		return New(cfg, &MockNotifier{}, zap.NewNop())
+	}
+	text := model.Message{ID: 1, ChatID: 100, Text: "sale: deal during the outage"}
+	file := model.Message{ID: 2, ChatID: 100, Text: "deal", Media: "document", File: &model.File{Name: "app.apk"}}
+
+	for _, tt := range []struct {
+		mode string
+		msg  model.Message
+		want string
+		also []string
+	}{
+		{"", text, "sale", nil},
+		{config.MatchFirst, file, "deal", nil},
+		{config.MatchPriority, text, "outage", nil},
+		{config.MatchPriority, file, "file:apk", nil}, // More urgent than any keyword
+		{config.MatchAll, text, "sale", []string{"deal", "outage"}},
+		{config.MatchAll, file, "deal", []string{"file:apk"}},
+	} {
+		for _, debug := range []bool{false, true} {
+			exp, ok := newScout(tt.mode, debug).evaluate(tt.msg)
+			if !ok || exp.Keyword != tt.want || !slices.Equal(exp.Also, tt.also) {
+				t.Errorf("mode %q, debug %v: expected %q also %v, got %q also %v", tt.mode, debug, tt.want, tt.also, exp.Keyword, exp.Also)
+			}
+		}
+	}
+
+	// Debug traces show every keyword tried under all
+	exp, _ := newScout(config.MatchAll, true).evaluate(text)
+	for _, ev := range exp.Evaluations[:3] {
+		if ev.Decision != DecisionMatched {
+			t.Errorf("expected %q evaluated and matched, got %q", ev.Keyword, ev.Decision)
+		}
+	}
+
+	// The alert carries the tags of every listed rule
+	cfg := newScout(config.MatchAll, false).cfg
+	cfg.Monitoring.Priorities = nil
+	notifier := &MockNotifier{NotifyChan: make(chan string, 1)}
+	s := New(cfg, notifier, zap.NewNop())
+	s.process(context.Background(), model.Message{ID: 3, ChatID: 100, ChatTitle: "Ops", Text: "outage sale"})
+	select {
+	case msg := <-notifier.NotifyChan:
+		if !strings.Contains(msg, "Also matched:</b> outage") || !strings.Contains(msg, "oncall, shop") {
+			t.Errorf("expected the other rule and both rules' tags, got %q", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected notification")
+	}
+}
+
+func TestScout_Prefilters(t *testing.T) {
+	global := config.FiltersConfig{MinLength: 5, MaxLength: 40, SkipEmojiOnly: true, SkipLinkOnly: true}
+	relaxed := global
+	relaxed.MinLength = 0
+	cfg := &config.Config{
+		Filters:      global,
+		ChatSettings: map[string]config.ChatSettings{"@short_posts": {Filters: relaxed}},
+	}
+	s := New(cfg, &MockNotifier{}, zap.NewNop())
+
+	tests := []struct {
+		name string
+		msg  model.Message
+		want string
+	}{
+		{"Plain Text", model.Message{Text: "selling an rtx 5070"}, ""},
+		{"Too Short", model.Message{Text: " ok  "}, "too_short"},
+		{"Too Long", model.Message{Text: strings.Repeat("a", 41)}, "too_long"},
+		{"Emoji", model.Message{Text: "🔥🔥 👍🏽 🇧🇷 👨‍👩‍👧 ❤️"}, "emoji_only"},
+		{"Emoji With Text", model.Message{Text: "🔥 hot deal 🔥"}, ""},
+		{"Sticker", model.Message{Media: "sticker"}, "emoji_only"},
+		{"Photo Without Caption", model.Message{Media: "photo"}, "too_short"},
+		{"Links", model.Message{Text: "https://example.com/a\nt.me/deals/5"}, "link_only"},
+		{"Link With Text", model.Message{Text: "deal at https://example.com"}, ""},
+		{"Chat Override", model.Message{Text: "ok", Username: "Short_Posts"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.filtered(tt.msg); got != tt.want {
+				t.Errorf("filtered() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScout_RejectedRules(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{Keywords: []string{"ok", "re:(unclosed"}},
+	}
+	s := New(cfg, &MockNotifier{}, zap.NewNop())
+
+	compiled, rejected := s.Rules()
+	if compiled != 1 {
+		t.Errorf("expected 1 compiled rule, got %d", compiled)
+	}
+	if len(rejected) != 1 || rejected[0].Keyword != "re:(unclosed" || rejected[0].Reason == "" {
+		t.Errorf("unexpected rejected rules: %+v", rejected)
+	}
+}
+
+func TestScout_HandleMentions(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{Keywords: []string{"mention:@MyBrand", "mention:brand!"}},
+	}
+	s := New(cfg, &MockNotifier{}, zap.NewNop())
+
+	if compiled, rejected := s.Rules(); compiled != 1 || len(rejected) != 1 {
+		t.Fatalf("expected the malformed handle rejected, got %d compiled, %+v", compiled, rejected)
+	}
+
+	// The handle in plain text or a link is not a mention entity
+	if _, ok := s.evaluate(model.Message{Text: "see t.me/mybrand or @mybrand"}); ok {
+		t.Error("expected no match without mention entities")
+	}
+
+	text := "thanks @MyBrand!"
+	exp, ok := s.evaluate(model.Message{Text: text, Entities: []model.Entity{{Kind: model.EntityMention, Value: "mybrand", Start: 7, End: 15}}})
+	if !ok {
+		t.Fatal("expected a match on the mention entity")
+	}
+	if exp.Kind != kindMention || exp.Matched != "@MyBrand" {
+		t.Errorf("unexpected explanation %+v", exp)
+	}
+}
+
+func TestScout_TagRules(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{Keywords: []string{"#Launch", "$btc", "#1"}},
+	}
+	s := New(cfg, &MockNotifier{}, zap.NewNop())
+	if s.engine.Load().rules[0].kind != kindHashtag || s.engine.Load().rules[1].kind != kindCashtag || s.engine.Load().rules[2].kind != kindWord {
+		t.Fatalf("unexpected rule kinds %s, %s, %s", s.engine.Load().rules[0].kind, s.engine.Load().rules[1].kind, s.engine.Load().rules[2].kind)
+	}
+
+	// Tag text inside links and words is not an entity
+	if _, ok := s.evaluate(model.Message{Text: "https://example.com/#launch costs US$btc"}); ok {
+		t.Error("expected no match without entities")
+	}
+
+	text := "buying $BTC now"
+	exp, ok := s.evaluate(model.Message{Text: text, Entities: []model.Entity{{Kind: model.EntityCashtag, Value: "btc", Start: 7, End: 11}}})
+	if !ok || exp.Kind != kindCashtag || exp.Matched != "$BTC" {
+		t.Errorf("expected a cashtag match, got %+v", exp)
+	}
+
+	// A mention of the same word is a different entity
+	if _, ok := s.evaluate(model.Message{Text: "@launch", Entities: []model.Entity{{Kind: model.EntityMention, Value: "launch", Start: 0, End: 7}}}); ok {
+		t.Error("expected a mention not to match a hashtag rule")
+	}
+}
+
+func TestScout_ShadowRules(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{
+			Keywords: []string{"urgent"},
+			Shadow:   []string{"deal", "urgent deal", "re:(unclosed"},
+		},
+		Explain: config.ExplainConfig{Size: 10},
+	}
+	notifier := &MockNotifier{}
+	s := New(cfg, notifier, zap.NewNop())
+	archived := &fakeArchive{}
+	s.UseArchive(archived)
+
+	if _, rejected := s.Rules(); len(rejected) != 1 || rejected[0].Keyword != "re:(unclosed" {
+		t.Errorf("expected the invalid shadow rule rejected, got %+v", rejected)
+	}
+
+	s.process(context.Background(), model.Message{ID: 1, ChatID: 100, Text: "a good deal"})
+	s.process(context.Background(), model.Message{ID: 2, ChatID: 100, Text: "urgent deal"})
+	time.Sleep(50 * time.Millisecond)
+
+	// Only the live rule alerts
+	if msgs := notifier.Messages(); len(msgs) != 1 {
+		t.Errorf("expected a single alert, got %d", len(msgs))
+	}
+	if got := matchMetrics.Get("shadow:urgent deal"); got == nil || got.String() != "1" {
+		t.Errorf("expected 1 shadow match for the phrase, got %v", got)
+	}
+	if got := matchMetrics.Get("shadow_new:deal"); got == nil || got.String() != "1" {
+		t.Errorf("expected 1 match no live rule made, got %v", got)
+	}
+
+	var shadow []string
+	for _, r := range archived.records {
+		if r.Kind == archive.KindShadow {
+			shadow = append(shadow, fmt.Sprintf("%d:%s", r.MsgID, r.Rule))
+		}
+	}
+	if want := []string{"1:deal", "2:deal", "2:urgent deal"}; !slices.Equal(shadow, want) {
+		t.Errorf("expected shadow records %v, got %v", want, shadow)
+	}
+}
+
+func TestScout_Counters(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{
+			Keywords: []string{"urgent"},
+			Counters: []string{"gpu", "urgent", "re:(unclosed"},
+		},
+	}
+	notifier := &MockNotifier{}
+	s := New(cfg, notifier, zap.NewNop())
+	archived := &fakeArchive{}
+	s.UseArchive(archived)
+
+	if _, rejected := s.Rules(); len(rejected) != 1 || rejected[0].Keyword != "re:(unclosed" {
+		t.Errorf("expected the invalid counter rejected, got %+v", rejected)
+	}
+
+	s.process(context.Background(), model.Message{ID: 1, ChatID: 100, Text: "gpu prices"})
+	s.process(context.Background(), model.Message{ID: 2, ChatID: 100, Text: "urgent: gpu"})
+	time.Sleep(50 * time.Millisecond)
+
+	// Counters neither alert nor keep the live rule from alerting
+	if msgs := notifier.Messages(); len(msgs) != 1 {
+		t.Errorf("expected a single alert, got %d", len(msgs))
+	}
+	if got := counterMetrics.Get("gpu"); got == nil || got.String() != "2" {
+		t.Errorf("expected 2 counted matches, got %v", got)
+	}
+	if got := counterMetrics.Get("urgent"); got == nil || got.String() != "1" {
+		t.Errorf("expected the counter next to the live rule, got %v", got)
+	}
+
+	var counted []string
+	for _, r := range archived.records {
+		if r.Kind == archive.KindCount {
+			counted = append(counted, fmt.Sprintf("%d:%s", r.MsgID, r.Rule))
+		}
+	}
+	if want := []string{"1:gpu", "2:gpu", "2:urgent"}; !slices.Equal(counted, want) {
+		t.Errorf("expected count records %v, got %v", want, counted)
+	}
+}
+
+func TestScout_Lint(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{
+			Keywords: []string{
+				"deal",
+				"Deal",              // Duplicate ignoring case
+				"big deal today",    // Contains an earlier word
+				"#deal",             // Hashtag text contains the word
+				"rtx * 5070",        // Not covered
+				"re:(?i)rtx",        // Plain keyword in disguise
+				"re:(?i)foo|bar",    // Two keywords in disguise
+				"re:Foo",            // Case-sensitive, stricter than a keyword
+				"re:(?i)rtx 5070",   // Contains the earlier regex's literal
+				"mention:@deal",     // Mentions by name need not contain the text
+				"hello   world",     // Not covered
+				"say hello world",   // Covered by the phrase despite spacing
+				"re:(?P<n>\\d+) gb", // Named groups are kept
+			},
+			Shadow: []string{"DEAL", "new"},
+		},
+	}
+	s := New(cfg, &MockNotifier{}, zap.NewNop())
+
+	got := make(map[string]string)
+	for _, f := range s.Lint() {
+		got[f.Keyword] = f.Problem + "; " + f.Suggestion
+	}
+	want := map[string]string{
+		"Deal":            "duplicate",
+		"big deal today":  "never fires",
+		"#deal":           "never fires",
+		"re:(?i)rtx":      `keyword "rtx"`,
+		"re:(?i)foo|bar":  `keywords "foo", "bar"`,
+		"re:(?i)rtx 5070": `matched by "re:(?i)rtx"`,
+		"say hello world": `matched by "hello   world"`,
+		"DEAL":            "shadow rule duplicates",
+	}
+	for keyword, text := range want {
+		if !strings.Contains(got[keyword], text) {
+			t.Errorf("finding for %q = %q, expected it to mention %q", keyword, got[keyword], text)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("expected %d findings, got %v", len(want), got)
+	}
+
+	// Everything after a bare wildcard is unreachable
+	cfg.Monitoring = config.MonitoringRules{Keywords: []string{"*", "urgent"}}
+	s = New(cfg, &MockNotifier{}, zap.NewNop())
+	if findings := s.Lint(); len(findings) != 1 || findings[0].Keyword != "urgent" {
+		t.Errorf("expected the rule after the wildcard flagged, got %+v", findings)
+	}
+}
+
+func TestAhoCorasick(t *testing.T) {
+	a := newAhoCorasick([]string{"she", "he", "hers", "his", "he"})
+	tests := map[string]int{
+		"ushers": 0, // "she" ends before "hers", but the lowest index wins
+		"ahis":   3,
+		"hxe":    -1,
+		"h":      -1,
+		"xhe":    1,
+	}
+	for text, want := range tests {
+		if got := a.lowest(text); got != want {
+			t.Errorf("lowest(%q) = %d, want %d", text, got, want)
+		}
+	}
+}
+
+func TestScout_Engines(t *testing.T) {
+	keywords := []string{"re:(?i)price:\\s*\\d+", "rtx 5090", "#launch", "deal*today"}
+	for i := range 40 {
+		keywords = append(keywords, fmt.Sprintf("word%d", i))
+	}
+	cfg := &config.Config{Monitoring: config.MonitoringRules{Keywords: keywords}}
+	s := New(cfg, &MockNotifier{}, zap.NewNop())
+
+	if name, auto := s.Engine(); name != config.EngineAhoCorasick || !auto {
+		t.Errorf("expected auto to pick aho-corasick for mostly words, got %s (auto %v)", name, auto)
+	}
+
+	msgs := []model.Message{
+		{Text: "nothing here"},
+		{Text: "WORD7 and word3"},
+		{Text: "word12 deal for today, price: 10"},
+		{Text: "an RTX\n5090 and word39"},
+		{Text: "#launch", Entities: []model.Entity{{Kind: model.EntityHashtag, Value: "launch", Start: 0, End: 7}}},
+		{Text: "İ word5"},
+	}
+	for _, name := range []string{config.EngineNaive, config.EngineAhoCorasick, config.EngineRegex} {
+		if err := s.SetEngine(name); err != nil {
+			t.Fatalf("SetEngine(%s) failed: %v", name, err)
+		}
+		var got []string
+		for _, msg := range msgs {
+			exp, _ := s.evaluate(msg)
+			got = append(got, exp.Keyword+"="+exp.Matched)
+		}
+		want := []string{"=", "word3=word3", "re:(?i)price:\\s*\\d+=price: 10", "rtx 5090=RTX\n5090", "#launch=#launch", "word5=word5"}
+		if !slices.Equal(got, want) {
+			t.Errorf("%s: got %q, want %q", name, got, want)
+		}
+	}
+
+	if err := s.SetEngine(config.EngineHyperscan); err == nil {
+		t.Error("expected hyperscan to be unavailable")
+	}
+	for _, r := range s.BenchEngines(msgs) {
+		if r.Engine == config.EngineHyperscan {
+			if r.Err == nil {
+				t.Error("expected the hyperscan benchmark to report it is unavailable")
+			}
+			continue
+		}
+		if r.Err != nil || r.Matches != 5 || r.Mismatches != 0 {
+			t.Errorf("unexpected %s benchmark %+v", r.Engine, r)
+		}
+	}
+}
+
+func TestScout_MatchCache(t *testing.T) {
+	cfg := &config.Config{
+		Monitoring: config.MonitoringRules{Keywords: []string{"re:RTX \\d+", "gpu"}},
+		Matching:   config.MatchingConfig{CacheTTL: time.Minute, CacheEntries: 2},
+	}
+	s := New(cfg, &MockNotifier{}, zap.NewNop())
+
+	tests := []struct {
+		text    string
+		keyword string
+		matched string
+	}{
+		{"selling RTX 5090", "re:RTX \\d+", "RTX 5090"},
+		// Same content hash, but the case-sensitive rule no longer matches
+		{"selling rtx 5090", "", ""},
+		{"selling  RTX 5090", "re:RTX \\d+", "RTX 5090"},
+		{"selling  RTX 5090", "re:RTX \\d+", "RTX 5090"},
+		{"cheap gpu", "gpu", "gpu"},
+		{"nothing", "", ""},
+		{"nothing", "", ""},
+	}
+	for _, tt := range tests {
+		exp, ok := s.evaluate(model.Message{Text: tt.text})
+		if ok != (tt.keyword != "") || exp.Keyword != tt.keyword || exp.Matched != tt.matched {
+			t.Errorf("evaluate(%q) = %q %q, want %q %q", tt.text, exp.Keyword, exp.Matched, tt.keyword, tt.matched)
+		}
+	}
+
+	if n := s.engine.Load().results.order.Len(); n != 2 {
+		t.Errorf("expected the cache capped at 2 entries, got %d", n)
+	}
+	if i, ok := s.engine.Load().results.get(contentHash("nothing"), model.Message{Text: "nothing"}); !ok || i != -1 {
+		t.Errorf("expected a cached miss, got %d %v", i, ok)
+	}
+	tagged := model.Message{Text: "cheap gpu", Entities: []model.Entity{{Kind: model.EntityHashtag, Value: "gpu", Start: 6, End: 9}}}
+	if _, ok := s.engine.Load().results.get(contentHash(tagged.Text), tagged); ok {
+		t.Error("expected the cache to fall back to matching for the same text with entities")
+	}
+}
+
+// Serve a fixed history, newest first
+type fakeHistory struct {
+	msgs []model.Message
+	err  error
+}
+
+func (f *fakeHistory) History(ctx context.Context, chat string, since time.Time, fn func(model.Message) error) error {
+	for _, msg := range f.msgs {
+		if msg.Date.Before(since) {
+			return nil
+		}
+		if err := fn(msg); err != nil {
+			return err
+		}
+	}
+	return f.err
+}
+
+func TestScout_Backfill(t *testing.T) {
+	cfg := &config.Config{Monitoring: config.MonitoringRules{Keywords: []string{"urgent"}}}
+	notif := &MockNotifier{}
+	s := New(cfg, notif, zap.NewNop())
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if _, err := s.StartBackfill(context.Background(), "@deals", since, true); err == nil {
+		t.Error("expected backfill to need the archive")
+	}
+	archived := &fakeArchive{}
+	s.UseArchive(archived)
+	s.UseHistoryReader(&fakeHistory{msgs: []model.Message{
+		{ID: 3, ChatID: -100, Text: "urgent sale", Date: since.Add(48 * time.Hour)},
+		{ID: 2, ChatID: -100, Text: "hello", Date: since.Add(24 * time.Hour)},
+		{ID: 1, ChatID: -100, Text: "urgent old", Date: since.Add(-time.Hour)},
+	}})
+
+	started, err := s.StartBackfill(context.Background(), "@deals", since, true)
+	if err != nil {
+		t.Fatalf("StartBackfill() error = %v", err)
+	}
+	var report BackfillReport
+	for deadline := time.Now().Add(time.Second); ; {
+		reports := s.Backfills()
+		if len(reports) == 1 && !reports[0].Finished.IsZero() {
+			report = reports[0]
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("backfill did not finish: %+v", reports)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if report.ID != started.ID || report.Messages != 2 || report.Matches["urgent"] != 1 || report.Error != "" {
+		t.Errorf("unexpected report %+v", report)
+	}
+	if !report.Oldest.Equal(since.Add(24 * time.Hour)) {
+		t.Errorf("expected the oldest message date, got %v", report.Oldest)
+	}
+	var kinds []string
+	for _, r := range archived.records {
+		kinds = append(kinds, fmt.Sprintf("%s:%d", r.Kind, r.MsgID))
+	}
+	if want := []string{"message:3", "backfill:3", "message:2"}; !slices.Equal(kinds, want) {
+		t.Errorf("archived %v, want %v", kinds, want)
+	}
+	if len(notif.Messages()) != 0 {
+		t.Error("expected no alerts from a backfill")
+	}
 }
@@ -0,0 +1,71 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"context"
+	"fmt"
+	"html"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/logger"
+	"github.com/h3nc4/TelegramScout/internal/model"
+	"github.com/h3nc4/TelegramScout/internal/urls"
+)
+
+// Capture linked pages before they disappear
+type Screenshotter interface {
+	// Render link and return the URL of the stored image
+	Screenshot(ctx context.Context, msg model.Message, link string) (string, error)
+}
+
+// Attach screenshots of linked pages to alerts through r. Call before Start.
+func (s *Scout) UseScreenshotter(r Screenshotter) {
+	s.screenshotter = r
+}
+
+// Render the first links of the message, returning an alert line per screenshot
+func (s *Scout) screenshots(ctx context.Context, msg model.Message) []string {
+	if s.screenshotter == nil {
+		return nil
+	}
+
+	links := urls.Extract(msg.Text)
+	maxLinks := s.cfg.Shots.MaxLinks
+	if maxLinks <= 0 {
+		maxLinks = config.DefaultScreenshotMaxLinks
+	}
+	if len(links) > maxLinks {
+		links = links[:maxLinks]
+	}
+
+	var lines []string
+	for _, link := range links {
+		url, err := s.screenshotter.Screenshot(ctx, msg, link)
+		if err != nil {
+			// Still alert without the screenshot
+			s.log.Warn("Failed to render linked page", zap.String("link", link), zap.Int64("chat_id", msg.ChatID), zap.Int("msg_id", msg.ID), logger.TraceField(msg.TraceID), zap.Error(err))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("🖼 <a href=\"%s\">Screenshot</a> of %s", html.EscapeString(url), html.EscapeString(link)))
+	}
+	return lines
+}
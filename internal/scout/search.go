@@ -0,0 +1,81 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/h3nc4/TelegramScout/internal/model"
+	"github.com/h3nc4/TelegramScout/internal/notifier"
+)
+
+// Longest message excerpt shown per search result, in runes
+const searchSnippet = 120
+
+// Search the history of monitored chats on the server
+type HistorySearcher interface {
+	Search(ctx context.Context, text string, chatID int64, limit int) ([]model.Message, error)
+}
+
+// Answer /search through h. Call before Commands.
+func (s *Scout) UseHistorySearcher(h HistorySearcher) {
+	s.searcher = h
+}
+
+// /search <query> [in <chat id>]
+func (s *Scout) searchCommand(ctx context.Context, cmd notifier.Command) (string, error) {
+	args, chatID, err := cutChat(cmd.Args)
+	if err != nil {
+		return "", err
+	}
+	if len(args) == 0 {
+		return "", fmt.Errorf("usage: /search <query> [in <chat id>]")
+	}
+	text := strings.Join(args, " ")
+
+	found, err := s.searcher.Search(ctx, text, chatID, s.cfg.Commands.SearchLimit)
+	if err != nil {
+		return "", fmt.Errorf("search failed: %w", err)
+	}
+	if len(found) == 0 {
+		return fmt.Sprintf("🔎 No messages found for <b>%s</b> in %s", html.EscapeString(text), chatLabel(chatID)), nil
+	}
+
+	lines := []string{fmt.Sprintf("🔎 <b>%d results for</b> %s", len(found), html.EscapeString(text))}
+	for _, m := range found {
+		line := fmt.Sprintf("• <b>%s</b> %s: %s", html.EscapeString(m.ChatTitle), m.Date.UTC().Format("2006-01-02 15:04"), html.EscapeString(snippet(m.Text)))
+		if m.Link != "" {
+			line += fmt.Sprintf(" <a href=\"%s\">Link</a>", html.EscapeString(m.Link))
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// Collapse whitespace in text and cut it to searchSnippet runes
+func snippet(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if r := []rune(text); len(r) > searchSnippet {
+		return string(r[:searchSnippet]) + "…"
+	}
+	return text
+}
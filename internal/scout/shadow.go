@@ -0,0 +1,78 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"expvar"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/archive"
+	"github.com/h3nc4/TelegramScout/internal/logger"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Matches of counter rules by rule, served at /debug/vars and pushed to the metrics backends
+var counterMetrics = expvar.NewMap("counters")
+
+// Run every shadow rule against a message, counting and archiving matches
+// instead of alerting. live reports whether a live rule matched it too, so
+// the matches a shadow rule would add are counted apart ("shadow_new:<rule>").
+func (s *Scout) evaluateShadow(msg model.Message, live bool) {
+	for _, rule := range s.shadow {
+		loc := rule.locate(msg)
+		if loc == nil {
+			continue
+		}
+
+		matchMetrics.Add("shadow:"+rule.original, 1)
+		if !live {
+			matchMetrics.Add("shadow_new:"+rule.original, 1)
+		}
+		if s.archive != nil {
+			r := archive.FromMessage(archive.KindShadow, msg)
+			r.Rule = rule.original
+			s.archive.Add(r)
+		}
+		s.log.Debug("Shadow rule matched",
+			zap.String("keyword", rule.original),
+			zap.String("matched", msg.Text[loc[0]:loc[1]]),
+			zap.Bool("live", live),
+			zap.Int64("chat_id", msg.ChatID),
+			zap.Int("msg_id", msg.ID),
+			logger.TraceField(msg.TraceID),
+		)
+	}
+}
+
+// Count the matches of every counter rule, archiving them when the archive is
+// enabled. Counters never alert and do not take part in live matching.
+func (s *Scout) evaluateCounters(msg model.Message) {
+	for _, rule := range s.counters {
+		if rule.locate(msg) == nil {
+			continue
+		}
+		counterMetrics.Add(rule.original, 1)
+		if s.archive != nil {
+			r := archive.FromMessage(archive.KindCount, msg)
+			r.Rule = rule.original
+			s.archive.Add(r)
+		}
+	}
+}
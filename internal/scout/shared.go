@@ -0,0 +1,162 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/cluster"
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Keys of the shared digest
+const (
+	sharedDigestList    = "digest"
+	sharedDigestDropped = "digest:dropped"
+)
+
+// Bound calls to the shared backend so it cannot stall the pipeline
+const sharedTimeout = 2 * time.Second
+
+// Share dedup and digests with other instances through store. Call before Start.
+func (s *Scout) UseSharedState(store *cluster.Store) {
+	s.shared = store
+	s.digest = &sharedDigest{store: store, size: s.digestSize(), log: s.log}
+}
+
+func (s *Scout) digestSize() int {
+	if s.cfg.Pipeline.DigestSize > 0 {
+		return s.cfg.Pipeline.DigestSize
+	}
+	return config.DefaultDigestSize
+}
+
+// Claim the message and its content for this instance, reporting false if
+// another instance alerted on either. Backend errors fail open.
+func (s *Scout) claim(ctx context.Context, msg model.Message, hash string) bool {
+	ttl := s.cfg.Dedup.TTL
+	if ttl <= 0 {
+		ttl = config.DefaultDedupTTL
+	}
+
+	keys := []string{fmt.Sprintf("seen:msg:%d:%d", msg.ChatID, msg.ID)}
+	if hash != "" {
+		keys = append(keys, "seen:content:"+hash)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, sharedTimeout)
+	defer cancel()
+	for _, k := range keys {
+		first, err := s.shared.Claim(ctx, k, ttl)
+		if err != nil {
+			s.log.Warn("Shared dedup unavailable, alerting anyway", zap.Error(err))
+			return true
+		}
+		if !first {
+			return false
+		}
+	}
+	return true
+}
+
+// digestStore kept in the shared backend, so any instance can flush it
+type sharedDigest struct {
+	store *cluster.Store
+	size  int
+	log   *zap.Logger
+}
+
+func (d *sharedDigest) context() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), sharedTimeout)
+}
+
+func (d *sharedDigest) add(line string) {
+	ctx, cancel := d.context()
+	defer cancel()
+
+	dropped, err := d.store.Push(ctx, sharedDigestList, line, d.size)
+	if err == nil && dropped > 0 {
+		err = d.store.Add(ctx, sharedDigestDropped, dropped)
+	}
+	if err != nil {
+		d.log.Error("Failed to queue alert in shared digest", zap.Error(err))
+	}
+}
+
+func (d *sharedDigest) take() ([]string, int) {
+	ctx, cancel := d.context()
+	defer cancel()
+
+	// Lines are short, a generous batch fits one message most of the time
+	lines, err := d.store.Pop(ctx, sharedDigestList, d.size)
+	if err != nil {
+		d.log.Error("Failed to read shared digest", zap.Error(err))
+		return nil, 0
+	}
+
+	length := 0
+	n := 0
+	for n < len(lines) {
+		size := utf8.RuneCountInString(lines[n]) + 1
+		if n > 0 && length+size > maxDigestLength {
+			break
+		}
+		length += size
+		n++
+	}
+	if err := d.store.PushFront(ctx, sharedDigestList, lines[n:]...); err != nil {
+		d.log.Error("Failed to return lines to shared digest", zap.Error(err))
+	}
+
+	dropped, err := d.store.Take(ctx, sharedDigestDropped)
+	if err != nil {
+		d.log.Error("Failed to read shared digest drop count", zap.Error(err))
+	}
+	return lines[:n], dropped
+}
+
+func (d *sharedDigest) requeue(lines []string, dropped int) {
+	ctx, cancel := d.context()
+	defer cancel()
+
+	err := d.store.PushFront(ctx, sharedDigestList, lines...)
+	if err == nil && dropped > 0 {
+		err = d.store.Add(ctx, sharedDigestDropped, dropped)
+	}
+	if err != nil {
+		d.log.Error("Failed to requeue shared digest", zap.Error(err))
+	}
+}
+
+func (d *sharedDigest) len() int {
+	ctx, cancel := d.context()
+	defer cancel()
+
+	n, err := d.store.Len(ctx, sharedDigestList)
+	if err != nil {
+		d.log.Error("Failed to read shared digest length", zap.Error(err))
+	}
+	return n
+}
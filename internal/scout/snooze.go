@@ -0,0 +1,185 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/audit"
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/notifier"
+	"github.com/h3nc4/TelegramScout/internal/snooze"
+)
+
+// Mute rules in chats while store holds a snooze for them. Call before Start.
+func (s *Scout) UseSnoozes(store *snooze.Store) {
+	s.snoozes = store
+}
+
+// Report whether rule is muted in chatID
+func (s *Scout) snoozed(rule string, chatID int64) bool {
+	return s.snoozes != nil && s.snoozes.Active(rule, chatID)
+}
+
+// Snooze rule in chatID for d, returning the confirmation shown to the user
+func (s *Scout) snooze(rule string, chatID int64, d time.Duration, by, source string) (string, error) {
+	if s.snoozes == nil {
+		return "", fmt.Errorf("snoozing is disabled")
+	}
+	sn, err := s.snoozes.Add(rule, chatID, d, by)
+	if err != nil {
+		// Still snoozed in memory, only the restart would forget it
+		s.log.Error("Failed to save snoozes", zap.Error(err))
+	}
+	s.log.Info("Rule snoozed", zap.String("rule", rule), zap.Int64("chat_id", chatID), zap.Duration("period", d), zap.String("by", by))
+	s.record(audit.Entry{Actor: by, Source: source, Action: "snooze", Target: rule, ChatID: chatID, Detail: "until " + sn.Until.UTC().Format(time.RFC3339)})
+	return fmt.Sprintf("🔕 Snoozed <b>%s</b> in %s until %s", html.EscapeString(rule), chatLabel(chatID), sn.Until.UTC().Format("2006-01-02 15:04 MST")), nil
+}
+
+// Return the bot commands the scout answers
+func (s *Scout) Commands() map[string]notifier.CommandHandler {
+	cmds := make(map[string]notifier.CommandHandler)
+	if s.searcher != nil {
+		cmds["search"] = s.searchCommand
+	}
+	if s.snoozes != nil {
+		cmds["snooze"] = s.snoozeCommand
+		cmds["unsnooze"] = s.unsnoozeCommand
+		cmds["snoozes"] = s.snoozesCommand
+	}
+	if s.temporary != nil {
+		cmds["addkeyword"] = s.addKeywordCommand
+		cmds["removekeyword"] = s.removeKeywordCommand
+		cmds["tempkeywords"] = s.tempKeywordsCommand
+	}
+	if s.actor != nil && s.cfg.Actions.Confirm {
+		cmds["confirm"] = s.confirmCommand
+		cmds["pending"] = s.pendingCommand
+	}
+	return cmds
+}
+
+// Commands that only read, which viewers may run, and inline queries
+var readOnlyCommands = []string{"search", "snoozes", "tempkeywords", "pending", "inline"}
+
+// Return the role needed to run a command or press an alert button. Every
+// button and every command not known to only read changes state.
+func CommandRole(name string) string {
+	if slices.Contains(readOnlyCommands, name) {
+		return config.RoleViewer
+	}
+	return config.RoleAdmin
+}
+
+// /snooze <rule> <duration> [in <chat id>]
+func (s *Scout) snoozeCommand(_ context.Context, cmd notifier.Command) (string, error) {
+	args, chatID, err := cutChat(cmd.Args)
+	if err != nil {
+		return "", err
+	}
+	if len(args) < 2 {
+		return "", fmt.Errorf("usage: /snooze <rule> <duration> [in <chat id>]")
+	}
+	d, err := parsePeriod(args[len(args)-1])
+	if err != nil {
+		return "", err
+	}
+	return s.snooze(strings.Join(args[:len(args)-1], " "), chatID, d, cmd.User, audit.SourceCommand)
+}
+
+// /unsnooze <rule> [in <chat id>]
+func (s *Scout) unsnoozeCommand(_ context.Context, cmd notifier.Command) (string, error) {
+	args, chatID, err := cutChat(cmd.Args)
+	if err != nil {
+		return "", err
+	}
+	if len(args) == 0 {
+		return "", fmt.Errorf("usage: /unsnooze <rule> [in <chat id>]")
+	}
+	rule := strings.Join(args, " ")
+	removed, err := s.snoozes.Remove(rule, chatID)
+	if err != nil {
+		s.log.Error("Failed to save snoozes", zap.Error(err))
+	}
+	if !removed {
+		return "", fmt.Errorf("%s is not snoozed in %s", rule, chatLabel(chatID))
+	}
+	s.log.Info("Rule unsnoozed", zap.String("rule", rule), zap.Int64("chat_id", chatID), zap.String("by", cmd.User))
+	s.record(audit.Entry{Actor: cmd.User, Source: audit.SourceCommand, Action: "unsnooze", Target: rule, ChatID: chatID})
+	return fmt.Sprintf("🔔 <b>%s</b> alerts again in %s", html.EscapeString(rule), chatLabel(chatID)), nil
+}
+
+// /snoozes
+func (s *Scout) snoozesCommand(_ context.Context, _ notifier.Command) (string, error) {
+	list := s.snoozes.List()
+	if len(list) == 0 {
+		return "Nothing is snoozed.", nil
+	}
+	lines := []string{"🔕 <b>Snoozed rules</b>"}
+	for _, sn := range list {
+		lines = append(lines, fmt.Sprintf("• %s in %s until %s (%s)",
+			html.EscapeString(sn.Rule), chatLabel(sn.ChatID), sn.Until.UTC().Format("2006-01-02 15:04 MST"), html.EscapeString(sn.By)))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// Split a trailing "in <chat id>" off args, zero meaning every chat
+func cutChat(args []string) ([]string, int64, error) {
+	n := len(args)
+	if n < 2 || !strings.EqualFold(args[n-2], "in") {
+		return args, 0, nil
+	}
+	id, err := strconv.ParseInt(args[n-1], 10, 64)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid chat ID %q", args[n-1])
+	}
+	return args[:n-2], id, nil
+}
+
+// Parse a Go duration, also accepting a number of days such as "2d"
+func parsePeriod(v string) (time.Duration, error) {
+	var d time.Duration
+	var err error
+	if days, ok := strings.CutSuffix(v, "d"); ok {
+		var n int
+		n, err = strconv.Atoi(days)
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		d, err = time.ParseDuration(v)
+	}
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid duration %q", v)
+	}
+	return d, nil
+}
+
+func chatLabel(chatID int64) string {
+	if chatID == 0 {
+		return "every chat"
+	}
+	return fmt.Sprintf("chat <code>%d</code>", chatID)
+}
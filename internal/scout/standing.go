@@ -0,0 +1,76 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/standing"
+)
+
+// Alert on a change of the account's standing, or a service notification it received
+func (s *Scout) StandingChange(ctx context.Context, ch standing.Change) {
+	if ch.Notice == "" {
+		s.log.Info("Account standing changed", zap.Bool("healthy", ch.After.Healthy()))
+	}
+	if err := s.notifier.Send(ctx, standingText(ch)); err != nil {
+		s.log.Error("Failed to send account standing alert", zap.Error(err))
+	}
+}
+
+func standingText(ch standing.Change) string {
+	if ch.Notice != "" {
+		return fmt.Sprintf("📨 <b>Telegram service notice</b>\n🕒 <b>Time:</b> %s\n\n<blockquote>%s</blockquote>",
+			ch.Time.Format(time.Kitchen), quote(ch.Notice))
+	}
+
+	st := ch.After
+	if st.Healthy() {
+		return fmt.Sprintf("✅ <b>Account limits lifted</b>\n🕒 <b>Time:</b> %s", ch.Time.Format(time.Kitchen))
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "⛔️ <b>Account limited</b>, some content may no longer arrive\n🕒 <b>Time:</b> %s\n", ch.Time.Format(time.Kitchen))
+	if st.Restricted {
+		b.WriteString("\n🚫 <b>Restricted</b>")
+		for _, r := range st.Reasons {
+			fmt.Fprintf(&b, "\n• %s", html.EscapeString(r))
+		}
+		b.WriteString("\n")
+	}
+	if st.Frozen {
+		b.WriteString("\n🧊 <b>Frozen</b>")
+		if !st.FrozenUntil.IsZero() {
+			fmt.Fprintf(&b, " until %s", st.FrozenUntil.Format(time.DateOnly))
+		}
+		if st.AppealURL != "" {
+			fmt.Fprintf(&b, ", <a href=\"%s\">Appeal</a>", html.EscapeString(st.AppealURL))
+		}
+		b.WriteString("\n")
+	}
+	if st.Limited {
+		fmt.Fprintf(&b, "\n🤖 <b>@SpamBot:</b>\n<blockquote>%s</blockquote>", quote(st.SpamBot))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
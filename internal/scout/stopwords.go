@@ -0,0 +1,43 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import "strings"
+
+// Words skipped between the words of phrases, by config.StopwordLanguages name.
+// Common function words, keeping negations as they change the meaning.
+var stopwordLists = map[string][]string{
+	"english": strings.Fields(`
+		a an the and or but if so as of at by for from in into on onto to with
+		about over under up down out off than then this that these those there
+		here is am are was were be been being has have had do does did will would
+		shall should can could may might must i me my we our you your he him his
+		she her it its they them their what which who whom just very too also`),
+	"portuguese": strings.Fields(`
+		a o as os um uma uns umas de do da dos das em no na nos nas num numa por
+		pelo pela pelos pelas para pra pro com e ou mas se que como ao aos à às
+		é ser foi era são está estão estou tem têm ter há eu tu ele ela nós vós
+		eles elas me te lhe nos vos lhes meu minha seu sua esse essa isso este
+		esta isto aquele aquela aquilo muito mais também já só`),
+	"spanish": strings.Fields(`
+		a al el la los las lo un una unos unas de del en por para con y e o u
+		pero si que como es son fue era está están estoy ser hay yo tú él ella
+		nosotros vosotros ellos ellas me te le nos os les mi tu su ese esa eso
+		este esta esto aquel aquella muy más también ya solo`),
+}
@@ -0,0 +1,35 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+// Count alerts so bursts can be flagged on dashboards
+type AlertCounter interface {
+	Alert()
+}
+
+// Report every alert to c. Call before Start.
+func (s *Scout) UseAlertCounter(c AlertCounter) {
+	s.alerts = c
+}
+
+func (s *Scout) countAlert() {
+	if s.alerts != nil {
+		s.alerts.Alert()
+	}
+}
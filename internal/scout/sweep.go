@@ -0,0 +1,52 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"context"
+	"time"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/logger"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Prepended to the sweep name in the rule of sweep results
+const sweepPrefix = "sweep:"
+
+// Alert on a message found by a scheduled sweep, reported as the rule
+// sweep:<name>, unless live monitoring already alerted on it
+func (s *Scout) SweepMatch(ctx context.Context, sweep config.SweepConfig, msg model.Message) {
+	if s.recent.contains(msg.ChatID, msg.ID) {
+		return
+	}
+	if msg.TraceID == "" {
+		msg.TraceID = logger.NewTraceID()
+	}
+	ctx = logger.WithTraceID(ctx, msg.TraceID)
+	s.alert(ctx, msg, Explanation{
+		Time:      time.Now(),
+		ChatID:    msg.ChatID,
+		ChatTitle: msg.ChatTitle,
+		MsgID:     msg.ID,
+		Keyword:   sweepPrefix + sweep.Name,
+		Kind:      kindSweep,
+		Matched:   sweep.Query,
+	}, "")
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"expvar"
+	"path"
+	"slices"
+)
+
+// Alerts counted per rule ("rule:<name>") and per tag ("tag:<name>"), served at /debug/vars
+var matchMetrics = expvar.NewMap("matches")
+
+// Return the labels configured for a rule, by exact name or glob, sorted and deduplicated
+func (s *Scout) tagsFor(rule string) []string {
+	var tags []string
+	for pattern, labels := range s.cfg.Monitoring.Tags {
+		if pattern == rule {
+			tags = append(tags, labels...)
+			continue
+		}
+		// Regex rules are rarely valid globs, a malformed pattern just does not match
+		if ok, _ := path.Match(pattern, rule); ok {
+			tags = append(tags, labels...)
+		}
+	}
+	slices.Sort(tags)
+	return slices.Compact(tags)
+}
+
+// Return the tags of the reported rule and of the others listed with it
+func (s *Scout) alertTags(exp Explanation) []string {
+	tags := s.tagsFor(exp.Keyword)
+	for _, rule := range exp.Also {
+		tags = append(tags, s.tagsFor(rule)...)
+	}
+	slices.Sort(tags)
+	return slices.Compact(tags)
+}
+
+// Count an alert under its rules and each of its tags
+func recordMatch(exp Explanation) {
+	matchMetrics.Add("rule:"+exp.Keyword, 1)
+	for _, rule := range exp.Also {
+		matchMetrics.Add("rule:"+rule, 1)
+	}
+	for _, tag := range exp.Tags {
+		matchMetrics.Add("tag:"+tag, 1)
+	}
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/watch"
+)
+
+// Alert on an edit or deletion of a watched message, or a watched user's profile change
+func (s *Scout) WatchChange(ctx context.Context, ch watch.Change) {
+	s.log.Info("Watched item changed", zap.String("title", ch.Title), zap.String("link", ch.Link), zap.String("change", ch.Kind))
+	if err := s.notifier.Send(ctx, watchText(ch)); err != nil {
+		s.log.Error("Failed to send watch alert", zap.String("link", ch.Link), zap.Error(err))
+	}
+}
+
+func watchText(ch watch.Change) string {
+	if ch.Kind == watch.Profile {
+		return profileText(ch)
+	}
+	head := "✏️ <b>Watched message edited</b>"
+	body := fmt.Sprintf("<b>Before:</b>\n<blockquote>%s</blockquote>\n<b>After:</b>\n<blockquote>%s</blockquote>",
+		quote(ch.Before), quote(ch.After))
+	if ch.Kind == watch.Deleted {
+		head = "🗑 <b>Watched message deleted</b>"
+		body = fmt.Sprintf("<b>Last seen:</b>\n<blockquote>%s</blockquote>", quote(ch.Before))
+	}
+	return fmt.Sprintf("%s\n📢 <b>Chat:</b> %s\n🕒 <b>Time:</b> %s\n🔗 <a href=\"%s\">Link to Message</a>\n\n%s",
+		head, html.EscapeString(ch.Title), ch.Time.Format(time.Kitchen), html.EscapeString(ch.Link), body)
+}
+
+// Labels of the profile fields a change can list
+var profileFields = map[string]string{"username": "Username", "name": "Name", "bio": "Bio", "photo": "Photo"}
+
+func profileText(ch watch.Change) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "👤 <b>Profile changed:</b> %s\n🕒 <b>Time:</b> %s\n", html.EscapeString(ch.Title), ch.Time.Format(time.Kitchen))
+	if ch.Link != "" {
+		fmt.Fprintf(&b, "🔗 <a href=\"%s\">Open Profile</a>\n", html.EscapeString(ch.Link))
+	}
+	for _, f := range ch.Fields {
+		fmt.Fprintf(&b, "\n<b>%s:</b> %s → %s", profileFields[f.Name], quoteField(f.Before), quoteField(f.After))
+	}
+	return b.String()
+}
+
+// Longest text quoted from a watched message, in characters
+const watchQuote = 1000
+
+// Escape message text for a blockquote, keeping long posts readable
+func quote(text string) string {
+	if text == "" {
+		return "<i>(no text)</i>"
+	}
+	if r := []rune(text); len(r) > watchQuote {
+		text = string(r[:watchQuote]) + "…"
+	}
+	return html.EscapeString(text)
+}
+
+// Escape a profile field value, marking empty ones
+func quoteField(value string) string {
+	if value == "" {
+		return "<i>(empty)</i>"
+	}
+	return "<code>" + html.EscapeString(value) + "</code>"
+}
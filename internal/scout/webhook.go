@@ -0,0 +1,74 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/acks"
+	"github.com/h3nc4/TelegramScout/internal/logger"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Deliver matches to the endpoints configured for their rule
+type WebhookDeliverer interface {
+	Deliver(ctx context.Context, m model.Match) error
+}
+
+// Send every match to the webhooks routed its rule through w. Call before Start.
+func (s *Scout) UseWebhooks(w WebhookDeliverer) {
+	s.webhooks = w
+}
+
+// Describe the match for the renderer, notifiers and webhooks
+func (s *Scout) matchFor(msg model.Message, exp Explanation) model.Match {
+	return model.Match{
+		ID:        acks.ID(msg.ChatID, msg.ID),
+		Rule:      exp.Keyword,
+		Kind:      exp.Kind,
+		Tags:      exp.Tags,
+		Also:      exp.Also,
+		Matched:   exp.Matched,
+		Captures:  exp.Captures,
+		ChatID:    msg.ChatID,
+		ChatTitle: msg.ChatTitle,
+		Username:  msg.Username,
+		MsgID:     msg.ID,
+		SenderID:  msg.SenderID,
+		Text:      msg.Text,
+		Link:      msg.Link,
+		AppLink:   msg.AppLink,
+		Date:      msg.Date,
+		Stale:     exp.Stale,
+		Instance:  s.cfg.Instance.Name,
+		Labels:    s.cfg.Instance.Labels,
+	}
+}
+
+// Hand the match to the webhooks, which deliver it in the background
+func (s *Scout) deliverWebhooks(ctx context.Context, msg model.Message, m model.Match) {
+	if s.webhooks == nil {
+		return
+	}
+	if err := s.webhooks.Deliver(ctx, m); err != nil {
+		s.log.Warn("Failed to queue webhooks", zap.String("keyword", m.Rule), zap.Int("msg_id", msg.ID), logger.TraceField(msg.TraceID), zap.Error(err))
+	}
+}
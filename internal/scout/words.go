@@ -0,0 +1,203 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scout
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/blevesearch/snowballstem"
+	"github.com/blevesearch/snowballstem/danish"
+	"github.com/blevesearch/snowballstem/dutch"
+	"github.com/blevesearch/snowballstem/english"
+	"github.com/blevesearch/snowballstem/finnish"
+	"github.com/blevesearch/snowballstem/french"
+	"github.com/blevesearch/snowballstem/german"
+	"github.com/blevesearch/snowballstem/hungarian"
+	"github.com/blevesearch/snowballstem/italian"
+	"github.com/blevesearch/snowballstem/norwegian"
+	"github.com/blevesearch/snowballstem/portuguese"
+	"github.com/blevesearch/snowballstem/romanian"
+	"github.com/blevesearch/snowballstem/russian"
+	"github.com/blevesearch/snowballstem/spanish"
+	"github.com/blevesearch/snowballstem/swedish"
+	"github.com/blevesearch/snowballstem/turkish"
+)
+
+// Snowball stemmers, by config.StemmingLanguages name
+var stemmers = map[string]func(*snowballstem.Env) bool{
+	"danish":     danish.Stem,
+	"dutch":      dutch.Stem,
+	"english":    english.Stem,
+	"finnish":    finnish.Stem,
+	"french":     french.Stem,
+	"german":     german.Stem,
+	"hungarian":  hungarian.Stem,
+	"italian":    italian.Stem,
+	"norwegian":  norwegian.Stem,
+	"portuguese": portuguese.Stem,
+	"romanian":   romanian.Stem,
+	"russian":    russian.Stem,
+	"spanish":    spanish.Stem,
+	"swedish":    swedish.Stem,
+	"turkish":    turkish.Stem,
+}
+
+// A lowercased word of a text and its byte offsets
+type token struct {
+	word       string
+	start, end int
+}
+
+// Split text into runs of letters and digits
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text {
+		inWord := unicode.IsLetter(r) || unicode.IsNumber(r)
+		switch {
+		case inWord && start < 0:
+			start = i
+		case !inWord && start >= 0:
+			tokens = append(tokens, token{strings.ToLower(text[start:i]), start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{strings.ToLower(text[start:]), start, len(text)})
+	}
+	return tokens
+}
+
+// Match plain keywords word by word on tokenized text: by their stems in the
+// configured languages, and skipping stopwords between the words of phrases.
+// The words of the last text are kept, as every rule is tried on the same text.
+type wordMatcher struct {
+	stemmers  []func(*snowballstem.Env) bool
+	stopwords map[string]bool // Nil when not skipped
+
+	mux    sync.Mutex
+	text   string
+	tokens []token    // Without stopwords
+	stems  [][]string // Stems of tokens per language, or the tokens themselves without stemming
+}
+
+// Return nil without stemming languages or stopwords, matching keywords as spelled
+func newWordMatcher(stemming, stopwords []string) *wordMatcher {
+	if len(stemming) == 0 && len(stopwords) == 0 {
+		return nil
+	}
+	wm := &wordMatcher{}
+	for _, lang := range stemming {
+		wm.stemmers = append(wm.stemmers, stemmers[lang])
+	}
+	for _, lang := range stopwords {
+		if wm.stopwords == nil {
+			wm.stopwords = make(map[string]bool)
+		}
+		for _, w := range stopwordLists[lang] {
+			wm.stopwords[w] = true
+		}
+	}
+	return wm
+}
+
+// Return the stems of words in every language
+func (wm *wordMatcher) stem(words []string) [][]string {
+	if len(wm.stemmers) == 0 {
+		return [][]string{words}
+	}
+	out := make([][]string, len(wm.stemmers))
+	for l, fn := range wm.stemmers {
+		out[l] = make([]string, len(words))
+		for i, w := range words {
+			env := snowballstem.NewEnv(w)
+			fn(env)
+			out[l][i] = env.Current()
+		}
+	}
+	return out
+}
+
+// Drop stopwords from tokens
+func (wm *wordMatcher) content(tokens []token) []token {
+	if wm.stopwords == nil {
+		return tokens
+	}
+	return slices.DeleteFunc(tokens, func(t token) bool { return wm.stopwords[t.word] })
+}
+
+// Return the words of text other than stopwords, and their stems in every language
+func (wm *wordMatcher) analyze(text string) ([]token, [][]string) {
+	wm.mux.Lock()
+	defer wm.mux.Unlock()
+	if wm.stems == nil || text != wm.text {
+		wm.tokens = wm.content(tokenize(text))
+		wm.text, wm.stems = text, wm.stem(tokenWords(wm.tokens))
+	}
+	return wm.tokens, wm.stems
+}
+
+// Return a find function matching the words of keyword in order, in any form
+// sharing their stems and with any stopwords between them. Nil for keywords
+// that are not only words, and for those matched as spelled anyway.
+func (wm *wordMatcher) find(keyword string) func(text string) []int {
+	if wm == nil {
+		return nil
+	}
+	tokens := tokenize(keyword)
+	if len(tokens) == 0 || strings.Join(tokenWords(tokens), " ") != normalizeLiteral(keyword) {
+		return nil
+	}
+	if len(tokens) == 1 && len(wm.stemmers) == 0 {
+		return nil
+	}
+	// Stopwords only matter between words, a single one is a keyword like any other
+	if len(tokens) > 1 {
+		tokens = wm.content(tokens)
+	}
+	if len(tokens) == 0 {
+		return nil
+	}
+	want := wm.stem(tokenWords(tokens))
+
+	return func(text string) []int {
+		tokens, stems := wm.analyze(text)
+		n := len(want[0])
+		for i := 0; i+n <= len(tokens); i++ {
+			for l := range want {
+				if slices.Equal(stems[l][i:i+n], want[l]) {
+					return []int{tokens[i].start, tokens[i+n-1].end}
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// Return the words of tokens
+func tokenWords(tokens []token) []string {
+	words := make([]string, len(tokens))
+	for i, t := range tokens {
+		words[i] = t.word
+	}
+	return words
+}
@@ -0,0 +1,183 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package scouttest drives the client, scout and notifier pipeline with
+// synthetic MTProto updates, for integration tests that need no Telegram
+// account or connection.
+//
+// Updates go through the same dispatcher a connected client feeds, so a test
+// for a new update type builds the tg.Updates Telegram would send, hands it
+// to Harness.Dispatch and checks the alerts that come out:
+//
+//	h := scouttest.New(t, config.Config{Monitoring: config.MonitoringRules{Keywords: []string{"gpu"}}})
+//	deals := scouttest.Channel(1001, "Deals", "deals")
+//	h.Post(deals, "cheap GPU")
+//	if alert := h.Alert(); !strings.Contains(alert, "Deals") { ... }
+package scouttest
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gotd/td/tg"
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/model"
+	"github.com/h3nc4/TelegramScout/internal/scout"
+	"github.com/h3nc4/TelegramScout/internal/telegram"
+)
+
+// How long Alert waits for an alert
+const AlertTimeout = 2 * time.Second
+
+// Alerts buffered before the pipeline blocks on sending
+const alertBuffer = 100
+
+// Run the pipeline on updates injected by the test
+type Harness struct {
+	Client *telegram.Client
+	Scout  *scout.Scout
+
+	t      testing.TB
+	ctx    context.Context
+	alerts chan string
+
+	mux    sync.Mutex
+	nextID int
+}
+
+// Start the pipeline with cfg, stopped when the test ends. Chats cannot be
+// resolved without a connection, so every chat is monitored through the
+// config.AllChats wildcard; exclude_chats still applies.
+func New(t testing.TB, cfg config.Config) *Harness {
+	t.Helper()
+	if cfg.Session == "" {
+		cfg.Session = "scouttest"
+	}
+	cfg.Monitoring.Chats = []string{config.AllChats}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	msgChan := make(chan model.Message)
+	client, err := telegram.NewClient(&cfg, zap.NewNop(), msgChan)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	h := &Harness{
+		Client: client,
+		t:      t,
+		ctx:    ctx,
+		alerts: make(chan string, alertBuffer),
+	}
+	h.Scout = scout.New(&cfg, notifier{alerts: h.alerts}, zap.NewNop())
+	go h.Scout.Start(ctx, msgChan)
+	return h
+}
+
+// Build a public channel to post in
+func Channel(id int64, title, username string) *tg.Channel {
+	return &tg.Channel{ID: id, AccessHash: id, Title: title, Username: username, Broadcast: true}
+}
+
+// Build a basic group to post in
+func Group(id int64, title string) *tg.Chat {
+	return &tg.Chat{ID: id, Title: title}
+}
+
+// Deliver updates to the client as Telegram would, failing the test on errors
+func (h *Harness) Dispatch(updates tg.UpdatesClass) {
+	h.t.Helper()
+	if err := h.Client.HandleUpdates(h.ctx, updates); err != nil {
+		h.t.Fatalf("failed to handle updates: %v", err)
+	}
+}
+
+// Post text in channel and return the new message
+func (h *Harness) Post(channel *tg.Channel, text string) *tg.Message {
+	h.t.Helper()
+	msg := h.Message(&tg.PeerChannel{ChannelID: channel.ID}, text)
+	h.Dispatch(&tg.Updates{
+		Updates: []tg.UpdateClass{&tg.UpdateNewChannelMessage{Message: msg}},
+		Chats:   []tg.ChatClass{channel},
+		Date:    msg.Date,
+	})
+	return msg
+}
+
+// Send text in group as user and return the new message
+func (h *Harness) Say(group *tg.Chat, user *tg.User, text string) *tg.Message {
+	h.t.Helper()
+	msg := h.Message(&tg.PeerChat{ChatID: group.ID}, text)
+	msg.SetFromID(&tg.PeerUser{UserID: user.ID})
+	h.Dispatch(&tg.Updates{
+		Updates: []tg.UpdateClass{&tg.UpdateNewMessage{Message: msg}},
+		Chats:   []tg.ChatClass{group},
+		Users:   []tg.UserClass{user},
+		Date:    msg.Date,
+	})
+	return msg
+}
+
+// Build a message in peer with the next ID, for tests that build their own updates
+func (h *Harness) Message(peer tg.PeerClass, text string) *tg.Message {
+	h.mux.Lock()
+	h.nextID++
+	id := h.nextID
+	h.mux.Unlock()
+	return &tg.Message{ID: id, PeerID: peer, Message: text, Date: int(time.Now().Unix())}
+}
+
+// Wait for the next alert, failing the test after AlertTimeout
+func (h *Harness) Alert() string {
+	h.t.Helper()
+	select {
+	case alert := <-h.alerts:
+		return alert
+	case <-time.After(AlertTimeout):
+		h.t.Fatal("expected an alert")
+		return ""
+	}
+}
+
+// Fail the test if an alert arrives within d
+func (h *Harness) NoAlert(d time.Duration) {
+	h.t.Helper()
+	select {
+	case alert := <-h.alerts:
+		h.t.Fatalf("expected no alert, got %q", alert)
+	case <-time.After(d):
+	}
+}
+
+// Collect alerts the scout sends
+type notifier struct {
+	alerts chan<- string
+}
+
+func (n notifier) Send(ctx context.Context, text string) error {
+	select {
+	case n.alerts <- text:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package scouttest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gotd/td/tg"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+func TestChannelPost(t *testing.T) {
+	h := New(t, config.Config{
+		Monitoring: config.MonitoringRules{Keywords: []string{"rtx 5090"}},
+		Links:      config.LinksConfig{Style: config.LinkStyleWeb},
+	})
+	deals := Channel(1803446893, "GPU Deals", "gpudeals")
+
+	h.Post(deals, "nothing interesting")
+	msg := h.Post(deals, "Selling an RTX 5090, barely used")
+
+	alert := h.Alert()
+	for _, want := range []string{"GPU Deals", "rtx 5090", fmt.Sprintf("https://t.me/gpudeals/%d", msg.ID)} {
+		if !strings.Contains(alert, want) {
+			t.Errorf("expected %q in the alert, got %q", want, alert)
+		}
+	}
+	h.NoAlert(50 * time.Millisecond)
+}
+
+func TestGroupMessage(t *testing.T) {
+	h := New(t, config.Config{
+		Monitoring: config.MonitoringRules{Keywords: []string{"urgent"}, ExcludeChats: []string{"-42"}},
+	})
+	alice := &tg.User{ID: 7, FirstName: "Alice"}
+
+	h.Say(Group(42, "Excluded"), alice, "urgent")
+	h.NoAlert(50 * time.Millisecond)
+
+	h.Say(Group(43, "Team"), alice, "this is URGENT")
+	if alert := h.Alert(); !strings.Contains(alert, "Team") {
+		t.Errorf("expected the group title in the alert, got %q", alert)
+	}
+}
+
+// Updates without a modelled message are ignored rather than failing
+func TestUnhandledUpdate(t *testing.T) {
+	h := New(t, config.Config{Monitoring: config.MonitoringRules{Keywords: []string{"urgent"}}})
+	h.Dispatch(&tg.Updates{
+		Updates: []tg.UpdateClass{&tg.UpdateNewChannelMessage{Message: &tg.MessageEmpty{ID: 1}}},
+	})
+	h.NoAlert(50 * time.Millisecond)
+}
@@ -0,0 +1,159 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package snooze mutes rules in chats for a while, keeping the periods
+// across restarts.
+package snooze
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/h3nc4/TelegramScout/internal/atomicfile"
+)
+
+// A rule muted in a chat until a point in time
+type Snooze struct {
+	Rule   string    `json:"rule"`
+	ChatID int64     `json:"chat_id"` // Zero mutes the rule in every chat
+	Until  time.Time `json:"until"`
+	By     string    `json:"by,omitempty"`
+}
+
+type key struct {
+	rule   string
+	chatID int64
+}
+
+// Hold the active snoozes, saving them to a file on every change
+type Store struct {
+	mux     sync.Mutex
+	path    string
+	snoozes map[key]Snooze
+	now     func() time.Time
+}
+
+// Load the snoozes saved at path, a missing file holds none. An empty path keeps them in memory only.
+func Load(path string) (*Store, error) {
+	s := &Store{path: path, snoozes: make(map[key]Snooze), now: time.Now}
+	if path == "" {
+		return s, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var list []Snooze
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("invalid snooze file %s: %w", path, err)
+	}
+	for _, sn := range list {
+		s.snoozes[key{sn.Rule, sn.ChatID}] = sn
+	}
+	return s, nil
+}
+
+// Mute rule in chatID for d on behalf of by, replacing any earlier snooze of the pair
+func (s *Store) Add(rule string, chatID int64, d time.Duration, by string) (Snooze, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	sn := Snooze{Rule: rule, ChatID: chatID, Until: s.now().Add(d), By: by}
+	s.snoozes[key{rule, chatID}] = sn
+	return sn, s.save()
+}
+
+// Unmute rule in chatID, reporting whether it was snoozed
+func (s *Store) Remove(rule string, chatID int64) (bool, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	k := key{rule, chatID}
+	sn, ok := s.snoozes[k]
+	if !ok {
+		return false, nil
+	}
+	delete(s.snoozes, k)
+	return s.now().Before(sn.Until), s.save()
+}
+
+// Report whether rule is muted in chatID, by a snooze for that chat or for every chat
+func (s *Store) Active(rule string, chatID int64) bool {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	now := s.now()
+	for _, k := range []key{{rule, chatID}, {rule, 0}} {
+		if sn, ok := s.snoozes[k]; ok && now.Before(sn.Until) {
+			return true
+		}
+	}
+	return false
+}
+
+// Return the snoozes still running, ending soonest first
+func (s *Store) List() []Snooze {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	now := s.now()
+	var out []Snooze
+	for _, sn := range s.snoozes {
+		if now.Before(sn.Until) {
+			out = append(out, sn)
+		}
+	}
+	slices.SortFunc(out, func(a, b Snooze) int { return a.Until.Compare(b.Until) })
+	return out
+}
+
+// Write the running snoozes to the file, dropping expired ones. Called with mux held.
+func (s *Store) save() error {
+	now := s.now()
+	list := []Snooze{}
+	for k, sn := range s.snoozes {
+		if !now.Before(sn.Until) {
+			delete(s.snoozes, k)
+			continue
+		}
+		list = append(list, sn)
+	}
+	if s.path == "" {
+		return nil
+	}
+	slices.SortFunc(list, func(a, b Snooze) int {
+		return cmp.Or(cmp.Compare(a.Rule, b.Rule), cmp.Compare(a.ChatID, b.ChatID))
+	})
+
+	data, err := json.Marshal(list)
+	if err != nil {
+		return err
+	}
+	return atomicfile.Write(s.path, data)
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package snooze
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snoozes.json")
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() of missing file error = %v", err)
+	}
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	if _, err := s.Add("giveaway", -1001, time.Hour, "alice"); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if _, err := s.Add("sale", 0, 2*time.Hour, "bob"); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	tests := []struct {
+		rule   string
+		chatID int64
+		want   bool
+	}{
+		{"giveaway", -1001, true},
+		{"giveaway", -1002, false},
+		{"sale", -1002, true},
+		{"urgent", -1001, false},
+	}
+	for _, tt := range tests {
+		if got := s.Active(tt.rule, tt.chatID); got != tt.want {
+			t.Errorf("Active(%q, %d) = %t, want %t", tt.rule, tt.chatID, got, tt.want)
+		}
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	loaded.now = s.now
+	if list := loaded.List(); len(list) != 2 || list[0].Rule != "giveaway" || list[0].By != "alice" {
+		t.Errorf("expected both snoozes to survive a restart, soonest first, got %+v", list)
+	}
+
+	if removed, err := s.Remove("sale", 0); err != nil || !removed {
+		t.Errorf("Remove() = %t, %v", removed, err)
+	}
+	if removed, _ := s.Remove("sale", 0); removed {
+		t.Error("expected nothing to remove twice")
+	}
+
+	now = now.Add(time.Hour)
+	if s.Active("giveaway", -1001) {
+		t.Error("expected the snooze to expire")
+	}
+	if list := s.List(); len(list) != 0 {
+		t.Errorf("expected no running snoozes, got %+v", list)
+	}
+}
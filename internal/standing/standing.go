@@ -0,0 +1,186 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package standing checks whether the monitoring account is restricted,
+// frozen or spam limited, since a limited account silently stops receiving
+// some content, and reports when that changes.
+package standing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/atomicfile"
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+// Wait after starting before the first check, so the client has connected
+const startDelay = time.Minute
+
+// What Telegram reports about the account
+type Status struct {
+	Restricted  bool      `json:"restricted"`
+	Reasons     []string  `json:"reasons,omitempty"`     // Restriction texts, prefixed by the platforms they apply to
+	Frozen      bool      `json:"frozen"`                // The account is frozen and can only appeal
+	FrozenUntil time.Time `json:"frozen_until,omitzero"` // When the freeze ends, if known
+	AppealURL   string    `json:"appeal_url,omitempty"`  // Where a freeze can be appealed
+	Limited     bool      `json:"limited"`               // @SpamBot reports limits
+	SpamBot     string    `json:"spambot,omitempty"`     // @SpamBot's answer, when asked
+	Checked     time.Time `json:"checked,omitzero"`      // When the status was fetched
+}
+
+// Report whether nothing limits the account
+func (s Status) Healthy() bool {
+	return !s.Restricted && !s.Frozen && !s.Limited
+}
+
+// Report whether two statuses describe the same limits, ignoring when they were fetched
+func (s Status) same(o Status) bool {
+	return s.Restricted == o.Restricted && slices.Equal(s.Reasons, o.Reasons) &&
+		s.Frozen == o.Frozen && s.FrozenUntil.Equal(o.FrozenUntil) && s.Limited == o.Limited
+}
+
+// Fetch the account's status, asking @SpamBot too when spamBot is set
+type Checker interface {
+	AccountStatus(ctx context.Context, spamBot bool) (Status, error)
+}
+
+// A change of the account's standing, or a notice Telegram sent it
+type Change struct {
+	Before Status
+	After  Status
+	Notice string // Text of a service notification, empty for status changes
+	Time   time.Time
+}
+
+// Called with every change
+type Handler func(ctx context.Context, ch Change)
+
+// Check the account on an interval, remembering the last status in a state file
+type Monitor struct {
+	interval time.Duration
+	spamBot  bool
+	check    Checker
+	handle   Handler
+	path     string
+	log      *zap.Logger
+
+	mux  sync.Mutex
+	last Status
+}
+
+// Create a Monitor, loading the last status seen from path
+func New(cfg config.StandingConfig, check Checker, path string, handle Handler, log *zap.Logger) (*Monitor, error) {
+	m := &Monitor{
+		interval: cfg.Interval,
+		spamBot:  cfg.SpamBot,
+		check:    check,
+		handle:   handle,
+		path:     path,
+		log:      log,
+	}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return nil, err
+	default:
+		if err := json.Unmarshal(data, &m.last); err != nil {
+			return nil, fmt.Errorf("invalid standing state %s: %w", path, err)
+		}
+	}
+	return m, nil
+}
+
+// Check the account shortly after starting and then on the interval until ctx is done
+func (m *Monitor) Run(ctx context.Context) {
+	wait := min(startDelay, m.interval)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+			m.Check(ctx)
+		}
+		wait = m.interval
+	}
+}
+
+// Fetch the account's status once, reporting it if its limits changed
+func (m *Monitor) Check(ctx context.Context) {
+	st, err := m.check.AccountStatus(ctx, m.spamBot)
+	if err != nil {
+		if ctx.Err() == nil {
+			m.log.Warn("Failed to check account standing", zap.Error(err))
+		}
+		return
+	}
+	if !st.Healthy() {
+		m.log.Warn("Account is limited", zap.Bool("restricted", st.Restricted), zap.Bool("frozen", st.Frozen), zap.Bool("spam_limited", st.Limited))
+	}
+
+	m.mux.Lock()
+	before := m.last
+	m.last = st
+	err = m.save()
+	m.mux.Unlock()
+	if err != nil {
+		m.log.Error("Failed to save account standing", zap.String("path", m.path), zap.Error(err))
+	}
+
+	// The first check of a healthy account has nothing to report
+	if before.Checked.IsZero() && st.Healthy() || before.same(st) {
+		return
+	}
+	m.handle(ctx, Change{Before: before, After: st, Time: st.Checked})
+}
+
+// Report a service notification Telegram sent the account, and check its standing right after
+func (m *Monitor) Notice(ctx context.Context, text string, at time.Time) {
+	m.mux.Lock()
+	last := m.last
+	m.mux.Unlock()
+	m.handle(ctx, Change{Before: last, After: last, Notice: text, Time: at})
+	m.Check(ctx)
+}
+
+// Return the last status fetched
+func (m *Monitor) Last() Status {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	return m.last
+}
+
+// Write the last status to the state file, with mux held
+func (m *Monitor) save() error {
+	data, err := json.Marshal(m.last)
+	if err != nil {
+		return err
+	}
+	return atomicfile.Write(m.path, data)
+}
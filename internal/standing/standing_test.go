@@ -0,0 +1,82 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package standing
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+type fakeChecker struct {
+	status  Status
+	spamBot bool
+}
+
+func (f *fakeChecker) AccountStatus(ctx context.Context, spamBot bool) (Status, error) {
+	f.spamBot = spamBot
+	f.status.Checked = time.Now()
+	return f.status, nil
+}
+
+func TestMonitor_Check(t *testing.T) {
+	check := &fakeChecker{}
+	path := filepath.Join(t.TempDir(), "standing.json")
+	var got []Change
+	handle := func(ctx context.Context, ch Change) { got = append(got, ch) }
+	cfg := config.StandingConfig{Enabled: true, Interval: time.Hour, SpamBot: true}
+	m, err := New(cfg, check, path, handle, zap.NewNop())
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	ctx := context.Background()
+
+	m.Check(ctx)
+	if len(got) != 0 || !check.spamBot {
+		t.Fatalf("expected a healthy first check to stay quiet and ask @SpamBot, got %+v", got)
+	}
+
+	check.status = Status{Restricted: true, Reasons: []string{"all: spam"}}
+	m.Check(ctx)
+	m.Check(ctx)
+	if len(got) != 1 || !got[0].Before.Healthy() || got[0].After.Healthy() {
+		t.Fatalf("expected one alert when restricted, got %+v", got)
+	}
+
+	// The restriction is remembered across restarts, so lifting it is reported
+	m, err = New(cfg, check, path, handle, zap.NewNop())
+	if err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	check.status = Status{}
+	m.Check(ctx)
+	if len(got) != 2 || !got[1].After.Healthy() || got[1].Before.Healthy() {
+		t.Fatalf("expected the lifted restriction reported, got %+v", got)
+	}
+
+	m.Notice(ctx, "Your account was limited", time.Now())
+	if len(got) != 3 || got[2].Notice != "Your account was limited" {
+		t.Errorf("expected the notice reported, got %+v", got)
+	}
+}
@@ -0,0 +1,186 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package sweep searches the history of monitored chats on a schedule and
+// reports the results it has not reported before.
+package sweep
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/atomicfile"
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/model"
+	"github.com/h3nc4/TelegramScout/internal/schedule"
+)
+
+// Results remembered per sweep, the oldest are forgotten beyond this
+const seenSize = 2000
+
+// Search the history of monitored chats on the server
+type Searcher interface {
+	Search(ctx context.Context, text string, chatID int64, limit int) ([]model.Message, error)
+}
+
+// Called with every result a sweep has not reported before
+type Handler func(ctx context.Context, sweep config.SweepConfig, msg model.Message)
+
+type job struct {
+	cfg   config.SweepConfig
+	sched schedule.Schedule
+}
+
+// Run the configured sweeps, remembering reported results in a state file
+type Runner struct {
+	jobs   []job
+	search Searcher
+	handle Handler
+	path   string
+	log    *zap.Logger
+
+	mux  sync.Mutex
+	seen map[string][]string // Sweep name to result keys, oldest first
+}
+
+// Create a Runner for sweeps, loading the results already reported from path
+func New(sweeps []config.SweepConfig, search Searcher, path string, handle Handler, log *zap.Logger) (*Runner, error) {
+	r := &Runner{search: search, handle: handle, path: path, log: log, seen: make(map[string][]string)}
+	for _, sw := range sweeps {
+		sched, err := schedule.Parse(sw.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("sweep %s: %w", sw.Name, err)
+		}
+		r.jobs = append(r.jobs, job{cfg: sw, sched: sched})
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &r.seen); err != nil {
+		return nil, fmt.Errorf("invalid sweep state %s: %w", path, err)
+	}
+	// Forget sweeps removed from the config
+	for name := range r.seen {
+		if !slices.ContainsFunc(sweeps, func(sw config.SweepConfig) bool { return sw.Name == name }) {
+			delete(r.seen, name)
+		}
+	}
+	return r, nil
+}
+
+// Run every sweep on its schedule until ctx is done
+func (r *Runner) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, j := range r.jobs {
+		wg.Go(func() { r.loop(ctx, j) })
+	}
+	wg.Wait()
+}
+
+func (r *Runner) loop(ctx context.Context, j job) {
+	for {
+		next := j.sched.Next(time.Now())
+		if next.IsZero() {
+			r.log.Warn("Sweep schedule never fires", zap.String("sweep", j.cfg.Name), zap.String("schedule", j.cfg.Schedule))
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Until(next)):
+		}
+		r.Sweep(ctx, j.cfg)
+	}
+}
+
+// Search once for sw and report its new results
+func (r *Runner) Sweep(ctx context.Context, sw config.SweepConfig) {
+	chats := sw.Chats
+	if len(chats) == 0 {
+		chats = []int64{0}
+	}
+
+	var found []model.Message
+	for _, chatID := range chats {
+		msgs, err := r.search.Search(ctx, sw.Query, chatID, sw.Limit)
+		if err != nil {
+			r.log.Warn("Sweep search failed", zap.String("sweep", sw.Name), zap.Int64("chat_id", chatID), zap.Error(err))
+			continue
+		}
+		found = append(found, msgs...)
+	}
+
+	fresh := r.record(sw.Name, found)
+	r.log.Info("Sweep finished", zap.String("sweep", sw.Name), zap.Int("results", len(found)), zap.Int("new", len(fresh)))
+	// Oldest first, so alerts arrive in the order the messages were posted
+	slices.SortStableFunc(fresh, func(a, b model.Message) int { return a.Date.Compare(b.Date) })
+	for _, msg := range fresh {
+		r.handle(ctx, sw, msg)
+	}
+	if len(fresh) > 0 {
+		if err := r.save(); err != nil {
+			r.log.Error("Failed to save sweep state", zap.Error(err))
+		}
+	}
+}
+
+// Remember the results of a sweep, returning those not seen before
+func (r *Runner) record(name string, found []model.Message) []model.Message {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	seen := r.seen[name]
+	var fresh []model.Message
+	for _, msg := range found {
+		key := fmt.Sprintf("%d:%d", msg.ChatID, msg.ID)
+		if slices.Contains(seen, key) {
+			continue
+		}
+		seen = append(seen, key)
+		fresh = append(fresh, msg)
+	}
+	if len(seen) > seenSize {
+		seen = seen[len(seen)-seenSize:]
+	}
+	r.seen[name] = seen
+	return fresh
+}
+
+func (r *Runner) save() error {
+	r.mux.Lock()
+	data, err := json.Marshal(r.seen)
+	r.mux.Unlock()
+	if err != nil {
+		return err
+	}
+	return atomicfile.Write(r.path, data)
+}
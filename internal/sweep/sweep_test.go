@@ -0,0 +1,93 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package sweep
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+type fakeSearcher struct {
+	results map[int64][]model.Message
+	chats   []int64
+}
+
+func (f *fakeSearcher) Search(ctx context.Context, text string, chatID int64, limit int) ([]model.Message, error) {
+	f.chats = append(f.chats, chatID)
+	return f.results[chatID], nil
+}
+
+func TestRunner_Sweep(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	search := &fakeSearcher{results: map[int64][]model.Message{
+		-1001: {{ID: 2, ChatID: -1001, Date: at.Add(time.Hour)}, {ID: 1, ChatID: -1001, Date: at}},
+		-1002: {{ID: 9, ChatID: -1002, Date: at.Add(time.Minute)}},
+	}}
+	sw := config.SweepConfig{Name: "gpus", Query: "rtx", Schedule: "@hourly", Chats: []int64{-1001, -1002}, Limit: 10}
+	path := filepath.Join(t.TempDir(), "sweeps.json")
+
+	var got []int
+	handle := func(ctx context.Context, s config.SweepConfig, msg model.Message) {
+		if s.Name != "gpus" {
+			t.Errorf("unexpected sweep %q", s.Name)
+		}
+		got = append(got, msg.ID)
+	}
+	r, err := New([]config.SweepConfig{sw}, search, path, handle, zap.NewNop())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	r.Sweep(context.Background(), sw)
+	if !slices.Equal(search.chats, []int64{-1001, -1002}) {
+		t.Errorf("expected each listed chat searched, got %v", search.chats)
+	}
+	if !slices.Equal(got, []int{1, 9, 2}) {
+		t.Errorf("expected every result, oldest first, got %v", got)
+	}
+
+	// A restarted runner only reports results it has not seen
+	search.results[-1001] = append([]model.Message{{ID: 3, ChatID: -1001, Date: at.Add(2 * time.Hour)}}, search.results[-1001]...)
+	got = nil
+	r, err = New([]config.SweepConfig{sw}, search, path, handle, zap.NewNop())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	r.Sweep(context.Background(), sw)
+	if !slices.Equal(got, []int{3}) {
+		t.Errorf("expected only the new result, got %v", got)
+	}
+
+	// Removed sweeps are forgotten
+	r, err = New(nil, search, path, handle, zap.NewNop())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if len(r.seen) != 0 {
+		t.Errorf("expected the state of removed sweeps dropped, got %v", r.seen)
+	}
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package telegram
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/gotd/td/tg"
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Stops a history walk once enough messages were read back
+var errCaughtUp = errors.New("caught up")
+
+// Read back up to limit messages of the last window from every resolved chat
+// once the chats are resolved, so a session replacing a crashed one does not
+// miss what was posted in between. Messages already seen are dropped by the
+// scout's deduplication. Call before Run.
+func (c *Client) CatchUp(window time.Duration, limit int) {
+	c.catchUpWindow, c.catchUpLimit = window, limit
+}
+
+// Send the recent history of the resolved chats down the message channel,
+// oldest first within each chat
+func (c *Client) catchUp(ctx context.Context, api *tg.Client) {
+	since := time.Now().Add(-c.catchUpWindow)
+	c.cacheMux.RLock()
+	chats := maps.Clone(c.peerCache)
+	c.cacheMux.RUnlock()
+
+	var read int
+	for i, id := range slices.Sorted(maps.Keys(chats)) {
+		info := chats[id]
+		if info.Peer == nil {
+			continue
+		}
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(historyPause):
+			}
+		}
+
+		var msgs []model.Message
+		err := c.walkHistory(ctx, api, id, info, since, func(msg model.Message) error {
+			msgs = append(msgs, msg)
+			if len(msgs) >= c.catchUpLimit {
+				return errCaughtUp
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, errCaughtUp) {
+			if ctx.Err() != nil {
+				return
+			}
+			c.log.Warn("Failed to catch up on chat history", zap.Int64("chat_id", id), zap.Error(err))
+		}
+		for _, msg := range slices.Backward(msgs) {
+			msg.Spare = c.cfg.IsSpare
+			select {
+			case c.msgChan <- msg:
+			case <-ctx.Done():
+				return
+			}
+		}
+		read += len(msgs)
+	}
+	c.log.Info("Caught up on recent history", zap.Int("chats", len(chats)), zap.Int("messages", read), zap.Duration("window", c.catchUpWindow))
+}
@@ -23,8 +23,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
-	"strconv"
+	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -38,10 +40,16 @@ import (
 	"github.com/gotd/td/tg"
 	"go.uber.org/zap"
 
+	"github.com/h3nc4/TelegramScout/internal/atomicfile"
+	"github.com/h3nc4/TelegramScout/internal/chatid"
 	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/logger"
 	"github.com/h3nc4/TelegramScout/internal/model"
 )
 
+// How often the connection is pinged
+const HeartbeatInterval = 30 * time.Second
+
 // Wrap MTProto client
 type Client struct {
 	client     *telegram.Client
@@ -55,13 +63,82 @@ type Client struct {
 	peerCache map[int64]peerInfo
 	cacheMux  sync.RWMutex
 
+	// Chats of watched message links that are not monitored, guarded by cacheMux
+	linkCache map[int64]peerInfo
+
+	// Watched users keyed by their config entry, guarded by cacheMux
+	userCache map[string]*tg.InputUser
+
+	// Monitor every chat unless excluded
+	allChats bool
+	excluded []chatid.Ref
+
+	// Media of recent messages, for downloads
+	media *mediaCache
+
+	// Raw updates are appended to it when set
+	recorder *Recorder
+
+	// Last resolution of configured usernames, for when they stop resolving
+	known *KnownChats
+
+	// History read back after resolving chats, zero window disables
+	catchUpWindow time.Duration
+	catchUpLimit  int
+
+	// Where alerts sent as the account go, resolved on first use
+	alertMux  sync.Mutex
+	alertPeer tg.InputPeerClass
+
 	stdin  io.Reader
 	stdout io.Writer
+
+	// Called after each resolution of the configured chats
+	onResolved func(ctx context.Context, report ResolveReport)
+
+	// Called with the outcome of each connection heartbeat
+	onHeartbeat func(err error)
+
+	// Called when a user goes online or offline
+	onPresence func(userID int64, online bool, at time.Time)
+
+	// Called with service notifications sent to the account
+	onServiceNotice func(text string, at time.Time)
+
+	// Called when a monitored group is upgraded to a supergroup
+	onMigrated func(ctx context.Context, m Migration)
+
+	// Registered by embedders around the update dispatcher
+	rawHooks     []RawUpdateHook
+	decodedHooks []UpdateHook
+	droppedHooks []UpdateHook
 }
 
 type peerInfo struct {
 	Title    string
 	Username string
+	Peer     tg.InputPeerClass // For API calls such as search, nil until known
+}
+
+// Summarize the outcome of resolving the configured chats
+type ResolveReport struct {
+	Resolved []ResolvedChat
+	Failed   []FailedChat
+}
+
+// Describe a configured chat that is being monitored
+type ResolvedChat struct {
+	Target string // As written in config
+	ID     int64
+	Title  string
+	Hint   string // Suggested config change, such as after a migration
+}
+
+// Describe a configured chat that could not be resolved
+type FailedChat struct {
+	Target string
+	Reason string
+	Hint   string // Suggested fix for the user
 }
 
 // Implement session.Storage for in-memory handling
@@ -144,44 +221,136 @@ func (a *terminalAuthenticator) SignUp(ctx context.Context) (auth.UserInfo, erro
 	return auth.UserInfo{}, errors.New("signup not supported in TelegramScout")
 }
 
+// Return the session file in the state directory, first moving there one
+// left in the working directory by versions that kept it there
+func sessionPath(cfg *config.Config, log *zap.Logger) (string, error) {
+	path, err := cfg.StatePath(cfg.SessionFile())
+	if err != nil {
+		return "", err
+	}
+	legacy, err := filepath.Abs(cfg.SessionFile())
+	if err != nil || legacy == path {
+		return path, nil
+	}
+	if abs, err := filepath.Abs(path); err == nil && abs == legacy {
+		return path, nil
+	}
+	if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+		return path, nil
+	}
+
+	data, err := os.ReadFile(legacy)
+	if errors.Is(err, fs.ErrNotExist) {
+		return path, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read old session file: %w", err)
+	}
+	// Copied rather than renamed, the state directory may be on another filesystem
+	if err := atomicfile.Write(path, data); err != nil {
+		return "", fmt.Errorf("failed to move session file to %s: %w", path, err)
+	}
+	if err := os.Remove(legacy); err != nil {
+		log.Warn("Failed to remove the old session file", zap.String("path", legacy), zap.Error(err))
+	}
+	log.Info("Moved session file to the state directory", zap.String("from", legacy), zap.String("to", path))
+	return path, nil
+}
+
 // Create new Telegram client instance
 func NewClient(cfg *config.Config, log *zap.Logger, msgChan chan<- model.Message) (*Client, error) {
 	var storage session.Storage
 	if cfg.Session != "" {
 		storage = &memorySession{data: []byte(cfg.Session)}
 	} else {
-		storage = &session.FileStorage{Path: "session.json"}
+		path, err := sessionPath(cfg, log)
+		if err != nil {
+			return nil, err
+		}
+		log.Info("Using session file", zap.String("path", path))
+		storage = &session.FileStorage{Path: path}
 	}
 
 	// Setup update dispatcher
 	d := tg.NewUpdateDispatcher()
 
-	opts := telegram.Options{
-		// Reduce log noise from the library
-		Logger:         log.WithOptions(zap.IncreaseLevel(zap.WarnLevel)),
-		SessionStorage: storage,
-		UpdateHandler:  d,
-	}
-
-	client := telegram.NewClient(cfg.AppID, cfg.AppHash, opts)
 	c := &Client{
-		client:     client,
 		log:        log,
 		cfg:        cfg,
 		msgChan:    msgChan,
 		dispatcher: d,
 		peerCache:  make(map[int64]peerInfo),
+		linkCache:  make(map[int64]peerInfo),
+		userCache:  make(map[string]*tg.InputUser),
+		allChats:   slices.Contains(cfg.Monitoring.Chats, config.AllChats),
+		media:      newMediaCache(mediaCacheSize),
 		stdin:      os.Stdin,
 		stdout:     os.Stdout,
 	}
+	for _, e := range cfg.Monitoring.ExcludeChats {
+		ref, err := chatid.Parse(e)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exclude_chats entry %q: %w", e, err)
+		}
+		c.excluded = append(c.excluded, ref)
+	}
+
+	c.client = telegram.NewClient(cfg.AppID, cfg.AppHash, telegram.Options{
+		// The library logs at the mtproto level, warn unless configured
+		Logger:         logger.For(log, logger.MTProto),
+		SessionStorage: storage,
+		UpdateHandler:  telegram.UpdateHandlerFunc(c.HandleUpdates),
+	})
 
 	// Register handlers
 	d.OnNewChannelMessage(c.handleNewChannelMessage)
 	d.OnNewMessage(c.handleNewMessage)
+	d.OnUserStatus(c.handleUserStatus)
+	d.OnServiceNotification(c.handleServiceNotification)
+	// The fallback is kept by value, the copy held by c needs it
+	c.dispatcher.OnFallback(c.handleDropped)
 
 	return c, nil
 }
 
+// Append every incoming update to r, for bug reports
+func (c *Client) RecordUpdates(r *Recorder) {
+	c.recorder = r
+}
+
+// Keep the last resolution of configured usernames in k, to find their chats
+// by ID when the usernames stop resolving
+func (c *Client) UseKnownChats(k *KnownChats) {
+	c.known = k
+}
+
+// Register a callback invoked after the configured chats are resolved
+func (c *Client) OnResolved(fn func(ctx context.Context, report ResolveReport)) {
+	c.onResolved = fn
+}
+
+// Register a callback invoked with the result of each periodic ping
+func (c *Client) OnHeartbeat(fn func(err error)) {
+	c.onHeartbeat = fn
+}
+
+// Register a callback invoked when a user the account can see goes online or offline
+func (c *Client) OnPresence(fn func(userID int64, online bool, at time.Time)) {
+	c.onPresence = fn
+}
+
+// Handle updates as if Telegram had sent them, without a connection. The
+// scouttest harness drives the pipeline through it.
+func (c *Client) HandleUpdates(ctx context.Context, updates tg.UpdatesClass) error {
+	if c.recorder != nil {
+		if err := c.recorder.Record(updates); err != nil {
+			c.log.Warn("Failed to record updates", zap.Error(err))
+		}
+	}
+	c.runUpdateHooks(ctx, updates)
+	return c.dispatcher.Handle(ctx, updates)
+}
+
 // Start client, authenticate, resolve peers, and listen for updates
 func (c *Client) Run(ctx context.Context) error {
 	return c.client.Run(ctx, func(ctx context.Context) error {
@@ -194,16 +363,56 @@ func (c *Client) Run(ctx context.Context) error {
 
 		// Resolve configured chats
 		c.log.Info("Resolving configured channels...")
-		if err := c.resolveMonitoringPeers(ctx); err != nil {
+		report, err := c.resolveMonitoringPeers(ctx)
+		if err != nil {
 			c.log.Error("Failed to resolve some peers", zap.Error(err))
 		}
+		if c.onResolved != nil {
+			c.onResolved(ctx, report)
+		}
+		if c.catchUpWindow > 0 {
+			go c.catchUp(ctx, c.client.API())
+		}
 
 		c.log.Info("Client is running and listening for updates...")
-		<-ctx.Done()
+		c.heartbeat(ctx)
 		return nil
 	})
 }
 
+// Ping the server periodically so a wedged connection shows up in health checks
+func (c *Client) heartbeat(ctx context.Context) {
+	beat := func(err error) {
+		if err != nil {
+			c.log.Warn("Heartbeat ping failed", zap.Error(err))
+		}
+		if c.onHeartbeat != nil {
+			c.onHeartbeat(err)
+		}
+	}
+	beat(nil)
+
+	ticker := time.NewTicker(HeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			pingCtx, cancel := context.WithTimeout(ctx, HeartbeatInterval/2)
+			err := c.client.Ping(pingCtx)
+			cancel()
+			if ctx.Err() != nil {
+				return
+			}
+			beat(err)
+		}
+	}
+}
+
+// Held while an account logs in interactively
+var authMux sync.Mutex
+
 func (c *Client) authenticate(ctx context.Context) error {
 	status, err := c.client.Auth().Status(ctx)
 	if err != nil {
@@ -211,7 +420,11 @@ func (c *Client) authenticate(ctx context.Context) error {
 	}
 
 	if !status.Authorized {
-		c.log.Info("Starting new authentication flow")
+		// The primary and spare accounts share the terminal, prompt for one at a time
+		authMux.Lock()
+		defer authMux.Unlock()
+
+		c.log.Info("Starting new authentication flow", zap.String("phone", c.cfg.Phone))
 		authenticator := &terminalAuthenticator{
 			phone:    c.cfg.Phone,
 			password: c.cfg.Password,
@@ -228,79 +441,133 @@ func (c *Client) authenticate(ctx context.Context) error {
 	return nil
 }
 
-func (c *Client) resolveMonitoringPeers(ctx context.Context) error {
+// Pair a parsed numeric chat reference with its config entry
+type wantedChat struct {
+	ref    chatid.Ref
+	target string
+}
+
+func (c *Client) resolveMonitoringPeers(ctx context.Context) (ResolveReport, error) {
+	var report ResolveReport
 	sender := message.NewSender(c.client.API())
 
-	// Store IDs to look for in dialogs
-	// Map: NormalizedID -> OriginalString
-	wantedIDs := make(map[int64]string)
+	// Numeric IDs are looked up in dialogs
+	var wanted []wantedChat
 
 	for _, target := range c.cfg.Monitoring.Chats {
-		// Check if it's a numeric ID
-		if id, ok := parseID(target); ok {
-			wantedIDs[id] = target
+		if target == config.AllChats {
+			continue
+		}
+		if phone, ok := chatid.ParsePhone(target); ok {
+			chat, err := c.resolvePhone(ctx, c.client.API(), target, phone)
+			if err != nil {
+				c.log.Warn("Could not resolve phone number", zap.String("chat", target), zap.Error(err))
+				report.Failed = append(report.Failed, FailedChat{Target: target, Reason: err.Error(), Hint: resolveHint(target)})
+				continue
+			}
+			report.Resolved = append(report.Resolved, chat)
+			continue
+		}
+		ref, err := chatid.Parse(target)
+		if err != nil {
+			c.log.Warn("Invalid chat reference", zap.String("chat", target), zap.Error(err))
+			report.Failed = append(report.Failed, FailedChat{Target: target, Reason: err.Error(), Hint: resolveHint(target)})
+			continue
+		}
+
+		if !ref.IsUsername() {
+			wanted = append(wanted, wantedChat{ref: ref, target: target})
 			continue
 		}
 
 		// If it's a username, resolve directly
-		if err := c.resolveUsername(ctx, sender, target); err != nil {
+		chat, err := c.resolveUsername(ctx, sender, target, ref.Username)
+		if err != nil {
 			c.log.Warn("Could not resolve chat username", zap.String("chat", target), zap.Error(err))
+			chat, failed := c.reresolve(ctx, c.client.API(), target, ref.Username, err)
+			if failed != nil {
+				report.Failed = append(report.Failed, *failed)
+			} else {
+				report.Resolved = append(report.Resolved, chat)
+			}
+			continue
 		}
+		report.Resolved = append(report.Resolved, chat)
 	}
 
 	// Scan dialogs for the collected numeric IDs
-	if len(wantedIDs) > 0 {
-		return c.scanDialogsForIDs(ctx, wantedIDs)
+	if len(wanted) > 0 {
+		if err := c.scanDialogsForIDs(ctx, wanted, &report); err != nil {
+			return report, err
+		}
 	}
-	return nil
+	return report, nil
 }
 
-func (c *Client) resolveUsername(ctx context.Context, sender *message.Sender, target string) error {
-	cleanTarget := strings.TrimPrefix(target, "@")
-	p, err := sender.Resolve(cleanTarget).AsInputPeer(ctx)
+func (c *Client) resolveUsername(ctx context.Context, sender *message.Sender, target, username string) (ResolvedChat, error) {
+	p, err := sender.Resolve(username).AsInputPeer(ctx)
 	if err != nil {
-		return err
+		return ResolvedChat{}, err
 	}
 
-	id := getPeerID(p)
+	id := chatid.BotAPI(getPeerID(p))
 	// Optimistically cache using the input username as title
-	c.updatePeerCache(id, cleanTarget, cleanTarget)
+	c.updatePeerCache(id, username, username, p)
+	c.rememberUsername(username, id, p)
 	c.log.Info("Resolved chat by username", zap.String("target", target), zap.Int64("id", id))
-	return nil
+	return ResolvedChat{Target: target, ID: id, Title: username}, nil
 }
 
-func (c *Client) scanDialogsForIDs(ctx context.Context, wantedIDs map[int64]string) error {
-	c.log.Info("Scanning dialogs to resolve chat IDs...", zap.Int("count", len(wantedIDs)))
+func (c *Client) scanDialogsForIDs(ctx context.Context, wanted []wantedChat, report *ResolveReport) error {
+	c.log.Info("Scanning dialogs to resolve chat IDs...", zap.Int("count", len(wanted)))
 
 	iter := query.GetDialogs(c.client.API()).Iter()
-	for iter.Next(ctx) {
+	for iter.Next(ctx) && len(wanted) > 0 {
 		d := iter.Value()
-		id := getPeerID(d.Peer)
+		kind, raw := getPeerID(d.Peer)
 
-		if originalTarget, found := wantedIDs[id]; found {
-			c.log.Info("Found chat by ID", zap.String("target", originalTarget), zap.Int64("id", id))
+		i := slices.IndexFunc(wanted, func(w wantedChat) bool { return w.ref.Matches(kind, raw) })
+		if i < 0 {
+			continue
+		}
+		target := wanted[i].target
+		wanted = slices.Delete(wanted, i, i+1)
 
-			title, username := getPeerInfoFromEntities(d.Peer, d.Entities)
-			if title == "" {
-				title = originalTarget
-			}
+		id := chatid.BotAPI(kind, raw)
+		c.log.Info("Found chat by ID", zap.String("target", target), zap.Int64("id", id), zap.String("strategy", "dialogs"))
 
-			c.updatePeerCache(id, title, username)
-			delete(wantedIDs, id)
+		title, username := getPeerInfoFromEntities(d.Peer, d.Entities)
+		if title == "" {
+			title = target
 		}
 
-		if len(wantedIDs) == 0 {
-			break
+		p, hint := d.Peer, ""
+		if chat, ok := d.Entities.Chat(raw); ok && kind == chatid.Group {
+			if to := migratedTo(chat); to != nil {
+				id, p, hint = c.resolvedMigration(target, id, title, to)
+			}
 		}
+		c.updatePeerCache(id, title, username, p)
+		report.Resolved = append(report.Resolved, ResolvedChat{Target: target, ID: id, Title: title, Hint: hint})
 	}
 
-	for _, t := range wantedIDs {
-		c.log.Warn("Could not find chat ID in recent dialogs (ensure you have joined the channel/group)", zap.String("target", t))
+	err := iter.Err()
+	if err == nil {
+		// Dialogs only list chats with recent activity
+		wanted = c.lookupIDs(ctx, c.client.API(), wanted, report)
 	}
-	return nil
+	for _, w := range wanted {
+		c.log.Warn("Could not find chat ID (ensure you have joined the channel/group)", zap.String("target", w.target))
+		report.Failed = append(report.Failed, FailedChat{Target: w.target, Reason: "not found in dialogs or by ID", Hint: resolveHint(w.target)})
+	}
+	return err
 }
 
 func (c *Client) handleNewChannelMessage(ctx context.Context, e tg.Entities, u *tg.UpdateNewChannelMessage) error {
+	if svc, ok := u.Message.(*tg.MessageService); ok {
+		c.handleMigration(ctx, svc, e)
+		return nil
+	}
 	msg, ok := u.Message.(*tg.Message)
 	if !ok {
 		return nil
@@ -309,40 +576,63 @@ func (c *Client) handleNewChannelMessage(ctx context.Context, e tg.Entities, u *
 }
 
 func (c *Client) handleNewMessage(ctx context.Context, e tg.Entities, u *tg.UpdateNewMessage) error {
+	if svc, ok := u.Message.(*tg.MessageService); ok {
+		c.handleMigration(ctx, svc, e)
+		return nil
+	}
 	msg, ok := u.Message.(*tg.Message)
 	if !ok {
 		return nil
 	}
+	c.serviceMessage(msg)
 	return c.emitMessage(ctx, msg, e)
 }
 
+// Report online and offline statuses, users hiding their last seen time report neither
+func (c *Client) handleUserStatus(ctx context.Context, e tg.Entities, u *tg.UpdateUserStatus) error {
+	if c.onPresence == nil {
+		return nil
+	}
+	switch s := u.Status.(type) {
+	case *tg.UserStatusOnline:
+		c.onPresence(u.UserID, true, time.Now())
+	case *tg.UserStatusOffline:
+		c.onPresence(u.UserID, false, time.Unix(int64(s.WasOnline), 0))
+	}
+	return nil
+}
+
 func (c *Client) emitMessage(ctx context.Context, msg *tg.Message, entities tg.Entities) error {
-	var chatID int64
+	var kind chatid.Kind
+	var rawID int64
 	var title, username string
 
 	// Handle different Peer types
 	switch p := msg.PeerID.(type) {
 	case *tg.PeerChannel:
-		chatID = p.ChannelID
-		if ch, ok := entities.Channels[chatID]; ok {
+		kind, rawID = chatid.Channel, p.ChannelID
+		if ch, ok := entities.Channels[rawID]; ok {
 			title = ch.Title
 			username = ch.Username
 		}
 	case *tg.PeerChat:
-		chatID = p.ChatID
-		if ch, ok := entities.Chats[chatID]; ok {
+		kind, rawID = chatid.Group, p.ChatID
+		if ch, ok := entities.Chats[rawID]; ok {
 			title = ch.Title
 		}
 	case *tg.PeerUser:
-		chatID = p.UserID
+		kind, rawID = chatid.User, p.UserID
 	}
 
-	// Only process messages from chats resolved.
+	// Key chats by their Bot API ID, the same form used in config
+	chatID := chatid.BotAPI(kind, rawID)
+
+	// Only process messages from chats resolved, or any chat with the wildcard
 	c.cacheMux.RLock()
 	info, allowed := c.peerCache[chatID]
 	c.cacheMux.RUnlock()
 
-	if !allowed {
+	if !allowed && !c.allChats {
 		// Ignore messages from non-monitored chats
 		return nil
 	}
@@ -355,13 +645,35 @@ func (c *Client) emitMessage(ctx context.Context, msg *tg.Message, entities tg.E
 		username = info.Username
 	}
 
-	// Construct Link
-	link := ""
-	if username != "" {
-		link = fmt.Sprintf("https://t.me/%s/%d", username, msg.ID)
-	} else {
-		// Private link format
-		link = fmt.Sprintf("https://t.me/c/%d/%d", chatID, msg.ID)
+	// Exclusions take precedence over any inclusion
+	if c.isExcluded(kind, rawID, username) {
+		return nil
+	}
+
+	// Chats only matched by the wildcard can be limited to mentions
+	parsed := parseEntities(msg, entities)
+	if !allowed && c.cfg.Monitoring.MentionsOnly && !msg.Mentioned && !hasMention(parsed) {
+		return nil
+	}
+
+	// Remember chats matched by the wildcard so they can be searched too
+	if info.Peer == nil {
+		if p := inputPeer(kind, rawID, entities); p != nil {
+			c.updatePeerCache(chatID, title, username, p)
+		}
+	}
+
+	ref := messageRef{
+		kind:     kind,
+		rawID:    rawID,
+		username: username,
+		topicID:  topicID(msg),
+		msgID:    msg.ID,
+	}
+
+	senderID, fromBot := sender(msg, entities)
+	if msg.Media != nil {
+		c.media.add(chatID, msg.ID, msg.Media)
 	}
 
 	c.msgChan <- model.Message{
@@ -369,9 +681,21 @@ func (c *Client) emitMessage(ctx context.Context, msg *tg.Message, entities tg.E
 		ChatID:    chatID,
 		ChatTitle: title,
 		Username:  username,
+		TopicID:   ref.topicID,
+		SenderID:  senderID,
+		FromBot:   fromBot,
+		Outgoing:  msg.Out,
+		Media:     mediaKind(msg),
+		File:      mediaFile(msg),
+		Forward:   forwardOrigin(msg, entities),
+		Entities:  parsed,
 		Text:      msg.Message,
 		Date:      time.Unix(int64(msg.Date), 0),
-		Link:      link,
+		Received:  time.Now(),
+		Link:      messageLink(c.cfg.Links.Style, ref),
+		AppLink:   appLink(c.cfg.Links.Style, ref),
+		Spare:     c.cfg.IsSpare,
+		TraceID:   logger.NewTraceID(),
 	}
 
 	return nil
@@ -379,41 +703,129 @@ func (c *Client) emitMessage(ctx context.Context, msg *tg.Message, entities tg.E
 
 // Helpers
 
-func (c *Client) updatePeerCache(id int64, title, username string) {
+// Return the Bot API ID of the message author and whether it is a bot
+func sender(msg *tg.Message, entities tg.Entities) (int64, bool) {
+	from := msg.FromID
+	// Incoming private messages omit the author, it is the peer itself
+	if from == nil && !msg.Out {
+		if p, ok := msg.PeerID.(*tg.PeerUser); ok {
+			from = p
+		}
+	}
+
+	switch f := from.(type) {
+	case *tg.PeerUser:
+		u, ok := entities.Users[f.UserID]
+		return chatid.BotAPI(chatid.User, f.UserID), ok && u.Bot
+	case *tg.PeerChannel:
+		// Posting on behalf of a channel
+		return chatid.BotAPI(chatid.Channel, f.ChannelID), false
+	case *tg.PeerChat:
+		return chatid.BotAPI(chatid.Group, f.ChatID), false
+	}
+	return 0, false
+}
+
+// Return where a forwarded message was originally posted, or nil
+func forwardOrigin(msg *tg.Message, entities tg.Entities) *model.Forward {
+	fwd, ok := msg.GetFwdFrom()
+	if !ok {
+		return nil
+	}
+
+	origin := &model.Forward{Title: fwd.FromName}
+	switch p := fwd.FromID.(type) {
+	case *tg.PeerChannel:
+		origin.ChatID = chatid.BotAPI(chatid.Channel, p.ChannelID)
+		if ch, ok := entities.Channels[p.ChannelID]; ok {
+			origin.Title, origin.Username = ch.Title, ch.Username
+		}
+	case *tg.PeerChat:
+		origin.ChatID = chatid.BotAPI(chatid.Group, p.ChatID)
+		if ch, ok := entities.Chats[p.ChatID]; ok {
+			origin.Title = ch.Title
+		}
+	case *tg.PeerUser:
+		origin.ChatID = chatid.BotAPI(chatid.User, p.UserID)
+		if u, ok := entities.Users[p.UserID]; ok {
+			origin.Title = strings.TrimSpace(u.FirstName + " " + u.LastName)
+			origin.Username = u.Username
+		}
+	}
+	if origin.ChatID == 0 && origin.Title == "" {
+		return nil
+	}
+	return origin
+}
+
+// Report whether the chat is listed in exclude_chats
+func (c *Client) isExcluded(kind chatid.Kind, rawID int64, username string) bool {
+	for _, ref := range c.excluded {
+		if ref.Matches(kind, rawID) || ref.MatchesUsername(username) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Client) updatePeerCache(id int64, title, username string, p tg.InputPeerClass) {
 	c.cacheMux.Lock()
 	defer c.cacheMux.Unlock()
 	c.peerCache[id] = peerInfo{
 		Title:    title,
 		Username: username,
+		Peer:     p,
 	}
 }
 
-func parseID(s string) (int64, bool) {
-	// Handle -100 prefix (Bot API Channel format)
-	if strings.HasPrefix(s, "-100") {
-		id, err := strconv.ParseInt(s[4:], 10, 64)
-		return id, err == nil
-	}
-	// Handle - prefix (Standard Chat format)
-	if strings.HasPrefix(s, "-") {
-		id, err := strconv.ParseInt(s[1:], 10, 64)
-		return id, err == nil
+// Suggest how to fix a chat entry that failed to resolve
+func resolveHint(target string) string {
+	ref, err := chatid.Parse(target)
+	switch {
+	case err != nil:
+		if _, ok := chatid.ParsePhone(target); ok {
+			return "the number must be in the account's contacts or let anyone find it by phone number"
+		}
+		return "use @username, -100<id> for channels and supergroups, -<id> for basic groups, a bare <id> or a +<phone>"
+	case ref.IsUsername():
+		return "check the username spelling; the chat may have been renamed or made private"
+	case ref.Kind == chatid.Channel:
+		return "join the channel or supergroup with the monitoring account"
+	case ref.Kind == chatid.Group:
+		return "join the group with the monitoring account; if it was upgraded to a supergroup use its new -100 ID"
+	default:
+		return "join the chat with the monitoring account, or use the Bot API form (-100… for channels, -… for groups)"
 	}
-	// Normal parsing
-	id, err := strconv.ParseInt(s, 10, 64)
-	return id, err == nil
 }
 
-func getPeerID(p tg.InputPeerClass) int64 {
+// Return the kind and MTProto ID of an input peer
+func getPeerID(p tg.InputPeerClass) (chatid.Kind, int64) {
 	switch t := p.(type) {
 	case *tg.InputPeerChannel:
-		return t.ChannelID
+		return chatid.Channel, t.ChannelID
 	case *tg.InputPeerChat:
-		return t.ChatID
+		return chatid.Group, t.ChatID
 	case *tg.InputPeerUser:
-		return t.UserID
+		return chatid.User, t.UserID
 	}
-	return 0
+	return chatid.Any, 0
+}
+
+// Build the input peer of a chat from the entities of an update, nil without its access hash
+func inputPeer(kind chatid.Kind, rawID int64, e tg.Entities) tg.InputPeerClass {
+	switch kind {
+	case chatid.Channel:
+		if ch, ok := e.Channels[rawID]; ok {
+			return &tg.InputPeerChannel{ChannelID: rawID, AccessHash: ch.AccessHash}
+		}
+	case chatid.Group:
+		return &tg.InputPeerChat{ChatID: rawID}
+	case chatid.User:
+		if u, ok := e.Users[rawID]; ok {
+			return &tg.InputPeerUser{UserID: rawID, AccessHash: u.AccessHash}
+		}
+	}
+	return nil
 }
 
 func getPeerInfoFromEntities(p tg.InputPeerClass, e peer.Entities) (string, string) {
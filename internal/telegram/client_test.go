@@ -20,14 +20,21 @@ package telegram
 
 import (
 	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gotd/td/tg"
+	"github.com/gotd/td/tgerr"
 	"go.uber.org/zap"
 
 	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/join"
 	"github.com/h3nc4/TelegramScout/internal/model"
+	"github.com/h3nc4/TelegramScout/internal/standing"
 )
 
 func TestNewClient(t *testing.T) {
@@ -52,17 +59,58 @@ func TestNewClient(t *testing.T) {
 }
 
 // Test message emission logic locally without full MTProto connection
+func TestSessionPath(t *testing.T) {
+	work, state := t.TempDir(), t.TempDir()
+	t.Chdir(work)
+	if err := os.WriteFile("session.json", []byte(`{"old":true}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{StateDir: state}
+
+	path, err := sessionPath(cfg, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != filepath.Join(state, "session.json") {
+		t.Errorf("unexpected session path %s", path)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != `{"old":true}` {
+		t.Errorf("expected the old session moved to the state directory, got %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(work, "session.json")); !os.IsNotExist(err) {
+		t.Errorf("expected the old session file removed, got %v", err)
+	}
+
+	// A session already in the state directory wins
+	if err := os.WriteFile("session.json", []byte(`{"stray":true}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sessionPath(cfg, zap.NewNop()); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != `{"old":true}` {
+		t.Errorf("expected the state directory session kept, got %q", data)
+	}
+
+	// The spare account has its own file
+	spare := &config.Config{StateDir: state, IsSpare: true}
+	if path, _ := sessionPath(spare, zap.NewNop()); filepath.Base(path) != "session-spare.json" {
+		t.Errorf("unexpected spare session path %s", path)
+	}
+}
+
 func TestEmitMessage(t *testing.T) {
 	msgChan := make(chan model.Message, 1)
 
 	// Pre-populate peerCache
 	cache := make(map[int64]peerInfo)
-	cache[999] = peerInfo{
+	cache[-1000000000999] = peerInfo{
 		Title:    "Test Channel",
 		Username: "testchan",
 	}
 
 	client := &Client{
+		cfg:       &config.Config{Links: config.LinksConfig{Style: config.LinkStyleWeb}},
 		msgChan:   msgChan,
 		peerCache: cache,
 	}
@@ -101,6 +149,9 @@ func TestEmitMessage(t *testing.T) {
 		if m.ChatTitle != "Test Channel" {
 			t.Errorf("expected title 'Test Channel', got %s", m.ChatTitle)
 		}
+		if m.ChatID != -1000000000999 {
+			t.Errorf("expected Bot API chat ID, got %d", m.ChatID)
+		}
 		if m.Link != "https://t.me/testchan/100" {
 			t.Errorf("unexpected link: %s", m.Link)
 		}
@@ -108,3 +159,234 @@ func TestEmitMessage(t *testing.T) {
 		t.Fatal("timeout waiting for message")
 	}
 }
+
+func TestEmitMessage_Exclusions(t *testing.T) {
+	msgChan := make(chan model.Message, 4)
+	cfg := &config.Config{Monitoring: config.MonitoringRules{
+		Chats:        []string{"*"},
+		ExcludeChats: []string{"-1000000000002", "@NoisyChan"},
+	}}
+	client, err := NewClient(cfg, zap.NewNop(), msgChan)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	entities := tg.Entities{Channels: map[int64]*tg.Channel{
+		1: {ID: 1, Title: "Wanted"},
+		2: {ID: 2, Title: "Excluded By ID"},
+		3: {ID: 3, Title: "Excluded By Username", Username: "noisychan"},
+	}}
+	for id := int64(1); id <= 3; id++ {
+		msg := &tg.Message{ID: 10, Message: "hi", PeerID: &tg.PeerChannel{ChannelID: id}}
+		if err := client.emitMessage(context.Background(), msg, entities); err != nil {
+			t.Fatalf("emitMessage failed: %v", err)
+		}
+	}
+
+	close(msgChan)
+	var titles []string
+	for m := range msgChan {
+		titles = append(titles, m.ChatTitle)
+	}
+	if len(titles) != 1 || titles[0] != "Wanted" {
+		t.Errorf("expected only the non-excluded chat, got %v", titles)
+	}
+
+	// Wildcard chats become searchable, excluded ones do not
+	if p, ok := client.peerCache[-1000000000001].Peer.(*tg.InputPeerChannel); !ok || p.ChannelID != 1 {
+		t.Errorf("expected the wanted chat's peer to be cached, got %+v", client.peerCache[-1000000000001])
+	}
+	if _, ok := client.peerCache[-1000000000002]; ok {
+		t.Error("expected the excluded chat not to be cached")
+	}
+}
+
+func TestSender(t *testing.T) {
+	entities := tg.Entities{Users: map[int64]*tg.User{
+		5: {ID: 5, Bot: true},
+		6: {ID: 6},
+	}}
+
+	tests := []struct {
+		name    string
+		msg     *tg.Message
+		wantID  int64
+		wantBot bool
+	}{
+		{"Bot In Group", &tg.Message{FromID: &tg.PeerUser{UserID: 5}, PeerID: &tg.PeerChat{ChatID: 9}}, 5, true},
+		{"User In Channel", &tg.Message{FromID: &tg.PeerUser{UserID: 6}, PeerID: &tg.PeerChannel{ChannelID: 9}}, 6, false},
+		{"Private Incoming", &tg.Message{PeerID: &tg.PeerUser{UserID: 5}}, 5, true},
+		{"Private Outgoing", &tg.Message{Out: true, PeerID: &tg.PeerUser{UserID: 5}}, 0, false},
+		{"Channel Post", &tg.Message{PeerID: &tg.PeerChannel{ChannelID: 9}}, 0, false},
+		{"As Channel", &tg.Message{FromID: &tg.PeerChannel{ChannelID: 9}, PeerID: &tg.PeerChannel{ChannelID: 8}}, -1000000000009, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, bot := sender(tt.msg, entities)
+			if id != tt.wantID || bot != tt.wantBot {
+				t.Errorf("sender() = %d, %t, want %d, %t", id, bot, tt.wantID, tt.wantBot)
+			}
+		})
+	}
+}
+
+func TestForwardOrigin(t *testing.T) {
+	entities := tg.Entities{Channels: map[int64]*tg.Channel{
+		7: {ID: 7, Title: "Source", Username: "sourcechan"},
+	}}
+
+	channel := &tg.Message{}
+	channel.SetFwdFrom(tg.MessageFwdHeader{FromID: &tg.PeerChannel{ChannelID: 7}})
+	got := forwardOrigin(channel, entities)
+	if got == nil || got.ChatID != -1000000000007 || got.Title != "Source" || got.Username != "sourcechan" {
+		t.Errorf("unexpected channel origin %+v", got)
+	}
+
+	hidden := &tg.Message{}
+	hidden.SetFwdFrom(tg.MessageFwdHeader{FromName: "Anonymous"})
+	if got := forwardOrigin(hidden, entities); got == nil || got.ChatID != 0 || got.Title != "Anonymous" {
+		t.Errorf("unexpected hidden origin %+v", got)
+	}
+
+	if got := forwardOrigin(&tg.Message{}, entities); got != nil {
+		t.Errorf("expected nil origin for original messages, got %+v", got)
+	}
+}
+
+func TestParseEntities(t *testing.T) {
+	msg := &tg.Message{
+		Message: "🚀 hi @Brand and Ana, #Launch $BTC not bold",
+		Entities: []tg.MessageEntityClass{
+			&tg.MessageEntityMention{Offset: 6, Length: 6},
+			&tg.MessageEntityMentionName{Offset: 17, Length: 3, UserID: 5},
+			&tg.MessageEntityMentionName{Offset: 17, Length: 3, UserID: 6},
+			&tg.MessageEntityHashtag{Offset: 22, Length: 7},
+			&tg.MessageEntityCashtag{Offset: 30, Length: 4},
+			&tg.MessageEntityBold{Offset: 39, Length: 4},
+		},
+	}
+	entities := tg.Entities{Users: map[int64]*tg.User{5: {ID: 5, Username: "ana_l"}}}
+
+	want := []struct{ kind, value, text string }{
+		{model.EntityMention, "brand", "@Brand"},
+		{model.EntityMention, "ana_l", "Ana"},
+		{model.EntityHashtag, "launch", "#Launch"},
+		{model.EntityCashtag, "btc", "$BTC"},
+	}
+	got := parseEntities(msg, entities)
+	if len(got) != len(want) {
+		t.Fatalf("expected %d entities, got %+v", len(want), got)
+	}
+	for i, w := range want {
+		e := got[i]
+		if e.Kind != w.kind || e.Value != w.value || msg.Message[e.Start:e.End] != w.text {
+			t.Errorf("entity %d = %+v (%q), want %s %q at %q", i, e, msg.Message[e.Start:e.End], w.kind, w.value, w.text)
+		}
+	}
+}
+
+func TestEmitMessage_MentionsOnly(t *testing.T) {
+	msgChan := make(chan model.Message, 4)
+	cfg := &config.Config{Monitoring: config.MonitoringRules{
+		Chats:        []string{"*", "-1000000000002"},
+		MentionsOnly: true,
+	}}
+	client, err := NewClient(cfg, zap.NewNop(), msgChan)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	client.updatePeerCache(-1000000000002, "Listed", "", nil)
+
+	mention := []tg.MessageEntityClass{&tg.MessageEntityMention{Offset: 0, Length: 6}}
+	for _, msg := range []*tg.Message{
+		{ID: 1, Message: "no mention", PeerID: &tg.PeerChannel{ChannelID: 1}},
+		{ID: 2, Message: "@brand", PeerID: &tg.PeerChannel{ChannelID: 1}, Entities: mention},
+		{ID: 3, Message: "listed chats send everything", PeerID: &tg.PeerChannel{ChannelID: 2}},
+	} {
+		if err := client.emitMessage(context.Background(), msg, tg.Entities{}); err != nil {
+			t.Fatalf("emitMessage failed: %v", err)
+		}
+	}
+
+	close(msgChan)
+	var ids []int
+	for m := range msgChan {
+		ids = append(ids, m.ID)
+	}
+	if len(ids) != 2 || ids[0] != 2 || ids[1] != 3 {
+		t.Errorf("expected the mention and the listed chat's message, got %v", ids)
+	}
+}
+
+func TestResolveHint(t *testing.T) {
+	tests := map[string]string{
+		"@somechannel":   "username",
+		"-1001803446893": "channel or supergroup",
+		"-4567":          "join the group",
+		"1710595474":     "Bot API form",
+		"+5511987654321": "contacts",
+	}
+	for target, want := range tests {
+		if hint := resolveHint(target); !strings.Contains(hint, want) {
+			t.Errorf("hint for %q = %q, expected it to mention %q", target, hint, want)
+		}
+	}
+}
+
+func TestJoinResult(t *testing.T) {
+	if status, err := joinResult(nil); status != join.Joined || err != nil {
+		t.Errorf("joinResult(nil) = %q, %v", status, err)
+	}
+	if status, _ := joinResult(tgerr.New(400, "USER_ALREADY_PARTICIPANT")); status != join.Member {
+		t.Errorf("expected an existing membership reported as %q, got %q", join.Member, status)
+	}
+	if status, _ := joinResult(tgerr.New(400, "INVITE_REQUEST_SENT")); status != join.Requested {
+		t.Errorf("expected a join request reported as %q, got %q", join.Requested, status)
+	}
+
+	var flood *join.FloodWaitError
+	if _, err := joinResult(tgerr.New(420, "FLOOD_WAIT_30")); !errors.As(err, &flood) || flood.Wait != 30*time.Second {
+		t.Errorf("expected a 30s flood wait, got %v", err)
+	}
+	if _, err := joinResult(tgerr.New(400, "CHANNELS_TOO_MUCH")); !errors.Is(err, join.ErrTooManyChannels) {
+		t.Errorf("expected ErrTooManyChannels, got %v", err)
+	}
+	if _, err := joinResult(tgerr.New(400, "INVITE_HASH_EXPIRED")); !errors.Is(err, join.ErrRejected) {
+		t.Errorf("expected an expired invite rejected, got %v", err)
+	}
+	if _, err := joinResult(errors.New("connection reset")); err == nil || errors.Is(err, join.ErrRejected) {
+		t.Errorf("expected a network error left retryable, got %v", err)
+	}
+}
+
+func TestFreezeState(t *testing.T) {
+	config := &tg.JSONObject{Value: []tg.JSONObjectValue{
+		{Key: "freeze_since_date", Value: &tg.JSONNumber{Value: 1767225600}},
+		{Key: "freeze_until_date", Value: &tg.JSONNumber{Value: 1769904000}},
+		{Key: "freeze_appeal_url", Value: &tg.JSONString{Value: "https://t.me/spambot"}},
+	}}
+	var st standing.Status
+	freezeState(config, &st)
+	if !st.Frozen || st.FrozenUntil.Unix() != 1769904000 || st.AppealURL != "https://t.me/spambot" {
+		t.Errorf("unexpected freeze state %+v", st)
+	}
+
+	st = standing.Status{}
+	freezeState(&tg.JSONObject{Value: []tg.JSONObjectValue{{Key: "freeze_appeal_url", Value: &tg.JSONString{Value: "https://t.me/spambot"}}}}, &st)
+	if st.Frozen || st.AppealURL != "" {
+		t.Errorf("expected an appeal URL alone ignored, got %+v", st)
+	}
+}
+
+func TestSpamLimited(t *testing.T) {
+	tests := map[string]bool{
+		"Good news, no limits are currently applied to your account. You’re free as a bird!": false,
+		"I’m afraid some Telegram users found your messages annoying":                        true,
+		"": false,
+	}
+	for answer, want := range tests {
+		if got := spamLimited(answer); got != want {
+			t.Errorf("spamLimited(%q) = %v, want %v", answer, got, want)
+		}
+	}
+}
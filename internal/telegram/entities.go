@@ -0,0 +1,95 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package telegram
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/gotd/td/tg"
+
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Return the mentions, hashtags and cashtags of the message. Mentions by
+// name, shown as a link without the username, are kept when the user's
+// username is known.
+func parseEntities(msg *tg.Message, entities tg.Entities) []model.Entity {
+	var out []model.Entity
+	for _, e := range msg.Entities {
+		var kind, value string
+		switch m := e.(type) {
+		case *tg.MessageEntityMention:
+			kind = model.EntityMention
+		case *tg.MessageEntityMentionName:
+			u, ok := entities.Users[m.UserID]
+			if !ok || u.Username == "" {
+				continue
+			}
+			kind, value = model.EntityMention, u.Username
+		case *tg.MessageEntityHashtag:
+			kind = model.EntityHashtag
+		case *tg.MessageEntityCashtag:
+			kind = model.EntityCashtag
+		default:
+			continue
+		}
+
+		start, end, ok := utf16Span(msg.Message, e.GetOffset(), e.GetLength())
+		if !ok {
+			continue
+		}
+		if value == "" {
+			// The entity text itself, after its '@', '#' or '$'
+			value = msg.Message[start+1 : end]
+		}
+		out = append(out, model.Entity{Kind: kind, Value: strings.ToLower(value), Start: start, End: end})
+	}
+	return out
+}
+
+// Report whether any of the entities is a mention
+func hasMention(entities []model.Entity) bool {
+	return slices.ContainsFunc(entities, func(e model.Entity) bool { return e.Kind == model.EntityMention })
+}
+
+// Convert an entity's UTF-16 offset and length to byte offsets in text
+func utf16Span(text string, offset, length int) (int, int, bool) {
+	start, end := -1, -1
+	units := 0
+	for i, r := range text {
+		if units == offset {
+			start = i
+		}
+		if units == offset+length {
+			end = i
+			break
+		}
+		units += utf16.RuneLen(r)
+	}
+	if units == offset+length && end < 0 {
+		end = len(text)
+	}
+	// Entities always span at least their leading character
+	if start < 0 || end <= start {
+		return 0, 0, false
+	}
+	return start, end, true
+}
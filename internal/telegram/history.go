@@ -0,0 +1,145 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package telegram
+
+import (
+	"context"
+	"time"
+
+	"github.com/gotd/td/tg"
+	"github.com/gotd/td/tgerr"
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/chatid"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Messages requested per history page, the most Telegram returns
+const historyPage = 100
+
+// Pause between history pages, keeping long walks clear of flood waits
+const historyPause = time.Second
+
+// Longest flood wait sat out before a history walk gives up
+const maxHistoryWait = 10 * time.Minute
+
+// Walk the history of a chat, given as @username or ID, from the newest
+// message back to since, calling fn for each message until it returns an error
+func (c *Client) History(ctx context.Context, target string, since time.Time, fn func(model.Message) error) error {
+	ref, err := chatid.Parse(target)
+	if err != nil {
+		return err
+	}
+	id, info, err := c.linkPeer(ctx, ref)
+	if err != nil {
+		return err
+	}
+	return c.walkHistory(ctx, c.client.API(), id, info, since, fn)
+}
+
+func (c *Client) walkHistory(ctx context.Context, api *tg.Client, id int64, info peerInfo, since time.Time, fn func(model.Message) error) error {
+	offsetID := 0
+	for {
+		msgs, entities, err := c.historyPage(ctx, api, info.Peer, offsetID)
+		if err != nil {
+			return err
+		}
+		for _, m := range msgs {
+			offsetID = m.GetID()
+			msg, ok := m.(*tg.Message)
+			if !ok {
+				continue
+			}
+			if time.Unix(int64(msg.Date), 0).Before(since) {
+				return nil
+			}
+			out := c.historyMessage(id, info, msg)
+			out.SenderID, out.FromBot = sender(msg, entities)
+			out.Outgoing = msg.Out
+			out.Media = mediaKind(msg)
+			out.File = mediaFile(msg)
+			out.Forward = forwardOrigin(msg, entities)
+			out.Entities = parseEntities(msg, entities)
+			if err := fn(out); err != nil {
+				return err
+			}
+		}
+		if len(msgs) < historyPage {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(historyPause):
+		}
+	}
+}
+
+// Fetch the page of history before offsetID, sitting out flood waits
+func (c *Client) historyPage(ctx context.Context, api *tg.Client, p tg.InputPeerClass, offsetID int) ([]tg.MessageClass, tg.Entities, error) {
+	for {
+		res, err := api.MessagesGetHistory(ctx, &tg.MessagesGetHistoryRequest{
+			Peer:     p,
+			OffsetID: offsetID,
+			Limit:    historyPage,
+		})
+		if err == nil {
+			modified, ok := res.AsModified()
+			if !ok {
+				return nil, tg.Entities{}, nil
+			}
+			return modified.GetMessages(), historyEntities(modified.GetUsers(), modified.GetChats()), nil
+		}
+
+		wait, ok := tgerr.AsFloodWait(err)
+		if !ok || wait > maxHistoryWait {
+			return nil, tg.Entities{}, err
+		}
+		c.log.Warn("Flood wait while reading history, pausing", zap.Duration("wait", wait))
+		select {
+		case <-ctx.Done():
+			return nil, tg.Entities{}, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// Index the users and chats returned alongside a page of messages
+func historyEntities(users []tg.UserClass, chats []tg.ChatClass) tg.Entities {
+	e := tg.Entities{
+		Users:    make(map[int64]*tg.User),
+		Chats:    make(map[int64]*tg.Chat),
+		Channels: make(map[int64]*tg.Channel),
+	}
+	for _, u := range users {
+		if user, ok := u.(*tg.User); ok {
+			e.Users[user.ID] = user
+		}
+	}
+	for _, ch := range chats {
+		switch chat := ch.(type) {
+		case *tg.Chat:
+			e.Chats[chat.ID] = chat
+		case *tg.Channel:
+			e.Channels[chat.ID] = chat
+		}
+	}
+	return e
+}
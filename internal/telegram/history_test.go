@@ -0,0 +1,110 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package telegram
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gotd/td/tg"
+	"github.com/gotd/td/tgerr"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+func TestWalkHistory(t *testing.T) {
+	c, mock := newResolveClient(t)
+	c.cfg = &config.Config{}
+	api := tg.NewClient(mock)
+	peer := &tg.InputPeerChannel{ChannelID: 1234, AccessHash: 5}
+	info := peerInfo{Title: "Deals", Username: "deals", Peer: peer}
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	date := func(days int) int { return int(since.AddDate(0, 0, days).Unix()) }
+
+	// A flood wait is sat out before the page is fetched again
+	req := &tg.MessagesGetHistoryRequest{Peer: peer, Limit: historyPage}
+	mock.ExpectCall(req).ThenRPCErr(&tgerr.Error{Code: 420, Type: "FLOOD_WAIT", Argument: 0})
+	mock.ExpectCall(req).ThenResult(&tg.MessagesChannelMessages{
+		Messages: []tg.MessageClass{
+			&tg.Message{ID: 12, PeerID: &tg.PeerChannel{ChannelID: 1234}, FromID: &tg.PeerUser{UserID: 7}, Message: "rtx 5090 #deal", Date: date(3),
+				Entities: []tg.MessageEntityClass{&tg.MessageEntityHashtag{Offset: 9, Length: 5}}},
+			&tg.MessageService{ID: 11, PeerID: &tg.PeerChannel{ChannelID: 1234}, Date: date(2), Action: &tg.MessageActionPinMessage{}},
+			&tg.Message{ID: 10, PeerID: &tg.PeerChannel{ChannelID: 1234}, Message: "hello", Date: date(1)},
+			&tg.Message{ID: 9, PeerID: &tg.PeerChannel{ChannelID: 1234}, Message: "too old", Date: date(-1)},
+		},
+		Users: []tg.UserClass{&tg.User{ID: 7, Bot: true}},
+	})
+
+	var got []model.Message
+	err := c.walkHistory(context.Background(), api, -1001234, info, since, func(msg model.Message) error {
+		got = append(got, msg)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("walkHistory() error = %v", err)
+	}
+	if len(got) != 2 || got[0].ID != 12 || got[1].ID != 10 {
+		t.Fatalf("expected messages 12 and 10, got %+v", got)
+	}
+	first := got[0]
+	if first.ChatID != -1001234 || first.Link != "https://t.me/deals/12" {
+		t.Errorf("unexpected chat or link: %d %s", first.ChatID, first.Link)
+	}
+	if first.SenderID != 7 || !first.FromBot {
+		t.Errorf("expected the bot sender, got %d %v", first.SenderID, first.FromBot)
+	}
+	if len(first.Entities) != 1 || first.Entities[0].Value != "deal" {
+		t.Errorf("expected the hashtag entity, got %+v", first.Entities)
+	}
+}
+
+func TestCatchUp(t *testing.T) {
+	c, mock := newResolveClient(t)
+	msgChan := make(chan model.Message, 10)
+	c.cfg, c.msgChan = &config.Config{}, msgChan
+	c.CatchUp(time.Hour, 2)
+	peer := &tg.InputPeerChannel{ChannelID: 1234, AccessHash: 5}
+	c.updatePeerCache(-1001234, "Deals", "deals", peer)
+	c.updatePeerCache(-4567, "Unreachable", "", nil)
+	now := int(time.Now().Unix())
+
+	// Reading stops at the limit, older messages are left alone
+	mock.ExpectCall(&tg.MessagesGetHistoryRequest{Peer: peer, Limit: historyPage}).ThenResult(&tg.MessagesChannelMessages{
+		Messages: []tg.MessageClass{
+			&tg.Message{ID: 12, PeerID: &tg.PeerChannel{ChannelID: 1234}, Message: "newest", Date: now},
+			&tg.Message{ID: 11, PeerID: &tg.PeerChannel{ChannelID: 1234}, Message: "mine", Date: now - 60, Out: true},
+			&tg.Message{ID: 10, PeerID: &tg.PeerChannel{ChannelID: 1234}, Message: "over the limit", Date: now - 120},
+		},
+	})
+	c.catchUp(context.Background(), tg.NewClient(mock))
+	close(msgChan)
+
+	var got []model.Message
+	for msg := range msgChan {
+		got = append(got, msg)
+	}
+	if len(got) != 2 || got[0].ID != 11 || got[1].ID != 12 {
+		t.Fatalf("expected messages 11 and 12 oldest first, got %+v", got)
+	}
+	if !got[0].Outgoing || !got[1].Received.IsZero() {
+		t.Errorf("expected history flagged as outgoing and not live, got %+v", got)
+	}
+}
@@ -0,0 +1,202 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package telegram
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/h3nc4/TelegramScout/internal/mentions"
+	"github.com/h3nc4/TelegramScout/internal/model"
+	"github.com/h3nc4/TelegramScout/internal/standing"
+)
+
+// Returned while no client session is running
+var ErrNotConnected = errors.New("telegram client not connected")
+
+// Give long-lived components access to whichever client session is current.
+// With a hot spare, the first attached client that is still running is current.
+type Holder struct {
+	mux     sync.RWMutex
+	clients []*Client
+}
+
+// Make c the current client, or the next one when another is attached
+func (h *Holder) Attach(c *Client) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	if !slices.Contains(h.clients, c) {
+		h.clients = append(h.clients, c)
+	}
+}
+
+// Forget c, handing over to the next attached client
+func (h *Holder) Detach(c *Client) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	h.clients = slices.DeleteFunc(h.clients, func(other *Client) bool { return other == c })
+}
+
+func (h *Holder) current() (*Client, error) {
+	h.mux.RLock()
+	defer h.mux.RUnlock()
+	if len(h.clients) == 0 {
+		return nil, ErrNotConnected
+	}
+	return h.clients[0], nil
+}
+
+// Stream the media of a recently received message through the client that received it
+func (h *Holder) Download(ctx context.Context, chatID int64, msgID int, w io.Writer) error {
+	h.mux.RLock()
+	clients := slices.Clone(h.clients)
+	h.mux.RUnlock()
+	if len(clients) == 0 {
+		return ErrNotConnected
+	}
+
+	var err error
+	for _, c := range clients {
+		if err = c.Download(ctx, chatID, msgID, w); !errors.Is(err, ErrMediaUnavailable) {
+			return err
+		}
+	}
+	return err
+}
+
+// Look up a referenced chat through the current client
+func (h *Holder) ResolveChat(ctx context.Context, ref mentions.Ref) (mentions.Info, error) {
+	c, err := h.current()
+	if err != nil {
+		return mentions.Info{}, err
+	}
+	return c.ResolveChat(ctx, ref)
+}
+
+// List the chats of a folder link through the current client
+func (h *Holder) ChatFolder(ctx context.Context, slug string) ([]mentions.Info, error) {
+	c, err := h.current()
+	if err != nil {
+		return nil, err
+	}
+	return c.ChatFolder(ctx, slug)
+}
+
+// Post an alert as the account through the current client
+func (h *Holder) SendAlert(ctx context.Context, text string) error {
+	c, err := h.current()
+	if err != nil {
+		return err
+	}
+	return c.SendAlert(ctx, text)
+}
+
+// Post plain text to a chat as the account through the current client
+func (h *Holder) Post(ctx context.Context, to, text string) error {
+	c, err := h.current()
+	if err != nil {
+		return err
+	}
+	return c.Post(ctx, to, text)
+}
+
+// Copy a monitored message into another chat through the current client
+func (h *Holder) Mirror(ctx context.Context, chatID int64, msgID int, to string) error {
+	c, err := h.current()
+	if err != nil {
+		return err
+	}
+	return c.Mirror(ctx, chatID, msgID, to)
+}
+
+// Forward a monitored message through the current client, to Saved Messages when to is empty
+func (h *Holder) Forward(ctx context.Context, chatID int64, msgID int, to string) error {
+	c, err := h.current()
+	if err != nil {
+		return err
+	}
+	return c.Forward(ctx, chatID, msgID, to)
+}
+
+// React to a monitored message through the current client
+func (h *Holder) React(ctx context.Context, chatID int64, msgID int, emoji string) error {
+	c, err := h.current()
+	if err != nil {
+		return err
+	}
+	return c.React(ctx, chatID, msgID, emoji)
+}
+
+// Search the history of the monitored chats through the current client
+func (h *Holder) Search(ctx context.Context, text string, chatID int64, limit int) ([]model.Message, error) {
+	c, err := h.current()
+	if err != nil {
+		return nil, err
+	}
+	return c.Search(ctx, text, chatID, limit)
+}
+
+// Walk the history of a chat through the current client
+func (h *Holder) History(ctx context.Context, target string, since time.Time, fn func(model.Message) error) error {
+	c, err := h.current()
+	if err != nil {
+		return err
+	}
+	return c.History(ctx, target, since, fn)
+}
+
+// Fetch a message by link through the current client
+func (h *Holder) Message(ctx context.Context, link string) (model.Message, bool, error) {
+	c, err := h.current()
+	if err != nil {
+		return model.Message{}, false, err
+	}
+	return c.Message(ctx, link)
+}
+
+// Fetch a user's profile through the current client
+func (h *Holder) Profile(ctx context.Context, target string) (model.Profile, error) {
+	c, err := h.current()
+	if err != nil {
+		return model.Profile{}, err
+	}
+	return c.Profile(ctx, target)
+}
+
+// Join a chat through the current client
+func (h *Holder) Join(ctx context.Context, link string) (string, error) {
+	c, err := h.current()
+	if err != nil {
+		return "", err
+	}
+	return c.Join(ctx, link)
+}
+
+// Fetch the account's standing through the current client
+func (h *Holder) AccountStatus(ctx context.Context, askSpamBot bool) (standing.Status, error) {
+	c, err := h.current()
+	if err != nil {
+		return standing.Status{}, err
+	}
+	return c.AccountStatus(ctx, askSpamBot)
+}
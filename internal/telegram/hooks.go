@@ -0,0 +1,96 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package telegram
+
+import (
+	"context"
+
+	"github.com/gotd/td/tg"
+)
+
+// Called with a batch of updates as Telegram sent it
+type RawUpdateHook func(ctx context.Context, updates tg.UpdatesClass)
+
+// Called with a single update and the users and chats sent along with it
+type UpdateHook func(ctx context.Context, e tg.Entities, update tg.UpdateClass)
+
+// Register fn to see every batch of updates before it is dispatched. Short
+// batches such as tg.UpdateShortMessage are never split into updates, so this
+// is the only hook that sees them. Like the other update hooks, several may
+// be registered, they run in order on the goroutine reading updates and must
+// be registered before Run.
+func (c *Client) OnRawUpdates(fn RawUpdateHook) {
+	c.rawHooks = append(c.rawHooks, fn)
+}
+
+// Register fn to see every update a batch decodes to, before the client
+// handles it
+func (c *Client) OnUpdate(fn UpdateHook) {
+	c.decodedHooks = append(c.decodedHooks, fn)
+}
+
+// Register fn to see the updates of types the client does not handle, such
+// as edits or reactions, which are otherwise dropped
+func (c *Client) OnDroppedUpdate(fn UpdateHook) {
+	c.droppedHooks = append(c.droppedHooks, fn)
+}
+
+func (c *Client) runUpdateHooks(ctx context.Context, updates tg.UpdatesClass) {
+	for _, fn := range c.rawHooks {
+		fn(ctx, updates)
+	}
+	if len(c.decodedHooks) == 0 {
+		return
+	}
+	e, list := decodeUpdates(updates)
+	for _, update := range list {
+		for _, fn := range c.decodedHooks {
+			fn(ctx, e, update)
+		}
+	}
+}
+
+func (c *Client) handleDropped(ctx context.Context, e tg.Entities, update tg.UpdateClass) error {
+	for _, fn := range c.droppedHooks {
+		fn(ctx, e, update)
+	}
+	return nil
+}
+
+// Split a batch into its updates and entities, as tg.UpdateDispatcher does
+func decodeUpdates(updates tg.UpdatesClass) (tg.Entities, []tg.UpdateClass) {
+	var e tg.Entities
+	switch u := updates.(type) {
+	case *tg.Updates:
+		e.Users = u.MapUsers().NotEmptyToMap()
+		chats := u.MapChats()
+		e.Chats, e.Channels = chats.ChatToMap(), chats.ChannelToMap()
+		return e, u.Updates
+	case *tg.UpdatesCombined:
+		e.Users = u.MapUsers().NotEmptyToMap()
+		chats := u.MapChats()
+		e.Chats, e.Channels = chats.ChatToMap(), chats.ChannelToMap()
+		return e, u.Updates
+	case *tg.UpdateShort:
+		e.Short = true
+		e.Users, e.Chats, e.Channels = map[int64]*tg.User{}, map[int64]*tg.Chat{}, map[int64]*tg.Channel{}
+		return e, []tg.UpdateClass{u.Update}
+	}
+	return e, nil
+}
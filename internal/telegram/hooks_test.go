@@ -0,0 +1,73 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package telegram
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gotd/td/tg"
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+func TestUpdateHooks(t *testing.T) {
+	c, err := NewClient(&config.Config{Session: "hooks"}, zap.NewNop(), make(chan model.Message, 10))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	var raw []tg.UpdatesClass
+	var decoded, dropped []tg.UpdateClass
+	var channels []string
+	c.OnRawUpdates(func(_ context.Context, u tg.UpdatesClass) { raw = append(raw, u) })
+	c.OnUpdate(func(_ context.Context, e tg.Entities, u tg.UpdateClass) {
+		decoded = append(decoded, u)
+		for _, ch := range e.Channels {
+			channels = append(channels, ch.Title)
+		}
+	})
+	c.OnDroppedUpdate(func(_ context.Context, _ tg.Entities, u tg.UpdateClass) { dropped = append(dropped, u) })
+
+	ctx := context.Background()
+	msg := &tg.Message{ID: 1, PeerID: &tg.PeerChannel{ChannelID: 1001}, Message: "hello"}
+	reaction := &tg.UpdateMessageReactions{Peer: &tg.PeerChannel{ChannelID: 1001}, MsgID: 1}
+	err = c.HandleUpdates(ctx, &tg.Updates{
+		Updates: []tg.UpdateClass{&tg.UpdateNewChannelMessage{Message: msg}, reaction},
+		Chats:   []tg.ChatClass{&tg.Channel{ID: 1001, Title: "Deals"}},
+	})
+	if err != nil {
+		t.Fatalf("failed to handle updates: %v", err)
+	}
+	short := &tg.UpdateShortMessage{ID: 2, UserID: 7, Message: "hi"}
+	if err := c.HandleUpdates(ctx, short); err != nil {
+		t.Fatalf("failed to handle updates: %v", err)
+	}
+
+	if len(raw) != 2 || raw[1] != short {
+		t.Errorf("expected both batches seen raw, got %v", raw)
+	}
+	if len(decoded) != 2 || len(channels) != 2 || channels[0] != "Deals" {
+		t.Errorf("expected both updates decoded with their channel, got %v and %v", decoded, channels)
+	}
+	if len(dropped) != 1 || dropped[0] != reaction {
+		t.Errorf("expected the unhandled reaction dropped, got %v", dropped)
+	}
+}
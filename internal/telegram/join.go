@@ -0,0 +1,83 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package telegram
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gotd/td/tg"
+	"github.com/gotd/td/tgerr"
+
+	"github.com/h3nc4/TelegramScout/internal/join"
+	"github.com/h3nc4/TelegramScout/internal/mentions"
+)
+
+// Join the chat behind an invite or public t.me link
+func (c *Client) Join(ctx context.Context, link string) (string, error) {
+	refs := mentions.Extract(link)
+	if len(refs) != 1 {
+		return "", fmt.Errorf("%w: %q is not a chat link", join.ErrRejected, link)
+	}
+	ref := refs[0]
+
+	api := c.client.API()
+	if ref.Invite != "" {
+		_, err := api.MessagesImportChatInvite(ctx, ref.Invite)
+		return joinResult(err)
+	}
+
+	resolved, err := api.ContactsResolveUsername(ctx, &tg.ContactsResolveUsernameRequest{Username: ref.Username})
+	if err != nil {
+		return joinResult(err)
+	}
+	for _, chat := range resolved.Chats {
+		if ch, ok := chat.(*tg.Channel); ok {
+			if !ch.Left {
+				return join.Member, nil
+			}
+			_, err := api.ChannelsJoinChannel(ctx, ch.AsInput())
+			return joinResult(err)
+		}
+	}
+	return "", fmt.Errorf("%w: %w", join.ErrRejected, ErrNotChat)
+}
+
+// Translate the errors Telegram answers joins with into what the join queue acts on
+func joinResult(err error) (string, error) {
+	if err == nil {
+		return join.Joined, nil
+	}
+	if d, ok := tgerr.AsFloodWait(err); ok {
+		return "", &join.FloodWaitError{Wait: d}
+	}
+	switch {
+	case tgerr.Is(err, "USER_ALREADY_PARTICIPANT"):
+		return join.Member, nil
+	case tgerr.Is(err, "INVITE_REQUEST_SENT"):
+		return join.Requested, nil
+	case tgerr.Is(err, "CHANNELS_TOO_MUCH"):
+		return "", join.ErrTooManyChannels
+	}
+	// Other RPC errors, such as expired invites, will not go away by retrying
+	if _, ok := tgerr.As(err); ok {
+		return "", fmt.Errorf("%w: %w", join.ErrRejected, err)
+	}
+	return "", fmt.Errorf("failed to join: %w", err)
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package telegram
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/h3nc4/TelegramScout/internal/atomicfile"
+)
+
+// A configured username as it last resolved, so the chat can still be found
+// by ID once the username stops resolving
+type KnownChat struct {
+	Username   string `json:"username"` // As configured, without @
+	ID         int64  `json:"id"`       // Bot API ID
+	AccessHash int64  `json:"access_hash"`
+}
+
+// Hold the last resolution of every configured username, saving them to a
+// file on every change
+type KnownChats struct {
+	mux   sync.Mutex
+	path  string
+	chats map[string]KnownChat
+}
+
+// Load the chats saved at path, a missing file holds none. An empty path keeps them in memory only.
+func LoadKnownChats(path string) (*KnownChats, error) {
+	k := &KnownChats{path: path, chats: make(map[string]KnownChat)}
+	if path == "" {
+		return k, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return k, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var list []KnownChat
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("invalid known chats file %s: %w", path, err)
+	}
+	for _, chat := range list {
+		k.chats[strings.ToLower(chat.Username)] = chat
+	}
+	return k, nil
+}
+
+// Return the chat username last resolved to
+func (k *KnownChats) Get(username string) (KnownChat, bool) {
+	k.mux.Lock()
+	defer k.mux.Unlock()
+	chat, ok := k.chats[strings.ToLower(username)]
+	return chat, ok
+}
+
+// Remember chat, saving the file when it changed
+func (k *KnownChats) Put(chat KnownChat) error {
+	k.mux.Lock()
+	defer k.mux.Unlock()
+
+	key := strings.ToLower(chat.Username)
+	if k.chats[key] == chat {
+		return nil
+	}
+	k.chats[key] = chat
+	if k.path == "" {
+		return nil
+	}
+	list := make([]KnownChat, 0, len(k.chats))
+	for _, c := range k.chats {
+		list = append(list, c)
+	}
+	slices.SortFunc(list, func(a, b KnownChat) int { return cmp.Compare(a.Username, b.Username) })
+	data, err := json.Marshal(list)
+	if err != nil {
+		return err
+	}
+	return atomicfile.Write(k.path, data)
+}
@@ -0,0 +1,52 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package telegram
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestKnownChats(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usernames.json")
+	k, err := LoadKnownChats(path)
+	if err != nil {
+		t.Fatalf("failed to load a missing file: %v", err)
+	}
+	chat := KnownChat{Username: "Deals", ID: -1001803446893, AccessHash: 77}
+	if err := k.Put(chat); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+
+	k, err = LoadKnownChats(path)
+	if err != nil {
+		t.Fatalf("failed to reload: %v", err)
+	}
+	if got, ok := k.Get("deals"); !ok || got != chat {
+		t.Errorf("expected the chat kept across restarts, got %+v", got)
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if _, err := LoadKnownChats(path); err == nil {
+		t.Error("expected a corrupt file rejected")
+	}
+}
@@ -0,0 +1,107 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package telegram
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/gotd/td/tg"
+
+	"github.com/h3nc4/TelegramScout/internal/chatid"
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+// Identify a message for link construction
+type messageRef struct {
+	kind     chatid.Kind
+	rawID    int64 // MTProto ID, without the Bot API -100 prefix
+	username string
+	topicID  int // Forum topic, zero outside topics
+	msgID    int
+}
+
+// Build a link to the message in the given style.
+// Basic groups and private chats have no message links, so they return "".
+func messageLink(style string, m messageRef) string {
+	if !m.linkable() {
+		return ""
+	}
+	if style == config.LinkStyleDeep {
+		return deepLink(m)
+	}
+	return webLink(m)
+}
+
+// Build the tg:// link shown next to a web link, for clients where t.me fails to open the app
+func appLink(style string, m messageRef) string {
+	if !m.linkable() || style == config.LinkStyleDeep {
+		return ""
+	}
+	return deepLink(m)
+}
+
+// Report whether Telegram supports links to messages in this chat
+func (m messageRef) linkable() bool {
+	return m.username != "" || m.kind == chatid.Channel
+}
+
+// Build a t.me link, including the topic segment for forum messages
+func webLink(m messageRef) string {
+	base := "https://t.me/c/" + strconv.FormatInt(m.rawID, 10)
+	if m.username != "" {
+		base = "https://t.me/" + m.username
+	}
+	if m.topicID != 0 {
+		return fmt.Sprintf("%s/%d/%d", base, m.topicID, m.msgID)
+	}
+	return fmt.Sprintf("%s/%d", base, m.msgID)
+}
+
+// Build a tg:// link that opens the message directly in the app
+func deepLink(m messageRef) string {
+	q := url.Values{}
+	target := "tg://privatepost?"
+	if m.username != "" {
+		target = "tg://resolve?"
+		q.Set("domain", m.username)
+	} else {
+		q.Set("channel", strconv.FormatInt(m.rawID, 10))
+	}
+	q.Set("post", strconv.Itoa(m.msgID))
+	if m.topicID != 0 {
+		q.Set("thread", strconv.Itoa(m.topicID))
+	}
+	return target + q.Encode()
+}
+
+// Return the forum topic a message belongs to, or zero
+func topicID(msg *tg.Message) int {
+	h, ok := msg.ReplyTo.(*tg.MessageReplyHeader)
+	if !ok || !h.ForumTopic {
+		return 0
+	}
+	// Replies inside a topic carry the topic ID separately
+	if h.ReplyToTopID != 0 {
+		return h.ReplyToTopID
+	}
+	// Top-level topic messages reply to the topic creation message
+	return h.ReplyToMsgID
+}
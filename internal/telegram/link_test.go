@@ -0,0 +1,93 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package telegram
+
+import (
+	"testing"
+
+	"github.com/gotd/td/tg"
+
+	"github.com/h3nc4/TelegramScout/internal/chatid"
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+func TestMessageLink(t *testing.T) {
+	public := messageRef{kind: chatid.Channel, rawID: 999, username: "testchan", msgID: 100}
+	private := messageRef{kind: chatid.Channel, rawID: 1803446893, msgID: 42}
+	topic := messageRef{kind: chatid.Channel, rawID: 1803446893, topicID: 7, msgID: 42}
+
+	tests := []struct {
+		name  string
+		style string
+		ref   messageRef
+		want  string
+	}{
+		{"Public Web", config.LinkStyleWeb, public, "https://t.me/testchan/100"},
+		{"Private Web", config.LinkStyleWeb, private, "https://t.me/c/1803446893/42"},
+		{"Topic Web", config.LinkStyleWeb, topic, "https://t.me/c/1803446893/7/42"},
+		{"Public Deep", config.LinkStyleDeep, public, "tg://resolve?domain=testchan&post=100"},
+		{"Topic Deep", config.LinkStyleDeep, topic, "tg://privatepost?channel=1803446893&post=42&thread=7"},
+		{"Basic Group", config.LinkStyleWeb, messageRef{kind: chatid.Group, rawID: 4567, msgID: 3}, ""},
+		{"Private Chat", config.LinkStyleWeb, messageRef{kind: chatid.User, rawID: 1710595474, msgID: 3}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := messageLink(tt.style, tt.ref); got != tt.want {
+				t.Errorf("messageLink() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppLink(t *testing.T) {
+	ref := messageRef{kind: chatid.Channel, rawID: 1803446893, topicID: 7, msgID: 42}
+	if got, want := appLink(config.LinkStyleWeb, ref), "tg://privatepost?channel=1803446893&post=42&thread=7"; got != want {
+		t.Errorf("appLink() = %q, want %q", got, want)
+	}
+	// The primary link already opens the app
+	if got := appLink(config.LinkStyleDeep, ref); got != "" {
+		t.Errorf("expected no app link in deep style, got %q", got)
+	}
+	if got := appLink(config.LinkStyleWeb, messageRef{kind: chatid.Group, rawID: 4567, msgID: 3}); got != "" {
+		t.Errorf("expected no app link for basic groups, got %q", got)
+	}
+}
+
+func TestTopicID(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply tg.MessageReplyHeaderClass
+		want  int
+	}{
+		{"No Reply", nil, 0},
+		{"Plain Reply", &tg.MessageReplyHeader{ReplyToMsgID: 5}, 0},
+		{"Topic Root", &tg.MessageReplyHeader{ForumTopic: true, ReplyToMsgID: 7}, 7},
+		{"Reply In Topic", &tg.MessageReplyHeader{ForumTopic: true, ReplyToMsgID: 12, ReplyToTopID: 7}, 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := &tg.Message{ReplyTo: tt.reply}
+			if got := topicID(msg); got != tt.want {
+				t.Errorf("topicID() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package telegram
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/gotd/td/telegram/downloader"
+	"github.com/gotd/td/tg"
+
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Recent messages whose media can still be downloaded
+const mediaCacheSize = 256
+
+// Returned when the media of a message is not known or cannot be downloaded
+var ErrMediaUnavailable = errors.New("media unavailable")
+
+// Media kinds reported in model.Message
+const (
+	mediaPhoto     = "photo"
+	mediaSticker   = "sticker"
+	mediaAnimation = "animation"
+	mediaVideo     = "video"
+	mediaVoice     = "voice"
+	mediaAudio     = "audio"
+	mediaDocument  = "document"
+	mediaWebPage   = "webpage"
+	mediaPoll      = "poll"
+	mediaDice      = "dice"
+	mediaOther     = "other"
+)
+
+// Classify the media attached to a message, or "" for plain text
+func mediaKind(msg *tg.Message) string {
+	switch m := msg.Media.(type) {
+	case nil, *tg.MessageMediaEmpty:
+		return ""
+	case *tg.MessageMediaPhoto:
+		return mediaPhoto
+	case *tg.MessageMediaWebPage:
+		return mediaWebPage
+	case *tg.MessageMediaPoll:
+		return mediaPoll
+	case *tg.MessageMediaDice:
+		return mediaDice
+	case *tg.MessageMediaDocument:
+		doc, ok := m.Document.(*tg.Document)
+		if !ok {
+			return mediaDocument
+		}
+		return documentKind(doc)
+	default:
+		return mediaOther
+	}
+}
+
+// Tell documents apart by their attributes
+func documentKind(doc *tg.Document) string {
+	kind := mediaDocument
+	for _, attr := range doc.Attributes {
+		switch a := attr.(type) {
+		case *tg.DocumentAttributeSticker:
+			return mediaSticker
+		case *tg.DocumentAttributeAnimated:
+			// GIFs also carry a video attribute, so this one wins
+			return mediaAnimation
+		case *tg.DocumentAttributeVideo:
+			kind = mediaVideo
+		case *tg.DocumentAttributeAudio:
+			if a.Voice {
+				kind = mediaVoice
+			} else {
+				kind = mediaAudio
+			}
+		}
+	}
+	return kind
+}
+
+// Return details of an attached document, or nil
+func mediaFile(msg *tg.Message) *model.File {
+	m, ok := msg.Media.(*tg.MessageMediaDocument)
+	if !ok {
+		return nil
+	}
+	doc, ok := m.Document.(*tg.Document)
+	if !ok {
+		return nil
+	}
+
+	f := &model.File{MimeType: doc.MimeType, Size: doc.Size}
+	for _, attr := range doc.Attributes {
+		if a, ok := attr.(*tg.DocumentAttributeFilename); ok {
+			f.Name = a.FileName
+		}
+	}
+	return f
+}
+
+// Build the download location of a message's media
+func mediaLocation(media tg.MessageMediaClass) (tg.InputFileLocationClass, bool) {
+	switch m := media.(type) {
+	case *tg.MessageMediaPhoto:
+		photo, ok := m.Photo.(*tg.Photo)
+		if !ok {
+			return nil, false
+		}
+		size := largestPhotoSize(photo)
+		if size == "" {
+			return nil, false
+		}
+		return &tg.InputPhotoFileLocation{
+			ID:            photo.ID,
+			AccessHash:    photo.AccessHash,
+			FileReference: photo.FileReference,
+			ThumbSize:     size,
+		}, true
+	case *tg.MessageMediaDocument:
+		doc, ok := m.Document.(*tg.Document)
+		if !ok {
+			return nil, false
+		}
+		return &tg.InputDocumentFileLocation{
+			ID:            doc.ID,
+			AccessHash:    doc.AccessHash,
+			FileReference: doc.FileReference,
+		}, true
+	}
+	return nil, false
+}
+
+// Pick the type of the biggest available photo size
+func largestPhotoSize(photo *tg.Photo) string {
+	best, bestSize := "", -1
+	for _, ps := range photo.Sizes {
+		switch p := ps.(type) {
+		case *tg.PhotoSize:
+			if p.Size > bestSize {
+				best, bestSize = p.Type, p.Size
+			}
+		case *tg.PhotoSizeProgressive:
+			if n := len(p.Sizes); n > 0 && p.Sizes[n-1] > bestSize {
+				best, bestSize = p.Type, p.Sizes[n-1]
+			}
+		}
+	}
+	return best
+}
+
+type mediaKey struct {
+	chatID int64
+	msgID  int
+}
+
+// Bounded map of recently emitted media, oldest evicted first
+type mediaCache struct {
+	mux   sync.Mutex
+	items map[mediaKey]tg.MessageMediaClass
+	order []mediaKey
+	next  int
+}
+
+func newMediaCache(size int) *mediaCache {
+	return &mediaCache{
+		items: make(map[mediaKey]tg.MessageMediaClass, size),
+		order: make([]mediaKey, size),
+	}
+}
+
+func (m *mediaCache) add(chatID int64, msgID int, media tg.MessageMediaClass) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	key := mediaKey{chatID, msgID}
+	if _, ok := m.items[key]; ok {
+		m.items[key] = media
+		return
+	}
+	delete(m.items, m.order[m.next])
+	m.order[m.next] = key
+	m.next = (m.next + 1) % len(m.order)
+	m.items[key] = media
+}
+
+func (m *mediaCache) get(chatID int64, msgID int) (tg.MessageMediaClass, bool) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	media, ok := m.items[mediaKey{chatID, msgID}]
+	return media, ok
+}
+
+// Stream the media of a recently received message to w
+func (c *Client) Download(ctx context.Context, chatID int64, msgID int, w io.Writer) error {
+	media, ok := c.media.get(chatID, msgID)
+	if !ok {
+		return ErrMediaUnavailable
+	}
+	loc, ok := mediaLocation(media)
+	if !ok {
+		return ErrMediaUnavailable
+	}
+	_, err := downloader.NewDownloader().Download(c.client.API(), loc).Stream(ctx, w)
+	return err
+}
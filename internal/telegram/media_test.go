@@ -0,0 +1,54 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package telegram
+
+import (
+	"testing"
+
+	"github.com/gotd/td/tg"
+)
+
+func TestMediaKind(t *testing.T) {
+	doc := func(attrs ...tg.DocumentAttributeClass) tg.MessageMediaClass {
+		return &tg.MessageMediaDocument{Document: &tg.Document{Attributes: attrs}}
+	}
+
+	tests := []struct {
+		name  string
+		media tg.MessageMediaClass
+		want  string
+	}{
+		{"Text", nil, ""},
+		{"Photo", &tg.MessageMediaPhoto{}, mediaPhoto},
+		{"Sticker", doc(&tg.DocumentAttributeSticker{}, &tg.DocumentAttributeImageSize{}), mediaSticker},
+		{"GIF", doc(&tg.DocumentAttributeVideo{}, &tg.DocumentAttributeAnimated{}), mediaAnimation},
+		{"Video", doc(&tg.DocumentAttributeVideo{}, &tg.DocumentAttributeFilename{FileName: "a.mp4"}), mediaVideo},
+		{"Voice", doc(&tg.DocumentAttributeAudio{Voice: true}), mediaVoice},
+		{"File", doc(&tg.DocumentAttributeFilename{FileName: "a.pdf"}), mediaDocument},
+		{"Dice", &tg.MessageMediaDice{Emoticon: "🎲"}, mediaDice},
+		{"Location", &tg.MessageMediaGeo{}, mediaOther},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mediaKind(&tg.Message{Media: tt.media}); got != tt.want {
+				t.Errorf("mediaKind() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
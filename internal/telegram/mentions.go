@@ -0,0 +1,137 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gotd/td/tg"
+
+	"github.com/h3nc4/TelegramScout/internal/chatid"
+	"github.com/h3nc4/TelegramScout/internal/mentions"
+)
+
+// Returned when a reference points to a user or bot rather than a group or channel
+var ErrNotChat = errors.New("reference is not a group or channel")
+
+// Look up the chat a mention or invite link points to
+func (c *Client) ResolveChat(ctx context.Context, ref mentions.Ref) (mentions.Info, error) {
+	api := c.client.API()
+	if ref.Invite != "" {
+		invite, err := api.MessagesCheckChatInvite(ctx, ref.Invite)
+		if err != nil {
+			return mentions.Info{}, fmt.Errorf("failed to check invite: %w", err)
+		}
+		return c.inviteInfo(ctx, invite)
+	}
+
+	resolved, err := api.ContactsResolveUsername(ctx, &tg.ContactsResolveUsernameRequest{Username: ref.Username})
+	if err != nil {
+		return mentions.Info{}, fmt.Errorf("failed to resolve username: %w", err)
+	}
+	if _, ok := resolved.Peer.(*tg.PeerUser); ok {
+		return mentions.Info{}, ErrNotChat
+	}
+	for _, chat := range resolved.Chats {
+		return c.chatInfo(ctx, chat)
+	}
+	return mentions.Info{}, ErrNotChat
+}
+
+// Return the groups and channels shared by a chat folder link, t.me/addlist/<slug>
+func (c *Client) ChatFolder(ctx context.Context, slug string) ([]mentions.Info, error) {
+	invite, err := c.client.API().ChatlistsCheckChatlistInvite(ctx, slug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check folder link: %w", err)
+	}
+	var chats []tg.ChatClass
+	switch i := invite.(type) {
+	case *tg.ChatlistsChatlistInvite:
+		chats = i.Chats
+	case *tg.ChatlistsChatlistInviteAlready:
+		chats = i.Chats
+	}
+	infos := make([]mentions.Info, 0, len(chats))
+	for _, chat := range chats {
+		if info, err := c.chatInfo(ctx, chat); err == nil {
+			infos = append(infos, info)
+		}
+	}
+	return infos, nil
+}
+
+// Describe the chat behind an invite, whether or not the account joined it
+func (c *Client) inviteInfo(ctx context.Context, invite tg.ChatInviteClass) (mentions.Info, error) {
+	switch i := invite.(type) {
+	case *tg.ChatInvite:
+		return mentions.Info{
+			Title:     i.Title,
+			Members:   i.ParticipantsCount,
+			Broadcast: i.Broadcast,
+		}, nil
+	case *tg.ChatInviteAlready:
+		info, err := c.chatInfo(ctx, i.Chat)
+		info.Joined = true
+		return info, err
+	case *tg.ChatInvitePeek:
+		return c.chatInfo(ctx, i.Chat)
+	}
+	return mentions.Info{}, ErrNotChat
+}
+
+// Describe a chat, asking for the full channel when its member count is missing
+func (c *Client) chatInfo(ctx context.Context, chat tg.ChatClass) (mentions.Info, error) {
+	switch ch := chat.(type) {
+	case *tg.Channel:
+		info := mentions.Info{
+			ID:        chatid.BotAPI(chatid.Channel, ch.ID),
+			Title:     ch.Title,
+			Username:  ch.Username,
+			Broadcast: ch.Broadcast,
+			Joined:    !ch.Left,
+		}
+		if count, ok := ch.GetParticipantsCount(); ok {
+			info.Members = count
+			return info, nil
+		}
+		full, err := c.client.API().ChannelsGetFullChannel(ctx, ch.AsInput())
+		if err != nil {
+			// The title alone is still worth reporting
+			return info, nil
+		}
+		if cf, ok := full.FullChat.(*tg.ChannelFull); ok {
+			info.Members, _ = cf.GetParticipantsCount()
+		}
+		return info, nil
+	case *tg.Chat:
+		return mentions.Info{
+			ID:      chatid.BotAPI(chatid.Group, ch.ID),
+			Title:   ch.Title,
+			Members: ch.ParticipantsCount,
+			Joined:  !ch.Left,
+		}, nil
+	case *tg.ChannelForbidden:
+		return mentions.Info{ID: chatid.BotAPI(chatid.Channel, ch.ID), Title: ch.Title, Broadcast: ch.Broadcast}, nil
+	case *tg.ChatForbidden:
+		return mentions.Info{ID: chatid.BotAPI(chatid.Group, ch.ID), Title: ch.Title}, nil
+	}
+	return mentions.Info{}, ErrNotChat
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package telegram
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gotd/td/tg"
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/chatid"
+)
+
+// A basic group upgraded to a supergroup, which carries on under a new ID
+type Migration struct {
+	From  int64 // Bot API ID of the basic group
+	To    int64 // Bot API ID of the supergroup
+	Title string
+}
+
+// Suggest the config change the migration calls for
+func (m Migration) Hint() string {
+	return fmt.Sprintf("upgraded to a supergroup, replace %d with %d in chats and chat_settings", m.From, m.To)
+}
+
+// Register a callback invoked when a monitored group is upgraded to a supergroup
+func (c *Client) OnMigrated(fn func(ctx context.Context, m Migration)) {
+	c.onMigrated = fn
+}
+
+// Return the supergroup a basic group was upgraded to, nil if it was not
+func migratedTo(chat *tg.Chat) *tg.InputChannel {
+	to, _ := chat.MigratedTo.(*tg.InputChannel)
+	return to
+}
+
+// Follow the service messages Telegram posts in both chats of a migration
+func (c *Client) handleMigration(ctx context.Context, msg *tg.MessageService, e tg.Entities) {
+	switch a := msg.Action.(type) {
+	case *tg.MessageActionChatMigrateTo:
+		if p, ok := msg.PeerID.(*tg.PeerChat); ok {
+			c.migrate(ctx, p.ChatID, a.ChannelID, e)
+		}
+	case *tg.MessageActionChannelMigrateFrom:
+		if p, ok := msg.PeerID.(*tg.PeerChannel); ok {
+			c.migrate(ctx, a.ChatID, p.ChannelID, e)
+		}
+	}
+}
+
+// Move a monitored basic group to the supergroup it became. Both service
+// messages of a migration arrive, the second finds nothing left to move.
+func (c *Client) migrate(ctx context.Context, chatID, channelID int64, e tg.Entities) {
+	m := Migration{From: chatid.BotAPI(chatid.Group, chatID), To: chatid.BotAPI(chatid.Channel, channelID)}
+
+	c.cacheMux.Lock()
+	info, ok := c.peerCache[m.From]
+	if !ok {
+		c.cacheMux.Unlock()
+		return
+	}
+	delete(c.peerCache, m.From)
+	info.Peer = inputPeer(chatid.Channel, channelID, e)
+	if ch, ok := e.Channels[channelID]; ok {
+		info.Title, info.Username = ch.Title, ch.Username
+	}
+	c.peerCache[m.To] = info
+	c.cacheMux.Unlock()
+
+	m.Title = info.Title
+	c.log.Warn("Monitored group was upgraded to a supergroup, following it",
+		zap.String("title", m.Title),
+		zap.Int64("from", m.From),
+		zap.Int64("to", m.To),
+		zap.String("hint", m.Hint()),
+	)
+	if c.onMigrated != nil {
+		c.onMigrated(ctx, m)
+	}
+}
+
+// Monitor the supergroup a configured basic group was upgraded to instead,
+// returning its ID, its peer and the hint for fixing the config
+func (c *Client) resolvedMigration(target string, id int64, title string, to *tg.InputChannel) (int64, tg.InputPeerClass, string) {
+	m := Migration{From: id, To: chatid.BotAPI(chatid.Channel, to.ChannelID), Title: title}
+	c.log.Warn("Configured group was upgraded to a supergroup, monitoring the supergroup",
+		zap.String("target", target),
+		zap.Int64("to", m.To),
+		zap.String("hint", m.Hint()),
+	)
+	return m.To, &tg.InputPeerChannel{ChannelID: to.ChannelID, AccessHash: to.AccessHash}, m.Hint()
+}
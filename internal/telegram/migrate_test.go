@@ -0,0 +1,95 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package telegram
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gotd/td/tg"
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/chatid"
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+func TestMigration(t *testing.T) {
+	msgChan := make(chan model.Message, 1)
+	c, err := NewClient(&config.Config{Session: "migrate"}, zap.NewNop(), msgChan)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	c.updatePeerCache(-4567, "Group", "", &tg.InputPeerChat{ChatID: 4567})
+	var migrations []Migration
+	c.OnMigrated(func(_ context.Context, m Migration) { migrations = append(migrations, m) })
+
+	ctx := context.Background()
+	channel := &tg.Channel{ID: 8888, AccessHash: 5, Title: "Group", Megagroup: true, Photo: &tg.ChatPhotoEmpty{}}
+	dispatch := func(update tg.UpdateClass) {
+		t.Helper()
+		err := c.HandleUpdates(ctx, &tg.Updates{Updates: []tg.UpdateClass{update}, Chats: []tg.ChatClass{channel}})
+		if err != nil {
+			t.Fatalf("failed to handle updates: %v", err)
+		}
+	}
+	dispatch(&tg.UpdateNewMessage{Message: &tg.MessageService{ID: 10, PeerID: &tg.PeerChat{ChatID: 4567}, Action: &tg.MessageActionChatMigrateTo{ChannelID: 8888}}})
+	dispatch(&tg.UpdateNewChannelMessage{Message: &tg.MessageService{ID: 1, PeerID: &tg.PeerChannel{ChannelID: 8888}, Action: &tg.MessageActionChannelMigrateFrom{Title: "Group", ChatID: 4567}}})
+
+	if len(migrations) != 1 {
+		t.Fatalf("expected one migration reported, got %+v", migrations)
+	}
+	if m := migrations[0]; m.From != -4567 || m.To != -1000000008888 || !strings.Contains(m.Hint(), "replace -4567 with -1000000008888") {
+		t.Errorf("unexpected migration %+v: %s", m, m.Hint())
+	}
+	if _, ok := c.peerCache[-4567]; ok {
+		t.Error("expected the basic group dropped from the peer cache")
+	}
+	if p, ok := c.peerCache[-1000000008888].Peer.(*tg.InputPeerChannel); !ok || p.AccessHash != 5 {
+		t.Errorf("expected the supergroup cached with its access hash, got %+v", c.peerCache[-1000000008888])
+	}
+
+	dispatch(&tg.UpdateNewChannelMessage{Message: &tg.Message{ID: 2, PeerID: &tg.PeerChannel{ChannelID: 8888}, Message: "still here"}})
+	select {
+	case msg := <-msgChan:
+		if msg.ChatID != -1000000008888 || msg.Text != "still here" {
+			t.Errorf("unexpected message %+v", msg)
+		}
+	default:
+		t.Error("expected messages of the supergroup monitored")
+	}
+}
+
+func TestFoundChatsMigrated(t *testing.T) {
+	c, _ := newResolveClient(t)
+	wanted := []wantedChat{{target: "-4567", ref: chatid.Ref{Kind: chatid.Group, ID: 4567}}}
+	found := []tg.ChatClass{&tg.Chat{ID: 4567, Title: "Group", Deactivated: true, MigratedTo: &tg.InputChannel{ChannelID: 8888, AccessHash: 5}}}
+
+	var report ResolveReport
+	if left := c.foundChats(found, "getChats", wanted, &report); len(left) != 0 {
+		t.Fatalf("expected the group resolved, got %+v", left)
+	}
+	if got := report.Resolved[0]; got.ID != -1000000008888 || got.Target != "-4567" || !strings.Contains(got.Hint, "with -1000000008888") {
+		t.Errorf("expected the supergroup resolved with a hint, got %+v", got)
+	}
+	if p, ok := c.peerCache[-1000000008888].Peer.(*tg.InputPeerChannel); !ok || p.AccessHash != 5 {
+		t.Errorf("expected the supergroup cached, got %+v", c.peerCache)
+	}
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+
+	"github.com/gotd/td/tg"
+
+	"github.com/h3nc4/TelegramScout/internal/chatid"
+)
+
+// Copy a received message into the chat to refers to as the account. Text,
+// media and formatting are kept, without the forwarded from header.
+func (c *Client) Mirror(ctx context.Context, chatID int64, msgID int, to string) error {
+	return c.forward(ctx, chatID, msgID, to, true)
+}
+
+// Forward a received message as the account to the chat to refers to, or to
+// Saved Messages when to is empty
+func (c *Client) Forward(ctx context.Context, chatID int64, msgID int, to string) error {
+	return c.forward(ctx, chatID, msgID, to, false)
+}
+
+// React to a received message as the account
+func (c *Client) React(ctx context.Context, chatID int64, msgID int, emoji string) error {
+	from, err := c.monitoredPeer(chatID)
+	if err != nil {
+		return err
+	}
+	return react(ctx, c.client.API(), from, msgID, emoji)
+}
+
+func (c *Client) forward(ctx context.Context, chatID int64, msgID int, to string, dropAuthor bool) error {
+	from, err := c.monitoredPeer(chatID)
+	if err != nil {
+		return err
+	}
+
+	var dest tg.InputPeerClass = &tg.InputPeerSelf{}
+	if to != "" {
+		ref, err := chatid.Parse(to)
+		if err != nil {
+			return err
+		}
+		_, info, err := c.linkPeer(ctx, ref)
+		if err != nil {
+			return fmt.Errorf("failed to resolve chat %q: %w", to, err)
+		}
+		dest = info.Peer
+	}
+	return forwardMessage(ctx, c.client.API(), from, dest, msgID, dropAuthor)
+}
+
+// Return the peer of a chat messages were received from
+func (c *Client) monitoredPeer(chatID int64) (tg.InputPeerClass, error) {
+	c.cacheMux.RLock()
+	defer c.cacheMux.RUnlock()
+	if p := c.peerCache[chatID].Peer; p != nil {
+		return p, nil
+	}
+	return nil, fmt.Errorf("chat %d is not monitored", chatID)
+}
+
+// Forward a message, without the header when dropAuthor is set, which is the
+// MTProto counterpart of the Bot API's copyMessage
+func forwardMessage(ctx context.Context, api *tg.Client, from, to tg.InputPeerClass, msgID int, dropAuthor bool) error {
+	_, err := api.MessagesForwardMessages(ctx, &tg.MessagesForwardMessagesRequest{
+		DropAuthor: dropAuthor,
+		FromPeer:   from,
+		ID:         []int{msgID},
+		RandomID:   []int64{rand.Int64()},
+		ToPeer:     to,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to forward message: %w", err)
+	}
+	return nil
+}
+
+func react(ctx context.Context, api *tg.Client, peer tg.InputPeerClass, msgID int, emoji string) error {
+	_, err := api.MessagesSendReaction(ctx, &tg.MessagesSendReactionRequest{
+		Peer:     peer,
+		MsgID:    msgID,
+		Reaction: []tg.ReactionClass{&tg.ReactionEmoji{Emoticon: emoji}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to react: %w", err)
+	}
+	return nil
+}
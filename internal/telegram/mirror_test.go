@@ -0,0 +1,71 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package telegram
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gotd/td/bin"
+	"github.com/gotd/td/tg"
+)
+
+func TestForwardMessage(t *testing.T) {
+	_, mock := newResolveClient(t)
+	from := &tg.InputPeerChannel{ChannelID: 1234, AccessHash: 5}
+	to := &tg.InputPeerChannel{ChannelID: 99, AccessHash: 6}
+
+	for _, drop := range []bool{true, false} {
+		mock.ExpectFunc(func(b bin.Encoder) {
+			req, ok := b.(*tg.MessagesForwardMessagesRequest)
+			if !ok {
+				t.Fatalf("unexpected request %T", b)
+			}
+			if req.DropAuthor != drop || req.FromPeer != from || req.ToPeer != to || len(req.ID) != 1 || req.ID[0] != 12 || len(req.RandomID) != 1 {
+				t.Errorf("unexpected forward request %+v", req)
+			}
+		}).ThenResult(&tg.Updates{})
+
+		if err := forwardMessage(context.Background(), tg.NewClient(mock), from, to, 12, drop); err != nil {
+			t.Fatalf("forwardMessage() error = %v", err)
+		}
+	}
+}
+
+func TestReact(t *testing.T) {
+	_, mock := newResolveClient(t)
+	peer := &tg.InputPeerChannel{ChannelID: 1234, AccessHash: 5}
+	mock.ExpectCall(&tg.MessagesSendReactionRequest{
+		Peer:     peer,
+		MsgID:    12,
+		Reaction: []tg.ReactionClass{&tg.ReactionEmoji{Emoticon: "👍"}},
+	}).ThenResult(&tg.Updates{})
+
+	if err := react(context.Background(), tg.NewClient(mock), peer, 12, "👍"); err != nil {
+		t.Fatalf("react() error = %v", err)
+	}
+}
+
+func TestMirror_UnknownChat(t *testing.T) {
+	c, _ := newResolveClient(t)
+	if err := c.Mirror(context.Background(), -1001234, 12, "@mirror"); err == nil || !strings.Contains(err.Error(), "not monitored") {
+		t.Errorf("expected an unknown chat to fail, got %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gotd/td/telegram/query"
+	"github.com/gotd/td/tg"
+
+	"github.com/h3nc4/TelegramScout/internal/chatid"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Returned when a watched reference points to a chat rather than a user
+var ErrNotUser = errors.New("reference is not a user")
+
+// Fetch the public profile of a user, given as @username or as the ID of a
+// user the account has a chat with
+func (c *Client) Profile(ctx context.Context, target string) (model.Profile, error) {
+	u, err := c.watchedUser(ctx, target)
+	if err != nil {
+		return model.Profile{}, err
+	}
+	full, err := c.client.API().UsersGetFullUser(ctx, u)
+	if err != nil {
+		return model.Profile{}, err
+	}
+
+	p := model.Profile{ID: u.UserID, Bio: full.FullUser.About}
+	for _, uc := range full.Users {
+		user, ok := uc.(*tg.User)
+		if !ok || user.ID != u.UserID {
+			continue
+		}
+		p.Username = user.Username
+		p.Name = strings.TrimSpace(user.FirstName + " " + user.LastName)
+		if photo, ok := user.Photo.(*tg.UserProfilePhoto); ok {
+			p.PhotoID = photo.PhotoID
+		}
+	}
+	return p, nil
+}
+
+// Resolve a watched user once, so it is still found after changing its username
+func (c *Client) watchedUser(ctx context.Context, target string) (*tg.InputUser, error) {
+	c.cacheMux.RLock()
+	u, ok := c.userCache[target]
+	c.cacheMux.RUnlock()
+	if ok {
+		return u, nil
+	}
+
+	ref, err := chatid.Parse(target)
+	if err != nil {
+		return nil, err
+	}
+	if ref.IsUsername() {
+		resolved, err := c.client.API().ContactsResolveUsername(ctx, &tg.ContactsResolveUsernameRequest{Username: ref.Username})
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve username: %w", err)
+		}
+		for _, uc := range resolved.Users {
+			if user, ok := uc.(*tg.User); ok {
+				u = &tg.InputUser{UserID: user.ID, AccessHash: user.AccessHash}
+				break
+			}
+		}
+	} else if ref.Kind == chatid.Any || ref.Kind == chatid.User {
+		// Access hashes of users known only by ID come from the account's chats
+		iter := query.GetDialogs(c.client.API()).Iter()
+		for iter.Next(ctx) {
+			if p, ok := iter.Value().Peer.(*tg.InputPeerUser); ok && p.UserID == ref.ID {
+				u = &tg.InputUser{UserID: p.UserID, AccessHash: p.AccessHash}
+				break
+			}
+		}
+		if err := iter.Err(); err != nil {
+			return nil, err
+		}
+		if u == nil {
+			return nil, fmt.Errorf("user %d not found in dialogs, use their @username instead", ref.ID)
+		}
+	}
+	if u == nil {
+		return nil, ErrNotUser
+	}
+
+	c.cacheMux.Lock()
+	c.userCache[target] = u
+	c.cacheMux.Unlock()
+	return u, nil
+}
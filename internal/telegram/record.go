@@ -0,0 +1,195 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package telegram
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gotd/td/bin"
+	"github.com/gotd/td/tg"
+)
+
+// Longest line of a recording, updates with large batches of users and chats
+// run to a few hundred kilobytes
+const maxRecordLine = 16 << 20
+
+// A batch of updates as received, one JSON object per line of a recording
+type recordedUpdates struct {
+	Time    time.Time `json:"time"`
+	Updates []byte    `json:"updates"` // TL encoding of a tg.UpdatesClass
+}
+
+// Append raw incoming updates to a file, so bugs can be reproduced from a
+// capture with Replay. Access hashes, phone numbers and file references are
+// scrubbed, since they grant access to the account's peers and media; text,
+// names and IDs are kept.
+type Recorder struct {
+	mux  sync.Mutex
+	file *os.File
+}
+
+// Open the recording at path, appending to it
+func OpenRecorder(path string) (*Recorder, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording %s: %w", path, err)
+	}
+	return &Recorder{file: f}, nil
+}
+
+// Append a scrubbed copy of updates, leaving them untouched
+func (r *Recorder) Record(updates tg.UpdatesClass) error {
+	var b bin.Buffer
+	if err := (&tg.UpdatesBox{Updates: updates}).Encode(&b); err != nil {
+		return fmt.Errorf("failed to encode updates: %w", err)
+	}
+	var scrubbed tg.UpdatesBox
+	if err := scrubbed.Decode(&bin.Buffer{Buf: b.Buf}); err != nil {
+		return fmt.Errorf("failed to copy updates: %w", err)
+	}
+	scrubUpdates(scrubbed.Updates)
+	b.Reset()
+	if err := scrubbed.Encode(&b); err != nil {
+		return fmt.Errorf("failed to encode updates: %w", err)
+	}
+
+	line, err := json.Marshal(recordedUpdates{Time: time.Now(), Updates: b.Buf})
+	if err != nil {
+		return err
+	}
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	_, err = r.file.Write(append(line, '\n'))
+	return err
+}
+
+func (r *Recorder) Close() error {
+	return r.file.Close()
+}
+
+// Read a recording and call fn with every batch of updates, in order
+func Replay(r io.Reader, fn func(at time.Time, updates tg.UpdatesClass) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxRecordLine)
+	for n := 1; scanner.Scan(); n++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var rec recordedUpdates
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return fmt.Errorf("line %d: %w", n, err)
+		}
+		var box tg.UpdatesBox
+		if err := box.Decode(&bin.Buffer{Buf: rec.Updates}); err != nil {
+			return fmt.Errorf("line %d: failed to decode updates: %w", n, err)
+		}
+		if err := fn(rec.Time, box.Updates); err != nil {
+			return fmt.Errorf("line %d: %w", n, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// Clear what grants access to peers and media from updates
+func scrubUpdates(updates tg.UpdatesClass) {
+	var list []tg.UpdateClass
+	switch u := updates.(type) {
+	case *tg.Updates:
+		list = u.Updates
+		scrubEntities(u.Users, u.Chats)
+	case *tg.UpdatesCombined:
+		list = u.Updates
+		scrubEntities(u.Users, u.Chats)
+	case *tg.UpdateShort:
+		list = []tg.UpdateClass{u.Update}
+	case *tg.UpdateShortSentMessage:
+		scrubMedia(u.Media)
+	}
+	for _, update := range list {
+		switch u := update.(type) {
+		case *tg.UpdateNewMessage:
+			scrubMessage(u.Message)
+		case *tg.UpdateNewChannelMessage:
+			scrubMessage(u.Message)
+		case *tg.UpdateEditMessage:
+			scrubMessage(u.Message)
+		case *tg.UpdateEditChannelMessage:
+			scrubMessage(u.Message)
+		case *tg.UpdateServiceNotification:
+			scrubMedia(u.Media)
+		}
+	}
+}
+
+func scrubEntities(users []tg.UserClass, chats []tg.ChatClass) {
+	for _, u := range users {
+		if u, ok := u.(*tg.User); ok {
+			u.AccessHash = 0
+			u.Phone = ""
+		}
+	}
+	for _, c := range chats {
+		switch c := c.(type) {
+		case *tg.Channel:
+			c.AccessHash = 0
+		case *tg.ChannelForbidden:
+			c.AccessHash = 0
+		}
+	}
+}
+
+func scrubMessage(msg tg.MessageClass) {
+	if m, ok := msg.(*tg.Message); ok {
+		scrubMedia(m.Media)
+	}
+}
+
+func scrubMedia(media tg.MessageMediaClass) {
+	switch m := media.(type) {
+	case *tg.MessageMediaPhoto:
+		scrubPhoto(m.Photo)
+	case *tg.MessageMediaDocument:
+		scrubDocument(m.Document)
+	case *tg.MessageMediaWebPage:
+		if page, ok := m.Webpage.(*tg.WebPage); ok {
+			scrubPhoto(page.Photo)
+			scrubDocument(page.Document)
+		}
+	}
+}
+
+func scrubPhoto(photo tg.PhotoClass) {
+	if p, ok := photo.(*tg.Photo); ok {
+		p.AccessHash = 0
+		p.FileReference = nil
+	}
+}
+
+func scrubDocument(doc tg.DocumentClass) {
+	if d, ok := doc.(*tg.Document); ok {
+		d.AccessHash = 0
+		d.FileReference = nil
+	}
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package telegram
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gotd/td/tg"
+)
+
+func TestRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "updates.jsonl")
+	rec, err := OpenRecorder(path)
+	if err != nil {
+		t.Fatalf("failed to open recorder: %v", err)
+	}
+
+	user := &tg.User{ID: 7, AccessHash: 42, Phone: "5511999999999", FirstName: "Ana"}
+	channel := &tg.Channel{ID: 1001, AccessHash: 43, Title: "Deals", Broadcast: true, Photo: &tg.ChatPhotoEmpty{}}
+	photo := &tg.Photo{ID: 9, AccessHash: 44, FileReference: []byte{1, 2, 3}}
+	msg := &tg.Message{ID: 1, PeerID: &tg.PeerChannel{ChannelID: 1001}, Message: "cheap GPU", Media: &tg.MessageMediaPhoto{Photo: photo}}
+	updates := &tg.Updates{
+		Updates: []tg.UpdateClass{&tg.UpdateNewChannelMessage{Message: msg}},
+		Users:   []tg.UserClass{user},
+		Chats:   []tg.ChatClass{channel},
+	}
+	if err := rec.Record(updates); err != nil {
+		t.Fatalf("failed to record: %v", err)
+	}
+	if err := rec.Record(&tg.UpdateShort{Update: &tg.UpdateUserStatus{UserID: 7, Status: &tg.UserStatusOnline{}}}); err != nil {
+		t.Fatalf("failed to record: %v", err)
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatalf("failed to close recorder: %v", err)
+	}
+	if user.Phone == "" || channel.AccessHash == 0 || photo.FileReference == nil {
+		t.Error("expected the recorded updates left untouched")
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open recording: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+	var replayed []tg.UpdatesClass
+	err = Replay(f, func(at time.Time, u tg.UpdatesClass) error {
+		if at.IsZero() {
+			t.Error("expected the time of recording")
+		}
+		replayed = append(replayed, u)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to replay: %v", err)
+	}
+	if len(replayed) != 2 {
+		t.Fatalf("expected 2 batches, got %d", len(replayed))
+	}
+
+	got, ok := replayed[0].(*tg.Updates)
+	if !ok {
+		t.Fatalf("expected *tg.Updates, got %T", replayed[0])
+	}
+	gotUser := got.Users[0].(*tg.User)
+	if gotUser.Phone != "" || gotUser.AccessHash != 0 || gotUser.FirstName != "Ana" {
+		t.Errorf("expected the phone and access hash scrubbed, got %+v", gotUser)
+	}
+	if gotChannel := got.Chats[0].(*tg.Channel); gotChannel.AccessHash != 0 || gotChannel.Title != "Deals" {
+		t.Errorf("expected the channel access hash scrubbed, got %+v", gotChannel)
+	}
+	gotMsg := got.Updates[0].(*tg.UpdateNewChannelMessage).Message.(*tg.Message)
+	if gotMsg.Message != "cheap GPU" {
+		t.Errorf("expected the text kept, got %q", gotMsg.Message)
+	}
+	gotPhoto := gotMsg.Media.(*tg.MessageMediaPhoto).Photo.(*tg.Photo)
+	if gotPhoto.AccessHash != 0 || len(gotPhoto.FileReference) != 0 || gotPhoto.ID != 9 {
+		t.Errorf("expected the photo access scrubbed, got %+v", gotPhoto)
+	}
+	if _, ok := replayed[1].(*tg.UpdateShort); !ok {
+		t.Errorf("expected *tg.UpdateShort, got %T", replayed[1])
+	}
+}
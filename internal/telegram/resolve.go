@@ -0,0 +1,221 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gotd/td/tg"
+	"github.com/gotd/td/tgerr"
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/chatid"
+)
+
+// Find chats missing from the dialogs by their ID, one request per chat so
+// an unknown ID does not fail the others. Basic groups are fetched with
+// messages.getChats and channels with channels.getChannels and a zero access
+// hash, which Telegram accepts for channels the account has seen.
+// messages.getAllChats would list every chat the account is in, but it is
+// not part of the API layer the client speaks.
+func (c *Client) lookupIDs(ctx context.Context, api *tg.Client, wanted []wantedChat, report *ResolveReport) []wantedChat {
+	for _, w := range slices.Clone(wanted) {
+		if w.ref.Kind == chatid.Group || w.ref.Kind == chatid.Any {
+			res, err := api.MessagesGetChats(ctx, []int64{w.ref.ID})
+			if err == nil {
+				wanted = c.foundChats(res.GetChats(), "getChats", wanted, report)
+			} else {
+				c.log.Debug("Chat not found with messages.getChats", zap.String("target", w.target), zap.Error(err))
+			}
+		}
+		if !slices.Contains(wanted, w) {
+			continue
+		}
+		if w.ref.Kind == chatid.Channel || w.ref.Kind == chatid.Any {
+			res, err := api.ChannelsGetChannels(ctx, []tg.InputChannelClass{&tg.InputChannel{ChannelID: w.ref.ID}})
+			if err == nil {
+				wanted = c.foundChats(res.GetChats(), "getChannels", wanted, report)
+			} else {
+				c.log.Debug("Chat not found with channels.getChannels", zap.String("target", w.target), zap.Error(err))
+			}
+		}
+	}
+	return wanted
+}
+
+// Monitor the chats in found that are wanted, returning the ones still missing
+func (c *Client) foundChats(found []tg.ChatClass, strategy string, wanted []wantedChat, report *ResolveReport) []wantedChat {
+	for _, chat := range found {
+		var kind chatid.Kind
+		var raw int64
+		var title, username string
+		var p tg.InputPeerClass
+		var to *tg.InputChannel
+		switch ch := chat.(type) {
+		case *tg.Chat:
+			kind, raw, title, p = chatid.Group, ch.ID, ch.Title, &tg.InputPeerChat{ChatID: ch.ID}
+			to = migratedTo(ch)
+		case *tg.Channel:
+			kind, raw, title, username, p = chatid.Channel, ch.ID, ch.Title, ch.Username, ch.AsInputPeer()
+		default:
+			// Chats the account left or was banned from cannot be monitored
+			continue
+		}
+
+		i := slices.IndexFunc(wanted, func(w wantedChat) bool { return w.ref.Matches(kind, raw) })
+		if i < 0 {
+			continue
+		}
+		target := wanted[i].target
+		wanted = slices.Delete(wanted, i, i+1)
+
+		id := chatid.BotAPI(kind, raw)
+		c.log.Info("Found chat by ID", zap.String("target", target), zap.Int64("id", id), zap.String("strategy", strategy))
+		var hint string
+		if to != nil {
+			id, p, hint = c.resolvedMigration(target, id, title, to)
+		}
+		c.updatePeerCache(id, title, username, p)
+		report.Resolved = append(report.Resolved, ResolvedChat{Target: target, ID: id, Title: title, Hint: hint})
+	}
+	return wanted
+}
+
+// Resolve a user by phone number with contacts.resolvePhone
+func (c *Client) resolvePhone(ctx context.Context, api *tg.Client, target, phone string) (ResolvedChat, error) {
+	res, err := api.ContactsResolvePhone(ctx, phone)
+	if err != nil {
+		return ResolvedChat{}, err
+	}
+	p, ok := res.Peer.(*tg.PeerUser)
+	if !ok {
+		return ResolvedChat{}, fmt.Errorf("phone number resolved to a %T, not a user", res.Peer)
+	}
+	var user *tg.User
+	for _, u := range res.Users {
+		if u, ok := u.(*tg.User); ok && u.ID == p.UserID {
+			user = u
+		}
+	}
+	if user == nil {
+		return ResolvedChat{}, fmt.Errorf("user %d missing from the response", p.UserID)
+	}
+
+	id := chatid.BotAPI(chatid.User, user.ID)
+	title := strings.TrimSpace(user.FirstName + " " + user.LastName)
+	if title == "" {
+		title = target
+	}
+	c.updatePeerCache(id, title, user.Username, user.AsInputPeer())
+	c.log.Info("Found chat by phone number", zap.String("target", target), zap.Int64("id", id), zap.String("strategy", "resolvePhone"))
+	return ResolvedChat{Target: target, ID: id, Title: title}, nil
+}
+
+// Remember what a configured username resolved to
+func (c *Client) rememberUsername(username string, id int64, p tg.InputPeerClass) {
+	if c.known == nil {
+		return
+	}
+	var hash int64
+	switch p := p.(type) {
+	case *tg.InputPeerChannel:
+		hash = p.AccessHash
+	case *tg.InputPeerUser:
+		hash = p.AccessHash
+	default:
+		return
+	}
+	if err := c.known.Put(KnownChat{Username: username, ID: id, AccessHash: hash}); err != nil {
+		c.log.Error("Failed to save known chats", zap.Error(err))
+	}
+}
+
+// Look for a configured username that stopped resolving through the chat it
+// resolved to before, which is still reachable by ID after a rename or after
+// dropping its public username. Returns the chat found, or why it failed.
+func (c *Client) reresolve(ctx context.Context, api *tg.Client, target, username string, cause error) (ResolvedChat, *FailedChat) {
+	failed := &FailedChat{Target: target, Reason: cause.Error(), Hint: resolveHint(target)}
+	if _, rpc := tgerr.As(cause); !rpc || c.known == nil {
+		return ResolvedChat{}, failed
+	}
+	known, ok := c.known.Get(username)
+	if !ok {
+		return ResolvedChat{}, failed
+	}
+
+	kind, raw := chatid.FromBotAPI(known.ID)
+	var title, current string
+	var p tg.InputPeerClass
+	switch kind {
+	case chatid.Channel:
+		res, err := api.ChannelsGetChannels(ctx, []tg.InputChannelClass{&tg.InputChannel{ChannelID: raw, AccessHash: known.AccessHash}})
+		if err != nil {
+			failed.Reason = fmt.Sprintf("%v; chat %d it pointed to is unreachable: %v", cause, known.ID, err)
+			failed.Hint = "the chat went private or removed the account; join it again through an invite link and list it by ID"
+			return ResolvedChat{}, failed
+		}
+		switch ch := firstChat(res.GetChats()).(type) {
+		case *tg.Channel:
+			title, current, p = ch.Title, ch.Username, ch.AsInputPeer()
+		case *tg.ChannelForbidden:
+			failed.Reason = fmt.Sprintf("%v; the account was banned from %s (%d) it pointed to", cause, ch.Title, known.ID)
+			failed.Hint = "remove the chat from chats, or have an admin unban the account"
+			return ResolvedChat{}, failed
+		default:
+			return ResolvedChat{}, failed
+		}
+	case chatid.User:
+		users, err := api.UsersGetUsers(ctx, []tg.InputUserClass{&tg.InputUser{UserID: raw, AccessHash: known.AccessHash}})
+		if err != nil || len(users) == 0 {
+			return ResolvedChat{}, failed
+		}
+		user, ok := users[0].(*tg.User)
+		if !ok {
+			return ResolvedChat{}, failed
+		}
+		title, current, p = strings.TrimSpace(user.FirstName+" "+user.LastName), user.Username, user.AsInputPeer()
+	default:
+		return ResolvedChat{}, failed
+	}
+
+	hint := fmt.Sprintf("no longer has a public username, replace %s with %d in chats", target, known.ID)
+	if current != "" {
+		hint = fmt.Sprintf("renamed to @%s, replace %s with @%s in chats", current, target, current)
+	}
+	if title == "" {
+		title = target
+	}
+	c.log.Warn("Found chat whose username stopped resolving by its ID",
+		zap.String("target", target),
+		zap.Int64("id", known.ID),
+		zap.String("hint", hint),
+	)
+	c.updatePeerCache(known.ID, title, current, p)
+	return ResolvedChat{Target: target, ID: known.ID, Title: title, Hint: hint}, nil
+}
+
+func firstChat(chats []tg.ChatClass) tg.ChatClass {
+	if len(chats) == 0 {
+		return nil
+	}
+	return chats[0]
+}
@@ -0,0 +1,150 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package telegram
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gotd/td/tg"
+	"github.com/gotd/td/tgerr"
+	"github.com/gotd/td/tgmock"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/h3nc4/TelegramScout/internal/chatid"
+)
+
+func newResolveClient(t *testing.T) (*Client, *tgmock.Mock) {
+	t.Helper()
+	mock := tgmock.New(t)
+	return &Client{log: zaptest.NewLogger(t), peerCache: make(map[int64]peerInfo)}, mock
+}
+
+func TestLookupIDs(t *testing.T) {
+	c, mock := newResolveClient(t)
+	api := tg.NewClient(mock)
+	wanted := []wantedChat{
+		{target: "-4567", ref: chatid.Ref{Kind: chatid.Group, ID: 4567}},
+		{target: "1803446893", ref: chatid.Ref{Kind: chatid.Any, ID: 1803446893}},
+		{target: "-1009999", ref: chatid.Ref{Kind: chatid.Channel, ID: 9999}},
+	}
+
+	// The basic group is found directly, the bare ID only as a channel
+	mock.ExpectCall(&tg.MessagesGetChatsRequest{ID: []int64{4567}}).ThenResult(&tg.MessagesChats{
+		Chats: []tg.ChatClass{&tg.Chat{ID: 4567, Title: "Group", Photo: &tg.ChatPhotoEmpty{}}},
+	})
+	mock.ExpectCall(&tg.MessagesGetChatsRequest{ID: []int64{1803446893}}).ThenRPCErr(&tgerr.Error{Code: 400, Type: "CHAT_ID_INVALID"})
+	mock.ExpectCall(&tg.ChannelsGetChannelsRequest{ID: []tg.InputChannelClass{&tg.InputChannel{ChannelID: 1803446893}}}).ThenResult(&tg.MessagesChats{
+		Chats: []tg.ChatClass{&tg.Channel{ID: 1803446893, AccessHash: 77, Title: "Channel", Username: "chan", Photo: &tg.ChatPhotoEmpty{}}},
+	})
+	mock.ExpectCall(&tg.ChannelsGetChannelsRequest{ID: []tg.InputChannelClass{&tg.InputChannel{ChannelID: 9999}}}).ThenRPCErr(&tgerr.Error{Code: 400, Type: "CHANNEL_INVALID"})
+
+	var report ResolveReport
+	left := c.lookupIDs(context.Background(), api, wanted, &report)
+
+	if len(left) != 1 || left[0].target != "-1009999" {
+		t.Errorf("expected only the unknown channel left, got %+v", left)
+	}
+	if len(report.Resolved) != 2 {
+		t.Fatalf("expected 2 resolved chats, got %+v", report.Resolved)
+	}
+	if got := report.Resolved[0]; got.ID != -4567 || got.Title != "Group" {
+		t.Errorf("unexpected group %+v", got)
+	}
+	if got := report.Resolved[1]; got.ID != -1001803446893 || got.Target != "1803446893" {
+		t.Errorf("unexpected channel %+v", got)
+	}
+	info, ok := c.peerCache[-1001803446893]
+	if !ok || info.Username != "chan" {
+		t.Errorf("expected the channel in the peer cache, got %+v", info)
+	}
+	if p, ok := info.Peer.(*tg.InputPeerChannel); !ok || p.AccessHash != 77 {
+		t.Errorf("expected the access hash from the response, got %+v", info.Peer)
+	}
+}
+
+func TestResolvePhone(t *testing.T) {
+	c, mock := newResolveClient(t)
+	api := tg.NewClient(mock)
+
+	mock.ExpectCall(&tg.ContactsResolvePhoneRequest{Phone: "5511987654321"}).ThenResult(&tg.ContactsResolvedPeer{
+		Peer:  &tg.PeerUser{UserID: 42},
+		Users: []tg.UserClass{&tg.User{ID: 42, AccessHash: 9, FirstName: "Ana", LastName: "Lima"}},
+	})
+	chat, err := c.resolvePhone(context.Background(), api, "+5511987654321", "5511987654321")
+	if err != nil {
+		t.Fatalf("resolvePhone failed: %v", err)
+	}
+	if chat.ID != 42 || chat.Title != "Ana Lima" {
+		t.Errorf("unexpected chat %+v", chat)
+	}
+	if _, ok := c.peerCache[42]; !ok {
+		t.Error("expected the user in the peer cache")
+	}
+
+	mock.ExpectCall(&tg.ContactsResolvePhoneRequest{Phone: "5511000000000"}).ThenRPCErr(&tgerr.Error{Code: 400, Type: "PHONE_NOT_OCCUPIED"})
+	if _, err := c.resolvePhone(context.Background(), api, "+5511000000000", "5511000000000"); err == nil {
+		t.Error("expected an error for an unknown number")
+	}
+}
+
+func TestReresolve(t *testing.T) {
+	c, mock := newResolveClient(t)
+	api := tg.NewClient(mock)
+	known, err := LoadKnownChats("")
+	if err != nil {
+		t.Fatalf("failed to load known chats: %v", err)
+	}
+	c.UseKnownChats(known)
+	c.rememberUsername("oldname", -1001803446893, &tg.InputPeerChannel{ChannelID: 1803446893, AccessHash: 77})
+	c.rememberUsername("gone", -1000000009999, &tg.InputPeerChannel{ChannelID: 9999, AccessHash: 5})
+	ctx := context.Background()
+	notFound := &tgerr.Error{Code: 400, Type: "USERNAME_NOT_OCCUPIED"}
+
+	// Renamed channels are found by ID and monitored under it
+	mock.ExpectCall(&tg.ChannelsGetChannelsRequest{ID: []tg.InputChannelClass{&tg.InputChannel{ChannelID: 1803446893, AccessHash: 77}}}).ThenResult(&tg.MessagesChats{
+		Chats: []tg.ChatClass{&tg.Channel{ID: 1803446893, AccessHash: 77, Title: "Deals", Username: "newname", Photo: &tg.ChatPhotoEmpty{}}},
+	})
+	chat, failed := c.reresolve(ctx, api, "@oldname", "oldname", notFound)
+	if failed != nil {
+		t.Fatalf("expected the renamed channel found, got %+v", failed)
+	}
+	if chat.ID != -1001803446893 || chat.Title != "Deals" || chat.Hint != "renamed to @newname, replace @oldname with @newname in chats" {
+		t.Errorf("unexpected chat %+v", chat)
+	}
+	if info := c.peerCache[-1001803446893]; info.Username != "newname" {
+		t.Errorf("expected the channel cached under its ID, got %+v", info)
+	}
+
+	// Private channels explain what happened
+	mock.ExpectCall(&tg.ChannelsGetChannelsRequest{ID: []tg.InputChannelClass{&tg.InputChannel{ChannelID: 9999, AccessHash: 5}}}).ThenRPCErr(&tgerr.Error{Code: 406, Type: "CHANNEL_PRIVATE"})
+	_, failed = c.reresolve(ctx, api, "gone", "gone", notFound)
+	if failed == nil || !strings.Contains(failed.Reason, "chat -1000000009999 it pointed to is unreachable") || !strings.Contains(failed.Hint, "invite link") {
+		t.Errorf("expected the private channel explained, got %+v", failed)
+	}
+
+	// Unknown usernames and connection errors fail as before, without requests
+	if _, failed := c.reresolve(ctx, api, "@never", "never", notFound); failed == nil || failed.Hint != resolveHint("@never") {
+		t.Errorf("expected an unknown username to fail, got %+v", failed)
+	}
+	if _, failed := c.reresolve(ctx, api, "@oldname", "oldname", context.DeadlineExceeded); failed == nil {
+		t.Error("expected a connection error not to re-resolve")
+	}
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package telegram
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/gotd/td/tg"
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Search the history of the monitored chats for text, newest first.
+// A non-zero chatID limits the search to that chat, which must be monitored.
+func (c *Client) Search(ctx context.Context, text string, chatID int64, limit int) ([]model.Message, error) {
+	type chat struct {
+		id   int64
+		info peerInfo
+	}
+	var chats []chat
+	c.cacheMux.RLock()
+	for id, info := range c.peerCache {
+		if info.Peer != nil && (chatID == 0 || id == chatID) {
+			chats = append(chats, chat{id, info})
+		}
+	}
+	c.cacheMux.RUnlock()
+	if len(chats) == 0 {
+		if chatID != 0 {
+			return nil, fmt.Errorf("chat %d is not monitored", chatID)
+		}
+		return nil, fmt.Errorf("no monitored chats to search yet")
+	}
+
+	var found []model.Message
+	for _, ch := range chats {
+		msgs, err := c.searchChat(ctx, ch.id, ch.info, text, limit)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			// One unreachable chat should not hide results from the rest
+			c.log.Warn("Failed to search chat", zap.Int64("chat_id", ch.id), zap.Error(err))
+			continue
+		}
+		found = append(found, msgs...)
+	}
+
+	slices.SortFunc(found, func(a, b model.Message) int {
+		return cmp.Or(b.Date.Compare(a.Date), cmp.Compare(a.ChatID, b.ChatID))
+	})
+	if len(found) > limit {
+		found = found[:limit]
+	}
+	return found, nil
+}
+
+// Run messages.search in one chat
+func (c *Client) searchChat(ctx context.Context, id int64, info peerInfo, text string, limit int) ([]model.Message, error) {
+	res, err := c.client.API().MessagesSearch(ctx, &tg.MessagesSearchRequest{
+		Peer:   info.Peer,
+		Q:      text,
+		Filter: &tg.InputMessagesFilterEmpty{},
+		Limit:  limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	modified, ok := res.AsModified()
+	if !ok {
+		return nil, nil
+	}
+
+	var out []model.Message
+	for _, m := range modified.GetMessages() {
+		if msg, ok := m.(*tg.Message); ok {
+			out = append(out, c.historyMessage(id, info, msg))
+		}
+	}
+	return out, nil
+}
+
+// Convert a message fetched from a chat's history
+func (c *Client) historyMessage(id int64, info peerInfo, msg *tg.Message) model.Message {
+	kind, rawID := getPeerID(info.Peer)
+	ref := messageRef{kind: kind, rawID: rawID, username: info.Username, topicID: topicID(msg), msgID: msg.ID}
+	out := model.Message{
+		ID:        msg.ID,
+		ChatID:    id,
+		ChatTitle: info.Title,
+		Username:  info.Username,
+		TopicID:   ref.topicID,
+		Text:      msg.Message,
+		Date:      time.Unix(int64(msg.Date), 0),
+		Link:      messageLink(c.cfg.Links.Style, ref),
+		AppLink:   appLink(c.cfg.Links.Style, ref),
+	}
+	if edited, ok := msg.GetEditDate(); ok {
+		out.Edited = time.Unix(int64(edited), 0)
+	}
+	return out
+}
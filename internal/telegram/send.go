@@ -0,0 +1,105 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package telegram
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gotd/td/telegram/message"
+	"github.com/gotd/td/telegram/message/html"
+	"github.com/gotd/td/telegram/query"
+	"github.com/gotd/td/tg"
+
+	"github.com/h3nc4/TelegramScout/internal/chatid"
+)
+
+// Post an HTML alert as the account to notifier.account.chat, Saved Messages by default
+func (c *Client) SendAlert(ctx context.Context, text string) error {
+	to, err := c.resolveAlertPeer(ctx)
+	if err != nil {
+		return err
+	}
+
+	b := message.NewSender(c.client.API()).To(to).NoWebpage()
+	if c.cfg.Notifier.Account.Silent {
+		b = b.Silent()
+	}
+	if _, err := b.StyledText(ctx, html.String(nil, text)); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	return nil
+}
+
+// Post plain text as the account to the chat to refers to
+func (c *Client) Post(ctx context.Context, to, text string) error {
+	ref, err := chatid.Parse(to)
+	if err != nil {
+		return err
+	}
+	_, info, err := c.linkPeer(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("failed to resolve chat %q: %w", to, err)
+	}
+	if _, err := message.NewSender(c.client.API()).To(info.Peer).NoWebpage().Text(ctx, text); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	return nil
+}
+
+// Resolve the alert destination once per session
+func (c *Client) resolveAlertPeer(ctx context.Context) (tg.InputPeerClass, error) {
+	c.alertMux.Lock()
+	defer c.alertMux.Unlock()
+	if c.alertPeer != nil {
+		return c.alertPeer, nil
+	}
+
+	target := c.cfg.Notifier.Account.Chat
+	if target == "" {
+		c.alertPeer = &tg.InputPeerSelf{}
+		return c.alertPeer, nil
+	}
+	ref, err := chatid.Parse(target)
+	if err != nil {
+		return nil, err
+	}
+
+	if ref.IsUsername() {
+		p, err := message.NewSender(c.client.API()).Resolve(ref.Username).AsInputPeer(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve alert chat %q: %w", target, err)
+		}
+		c.alertPeer = p
+		return p, nil
+	}
+
+	// Numeric IDs need the access hash from a dialog
+	iter := query.GetDialogs(c.client.API()).Iter()
+	for iter.Next(ctx) {
+		if d := iter.Value(); ref.Matches(getPeerID(d.Peer)) {
+			c.alertPeer = d.Peer
+			return d.Peer, nil
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan dialogs: %w", err)
+	}
+	return nil, fmt.Errorf("alert chat %q not found in dialogs", target)
+}
@@ -0,0 +1,173 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gotd/td/telegram/message"
+	"github.com/gotd/td/tg"
+
+	"github.com/h3nc4/TelegramScout/internal/standing"
+)
+
+// Telegram's service notifications account
+const serviceUserID = 777000
+
+// How long @SpamBot gets to answer, polled every second
+const spamBotWait = 10 * time.Second
+
+// Register a callback invoked with the service notifications Telegram sends the account
+func (c *Client) OnServiceNotice(fn func(text string, at time.Time)) {
+	c.onServiceNotice = fn
+}
+
+func (c *Client) handleServiceNotification(ctx context.Context, e tg.Entities, u *tg.UpdateServiceNotification) error {
+	if c.onServiceNotice != nil && u.Message != "" {
+		at := time.Now()
+		if date, ok := u.GetInboxDate(); ok {
+			at = time.Unix(int64(date), 0)
+		}
+		c.onServiceNotice(u.Message, at)
+	}
+	return nil
+}
+
+// Report messages from the service notifications account, which are not monitored like other chats
+func (c *Client) serviceMessage(msg *tg.Message) {
+	if c.onServiceNotice == nil || msg.Out {
+		return
+	}
+	if p, ok := msg.PeerID.(*tg.PeerUser); ok && p.UserID == serviceUserID {
+		c.onServiceNotice(msg.Message, time.Unix(int64(msg.Date), 0))
+	}
+}
+
+// Fetch the account's restrictions and freeze state, and @SpamBot's verdict when asked
+func (c *Client) AccountStatus(ctx context.Context, askSpamBot bool) (standing.Status, error) {
+	api := c.client.API()
+	st := standing.Status{Checked: time.Now()}
+
+	users, err := api.UsersGetUsers(ctx, []tg.InputUserClass{&tg.InputUserSelf{}})
+	if err != nil {
+		return st, fmt.Errorf("failed to get own user: %w", err)
+	}
+	for _, u := range users {
+		if self, ok := u.(*tg.User); ok {
+			st.Restricted = self.Restricted
+			for _, r := range self.RestrictionReason {
+				st.Reasons = append(st.Reasons, fmt.Sprintf("%s: %s", r.Platform, r.Text))
+			}
+		}
+	}
+
+	appConfig, err := api.HelpGetAppConfig(ctx, 0)
+	if err != nil {
+		return st, fmt.Errorf("failed to get app config: %w", err)
+	}
+	if cfg, ok := appConfig.(*tg.HelpAppConfig); ok {
+		freezeState(cfg.Config, &st)
+	}
+
+	if askSpamBot {
+		answer, err := c.askSpamBot(ctx)
+		if err != nil {
+			return st, err
+		}
+		st.SpamBot = answer
+		st.Limited = spamLimited(answer)
+	}
+	return st, nil
+}
+
+// Read the freeze hints Telegram puts in the app config of frozen accounts
+func freezeState(config tg.JSONValueClass, st *standing.Status) {
+	obj, ok := config.(*tg.JSONObject)
+	if !ok {
+		return
+	}
+	for _, kv := range obj.Value {
+		switch v := kv.Value.(type) {
+		case *tg.JSONNumber:
+			switch kv.Key {
+			case "freeze_since_date":
+				st.Frozen = v.Value > 0
+			case "freeze_until_date":
+				st.FrozenUntil = time.Unix(int64(v.Value), 0)
+			}
+		case *tg.JSONString:
+			if kv.Key == "freeze_appeal_url" {
+				st.AppealURL = v.Value
+			}
+		}
+	}
+	if !st.Frozen {
+		st.FrozenUntil, st.AppealURL = time.Time{}, ""
+	}
+}
+
+// Send /start to @SpamBot and wait for its answer
+func (c *Client) askSpamBot(ctx context.Context) (string, error) {
+	sender := message.NewSender(c.client.API())
+	peer, err := sender.Resolve("SpamBot").AsInputPeer(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve @SpamBot: %w", err)
+	}
+	sent := time.Now().Add(-time.Second)
+	if _, err := sender.To(peer).Text(ctx, "/start"); err != nil {
+		return "", fmt.Errorf("failed to message @SpamBot: %w", err)
+	}
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	deadline := time.After(spamBotWait)
+	for {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-deadline:
+			return "", fmt.Errorf("@SpamBot did not answer within %s", spamBotWait)
+		case <-ticker.C:
+		}
+
+		history, err := c.client.API().MessagesGetHistory(ctx, &tg.MessagesGetHistoryRequest{Peer: peer, Limit: 1})
+		if err != nil {
+			return "", fmt.Errorf("failed to read @SpamBot's answer: %w", err)
+		}
+		msgs, ok := history.AsModified()
+		if !ok {
+			continue
+		}
+		for _, m := range msgs.GetMessages() {
+			if msg, ok := m.(*tg.Message); ok && !msg.Out && !time.Unix(int64(msg.Date), 0).Before(sent) {
+				return msg.Message, nil
+			}
+		}
+	}
+}
+
+// Report whether @SpamBot's answer describes limits. Only its English
+// all-clear is recognized, any other answer counts as limited.
+func spamLimited(answer string) bool {
+	answer = strings.ToLower(answer)
+	return answer != "" && !strings.Contains(answer, "no limits")
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package telegram
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gotd/td/telegram/message"
+	"github.com/gotd/td/telegram/query"
+	"github.com/gotd/td/tg"
+
+	"github.com/h3nc4/TelegramScout/internal/chatid"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Fetch the message a t.me link points to, reporting false once it is deleted
+func (c *Client) Message(ctx context.Context, link string) (model.Message, bool, error) {
+	ref, msgID, err := chatid.ParseLink(link)
+	if err != nil {
+		return model.Message{}, false, err
+	}
+	id, info, err := c.linkPeer(ctx, ref)
+	if err != nil {
+		return model.Message{}, false, err
+	}
+	ch, ok := info.Peer.(*tg.InputPeerChannel)
+	if !ok {
+		return model.Message{}, false, fmt.Errorf("%s is not a channel or supergroup", link)
+	}
+
+	res, err := c.client.API().ChannelsGetMessages(ctx, &tg.ChannelsGetMessagesRequest{
+		Channel: &tg.InputChannel{ChannelID: ch.ChannelID, AccessHash: ch.AccessHash},
+		ID:      []tg.InputMessageClass{&tg.InputMessageID{ID: msgID}},
+	})
+	if err != nil {
+		return model.Message{}, false, err
+	}
+	modified, ok := res.AsModified()
+	if !ok {
+		return model.Message{}, false, nil
+	}
+	// Deleted messages come back as messageEmpty
+	for _, m := range modified.GetMessages() {
+		if msg, ok := m.(*tg.Message); ok && msg.ID == msgID {
+			return c.historyMessage(id, info, msg), true, nil
+		}
+	}
+	return model.Message{}, false, nil
+}
+
+// Find the chat of a message link among the known peers, resolving it once otherwise
+func (c *Client) linkPeer(ctx context.Context, ref chatid.Ref) (int64, peerInfo, error) {
+	c.cacheMux.RLock()
+	for _, cache := range []map[int64]peerInfo{c.peerCache, c.linkCache} {
+		for id, info := range cache {
+			if info.Peer == nil {
+				continue
+			}
+			kind, rawID := getPeerID(info.Peer)
+			if ref.Matches(kind, rawID) || ref.MatchesUsername(info.Username) {
+				c.cacheMux.RUnlock()
+				return id, info, nil
+			}
+		}
+	}
+	c.cacheMux.RUnlock()
+
+	var info peerInfo
+	if ref.IsUsername() {
+		p, err := message.NewSender(c.client.API()).Resolve(ref.Username).AsInputPeer(ctx)
+		if err != nil {
+			return 0, peerInfo{}, err
+		}
+		info = peerInfo{Title: ref.Username, Username: ref.Username, Peer: p}
+	} else {
+		// Private links only work for chats the account is in
+		iter := query.GetDialogs(c.client.API()).Iter()
+		for iter.Next(ctx) {
+			d := iter.Value()
+			if kind, rawID := getPeerID(d.Peer); ref.Matches(kind, rawID) {
+				title, username := getPeerInfoFromEntities(d.Peer, d.Entities)
+				info = peerInfo{Title: title, Username: username, Peer: d.Peer}
+				break
+			}
+		}
+		if err := iter.Err(); err != nil {
+			return 0, peerInfo{}, err
+		}
+		if info.Peer == nil {
+			return 0, peerInfo{}, fmt.Errorf("channel %d not found in dialogs, join it with the monitoring account", ref.ID)
+		}
+	}
+
+	// Kept apart from the monitored chats so watching a chat does not monitor it
+	id := chatid.BotAPI(getPeerID(info.Peer))
+	if info.Title == "" {
+		info.Title = fmt.Sprint(id)
+	}
+	c.cacheMux.Lock()
+	c.linkCache[id] = info
+	c.cacheMux.Unlock()
+	return id, info, nil
+}
@@ -0,0 +1,138 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package temprules keeps keywords added from bot commands until they
+// expire, across restarts.
+package temprules
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/h3nc4/TelegramScout/internal/atomicfile"
+)
+
+// A keyword matched until a point in time
+type Rule struct {
+	Keyword string    `json:"keyword"`
+	Expires time.Time `json:"expires"`
+	By      string    `json:"by,omitempty"`
+}
+
+// Hold the temporary keywords, saving them to a file on every change
+type Store struct {
+	mux   sync.Mutex
+	path  string
+	rules map[string]Rule
+	now   func() time.Time
+}
+
+// Load the keywords saved at path, a missing file holds none. An empty path keeps them in memory only.
+func Load(path string) (*Store, error) {
+	s := &Store{path: path, rules: make(map[string]Rule), now: time.Now}
+	if path == "" {
+		return s, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var list []Rule
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("invalid temporary rules file %s: %w", path, err)
+	}
+	for _, r := range list {
+		s.rules[r.Keyword] = r
+	}
+	return s, nil
+}
+
+// Match keyword for d on behalf of by, replacing the expiry of an earlier addition
+func (s *Store) Add(keyword string, d time.Duration, by string) (Rule, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	r := Rule{Keyword: keyword, Expires: s.now().Add(d), By: by}
+	s.rules[keyword] = r
+	return r, s.save()
+}
+
+// Stop matching keyword, reporting whether it was still active
+func (s *Store) Remove(keyword string) (bool, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	r, ok := s.rules[keyword]
+	if !ok {
+		return false, nil
+	}
+	delete(s.rules, keyword)
+	return s.now().Before(r.Expires), s.save()
+}
+
+// Return the keywords still active, expiring soonest first
+func (s *Store) List() []Rule {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	now := s.now()
+	var out []Rule
+	for _, r := range s.rules {
+		if now.Before(r.Expires) {
+			out = append(out, r)
+		}
+	}
+	slices.SortFunc(out, func(a, b Rule) int {
+		return cmp.Or(a.Expires.Compare(b.Expires), cmp.Compare(a.Keyword, b.Keyword))
+	})
+	return out
+}
+
+// Write the active keywords to the file, dropping expired ones. Called with mux held.
+func (s *Store) save() error {
+	now := s.now()
+	list := []Rule{}
+	for k, r := range s.rules {
+		if !now.Before(r.Expires) {
+			delete(s.rules, k)
+			continue
+		}
+		list = append(list, r)
+	}
+	if s.path == "" {
+		return nil
+	}
+	slices.SortFunc(list, func(a, b Rule) int { return cmp.Compare(a.Keyword, b.Keyword) })
+
+	data, err := json.Marshal(list)
+	if err != nil {
+		return err
+	}
+	return atomicfile.Write(s.path, data)
+}
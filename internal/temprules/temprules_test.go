@@ -0,0 +1,69 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package temprules
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "temporary_rules.json")
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() of missing file error = %v", err)
+	}
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	if _, err := s.Add("black friday", 48*time.Hour, "alice"); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if _, err := s.Add("launch", time.Hour, "bob"); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if list := s.List(); len(list) != 2 || list[0].Keyword != "launch" || !list[1].Expires.Equal(now.Add(48*time.Hour)) {
+		t.Fatalf("expected both keywords, soonest first, got %+v", list)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	loaded.now = s.now
+	if list := loaded.List(); len(list) != 2 || list[1].By != "alice" {
+		t.Errorf("expected the keywords to survive a restart, got %+v", list)
+	}
+
+	// Expired keywords are no longer listed, then dropped from the file on the next change
+	now = now.Add(2 * time.Hour)
+	if list := s.List(); len(list) != 1 || list[0].Keyword != "black friday" {
+		t.Errorf("expected only the unexpired keyword, got %+v", list)
+	}
+	if removed, err := s.Remove("launch"); err != nil || removed {
+		t.Errorf("Remove() of an expired keyword = %t, %v", removed, err)
+	}
+	if removed, err := s.Remove("black friday"); err != nil || !removed {
+		t.Errorf("Remove() = %t, %v, want true", removed, err)
+	}
+	if loaded, err = Load(path); err != nil || len(loaded.rules) != 0 {
+		t.Errorf("expected an empty file, got %+v, %v", loaded.rules, err)
+	}
+}
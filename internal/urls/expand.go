@@ -0,0 +1,152 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package urls
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+// Expanded links remembered before the cache is reset
+const expandCacheSize = 1024
+
+// Well-known link shorteners, extended by urls.shorteners
+var defaultShorteners = []string{
+	"bit.ly", "buff.ly", "cutt.ly", "goo.gl", "is.gd", "lnkd.in", "ow.ly",
+	"rb.gy", "rebrand.ly", "s.id", "shorturl.at", "t.co", "tiny.cc", "tinyurl.com",
+}
+
+// Resolve short links by following their redirects.
+// Only shortener hosts are contacted: the first hop leaving them is the
+// destination, so the linked site itself never sees a request.
+type Expander struct {
+	client       *http.Client
+	shorteners   map[string]bool
+	maxRedirects int
+
+	mux   sync.Mutex
+	cache map[string]string
+}
+
+// Create new Expander
+func NewExpander(cfg config.URLsConfig) *Expander {
+	maxRedirects := cfg.MaxRedirects
+	if maxRedirects <= 0 {
+		maxRedirects = config.DefaultURLMaxRedirects
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = config.DefaultURLTimeout
+	}
+
+	shorteners := make(map[string]bool)
+	for _, h := range append(defaultShorteners, cfg.Shorteners...) {
+		shorteners[Host("http://"+h)] = true
+	}
+	return &Expander{
+		client: &http.Client{
+			Timeout: timeout,
+			// Redirects are followed by hand to inspect every hop
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		shorteners:   shorteners,
+		maxRedirects: maxRedirects,
+		cache:        make(map[string]string),
+	}
+}
+
+// Report whether link points to a known shortener
+func (e *Expander) IsShort(link string) bool {
+	return e.shorteners[Host(link)]
+}
+
+// Return the destination of link, or link itself if it is not short
+func (e *Expander) Expand(ctx context.Context, link string) (string, error) {
+	e.mux.Lock()
+	cached, ok := e.cache[link]
+	e.mux.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	current := link
+	for range e.maxRedirects {
+		if !e.IsShort(current) {
+			break
+		}
+		next, err := e.follow(ctx, current)
+		if err != nil {
+			return "", err
+		}
+		if next == "" {
+			// The shortener answered without redirecting
+			break
+		}
+		current = next
+	}
+
+	e.mux.Lock()
+	if len(e.cache) >= expandCacheSize {
+		e.cache = make(map[string]string)
+	}
+	e.cache[link] = current
+	e.mux.Unlock()
+	return current, nil
+}
+
+// Return the redirect target of link, or "" if it does not redirect
+func (e *Expander) follow(ctx context.Context, link string) (string, error) {
+	resp, err := e.request(ctx, http.MethodHead, link)
+	if err != nil {
+		return "", err
+	}
+	// Some shorteners only redirect GET requests
+	if resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented {
+		if resp, err = e.request(ctx, http.MethodGet, link); err != nil {
+			return "", err
+		}
+	}
+
+	if resp.StatusCode < 300 || resp.StatusCode >= 400 {
+		return "", nil
+	}
+	loc, err := resp.Location()
+	if err != nil {
+		return "", fmt.Errorf("redirect without location: %w", err)
+	}
+	return loc.String(), nil
+}
+
+// Send a request without reading the response body
+func (e *Expander) request(ctx context.Context, method, link string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, link, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("network error: %w", err)
+	}
+	_ = resp.Body.Close()
+	return resp, nil
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package urls
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+)
+
+func TestExpander_Expand(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Path {
+		case "/chain":
+			http.Redirect(w, r, "/final", http.StatusFound)
+		case "/final":
+			http.Redirect(w, r, "https://phish.example.com/login", http.StatusMovedPermanently)
+		case "/get-only":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			http.Redirect(w, r, "https://example.org/", http.StatusFound)
+		case "/loop":
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	e := NewExpander(config.URLsConfig{Shorteners: []string{"127.0.0.1"}, MaxRedirects: 3})
+	ctx := context.Background()
+
+	tests := map[string]string{
+		server.URL + "/chain":    "https://phish.example.com/login",
+		server.URL + "/get-only": "https://example.org/",
+		server.URL + "/ok":       server.URL + "/ok",
+		server.URL + "/loop":     server.URL + "/loop",
+	}
+	for link, want := range tests {
+		got, err := e.Expand(ctx, link)
+		if err != nil || got != want {
+			t.Errorf("Expand(%q) = %q, %v, want %q", link, got, err, want)
+		}
+	}
+
+	// Destinations are never contacted and results are cached
+	before := requests.Load()
+	if got, _ := e.Expand(ctx, server.URL+"/chain"); got != "https://phish.example.com/login" || requests.Load() != before {
+		t.Errorf("expected cached expansion, got %q after %d requests", got, requests.Load()-before)
+	}
+	if got, _ := e.Expand(ctx, "https://example.com/page"); got != "https://example.com/page" {
+		t.Errorf("expected links off shorteners to be returned as is, got %q", got)
+	}
+}
+
+func TestExpander_IsShort(t *testing.T) {
+	e := NewExpander(config.URLsConfig{Shorteners: []string{"sho.rt"}})
+	for link, want := range map[string]bool{
+		"https://bit.ly/x":     true,
+		"http://www.bit.ly/x":  true,
+		"https://sho.rt/abc":   true,
+		"https://example.com/": false,
+	} {
+		if got := e.IsShort(link); got != want {
+			t.Errorf("IsShort(%q) = %t, want %t", link, got, want)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package urls finds links in message text and resolves short links to
+// their destination.
+package urls
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Links with a scheme or starting with www.
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>"']+`)
+
+// Return the links in text, in order of appearance
+func Extract(text string) []string {
+	matches := linkPattern.FindAllString(text, -1)
+	links := make([]string, 0, len(matches))
+	for _, m := range matches {
+		// Sentence punctuation right after a link is not part of it
+		m = strings.TrimRight(m, ".,;:!?)]}")
+		if !strings.Contains(m, "://") {
+			m = "http://" + m
+		}
+		links = append(links, m)
+	}
+	return links
+}
+
+// Return the lowercased host of a link without any www. prefix, or "" if it has none
+func Host(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+// Return the entry of domains that host equals or is a subdomain of, or ""
+func MatchDomain(host string, domains []string) string {
+	if host == "" {
+		return ""
+	}
+	for _, d := range domains {
+		d = strings.TrimPrefix(strings.ToLower(d), "www.")
+		if host == d || strings.HasSuffix(host, "."+d) {
+			return d
+		}
+	}
+	return ""
+}
@@ -0,0 +1,65 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package urls
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtract(t *testing.T) {
+	got := Extract("Grab it at https://bit.ly/abc123, or www.Example.com/path. (see http://t.co/x)")
+	want := []string{"https://bit.ly/abc123", "http://www.Example.com/path", "http://t.co/x"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Extract() = %v, want %v", got, want)
+	}
+	if links := Extract("no links here"); len(links) != 0 {
+		t.Errorf("expected no links, got %v", links)
+	}
+}
+
+func TestHost(t *testing.T) {
+	tests := map[string]string{
+		"https://WWW.Example.com:8443/x": "example.com",
+		"http://sub.example.com":         "sub.example.com",
+		"not a url %zz":                  "",
+	}
+	for link, want := range tests {
+		if got := Host(link); got != want {
+			t.Errorf("Host(%q) = %q, want %q", link, got, want)
+		}
+	}
+}
+
+func TestMatchDomain(t *testing.T) {
+	domains := []string{"evil.com", "www.Phish.org"}
+	tests := map[string]string{
+		"evil.com":         "evil.com",
+		"login.evil.com":   "evil.com",
+		"notevil.com":      "",
+		"phish.org":        "phish.org",
+		"":                 "",
+		"evil.com.safe.io": "",
+	}
+	for host, want := range tests {
+		if got := MatchDomain(host, domains); got != want {
+			t.Errorf("MatchDomain(%q) = %q, want %q", host, got, want)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package version
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/semver"
+)
+
+// Latest release endpoint of the upstream repository
+const releasesURL = "https://api.github.com/repos/h3nc4/TelegramScout/releases/latest"
+
+// Describe a published GitHub release
+type Release struct {
+	TagName string  `json:"tag_name"`
+	Assets  []Asset `json:"assets"`
+}
+
+// Describe a downloadable release file
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// Fetch releases and replace the running binary
+type Updater struct {
+	client *http.Client
+	apiURL string
+}
+
+// Create new Updater against the upstream GitHub repository
+func NewUpdater() *Updater {
+	return &Updater{
+		client: &http.Client{Timeout: 5 * time.Minute},
+		apiURL: releasesURL,
+	}
+}
+
+// Return the latest published release
+func (u *Updater) Latest(ctx context.Context) (*Release, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query releases: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("releases api returned status: %d", resp.StatusCode)
+	}
+
+	var rel Release
+	if err := json.NewDecoder(resp.Body).Decode(&rel); err != nil {
+		return nil, fmt.Errorf("failed to decode release: %w", err)
+	}
+	return &rel, nil
+}
+
+// Report whether the release is newer than the running version. Neither
+// development builds nor tags that are not semantic versions are ever older.
+func (r *Release) Newer(current string) bool {
+	return IsRelease(r.TagName) && IsRelease(current) && semver.Compare(canonical(r.TagName), canonical(current)) > 0
+}
+
+// Report whether v is a released semantic version, with or without the leading v
+func IsRelease(v string) bool {
+	return semver.IsValid(canonical(v))
+}
+
+func canonical(v string) string {
+	if !strings.HasPrefix(v, "v") {
+		return "v" + v
+	}
+	return v
+}
+
+// Return the binary asset for the running platform, named telegram-scout_<os>_<arch>
+func (r *Release) AssetFor(goos, goarch string) (*Asset, bool) {
+	name := fmt.Sprintf("telegram-scout_%s_%s", goos, goarch)
+	if goos == "windows" {
+		name += ".exe"
+	}
+	for i := range r.Assets {
+		if r.Assets[i].Name == name {
+			return &r.Assets[i], true
+		}
+	}
+	return nil, false
+}
+
+// Return the checksums file of the release, checksums.txt or <project>_<version>_checksums.txt
+func (r *Release) Checksums() (*Asset, bool) {
+	for i := range r.Assets {
+		if name := r.Assets[i].Name; name == "checksums.txt" || strings.HasSuffix(name, "_checksums.txt") {
+			return &r.Assets[i], true
+		}
+	}
+	return nil, false
+}
+
+// Download the asset, check it against the SHA-256 listed for it in sums and
+// atomically swap it in place of the executable at path
+func (u *Updater) Install(ctx context.Context, asset, sums *Asset, path string) error {
+	want, err := u.checksum(ctx, sums, asset.Name)
+	if err != nil {
+		return err
+	}
+
+	resp, err := u.get(ctx, asset)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// Stage next to the target so the final rename stays on one filesystem
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".telegram-scout-update-*")
+	if err != nil {
+		return fmt.Errorf("failed to stage update: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, hash), resp.Body); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write update: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if got := hex.EncodeToString(hash.Sum(nil)); got != want {
+		return fmt.Errorf("checksum mismatch for %s: got %s, want %s", asset.Name, got, want)
+	}
+	if err := os.Chmod(tmp.Name(), 0o755); err != nil {
+		return err
+	}
+
+	// Windows refuses to overwrite a running executable, move it aside first
+	if runtime.GOOS == "windows" {
+		old := path + ".old"
+		_ = os.Remove(old)
+		if err := os.Rename(path, old); err != nil {
+			return fmt.Errorf("failed to move current binary aside: %w", err)
+		}
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace binary: %w", err)
+	}
+	return nil
+}
+
+// Return the SHA-256 the checksums file lists for name, in sha256sum format
+func (u *Updater) checksum(ctx context.Context, sums *Asset, name string) (string, error) {
+	if sums == nil {
+		return "", fmt.Errorf("no checksums to verify %s against", name)
+	}
+	resp, err := u.get(ctx, sums)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	scanner := bufio.NewScanner(io.LimitReader(resp.Body, 1<<20))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", sums.Name, err)
+	}
+	return "", fmt.Errorf("%s lists no checksum for %s", sums.Name, name)
+}
+
+// Start downloading asset, failing on any status but 200
+func (u *Updater) get(ctx context.Context, asset *Asset) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, asset.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", asset.Name, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("download of %s returned status: %d", asset.Name, resp.StatusCode)
+	}
+	return resp, nil
+}
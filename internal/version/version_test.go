@@ -0,0 +1,147 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package version
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestGet(t *testing.T) {
+	info := Get()
+	if info.Version == "" || info.GoVersion != runtime.Version() {
+		t.Errorf("unexpected info: %+v", info)
+	}
+
+	s := Info{Version: "1.2.3", Commit: "abcdef0123456", Date: "2026-01-01"}.String()
+	if s != "1.2.3 (abcdef0, 2026-01-01)" {
+		t.Errorf("unexpected string: %s", s)
+	}
+}
+
+func TestNewer(t *testing.T) {
+	rel := &Release{TagName: "v1.10.0"}
+	tests := []struct {
+		current string
+		want    bool
+	}{
+		{"1.9.3", true},
+		{"v1.2.0", true},
+		{"1.10.0-rc.1", true},
+		{"1.10.0", false},
+		{"v1.11.0", false},
+		{"2.0.0", false},
+		{"dev", false},
+	}
+	for _, tt := range tests {
+		if got := rel.Newer(tt.current); got != tt.want {
+			t.Errorf("Newer(%q) = %v, want %v", tt.current, got, tt.want)
+		}
+	}
+	if (&Release{TagName: "nightly"}).Newer("1.0.0") {
+		t.Error("expected a tag that is not a version never to be newer")
+	}
+	if IsRelease("dev") || !IsRelease("1.2.3") {
+		t.Error("unexpected IsRelease result")
+	}
+}
+
+func TestUpdater(t *testing.T) {
+	sum := sha256.Sum256([]byte("new-binary"))
+	sums := hex.EncodeToString(sum[:]) + "  telegram-scout_linux_amd64\n"
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/latest":
+			_ = json.NewEncoder(w).Encode(Release{
+				TagName: "v1.1.0",
+				Assets: []Asset{
+					{Name: "telegram-scout_linux_amd64", URL: server.URL + "/download"},
+					{Name: "telegram-scout_linux_arm64", URL: server.URL + "/tampered"},
+					{Name: "telegram-scout_1.1.0_checksums.txt", URL: server.URL + "/checksums"},
+				},
+			})
+		case "/download":
+			_, _ = w.Write([]byte("new-binary"))
+		case "/tampered":
+			_, _ = w.Write([]byte("evil-binary"))
+		case "/checksums":
+			_, _ = w.Write([]byte(sums + hex.EncodeToString(sum[:]) + "  telegram-scout_linux_arm64\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	u := NewUpdater()
+	u.apiURL = server.URL + "/latest"
+
+	rel, err := u.Latest(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !rel.Newer("1.0.0") || rel.Newer("v1.1.0") {
+		t.Error("unexpected version comparison result")
+	}
+
+	if _, ok := rel.AssetFor("darwin", "arm64"); ok {
+		t.Error("expected no asset for darwin/arm64")
+	}
+	asset, ok := rel.AssetFor("linux", "amd64")
+	if !ok {
+		t.Fatal("expected asset for linux/amd64")
+	}
+	checksums, ok := rel.Checksums()
+	if !ok {
+		t.Fatal("expected a checksums asset")
+	}
+
+	target := filepath.Join(t.TempDir(), "telegram-scout")
+	if err := os.WriteFile(target, []byte("old-binary"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	tampered, _ := rel.AssetFor("linux", "arm64")
+	if err := u.Install(context.Background(), tampered, checksums, target); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("expected a checksum mismatch, got %v", err)
+	}
+	if err := u.Install(context.Background(), asset, nil, target); err == nil {
+		t.Error("expected an install without checksums to fail")
+	}
+	if data, _ := os.ReadFile(target); string(data) != "old-binary" {
+		t.Errorf("expected failed installs to leave the binary alone, got %q", data)
+	}
+
+	if err := u.Install(context.Background(), asset, checksums, target); err != nil {
+		t.Fatalf("unexpected install error: %v", err)
+	}
+	data, err := os.ReadFile(target)
+	if err != nil || string(data) != "new-binary" {
+		t.Errorf("expected binary to be replaced, got %q (%v)", data, err)
+	}
+}
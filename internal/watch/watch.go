@@ -0,0 +1,303 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package watch polls specific messages and users, reporting when messages
+// are edited or deleted and when users change their profile.
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/atomicfile"
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+// Kinds of change
+const (
+	Edited  = "edited"  // A watched message's text changed
+	Deleted = "deleted" // A watched message is gone
+	Profile = "profile" // A watched user's username, name, bio or photo changed
+)
+
+// Fetch watched messages and users
+type Fetcher interface {
+	// Fetch a message by its t.me link, reporting false once it is deleted
+	Message(ctx context.Context, link string) (model.Message, bool, error)
+	// Fetch the profile of a user by @username or ID
+	Profile(ctx context.Context, user string) (model.Profile, error)
+}
+
+// A change noticed on a watched message or user
+type Change struct {
+	Kind   string
+	Link   string
+	Title  string    // Chat title, or the user's name
+	Before string    // Message text when last seen
+	After  string    // Message text now, empty for deletions
+	Fields []Field   // Profile fields that changed
+	Time   time.Time // Edit time, or when the change was noticed
+}
+
+// A profile field before and after a change
+type Field struct {
+	Name   string // username, name, bio or photo
+	Before string
+	After  string
+}
+
+// Called with every change
+type Handler func(ctx context.Context, ch Change)
+
+// Last seen state of a message
+type snapshot struct {
+	Text    string    `json:"text"`
+	Title   string    `json:"title,omitempty"`
+	Edited  time.Time `json:"edited,omitzero"`
+	Deleted bool      `json:"deleted,omitempty"`
+}
+
+// Last seen profile of a user
+type profile struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Bio      string `json:"bio,omitempty"`
+	PhotoID  int64  `json:"photo_id,omitempty"`
+}
+
+// Everything last seen, as saved to the state file
+type state struct {
+	Messages map[string]snapshot `json:"messages"` // Keyed by link
+	Users    map[string]profile  `json:"users"`    // Keyed by config entry
+}
+
+// Poll the watched messages and users, remembering their last seen state in a state file
+type Runner struct {
+	links    []string
+	users    []string
+	interval time.Duration
+	fetch    Fetcher
+	handle   Handler
+	path     string
+	log      *zap.Logger
+
+	mux  sync.Mutex
+	seen state
+}
+
+// Create a Runner for the watched messages and users, loading their last seen state from path
+func New(cfg config.WatchConfig, fetch Fetcher, path string, handle Handler, log *zap.Logger) (*Runner, error) {
+	r := &Runner{
+		links:    cfg.Messages,
+		users:    cfg.Users,
+		interval: cfg.Interval,
+		fetch:    fetch,
+		handle:   handle,
+		path:     path,
+		log:      log,
+	}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return nil, err
+	default:
+		if err := json.Unmarshal(data, &r.seen); err != nil {
+			return nil, fmt.Errorf("invalid watch state %s: %w", path, err)
+		}
+	}
+	if r.seen.Messages == nil {
+		r.seen.Messages = make(map[string]snapshot)
+	}
+	if r.seen.Users == nil {
+		r.seen.Users = make(map[string]profile)
+	}
+
+	// Forget messages and users no longer watched
+	for link := range r.seen.Messages {
+		if !slices.Contains(r.links, link) {
+			delete(r.seen.Messages, link)
+		}
+	}
+	for user := range r.seen.Users {
+		if !slices.Contains(r.users, user) {
+			delete(r.seen.Users, user)
+		}
+	}
+	return r, nil
+}
+
+// Poll every watched message and user on the interval until ctx is done, the first
+// time one interval after starting so the client has connected
+func (r *Runner) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.Poll(ctx)
+		}
+	}
+}
+
+// Fetch every watched message and user once, reporting changes since the
+// last poll. The first sighting of each only records it.
+func (r *Runner) Poll(ctx context.Context) {
+	changed := r.pollMessages(ctx)
+	if r.pollUsers(ctx) {
+		changed = true
+	}
+	if changed {
+		if err := r.save(); err != nil {
+			r.log.Error("Failed to save watch state", zap.String("path", r.path), zap.Error(err))
+		}
+	}
+}
+
+// Report edits and deletions of the watched messages, and whether the state changed
+func (r *Runner) pollMessages(ctx context.Context) bool {
+	changed := false
+	for _, link := range r.links {
+		msg, found, err := r.fetch.Message(ctx, link)
+		if err != nil {
+			if ctx.Err() != nil {
+				return changed
+			}
+			r.log.Warn("Failed to fetch watched message", zap.String("link", link), zap.Error(err))
+			continue
+		}
+
+		r.mux.Lock()
+		prev, known := r.seen.Messages[link]
+		next := snapshot{Text: msg.Text, Title: msg.ChatTitle, Edited: msg.Edited}
+		if !found {
+			next = prev
+			next.Deleted = true
+		}
+		modified := known && (next.Text != prev.Text || !next.Edited.Equal(prev.Edited) || next.Deleted != prev.Deleted)
+		if !known || modified || next.Title != prev.Title {
+			r.seen.Messages[link] = next
+			changed = true
+		}
+		r.mux.Unlock()
+
+		if !modified {
+			continue
+		}
+		switch {
+		case !found:
+			r.handle(ctx, Change{Kind: Deleted, Link: link, Title: prev.Title, Before: prev.Text, Time: time.Now()})
+		case prev.Deleted:
+			// A link reused after a deletion is a new message, not an edit
+		default:
+			at := msg.Edited
+			if at.IsZero() {
+				at = time.Now()
+			}
+			r.handle(ctx, Change{Kind: Edited, Link: link, Title: msg.ChatTitle, Before: prev.Text, After: msg.Text, Time: at})
+		}
+	}
+	return changed
+}
+
+// Report profile changes of the watched users, and whether the state changed
+func (r *Runner) pollUsers(ctx context.Context) bool {
+	changed := false
+	for _, user := range r.users {
+		p, err := r.fetch.Profile(ctx, user)
+		if err != nil {
+			if ctx.Err() != nil {
+				return changed
+			}
+			r.log.Warn("Failed to fetch watched user", zap.String("user", user), zap.Error(err))
+			continue
+		}
+
+		next := profile(p)
+		r.mux.Lock()
+		prev, known := r.seen.Users[user]
+		if next != prev {
+			r.seen.Users[user] = next
+			changed = true
+		}
+		r.mux.Unlock()
+
+		if !known || next == prev {
+			continue
+		}
+		ch := Change{Kind: Profile, Title: next.Name, Fields: diff(prev, next), Time: time.Now()}
+		if next.Username != "" {
+			ch.Link = "https://t.me/" + next.Username
+		}
+		if ch.Title == "" {
+			ch.Title = user
+		}
+		r.handle(ctx, ch)
+	}
+	return changed
+}
+
+// List the fields that differ between two profiles
+func diff(prev, next profile) []Field {
+	var fields []Field
+	for _, f := range []Field{
+		{"username", prev.Username, next.Username},
+		{"name", prev.Name, next.Name},
+		{"bio", prev.Bio, next.Bio},
+	} {
+		if f.Before != f.After {
+			fields = append(fields, f)
+		}
+	}
+	// Photo IDs mean nothing to readers, only whether there is one
+	if prev.PhotoID != next.PhotoID {
+		f := Field{Name: "photo", Before: "set", After: "set"}
+		if prev.PhotoID == 0 {
+			f.Before = "none"
+		}
+		if next.PhotoID == 0 {
+			f.After = "none"
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+func (r *Runner) save() error {
+	r.mux.Lock()
+	data, err := json.Marshal(r.seen)
+	r.mux.Unlock()
+	if err != nil {
+		return err
+	}
+	return atomicfile.Write(r.path, data)
+}
@@ -0,0 +1,138 @@
+/*
+ * Copyright (C) 2026  Henrique Almeida
+ * This file is part of TelegramScout.
+ *
+ * TelegramScout is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * TelegramScout is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with TelegramScout.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package watch
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/h3nc4/TelegramScout/internal/config"
+	"github.com/h3nc4/TelegramScout/internal/model"
+)
+
+const link = "https://t.me/example_channel/42"
+
+type fakeFetcher struct {
+	msg     model.Message
+	found   bool
+	profile model.Profile
+	err     error
+}
+
+func (f *fakeFetcher) Message(ctx context.Context, l string) (model.Message, bool, error) {
+	return f.msg, f.found, f.err
+}
+
+func (f *fakeFetcher) Profile(ctx context.Context, user string) (model.Profile, error) {
+	return f.profile, f.err
+}
+
+func TestRunner_Poll(t *testing.T) {
+	fetch := &fakeFetcher{msg: model.Message{ChatTitle: "Shop", Text: "Returns within 30 days"}, found: true}
+	path := filepath.Join(t.TempDir(), "watch.json")
+	cfg := config.WatchConfig{Messages: []string{link}, Interval: time.Minute}
+
+	var got []Change
+	handle := func(ctx context.Context, ch Change) { got = append(got, ch) }
+	r, err := New(cfg, fetch, path, handle, zap.NewNop())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	r.Poll(context.Background())
+	if len(got) != 0 {
+		t.Fatalf("expected the first sighting only recorded, got %+v", got)
+	}
+
+	// A restarted runner compares against the saved state
+	edited := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	fetch.msg = model.Message{ChatTitle: "Shop", Text: "Returns within 14 days", Edited: edited}
+	r, err = New(cfg, fetch, path, handle, zap.NewNop())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	r.Poll(context.Background())
+	r.Poll(context.Background())
+	want := Change{Kind: Edited, Link: link, Title: "Shop", Before: "Returns within 30 days", After: "Returns within 14 days", Time: edited}
+	if len(got) != 1 || !reflect.DeepEqual(got[0], want) {
+		t.Fatalf("expected one edit reported, got %+v", got)
+	}
+
+	// Failed fetches are not deletions
+	fetch.err = errors.New("flood wait")
+	r.Poll(context.Background())
+	fetch.err = nil
+
+	fetch.found = false
+	r.Poll(context.Background())
+	r.Poll(context.Background())
+	if len(got) != 2 || got[1].Kind != Deleted || got[1].Before != "Returns within 14 days" {
+		t.Fatalf("expected one deletion reported, got %+v", got)
+	}
+}
+
+func TestRunner_PollUsers(t *testing.T) {
+	fetch := &fakeFetcher{profile: model.Profile{ID: 7, Username: "alice", Name: "Alice", Bio: "Seller"}}
+	cfg := config.WatchConfig{Users: []string{"@alice"}, Interval: time.Minute}
+
+	var got []Change
+	r, err := New(cfg, fetch, filepath.Join(t.TempDir(), "watch.json"), func(ctx context.Context, ch Change) { got = append(got, ch) }, zap.NewNop())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	r.Poll(context.Background())
+	if len(got) != 0 {
+		t.Fatalf("expected the first sighting only recorded, got %+v", got)
+	}
+
+	fetch.profile = model.Profile{ID: 7, Username: "alice_2", Name: "Alice", Bio: "Seller", PhotoID: 99}
+	r.Poll(context.Background())
+	r.Poll(context.Background())
+	want := []Field{{"username", "alice", "alice_2"}, {"photo", "none", "set"}}
+	if len(got) != 1 || got[0].Kind != Profile || got[0].Link != "https://t.me/alice_2" || got[0].Title != "Alice" || !slices.Equal(got[0].Fields, want) {
+		t.Fatalf("expected one profile change, got %+v", got)
+	}
+}
+
+func TestNew_ForgetsUnwatched(t *testing.T) {
+	fetch := &fakeFetcher{msg: model.Message{Text: "a"}, found: true}
+	path := filepath.Join(t.TempDir(), "watch.json")
+	handle := func(ctx context.Context, ch Change) {}
+
+	r, err := New(config.WatchConfig{Messages: []string{link}}, fetch, path, handle, zap.NewNop())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	r.Poll(context.Background())
+
+	r, err = New(config.WatchConfig{}, fetch, path, handle, zap.NewNop())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if len(r.seen.Messages) != 0 {
+		t.Errorf("expected state of unwatched messages dropped, got %v", r.seen)
+	}
+}